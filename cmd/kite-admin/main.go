@@ -40,6 +40,7 @@ and other administrative tasks.`,
 	rootCmd.AddCommand(commands.NewConfigCmd())
 	rootCmd.AddCommand(commands.NewMetricsCmd())
 	rootCmd.AddCommand(commands.NewBackupCmd())
+	rootCmd.AddCommand(commands.NewSourcesCmd())
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
@@ -11,10 +12,15 @@ import (
 	"github.com/gongahkia/kite/internal/api"
 	"github.com/gongahkia/kite/internal/api/middleware"
 	"github.com/gongahkia/kite/internal/config"
+	"github.com/gongahkia/kite/internal/export"
 	"github.com/gongahkia/kite/internal/grpc"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/scraper/jurisdictions"
+	"github.com/gongahkia/kite/internal/search"
 	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/validation"
 )
 
 func main() {
@@ -37,6 +43,12 @@ func main() {
 	var store storage.Storage
 	var err error
 
+	connectRetry := storage.ConnectRetry{
+		Attempts: cfg.Database.ConnectRetries,
+		Interval: cfg.Database.ConnectRetryInterval,
+		Logger:   logger,
+	}
+
 	switch cfg.Database.Driver {
 	case "memory", "":
 		store = storage.NewMemoryStorage()
@@ -57,7 +69,12 @@ func main() {
 		connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
 			cfg.Database.Host, cfg.Database.Port, cfg.Database.Username,
 			cfg.Database.Password, cfg.Database.Database, cfg.Database.SSLMode)
-		store, err = storage.NewPostgresStorage(connStr)
+		store, err = storage.NewPostgresStorageWithConfig(connStr, storage.PostgresConfig{
+			MaxOpenConns:    cfg.Database.MaxOpenConns,
+			MaxIdleConns:    cfg.Database.MaxIdleConns,
+			ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+			ConnectRetry:    connectRetry,
+		})
 		if err != nil {
 			logger.Fatalf("Failed to initialize PostgreSQL storage: %v", err)
 		}
@@ -70,7 +87,19 @@ func main() {
 		if cfg.Database.Username == "" {
 			uri = fmt.Sprintf("mongodb://%s:%d", cfg.Database.Host, cfg.Database.Port)
 		}
-		store, err = storage.NewMongoStorage(uri, cfg.Database.Database)
+		store, err = storage.NewMongoStorageWithConfig(uri, cfg.Database.Database, storage.MongoConfig{
+			ReadPreference:         cfg.Database.Mongo.ReadPreference,
+			ReadConcern:            cfg.Database.Mongo.ReadConcern,
+			WriteConcern:           cfg.Database.Mongo.WriteConcern,
+			Journal:                cfg.Database.Mongo.Journal,
+			WriteTimeout:           cfg.Database.Mongo.WriteTimeout,
+			ConnectTimeout:         cfg.Database.Mongo.ConnectTimeout,
+			ServerSelectionTimeout: cfg.Database.Mongo.ServerSelectionTimeout,
+			SocketTimeout:          cfg.Database.Mongo.SocketTimeout,
+			ConnectRetry:           connectRetry,
+			TextLanguage:           cfg.Database.Mongo.TextLanguage,
+			TextLanguageOverride:   cfg.Database.Mongo.TextLanguageOverride,
+		})
 		if err != nil {
 			logger.Fatalf("Failed to initialize MongoDB storage: %v", err)
 		}
@@ -81,6 +110,61 @@ func main() {
 		logger.Fatalf("Unsupported storage driver: %s", cfg.Database.Driver)
 	}
 
+	// Log query plans for slow queries (debug only)
+	if cfg.Database.ExplainSlowQueries {
+		if s, ok := store.(storage.SlowQueryExplainerSetter); ok {
+			s.SetSlowQueryExplainer(storage.NewSlowQueryExplainer(storage.ExplainConfig{
+				Enabled:   true,
+				Threshold: cfg.Database.SlowQueryThreshold,
+				Logger:    logger,
+			}))
+			logger.Infof("Slow query explain enabled (threshold %s)", cfg.Database.SlowQueryThreshold)
+		}
+	}
+
+	// Recompute term statistics to pick up cases written by the workers
+	if s, ok := store.(storage.TermStatsRefresher); ok {
+		s.SetTermStatsRefresh(cfg.Database.TermStatsRefresh)
+	}
+
+	// Bound every storage call; wrapped around the backend so that the
+	// decorators below are bounded by it too
+	timeouts, err := storage.NewTimeoutStorage(store, storage.TimeoutConfig{
+		Default:    cfg.Database.QueryTimeout,
+		Operations: cfg.Database.QueryTimeouts,
+		Metrics:    metrics,
+	})
+	if err != nil {
+		logger.Fatalf("Invalid storage timeouts: %v", err)
+	}
+	store = timeouts
+
+	// Isolate tenants' data; wrapped first so status checks see only the
+	// tenant's cases
+	if cfg.Database.Tenancy.Enabled {
+		store = storage.NewTenantStorage(store)
+		logger.Info("Multi-tenancy enabled")
+	}
+
+	// Reject illegal case status transitions
+	if sl := cfg.Database.StatusLifecycle; sl.Enabled {
+		var transitions storage.StatusTransitions
+		if len(sl.Transitions) > 0 {
+			transitions, err = storage.ParseStatusTransitions(sl.Transitions)
+			if err != nil {
+				logger.Fatalf("Invalid status lifecycle: %v", err)
+			}
+		}
+		store = storage.NewLifecycleStorage(store, transitions, nil)
+		logger.Info("Case status lifecycle enabled")
+	}
+
+	// Serve only the cases of enabled jurisdictions; wrapped last so the
+	// decorators above still see every stored case
+	if len(cfg.Scraper.EnabledJurisdictions) > 0 {
+		store = storage.NewJurisdictionStorage(store, cfg.Scraper.EnabledJurisdictions)
+	}
+
 	// Initialize authentication configuration
 	authConfig := &middleware.AuthConfig{
 		APIKeys:       make(map[string]string),
@@ -92,11 +176,15 @@ func main() {
 	for key, clientID := range cfg.Security.APIKeys {
 		authConfig.APIKeys[key] = clientID
 	}
+	if cfg.Database.Tenancy.Enabled {
+		authConfig.Tenants = cfg.Database.Tenancy.Clients
+	}
 
 	logger.Info("Authentication configured")
 
 	// Initialize queue
 	var jobQueue queue.Queue
+	var dedupStore queue.DedupStore
 	switch cfg.Queue.Driver {
 	case "memory", "":
 		jobQueue = queue.NewMemoryQueue()
@@ -126,18 +214,122 @@ func main() {
 			Consumer:   "worker-1",
 			MaxRetries: cfg.Queue.MaxRetries,
 		}
-		jobQueue, err = queue.NewRedisQueue(redisConfig)
+		redisQueue, err := queue.NewRedisQueue(redisConfig)
 		if err != nil {
 			logger.Fatalf("Failed to initialize Redis queue: %v", err)
 		}
+		jobQueue = redisQueue
+		dedupStore = redisQueue.DedupStore()
 		logger.Infof("Using Redis queue: %s", redisAddr)
 
 	default:
 		logger.Fatalf("Unsupported queue driver: %s", cfg.Queue.Driver)
 	}
+	if cfg.Worker.EnrichCount > 0 {
+		enrichQueue, err := openEnrichQueue(cfg)
+		if err != nil {
+			logger.Fatalf("Failed to initialize enrich queue: %v", err)
+		}
+		jobQueue = queue.NewRoutedQueue(jobQueue, map[queue.JobType]queue.Queue{
+			queue.JobTypeEnrich: enrichQueue,
+		})
+		logger.Info("Routing enrich jobs to their own queue")
+	}
+	jobQueue = queue.NewDedupQueue(jobQueue, dedupStore, cfg.Queue.DedupWindow)
 
 	// Create API server
 	server := api.NewServer(store, logger, metrics, authConfig)
+	server.SetJobQueue(jobQueue, cfg.Queue.MaxReplays)
+	// Export job files, written by the workers to the same directory
+	server.SetExportStore(export.NewJobStore(cfg.Worker.ExportDir))
+	adminIPs, err := middleware.NewIPFilter(middleware.IPFilterConfig{
+		Allow:          cfg.Auth.AdminAllowCIDRs,
+		Deny:           cfg.Auth.AdminDenyCIDRs,
+		TrustedProxies: cfg.Auth.TrustedProxies,
+	})
+	if err != nil {
+		logger.Fatalf("Invalid admin IP filter: %v", err)
+	}
+	server.SetAdminIPFilter(adminIPs)
+
+	// Advertise only sources for enabled jurisdictions
+	scrapers := scraper.NewScraperRegistry()
+	scrapers.SetJurisdictionFilter(scraper.NewJurisdictionFilter(cfg.Scraper.EnabledJurisdictions))
+	jurisdictions.RegisterAll(scrapers)
+	server.SetScrapers(scrapers)
+
+	// Field combinations that identify duplicate cases
+	dedupHashes := validation.DefaultHashTemplates()
+	if len(cfg.Validation.DedupHashes) > 0 {
+		templates := make([]validation.HashTemplate, 0, len(cfg.Validation.DedupHashes))
+		for _, h := range cfg.Validation.DedupHashes {
+			templates = append(templates, validation.HashTemplate{
+				Name:       h.Name,
+				Fields:     h.Fields,
+				Normalize:  h.Normalize,
+				AllowEmpty: h.AllowEmpty,
+				MinLength:  h.MinLength,
+				MaxLength:  h.MaxLength,
+			})
+		}
+		if err := validation.ValidateHashTemplates(templates); err != nil {
+			logger.Fatalf("Invalid dedup hashes: %v", err)
+		}
+		server.SetDedupHashes(templates)
+		logger.Infof("Using %d configured dedup hashes", len(templates))
+		dedupHashes = templates
+	}
+
+	// Collapse duplicate search results by case ID, and across sources by
+	// one of the dedup hashes
+	searchDedup := search.DedupConfig{Enabled: cfg.Server.SearchDedup}
+	if name := cfg.Server.SearchDedupHash; name != "" {
+		for i := range dedupHashes {
+			if dedupHashes[i].Name == name {
+				searchDedup.ContentHash = &dedupHashes[i]
+			}
+		}
+		if searchDedup.ContentHash == nil {
+			logger.Fatalf("Unknown search dedup hash: %s", name)
+		}
+	}
+	server.SetSearchDedup(searchDedup)
+
+	conceptBoost := search.ConceptBoostConfig{
+		MinImportance: cfg.Server.SearchConceptMinImportance,
+		Weight:        cfg.Server.SearchConceptBoost,
+	}
+	if err := conceptBoost.Validate(); err != nil {
+		logger.Fatalf("Invalid search concept boost: %v", err)
+	}
+	server.SetConceptBoost(conceptBoost)
+
+	fieldNaming, err := middleware.ParseFieldNaming(cfg.Server.JSONNaming)
+	if err != nil {
+		logger.Fatalf("Invalid server.json_naming: %v", err)
+	}
+	server.SetFieldNaming(fieldNaming)
+
+	resultWindow := storage.ResultWindow{
+		DefaultLimit: cfg.Server.DefaultPageSize,
+		MaxLimit:     cfg.Server.MaxResultLimit,
+		MaxOffset:    cfg.Server.MaxResultOffset,
+	}
+
+	cacheConfig := middleware.DefaultCacheConfig()
+	cacheConfig.Case.MaxAge = cfg.Server.CacheCaseTTL
+	cacheConfig.List.MaxAge = cfg.Server.CacheListTTL
+	cacheConfig.Stats.MaxAge = cfg.Server.CacheStatsTTL
+	cacheConfig.Reference.MaxAge = cfg.Server.CacheReferenceTTL
+	server.SetCacheConfig(cacheConfig)
+	server.SetResultWindow(resultWindow)
+	server.SetTimeouts(api.ServerTimeouts{
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		StreamPaths:       cfg.Server.StreamPaths,
+	})
 	server.SetupRoutes()
 
 	// Start HTTP server in goroutine
@@ -157,6 +349,12 @@ func main() {
 			Storage: store,
 			Queue:   jobQueue,
 			Logger:  logger,
+
+			ResultWindow: resultWindow,
+
+			Reflection:     cfg.Server.GRPCReflection,
+			MaxRecvMsgSize: cfg.Server.GRPCMaxRecvMsgSize,
+			MaxSendMsgSize: cfg.Server.GRPCMaxSendMsgSize,
 		}
 
 		grpcServer, err = grpc.NewServer(grpcConfig)
@@ -179,33 +377,62 @@ func main() {
 
 	logger.Info("Shutting down servers...")
 
-	// Graceful shutdown with timeout
+	// Graceful shutdown with timeout: drain in-flight HTTP and gRPC requests
+	// together first, then close the queue and storage they use, each close
+	// with a timeout of its own
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
-	// Shutdown HTTP server
-	if err := server.Shutdown(); err != nil {
-		logger.Errorf("HTTP server forced to shutdown: %v", err)
+	servers := []api.ShutdownStep{
+		{Name: "HTTP server", Stop: server.ShutdownWithContext},
 	}
-
-	// Shutdown gRPC server if running
 	if grpcServer != nil {
-		grpcServer.Stop()
-		logger.Info("gRPC server stopped")
+		servers = append(servers, api.ShutdownStep{Name: "gRPC server", Stop: grpcServer.StopWithContext})
 	}
-
-	// Close queue
-	if err := jobQueue.Close(ctx); err != nil {
-		logger.Errorf("Failed to close queue: %v", err)
+	resources := []api.ShutdownStep{
+		{Name: "queue", Stop: func(context.Context) error { return jobQueue.Close() }},
+		{Name: "storage", Stop: func(context.Context) error { return store.Close() }},
 	}
-
-	// Close storage
-	if err := store.Close(); err != nil {
-		logger.Errorf("Failed to close storage: %v", err)
+	err = api.GracefulShutdown(ctx, cfg.Server.ShutdownTimeout, servers, resources...)
+	if errors.Is(err, api.ErrDrainTimeout) {
+		logger.Warnf("Requests still running after %s, not closing the queue and storage: %v", cfg.Server.ShutdownTimeout, err)
+	} else if err != nil {
+		logger.Errorf("Unclean shutdown: %v", err)
 	}
 
 	logger.Info("All servers exited")
+}
+
+// openEnrichQueue opens the queue that enrich jobs are routed to when a
+// dedicated enrichment pool is configured. It must match the queue the
+// enrichment workers consume.
+func openEnrichQueue(cfg *config.Config) (queue.Queue, error) {
+	switch cfg.Queue.Driver {
+	case "memory", "":
+		return queue.NewMemoryQueue(), nil
+
+	case "nats":
+		natsConfig := queue.DefaultNATSQueueConfig()
+		natsConfig.URL = cfg.Queue.URL
+		natsConfig.Stream = "KITE_ENRICH"
+		natsConfig.Subject = "enrich.*"
+		natsConfig.DLQSubject = "enrich.dlq"
+		natsConfig.Consumer = "kite-enrich-worker"
+		natsConfig.MaxRetries = cfg.Queue.MaxRetries
+		return queue.NewNATSQueue(natsConfig)
 
-	// Wait for context timeout
-	<-ctx.Done()
+	case "redis":
+		redisConfig := queue.DefaultRedisQueueConfig()
+		redisConfig.Addr = fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
+		redisConfig.Password = cfg.Redis.Password
+		redisConfig.DB = cfg.Redis.DB
+		redisConfig.Stream = "kite:jobs:enrich"
+		redisConfig.Group = "kite-enrich-workers"
+		redisConfig.DLQStream = "kite:jobs:enrich:dlq"
+		redisConfig.MaxRetries = cfg.Queue.MaxRetries
+		return queue.NewRedisQueue(redisConfig)
+
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", cfg.Queue.Driver)
+	}
 }
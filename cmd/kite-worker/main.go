@@ -8,12 +8,20 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/gongahkia/kite/internal/citation"
+	"github.com/gongahkia/kite/internal/compliance"
+	"github.com/gongahkia/kite/internal/concepts"
 	"github.com/gongahkia/kite/internal/config"
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/jurisdiction"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/queue"
 	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/scraper/jurisdictions"
 	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/internal/worker"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
@@ -53,7 +61,40 @@ func main() {
 		logger.Error("Unsupported database driver", "driver", cfg.Database.Driver)
 		os.Exit(1)
 	}
-	defer store.Close()
+	timeouts, err := storage.NewTimeoutStorage(store, storage.TimeoutConfig{
+		Default:    cfg.Database.QueryTimeout,
+		Operations: cfg.Database.QueryTimeouts,
+		Metrics:    metrics,
+	})
+	if err != nil {
+		logger.Error("Invalid storage timeouts", "error", err)
+		os.Exit(1)
+	}
+	store = timeouts
+	if wb := cfg.Database.WriteBehind; wb.Enabled {
+		store = storage.NewWriteBehindStorage(store, storage.WriteBehindConfig{
+			BatchSize:     wb.BatchSize,
+			FlushInterval: wb.FlushInterval,
+			BufferSize:    wb.BufferSize,
+			WriteTimeout:  wb.WriteTimeout,
+			Logger:        logger,
+			Metrics:       metrics,
+		})
+		logger.Info("Write-behind buffer enabled", "batch_size", wb.BatchSize, "flush_interval", wb.FlushInterval)
+	}
+	if sl := cfg.Database.StatusLifecycle; sl.Enabled {
+		var transitions storage.StatusTransitions
+		if len(sl.Transitions) > 0 {
+			transitions, err = storage.ParseStatusTransitions(sl.Transitions)
+			if err != nil {
+				logger.Error("Invalid status lifecycle", "error", err)
+				os.Exit(1)
+			}
+		}
+		store = storage.NewLifecycleStorage(store, transitions, nil)
+		logger.Info("Case status lifecycle enabled")
+	}
+	defer store.Close() // flushes the write-behind buffer, if enabled
 
 	// Initialize queue
 	var q queue.Queue
@@ -79,12 +120,254 @@ func main() {
 		logger.Error("Unsupported queue driver", "driver", cfg.Queue.Driver)
 		os.Exit(1)
 	}
+
+	// Back off before requeueing failed jobs, whichever backend is in use
+	retryPolicy := queue.RetryPolicy{
+		Strategy:  queue.RetryStrategy(cfg.Queue.RetryStrategy),
+		BaseDelay: cfg.Queue.RetryDelay,
+		MaxDelay:  cfg.Queue.RetryMaxDelay,
+		Jitter:    cfg.Queue.RetryJitter,
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		logger.Error("Invalid retry policy", "error", err)
+		os.Exit(1)
+	}
+	q = queue.NewRetryQueue(q, retryPolicy)
+	logger.Info("Retry policy configured", "strategy", retryPolicy.Strategy, "base_delay", retryPolicy.BaseDelay, "max_delay", retryPolicy.MaxDelay)
 	defer q.Close()
 
+	// Initialize scrapers
+	scrapers := scraper.NewScraperRegistry()
+	scrapers.SetJurisdictionFilter(scraper.NewJurisdictionFilter(cfg.Scraper.EnabledJurisdictions))
+	idGenerator := scraper.NewCaseIDGenerator(scraper.IDStrategy(cfg.Scraper.IDStrategy))
+	idGenerator.SetCaseLookup(store.GetCase) // hash IDs are fetched by the source ID stored with the case
+	scrapers.SetIDGenerator(idGenerator)
+	scrapers.SetMaxFullTextBytes(cfg.Scraper.MaxFullTextBytes, logger)
+	scrapers.SetExtractionVersion(cfg.Scraper.ExtractionVersion)
+	scrapers.SetFallbackReporting(logger, metrics)
+	if err := scrapers.SetDefaultCharsets(cfg.Scraper.DefaultCharsets); err != nil {
+		logger.Error("Invalid scraper charset", "error", err)
+		os.Exit(1)
+	}
+	textStructure, err := scraper.NewTextStructure(cfg.Scraper.PreservedTags)
+	if err != nil {
+		logger.Error("Invalid preserved tags", "error", err)
+		os.Exit(1)
+	}
+	scrapers.SetTextStructure(textStructure)
+	if cfg.Scraper.SelectorsFile != "" {
+		overrides, err := scraper.LoadSelectorOverrides(cfg.Scraper.SelectorsFile)
+		if err != nil {
+			logger.Error("Invalid scraper selectors", "error", err)
+			os.Exit(1)
+		}
+		scrapers.SetSelectors(overrides)
+		logger.Info("Scraper selectors overridden", "file", cfg.Scraper.SelectorsFile, "scrapers", len(overrides))
+	}
+	if cfg.Scraper.HTMLDumpEnabled {
+		scrapers.SetHTMLDumper(scraper.NewHTMLDumper(scraper.HTMLDumpConfig{
+			Enabled:   true,
+			Dir:       cfg.Scraper.HTMLDumpDir,
+			MaxBytes:  cfg.Scraper.HTMLDumpMaxBytes,
+			Retention: cfg.Scraper.HTMLDumpRetention,
+		}))
+		logger.Info("HTML dumps enabled for parse failures", "dir", cfg.Scraper.HTMLDumpDir)
+	}
+	if cfg.Scraper.ArchiveEnabled {
+		var sink scraper.ArchiveSink
+		switch cfg.Scraper.ArchiveDriver {
+		case "s3":
+			s3Sink, err := scraper.NewS3ArchiveSink(scraper.S3ArchiveConfig{
+				Endpoint:        cfg.Scraper.ArchiveS3Endpoint,
+				Bucket:          cfg.Scraper.ArchiveS3Bucket,
+				Region:          cfg.Scraper.ArchiveS3Region,
+				AccessKeyID:     cfg.Scraper.ArchiveS3AccessKeyID,
+				SecretAccessKey: cfg.Scraper.ArchiveS3SecretAccessKey,
+				Prefix:          cfg.Scraper.ArchiveS3Prefix,
+			})
+			if err != nil {
+				logger.Error("Invalid scrape archive", "error", err)
+				os.Exit(1)
+			}
+			sink = s3Sink
+		case "dir", "":
+			sink = scraper.NewDirArchiveSink(cfg.Scraper.ArchiveDir)
+		default:
+			logger.Error("Unknown scrape archive driver", "driver", cfg.Scraper.ArchiveDriver)
+			os.Exit(1)
+		}
+		archiver := scraper.NewResultArchiver(sink, cfg.Scraper.ArchiveBufferSize)
+		archiver.SetLogger(logger)
+		archiver.SetMetrics(metrics)
+		scrapers.SetArchiver(archiver)
+		defer archiver.Close() // writes the cases still queued
+		logger.Info("Scrape results archived", "driver", cfg.Scraper.ArchiveDriver)
+	}
+	jurisdictions.RegisterAll(scrapers)
+	logger.Info("Scrapers registered", "count", len(scrapers.GetAll()))
+
+	// Skip non-case URLs blocklisted by each source's scraping policy or configured
+	policies := compliance.NewPolicyManager()
+	blocklists := make(map[string]*scraper.URLBlocklist)
+	for name, s := range scrapers.GetAll() {
+		var patterns []string
+		if policy, ok := policies.GetPolicy(s.GetName()); ok {
+			patterns = append(patterns, policy.Blocklist...)
+		}
+		patterns = append(patterns, cfg.Scraper.URLBlocklist[name]...)
+		if len(patterns) == 0 {
+			continue
+		}
+		blocklist, err := scraper.NewURLBlocklist(patterns)
+		if err != nil {
+			logger.Error("Invalid URL blocklist", "scraper", name, "error", err)
+			os.Exit(1)
+		}
+		blocklists[name] = blocklist
+	}
+	scrapers.SetURLBlocklists(blocklists)
+
+	// Scrape each source only within the scraping window of its policy or configured
+	windows := make(map[string]*scraper.ScrapeWindow)
+	for name, s := range scrapers.GetAll() {
+		var ranges []string
+		var timezone string
+		if policy, ok := policies.GetPolicy(s.GetName()); ok {
+			ranges, timezone = policy.ScrapeWindows, policy.Timezone
+		}
+		if configured, ok := cfg.Scraper.ScrapeWindows[name]; ok {
+			ranges = configured
+		}
+		if tz, ok := cfg.Scraper.SourceTimezones[name]; ok {
+			timezone = tz
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+		window, err := scraper.NewScrapeWindow(timezone, ranges)
+		if err != nil {
+			logger.Error("Invalid scrape window", "scraper", name, "error", err)
+			os.Exit(1)
+		}
+		windows[name] = window
+		logger.Info("Scrape window configured", "scraper", name, "windows", ranges, "timezone", timezone)
+	}
+	scrapers.SetScrapeWindows(windows)
+	scrapers.SetConcurrencyLimits(cfg.Scraper.ConcurrentLimit, cfg.Scraper.ConcurrentLimits)
+	scrapers.SetRetryConfig(scraper.RetryConfig{
+		MaxRetries: cfg.Scraper.MaxRetries,
+		Budget:     cfg.Scraper.RetryBudget,
+		Delay:      cfg.Scraper.RetryDelay,
+	})
+
+	if cfg.Scraper.SharedRateLimit {
+		redisAddr := fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     redisAddr,
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+		defer redisClient.Close()
+
+		scrapers.SetSharedRateLimit(scraper.NewRedisTokenBucket(redisClient), logger)
+		logger.Info("Sharing scraper rate limits through Redis", "address", redisAddr)
+	}
+
 	// Create job handler
 	handler := worker.NewJobHandler(store, logger, metrics)
 	logger.Info("Job handler initialized")
 
+	// Store only scraped cases from the configured court levels
+	courtLevels := make([]models.CourtLevel, 0, len(cfg.Scraper.CourtLevels))
+	for _, level := range cfg.Scraper.CourtLevels {
+		courtLevels = append(courtLevels, models.CourtLevel(level))
+	}
+	handler = worker.NewTypeJobHandler(map[queue.JobType]worker.JobHandler{
+		queue.JobTypeScrape: worker.NewScrapeJobHandlerWithOptions(store, scrapers, worker.NewCourtLevelFilter(courtLevels), worker.ScrapeOptions{
+			OnlyNew: cfg.Worker.ScrapeOnlyNew,
+		}),
+		queue.JobTypeExport: worker.NewExportJobHandler(store, export.NewJobStore(cfg.Worker.ExportDir)),
+	}, handler)
+	if len(courtLevels) > 0 {
+		logger.Info("Filtering scraped cases by court level", "levels", cfg.Scraper.CourtLevels)
+	}
+
+	metadataEnricher := jurisdiction.NewMetadataEnricher()
+	metadataEnricher.SetMetrics(metrics)
+	partyNormalizer := jurisdiction.NewPartyNormalizer()
+	partyNormalizer.SetTitleCase(cfg.Worker.PartyTitleCase)
+	partyNormalizer.AddCompanySuffixes(cfg.Worker.PartyCompanySuffixes...)
+	partyNormalizer.AddGovernmentKeywords(cfg.Worker.PartyGovernmentKeywords...)
+	metadataEnricher.SetPartyNormalizer(partyNormalizer)
+	conceptService := concepts.NewService(store)
+	citationService := citation.NewService(store)
+	enrichSteps, err := worker.OrderEnrichSteps([]worker.NamedEnrichStep{
+		{Name: "metadata", Run: func(ctx context.Context, c *models.Case) error {
+			if err := metadataEnricher.EnrichCase(c); err != nil {
+				return err
+			}
+			return store.UpdateCase(ctx, c)
+		}},
+		{Name: "concepts", Requires: []string{"metadata"}, Run: func(ctx context.Context, c *models.Case) error {
+			_, err := conceptService.ExtractAndStoreConcepts(ctx, c)
+			return err
+		}},
+		{Name: "citations", Run: func(ctx context.Context, c *models.Case) error {
+			if err := citationService.NormalizeCaseCitations(ctx, c); err != nil {
+				return err
+			}
+			_, err := citationService.ExtractAndStoreCitations(ctx, c)
+			return err
+		}},
+		{Name: "treatments", Requires: []string{"citations"}, Run: func(ctx context.Context, c *models.Case) error {
+			_, err := citationService.ApplyTreatments(ctx, c)
+			return err
+		}},
+		{Name: "appeal", Run: func(ctx context.Context, c *models.Case) error {
+			_, err := citationService.LinkAppeal(ctx, c)
+			return err
+		}},
+	}, cfg.Worker.EnrichOrder, cfg.Worker.EnrichRequires)
+	if err != nil {
+		logger.Error("Invalid enrichment order", "error", err)
+		os.Exit(1)
+	}
+	stepNames := make([]string, len(enrichSteps))
+	stepRuns := make([]worker.EnrichStep, len(enrichSteps))
+	for i, step := range enrichSteps {
+		stepNames[i], stepRuns[i] = step.Name, step.Run
+	}
+	enrichHandler := worker.NewEnrichJobHandler(store, stepRuns...)
+	logger.Info("Enrichment steps ordered", "steps", stepNames)
+
+	// Enrich jobs get their own queue and pool when one is configured, and
+	// otherwise share the main pool
+	var enrichPool *worker.Pool
+	if cfg.Worker.EnrichCount > 0 {
+		enrichQueue, err := openEnrichQueue(cfg)
+		if err != nil {
+			logger.Error("Failed to initialize enrich queue", "error", err)
+			os.Exit(1)
+		}
+		enrichQueue = queue.NewRetryQueue(enrichQueue, retryPolicy)
+		defer enrichQueue.Close()
+
+		enrichPool = worker.NewPool(worker.PoolConfig{
+			WorkerCount:   cfg.Worker.EnrichCount,
+			JobTimeout:    cfg.Worker.JobTimeout,
+			ShutdownGrace: cfg.Worker.ShutdownGrace,
+		}, enrichQueue, enrichHandler)
+		if err := enrichPool.Start(cfg.Worker.EnrichCount); err != nil {
+			logger.Error("Failed to start enrichment pool", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("Enrichment pool started", "workers", cfg.Worker.EnrichCount)
+	} else {
+		handler = worker.NewTypeJobHandler(map[queue.JobType]worker.JobHandler{
+			queue.JobTypeEnrich: enrichHandler,
+		}, handler)
+	}
+
 	// Create worker pool
 	workerCount := cfg.Worker.PoolSize
 	if workerCount <= 0 {
@@ -105,6 +388,44 @@ func main() {
 
 	logger.Info("Worker pool started successfully")
 
+	// Start stale-case refresh
+	if cfg.Worker.RefreshEnabled {
+		refresher := worker.NewCaseRefresher(store, scrapers, worker.RefreshConfig{
+			MaxAge:         cfg.Worker.RefreshMaxAge,
+			BatchSize:      cfg.Worker.RefreshBatchSize,
+			BudgetFraction: cfg.Worker.RefreshBudgetFraction,
+		})
+		refresher.SetMetrics(metrics)
+		go refresher.Start(ctx, cfg.Worker.RefreshInterval)
+		logger.Info("Stale-case refresh enabled", "interval", cfg.Worker.RefreshInterval)
+	}
+
+	// Start saved search alerts
+	if cfg.Worker.AlertsEnabled {
+		alerts, err := worker.NewAlertRunner(store, worker.NewWebhookNotifier(), worker.AlertConfig{
+			Lookback: cfg.Worker.AlertsLookback,
+		})
+		if err != nil {
+			logger.Error("Failed to create saved search alerts", "error", err)
+			os.Exit(1)
+		}
+		go alerts.Start(ctx, cfg.Worker.AlertsInterval)
+		logger.Info("Saved search alerts enabled", "interval", cfg.Worker.AlertsInterval)
+	}
+
+	// Start scheduled incremental scrapes
+	if len(cfg.Scraper.Schedules) > 0 {
+		scheduler, err := worker.NewScrapeScheduler(q, scrapers, cfg.Scraper.Schedules)
+		if err != nil {
+			logger.Error("Invalid scrape schedule", "error", err)
+			os.Exit(1)
+		}
+		go scheduler.Start(ctx, time.Minute)
+		for _, sched := range scheduler.Schedules() {
+			logger.Info("Scrape scheduled", "source", sched.Source, "schedule", sched.Schedule, "next_run", sched.NextRun)
+		}
+	}
+
 	// Start metrics server
 	if cfg.Observability.MetricsEnabled {
 		go func() {
@@ -135,5 +456,46 @@ func main() {
 		logger.Info("Worker pool stopped gracefully")
 	}
 
+	if enrichPool != nil {
+		if err := enrichPool.Stop(cfg.Worker.ShutdownGrace); err != nil {
+			logger.Error("Error during enrichment pool shutdown", "error", err)
+		} else {
+			logger.Info("Enrichment pool stopped gracefully")
+		}
+	}
+
 	logger.Info("Kite Worker shutdown complete")
 }
+
+// openEnrichQueue opens the queue the enrichment pool consumes. It must match
+// the queue the API routes enrich jobs to.
+func openEnrichQueue(cfg *config.Config) (queue.Queue, error) {
+	switch cfg.Queue.Driver {
+	case "memory", "":
+		return queue.NewMemoryQueue(), nil
+
+	case "nats":
+		natsConfig := queue.DefaultNATSQueueConfig()
+		natsConfig.URL = cfg.Queue.URL
+		natsConfig.Stream = "KITE_ENRICH"
+		natsConfig.Subject = "enrich.*"
+		natsConfig.DLQSubject = "enrich.dlq"
+		natsConfig.Consumer = "kite-enrich-worker"
+		natsConfig.MaxRetries = cfg.Queue.MaxRetries
+		return queue.NewNATSQueue(natsConfig)
+
+	case "redis":
+		redisConfig := queue.DefaultRedisQueueConfig()
+		redisConfig.Addr = fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
+		redisConfig.Password = cfg.Redis.Password
+		redisConfig.DB = cfg.Redis.DB
+		redisConfig.Stream = "kite:jobs:enrich"
+		redisConfig.Group = "kite-enrich-workers"
+		redisConfig.DLQStream = "kite:jobs:enrich:dlq"
+		redisConfig.MaxRetries = cfg.Queue.MaxRetries
+		return queue.NewRedisQueue(redisConfig)
+
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", cfg.Queue.Driver)
+	}
+}
@@ -4,12 +4,14 @@ go 1.22
 
 require (
 	github.com/gofiber/fiber/v2 v2.52.0
+	github.com/valyala/fasthttp v1.51.0
 	github.com/rs/zerolog v1.31.0
 	github.com/prometheus/client_golang v1.18.0
 	github.com/spf13/viper v1.18.2
 	github.com/spf13/cobra v1.8.0
 	github.com/go-playground/validator/v10 v10.16.0
 	github.com/PuerkitoBio/goquery v1.9.0
+	github.com/andybalholm/cascadia v1.3.2
 	github.com/lib/pq v1.10.9
 	github.com/golang-jwt/jwt/v5 v5.2.0
 	github.com/swaggo/swag v1.16.2
@@ -22,4 +24,6 @@ require (
 	golang.org/x/net v0.20.0
 	google.golang.org/grpc v1.60.1
 	google.golang.org/protobuf v1.32.0
+	github.com/xitongsys/parquet-go v1.6.2
+	github.com/xitongsys/parquet-go-source v0.0.0-20240122235623-d6294584ab18
 )
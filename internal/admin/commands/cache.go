@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -24,58 +25,70 @@ func NewCacheCmd() *cobra.Command {
 	return cmd
 }
 
+// cacheStats summarises cache usage
+type cacheStats struct {
+	TotalKeys     int     `json:"total_keys"`
+	Hits          int     `json:"hits"`
+	Misses        int     `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`
+	Evictions     int     `json:"evictions"`
+	MemoryUsedMB  int     `json:"memory_used_mb"`
+	UptimeSeconds int64   `json:"uptime_seconds"`
+}
+
+// cacheKeys is a page of cache keys
+type cacheKeys struct {
+	Keys  []string `json:"keys"`
+	Total int      `json:"total"`
+}
+
 func newCacheFlushCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "flush",
 		Short: "Flush all cache entries",
 		Long:  "Remove all entries from the cache",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Println("Flushing all cache entries...")
+				out.Progressf("Flushing all cache entries...")
 			}
 
 			// In real implementation, would connect to cache service
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Println("✓ Cache flushed successfully")
-			return nil
+			return out.Done("Cache flushed successfully", nil)
 		},
 	}
 }
 
-func newCacheStatsCmd() *cobra.Command{
+func newCacheStatsCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "stats",
 		Short: "Show cache statistics",
 		Long:  "Display cache hit rate, size, and other metrics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "total_keys": 8456,
-  "hits": 125643,
-  "misses": 8921,
-  "hit_rate": 0.9337,
-  "evictions": 231,
-  "memory_used_mb": 512,
-  "uptime_seconds": 86400
-}`)
-			} else {
-				fmt.Println("Cache Statistics:")
-				fmt.Println("=================")
-				fmt.Println("Total Keys:      8,456")
-				fmt.Println("Hits:            125,643")
-				fmt.Println("Misses:          8,921")
-				fmt.Println("Hit Rate:        93.37%")
-				fmt.Println("Evictions:       231")
-				fmt.Println("Memory Used:     512 MB")
-				fmt.Println("Uptime:          24h 0m 0s")
+			stats := cacheStats{
+				TotalKeys:     8456,
+				Hits:          125643,
+				Misses:        8921,
+				HitRate:       0.9337,
+				Evictions:     231,
+				MemoryUsedMB:  512,
+				UptimeSeconds: 86400,
 			}
 
-			return nil
+			return newOutput(cmd).Render(stats, func(w io.Writer) {
+				heading(w, "Cache Statistics:")
+				fmt.Fprintf(w, "Total Keys:\t%d\n", stats.TotalKeys)
+				fmt.Fprintf(w, "Hits:\t%d\n", stats.Hits)
+				fmt.Fprintf(w, "Misses:\t%d\n", stats.Misses)
+				fmt.Fprintf(w, "Hit Rate:\t%.2f%%\n", stats.HitRate*100)
+				fmt.Fprintf(w, "Evictions:\t%d\n", stats.Evictions)
+				fmt.Fprintf(w, "Memory Used:\t%d MB\n", stats.MemoryUsedMB)
+				fmt.Fprintf(w, "Uptime:\t%s\n", time.Duration(stats.UptimeSeconds)*time.Second)
+			})
 		},
 	}
 }
@@ -88,6 +101,7 @@ func newCacheClearCmd() *cobra.Command {
 		Short: "Clear cache entries by pattern",
 		Long:  "Remove cache entries matching the specified pattern",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if pattern == "" {
@@ -95,14 +109,15 @@ func newCacheClearCmd() *cobra.Command {
 			}
 
 			if verbose {
-				fmt.Printf("Clearing cache entries matching pattern: %s\n", pattern)
+				out.Progressf("Clearing cache entries matching pattern: %s", pattern)
 			}
 
 			// In real implementation, would clear matching keys
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Printf("✓ Cleared cache entries matching pattern: %s\n", pattern)
-			return nil
+			return out.Done(fmt.Sprintf("Cleared cache entries matching pattern: %s", pattern), map[string]interface{}{
+				"pattern": pattern,
+			})
 		},
 	}
 
@@ -118,22 +133,22 @@ func newCacheWarmCmd() *cobra.Command {
 		Short: "Warm up cache",
 		Long:  "Pre-populate cache with frequently accessed data",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Println("Warming up cache with frequently accessed data...")
+				out.Progressf("Warming up cache with frequently accessed data...")
 			}
 
 			// In real implementation, would query database and populate cache
-			fmt.Println("Loading popular cases...")
+			out.Progressf("Loading popular cases...")
 			time.Sleep(200 * time.Millisecond)
-			fmt.Println("Loading jurisdiction metadata...")
+			out.Progressf("Loading jurisdiction metadata...")
 			time.Sleep(200 * time.Millisecond)
-			fmt.Println("Loading legal concepts...")
+			out.Progressf("Loading legal concepts...")
 			time.Sleep(200 * time.Millisecond)
 
-			fmt.Println("✓ Cache warmed successfully")
-			return nil
+			return out.Done("Cache warmed successfully", nil)
 		},
 	}
 }
@@ -146,21 +161,26 @@ func newCacheKeysCmd() *cobra.Command {
 		Short: "List cache keys",
 		Long:  "Display cache keys with optional limit",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Printf("Listing cache keys (limit: %d)...\n", limit)
+				out.Progressf("Listing cache keys (limit: %d)...", limit)
 			}
 
 			// In real implementation, would fetch keys from cache
-			fmt.Println("Cache Keys:")
-			fmt.Println("===========")
+			result := cacheKeys{Keys: []string{}, Total: 8456}
 			for i := 0; i < limit && i < 10; i++ {
-				fmt.Printf("case:cth/HCA/2023/%d\n", i+1)
+				result.Keys = append(result.Keys, fmt.Sprintf("case:cth/HCA/2023/%d", i+1))
 			}
-			fmt.Printf("... (showing %d of 8,456 keys)\n", limit)
 
-			return nil
+			return out.Render(result, func(w io.Writer) {
+				heading(w, "Cache Keys:")
+				for _, key := range result.Keys {
+					fmt.Fprintln(w, key)
+				}
+				fmt.Fprintf(w, "... (showing %d of %d keys)\n", len(result.Keys), result.Total)
+			})
 		},
 	}
 
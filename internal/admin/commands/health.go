@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -24,43 +25,83 @@ func NewHealthCmd() *cobra.Command {
 	return cmd
 }
 
+// healthReport is the result of a full health check
+type healthReport struct {
+	Status        string            `json:"status"`
+	Version       string            `json:"version"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	Checks        map[string]string `json:"checks"`
+}
+
+// componentHealth is the result of checking a single component
+type componentHealth struct {
+	Component string                 `json:"component"`
+	Status    string                 `json:"status"`
+	Details   map[string]interface{} `json:"details"`
+}
+
+// healthDetail is one line of a component check
+type healthDetail struct {
+	key   string
+	label string
+	value interface{}
+}
+
+// renderComponent writes a healthy component check, listing details in order
+func renderComponent(o *output, component, name string, details ...healthDetail) error {
+	h := componentHealth{
+		Component: component,
+		Status:    "healthy",
+		Details:   make(map[string]interface{}, len(details)),
+	}
+	for _, d := range details {
+		h.Details[d.key] = d.value
+	}
+
+	return o.Render(h, func(w io.Writer) {
+		fmt.Fprintf(w, "%s %s is %s\n", checkMark(h.Status), name, h.Status)
+		for _, d := range details {
+			fmt.Fprintf(w, "  %s:\t%v\n", d.label, d.value)
+		}
+	})
+}
+
 func newHealthCheckCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "check",
 		Short: "Perform full health check",
 		Long:  "Check health of all system components",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "status": "healthy",
-  "version": "4.0.0",
-  "uptime_seconds": 86400,
-  "checks": {
-    "api": "healthy",
-    "database": "healthy",
-    "cache": "healthy",
-    "queue": "healthy",
-    "workers": "healthy"
-  }
-}`)
-			} else {
-				fmt.Println("System Health Check:")
-				fmt.Println("====================")
-				fmt.Println("Overall Status:  ✓ healthy")
-				fmt.Println("Version:         4.0.0")
-				fmt.Println("Uptime:          24h 0m 0s")
-				fmt.Println()
-				fmt.Println("Component Checks:")
-				fmt.Println("  API:           ✓ healthy")
-				fmt.Println("  Database:      ✓ healthy")
-				fmt.Println("  Cache:         ✓ healthy")
-				fmt.Println("  Queue:         ✓ healthy")
-				fmt.Println("  Workers:       ✓ healthy")
+			report := healthReport{
+				Status:        "healthy",
+				Version:       "4.0.0",
+				UptimeSeconds: 86400,
+				Checks: map[string]string{
+					"api":      "healthy",
+					"database": "healthy",
+					"cache":    "healthy",
+					"queue":    "healthy",
+					"workers":  "healthy",
+				},
 			}
 
-			return nil
+			return newOutput(cmd).Render(report, func(w io.Writer) {
+				heading(w, "System Health Check:")
+				fmt.Fprintf(w, "Overall Status:\t%s\n", statusMark(report.Status))
+				fmt.Fprintf(w, "Version:\t%s\n", report.Version)
+				fmt.Fprintf(w, "Uptime:\t%s\n", time.Duration(report.UptimeSeconds)*time.Second)
+				fmt.Fprintln(w)
+				fmt.Fprintln(w, "Component Checks:")
+				for _, c := range []struct{ key, label string }{
+					{"api", "API"},
+					{"database", "Database"},
+					{"cache", "Cache"},
+					{"queue", "Queue"},
+					{"workers", "Workers"},
+				} {
+					fmt.Fprintf(w, "  %s:\t%s\n", c.label, statusMark(report.Checks[c.key]))
+				}
+			})
 		},
 	}
 }
@@ -71,17 +112,17 @@ func newHealthAPICmd() *cobra.Command {
 		Short: "Check API health",
 		Long:  "Check if the API server is responding",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Checking API health...")
+			out := newOutput(cmd)
+			out.Progressf("Checking API health...")
 
 			// In real implementation, would make HTTP request to /health
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Println("✓ API is healthy")
-			fmt.Println("  Response Time: 42ms")
-			fmt.Println("  Active Connections: 23")
-			fmt.Println("  Requests/sec: 45.2")
-
-			return nil
+			return renderComponent(out, "api", "API",
+				healthDetail{"response_time", "Response Time", "42ms"},
+				healthDetail{"active_connections", "Active Connections", 23},
+				healthDetail{"requests_per_sec", "Requests/sec", 45.2},
+			)
 		},
 	}
 }
@@ -97,7 +138,8 @@ func newHealthDatabaseCmd() *cobra.Command {
 				return err
 			}
 
-			fmt.Println("Checking database health...")
+			out := newOutput(cmd)
+			out.Progressf("Checking database health...")
 
 			db, err := initStorage(cfg)
 			if err != nil {
@@ -108,13 +150,12 @@ func newHealthDatabaseCmd() *cobra.Command {
 			// Perform a simple ping
 			time.Sleep(50 * time.Millisecond)
 
-			fmt.Println("✓ Database is healthy")
-			fmt.Println("  Connection: OK")
-			fmt.Println("  Ping Time: 12ms")
-			fmt.Println("  Active Connections: 8/25")
-			fmt.Println("  Idle Connections: 3")
-
-			return nil
+			return renderComponent(out, "database", "Database",
+				healthDetail{"connection", "Connection", "OK"},
+				healthDetail{"ping_time", "Ping Time", "12ms"},
+				healthDetail{"active_connections", "Active Connections", "8/25"},
+				healthDetail{"idle_connections", "Idle Connections", 3},
+			)
 		},
 	}
 }
@@ -125,18 +166,18 @@ func newHealthCacheCmd() *cobra.Command {
 		Short: "Check cache health",
 		Long:  "Check cache connectivity and performance",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Checking cache health...")
+			out := newOutput(cmd)
+			out.Progressf("Checking cache health...")
 
 			// In real implementation, would ping cache service
 			time.Sleep(50 * time.Millisecond)
 
-			fmt.Println("✓ Cache is healthy")
-			fmt.Println("  Connection: OK")
-			fmt.Println("  Ping Time: 3ms")
-			fmt.Println("  Hit Rate: 93.4%")
-			fmt.Println("  Keys: 8,456")
-
-			return nil
+			return renderComponent(out, "cache", "Cache",
+				healthDetail{"connection", "Connection", "OK"},
+				healthDetail{"ping_time", "Ping Time", "3ms"},
+				healthDetail{"hit_rate", "Hit Rate", "93.4%"},
+				healthDetail{"keys", "Keys", 8456},
+			)
 		},
 	}
 }
@@ -147,18 +188,18 @@ func newHealthQueueCmd() *cobra.Command {
 		Short: "Check queue health",
 		Long:  "Check job queue connectivity and status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Checking queue health...")
+			out := newOutput(cmd)
+			out.Progressf("Checking queue health...")
 
 			// In real implementation, would check queue service
 			time.Sleep(50 * time.Millisecond)
 
-			fmt.Println("✓ Queue is healthy")
-			fmt.Println("  Connection: OK")
-			fmt.Println("  Pending Jobs: 152")
-			fmt.Println("  Processing Rate: 42.3 jobs/min")
-			fmt.Println("  DLQ Size: 5")
-
-			return nil
+			return renderComponent(out, "queue", "Queue",
+				healthDetail{"connection", "Connection", "OK"},
+				healthDetail{"pending_jobs", "Pending Jobs", 152},
+				healthDetail{"processing_rate", "Processing Rate", "42.3 jobs/min"},
+				healthDetail{"dlq_size", "DLQ Size", 5},
+			)
 		},
 	}
 }
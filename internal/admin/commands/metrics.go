@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/spf13/cobra"
 )
@@ -22,6 +23,50 @@ func NewMetricsCmd() *cobra.Command {
 	return cmd
 }
 
+// querySample is a single PromQL result value
+type querySample struct {
+	Query     string  `json:"query"`
+	Timestamp string  `json:"timestamp"`
+	Value     float64 `json:"value"`
+}
+
+// apiMetrics summarises API performance
+type apiMetrics struct {
+	RequestRate       float64 `json:"request_rate"`
+	ErrorRate         float64 `json:"error_rate"`
+	P50LatencyMs      int     `json:"p50_latency_ms"`
+	P95LatencyMs      int     `json:"p95_latency_ms"`
+	P99LatencyMs      int     `json:"p99_latency_ms"`
+	ActiveConnections int     `json:"active_connections"`
+}
+
+// workerMetrics summarises the worker pool
+type workerMetrics struct {
+	ActiveWorkers      int     `json:"active_workers"`
+	TotalWorkers       int     `json:"total_workers"`
+	Utilization        float64 `json:"utilization"`
+	QueueSize          int     `json:"queue_size"`
+	JobsPerMinute      float64 `json:"jobs_per_minute"`
+	SuccessRate        float64 `json:"success_rate"`
+	AvgDurationSeconds float64 `json:"avg_duration_seconds"`
+}
+
+// jurisdictionScrapeMetrics summarises scraping for one jurisdiction
+type jurisdictionScrapeMetrics struct {
+	Jurisdiction string  `json:"jurisdiction"`
+	Requests     int     `json:"requests"`
+	SuccessRate  float64 `json:"success_rate"`
+}
+
+// scraperMetrics summarises scraping across jurisdictions
+type scraperMetrics struct {
+	TotalRequests      int                         `json:"total_requests"`
+	SuccessRate        float64                     `json:"success_rate"`
+	CasesScraped       int                         `json:"cases_scraped"`
+	AvgDurationSeconds float64                     `json:"avg_duration_seconds"`
+	ByJurisdiction     []jurisdictionScrapeMetrics `json:"by_jurisdiction"`
+}
+
 func newMetricsQueryCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "query [promql]",
@@ -31,19 +76,25 @@ func newMetricsQueryCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			query := args[0]
 
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Printf("Executing query: %s\n", query)
+				out.Progressf("Executing query: %s", query)
 			}
 
 			// In real implementation, would query Prometheus API
-			fmt.Println("Query Results:")
-			fmt.Println("==============")
-			fmt.Println("timestamp: 2023-12-16T10:00:00Z")
-			fmt.Println("value: 42.5")
+			sample := querySample{
+				Query:     query,
+				Timestamp: "2023-12-16T10:00:00Z",
+				Value:     42.5,
+			}
 
-			return nil
+			return out.Render(sample, func(w io.Writer) {
+				heading(w, "Query Results:")
+				fmt.Fprintf(w, "timestamp: %s\n", sample.Timestamp)
+				fmt.Fprintf(w, "value: %g\n", sample.Value)
+			})
 		},
 	}
 }
@@ -54,29 +105,24 @@ func newMetricsAPICmd() *cobra.Command {
 		Short: "Show API metrics",
 		Long:  "Display key API performance metrics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "request_rate": 45.2,
-  "error_rate": 0.02,
-  "p50_latency_ms": 42,
-  "p95_latency_ms": 125,
-  "p99_latency_ms": 312,
-  "active_connections": 23
-}`)
-			} else {
-				fmt.Println("API Metrics:")
-				fmt.Println("============")
-				fmt.Println("Request Rate:        45.2 req/s")
-				fmt.Println("Error Rate:          2.0%")
-				fmt.Println("P50 Latency:         42ms")
-				fmt.Println("P95 Latency:         125ms")
-				fmt.Println("P99 Latency:         312ms")
-				fmt.Println("Active Connections:  23")
+			m := apiMetrics{
+				RequestRate:       45.2,
+				ErrorRate:         0.02,
+				P50LatencyMs:      42,
+				P95LatencyMs:      125,
+				P99LatencyMs:      312,
+				ActiveConnections: 23,
 			}
 
-			return nil
+			return newOutput(cmd).Render(m, func(w io.Writer) {
+				heading(w, "API Metrics:")
+				fmt.Fprintf(w, "Request Rate:\t%.1f req/s\n", m.RequestRate)
+				fmt.Fprintf(w, "Error Rate:\t%.1f%%\n", m.ErrorRate*100)
+				fmt.Fprintf(w, "P50 Latency:\t%dms\n", m.P50LatencyMs)
+				fmt.Fprintf(w, "P95 Latency:\t%dms\n", m.P95LatencyMs)
+				fmt.Fprintf(w, "P99 Latency:\t%dms\n", m.P99LatencyMs)
+				fmt.Fprintf(w, "Active Connections:\t%d\n", m.ActiveConnections)
+			})
 		},
 	}
 }
@@ -87,30 +133,25 @@ func newMetricsWorkerCmd() *cobra.Command {
 		Short: "Show worker metrics",
 		Long:  "Display worker pool and job processing metrics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "active_workers": 3,
-  "total_workers": 4,
-  "utilization": 0.75,
-  "queue_size": 152,
-  "jobs_per_minute": 42.3,
-  "success_rate": 0.98,
-  "avg_duration_seconds": 2.8
-}`)
-			} else {
-				fmt.Println("Worker Metrics:")
-				fmt.Println("===============")
-				fmt.Println("Active Workers:     3/4")
-				fmt.Println("Utilization:        75%")
-				fmt.Println("Queue Size:         152")
-				fmt.Println("Jobs/Minute:        42.3")
-				fmt.Println("Success Rate:       98%")
-				fmt.Println("Avg Duration:       2.8s")
+			m := workerMetrics{
+				ActiveWorkers:      3,
+				TotalWorkers:       4,
+				Utilization:        0.75,
+				QueueSize:          152,
+				JobsPerMinute:      42.3,
+				SuccessRate:        0.98,
+				AvgDurationSeconds: 2.8,
 			}
 
-			return nil
+			return newOutput(cmd).Render(m, func(w io.Writer) {
+				heading(w, "Worker Metrics:")
+				fmt.Fprintf(w, "Active Workers:\t%d/%d\n", m.ActiveWorkers, m.TotalWorkers)
+				fmt.Fprintf(w, "Utilization:\t%.0f%%\n", m.Utilization*100)
+				fmt.Fprintf(w, "Queue Size:\t%d\n", m.QueueSize)
+				fmt.Fprintf(w, "Jobs/Minute:\t%.1f\n", m.JobsPerMinute)
+				fmt.Fprintf(w, "Success Rate:\t%.0f%%\n", m.SuccessRate*100)
+				fmt.Fprintf(w, "Avg Duration:\t%.1fs\n", m.AvgDurationSeconds)
+			})
 		},
 	}
 }
@@ -121,35 +162,30 @@ func newMetricsScraperCmd() *cobra.Command {
 		Short: "Show scraper metrics",
 		Long:  "Display web scraping metrics by jurisdiction",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "total_requests": 1523,
-  "success_rate": 0.95,
-  "cases_scraped": 1445,
-  "avg_duration_seconds": 1.2,
-  "by_jurisdiction": {
-    "Australia": {"requests": 420, "success_rate": 0.96},
-    "Canada": {"requests": 385, "success_rate": 0.94},
-    "United Kingdom": {"requests": 362, "success_rate": 0.95}
-  }
-}`)
-			} else {
-				fmt.Println("Scraper Metrics:")
-				fmt.Println("================")
-				fmt.Println("Total Requests:     1,523")
-				fmt.Println("Success Rate:       95%")
-				fmt.Println("Cases Scraped:      1,445")
-				fmt.Println("Avg Duration:       1.2s")
-				fmt.Println()
-				fmt.Println("By Jurisdiction:")
-				fmt.Println("  Australia:        420 requests (96% success)")
-				fmt.Println("  Canada:           385 requests (94% success)")
-				fmt.Println("  United Kingdom:   362 requests (95% success)")
+			m := scraperMetrics{
+				TotalRequests:      1523,
+				SuccessRate:        0.95,
+				CasesScraped:       1445,
+				AvgDurationSeconds: 1.2,
+				ByJurisdiction: []jurisdictionScrapeMetrics{
+					{Jurisdiction: "Australia", Requests: 420, SuccessRate: 0.96},
+					{Jurisdiction: "Canada", Requests: 385, SuccessRate: 0.94},
+					{Jurisdiction: "United Kingdom", Requests: 362, SuccessRate: 0.95},
+				},
 			}
 
-			return nil
+			return newOutput(cmd).Render(m, func(w io.Writer) {
+				heading(w, "Scraper Metrics:")
+				fmt.Fprintf(w, "Total Requests:\t%d\n", m.TotalRequests)
+				fmt.Fprintf(w, "Success Rate:\t%.0f%%\n", m.SuccessRate*100)
+				fmt.Fprintf(w, "Cases Scraped:\t%d\n", m.CasesScraped)
+				fmt.Fprintf(w, "Avg Duration:\t%.1fs\n", m.AvgDurationSeconds)
+				fmt.Fprintln(w)
+				fmt.Fprintln(w, "By Jurisdiction:")
+				for _, j := range m.ByJurisdiction {
+					fmt.Fprintf(w, "  %s:\t%d requests (%.0f%% success)\n", j.Jurisdiction, j.Requests, j.SuccessRate*100)
+				}
+			})
 		},
 	}
 }
@@ -224,7 +224,7 @@ func loadConfig(cmd *cobra.Command) (*config.Config, error) {
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
 	if verbose {
-		fmt.Printf("Loading config from: %s\n", configPath)
+		fmt.Fprintf(cmd.ErrOrStderr(), "Loading config from: %s\n", configPath)
 	}
 
 	cfg, err := config.Load(configPath)
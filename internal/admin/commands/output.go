@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// output writes command results as indented JSON when the global --json flag
+// is set, and as human-readable tables otherwise
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// newOutput creates an output for cmd honoring the global --json flag
+func newOutput(cmd *cobra.Command) *output {
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	return &output{
+		w:    cmd.OutOrStdout(),
+		json: jsonOutput,
+	}
+}
+
+// JSON reports whether structured output was requested
+func (o *output) JSON() bool {
+	return o.json
+}
+
+// Progressf writes a progress message in human mode only, so JSON output
+// stays machine-parseable
+func (o *output) Progressf(format string, args ...interface{}) {
+	if o.json {
+		return
+	}
+	fmt.Fprintf(o.w, format+"\n", args...)
+}
+
+// Render writes v as JSON, or calls human with a tab-aligned writer
+func (o *output) Render(v interface{}, human func(w io.Writer)) error {
+	if o.json {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode output: %w", err)
+		}
+		_, err = fmt.Fprintln(o.w, string(data))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	human(tw)
+	return tw.Flush()
+}
+
+// actionResult is the JSON form of a command that performs an action
+type actionResult struct {
+	Status  string                 `json:"status"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Done reports a completed action
+func (o *output) Done(message string, details map[string]interface{}) error {
+	return o.Render(actionResult{Status: "ok", Message: message, Details: details}, func(w io.Writer) {
+		fmt.Fprintf(w, "✓ %s\n", message)
+	})
+}
+
+// heading writes an underlined table title
+func heading(w io.Writer, title string) {
+	fmt.Fprintln(w, title)
+	fmt.Fprintln(w, strings.Repeat("=", len(title)))
+}
+
+// checkMark returns a check for a healthy status and a cross otherwise
+func checkMark(status string) string {
+	if status == "healthy" {
+		return "✓"
+	}
+	return "✗"
+}
+
+// statusMark renders a health status with its check mark
+func statusMark(status string) string {
+	return checkMark(status) + " " + status
+}
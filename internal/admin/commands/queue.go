@@ -1,9 +1,14 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"time"
 
+	"github.com/gongahkia/kite/internal/config"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -12,18 +17,43 @@ func NewQueueCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "queue",
 		Short: "Job queue management commands",
-		Long:  "Inspect and manage the job queue (list, stats, purge, retry)",
+		Long:  "Inspect and manage the job queue (list, stats, status, purge, retry, replay)",
 	}
 
 	cmd.AddCommand(newQueueListCmd())
 	cmd.AddCommand(newQueueStatsCmd())
+	cmd.AddCommand(newQueueStatusCmd())
 	cmd.AddCommand(newQueuePurgeCmd())
 	cmd.AddCommand(newQueueRetryCmd())
 	cmd.AddCommand(newQueueDLQCmd())
+	cmd.AddCommand(newQueueReplayCmd())
 
 	return cmd
 }
 
+// queuedJob is a job as listed by the queue commands
+type queuedJob struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Priority  string `json:"priority,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	FailedAt  string `json:"failed_at,omitempty"`
+}
+
+// queueStats summarises queue throughput
+type queueStats struct {
+	TotalJobs           int     `json:"total_jobs"`
+	Pending             int     `json:"pending"`
+	Running             int     `json:"running"`
+	Completed           int     `json:"completed"`
+	Failed              int     `json:"failed"`
+	ThroughputPerMinute float64 `json:"throughput_per_minute"`
+	AvgDurationSeconds  float64 `json:"avg_duration_seconds"`
+	DLQSize             int     `json:"dlq_size"`
+}
+
 func newQueueListCmd() *cobra.Command {
 	var (
 		status string
@@ -35,26 +65,21 @@ func newQueueListCmd() *cobra.Command {
 		Short: "List jobs in queue",
 		Long:  "Display jobs in the queue with optional status filter",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`[
-  {"id": "job_001", "type": "scrape", "status": "pending", "priority": "high", "created_at": "2023-12-16T10:00:00Z"},
-  {"id": "job_002", "type": "export", "status": "running", "priority": "normal", "created_at": "2023-12-16T10:01:00Z"},
-  {"id": "job_003", "type": "validate", "status": "pending", "priority": "low", "created_at": "2023-12-16T10:02:00Z"}
-]`)
-			} else {
-				fmt.Println("Job Queue:")
-				fmt.Println("==========")
-				fmt.Println("ID        Type       Status    Priority  Created At")
-				fmt.Println("--------  ---------  --------  --------  ----------")
-				fmt.Println("job_001   scrape     pending   high      10:00:00")
-				fmt.Println("job_002   export     running   normal    10:01:00")
-				fmt.Println("job_003   validate   pending   low       10:02:00")
-				fmt.Printf("\n(showing %d of 152 jobs)\n", limit)
+			jobs := []queuedJob{
+				{ID: "job_001", Type: "scrape", Status: "pending", Priority: "high", CreatedAt: "2023-12-16T10:00:00Z"},
+				{ID: "job_002", Type: "export", Status: "running", Priority: "normal", CreatedAt: "2023-12-16T10:01:00Z"},
+				{ID: "job_003", Type: "validate", Status: "pending", Priority: "low", CreatedAt: "2023-12-16T10:02:00Z"},
 			}
 
-			return nil
+			return newOutput(cmd).Render(jobs, func(w io.Writer) {
+				heading(w, "Job Queue:")
+				fmt.Fprintln(w, "ID\tType\tStatus\tPriority\tCreated At")
+				fmt.Fprintln(w, "--------\t---------\t--------\t--------\t----------")
+				for _, job := range jobs {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", job.ID, job.Type, job.Status, job.Priority, clockTime(job.CreatedAt))
+				}
+				fmt.Fprintf(w, "\n(showing %d of 152 jobs)\n", limit)
+			})
 		},
 	}
 
@@ -70,37 +95,132 @@ func newQueueStatsCmd() *cobra.Command {
 		Short: "Show queue statistics",
 		Long:  "Display queue size, throughput, and other metrics",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			jsonOutput, _ := cmd.Flags().GetBool("json")
-
-			if jsonOutput {
-				fmt.Println(`{
-  "total_jobs": 152,
-  "pending": 98,
-  "running": 12,
-  "completed": 1453,
-  "failed": 31,
-  "throughput_per_minute": 42.3,
-  "avg_duration_seconds": 2.8,
-  "dlq_size": 5
-}`)
-			} else {
-				fmt.Println("Queue Statistics:")
-				fmt.Println("=================")
-				fmt.Println("Total Jobs:       152")
-				fmt.Println("Pending:          98")
-				fmt.Println("Running:          12")
-				fmt.Println("Completed:        1,453")
-				fmt.Println("Failed:           31")
-				fmt.Println("Throughput:       42.3 jobs/min")
-				fmt.Println("Avg Duration:     2.8s")
-				fmt.Println("DLQ Size:         5")
+			stats := queueStats{
+				TotalJobs:           152,
+				Pending:             98,
+				Running:             12,
+				Completed:           1453,
+				Failed:              31,
+				ThroughputPerMinute: 42.3,
+				AvgDurationSeconds:  2.8,
+				DLQSize:             5,
 			}
 
-			return nil
+			return newOutput(cmd).Render(stats, func(w io.Writer) {
+				heading(w, "Queue Statistics:")
+				fmt.Fprintf(w, "Total Jobs:\t%d\n", stats.TotalJobs)
+				fmt.Fprintf(w, "Pending:\t%d\n", stats.Pending)
+				fmt.Fprintf(w, "Running:\t%d\n", stats.Running)
+				fmt.Fprintf(w, "Completed:\t%d\n", stats.Completed)
+				fmt.Fprintf(w, "Failed:\t%d\n", stats.Failed)
+				fmt.Fprintf(w, "Throughput:\t%.1f jobs/min\n", stats.ThroughputPerMinute)
+				fmt.Fprintf(w, "Avg Duration:\t%.1fs\n", stats.AvgDurationSeconds)
+				fmt.Fprintf(w, "DLQ Size:\t%d\n", stats.DLQSize)
+			})
 		},
 	}
 }
 
+// queueStatusRow is the JSON form of a queue's live status
+type queueStatusRow struct {
+	Name                    string  `json:"name"`
+	Backend                 string  `json:"backend"`
+	Depth                   int64   `json:"depth"`
+	InFlight                int64   `json:"in_flight"`
+	DeadLetter              int64   `json:"dead_letter"`
+	OldestMessageAgeSeconds float64 `json:"oldest_message_age_seconds"`
+}
+
+func newQueueStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show live queue status",
+		Long:  "Report per-queue depth, in-flight jobs, dead letter depth and oldest message age read from the queue backend",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			q, err := openQueue(cfg)
+			if err != nil {
+				return err
+			}
+			defer q.Close()
+
+			inspector, ok := q.(queue.Inspector)
+			if !ok {
+				return fmt.Errorf("queue driver %q does not support inspection", cfg.Queue.Driver)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			statuses, err := inspector.Inspect(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to inspect queue: %w", err)
+			}
+
+			return renderQueueStatus(newOutput(cmd), statuses)
+		},
+	}
+}
+
+// renderQueueStatus writes one row per inspected queue
+func renderQueueStatus(out *output, statuses []queue.QueueStatus) error {
+	rows := make([]queueStatusRow, 0, len(statuses))
+	for _, s := range statuses {
+		rows = append(rows, queueStatusRow{
+			Name:                    s.Name,
+			Backend:                 s.Backend,
+			Depth:                   s.Depth,
+			InFlight:                s.InFlight,
+			DeadLetter:              s.DeadLetter,
+			OldestMessageAgeSeconds: s.OldestMessageAge.Seconds(),
+		})
+	}
+
+	return out.Render(rows, func(w io.Writer) {
+		heading(w, "Queue Status:")
+		fmt.Fprintln(w, "Queue\tBackend\tDepth\tIn-Flight\tDead Letter\tOldest")
+		fmt.Fprintln(w, "-----\t-------\t-----\t---------\t-----------\t------")
+		for _, s := range statuses {
+			oldest := "-"
+			if s.OldestMessageAge > 0 {
+				oldest = s.OldestMessageAge.Round(time.Second).String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%s\n", s.Name, s.Backend, s.Depth, s.InFlight, s.DeadLetter, oldest)
+		}
+	})
+}
+
+// openQueue connects to the configured queue backend. The memory queue lives
+// inside the API process, so it cannot be inspected from here.
+func openQueue(cfg *config.Config) (queue.Queue, error) {
+	switch cfg.Queue.Driver {
+	case "redis":
+		redisConfig := queue.DefaultRedisQueueConfig()
+		redisConfig.Addr = fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port)
+		redisConfig.Password = cfg.Redis.Password
+		redisConfig.DB = cfg.Redis.DB
+		redisConfig.EnableMetrics = false
+		return queue.NewRedisQueue(redisConfig)
+
+	case "nats":
+		natsConfig := queue.DefaultNATSQueueConfig()
+		natsConfig.URL = cfg.Queue.URL
+		natsConfig.Consumer = "kite-worker"
+		natsConfig.EnableMetrics = false
+		return queue.NewNATSQueue(natsConfig)
+
+	case "memory", "":
+		return nil, fmt.Errorf("the memory queue lives inside the API process and cannot be inspected remotely")
+
+	default:
+		return nil, fmt.Errorf("unsupported queue driver: %s", cfg.Queue.Driver)
+	}
+}
+
 func newQueuePurgeCmd() *cobra.Command {
 	var (
 		status string
@@ -112,22 +232,27 @@ func newQueuePurgeCmd() *cobra.Command {
 		Short: "Purge jobs from queue",
 		Long:  "Remove jobs from the queue by status",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+
 			if !force {
-				fmt.Println("This will permanently delete jobs. Use --force to confirm.")
-				return nil
+				message := "This will permanently delete jobs. Use --force to confirm."
+				return out.Render(actionResult{Status: "skipped", Message: message}, func(w io.Writer) {
+					fmt.Fprintln(w, message)
+				})
 			}
 
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Printf("Purging jobs with status: %s\n", status)
+				out.Progressf("Purging jobs with status: %s", status)
 			}
 
 			// In real implementation, would delete jobs from queue
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Printf("✓ Purged %s jobs from queue\n", status)
-			return nil
+			return out.Done(fmt.Sprintf("Purged %s jobs from queue", status), map[string]interface{}{
+				"status": status,
+			})
 		},
 	}
 
@@ -146,17 +271,19 @@ func newQueueRetryCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			jobID := args[0]
 
+			out := newOutput(cmd)
 			verbose, _ := cmd.Flags().GetBool("verbose")
 
 			if verbose {
-				fmt.Printf("Retrying job: %s\n", jobID)
+				out.Progressf("Retrying job: %s", jobID)
 			}
 
 			// In real implementation, would requeue the job
 			time.Sleep(100 * time.Millisecond)
 
-			fmt.Printf("✓ Job %s has been requeued\n", jobID)
-			return nil
+			return out.Done(fmt.Sprintf("Job %s has been requeued", jobID), map[string]interface{}{
+				"job_id": jobID,
+			})
 		},
 	}
 }
@@ -173,15 +300,20 @@ func newQueueDLQCmd() *cobra.Command {
 		Short: "List DLQ jobs",
 		Long:  "Display jobs in the dead letter queue",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Dead Letter Queue:")
-			fmt.Println("==================")
-			fmt.Println("ID        Type       Reason                  Failed At")
-			fmt.Println("--------  ---------  ----------------------  ----------")
-			fmt.Println("job_101   scrape     Max retries exceeded    09:30:00")
-			fmt.Println("job_142   export     Permanent error         09:45:00")
-			fmt.Println("job_189   validate   Timeout                 10:15:00")
-
-			return nil
+			jobs := []queuedJob{
+				{ID: "job_101", Type: "scrape", Status: "failed", Reason: "Max retries exceeded", FailedAt: "2023-12-16T09:30:00Z"},
+				{ID: "job_142", Type: "export", Status: "failed", Reason: "Permanent error", FailedAt: "2023-12-16T09:45:00Z"},
+				{ID: "job_189", Type: "validate", Status: "failed", Reason: "Timeout", FailedAt: "2023-12-16T10:15:00Z"},
+			}
+
+			return newOutput(cmd).Render(jobs, func(w io.Writer) {
+				heading(w, "Dead Letter Queue:")
+				fmt.Fprintln(w, "ID\tType\tReason\tFailed At")
+				fmt.Fprintln(w, "--------\t---------\t----------------------\t----------")
+				for _, job := range jobs {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", job.ID, job.Type, job.Reason, clockTime(job.FailedAt))
+				}
+			})
 		},
 	})
 
@@ -190,10 +322,12 @@ func newQueueDLQCmd() *cobra.Command {
 		Short: "Retry all DLQ jobs",
 		Long:  "Retry all jobs in the dead letter queue",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Retrying all DLQ jobs...")
+			out := newOutput(cmd)
+			out.Progressf("Retrying all DLQ jobs...")
 			time.Sleep(200 * time.Millisecond)
-			fmt.Println("✓ Requeued 5 jobs from DLQ")
-			return nil
+			return out.Done("Requeued 5 jobs from DLQ", map[string]interface{}{
+				"requeued": 5,
+			})
 		},
 	})
 
@@ -202,12 +336,97 @@ func newQueueDLQCmd() *cobra.Command {
 		Short: "Clear DLQ",
 		Long:  "Remove all jobs from the dead letter queue",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("Clearing DLQ...")
+			out := newOutput(cmd)
+			out.Progressf("Clearing DLQ...")
 			time.Sleep(100 * time.Millisecond)
-			fmt.Println("✓ DLQ cleared successfully")
-			return nil
+			return out.Done("DLQ cleared successfully", nil)
 		},
 	})
 
 	return cmd
 }
+
+func newQueueReplayCmd() *cobra.Command {
+	var (
+		jobType string
+		since   string
+		apiURL  string
+		apiKey  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Replay failed jobs",
+		Long:  "Requeue failed jobs from the dead letter queue through the API, respecting the max replay count",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			if apiURL == "" {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				apiURL = fmt.Sprintf("http://localhost:%d", cfg.Server.Port)
+			}
+
+			params := client.ReplayJobsParams{Type: jobType}
+			if since != "" {
+				t, err := parseSince(since)
+				if err != nil {
+					return err
+				}
+				params.Since = &t
+			}
+
+			if verbose {
+				out.Progressf("Replaying failed %s jobs via %s", jobType, apiURL)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+
+			result, err := client.NewClient(apiURL, apiKey).ReplayFailedJobs(ctx, params)
+			if err != nil {
+				return fmt.Errorf("failed to replay jobs: %w", err)
+			}
+
+			return out.Render(result, func(w io.Writer) {
+				fmt.Fprintf(w, "✓ Replayed %d failed jobs\n", len(result.Replayed))
+				if len(result.Skipped) > 0 {
+					fmt.Fprintf(w, "  %d jobs skipped (already replayed %d times)\n", len(result.Skipped), result.MaxReplays)
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&jobType, "type", "t", "scrape", "Job type to replay")
+	cmd.Flags().StringVar(&since, "since", "", "Only replay jobs that failed after this time (RFC3339 or duration like 24h)")
+	cmd.Flags().StringVar(&apiURL, "api-url", "", "Kite API base URL (defaults to localhost and the configured server port)")
+	cmd.Flags().StringVar(&apiKey, "api-key", "", "Admin JWT used to authenticate against the API")
+
+	return cmd
+}
+
+// clockTime formats an RFC3339 timestamp as a time of day for tables
+func clockTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.Format("15:04:05")
+}
+
+// parseSince accepts either an RFC3339 timestamp or a duration relative to now
+func parseSince(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since value %q: expected RFC3339 timestamp or duration", value)
+	}
+
+	return time.Now().Add(-d), nil
+}
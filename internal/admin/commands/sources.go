@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gongahkia/kite/internal/config"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/scraper/jurisdictions"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/worker"
+	"github.com/spf13/cobra"
+)
+
+// NewSourcesCmd creates the sources command
+func NewSourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sources",
+		Short: "Scraper source commands",
+		Long:  "Inspect and test the enabled scraper sources",
+	}
+
+	cmd.AddCommand(newSourcesTestCmd())
+	cmd.AddCommand(newSourcesScheduleCmd())
+	cmd.AddCommand(newSourcesHealthCmd())
+
+	return cmd
+}
+
+// sourcesTestReport is the result of self-testing the enabled sources
+type sourcesTestReport struct {
+	Passed  int                      `json:"passed"`
+	Failed  int                      `json:"failed"`
+	Results []scraper.SelfTestResult `json:"results"`
+}
+
+func newSourcesTestCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "test [source...]",
+		Short: "Self-test scraper sources",
+		Long: `Fetch a known sample case from each enabled source and check that its name,
+decision date and court were extracted. Pass source names to test only those.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			scrapers, err := selectSources(newSourceRegistry(cfg), args)
+			if err != nil {
+				return err
+			}
+
+			out := newOutput(cmd)
+			out.Progressf("Testing %d sources...", len(scrapers))
+
+			results := scraper.SelfTest(context.Background(), scrapers, timeout)
+			report := sourcesTestReport{Results: results}
+			for _, r := range results {
+				if r.Passed {
+					report.Passed++
+				} else {
+					report.Failed++
+				}
+			}
+
+			err = out.Render(report, func(w io.Writer) {
+				heading(w, "Source Self-Test:")
+				for _, r := range results {
+					status := "healthy"
+					if !r.Passed {
+						status = "failed"
+					}
+					fmt.Fprintf(w, "%s %s\t%s\t%s\n", checkMark(status), r.Source, r.Jurisdiction, r.Duration.Round(time.Millisecond))
+					if r.Error != "" {
+						fmt.Fprintf(w, "  %s\n", r.Error)
+					}
+				}
+				fmt.Fprintln(w)
+				fmt.Fprintf(w, "Passed:\t%d\n", report.Passed)
+				fmt.Fprintf(w, "Failed:\t%d\n", report.Failed)
+			})
+			if err != nil {
+				return err
+			}
+
+			if report.Failed > 0 {
+				return fmt.Errorf("%d of %d sources failed validation", report.Failed, len(results))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVarP(&timeout, "timeout", "t", time.Minute, "Time allowed for each source")
+
+	return cmd
+}
+
+func newSourcesScheduleCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schedule",
+		Short: "Show scheduled scrapes",
+		Long:  "Show each source's incremental scrape schedule and when it next runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			scheduler, err := worker.NewScrapeScheduler(nil, newSourceRegistry(cfg), cfg.Scraper.Schedules)
+			if err != nil {
+				return err
+			}
+			schedules := scheduler.Schedules()
+
+			return newOutput(cmd).Render(schedules, func(w io.Writer) {
+				heading(w, "Scheduled Scrapes:")
+				if len(schedules) == 0 {
+					fmt.Fprintln(w, "No scrapes scheduled (set scraper.schedules)")
+					return
+				}
+				for _, sched := range schedules {
+					next := "never"
+					if !sched.NextRun.IsZero() {
+						next = sched.NextRun.Format(time.RFC3339)
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\tnext run %s\n", sched.Source, sched.Jurisdiction, sched.Schedule, next)
+				}
+			})
+		},
+	}
+}
+
+func newSourcesHealthCmd() *cobra.Command {
+	var staleAfter time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Show how fresh each source's cases are",
+		Long: `Show how many cases are stored from each source, how many were not updated
+within the stale-after window, and when the oldest was last updated. The window
+defaults to worker.refresh_max_age.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if staleAfter <= 0 {
+				staleAfter = cfg.Worker.RefreshMaxAge
+			}
+			if staleAfter <= 0 {
+				return fmt.Errorf("stale-after must be positive")
+			}
+
+			out := newOutput(cmd)
+			out.Progressf("Checking source freshness...")
+
+			db, err := initStorage(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			sources, err := storage.CaseFreshness(context.Background(), db, staleAfter, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to check freshness: %w", err)
+			}
+
+			return out.Render(sources, func(w io.Writer) {
+				heading(w, "Source Freshness:")
+				if len(sources) == 0 {
+					fmt.Fprintln(w, "No cases stored")
+					return
+				}
+				for _, s := range sources {
+					status := "healthy"
+					if s.Stale > 0 {
+						status = "stale"
+					}
+					fmt.Fprintf(w, "%s %s\t%d cases\t%d stale\toldest updated %s ago\n",
+						checkMark(status), s.Source, s.Cases, s.Stale, s.OldestAge.Round(time.Hour))
+				}
+				fmt.Fprintln(w)
+				fmt.Fprintf(w, "Stale after:\t%s\n", staleAfter)
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&staleAfter, "stale-after", 0, "Age after which a case is stale (default worker.refresh_max_age)")
+
+	return cmd
+}
+
+// newSourceRegistry registers the scrapers enabled by the configuration
+func newSourceRegistry(cfg *config.Config) *scraper.ScraperRegistry {
+	registry := scraper.NewScraperRegistry()
+	registry.SetJurisdictionFilter(scraper.NewJurisdictionFilter(cfg.Scraper.EnabledJurisdictions))
+	jurisdictions.RegisterAll(registry)
+	return registry
+}
+
+// selectSources returns the named scrapers, or every registered scraper when
+// no names are given
+func selectSources(registry *scraper.ScraperRegistry, names []string) (map[string]scraper.Scraper, error) {
+	if len(names) == 0 {
+		return registry.GetAll(), nil
+	}
+
+	selected := make(map[string]scraper.Scraper, len(names))
+	for _, name := range names {
+		s, ok := registry.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown or disabled source: %s", name)
+		}
+		selected[name] = s
+	}
+	return selected, nil
+}
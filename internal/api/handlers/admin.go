@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+)
+
+// AdminHandler handles administrative requests
+type AdminHandler struct {
+	storage    storage.Storage
+	queue      queue.Queue
+	maxReplays int
+	logger     *observability.Logger
+}
+
+// NewAdminHandler creates a new AdminHandler. The queue may be nil when job
+// replay is not available.
+func NewAdminHandler(storage storage.Storage, q queue.Queue, maxReplays int, logger *observability.Logger) *AdminHandler {
+	return &AdminHandler{
+		storage:    storage,
+		queue:      q,
+		maxReplays: maxReplays,
+		logger:     logger,
+	}
+}
+
+// deleteCasesRequest is a case filter plus the delete options
+type deleteCasesRequest struct {
+	storage.CaseFilter
+	storage.DeleteOptions
+}
+
+// DeleteCases handles POST /api/v1/admin/cases/delete. The body is a case
+// filter; an empty filter is rejected unless "force" is true.
+func (h *AdminHandler) DeleteCases(c *fiber.Ctx) error {
+	var req deleteCasesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	deleted, err := h.storage.DeleteCasesByFilter(c.Context(), req.CaseFilter, req.DeleteOptions)
+	if errors.Is(err, storage.ErrEmptyDeleteFilter) || errors.Is(err, storage.ErrUnsupportedDeleteFilter) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	h.logger.Infof("Deleted %d cases by filter", deleted)
+
+	return c.JSON(fiber.Map{
+		"deleted": deleted,
+	})
+}
+
+// ReplayJobs handles POST /api/v1/admin/jobs/replay
+func (h *AdminHandler) ReplayJobs(c *fiber.Ctx) error {
+	provider, ok := h.queue.(queue.DLQProvider)
+	if !ok || provider.GetDLQ() == nil {
+		return fiber.NewError(fiber.StatusNotImplemented, "Job replay not supported by this queue backend")
+	}
+
+	status := queue.JobStatus(c.Query("status", string(queue.JobStatusFailed)))
+	if status != queue.JobStatusFailed {
+		return fiber.NewError(fiber.StatusBadRequest, "Only failed jobs can be replayed")
+	}
+
+	filter := queue.ReplayFilter{
+		Status:     status,
+		Type:       queue.JobType(c.Query("type", string(queue.JobTypeScrape))),
+		MaxReplays: h.maxReplays,
+	}
+
+	if since := c.Query("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid since parameter, expected RFC3339 timestamp")
+		}
+		filter.Since = &t
+	}
+
+	result, err := queue.ReplayJobs(c.Context(), h.queue, provider.GetDLQ(), filter)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Infof("Replayed %d failed jobs (%d skipped at replay limit)", len(result.Replayed), len(result.Skipped))
+
+	return c.JSON(fiber.Map{
+		"replayed":    result.Replayed,
+		"skipped":     result.Skipped,
+		"max_replays": h.maxReplays,
+	})
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/storage"
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/google/uuid"
+)
+
+// AnnotationHandler handles the private notes and tags users attach to
+// cases. Each caller only ever sees and changes its own annotations.
+type AnnotationHandler struct {
+	storage     storage.Storage
+	annotations storage.AnnotationStore
+	logger      *observability.Logger
+}
+
+// NewAnnotationHandler creates a new AnnotationHandler
+func NewAnnotationHandler(store storage.Storage, logger *observability.Logger) *AnnotationHandler {
+	annotations, _ := storage.Annotations(store)
+	return &AnnotationHandler{
+		storage:     store,
+		annotations: annotations,
+		logger:      logger,
+	}
+}
+
+// AnnotationRequest is the body of annotation create and update requests
+type AnnotationRequest struct {
+	Note string   `json:"note"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// owner returns the filter selecting the caller's annotations on the case in
+// the path. The caller is the JWT's user, or else the API key's client, within
+// its tenant; anonymous callers have no annotations.
+func (h *AnnotationHandler) owner(c *fiber.Ctx) (storage.AnnotationFilter, error) {
+	if h.annotations == nil {
+		return storage.AnnotationFilter{}, fiber.NewError(fiber.StatusNotImplemented, "Annotations not supported by this storage backend")
+	}
+
+	userID, tenantID := caller(c)
+	if userID == "" {
+		return storage.AnnotationFilter{}, fiber.NewError(fiber.StatusUnauthorized, "Annotations require authentication")
+	}
+
+	return storage.AnnotationFilter{CaseID: c.Params("id"), UserID: userID, TenantID: tenantID}, nil
+}
+
+// caller returns the JWT's user, or else the API key's client, and its
+// tenant; the user is empty for anonymous callers
+func caller(c *fiber.Ctx) (userID, tenantID string) {
+	userID, _ = c.Locals("user_id").(string)
+	if userID == "" {
+		userID, _ = c.Locals("client_id").(string)
+	}
+	tenantID, _ = c.Locals(storage.TenantScopeKey).(string)
+	return userID, tenantID
+}
+
+// parseAnnotation reads an annotation request, trimming blank tags
+func parseAnnotation(c *fiber.Ctx) (AnnotationRequest, error) {
+	var req AnnotationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	tags := make([]string, 0, len(req.Tags))
+	for _, tag := range req.Tags {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	req.Tags = tags
+
+	if strings.TrimSpace(req.Note) == "" && len(req.Tags) == 0 {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Annotation needs a note or tags")
+	}
+	return req, nil
+}
+
+// ownAnnotation loads an annotation on the case in the path, reporting
+// another caller's annotation exactly as a missing one
+func (h *AnnotationHandler) ownAnnotation(c *fiber.Ctx, owner storage.AnnotationFilter) (*models.Annotation, error) {
+	a, err := h.annotations.GetAnnotation(c.Context(), c.Params("annotation_id"))
+	if errors.Is(err, kiteerrors.ErrNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Annotation not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if a.CaseID != owner.CaseID || a.UserID != owner.UserID || a.TenantID != owner.TenantID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Annotation not found")
+	}
+	return a, nil
+}
+
+// CreateAnnotation handles POST /api/v1/cases/:id/annotations
+func (h *AnnotationHandler) CreateAnnotation(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseAnnotation(c)
+	if err != nil {
+		return err
+	}
+
+	// Only cases the caller can read may be annotated
+	if _, err := h.storage.GetCase(c.Context(), owner.CaseID); err != nil {
+		if errors.Is(err, kiteerrors.ErrNotFound) {
+			return fiber.NewError(fiber.StatusNotFound, "Case not found")
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	annotation := &models.Annotation{
+		ID:        uuid.New().String(),
+		CaseID:    owner.CaseID,
+		UserID:    owner.UserID,
+		TenantID:  owner.TenantID,
+		Note:      req.Note,
+		Tags:      req.Tags,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := h.annotations.SaveAnnotation(c.Context(), annotation); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(annotation)
+}
+
+// ListAnnotations handles GET /api/v1/cases/:id/annotations, listing the
+// caller's annotations on the case oldest first
+func (h *AnnotationHandler) ListAnnotations(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := h.annotations.ListAnnotations(c.Context(), owner)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  annotations,
+		"total": len(annotations),
+	})
+}
+
+// UpdateAnnotation handles PUT /api/v1/cases/:id/annotations/:annotation_id,
+// replacing the note and tags
+func (h *AnnotationHandler) UpdateAnnotation(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseAnnotation(c)
+	if err != nil {
+		return err
+	}
+	annotation, err := h.ownAnnotation(c, owner)
+	if err != nil {
+		return err
+	}
+
+	annotation.Note = req.Note
+	annotation.Tags = req.Tags
+	annotation.UpdatedAt = time.Now().UTC()
+	if err := h.annotations.SaveAnnotation(c.Context(), annotation); err != nil {
+		return err
+	}
+
+	return c.JSON(annotation)
+}
+
+// DeleteAnnotation handles DELETE /api/v1/cases/:id/annotations/:annotation_id
+func (h *AnnotationHandler) DeleteAnnotation(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	annotation, err := h.ownAnnotation(c, owner)
+	if err != nil {
+		return err
+	}
+
+	if err := h.annotations.DeleteAnnotation(c.Context(), annotation.ID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
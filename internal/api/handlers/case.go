@@ -1,9 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/api/middleware"
+	"github.com/gongahkia/kite/internal/jurisdiction"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/storage"
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -11,23 +18,74 @@ import (
 type CaseHandler struct {
 	storage storage.Storage
 	logger  *observability.Logger
+	courts  *jurisdiction.CourtHierarchy
+	window  storage.ResultWindow
 }
 
 // NewCaseHandler creates a new CaseHandler
-func NewCaseHandler(storage storage.Storage, logger *observability.Logger) *CaseHandler {
+func NewCaseHandler(store storage.Storage, logger *observability.Logger) *CaseHandler {
 	return &CaseHandler{
-		storage: storage,
+		storage: store,
 		logger:  logger,
+		courts:  jurisdiction.NewCourtHierarchy(),
+		window:  storage.DefaultResultWindow(),
 	}
 }
 
-// ListCases handles GET /api/v1/cases
+// SetResultWindow sets the default page size of list and search requests
+func (h *CaseHandler) SetResultWindow(window storage.ResultWindow) {
+	h.window = window
+}
+
+// ListCases handles GET /api/v1/cases. The jurisdiction and court query
+// parameters may be repeated to match any of several values. Cases are
+// listed without full text unless include_full_text is true, and staler_than
+// (a duration such as "720h") lists only cases not updated within it.
+// order_by=authority lists cases from the highest courts first, most recent
+// first at each level. With "Accept: application/x-ndjson" the cases are
+// streamed one per line as they are fetched from storage, every match unless
+// limit is set.
 func (h *CaseHandler) ListCases(c *fiber.Ctx) error {
 	filter := storage.CaseFilter{
-		Jurisdiction: c.Query("jurisdiction"),
-		Court:        c.Query("court"),
-		Limit:        c.QueryInt("limit", 10),
-		Offset:       c.QueryInt("offset", 0),
+		Jurisdictions:          queryValues(c, "jurisdiction"),
+		CourtID:                c.Query("court_id"),
+		ExtractionVersionBelow: c.QueryInt("extraction_version_below", 0),
+		IncludeFullText:        c.QueryBool("include_full_text", false),
+		Limit:                  h.window.Limit(c.QueryInt("limit", 0)),
+		Offset:                 c.QueryInt("offset", 0),
+	}
+
+	if stalerThan := c.Query("staler_than"); stalerThan != "" {
+		maxAge, err := time.ParseDuration(stalerThan)
+		if err != nil || maxAge <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid staler_than duration")
+		}
+		filter.StalerThan = maxAge
+	}
+
+	// Match court variants ("UKSC", "UK Supreme Court") on the normalized
+	// identifier. Cases stored before courts were normalized have none, so
+	// they match by any of the court's names instead.
+	for _, court := range queryValues(c, "court") {
+		if courtID, ok := h.courts.ResolveCourtID(court); ok {
+			filter.CourtIDs = append(filter.CourtIDs, courtID)
+		}
+		filter.Courts = append(filter.Courts, h.courts.CourtNames(court)...)
+	}
+
+	// Only authority ordering can be requested
+	filter.OrderBy = c.Query("order_by")
+	if filter.OrderBy != "" && filter.OrderBy != storage.OrderByAuthority {
+		return fiber.NewError(fiber.StatusBadRequest, "order_by must be authority")
+	}
+
+	if middleware.AcceptsNDJSON(c) {
+		filter.Limit = c.QueryInt("limit", 0)
+		return streamNDJSON(c, h.logger, func(ctx context.Context, emit func(v interface{}) error) error {
+			return storage.StreamCases(ctx, h.storage, filter, func(found *models.Case) error {
+				return emit(found)
+			})
+		})
 	}
 
 	cases, err := h.storage.ListCases(c.Context(), filter)
@@ -38,9 +96,9 @@ func (h *CaseHandler) ListCases(c *fiber.Ctx) error {
 	total, _ := h.storage.CountCases(c.Context(), filter)
 
 	return c.JSON(fiber.Map{
-		"data":  cases,
-		"total": total,
-		"limit": filter.Limit,
+		"data":   cases,
+		"total":  total,
+		"limit":  filter.Limit,
 		"offset": filter.Offset,
 	})
 }
@@ -57,21 +115,31 @@ func (h *CaseHandler) GetCase(c *fiber.Ctx) error {
 	return c.JSON(caseData)
 }
 
-// CreateCase handles POST /api/v1/cases
+// CreateCase handles POST /api/v1/cases. A case whose ID is already taken is
+// rejected with 409 Conflict; use PUT to replace it. A status outside the
+// case lifecycle is rejected with 400 Bad Request.
 func (h *CaseHandler) CreateCase(c *fiber.Ctx) error {
 	var caseData models.Case
 	if err := c.BodyParser(&caseData); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if err := h.storage.SaveCase(c.Context(), &caseData); err != nil {
+	err := h.storage.CreateCase(c.Context(), &caseData)
+	if errors.Is(err, kiteerrors.ErrAlreadyExists) {
+		return fiber.NewError(fiber.StatusConflict, "Case already exists")
+	}
+	if errors.Is(err, storage.ErrIllegalStatusTransition) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
 		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(caseData)
 }
 
-// UpdateCase handles PUT /api/v1/cases/:id
+// UpdateCase handles PUT /api/v1/cases/:id. An update that makes an illegal
+// status transition is rejected with 409 Conflict.
 func (h *CaseHandler) UpdateCase(c *fiber.Ctx) error {
 	id := c.Params("id")
 
@@ -82,7 +150,11 @@ func (h *CaseHandler) UpdateCase(c *fiber.Ctx) error {
 
 	caseData.ID = id
 
-	if err := h.storage.UpdateCase(c.Context(), &caseData); err != nil {
+	err := h.storage.UpdateCase(c.Context(), &caseData)
+	if errors.Is(err, storage.ErrIllegalStatusTransition) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
 		return err
 	}
 
@@ -107,9 +179,7 @@ func (h *CaseHandler) SearchCases(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
 
-	if query.Limit == 0 {
-		query.Limit = 10
-	}
+	query.Limit = h.window.Limit(query.Limit)
 
 	cases, err := h.storage.SearchCases(c.Context(), query)
 	if err != nil {
@@ -124,3 +194,14 @@ func (h *CaseHandler) SearchCases(c *fiber.Ctx) error {
 		"offset": query.Offset,
 	})
 }
+
+// queryValues returns every non-empty value of a repeated query parameter
+func queryValues(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, v := range c.Context().QueryArgs().PeekMulti(key) {
+		if len(v) > 0 {
+			values = append(values, string(v))
+		}
+	}
+	return values
+}
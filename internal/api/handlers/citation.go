@@ -1,6 +1,13 @@
 package handlers
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/storage"
@@ -11,23 +18,30 @@ import (
 type CitationHandler struct {
 	storage storage.Storage
 	logger  *observability.Logger
+	window  storage.ResultWindow
 }
 
 // NewCitationHandler creates a new CitationHandler
-func NewCitationHandler(storage storage.Storage, logger *observability.Logger) *CitationHandler {
+func NewCitationHandler(store storage.Storage, logger *observability.Logger) *CitationHandler {
 	return &CitationHandler{
-		storage: storage,
+		storage: store,
 		logger:  logger,
+		window:  storage.DefaultResultWindow(),
 	}
 }
 
+// SetResultWindow sets the default page size of list requests
+func (h *CitationHandler) SetResultWindow(window storage.ResultWindow) {
+	h.window = window
+}
+
 // ListCitations handles GET /api/v1/citations
 func (h *CitationHandler) ListCitations(c *fiber.Ctx) error {
 	filter := storage.CitationFilter{
 		CaseID: c.Query("case_id"),
 		Format: c.Query("format"),
 		Year:   c.QueryInt("year", 0),
-		Limit:  c.QueryInt("limit", 10),
+		Limit:  h.window.Limit(c.QueryInt("limit", 0)),
 		Offset: c.QueryInt("offset", 0),
 	}
 
@@ -69,3 +83,194 @@ func (h *CitationHandler) CreateCitation(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusCreated).JSON(citation)
 }
+
+// citationImportBatchSize is how many imported citations are saved together
+const citationImportBatchSize = 500
+
+// maxImportErrors caps the rejected records listed in an import response
+const maxImportErrors = 100
+
+// citationImportRecord is a citation edge from an external dataset. The cited
+// case may be given as cited_case_id or case_id, either a stored case ID or a
+// citation naming it; it defaults to the raw citation. It is stored as the
+// citation's case ID.
+type citationImportRecord struct {
+	models.Citation
+	CitedCaseID string `json:"cited_case_id,omitempty"`
+}
+
+// CitationImportResult reports the outcome of a citation import
+type CitationImportResult struct {
+	Imported int      `json:"imported"`
+	Linked   int      `json:"linked"`   // both cases found in storage
+	Dangling int      `json:"dangling"` // saved with the citing or cited case unresolved
+	Rejected int      `json:"rejected"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// ImportCitations handles POST /api/v1/citations/import. The body is a stream
+// of citation records, newline-delimited or as a JSON array. Each record's
+// citing and cited cases are resolved against stored cases, by ID or else by
+// citation, and the citations are saved in batches. Citations whose cases
+// can't all be resolved are still saved, keeping the unresolved reference, and
+// counted as dangling so they can be linked once the cases are scraped.
+func (h *CitationHandler) ImportCitations(c *fiber.Ctx) error {
+	ctx := c.Context()
+	result := &CitationImportResult{}
+	resolved := make(map[string]string)
+	batch := make([]*models.Citation, 0, citationImportBatchSize)
+
+	flush := func() error {
+		if err := storage.SaveCitations(ctx, h.storage, batch); err != nil {
+			return err
+		}
+		result.Imported += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	err := decodeCitationRecords(c.Body(), func(n int, record *citationImportRecord, err error) error {
+		if err == nil {
+			err = h.linkImportedCitation(ctx, record, resolved, result)
+		}
+		if err != nil {
+			result.Rejected++
+			if len(result.Errors) < maxImportErrors {
+				result.Errors = append(result.Errors, fmt.Sprintf("record %d: %v", n, err))
+			}
+			return nil
+		}
+
+		batch = append(batch, &record.Citation)
+		if len(batch) == citationImportBatchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = flush()
+	}
+	if err != nil {
+		if h.logger != nil {
+			h.logger.Errorf("Citation import stopped after %d citations: %v", result.Imported, err)
+		}
+		return err
+	}
+
+	return c.JSON(result)
+}
+
+// linkImportedCitation resolves a record's citing and cited cases and fills
+// in the citation's defaults, counting it as linked or dangling
+func (h *CitationHandler) linkImportedCitation(ctx context.Context, record *citationImportRecord, resolved map[string]string, result *CitationImportResult) error {
+	cit := &record.Citation
+
+	cited := record.CitedCaseID
+	if cited == "" {
+		cited = cit.CaseID
+	}
+	if cited == "" {
+		cited = cit.RawCitation
+	}
+	if strings.TrimSpace(cited) == "" {
+		return fmt.Errorf("no cited case or raw citation")
+	}
+	if cit.RawCitation == "" {
+		cit.RawCitation = cited
+	}
+	if cit.Format == "" {
+		cit.Format = models.CitationFormatOther
+	}
+	if cit.ExtractedAt.IsZero() {
+		cit.ExtractedAt = time.Now()
+	}
+
+	citedID, err := h.resolveImportedCase(ctx, cited, resolved)
+	if err != nil {
+		return err
+	}
+	citingID, err := h.resolveImportedCase(ctx, cit.CitingCaseID, resolved)
+	if err != nil {
+		return err
+	}
+
+	cit.CaseID = cited
+	if citedID != "" {
+		cit.CaseID = citedID
+	}
+	if citingID != "" {
+		cit.CitingCaseID = citingID
+	}
+
+	if citedID != "" && citingID != "" {
+		result.Linked++
+	} else {
+		result.Dangling++
+	}
+	return nil
+}
+
+// resolveImportedCase returns the ID of the stored case ref names, as a case
+// ID or else a citation, or "" if there is none. Lookups are remembered for
+// the rest of the import, as a dataset cites the same cases many times.
+func (h *CitationHandler) resolveImportedCase(ctx context.Context, ref string, resolved map[string]string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", nil
+	}
+	if id, ok := resolved[ref]; ok {
+		return id, nil
+	}
+
+	id := ""
+	if stored, err := h.storage.GetCase(ctx, ref); err == nil && stored != nil {
+		id = stored.ID
+	} else {
+		found, err := storage.FindCaseByCitation(ctx, h.storage, ref)
+		if err != nil {
+			return "", err
+		}
+		if found != nil {
+			id = found.ID
+		}
+	}
+
+	resolved[ref] = id
+	return id, nil
+}
+
+// decodeCitationRecords decodes citation records from newline-delimited JSON
+// or a JSON array, calling handle with each record's 1-based line or array
+// position. A record that doesn't decode is passed with its error, so one bad
+// line doesn't stop the import; an array that isn't valid JSON is rejected.
+func decodeCitationRecords(body []byte, handle func(n int, record *citationImportRecord, err error) error) error {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var raws []json.RawMessage
+		if err := json.Unmarshal(trimmed, &raws); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+		for i, raw := range raws {
+			var record citationImportRecord
+			err := json.Unmarshal(raw, &record)
+			if err := handle(i+1, &record, err); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var record citationImportRecord
+		err := json.Unmarshal(line, &record)
+		if err := handle(i+1, &record, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/concepts"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// maxCooccurrenceLimit caps the related concepts returned per request
+const maxCooccurrenceLimit = 100
+
+// ConceptHandler handles legal concept analytics requests
+type ConceptHandler struct {
+	concepts *concepts.Service
+	logger   *observability.Logger
+	window   storage.ResultWindow
+}
+
+// NewConceptHandler creates a new ConceptHandler
+func NewConceptHandler(service *concepts.Service, logger *observability.Logger) *ConceptHandler {
+	return &ConceptHandler{
+		concepts: service,
+		logger:   logger,
+		window:   storage.DefaultResultWindow(),
+	}
+}
+
+// SetResultWindow sets the page size used when listing a concept's cases
+func (h *ConceptHandler) SetResultWindow(window storage.ResultWindow) {
+	h.window = window
+}
+
+// ListConcepts handles GET /api/v1/concepts, optionally filtered by ?area=
+func (h *ConceptHandler) ListConcepts(c *fiber.Ctx) error {
+	area := models.AreaOfLaw(c.Query("area"))
+	list := h.concepts.ListConcepts(area)
+
+	return c.JSON(fiber.Map{
+		"data":  list,
+		"total": len(list),
+		"area":  area,
+	})
+}
+
+// GetConcept handles GET /api/v1/concepts/:id, returning the concept with its
+// parents (nearest first) and children in the taxonomy
+func (h *ConceptHandler) GetConcept(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	concept, exists := h.concepts.GetConcept(id)
+	if !exists {
+		return fiber.NewError(fiber.StatusNotFound, "Concept not found")
+	}
+
+	return c.JSON(fiber.Map{
+		"data":     concept,
+		"parents":  h.concepts.GetConceptParents(id),
+		"children": h.concepts.GetConceptChildren(id),
+	})
+}
+
+// GetConceptCases handles GET /api/v1/concepts/:id/cases
+func (h *ConceptHandler) GetConceptCases(c *fiber.Ctx) error {
+	id := c.Params("id")
+	limit := h.window.Limit(c.QueryInt("limit", 0))
+	offset := c.QueryInt("offset", 0)
+
+	cases, total, err := h.concepts.ListConceptCases(c.Context(), id, limit, offset)
+	if err != nil {
+		h.logger.WithField("error", err).Error("Listing concept cases failed")
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"concept": id,
+		"data":    cases,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetCooccurrence handles GET /api/v1/concepts/:id/cooccurrence
+func (h *ConceptHandler) GetCooccurrence(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	limit := c.QueryInt("limit", 10)
+	if limit <= 0 || limit > maxCooccurrenceLimit {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	pairs, err := h.concepts.GetConceptCooccurrence(c.Context(), id, limit)
+	if err != nil {
+		h.logger.WithField("error", err).Error("Concept co-occurrence failed")
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"concept": id,
+		"data":    pairs,
+		"total":   len(pairs),
+		"limit":   limit,
+	})
+}
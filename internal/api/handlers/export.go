@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+)
+
+// ExportHandler reports on export jobs and serves the files the workers write
+// for them. Jobs of other tenants are not found.
+type ExportHandler struct {
+	jobs   *export.JobStore
+	logger *observability.Logger
+}
+
+// NewExportHandler creates a new ExportHandler
+func NewExportHandler(jobs *export.JobStore, logger *observability.Logger) *ExportHandler {
+	return &ExportHandler{
+		jobs:   jobs,
+		logger: logger,
+	}
+}
+
+// record returns the record of the requested job, if the caller may see it
+func (h *ExportHandler) record(c *fiber.Ctx) (*export.JobRecord, error) {
+	record, err := h.jobs.Get(c.Params("id"))
+	if err == nil && !record.VisibleTo(c.Context()) {
+		err = export.ErrJobNotFound
+	}
+	if errors.Is(err, export.ErrJobNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Export job not found")
+	}
+	return record, err
+}
+
+// GetExportJob handles GET /api/v1/exports/:id
+func (h *ExportHandler) GetExportJob(c *fiber.Ctx) error {
+	record, err := h.record(c)
+	if err != nil {
+		return err
+	}
+
+	response := fiber.Map{
+		"id":         record.ID,
+		"status":     record.Status,
+		"format":     record.Format,
+		"updated_at": record.UpdatedAt,
+	}
+	if record.Error != "" {
+		response["error"] = record.Error
+	}
+	if record.Status == queue.JobStatusCompleted {
+		response["count"] = record.Count
+		response["download_url"] = c.BaseURL() + "/api/v1/exports/" + record.ID + "/download"
+	}
+	return c.JSON(response)
+}
+
+// DownloadExport handles GET /api/v1/exports/:id/download. Jobs that have
+// not completed are answered with 409 Conflict.
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	record, err := h.record(c)
+	if err != nil {
+		return err
+	}
+	if record.Status != queue.JobStatusCompleted {
+		return fiber.NewError(fiber.StatusConflict, "Export job is "+string(record.Status))
+	}
+
+	f, err := h.jobs.Open(record)
+	if errors.Is(err, export.ErrJobNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Export file not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	// The stream is closed once it has been sent
+	c.Attachment(record.ID + "." + record.Format.Extension())
+	return c.SendStream(f)
+}
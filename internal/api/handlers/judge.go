@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/storage"
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -11,23 +14,30 @@ import (
 type JudgeHandler struct {
 	storage storage.Storage
 	logger  *observability.Logger
+	window  storage.ResultWindow
 }
 
 // NewJudgeHandler creates a new JudgeHandler
-func NewJudgeHandler(storage storage.Storage, logger *observability.Logger) *JudgeHandler {
+func NewJudgeHandler(store storage.Storage, logger *observability.Logger) *JudgeHandler {
 	return &JudgeHandler{
-		storage: storage,
+		storage: store,
 		logger:  logger,
+		window:  storage.DefaultResultWindow(),
 	}
 }
 
+// SetResultWindow sets the default page size of list requests
+func (h *JudgeHandler) SetResultWindow(window storage.ResultWindow) {
+	h.window = window
+}
+
 // ListJudges handles GET /api/v1/judges
 func (h *JudgeHandler) ListJudges(c *fiber.Ctx) error {
 	filter := storage.JudgeFilter{
 		Name:         c.Query("name"),
 		Court:        c.Query("court"),
 		Jurisdiction: c.Query("jurisdiction"),
-		Limit:        c.QueryInt("limit", 10),
+		Limit:        h.window.Limit(c.QueryInt("limit", 0)),
 		Offset:       c.QueryInt("offset", 0),
 	}
 
@@ -56,6 +66,33 @@ func (h *JudgeHandler) GetJudge(c *fiber.Ctx) error {
 	return c.JSON(judge)
 }
 
+// GetJudgeCases handles GET /api/v1/judges/:id/cases, newest first
+func (h *JudgeHandler) GetJudgeCases(c *fiber.Ctx) error {
+	id := c.Params("id")
+	filter := storage.CaseFilter{
+		Jurisdictions: queryValues(c, "jurisdiction"),
+		Courts:        queryValues(c, "court"),
+		Limit:         h.window.Limit(c.QueryInt("limit", 0)),
+		Offset:        c.QueryInt("offset", 0),
+	}
+
+	cases, total, err := storage.CasesByJudge(c.Context(), h.storage, id, filter)
+	if errors.Is(err, kiteerrors.ErrNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, "Judge not found")
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"judge":  id,
+		"data":   cases,
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
 // CreateJudge handles POST /api/v1/judges
 func (h *JudgeHandler) CreateJudge(c *fiber.Ctx) error {
 	var judge models.Judge
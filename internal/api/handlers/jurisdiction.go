@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/compliance"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/scraper"
+)
+
+// JurisdictionHandler handles jurisdiction and source requests
+type JurisdictionHandler struct {
+	scrapers *scraper.ScraperRegistry
+	policies *compliance.PolicyManager
+	statuses *scraper.SourceStatusCache
+	logger   *observability.Logger
+}
+
+// SourceCompliance summarizes the scraping policy of a source
+type SourceCompliance struct {
+	AllowScraping       bool                           `json:"allow_scraping"`
+	RateLimit           int                            `json:"rate_limit"`
+	CommercialUse       compliance.CommercialUsePolicy `json:"commercial_use"`
+	RequiresAttribution bool                           `json:"requires_attribution"`
+	TermsOfServiceURL   string                         `json:"terms_of_service_url,omitempty"`
+}
+
+// SourceInfo describes a registered source and its current availability
+type SourceInfo struct {
+	Name         string                `json:"name"`
+	Jurisdiction string                `json:"jurisdiction"`
+	BaseURL      string                `json:"base_url"`
+	RateLimit    int                   `json:"rate_limit"`
+	Compliance   *SourceCompliance     `json:"compliance"`
+	Status       scraper.ScraperStatus `json:"status"`
+	LastChecked  time.Time             `json:"last_checked"`
+}
+
+// NewJurisdictionHandler creates a new JurisdictionHandler
+func NewJurisdictionHandler(scrapers *scraper.ScraperRegistry, logger *observability.Logger) *JurisdictionHandler {
+	return &JurisdictionHandler{
+		scrapers: scrapers,
+		policies: compliance.NewPolicyManager(),
+		statuses: scraper.NewSourceStatusCache(),
+		logger:   logger,
+	}
+}
+
+// SetPolicyManager sets the scraping policies reported for each source
+func (h *JurisdictionHandler) SetPolicyManager(policies *compliance.PolicyManager) {
+	h.policies = policies
+}
+
+// ListJurisdictions handles GET /api/v1/jurisdictions
+func (h *JurisdictionHandler) ListJurisdictions(c *fiber.Ctx) error {
+	scrapers := h.scrapers.GetAll()
+	statuses := h.statuses.Statuses(c.UserContext(), scrapers)
+
+	sources := make([]SourceInfo, 0, len(scrapers))
+	for name, s := range scrapers {
+		metadata := s.GetMetadata()
+		info := SourceInfo{
+			Name:         s.GetName(),
+			Jurisdiction: s.GetJurisdiction(),
+			BaseURL:      metadata.BaseURL,
+			RateLimit:    s.GetRateLimit(),
+			Status:       statuses[name].Status,
+			LastChecked:  statuses[name].LastChecked,
+		}
+		if policy, ok := h.policies.GetPolicy(s.GetName()); ok {
+			info.Compliance = &SourceCompliance{
+				AllowScraping:       policy.AllowScraping,
+				RateLimit:           policy.RateLimit,
+				CommercialUse:       policy.CommercialUse,
+				RequiresAttribution: policy.RequiresAttribution,
+				TermsOfServiceURL:   policy.TermsOfServiceURL,
+			}
+		}
+		sources = append(sources, info)
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		if sources[i].Jurisdiction != sources[j].Jurisdiction {
+			return sources[i].Jurisdiction < sources[j].Jurisdiction
+		}
+		return sources[i].Name < sources[j].Name
+	})
+
+	return c.JSON(fiber.Map{
+		"data":  sources,
+		"total": len(sources),
+	})
+}
+
+// ListSources handles GET /api/v1/jurisdictions/:jurisdiction/sources
+func (h *JurisdictionHandler) ListSources(c *fiber.Ctx) error {
+	jurisdiction, err := url.PathUnescape(c.Params("jurisdiction"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid jurisdiction")
+	}
+
+	sources := make([]scraper.ScraperMetadata, 0)
+	for _, s := range h.scrapers.GetAll() {
+		if strings.EqualFold(s.GetJurisdiction(), jurisdiction) {
+			sources = append(sources, s.GetMetadata())
+		}
+	}
+
+	if len(sources) == 0 {
+		return fiber.NewError(fiber.StatusNotFound, "Jurisdiction not found")
+	}
+
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+
+	return c.JSON(fiber.Map{
+		"jurisdiction": jurisdiction,
+		"data":         sources,
+		"total":        len(sources),
+	})
+}
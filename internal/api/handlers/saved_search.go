@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/events"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/storage"
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/google/uuid"
+)
+
+// SavedSearchHandler handles the searches users save to be alerted of newly
+// scraped matching cases. Each caller only ever sees and changes its own.
+type SavedSearchHandler struct {
+	searches storage.SavedSearchStore
+	logger   *observability.Logger
+}
+
+// NewSavedSearchHandler creates a new SavedSearchHandler
+func NewSavedSearchHandler(store storage.Storage, logger *observability.Logger) *SavedSearchHandler {
+	searches, _ := storage.SavedSearches(store)
+	return &SavedSearchHandler{
+		searches: searches,
+		logger:   logger,
+	}
+}
+
+// SavedSearchRequest is the body of saved search create and update requests
+type SavedSearchRequest struct {
+	Name       string                    `json:"name"`
+	Query      string                    `json:"query"`
+	Filters    models.SavedSearchFilters `json:"filters"`
+	WebhookURL string                    `json:"webhook_url"`
+}
+
+// owner returns the filter selecting the caller's saved searches
+func (h *SavedSearchHandler) owner(c *fiber.Ctx) (storage.SavedSearchFilter, error) {
+	if h.searches == nil {
+		return storage.SavedSearchFilter{}, fiber.NewError(fiber.StatusNotImplemented, "Saved searches not supported by this storage backend")
+	}
+
+	userID, tenantID := caller(c)
+	if userID == "" {
+		return storage.SavedSearchFilter{}, fiber.NewError(fiber.StatusUnauthorized, "Saved searches require authentication")
+	}
+
+	return storage.SavedSearchFilter{UserID: userID, TenantID: tenantID}, nil
+}
+
+// parseSavedSearch reads a saved search request. A search needs a query or a
+// filter, so it doesn't alert on every case, and an HTTP(S) webhook on a
+// public host to alert.
+func parseSavedSearch(c *fiber.Ctx) (SavedSearchRequest, error) {
+	var req SavedSearchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	concepts := make([]string, 0, len(req.Filters.Concepts))
+	for _, concept := range req.Filters.Concepts {
+		if concept = strings.TrimSpace(concept); concept != "" {
+			concepts = append(concepts, concept)
+		}
+	}
+	req.Filters.Concepts = concepts
+
+	if req.Query == "" && req.Filters.Jurisdiction == "" && req.Filters.Court == "" && len(req.Filters.Concepts) == 0 {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Saved search needs a query or filters")
+	}
+	if err := events.CheckPublicURL(req.WebhookURL); err != nil {
+		return req, fiber.NewError(fiber.StatusBadRequest, "Saved search needs a public http or https webhook_url: "+err.Error())
+	}
+	if req.Name == "" {
+		req.Name = req.Query
+	}
+	return req, nil
+}
+
+// ownSavedSearch loads the saved search in the path, reporting another
+// caller's search exactly as a missing one
+func (h *SavedSearchHandler) ownSavedSearch(c *fiber.Ctx, owner storage.SavedSearchFilter) (*models.SavedSearch, error) {
+	s, err := h.searches.GetSavedSearch(c.Context(), c.Params("id"))
+	if errors.Is(err, kiteerrors.ErrNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Saved search not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.UserID != owner.UserID || s.TenantID != owner.TenantID {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Saved search not found")
+	}
+	return s, nil
+}
+
+// CreateSavedSearch handles POST /api/v1/saved-searches. Only cases scraped
+// after the search is saved alert for it.
+func (h *SavedSearchHandler) CreateSavedSearch(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseSavedSearch(c)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	search := &models.SavedSearch{
+		ID:         uuid.New().String(),
+		UserID:     owner.UserID,
+		TenantID:   owner.TenantID,
+		Name:       req.Name,
+		Query:      req.Query,
+		Filters:    req.Filters,
+		WebhookURL: req.WebhookURL,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := h.searches.SaveSavedSearch(c.Context(), search); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(search)
+}
+
+// ListSavedSearches handles GET /api/v1/saved-searches, listing the caller's
+// saved searches oldest first
+func (h *SavedSearchHandler) ListSavedSearches(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+
+	searches, err := h.searches.ListSavedSearches(c.Context(), owner)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"data":  searches,
+		"total": len(searches),
+	})
+}
+
+// GetSavedSearch handles GET /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) GetSavedSearch(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	search, err := h.ownSavedSearch(c, owner)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(search)
+}
+
+// UpdateSavedSearch handles PUT /api/v1/saved-searches/:id, replacing the
+// query, filters and webhook. Cases already alerted do not alert again.
+func (h *SavedSearchHandler) UpdateSavedSearch(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	req, err := parseSavedSearch(c)
+	if err != nil {
+		return err
+	}
+	search, err := h.ownSavedSearch(c, owner)
+	if err != nil {
+		return err
+	}
+
+	search.Name = req.Name
+	search.Query = req.Query
+	search.Filters = req.Filters
+	search.WebhookURL = req.WebhookURL
+	search.UpdatedAt = time.Now().UTC()
+	if err := h.searches.SaveSavedSearch(c.Context(), search); err != nil {
+		return err
+	}
+
+	return c.JSON(search)
+}
+
+// DeleteSavedSearch handles DELETE /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *fiber.Ctx) error {
+	owner, err := h.owner(c)
+	if err != nil {
+		return err
+	}
+	search, err := h.ownSavedSearch(c, owner)
+	if err != nil {
+		return err
+	}
+
+	if err := h.searches.DeleteSavedSearch(c.Context(), search.ID); err != nil {
+		return err
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/gongahkia/kite/internal/api/middleware"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/search"
 	"github.com/gongahkia/kite/internal/storage"
@@ -22,40 +25,66 @@ type SearchHandler struct {
 // NewSearchHandler creates a new search handler
 func NewSearchHandler(storage storage.Storage, logger *observability.Logger, metrics *observability.Metrics) *SearchHandler {
 	return &SearchHandler{
-		engine:      search.NewSearchEngine(storage, logger, metrics),
+		engine:      search.NewSearchEngine(storage, logger, metrics, search.DefaultRankingConfig()),
 		suggestions: search.NewSuggestionEngine(storage),
 		logger:      logger,
 		metrics:     metrics,
 	}
 }
 
+// SetResultWindow sets the default page size and the maximum limit and offset
+// the search engine accepts
+func (h *SearchHandler) SetResultWindow(window storage.ResultWindow) {
+	h.engine.SetResultWindow(window)
+}
+
+// SetDedup sets how the search engine collapses duplicate results
+func (h *SearchHandler) SetDedup(config search.DedupConfig) {
+	h.engine.SetDedup(config)
+}
+
+// SetConceptBoost sets how the search engine boosts matches on important
+// legal concepts
+func (h *SearchHandler) SetConceptBoost(config search.ConceptBoostConfig) {
+	h.engine.SetConceptBoost(config)
+}
+
 // SearchRequest represents a search request
 type SearchRequest struct {
-	Query        string   `json:"query"`
-	QueryType    string   `json:"query_type,omitempty"`
-	Fields       []string `json:"fields,omitempty"`
-	Jurisdiction string   `json:"jurisdiction,omitempty"`
-	Court        string   `json:"court,omitempty"`
-	CourtLevel   int      `json:"court_level,omitempty"`
-	StartDate    string   `json:"start_date,omitempty"`
-	EndDate      string   `json:"end_date,omitempty"`
-	Judges       []string `json:"judges,omitempty"`
-	Parties      []string `json:"parties,omitempty"`
-	Concepts     []string `json:"concepts,omitempty"`
-	MinQuality   float64  `json:"min_quality,omitempty"`
-	SortBy       string   `json:"sort_by,omitempty"`
-	SortDesc     bool     `json:"sort_desc,omitempty"`
-	Limit        int      `json:"limit,omitempty"`
-	Offset       int      `json:"offset,omitempty"`
-	Facets       []string `json:"facets,omitempty"`
+	Query             string   `json:"query"`
+	QueryType         string   `json:"query_type,omitempty"`
+	Fields            []string `json:"fields,omitempty"`
+	Jurisdiction      string   `json:"jurisdiction,omitempty"`
+	Court             string   `json:"court,omitempty"`
+	Jurisdictions     []string `json:"jurisdictions,omitempty"`
+	Courts            []string `json:"courts,omitempty"`
+	CourtLevel        int      `json:"court_level,omitempty"`
+	StartDate         string   `json:"start_date,omitempty"`
+	EndDate           string   `json:"end_date,omitempty"`
+	Judges            []string `json:"judges,omitempty"`
+	Parties           []string `json:"parties,omitempty"`
+	Concepts          []string `json:"concepts,omitempty"`
+	MinQuality        float64  `json:"min_quality,omitempty"`
+	MinFullTextLength int      `json:"min_full_text_length,omitempty"`
+	SortBy            string   `json:"sort_by,omitempty"`
+	SortDesc          bool     `json:"sort_desc,omitempty"`
+	Limit             int      `json:"limit,omitempty"`
+	Offset            int      `json:"offset,omitempty"`
+	Facets            []string `json:"facets,omitempty"`
+	FacetsOnly        bool     `json:"facets_only,omitempty"`
+	Timeline          string   `json:"timeline,omitempty"`
+	Explain           bool     `json:"explain,omitempty"`        // explain each result's score
+	SnippetLength     int      `json:"snippet_length,omitempty"` // characters of case text per highlight
+	MaxHighlights     int      `json:"max_highlights,omitempty"` // highlights per result
 }
 
 // SearchResponse represents a search response
 type SearchResponse struct {
-	Results    []SearchResult        `json:"results"`
-	TotalHits  int                   `json:"total_hits"`
-	SearchTime float64               `json:"search_time_ms"`
-	Facets     map[string][]FacetVal `json:"facets,omitempty"`
+	Results    []SearchResult           `json:"results"`
+	TotalHits  int                      `json:"total_hits"`
+	SearchTime float64                  `json:"search_time_ms"`
+	Facets     map[string][]FacetVal    `json:"facets,omitempty"`
+	Timeline   []storage.TimelineBucket `json:"timeline,omitempty"`
 }
 
 // SearchResult represents a single search result
@@ -63,6 +92,7 @@ type SearchResult struct {
 	Case       *models.Case `json:"case"`
 	Score      float64      `json:"score"`
 	Highlights []string     `json:"highlights,omitempty"`
+	Explain    string       `json:"explain,omitempty"`
 }
 
 // FacetVal represents a facet value
@@ -71,7 +101,8 @@ type FacetVal struct {
 	Count int    `json:"count"`
 }
 
-// Search handles POST /api/v1/search
+// Search handles POST /api/v1/search. With "Accept: application/x-ndjson"
+// the results are streamed one per line, without totals or facets.
 func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	var req SearchRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -109,6 +140,14 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 		qb.FilterByCourt(req.Court)
 	}
 
+	if len(req.Jurisdictions) > 0 {
+		qb.FilterByJurisdictions(req.Jurisdictions...)
+	}
+
+	if len(req.Courts) > 0 {
+		qb.FilterByCourts(req.Courts...)
+	}
+
 	if req.CourtLevel > 0 {
 		qb.FilterByCourtLevel(models.CourtLevel(req.CourtLevel))
 	}
@@ -139,6 +178,15 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 		qb.FilterByMinQuality(req.MinQuality)
 	}
 
+	if req.MinFullTextLength < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "min_full_text_length must be non-negative",
+		})
+	}
+	if req.MinFullTextLength > 0 {
+		qb.FilterByMinFullTextLength(req.MinFullTextLength)
+	}
+
 	// Set sorting
 	if req.SortBy != "" {
 		qb.SortBy(req.SortBy, req.SortDesc)
@@ -146,12 +194,8 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 		qb.SortByRelevance()
 	}
 
-	// Set pagination
-	limit := req.Limit
-	if limit == 0 {
-		limit = 10
-	}
-	qb.Limit(limit)
+	// Set pagination; the engine applies the default page size to a zero limit
+	qb.Limit(req.Limit)
 
 	if req.Offset > 0 {
 		qb.Offset(req.Offset)
@@ -161,6 +205,32 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 	if len(req.Facets) > 0 {
 		qb.WithFacets(req.Facets...)
 	}
+	if req.Timeline != "" {
+		period := storage.TimelinePeriod(req.Timeline)
+		if !period.Valid() {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "timeline must be year, quarter or month",
+			})
+		}
+		qb.WithTimeline(period)
+	}
+	if req.FacetsOnly {
+		qb.FacetsOnly()
+	}
+	if req.Explain {
+		qb.WithExplain()
+	}
+	if req.SnippetLength < 0 || req.SnippetLength > search.MaxSnippetLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("snippet_length must be between 0 and %d", search.MaxSnippetLength),
+		})
+	}
+	if req.MaxHighlights < 0 || req.MaxHighlights > search.MaxHighlightsLimit {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": fmt.Sprintf("max_highlights must be between 0 and %d", search.MaxHighlightsLimit),
+		})
+	}
+	qb.WithSnippetLength(req.SnippetLength).WithMaxHighlights(req.MaxHighlights)
 
 	query := qb.Build()
 
@@ -180,9 +250,21 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 			Case:       r.Case,
 			Score:      r.Score,
 			Highlights: r.Highlights,
+			Explain:    r.Explain,
 		}
 	}
 
+	if middleware.AcceptsNDJSON(c) {
+		return streamNDJSON(c, h.logger, func(ctx context.Context, emit func(v interface{}) error) error {
+			for _, r := range searchResults {
+				if err := emit(r); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+
 	// Convert facets
 	facets := make(map[string][]FacetVal)
 	for field, facet := range results.Facets {
@@ -201,6 +283,7 @@ func (h *SearchHandler) Search(c *fiber.Ctx) error {
 		TotalHits:  results.TotalHits,
 		SearchTime: float64(results.SearchTime.Milliseconds()),
 		Facets:     facets,
+		Timeline:   results.Timeline,
 	})
 }
 
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/api/middleware"
+	"github.com/gongahkia/kite/internal/observability"
+)
+
+// streamNDJSON responds with newline-delimited JSON, one value per line,
+// writing each line to the client as soon as produce emits it. The response
+// is sent chunked, so the first result arrives before the last is fetched.
+// The status is sent before produce runs; if it fails part way, a final
+// {"error": ...} line ends the stream.
+func streamNDJSON(c *fiber.Ctx, logger *observability.Logger, produce func(ctx context.Context, emit func(v interface{}) error) error) error {
+	c.Set(fiber.HeaderContentType, middleware.MIMEApplicationNDJSON)
+
+	// The request context is only valid until the handler returns, which is
+	// before the stream is written
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		encoder := json.NewEncoder(w)
+		emit := func(v interface{}) error {
+			if err := encoder.Encode(v); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+
+		if err := produce(ctx, emit); err != nil {
+			logger.WithField("error", err.Error()).Warn("NDJSON stream ended early")
+			emit(fiber.Map{"error": "stream ended early"})
+		}
+	})
+	return nil
+}
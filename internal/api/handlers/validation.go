@@ -31,6 +31,15 @@ func NewValidationHandler(storage storage.Storage, logger *observability.Logger,
 	}
 }
 
+// SetDedupHashes sets the field combinations that identify duplicate cases,
+// for both the pipeline's duplication stage and duplicate detection
+func (h *ValidationHandler) SetDedupHashes(templates []validation.HashTemplate) {
+	config := validation.DefaultPipelineConfig()
+	config.DedupHashes = templates
+	h.pipeline = validation.NewPipeline(h.logger, h.metrics, config)
+	h.detector = validation.NewDuplicateDetectorWithTemplates(templates)
+}
+
 // ValidateCaseRequest represents a validation request
 type ValidateCaseRequest struct {
 	CaseID string `json:"case_id"`
@@ -257,8 +266,8 @@ func (h *ValidationHandler) DetectDuplicates(c *fiber.Ctx) error {
 
 // GetQualityMetrics handles GET /api/v1/validation/metrics
 func (h *ValidationHandler) GetQualityMetrics(c *fiber.Ctx) error {
-	// Get all cases (limited to 1000 for metrics)
-	filter := storage.CaseFilter{Limit: 1000}
+	// Get all cases (limited to 1000 for metrics), with the full text validated
+	filter := storage.CaseFilter{Limit: 1000, IncludeFullText: true}
 	cases, err := h.storage.ListCases(c.Context(), filter)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
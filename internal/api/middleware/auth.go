@@ -13,6 +13,7 @@ import (
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
 	APIKeys       map[string]string // API Key -> User/Client ID
+	Tenants       map[string]string // Client ID -> Tenant ID, see TenantScope
 	JWTSecret     string
 	JWTExpiration time.Duration
 	Skipper       func(*fiber.Ctx) bool // Optional skip function
@@ -87,6 +88,7 @@ type JWTClaims struct {
 	UserID   string   `json:"user_id"`
 	ClientID string   `json:"client_id"`
 	Roles    []string `json:"roles"`
+	TenantID string   `json:"tenant_id,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -160,6 +162,7 @@ func JWTAuth(config *AuthConfig, logger *observability.Logger) fiber.Handler {
 		c.Locals("user_id", claims.UserID)
 		c.Locals("client_id", claims.ClientID)
 		c.Locals("roles", claims.Roles)
+		c.Locals("tenant_id", claims.TenantID)
 		c.Locals("auth_method", "jwt")
 
 		logger.WithFields(map[string]interface{}{
@@ -191,6 +194,7 @@ func OptionalAuth(config *AuthConfig, logger *observability.Logger) fiber.Handle
 					c.Locals("user_id", claims.UserID)
 					c.Locals("client_id", claims.ClientID)
 					c.Locals("roles", claims.Roles)
+					c.Locals("tenant_id", claims.TenantID)
 					c.Locals("auth_method", "jwt")
 					c.Locals("authenticated", true)
 					return c.Next()
@@ -254,12 +258,14 @@ func RequireRoles(requiredRoles ...string) fiber.Handler {
 	}
 }
 
-// GenerateJWT generates a new JWT token
+// GenerateJWT generates a new JWT token, carrying the client's tenant if it
+// has one
 func GenerateJWT(userID, clientID string, roles []string, config *AuthConfig) (string, error) {
 	claims := &JWTClaims{
 		UserID:   userID,
 		ClientID: clientID,
 		Roles:    roles,
+		TenantID: config.Tenants[clientID],
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(config.JWTExpiration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
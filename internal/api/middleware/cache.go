@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// CachePolicy describes the caching headers sent for a route
+type CachePolicy struct {
+	MaxAge  time.Duration
+	Private bool
+	Vary    []string
+}
+
+// CacheConfig holds cache policies per endpoint type
+type CacheConfig struct {
+	// Cases are effectively immutable once scraped
+	Case CachePolicy
+	// Lists and search results change as new cases are added
+	List CachePolicy
+	// Stats change continuously
+	Stats CachePolicy
+	// Reference data (jurisdictions, court hierarchy) rarely changes
+	Reference CachePolicy
+}
+
+// DefaultCacheConfig returns the default cache configuration
+func DefaultCacheConfig() *CacheConfig {
+	vary := []string{"Accept", "Accept-Encoding"}
+	return &CacheConfig{
+		Case:      CachePolicy{MaxAge: 24 * time.Hour, Vary: vary},
+		List:      CachePolicy{MaxAge: 5 * time.Minute, Vary: vary},
+		Stats:     CachePolicy{MaxAge: time.Minute, Vary: vary},
+		Reference: CachePolicy{MaxAge: time.Hour, Vary: vary},
+	}
+}
+
+// HeaderValue returns the Cache-Control header value for the policy
+func (p CachePolicy) HeaderValue() string {
+	if p.MaxAge <= 0 {
+		return "no-cache"
+	}
+
+	visibility := "public"
+	if p.Private {
+		visibility = "private"
+	}
+
+	return fmt.Sprintf("%s, max-age=%d", visibility, int(p.MaxAge.Seconds()))
+}
+
+// CacheControl sets Cache-Control and Vary headers on successful GET/HEAD responses.
+// Error responses are marked no-store so failures aren't cached by shared caches.
+func CacheControl(policy CachePolicy) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil || (status >= 300 && status != fiber.StatusNotModified) {
+			c.Set(fiber.HeaderCacheControl, "no-store")
+			return err
+		}
+
+		c.Set(fiber.HeaderCacheControl, policy.HeaderValue())
+		if len(policy.Vary) > 0 {
+			c.Set(fiber.HeaderVary, strings.Join(policy.Vary, ", "))
+		}
+
+		return nil
+	}
+}
+
+// ETag adds weak ETags to responses so clients can revalidate once max-age
+// expires. NDJSON streams are left without one, as hashing them would buffer
+// the whole stream.
+func ETag() fiber.Handler {
+	return etag.New(etag.Config{
+		Weak: true,
+		Next: AcceptsNDJSON,
+	})
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IPFilterConfig restricts requests by client address. Entries are CIDR
+// ranges ("10.0.0.0/8") or single addresses ("192.0.2.7").
+type IPFilterConfig struct {
+	// Allow admits only clients in these ranges; empty admits any client not denied
+	Allow []string
+	// Deny refuses clients in these ranges, even if allowed
+	Deny []string
+	// TrustedProxies are the load balancers and proxies whose
+	// X-Forwarded-For header is believed. Without them the client is the
+	// connection's peer and the header is ignored.
+	TrustedProxies []string
+}
+
+// IPFilter decides which client addresses may make a request
+type IPFilter struct {
+	allow   []netip.Prefix
+	deny    []netip.Prefix
+	trusted []netip.Prefix
+}
+
+// NewIPFilter parses the configured ranges
+func NewIPFilter(config IPFilterConfig) (*IPFilter, error) {
+	allow, err := parsePrefixes(config.Allow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allow list: %w", err)
+	}
+	deny, err := parsePrefixes(config.Deny)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny list: %w", err)
+	}
+	trusted, err := parsePrefixes(config.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	return &IPFilter{allow: allow, deny: deny, trusted: trusted}, nil
+}
+
+// parsePrefixes parses CIDR ranges, reading a bare address as a range of one
+func parsePrefixes(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, err
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// Allowed reports whether a client address may make a request
+func (f *IPFilter) Allowed(addr netip.Addr) bool {
+	if f == nil {
+		return true
+	}
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+	if containsAddr(f.deny, addr) {
+		return false
+	}
+	return len(f.allow) == 0 || containsAddr(f.allow, addr)
+}
+
+// ClientIP returns the address of the client behind a request. When the
+// peer is a trusted proxy, X-Forwarded-For is read from the right, skipping
+// trusted proxies, so addresses a client prepends to the header itself are
+// never used. It returns an invalid address if the header is malformed.
+func (f *IPFilter) ClientIP(c *fiber.Ctx) netip.Addr {
+	addr, _ := netip.AddrFromSlice(c.Context().RemoteIP())
+	addr = addr.Unmap()
+	if f == nil || !containsAddr(f.trusted, addr) {
+		return addr
+	}
+
+	hops := strings.Split(c.Get(fiber.HeaderXForwardedFor), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			if len(hops) == 1 {
+				break // no header: the proxy is the client
+			}
+			return netip.Addr{}
+		}
+		next, err := netip.ParseAddr(hop)
+		if err != nil {
+			return netip.Addr{}
+		}
+		addr = next.Unmap()
+		if !containsAddr(f.trusted, addr) {
+			break
+		}
+	}
+	return addr
+}
+
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictIPs responds 403 to requests from clients the filter refuses. A
+// nil filter admits everyone.
+func RestrictIPs(filter *IPFilter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if filter == nil {
+			return c.Next()
+		}
+		if !filter.Allowed(filter.ClientIP(c)) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Access denied from this address",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -114,6 +114,8 @@ func ErrorHandler(logger *observability.Logger) fiber.ErrorHandler {
 				code = fiber.StatusBadRequest
 			case "AUTH_ERROR":
 				code = fiber.StatusUnauthorized
+			case "FORBIDDEN":
+				code = fiber.StatusForbidden
 			default:
 				code = fiber.StatusInternalServerError
 			}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldNaming is the convention for object keys in JSON responses
+type FieldNaming string
+
+const (
+	SnakeCase FieldNaming = "snake_case" // case_name, as the models are tagged
+	CamelCase FieldNaming = "camelCase"  // caseName, as in the GraphQL schema
+)
+
+// ParseFieldNaming parses a field naming convention: snake_case (or snake)
+// or camelCase (or camel), in any letter case
+func ParseFieldNaming(s string) (FieldNaming, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "snake", "snake_case":
+		return SnakeCase, nil
+	case "camel", "camelcase":
+		return CamelCase, nil
+	}
+	return "", fmt.Errorf("unknown field naming: %q (want snake_case or camelCase)", s)
+}
+
+// ResponseFieldNaming renames the object keys of JSON responses to the
+// convention a request asks for with a naming parameter on its Accept header,
+// e.g. "application/json; naming=camel", and otherwise to defaultNaming. The
+// models are tagged in snake_case, so snake_case responses pass through as
+// they are. Keys are renamed throughout the body, including those of nested
+// objects such as metadata.
+func ResponseFieldNaming(defaultNaming FieldNaming) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAccept)
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		naming := requestedFieldNaming(c.Get(fiber.HeaderAccept), defaultNaming)
+		if naming == SnakeCase {
+			return nil
+		}
+		contentType := string(c.Response().Header.ContentType())
+		if !strings.HasPrefix(contentType, fiber.MIMEApplicationJSON) {
+			return nil
+		}
+
+		body, err := RenameJSONKeys(c.Response().Body(), naming)
+		if err != nil {
+			// Leave bodies that aren't a single JSON value as they are
+			return nil
+		}
+		c.Response().SetBodyRaw(body)
+		return nil
+	}
+}
+
+// requestedFieldNaming returns the naming parameter of the first media range
+// in an Accept header that has a valid one, or fallback
+func requestedFieldNaming(accept string, fallback FieldNaming) FieldNaming {
+	for _, part := range strings.Split(accept, ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["naming"] == "" {
+			continue
+		}
+		if naming, err := ParseFieldNaming(params["naming"]); err == nil {
+			return naming
+		}
+	}
+	return fallback
+}
+
+// RenameJSONKeys rewrites every object key in a JSON document to the naming
+// convention, keeping key order, values and numbers exactly as they were
+func RenameJSONKeys(body []byte, naming FieldNaming) ([]byte, error) {
+	rename := snakeToCamel
+	if naming == SnakeCase {
+		rename = camelToSnake
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	if err := copyRenamed(dec, &out, rename); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data after JSON value")
+	}
+	return out.Bytes(), nil
+}
+
+// copyRenamed copies the next JSON value from dec to out, renaming object keys
+func copyRenamed(dec *json.Decoder, out *bytes.Buffer, rename func(string) string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		encoded, err := json.Marshal(tok)
+		if err != nil {
+			return err
+		}
+		out.Write(encoded)
+		return nil
+	}
+
+	object := delim == '{'
+	if object {
+		out.WriteByte('{')
+	} else {
+		out.WriteByte('[')
+	}
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if object {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			encoded, err := json.Marshal(rename(key.(string)))
+			if err != nil {
+				return err
+			}
+			out.Write(encoded)
+			out.WriteByte(':')
+		}
+		if err := copyRenamed(dec, out, rename); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if object {
+		out.WriteByte('}')
+	} else {
+		out.WriteByte(']')
+	}
+	return nil
+}
+
+// snakeToCamel converts "source_database" to "sourceDatabase"
+func snakeToCamel(key string) string {
+	if !strings.Contains(key, "_") {
+		return key
+	}
+
+	var b strings.Builder
+	upper := false
+	for i, r := range key {
+		switch {
+		case r == '_' && i > 0:
+			upper = true
+		case upper:
+			b.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// camelToSnake converts "sourceDatabase" to "source_database", keeping
+// initialisms together ("courtID" to "court_id")
+func camelToSnake(key string) string {
+	runes := []rune(key)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
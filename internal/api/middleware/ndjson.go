@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MIMEApplicationNDJSON is the media type of newline-delimited JSON, one
+// value per line
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// AcceptsNDJSON reports whether a request names newline-delimited JSON in its
+// Accept header. Wildcards don't count, so only clients that ask for a stream
+// get one.
+func AcceptsNDJSON(c *fiber.Ctx) bool {
+	for _, part := range strings.Split(c.Get(fiber.HeaderAccept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == MIMEApplicationNDJSON {
+			return true
+		}
+	}
+	return false
+}
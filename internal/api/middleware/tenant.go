@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/storage"
+)
+
+// TenantScope scopes storage access for the rest of the request to the
+// caller's tenant: the tenant_id claim of its JWT, or else the tenant
+// configured for its client ID. Unauthenticated callers and clients without a
+// tenant see shared data only. Cacheable responses to a tenant are marked
+// private, so shared caches don't serve one tenant's data to another.
+func TenantScope(config *AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		tenantID, _ := c.Locals("tenant_id").(string)
+		if tenantID == "" {
+			if clientID, ok := c.Locals("client_id").(string); ok {
+				tenantID = config.Tenants[clientID]
+			}
+		}
+		c.Locals(storage.TenantScopeKey, tenantID)
+
+		err := c.Next()
+
+		if tenantID != "" {
+			cacheControl := string(c.Response().Header.Peek(fiber.HeaderCacheControl))
+			if strings.HasPrefix(cacheControl, "public") {
+				c.Set(fiber.HeaderCacheControl, "private"+strings.TrimPrefix(cacheControl, "public"))
+			}
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/errors"
+)
+
+// windowBody picks the page bounds out of search request bodies
+type windowBody struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// ResultWindow responds 400 to list and search requests that ask for more
+// results or a deeper page than the window allows. Bounds are read from the
+// limit and offset query parameters or a JSON request body.
+func ResultWindow(window storage.ResultWindow) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		limit := c.QueryInt("limit", 0)
+		offset := c.QueryInt("offset", 0)
+
+		if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEApplicationJSON) && len(c.Body()) > 0 {
+			var body windowBody
+			if json.Unmarshal(c.Body(), &body) == nil {
+				if body.Limit != 0 {
+					limit = body.Limit
+				}
+				if body.Offset != 0 {
+					offset = body.Offset
+				}
+			}
+		}
+
+		if err := window.Check(limit, offset); err != nil {
+			message := err.Error()
+			if kiteErr, ok := err.(*errors.KiteError); ok {
+				message = kiteErr.Message
+			}
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":      message,
+				"max_limit":  window.MaxLimit,
+				"max_offset": window.MaxOffset,
+			})
+		}
+
+		return c.Next()
+	}
+}
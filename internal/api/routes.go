@@ -1,14 +1,21 @@
 package api
 
 import (
+	"context"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	swagger "github.com/swaggo/fiber-swagger"
 	"github.com/gongahkia/kite/internal/api/handlers"
 	"github.com/gongahkia/kite/internal/api/middleware"
+	"github.com/gongahkia/kite/internal/concepts"
+	"github.com/gongahkia/kite/internal/export"
 	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/search"
 	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/validation"
 	_ "github.com/gongahkia/kite/docs" // Import generated docs
 )
 
@@ -19,10 +26,21 @@ type Server struct {
 	logger     *observability.Logger
 	metrics    *observability.Metrics
 	authConfig *middleware.AuthConfig
+	jobQueue   queue.Queue
+	maxReplays int
+	exports    *export.JobStore
+	cache      *middleware.CacheConfig
+	scrapers   *scraper.ScraperRegistry
+	window     storage.ResultWindow
+	dedup      []validation.HashTemplate
+	search     search.DedupConfig
+	concepts   search.ConceptBoostConfig
+	naming     middleware.FieldNaming
+	adminIPs   *middleware.IPFilter
 }
 
 // NewServer creates a new API server
-func NewServer(storage storage.Storage, logger *observability.Logger, metrics *observability.Metrics, authConfig *middleware.AuthConfig) *Server {
+func NewServer(store storage.Storage, logger *observability.Logger, metrics *observability.Metrics, authConfig *middleware.AuthConfig) *Server {
 	app := fiber.New(fiber.Config{
 		AppName:      "Kite API v4.0.0",
 		ServerHeader: "Kite",
@@ -37,15 +55,84 @@ func NewServer(storage storage.Storage, logger *observability.Logger, metrics *o
 		authConfig.JWTExpiration = 24 * time.Hour
 	}
 
-	return &Server{
+	s := &Server{
 		app:        app,
-		storage:    storage,
+		storage:    store,
 		logger:     logger,
 		metrics:    metrics,
 		authConfig: authConfig,
+		cache:      middleware.DefaultCacheConfig(),
+		window:     storage.DefaultResultWindow(),
+		search:     search.DefaultDedupConfig(),
+		concepts:   search.DefaultConceptBoostConfig(),
+		naming:     middleware.SnakeCase,
+	}
+	s.SetTimeouts(DefaultServerTimeouts())
+
+	return s
+}
+
+// SetJobQueue attaches the job queue used by the admin endpoints
+func (s *Server) SetJobQueue(q queue.Queue, maxReplays int) {
+	s.jobQueue = q
+	s.maxReplays = maxReplays
+}
+
+// SetExportStore attaches the store export jobs write their files to, which
+// the exports endpoints report on and serve
+func (s *Server) SetExportStore(exports *export.JobStore) {
+	s.exports = exports
+}
+
+// SetAdminIPFilter restricts the admin endpoints to the client addresses the
+// filter allows (nil allows any)
+func (s *Server) SetAdminIPFilter(filter *middleware.IPFilter) {
+	s.adminIPs = filter
+}
+
+// SetCacheConfig sets the Cache-Control policies for public read endpoints
+func (s *Server) SetCacheConfig(cache *middleware.CacheConfig) {
+	if cache != nil {
+		s.cache = cache
 	}
 }
 
+// SetResultWindow sets the default page size and the maximum limit and offset
+// accepted by list and search endpoints
+func (s *Server) SetResultWindow(window storage.ResultWindow) {
+	s.window = window
+}
+
+// SetScrapers attaches the scraper registry used to advertise sources, which
+// lists only enabled jurisdictions. Their cases are served through
+// storage.JurisdictionStorage.
+func (s *Server) SetScrapers(scrapers *scraper.ScraperRegistry) {
+	s.scrapers = scrapers
+}
+
+// SetDedupHashes sets the field combinations the validation endpoints use to
+// identify duplicate cases
+func (s *Server) SetDedupHashes(templates []validation.HashTemplate) {
+	s.dedup = templates
+}
+
+// SetSearchDedup sets how the search endpoint collapses duplicate results
+func (s *Server) SetSearchDedup(config search.DedupConfig) {
+	s.search = config
+}
+
+// SetConceptBoost sets how the search endpoint boosts matches on important
+// legal concepts. Without a taxonomy, the concept taxonomy's is used.
+func (s *Server) SetConceptBoost(config search.ConceptBoostConfig) {
+	s.concepts = config
+}
+
+// SetFieldNaming sets the key naming of JSON responses to requests that don't
+// ask for one
+func (s *Server) SetFieldNaming(naming middleware.FieldNaming) {
+	s.naming = naming
+}
+
 // SetupRoutes configures all API routes
 func (s *Server) SetupRoutes() {
 	// Apply global middleware
@@ -54,6 +141,7 @@ func (s *Server) SetupRoutes() {
 	s.app.Use(middleware.CORS())
 	s.app.Use(middleware.Recovery(s.logger))
 	s.app.Use(middleware.Metrics(s.metrics))
+	s.app.Use(middleware.ResponseFieldNaming(s.naming))
 	s.app.Use(middleware.IPRateLimit(100, 200, s.logger)) // Global rate limit: 100 req/s
 
 	// Swagger UI documentation
@@ -87,53 +175,126 @@ func (s *Server) SetupRoutes() {
 	// Apply optional auth to all API routes (allows both authenticated and unauthenticated access)
 	// For production, you may want to require auth for all routes except public endpoints
 	api.Use(middleware.OptionalAuth(s.authConfig, s.logger))
+	api.Use(middleware.TenantScope(s.authConfig))
 	api.Use(middleware.EndpointRateLimit(endpointRateLimitConfig, s.logger))
 
+	// Reject oversized pages before they reach storage
+	api.Use(middleware.ResultWindow(s.window))
+
+	// Export job files are streamed to the client, so their routes come
+	// before ETags, which would read a whole file into memory to hash it
+	if s.exports != nil {
+		exportHandler := handlers.NewExportHandler(s.exports, s.logger)
+		exports := api.Group("/exports")
+		exports.Get("/:id", exportHandler.GetExportJob)
+		exports.Get("/:id/download", exportHandler.DownloadExport)
+	}
+
+	// ETags come after auth and rate limiting, so only admitted requests are
+	// hashed or answered with 304 Not Modified
+	api.Use(middleware.ETag())
+
 	// Case routes
 	caseHandler := handlers.NewCaseHandler(s.storage, s.logger)
+	caseHandler.SetResultWindow(s.window)
 	cases := api.Group("/cases")
-	cases.Get("/", caseHandler.ListCases)
-	cases.Get("/:id", caseHandler.GetCase)
+	cases.Get("/", middleware.CacheControl(s.cache.List), caseHandler.ListCases)
+	cases.Get("/:id", middleware.CacheControl(s.cache.Case), caseHandler.GetCase)
 	cases.Post("/", caseHandler.CreateCase)
 	cases.Put("/:id", caseHandler.UpdateCase)
 	cases.Delete("/:id", caseHandler.DeleteCase)
 	cases.Post("/search", caseHandler.SearchCases)
 
+	// Private case annotations, scoped to the calling user and tenant
+	annotationHandler := handlers.NewAnnotationHandler(s.storage, s.logger)
+	cases.Get("/:id/annotations", annotationHandler.ListAnnotations)
+	cases.Post("/:id/annotations", annotationHandler.CreateAnnotation)
+	cases.Put("/:id/annotations/:annotation_id", annotationHandler.UpdateAnnotation)
+	cases.Delete("/:id/annotations/:annotation_id", annotationHandler.DeleteAnnotation)
+
+	// Saved searches alerting of newly scraped cases, scoped like annotations
+	savedSearchHandler := handlers.NewSavedSearchHandler(s.storage, s.logger)
+	savedSearches := api.Group("/saved-searches")
+	savedSearches.Get("/", savedSearchHandler.ListSavedSearches)
+	savedSearches.Post("/", savedSearchHandler.CreateSavedSearch)
+	savedSearches.Get("/:id", savedSearchHandler.GetSavedSearch)
+	savedSearches.Put("/:id", savedSearchHandler.UpdateSavedSearch)
+	savedSearches.Delete("/:id", savedSearchHandler.DeleteSavedSearch)
+
 	// Judge routes
 	judgeHandler := handlers.NewJudgeHandler(s.storage, s.logger)
+	judgeHandler.SetResultWindow(s.window)
 	judges := api.Group("/judges")
-	judges.Get("/", judgeHandler.ListJudges)
-	judges.Get("/:id", judgeHandler.GetJudge)
+	judges.Get("/", middleware.CacheControl(s.cache.List), judgeHandler.ListJudges)
+	judges.Get("/:id", middleware.CacheControl(s.cache.Reference), judgeHandler.GetJudge)
+	judges.Get("/:id/cases", middleware.CacheControl(s.cache.List), judgeHandler.GetJudgeCases)
 	judges.Post("/", judgeHandler.CreateJudge)
 	judges.Put("/:id", judgeHandler.UpdateJudge)
 
 	// Citation routes
 	citationHandler := handlers.NewCitationHandler(s.storage, s.logger)
+	citationHandler.SetResultWindow(s.window)
 	citations := api.Group("/citations")
-	citations.Get("/", citationHandler.ListCitations)
-	citations.Get("/:id", citationHandler.GetCitation)
+	citations.Get("/", middleware.CacheControl(s.cache.List), citationHandler.ListCitations)
+	citations.Get("/:id", middleware.CacheControl(s.cache.Case), citationHandler.GetCitation)
 	citations.Post("/", citationHandler.CreateCitation)
+	citations.Post("/import", citationHandler.ImportCitations)
 
 	// Search routes (advanced search API)
 	searchHandler := handlers.NewSearchHandler(s.storage, s.logger, s.metrics)
+	searchHandler.SetResultWindow(s.window)
+	searchHandler.SetDedup(s.search)
+	conceptBoost := s.concepts
+	if conceptBoost.Taxonomy == nil {
+		conceptBoost.Taxonomy = concepts.NewTaxonomy()
+	}
+	searchHandler.SetConceptBoost(conceptBoost)
 	searchGroup := api.Group("/search")
 	searchGroup.Post("/", searchHandler.Search)
-	searchGroup.Get("/suggest", searchHandler.Suggest)
-	searchGroup.Get("/autocomplete", searchHandler.Autocomplete)
+	searchGroup.Get("/suggest", middleware.CacheControl(s.cache.List), searchHandler.Suggest)
+	searchGroup.Get("/autocomplete", middleware.CacheControl(s.cache.List), searchHandler.Autocomplete)
 
 	// Validation routes
 	validationHandler := handlers.NewValidationHandler(s.storage, s.logger, s.metrics)
+	if len(s.dedup) > 0 {
+		validationHandler.SetDedupHashes(s.dedup)
+	}
 	validation := api.Group("/validation")
 	validation.Post("/case", validationHandler.ValidateCase)
 	validation.Post("/batch", validationHandler.ValidateBatch)
 	validation.Post("/duplicates", validationHandler.DetectDuplicates)
-	validation.Get("/metrics", validationHandler.GetQualityMetrics)
+	validation.Get("/metrics", middleware.CacheControl(s.cache.Stats), validationHandler.GetQualityMetrics)
 
 	// Stats routes
 	statsHandler := handlers.NewStatsHandler(s.storage, s.logger)
 	stats := api.Group("/stats")
-	stats.Get("/", statsHandler.GetStats)
-	stats.Get("/storage", statsHandler.GetStorageStats)
+	stats.Get("/", middleware.CacheControl(s.cache.Stats), statsHandler.GetStats)
+	stats.Get("/storage", middleware.CacheControl(s.cache.Stats), statsHandler.GetStorageStats)
+
+	// Concept analytics routes
+	conceptHandler := handlers.NewConceptHandler(concepts.NewService(s.storage), s.logger)
+	conceptHandler.SetResultWindow(s.window)
+	conceptGroup := api.Group("/concepts")
+	conceptGroup.Get("/", middleware.CacheControl(s.cache.Reference), conceptHandler.ListConcepts)
+	conceptGroup.Get("/:id", middleware.CacheControl(s.cache.Reference), conceptHandler.GetConcept)
+	conceptGroup.Get("/:id/cases", middleware.CacheControl(s.cache.List), conceptHandler.GetConceptCases)
+	conceptGroup.Get("/:id/cooccurrence", middleware.CacheControl(s.cache.Stats), conceptHandler.GetCooccurrence)
+
+	// Jurisdiction routes
+	if s.scrapers != nil {
+		jurisdictionHandler := handlers.NewJurisdictionHandler(s.scrapers, s.logger)
+		jurisdictions := api.Group("/jurisdictions")
+		jurisdictions.Get("/", middleware.CacheControl(s.cache.Stats), jurisdictionHandler.ListJurisdictions)
+		jurisdictions.Get("/:jurisdiction/sources", middleware.CacheControl(s.cache.Reference), jurisdictionHandler.ListSources)
+	}
+
+	// Admin routes (require admin role)
+	adminHandler := handlers.NewAdminHandler(s.storage, s.jobQueue, s.maxReplays, s.logger)
+	admin := api.Group("/admin", middleware.RestrictIPs(s.adminIPs), middleware.JWTAuth(s.authConfig, s.logger), middleware.RequireRoles("admin"))
+	admin.Post("/cases/delete", adminHandler.DeleteCases)
+	if s.jobQueue != nil {
+		admin.Post("/jobs/replay", adminHandler.ReplayJobs)
+	}
 
 	// 404 handler
 	s.app.Use(func(c *fiber.Ctx) error {
@@ -158,3 +319,9 @@ func (s *Server) Start(address string) error {
 func (s *Server) Shutdown() error {
 	return s.app.Shutdown()
 }
+
+// ShutdownWithContext stops accepting connections and waits for in-flight
+// requests to finish, giving up when ctx is done
+func (s *Server) ShutdownWithContext(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrDrainTimeout is returned by GracefulShutdown when a server ran out of
+// time to drain, so its resources were left open
+var ErrDrainTimeout = errors.New("servers did not finish draining")
+
+// ShutdownStep is one component stopped during a graceful shutdown
+type ShutdownStep struct {
+	Name string
+	Stop func(ctx context.Context) error
+}
+
+// GracefulShutdown stops the servers concurrently, each bounded by ctx, then
+// closes the resources they use in order, each bounded by its own
+// closeTimeout, and returns the errors of the steps that failed. A server's
+// step returns once its in-flight requests have finished, so nothing is still
+// using a resource when it closes, and draining the servers together lets each
+// use the whole of ctx. If a server runs out of time its requests may still be
+// running, so the resources are left open and the error wraps
+// ErrDrainTimeout. Any other failure does not stop the steps after it.
+func GracefulShutdown(ctx context.Context, closeTimeout time.Duration, servers []ShutdownStep, resources ...ShutdownStep) error {
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, step := range servers {
+		wg.Add(1)
+		go func(i int, step ShutdownStep) {
+			defer wg.Done()
+			if err := step.Stop(ctx); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", step.Name, err)
+			}
+		}(i, step)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			names := make([]string, len(resources))
+			for i, step := range resources {
+				names[i] = step.Name
+			}
+			errs = append(errs, fmt.Errorf("%w, leaving open: %s", ErrDrainTimeout, strings.Join(names, ", ")))
+			return errors.Join(errs...)
+		}
+	}
+
+	// ctx may be spent by the drain, so each close gets a deadline of its own
+	for _, step := range resources {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), closeTimeout)
+		if err := step.Stop(closeCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+		}
+		cancel()
+	}
+	return errors.Join(errs...)
+}
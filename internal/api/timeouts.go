@@ -0,0 +1,65 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// ServerTimeouts bounds how long the HTTP server waits on a client
+type ServerTimeouts struct {
+	ReadHeaderTimeout time.Duration // reading the request line and headers
+	ReadTimeout       time.Duration // reading the request body, once the headers are in
+	WriteTimeout      time.Duration // writing the response
+	IdleTimeout       time.Duration // waiting for the next request on a keep-alive connection
+
+	// StreamPaths are path prefixes of long-poll and streaming routes, whose
+	// responses are not bound by WriteTimeout
+	StreamPaths []string
+}
+
+// DefaultServerTimeouts returns timeouts that cut off slow clients without
+// affecting ordinary requests
+func DefaultServerTimeouts() ServerTimeouts {
+	return ServerTimeouts{
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       120 * time.Second,
+	}
+}
+
+// isStreamPath reports whether a request URI falls under a stream path
+func (t ServerTimeouts) isStreamPath(uri string) bool {
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		uri = uri[:i]
+	}
+	for _, prefix := range t.StreamPaths {
+		if prefix != "" && strings.HasPrefix(uri, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetTimeouts sets the read, write and idle timeouts of the HTTP server
+func (s *Server) SetTimeouts(timeouts ServerTimeouts) {
+	// The server's read deadline covers the headers; once they are in,
+	// HeaderReceived moves it out for the body and sets the write deadline
+	// per route, so stream paths can run without one
+	srv := s.app.Server()
+	srv.ReadTimeout = timeouts.ReadHeaderTimeout
+	if srv.ReadTimeout == 0 {
+		srv.ReadTimeout = timeouts.ReadTimeout
+	}
+	srv.WriteTimeout = 0
+	srv.IdleTimeout = timeouts.IdleTimeout
+	srv.HeaderReceived = func(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+		config := fasthttp.RequestConfig{ReadTimeout: timeouts.ReadTimeout}
+		if !timeouts.isStreamPath(string(header.RequestURI())) {
+			config.WriteTimeout = timeouts.WriteTimeout
+		}
+		return config
+	}
+}
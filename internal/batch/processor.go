@@ -6,9 +6,14 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/validation"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
+// validatePageSize is how many cases a validate job lists from storage at a time
+const validatePageSize = 500
+
 // BatchProcessor handles batch operations on cases
 type BatchProcessor struct {
 	workers   int
@@ -17,6 +22,8 @@ type BatchProcessor struct {
 	wg        sync.WaitGroup
 	ctx       context.Context
 	cancel    context.CancelFunc
+	storage   storage.Storage
+	pipeline  *validation.Pipeline
 }
 
 // BatchJob represents a batch operation
@@ -93,6 +100,17 @@ func NewBatchProcessor(workers int) *BatchProcessor {
 	return bp
 }
 
+// SetStorage sets the storage that validate jobs read cases from and write
+// quality scores to
+func (bp *BatchProcessor) SetStorage(store storage.Storage) {
+	bp.storage = store
+}
+
+// SetValidationPipeline sets the pipeline validate jobs run cases through
+func (bp *BatchProcessor) SetValidationPipeline(pipeline *validation.Pipeline) {
+	bp.pipeline = pipeline
+}
+
 // startWorkers starts the worker goroutines
 func (bp *BatchProcessor) startWorkers() {
 	for i := 0; i < bp.workers; i++ {
@@ -215,31 +233,118 @@ func (bp *BatchProcessor) processExportJob(job BatchJob) (interface{}, error) {
 	}, nil
 }
 
-// processValidateJob processes a batch validation job
+// processValidateJob runs cases through the validation pipeline and writes
+// each case's quality score and validation metadata back to storage. The
+// input selects cases with either "cases" ([]*models.Case) or "filter"
+// (storage.CaseFilter); an empty filter validates every case. The output
+// counts valid and invalid cases, with invalid cases' error codes in
+// "reasons".
 func (bp *BatchProcessor) processValidateJob(job BatchJob) (interface{}, error) {
 	input, ok := job.Input.(map[string]interface{})
 	if !ok {
 		return nil, fmt.Errorf("invalid validate job input")
 	}
+	if bp.storage == nil || bp.pipeline == nil {
+		return nil, fmt.Errorf("validate jobs need storage and a validation pipeline")
+	}
 
-	cases := input["cases"].([]*models.Case)
+	cases, ok := input["cases"].([]*models.Case)
+	if !ok {
+		filter, _ := input["filter"].(storage.CaseFilter)
+		var err error
+		cases, err = bp.listCases(filter)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Validate cases (placeholder)
 	validCount := 0
 	invalidCount := 0
+	failedCount := 0
+	reasons := make(map[string]int)
+
+	for _, c := range cases {
+		if err := bp.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		report, err := bp.pipeline.Validate(bp.ctx, c)
+		if err != nil {
+			failedCount++
+			continue
+		}
 
-	for range cases {
-		// Placeholder validation
-		validCount++
+		applyValidationReport(c, report)
+		if err := bp.storage.UpdateCase(bp.ctx, c); err != nil {
+			failedCount++
+			continue
+		}
+
+		if report.Valid {
+			validCount++
+			continue
+		}
+		invalidCount++
+		for _, e := range report.Errors {
+			reasons[e.Code]++
+		}
 	}
 
 	return map[string]interface{}{
 		"total":   len(cases),
 		"valid":   validCount,
 		"invalid": invalidCount,
+		"failed":  failedCount,
+		"reasons": reasons,
 	}, nil
 }
 
+// listCases loads every case matching filter, a page at a time. A filter
+// limit caps the number of cases loaded.
+func (bp *BatchProcessor) listCases(filter storage.CaseFilter) ([]*models.Case, error) {
+	remaining := filter.Limit
+	var cases []*models.Case
+
+	for {
+		// Cases are validated on their full text and saved back
+		page := filter
+		page.Offset = filter.Offset + len(cases)
+		page.Limit = validatePageSize
+		page.IncludeFullText = true
+		if remaining > 0 && remaining-len(cases) < page.Limit {
+			page.Limit = remaining - len(cases)
+		}
+
+		batch, err := bp.storage.ListCases(bp.ctx, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cases to validate: %w", err)
+		}
+		cases = append(cases, batch...)
+
+		if len(batch) < page.Limit || (remaining > 0 && len(cases) >= remaining) {
+			return cases, nil
+		}
+	}
+}
+
+// applyValidationReport records a validation report on a case
+func applyValidationReport(c *models.Case, report *validation.ValidationReport) {
+	c.SetQualityScore(report.OverallScore)
+
+	errs := make([]string, 0, len(report.Errors))
+	for _, e := range report.Errors {
+		errs = append(errs, fmt.Sprintf("%s: %s", e.Field, e.Message))
+	}
+
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	c.Metadata["validation_valid"] = report.Valid
+	c.Metadata["validation_completeness"] = report.Completeness
+	c.Metadata["validation_errors"] = errs
+	c.Metadata["validated_at"] = report.Timestamp
+}
+
 // processEnrichJob processes a batch enrichment job
 func (bp *BatchProcessor) processEnrichJob(job BatchJob) (interface{}, error) {
 	input, ok := job.Input.(map[string]interface{})
@@ -407,6 +512,11 @@ func generateJobID() string {
 	return fmt.Sprintf("batch_%d", time.Now().UnixNano())
 }
 
+// Processor returns the processor that runs the manager's jobs
+func (bjm *BatchJobManager) Processor() *BatchProcessor {
+	return bjm.processor
+}
+
 // Shutdown gracefully shuts down the batch job manager
 func (bjm *BatchJobManager) Shutdown() {
 	bjm.processor.Shutdown()
@@ -0,0 +1,156 @@
+package citation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// appealWindow is how far past an appeal phrase references to the decision
+// below are looked for, in bytes
+const appealWindow = 300
+
+var (
+	// appealPhrase introduces the decision a judgment is an appeal from, as in
+	// "On appeal from [2019] EWHC 123 (QB)" or "appeal against the judgment
+	// of the High Court"
+	appealPhrase = regexp.MustCompile(`(?i)\b(?:on appeal from|appeals? (?:from|against)|appealed from|(?:court|judge|decision|judgment) below|at first instance)\b`)
+
+	// docketReference matches a docket or case number, as in "No. 19-1234",
+	// "Case No. HCA 123/2019" or "Claim No: QB-2018-000123"
+	docketReference = regexp.MustCompile(`\b(?:(?:Docket|Case|Claim|Suit|Appeal)\s+)?No\.?:?\s*([A-Z]{1,5}[ -]?\d(?:[\w/.-]*\w)?|\d(?:[\w/.-]*\w)?)`)
+)
+
+// AppealReference is a reference in a judgment to the decision it appeals
+// from: a citation, a docket number, or both
+type AppealReference struct {
+	Citation *models.Citation
+	Docket   string
+	Context  string
+}
+
+// DetectAppealReferences returns the references to the decision below that
+// follow appeal language in text, such as "On appeal from [2019] EWHC 123
+// (QB)" or "appeal from the judgment in Claim No. HQ17X01234", in order and
+// without duplicates
+func (s *Service) DetectAppealReferences(text string) []AppealReference {
+	var refs []AppealReference
+	seen := make(map[string]bool)
+
+	for _, loc := range appealPhrase.FindAllStringIndex(text, -1) {
+		window := appealContext(text, loc[0])
+
+		for _, citation := range s.extractor.ExtractCitations(window) {
+			raw := normalizeReference(citation.RawCitation)
+			if seen[raw] {
+				continue
+			}
+			seen[raw] = true
+			citation.RawCitation = raw
+			refs = append(refs, AppealReference{Citation: citation, Context: window})
+		}
+
+		for _, match := range docketReference.FindAllStringSubmatch(window, -1) {
+			docket := normalizeReference(match[1])
+			if seen[docket] {
+				continue
+			}
+			seen[docket] = true
+			refs = append(refs, AppealReference{Docket: docket, Context: window})
+		}
+	}
+
+	return refs
+}
+
+// appealContext returns the text after an appeal phrase in which the
+// decision below is named, ending at a paragraph break
+func appealContext(text string, start int) string {
+	end := start + appealWindow
+	if end > len(text) {
+		end = len(text)
+	}
+	window := text[start:end]
+	if i := strings.Index(window, "\n\n"); i >= 0 {
+		window = window[:i]
+	}
+	return strings.TrimSpace(window)
+}
+
+// LinkAppeal finds the stored decision that c appealed from and links the two
+// cases, setting c's LowerCourtCaseID and the decision's AppealedToCaseID.
+// References are resolved by case number and docket; a match must be from a
+// court c hears appeals from and not decided after c. It returns the linked
+// decision, or nil if none was found.
+func (s *Service) LinkAppeal(ctx context.Context, c *models.Case) (*models.Case, error) {
+	for _, ref := range s.DetectAppealReferences(c.FullText) {
+		lower, err := s.resolveAppealReference(ctx, c, ref)
+		if err != nil {
+			return nil, err
+		}
+		if lower == nil {
+			continue
+		}
+
+		if lower.AppealedToCaseID != c.ID {
+			lower.AppealedToCaseID = c.ID
+			if err := s.storage.UpdateCase(ctx, lower); err != nil {
+				return nil, fmt.Errorf("failed to update case %s: %w", lower.ID, err)
+			}
+		}
+		if c.LowerCourtCaseID != lower.ID {
+			c.LowerCourtCaseID = lower.ID
+			if err := s.storage.UpdateCase(ctx, c); err != nil {
+				return nil, fmt.Errorf("failed to update case %s: %w", c.ID, err)
+			}
+		}
+		return lower, nil
+	}
+
+	return nil, nil
+}
+
+// resolveAppealReference returns the stored case a reference names, if it
+// can be the decision c appealed from
+func (s *Service) resolveAppealReference(ctx context.Context, c *models.Case, ref AppealReference) (*models.Case, error) {
+	// The case found is saved back with its link, so load it whole
+	var filters []storage.CaseFilter
+	if ref.Citation != nil {
+		filters = append(filters, storage.CaseFilter{CaseNumber: ref.Citation.RawCitation, IncludeFullText: true})
+	}
+	if ref.Docket != "" {
+		filters = append(filters,
+			storage.CaseFilter{Docket: ref.Docket, IncludeFullText: true},
+			storage.CaseFilter{CaseNumber: ref.Docket, IncludeFullText: true},
+		)
+	}
+
+	for _, filter := range filters {
+		candidates, err := s.storage.ListCases(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, candidate := range candidates {
+			if s.appealedFrom(c, candidate) {
+				return candidate, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// appealedFrom reports whether lower can be the decision appeal was heard from
+func (s *Service) appealedFrom(appeal, lower *models.Case) bool {
+	if lower.ID == appeal.ID || decidedAfter(lower, appeal) {
+		return false
+	}
+	if appeal.Jurisdiction != "" && lower.Jurisdiction != "" && appeal.Jurisdiction != lower.Jurisdiction {
+		return false
+	}
+	return s.hierarchy.HearsAppealsFrom(appeal.Court, lower.Court)
+}
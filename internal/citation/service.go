@@ -2,7 +2,12 @@ package citation
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/jurisdiction"
 	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/pkg/models"
 )
@@ -12,7 +17,11 @@ type Service struct {
 	extractor *Extractor
 	normalizer *Normalizer
 	analyzer  *NetworkAnalyzer
+	treatments *TreatmentDetector
+	hierarchy *jurisdiction.CourtHierarchy
+	rules     *jurisdiction.JurisdictionRules
 	storage   storage.Storage
+	clock     clock.Clock
 }
 
 // NewService creates a new citation service
@@ -21,10 +30,19 @@ func NewService(store storage.Storage) *Service {
 		extractor:  NewExtractor(),
 		normalizer: NewNormalizer(),
 		analyzer:   NewNetworkAnalyzer(),
+		treatments: NewTreatmentDetector(),
+		hierarchy:  jurisdiction.NewCourtHierarchy(),
+		rules:      jurisdiction.NewJurisdictionRules(),
 		storage:    store,
+		clock:      clock.Real(),
 	}
 }
 
+// SetClock sets the clock used to stamp extracted citations
+func (s *Service) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
 // ExtractAndStoreCitations extracts citations from a case and stores them
 func (s *Service) ExtractAndStoreCitations(ctx context.Context, c *models.Case) ([]*models.Citation, error) {
 	// Extract citations
@@ -33,8 +51,11 @@ func (s *Service) ExtractAndStoreCitations(ctx context.Context, c *models.Case)
 	// Normalize citations
 	normalizedCitations := s.normalizer.NormalizeBatch(citations)
 
-	// Store citations
+	// Store citations, checking reporters against the citing case's jurisdiction
+	now := s.clock.Now()
 	for _, citation := range normalizedCitations {
+		citation.ExtractedAt = now
+		s.rules.ValidateCitation(c.Jurisdiction, citation)
 		if err := s.storage.CreateCitation(ctx, citation); err != nil {
 			// Log error but continue with other citations
 			continue
@@ -44,6 +65,136 @@ func (s *Service) ExtractAndStoreCitations(ctx context.Context, c *models.Case)
 	return normalizedCitations, nil
 }
 
+// NormalizeCaseCitations normalizes the citations embedded in a case, setting
+// their normalized form, and drops any that normalize to the same citation as
+// an earlier one. Citations scraped without their components are parsed from
+// the raw text first. The case is saved if its citations changed.
+func (s *Service) NormalizeCaseCitations(ctx context.Context, c *models.Case) error {
+	citations := make([]models.Citation, 0, len(c.Citations))
+	seen := make(map[string]bool, len(c.Citations))
+	for _, cit := range c.Citations {
+		normalized := s.normalizer.Normalize(s.parseRawCitation(cit))
+		key := citationKey(normalized.NormalizedCitation)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cit.NormalizedCitation = strings.Join(strings.Fields(normalized.NormalizedCitation), " ")
+		cit.IsNormalized = true
+		citations = append(citations, cit)
+	}
+
+	if reflect.DeepEqual(citations, c.Citations) {
+		return nil
+	}
+	c.Citations = citations
+	return s.storage.UpdateCase(ctx, c)
+}
+
+// parseRawCitation returns the citation with its components parsed from the
+// raw text, or the citation itself if it already has them or the raw text
+// isn't a single recognised citation
+func (s *Service) parseRawCitation(cit models.Citation) *models.Citation {
+	if cit.CaseYear != 0 || cit.Court != "" {
+		return &cit
+	}
+	raw := strings.TrimSpace(cit.RawCitation)
+	for _, parsed := range s.extractor.ExtractCitations(raw) {
+		if parsed.RawCitation == raw {
+			return parsed
+		}
+	}
+	return &cit
+}
+
+// citationKey is the form citations are deduplicated by, ignoring case and
+// spacing that normalization leaves alone
+func citationKey(normalized string) string {
+	return strings.ToLower(strings.Join(strings.Fields(normalized), " "))
+}
+
+// ApplyTreatments finds prior cases that c overrules or distinguishes and
+// records the treatment on those already stored, matched by case number.
+// Overruled cases get CaseStatusOverruled with the overruling case in metadata
+// overruled_by; distinguishing cases are listed in metadata distinguished_by.
+// It returns the updated cases.
+func (s *Service) ApplyTreatments(ctx context.Context, c *models.Case) ([]*models.Case, error) {
+	var updated []*models.Case
+
+	for _, match := range s.treatments.Detect(c.FullText) {
+		// Treated cases are saved back, so load them whole
+		treated, err := s.storage.ListCases(ctx, storage.CaseFilter{
+			CaseNumber:      match.Citation.RawCitation,
+			IncludeFullText: true,
+		})
+		if err != nil {
+			return updated, err
+		}
+
+		for _, prior := range treated {
+			// A judgment can only treat cases decided before it
+			if prior.ID == c.ID || decidedAfter(prior, c) {
+				continue
+			}
+			if !applyTreatment(prior, c, match.Treatment) {
+				continue
+			}
+			if err := s.storage.UpdateCase(ctx, prior); err != nil {
+				return updated, fmt.Errorf("failed to update case %s: %w", prior.ID, err)
+			}
+			updated = append(updated, prior)
+		}
+	}
+
+	return updated, nil
+}
+
+// applyTreatment records on prior how by treated it, reporting whether prior changed
+func applyTreatment(prior, by *models.Case, treatment string) bool {
+	if prior.Metadata == nil {
+		prior.Metadata = make(map[string]interface{})
+	}
+
+	switch treatment {
+	case TreatmentOverruled:
+		if prior.Status == models.CaseStatusOverruled && prior.Metadata["overruled_by"] == by.ID {
+			return false
+		}
+		prior.Status = models.CaseStatusOverruled
+		prior.Metadata["overruled_by"] = by.ID
+		prior.Metadata["overruled_by_citation"] = by.CaseNumber
+		return true
+	case TreatmentDistinguished:
+		return addMetadataValue(prior.Metadata, "distinguished_by", by.ID)
+	}
+
+	return false
+}
+
+// addMetadataValue adds value to the list stored under key, reporting whether
+// it was missing. Lists read back from storage may be of any slice type.
+func addMetadataValue(metadata map[string]interface{}, key, value string) bool {
+	var values []string
+	if existing := reflect.ValueOf(metadata[key]); existing.Kind() == reflect.Slice {
+		for i := 0; i < existing.Len(); i++ {
+			v := fmt.Sprint(existing.Index(i).Interface())
+			if v == value {
+				return false
+			}
+			values = append(values, v)
+		}
+	}
+
+	metadata[key] = append(values, value)
+	return true
+}
+
+// decidedAfter reports whether a was decided after b, when both dates are known
+func decidedAfter(a, b *models.Case) bool {
+	return a.DecisionDate != nil && b.DecisionDate != nil && a.DecisionDate.After(*b.DecisionDate)
+}
+
 // ExtractCitationsFromText extracts citations from raw text
 func (s *Service) ExtractCitationsFromText(text string) []*models.Citation {
 	citations := s.extractor.ExtractCitations(text)
@@ -70,6 +221,16 @@ func (s *Service) BuildCitationNetwork(ctx context.Context) (*models.CitationNet
 	return network, nil
 }
 
+// GetCitationNetwork returns the citation network within depth hops of a case
+func (s *Service) GetCitationNetwork(ctx context.Context, caseID string, depth int) (*models.CitationNetwork, error) {
+	// Make sure the root case exists
+	if _, err := s.storage.GetCase(ctx, caseID); err != nil {
+		return nil, err
+	}
+
+	return LoadCaseSubgraph(ctx, s.storage, caseID, depth)
+}
+
 // GetMostCitedCases returns the most cited cases
 func (s *Service) GetMostCitedCases(ctx context.Context, limit int) ([]*models.CitationNode, error) {
 	// Build network
@@ -0,0 +1,172 @@
+package citation
+
+import (
+	"context"
+
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// subgraphBatchSize caps the case IDs looked up in one storage query, which
+// stays under SQLite's bind parameter limit
+const subgraphBatchSize = 400
+
+// LoadCaseSubgraph builds the citation network within depth hops of a case
+// from storage, as BuildCaseSubgraph does. Only the citations of each level's
+// cases are loaded, one query per level, and node case names are loaded in
+// batches.
+func LoadCaseSubgraph(ctx context.Context, store storage.Storage, rootCaseID string, depth int) (*models.CitationNetwork, error) {
+	citations := make([]*models.Citation, 0)
+	seen := make(map[string]bool)
+	visited := map[string]bool{rootCaseID: true}
+	frontier := []string{rootCaseID}
+
+	// The last level is loaded too, for the edges between its cases
+	for hop := 0; hop <= depth && len(frontier) > 0; hop++ {
+		level, err := citationsOf(ctx, store, frontier, seen)
+		if err != nil {
+			return nil, err
+		}
+		citations = append(citations, level...)
+
+		next := make([]string, 0)
+		for _, c := range level {
+			for _, id := range []string{c.CitingCaseID, c.CaseID} {
+				if id != "" && !visited[id] {
+					visited[id] = true
+					next = append(next, id)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	network := BuildCaseSubgraph(rootCaseID, citations, depth)
+
+	ids := make([]string, len(network.Nodes))
+	for i, n := range network.Nodes {
+		ids[i] = n.CaseID
+	}
+	names := make(map[string]string, len(ids))
+	for start := 0; start < len(ids); start += subgraphBatchSize {
+		end := start + subgraphBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		cases, err := store.ListCases(ctx, storage.CaseFilter{IDs: ids[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cases {
+			names[c.ID] = c.CaseName
+		}
+	}
+	for i := range network.Nodes {
+		network.Nodes[i].CaseName = names[network.Nodes[i].CaseID]
+	}
+
+	return network, nil
+}
+
+// citationsOf loads the citations made by or pointing at any of the cases,
+// leaving out those already seen
+func citationsOf(ctx context.Context, store storage.Storage, caseIDs []string, seen map[string]bool) ([]*models.Citation, error) {
+	citations := make([]*models.Citation, 0)
+	for start := 0; start < len(caseIDs); start += subgraphBatchSize {
+		end := start + subgraphBatchSize
+		if end > len(caseIDs) {
+			end = len(caseIDs)
+		}
+		batch, err := store.ListCitations(ctx, storage.CitationFilter{CaseIDs: caseIDs[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range batch {
+			if c.ID != "" {
+				if seen[c.ID] {
+					continue
+				}
+				seen[c.ID] = true
+			}
+			citations = append(citations, c)
+		}
+	}
+	return citations, nil
+}
+
+// BuildCaseSubgraph builds the citation network within depth hops of a case,
+// following citations in both directions (cases cited by and citing the root).
+// Edges are included when both endpoints are within the subgraph.
+func BuildCaseSubgraph(rootCaseID string, citations []*models.Citation, depth int) *models.CitationNetwork {
+	network := &models.CitationNetwork{
+		Nodes: make([]models.CitationNode, 0),
+		Edges: make([]models.CitationEdge, 0),
+	}
+
+	if rootCaseID == "" {
+		return network
+	}
+
+	// Build adjacency in both directions
+	neighbors := make(map[string][]string)
+	for _, c := range citations {
+		if c.CitingCaseID == "" || c.CaseID == "" || c.CitingCaseID == c.CaseID {
+			continue
+		}
+		neighbors[c.CitingCaseID] = append(neighbors[c.CitingCaseID], c.CaseID)
+		neighbors[c.CaseID] = append(neighbors[c.CaseID], c.CitingCaseID)
+	}
+
+	// Breadth-first search up to depth hops
+	visited := map[string]bool{rootCaseID: true}
+	order := []string{rootCaseID}
+	frontier := []string{rootCaseID}
+	for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+		next := make([]string, 0)
+		for _, id := range frontier {
+			for _, n := range neighbors[id] {
+				if !visited[n] {
+					visited[n] = true
+					order = append(order, n)
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	// Collect edges within the subgraph
+	inDegree := make(map[string]int)
+	outDegree := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, c := range citations {
+		if !visited[c.CitingCaseID] || !visited[c.CaseID] || c.CitingCaseID == c.CaseID {
+			continue
+		}
+
+		key := c.CitingCaseID + "->" + c.CaseID
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		network.Edges = append(network.Edges, models.CitationEdge{
+			FromCaseID:    c.CitingCaseID,
+			ToCaseID:      c.CaseID,
+			TreatmentType: c.TreatmentType,
+			Weight:        1.0,
+		})
+		outDegree[c.CitingCaseID]++
+		inDegree[c.CaseID]++
+	}
+
+	for _, id := range order {
+		network.Nodes = append(network.Nodes, models.CitationNode{
+			CaseID:    id,
+			InDegree:  inDegree[id],
+			OutDegree: outDegree[id],
+		})
+	}
+
+	return network
+}
@@ -0,0 +1,171 @@
+package citation
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// Treatments of a cited case, recorded in Citation.TreatmentType
+const (
+	TreatmentOverruled     = "overruled"
+	TreatmentDistinguished = "distinguished"
+)
+
+// TreatmentMatch is a prior case that a judgment overrules or distinguishes
+type TreatmentMatch struct {
+	Citation  *models.Citation // the reference to the prior case
+	Treatment string
+	Sentence  string
+}
+
+// treatmentPhrase is language that applies a treatment to the cases cited
+// around it
+type treatmentPhrase struct {
+	treatment string
+	pattern   *regexp.Regexp
+}
+
+var treatmentPhrases = []treatmentPhrase{
+	{TreatmentOverruled, regexp.MustCompile(`(?i)\b(?:overrul(?:e|es|ed|ing)|(?:is|was) no longer good law)\b`)},
+	{TreatmentDistinguished, regexp.MustCompile(`(?i)\bdistinguish(?:es|ed|ing|able)?\b`)},
+}
+
+var (
+	// treatmentNegation matches a negation just before a treatment phrase, as
+	// in "we decline to overrule" or "it is not necessary to distinguish"
+	treatmentNegation = regexp.MustCompile(`(?i)\b(?:not|never|decline to|declined to|need not|no need to|unnecessary to)(?:\s+\w+){0,3}\s*$`)
+	sentenceBreak     = regexp.MustCompile(`[.!?;]\s+|\n\s*\n`)
+	clauseBreak       = regexp.MustCompile(`(?i),|\s(?:but|whereas|while)\s`)
+)
+
+// TreatmentDetector finds overruling and distinguishing language in judgments
+type TreatmentDetector struct {
+	extractor *Extractor
+}
+
+// NewTreatmentDetector creates a new treatment detector
+func NewTreatmentDetector() *TreatmentDetector {
+	return &TreatmentDetector{
+		extractor: NewExtractor(),
+	}
+}
+
+// Detect returns the cases a judgment treats, one match per cited case. Each
+// citation in a sentence with treatment language takes the nearest phrase in
+// its clause, or in the sentence if its clause has none, so "we distinguish A
+// but overrule B" treats A and B differently.
+func (td *TreatmentDetector) Detect(text string) []TreatmentMatch {
+	var matches []TreatmentMatch
+	seen := make(map[string]bool)
+
+	for _, sentence := range sentenceBreak.Split(text, -1) {
+		phrases := treatmentPositions(sentence)
+		if len(phrases) == 0 {
+			continue
+		}
+
+		for _, citation := range td.extractor.ExtractCitations(sentence) {
+			raw := normalizeReference(citation.RawCitation)
+			if seen[raw] {
+				continue
+			}
+
+			offset := strings.Index(sentence, citation.RawCitation)
+			candidates := phrases
+			if inClause := clausePhrases(sentence, phrases, offset); len(inClause) > 0 {
+				candidates = inClause
+			}
+
+			treatment, ok := nearestTreatment(candidates, offset)
+			if !ok {
+				continue
+			}
+
+			seen[raw] = true
+			citation.RawCitation = raw
+			citation.TreatmentType = treatment
+			citation.Context = strings.TrimSpace(sentence)
+			matches = append(matches, TreatmentMatch{
+				Citation:  citation,
+				Treatment: treatment,
+				Sentence:  citation.Context,
+			})
+		}
+	}
+
+	return matches
+}
+
+// treatmentPosition is a treatment phrase found at an offset in a sentence
+type treatmentPosition struct {
+	treatment string
+	offset    int
+	negated   bool
+}
+
+// treatmentPositions finds every treatment phrase in a sentence
+func treatmentPositions(sentence string) []treatmentPosition {
+	var positions []treatmentPosition
+	for _, phrase := range treatmentPhrases {
+		for _, loc := range phrase.pattern.FindAllStringIndex(sentence, -1) {
+			positions = append(positions, treatmentPosition{
+				treatment: phrase.treatment,
+				offset:    loc[0],
+				negated:   treatmentNegation.MatchString(sentence[:loc[0]]),
+			})
+		}
+	}
+	return positions
+}
+
+// clausePhrases returns the phrases in the clause of sentence containing offset
+func clausePhrases(sentence string, positions []treatmentPosition, offset int) []treatmentPosition {
+	start, end := 0, len(sentence)
+	for _, loc := range clauseBreak.FindAllStringIndex(sentence, -1) {
+		if loc[1] <= offset {
+			start = loc[1]
+		} else if loc[0] > offset {
+			end = loc[0]
+			break
+		}
+	}
+
+	var inClause []treatmentPosition
+	for _, p := range positions {
+		if p.offset >= start && p.offset < end {
+			inClause = append(inClause, p)
+		}
+	}
+	return inClause
+}
+
+// nearestTreatment returns the treatment of the phrase closest to offset. A
+// negated phrase ("we do not overrule") leaves the citation untreated.
+func nearestTreatment(positions []treatmentPosition, offset int) (string, bool) {
+	best := -1
+	for i, p := range positions {
+		if best < 0 || distance(p.offset, offset) < distance(positions[best].offset, offset) {
+			best = i
+		}
+	}
+
+	if best < 0 || positions[best].negated {
+		return "", false
+	}
+	return positions[best].treatment, true
+}
+
+func distance(a, b int) int {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// normalizeReference collapses whitespace so that a reference matches the
+// stored case number
+func normalizeReference(raw string) string {
+	return strings.Join(strings.Fields(raw), " ")
+}
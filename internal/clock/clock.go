@@ -0,0 +1,55 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time. Code that stamps or compares timestamps takes a Clock
+// so that tests can supply a fixed time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real returns a Clock that reads the system time
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed is a Clock that stays at a set time until it is moved
+type Fixed struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewFixed creates a Fixed clock set to now
+func NewFixed(now time.Time) *Fixed {
+	return &Fixed{now: now}
+}
+
+// Now returns the clock's current time
+func (f *Fixed) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+// Set moves the clock to now
+func (f *Fixed) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the clock forward by d
+func (f *Fixed) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -2,6 +2,8 @@ package compliance
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,12 +13,19 @@ import (
 // AttributionHandler manages attribution requirements for scraped data
 type AttributionHandler struct {
 	policyManager *PolicyManager
+	styles        map[string]CitationStyle // keyed by lowercase jurisdiction
 }
 
 // NewAttributionHandler creates a new attribution handler
 func NewAttributionHandler(pm *PolicyManager) *AttributionHandler {
+	styles := make(map[string]CitationStyle, len(jurisdictionCitationStyles))
+	for jurisdiction, style := range jurisdictionCitationStyles {
+		styles[jurisdiction] = style
+	}
+
 	return &AttributionHandler{
 		policyManager: pm,
+		styles:        styles,
 	}
 }
 
@@ -211,11 +220,28 @@ func (ah *AttributionHandler) ValidateAttribution(c *models.Case) (bool, []strin
 	return len(issues) == 0, issues
 }
 
-// GenerateCitation generates a proper legal citation with attribution
+// GenerateCitation generates a proper legal citation with attribution. An empty
+// or auto style uses the customary style of the case's jurisdiction; any other
+// style overrides it.
 func (ah *AttributionHandler) GenerateCitation(c *models.Case, style CitationStyle) string {
+	return ah.GeneratePinpointCitation(c, style, "")
+}
+
+// GeneratePinpointCitation generates a citation to a paragraph of a case, such
+// as "45" or "45-47". Pinpoints are only written by styles that cite
+// paragraphs (OSCOLA and AGLC); other styles cite the whole case.
+func (ah *AttributionHandler) GeneratePinpointCitation(c *models.Case, style CitationStyle, paragraph string) string {
+	if style == "" || style == CitationStyleAuto {
+		style = ah.CitationStyleFor(c.Jurisdiction)
+	}
+
 	switch style {
 	case CitationStyleBluebook:
 		return ah.generateBluebookCitation(c)
+	case CitationStyleOSCOLA:
+		return ah.generateOSCOLACitation(c, paragraph)
+	case CitationStyleAGLC:
+		return ah.generateAGLCCitation(c, paragraph)
 	case CitationStyleAPA:
 		return ah.generateAPACitation(c)
 	case CitationStyleMLA:
@@ -229,20 +255,69 @@ func (ah *AttributionHandler) GenerateCitation(c *models.Case, style CitationSty
 type CitationStyle string
 
 const (
+	CitationStyleAuto     CitationStyle = "auto" // the customary style of the case's jurisdiction
 	CitationStylePlain    CitationStyle = "plain"
 	CitationStyleBluebook CitationStyle = "bluebook"
+	CitationStyleOSCOLA   CitationStyle = "oscola"
+	CitationStyleAGLC     CitationStyle = "aglc"
 	CitationStyleAPA      CitationStyle = "apa"
 	CitationStyleMLA      CitationStyle = "mla"
 )
 
+// jurisdictionCitationStyles maps jurisdictions to the citation style their
+// courts and legal writers use
+var jurisdictionCitationStyles = map[string]CitationStyle{
+	"united states":     CitationStyleBluebook,
+	"us":                CitationStyleBluebook,
+	"usa":               CitationStyleBluebook,
+	"united kingdom":    CitationStyleOSCOLA,
+	"uk":                CitationStyleOSCOLA,
+	"england and wales": CitationStyleOSCOLA,
+	"scotland":          CitationStyleOSCOLA,
+	"northern ireland":  CitationStyleOSCOLA,
+	"ireland":           CitationStyleOSCOLA,
+	"australia":         CitationStyleAGLC,
+	"au":                CitationStyleAGLC,
+}
+
+// SetJurisdictionStyle overrides the citation style used for a jurisdiction
+func (ah *AttributionHandler) SetJurisdictionStyle(jurisdiction string, style CitationStyle) {
+	ah.styles[strings.ToLower(strings.TrimSpace(jurisdiction))] = style
+}
+
+// CitationStyleFor returns the citation style for a jurisdiction, plain if it
+// has no customary style
+func (ah *AttributionHandler) CitationStyleFor(jurisdiction string) CitationStyle {
+	if style, ok := ah.styles[strings.ToLower(strings.TrimSpace(jurisdiction))]; ok {
+		return style
+	}
+	return CitationStylePlain
+}
+
+// reporterCitation matches a reported citation such as "573 U.S. 373"
+var reporterCitation = regexp.MustCompile(`^\d+ [A-Za-z0-9. ]+ \d+$`)
+
 // generateBluebookCitation generates a Bluebook-style citation
 func (ah *AttributionHandler) generateBluebookCitation(c *models.Case) string {
-	// Example: Case Name, Citation, Court Date
+	// Example: Case Name, 573 U.S. 373 (2014)
+	// Unreported: Case Name, Citation (Court Date)
 	citation := c.CaseName
 	if c.CaseNumber != "" {
 		citation += ", " + c.CaseNumber
 	}
-	if c.Court != "" {
+	if reporterCitation.MatchString(c.CaseNumber) {
+		// Reported cases give the year, and the court unless the reporter implies it
+		var parenthetical []string
+		if c.Court != "" && !strings.Contains(c.CaseNumber, " U.S. ") {
+			parenthetical = append(parenthetical, c.Court)
+		}
+		if c.DecisionDate != nil {
+			parenthetical = append(parenthetical, strconv.Itoa(c.DecisionDate.Year()))
+		}
+		if len(parenthetical) > 0 {
+			citation += " (" + strings.Join(parenthetical, " ") + ")"
+		}
+	} else if c.Court != "" {
 		citation += " (" + c.Court
 		if c.DecisionDate != nil {
 			citation += " " + c.DecisionDate.Format("Jan. 2, 2006")
@@ -258,6 +333,59 @@ func (ah *AttributionHandler) generateBluebookCitation(c *models.Case) string {
 	return citation
 }
 
+// generateOSCOLACitation generates an OSCOLA-style citation, as used in the UK
+func (ah *AttributionHandler) generateOSCOLACitation(c *models.Case, paragraph string) string {
+	// Neutral: Case Name [2017] UKSC 5 [45]
+	// Law report: Case Name [1932] AC 562 (HL) [45]
+	// Unreported: Case Name (Court, 2 January 2006) [45]
+	citation := legalPartyNames(c.CaseName)
+	if number := strings.TrimSpace(c.CaseNumber); number != "" {
+		citation += " " + number
+		// Law reports don't identify the court, so OSCOLA adds it
+		if court, ok := oscolaCourtAbbreviations[c.CourtID]; ok && !isNeutralCitation(number) {
+			citation += " (" + court + ")"
+		}
+	} else {
+		citation += unreportedDetails(c, false)
+	}
+
+	if pinpoint := paragraphPinpoint(paragraph); pinpoint != "" {
+		citation += " " + pinpoint
+	}
+
+	if attribution := ah.policyManager.GetAttributionText(c.SourceDatabase); attribution != "" {
+		citation += ". " + attribution
+	}
+
+	return citation
+}
+
+// generateAGLCCitation generates an AGLC-style citation, as used in Australia
+func (ah *AttributionHandler) generateAGLCCitation(c *models.Case, paragraph string) string {
+	// Neutral: Case Name [2023] HCA 15, [45]
+	// Law report: Case Name (1992) 175 CLR 1, [45]
+	// Unreported: Case Name (Court, Judge J, 15 June 2023) [45]
+	citation := legalPartyNames(c.CaseName)
+	pinpoint := paragraphPinpoint(paragraph)
+	if number := strings.TrimSpace(c.CaseNumber); number != "" {
+		citation += " " + number
+		if pinpoint != "" {
+			citation += ", " + pinpoint
+		}
+	} else {
+		citation += unreportedDetails(c, true)
+		if pinpoint != "" {
+			citation += " " + pinpoint
+		}
+	}
+
+	if attribution := ah.policyManager.GetAttributionText(c.SourceDatabase); attribution != "" {
+		citation += ". " + attribution
+	}
+
+	return citation
+}
+
 // generateAPACitation generates an APA-style citation
 func (ah *AttributionHandler) generateAPACitation(c *models.Case) string {
 	// Example: Case Name, Citation (Court Year)
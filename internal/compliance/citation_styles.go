@@ -0,0 +1,88 @@
+package compliance
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+var (
+	// versusPattern matches the ways scraped case names separate the parties
+	versusPattern = regexp.MustCompile(`(?i)\s+(?:v|vs|versus)\.?\s+`)
+
+	// abbreviationStops matches full stops after abbreviations in party names,
+	// which OSCOLA and AGLC both omit ("Ltd", "Pty", "(No 2)")
+	abbreviationStops = regexp.MustCompile(`\b(Ltd|Pty|Co|Inc|Corp|Plc|Bros|No)\.`)
+
+	// neutralCitation matches a neutral citation such as "[2017] UKSC 5",
+	// "[2019] EWCA Civ 1010", "[2019] EWHC 1234 (Ch)" or "2016 SCC 27"
+	neutralCitation = regexp.MustCompile(`^(?:\[\d{4}\]|\d{4}) ([A-Z]{3,}(?: [A-Z][a-z]+)?) \d+(?: \([A-Za-z]+\))?$`)
+
+	paragraphNumber = regexp.MustCompile(`\d+`)
+)
+
+// lawReportSeries are report abbreviations that look like neutral citation
+// court identifiers, as in "[2001] ICR 1"
+var lawReportSeries = map[string]bool{
+	"ICR": true, "IRLR": true, "EMLR": true, "PNLR": true, "BCLC": true, "BCC": true,
+	"FLR": true, "FSR": true, "RPC": true, "HLR": true, "CMLR": true, "WLR": true,
+}
+
+// oscolaCourtAbbreviations maps court identifiers to the abbreviation OSCOLA
+// places after a law report citation that doesn't identify the court
+var oscolaCourtAbbreviations = map[string]string{
+	"UKHL": "HL",
+	"UKPC": "PC",
+	"EWCA": "CA",
+	"EWHC": "HC",
+}
+
+// legalPartyNames formats a case name the way OSCOLA and AGLC write it: "v"
+// between the parties and no full stops in abbreviations
+func legalPartyNames(name string) string {
+	name = strings.Join(strings.Fields(name), " ")
+	name = versusPattern.ReplaceAllString(name, " v ")
+	return abbreviationStops.ReplaceAllString(name, "$1")
+}
+
+// isNeutralCitation reports whether a citation is a court-issued neutral
+// citation rather than a law report citation
+func isNeutralCitation(citation string) bool {
+	m := neutralCitation.FindStringSubmatch(citation)
+	return m != nil && !lawReportSeries[m[1]]
+}
+
+// paragraphPinpoint formats a paragraph reference such as "45", "para 45" or
+// "45-47" as "[45]" or "[45]–[47]", or returns "" if it has no paragraph number
+func paragraphPinpoint(paragraph string) string {
+	numbers := paragraphNumber.FindAllString(paragraph, -1)
+	switch {
+	case len(numbers) == 0:
+		return ""
+	case len(numbers) == 1 || numbers[0] == numbers[len(numbers)-1]:
+		return "[" + numbers[0] + "]"
+	default:
+		return "[" + numbers[0] + "]–[" + numbers[len(numbers)-1] + "]"
+	}
+}
+
+// unreportedDetails returns the parenthetical identifying an unreported
+// judgment: the court, the judges if withJudges is set, and the full date
+func unreportedDetails(c *models.Case, withJudges bool) string {
+	var details []string
+	if c.Court != "" {
+		details = append(details, c.Court)
+	}
+	if withJudges && len(c.Judges) > 0 {
+		details = append(details, strings.Join(c.Judges, ", "))
+	}
+	if c.DecisionDate != nil {
+		details = append(details, c.DecisionDate.Format("2 January 2006"))
+	}
+
+	if len(details) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(details, ", ") + ")"
+}
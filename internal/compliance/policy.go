@@ -27,6 +27,15 @@ type ScrapingPolicy struct {
 	BulkDownload      bool            `yaml:"bulk_download" json:"bulk_download"`
 	APIAvailable      bool            `yaml:"api_available" json:"api_available"`
 	ContactEmail      string          `yaml:"contact_email,omitempty" json:"contact_email,omitempty"`
+
+	// Regular expressions matching URLs or paths that are never scraped,
+	// such as index pages and other non-case content
+	Blocklist []string `yaml:"blocklist,omitempty" json:"blocklist,omitempty"`
+
+	// Times of day the source may be scraped, as "HH:MM-HH:MM" ranges in
+	// Timezone, the source's IANA timezone; empty allows any time
+	ScrapeWindows []string `yaml:"scrape_windows,omitempty" json:"scrape_windows,omitempty"`
+	Timezone      string   `yaml:"timezone,omitempty" json:"timezone,omitempty"`
 }
 
 // CommercialUsePolicy represents the commercial use policy
@@ -66,6 +75,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		APIAvailable:      true,
 		ContactEmail:      "info@free.law",
 		Restrictions:      []string{"Must credit Free Law Project"},
+		Blocklist:         []string{`^/(api|help|about)/`},
+		Timezone:          "America/New_York",
 	})
 
 	// CanLII (Canada)
@@ -84,6 +95,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		APIAvailable:      true,
 		ContactEmail:      "info@canlii.org",
 		Restrictions:      []string{"Must include citation", "Non-commercial preferred"},
+		Blocklist:         []string{`/laws/(stat|regu)/`, `/nav/`},
+		Timezone:          "America/Toronto",
 	})
 
 	// BAILII (UK/Ireland)
@@ -101,6 +114,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		BulkDownload:      false,
 		APIAvailable:      false,
 		Restrictions:      []string{"Must credit BAILII", "Commercial use requires permission"},
+		Blocklist:         []string{`^/cgi-bin/`, `/index\.html?$`, `/(recent|databases)\.html$`},
+		Timezone:          "Europe/London",
 	})
 
 	// AustLII (Australia)
@@ -118,6 +133,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		BulkDownload:      false,
 		APIAvailable:      false,
 		Restrictions:      []string{"Must credit AustLII", "Non-commercial preferred"},
+		Blocklist:         []string{`^/cgi-bin/`, `/index\.html?$`, `^/au/legis/`},
+		Timezone:          "Australia/Sydney",
 	})
 
 	// HKLII (Hong Kong)
@@ -135,6 +152,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		BulkDownload:      false,
 		APIAvailable:      false,
 		Restrictions:      []string{"Must credit HKLII", "Respect robots.txt"},
+		Blocklist:         []string{`/index\.html?$`, `/legis/`},
+		Timezone:          "Asia/Hong_Kong",
 	})
 
 	// Indian Kanoon (India)
@@ -153,6 +172,8 @@ func (pm *PolicyManager) initializeDefaults() {
 		APIAvailable:      false,
 		ContactEmail:      "contact@indiankanoon.org",
 		Restrictions:      []string{"Moderate use only", "Must credit Indian Kanoon"},
+		Blocklist:         []string{`^/(search|browse)/`},
+		Timezone:          "Asia/Kolkata",
 	})
 
 	// Add more jurisdictions as needed
@@ -8,6 +8,10 @@ import (
 	"github.com/gongahkia/kite/pkg/models"
 )
 
+// catchwordConfidence is the confidence of a concept named in a case's
+// catchwords, which the reporter's editors assign
+const catchwordConfidence = 0.95
+
 // Extractor extracts legal concepts from case text
 type Extractor struct {
 	taxonomy *Taxonomy
@@ -75,9 +79,66 @@ func (e *Extractor) ExtractConceptsFromCase(ctx context.Context, c *models.Case)
 		c.Summary,
 		c.FullText,
 		strings.Join(c.KeyIssues, " "),
+		strings.Join(c.Catchwords, " "),
 	}, " ")
 
-	return e.ExtractConcepts(ctx, text)
+	matches := e.ExtractConcepts(ctx, text)
+	if len(c.Catchwords) > 0 {
+		matches = e.boostCatchwords(matches, c.Catchwords)
+	}
+	return matches
+}
+
+// boostCatchwords raises concepts named in catchwords to catchwordConfidence,
+// adding those the text alone did not match
+func (e *Extractor) boostCatchwords(matches []models.ConceptMatch, catchwords []string) []models.ConceptMatch {
+	named := make(map[string]*models.LegalConcept)
+	for _, concept := range e.taxonomy.GetAllConcepts() {
+		if namesConcept(catchwords, concept) {
+			named[concept.ID] = concept
+		}
+	}
+	if len(named) == 0 {
+		return matches
+	}
+
+	for i := range matches {
+		if _, ok := named[matches[i].ConceptID]; !ok {
+			continue
+		}
+		if matches[i].Confidence < catchwordConfidence {
+			matches[i].Confidence = catchwordConfidence
+		}
+		delete(named, matches[i].ConceptID)
+	}
+
+	for _, concept := range named {
+		matches = append(matches, models.ConceptMatch{
+			ConceptID:  concept.ID,
+			Name:       concept.Name,
+			Area:       concept.Area,
+			Confidence: catchwordConfidence,
+		})
+	}
+
+	sortByConfidence(matches)
+	return matches
+}
+
+// namesConcept reports whether a catchword is the concept's name, a synonym or
+// an alias. Keywords are not enough, since several concepts share them.
+func namesConcept(catchwords []string, concept *models.LegalConcept) bool {
+	names := append([]string{concept.Name}, concept.Synonyms...)
+	names = append(names, concept.Aliases...)
+
+	for _, catchword := range catchwords {
+		for _, name := range names {
+			if strings.EqualFold(strings.TrimSpace(catchword), name) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ExtractConceptsConcurrent extracts concepts from multiple texts concurrently
@@ -2,6 +2,7 @@ package concepts
 
 import (
 	"context"
+	"sort"
 
 	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/pkg/models"
@@ -38,7 +39,7 @@ func (s *Service) ExtractAndStoreConcepts(ctx context.Context, c *models.Case) (
 	}
 
 	// Store updated case
-	if err := s.storage.UpdateCase(ctx, c.ID, c); err != nil {
+	if err := s.storage.UpdateCase(ctx, c); err != nil {
 		return nil, err
 	}
 
@@ -65,6 +66,30 @@ func (s *Service) GetConceptsByArea(area models.AreaOfLaw) []*models.LegalConcep
 	return s.taxonomy.GetConceptsByArea(area)
 }
 
+// ListConcepts returns the concepts in the taxonomy ordered by ID, only those
+// in area if it is not empty
+func (s *Service) ListConcepts(area models.AreaOfLaw) []*models.LegalConcept {
+	var concepts []*models.LegalConcept
+	if area != "" {
+		concepts = s.taxonomy.GetConceptsByArea(area)
+	} else {
+		concepts = s.taxonomy.GetAllConcepts()
+	}
+
+	sort.Slice(concepts, func(i, j int) bool { return concepts[i].ID < concepts[j].ID })
+	return concepts
+}
+
+// GetConceptParents returns the ancestors of a concept, nearest first
+func (s *Service) GetConceptParents(id string) []*models.LegalConcept {
+	return s.taxonomy.GetParents(id)
+}
+
+// GetConceptChildren returns the concepts directly beneath a concept
+func (s *Service) GetConceptChildren(id string) []*models.LegalConcept {
+	return s.taxonomy.GetChildren(id)
+}
+
 // SearchConcepts searches for concepts by keyword
 func (s *Service) SearchConcepts(keyword string) []*models.LegalConcept {
 	return s.taxonomy.SearchByKeyword(keyword)
@@ -77,8 +102,8 @@ func (s *Service) GetTaxonomyStats() map[string]int {
 
 // AnalyzeConceptDistribution analyzes concept distribution across cases
 func (s *Service) AnalyzeConceptDistribution(ctx context.Context) (map[models.AreaOfLaw]int, error) {
-	// Get all cases
-	cases, err := s.storage.ListCases(ctx, storage.CaseFilter{})
+	// Get all cases, with the full text concepts are extracted from
+	cases, err := s.storage.ListCases(ctx, storage.CaseFilter{IncludeFullText: true})
 	if err != nil {
 		return nil, err
 	}
@@ -117,6 +142,36 @@ func (s *Service) GetRelatedConcepts(conceptID string) []*models.LegalConcept {
 	return filtered
 }
 
+// defaultCooccurrenceLimit is the number of related concepts returned when no limit is given
+const defaultCooccurrenceLimit = 10
+
+// GetConceptCooccurrence returns the concepts that most often appear alongside
+// a concept in stored cases, with the number of cases they share. conceptID
+// may be a taxonomy ID or a concept name as stored on cases.
+func (s *Service) GetConceptCooccurrence(ctx context.Context, conceptID string, limit int) ([]storage.ConceptPair, error) {
+	if limit <= 0 {
+		limit = defaultCooccurrenceLimit
+	}
+
+	return storage.ConceptCooccurrence(ctx, s.storage, s.conceptName(conceptID), storage.CaseFilter{}, limit)
+}
+
+// ListConceptCases returns a page of the stored cases tagged with a concept,
+// ordered by case ID, and the number tagged in total. conceptID may be a
+// taxonomy ID or a concept name as stored on cases.
+func (s *Service) ListConceptCases(ctx context.Context, conceptID string, limit, offset int) ([]*models.Case, int64, error) {
+	return storage.CasesByConcept(ctx, s.storage, s.conceptName(conceptID), storage.CaseFilter{Limit: limit, Offset: offset})
+}
+
+// conceptName returns the name cases are tagged with for a taxonomy ID, or
+// conceptID itself if it isn't one
+func (s *Service) conceptName(conceptID string) string {
+	if concept, exists := s.taxonomy.GetConcept(conceptID); exists {
+		return concept.Name
+	}
+	return conceptID
+}
+
 // BatchExtractConcepts extracts concepts from multiple cases concurrently
 func (s *Service) BatchExtractConcepts(ctx context.Context, cases []*models.Case) ([][]models.ConceptMatch, error) {
 	texts := make([]string, len(cases))
@@ -134,7 +189,7 @@ func (s *Service) BatchExtractConcepts(ctx context.Context, cases []*models.Case
 		}
 
 		// Store updated case
-		if err := s.storage.UpdateCase(ctx, cases[i].ID, cases[i]); err != nil {
+		if err := s.storage.UpdateCase(ctx, cases[i]); err != nil {
 			// Log error but continue
 			continue
 		}
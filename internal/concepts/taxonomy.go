@@ -1,6 +1,7 @@
 package concepts
 
 import (
+	"sort"
 	"strings"
 	"sync"
 
@@ -36,6 +37,20 @@ func (t *Taxonomy) GetConcept(id string) (*models.LegalConcept, bool) {
 	return concept, exists
 }
 
+// ImportanceOf returns the importance of the concept with the given name,
+// ignoring case, as cases are tagged by concept name
+func (t *Taxonomy) ImportanceOf(name string) (int, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, concept := range t.concepts {
+		if strings.EqualFold(concept.Name, name) {
+			return concept.Importance, true
+		}
+	}
+	return 0, false
+}
+
 // GetConceptsByArea retrieves all concepts for a specific area of law
 func (t *Taxonomy) GetConceptsByArea(area models.AreaOfLaw) []*models.LegalConcept {
 	t.mu.RLock()
@@ -99,6 +114,57 @@ func (t *Taxonomy) GetAllConcepts() []*models.LegalConcept {
 	return concepts
 }
 
+// GetParents returns the ancestors of a concept, nearest first
+func (t *Taxonomy) GetParents(id string) []*models.LegalConcept {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	parents := make([]*models.LegalConcept, 0)
+	seen := map[string]bool{id: true}
+	for concept, ok := t.concepts[id]; ok && concept.ParentConcept != ""; {
+		if seen[concept.ParentConcept] {
+			break
+		}
+		seen[concept.ParentConcept] = true
+
+		concept, ok = t.concepts[concept.ParentConcept]
+		if ok {
+			parents = append(parents, concept)
+		}
+	}
+
+	return parents
+}
+
+// GetChildren returns the concepts directly beneath a concept, whether listed
+// in its ChildConcepts or naming it as their ParentConcept, ordered by ID
+func (t *Taxonomy) GetChildren(id string) []*models.LegalConcept {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	childIDs := make(map[string]bool)
+	if concept, ok := t.concepts[id]; ok {
+		for _, child := range concept.ChildConcepts {
+			childIDs[child] = true
+		}
+	}
+	for _, concept := range t.concepts {
+		if concept.ParentConcept == id {
+			childIDs[concept.ID] = true
+		}
+	}
+
+	children := make([]*models.LegalConcept, 0, len(childIDs))
+	for child := range childIDs {
+		if concept, ok := t.concepts[child]; ok && child != id {
+			children = append(children, concept)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].ID < children[j].ID })
+
+	return children
+}
+
 // GetStats returns statistics about the taxonomy
 func (t *Taxonomy) GetStats() map[string]int {
 	t.mu.RLock()
@@ -132,7 +198,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "const-01",
 			Name:        "Freedom of Speech",
 			Description: "The right to express opinions without government restraint",
-			Area:        models.AreaConstitutional,
+			Area:        models.AreaOfLawConstitutional,
 			Keywords:    []string{"freedom of speech", "first amendment", "expression", "free speech", "censorship"},
 			Importance:  9,
 		},
@@ -140,7 +206,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "const-02",
 			Name:        "Due Process",
 			Description: "Fair treatment through the normal judicial system",
-			Area:        models.AreaConstitutional,
+			Area:        models.AreaOfLawConstitutional,
 			Keywords:    []string{"due process", "procedural fairness", "natural justice", "fair hearing"},
 			Importance:  10,
 		},
@@ -148,7 +214,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "const-03",
 			Name:        "Equal Protection",
 			Description: "Equal treatment under the law",
-			Area:        models.AreaConstitutional,
+			Area:        models.AreaOfLawConstitutional,
 			Keywords:    []string{"equal protection", "discrimination", "equality", "disparate treatment"},
 			Importance:  9,
 		},
@@ -158,7 +224,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "crim-01",
 			Name:        "Mens Rea",
 			Description: "Criminal intent or knowledge of wrongdoing",
-			Area:        models.AreaCriminal,
+			Area:        models.AreaOfLawCriminal,
 			Keywords:    []string{"mens rea", "criminal intent", "guilty mind", "intention", "recklessness"},
 			Importance:  10,
 		},
@@ -166,7 +232,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "crim-02",
 			Name:        "Actus Reus",
 			Description: "The physical act of committing a crime",
-			Area:        models.AreaCriminal,
+			Area:        models.AreaOfLawCriminal,
 			Keywords:    []string{"actus reus", "guilty act", "criminal act", "physical element"},
 			Importance:  10,
 		},
@@ -174,7 +240,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "crim-03",
 			Name:        "Self-Defense",
 			Description: "Legal justification for using force to protect oneself",
-			Area:        models.AreaCriminal,
+			Area:        models.AreaOfLawCriminal,
 			Keywords:    []string{"self-defense", "self-defence", "defense of person", "justification"},
 			Importance:  8,
 		},
@@ -182,7 +248,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "crim-04",
 			Name:        "Reasonable Doubt",
 			Description: "Standard of proof in criminal cases",
-			Area:        models.AreaCriminal,
+			Area:        models.AreaOfLawCriminal,
 			Keywords:    []string{"reasonable doubt", "beyond reasonable doubt", "burden of proof", "standard of proof"},
 			Importance:  10,
 		},
@@ -192,7 +258,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "cont-01",
 			Name:        "Offer and Acceptance",
 			Description: "Essential elements for contract formation",
-			Area:        models.AreaContract,
+			Area:        models.AreaOfLawContract,
 			Keywords:    []string{"offer", "acceptance", "agreement", "meeting of minds", "consensus ad idem"},
 			Importance:  10,
 		},
@@ -200,7 +266,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "cont-02",
 			Name:        "Consideration",
 			Description: "Something of value exchanged between parties",
-			Area:        models.AreaContract,
+			Area:        models.AreaOfLawContract,
 			Keywords:    []string{"consideration", "quid pro quo", "valuable consideration", "bargain"},
 			Importance:  10,
 		},
@@ -208,7 +274,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "cont-03",
 			Name:        "Breach of Contract",
 			Description: "Failure to perform contractual obligations",
-			Area:        models.AreaContract,
+			Area:        models.AreaOfLawContract,
 			Keywords:    []string{"breach", "breach of contract", "material breach", "fundamental breach", "repudiation"},
 			Importance:  9,
 		},
@@ -216,7 +282,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "cont-04",
 			Name:        "Damages",
 			Description: "Monetary compensation for breach",
-			Area:        models.AreaContract,
+			Area:        models.AreaOfLawContract,
 			Keywords:    []string{"damages", "compensation", "expectation damages", "reliance damages", "restitution"},
 			Importance:  8,
 		},
@@ -226,7 +292,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "tort-01",
 			Name:        "Negligence",
 			Description: "Failure to exercise reasonable care",
-			Area:        models.AreaTort,
+			Area:        models.AreaOfLawTort,
 			Keywords:    []string{"negligence", "duty of care", "breach of duty", "reasonable person", "standard of care"},
 			Importance:  10,
 		},
@@ -234,7 +300,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "tort-02",
 			Name:        "Causation",
 			Description: "Link between conduct and harm",
-			Area:        models.AreaTort,
+			Area:        models.AreaOfLawTort,
 			Keywords:    []string{"causation", "proximate cause", "but-for test", "cause in fact", "foreseeability"},
 			Importance:  9,
 		},
@@ -242,7 +308,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "tort-03",
 			Name:        "Defamation",
 			Description: "False statement harming reputation",
-			Area:        models.AreaTort,
+			Area:        models.AreaOfLawTort,
 			Keywords:    []string{"defamation", "libel", "slander", "reputation", "false statement"},
 			Importance:  7,
 		},
@@ -252,7 +318,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "prop-01",
 			Name:        "Adverse Possession",
 			Description: "Acquiring ownership through continuous possession",
-			Area:        models.AreaProperty,
+			Area:        models.AreaOfLawProperty,
 			Keywords:    []string{"adverse possession", "squatter's rights", "prescription", "possessory title"},
 			Importance:  7,
 		},
@@ -260,7 +326,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "prop-02",
 			Name:        "Easement",
 			Description: "Right to use another's property for specific purpose",
-			Area:        models.AreaProperty,
+			Area:        models.AreaOfLawProperty,
 			Keywords:    []string{"easement", "right of way", "servitude", "profit à prendre"},
 			Importance:  6,
 		},
@@ -270,7 +336,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "fam-01",
 			Name:        "Child Custody",
 			Description: "Legal guardianship of a child",
-			Area:        models.AreaFamily,
+			Area:        models.AreaOfLawFamily,
 			Keywords:    []string{"custody", "child custody", "parental rights", "best interests of child"},
 			Importance:  8,
 		},
@@ -278,7 +344,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "fam-02",
 			Name:        "Divorce",
 			Description: "Legal dissolution of marriage",
-			Area:        models.AreaFamily,
+			Area:        models.AreaOfLawFamily,
 			Keywords:    []string{"divorce", "dissolution", "marriage breakdown", "separation"},
 			Importance:  7,
 		},
@@ -288,7 +354,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "admin-01",
 			Name:        "Judicial Review",
 			Description: "Court review of administrative decisions",
-			Area:        models.AreaAdministrative,
+			Area:        models.AreaOfLawAdministrative,
 			Keywords:    []string{"judicial review", "administrative review", "ultra vires", "unreasonableness"},
 			Importance:  8,
 		},
@@ -296,7 +362,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "admin-02",
 			Name:        "Procedural Fairness",
 			Description: "Fair process in administrative decisions",
-			Area:        models.AreaAdministrative,
+			Area:        models.AreaOfLawAdministrative,
 			Keywords:    []string{"procedural fairness", "natural justice", "right to be heard", "bias"},
 			Importance:  9,
 		},
@@ -306,7 +372,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "labor-01",
 			Name:        "Wrongful Dismissal",
 			Description: "Termination without just cause",
-			Area:        models.AreaLabor,
+			Area:        models.AreaOfLawEmployment,
 			Keywords:    []string{"wrongful dismissal", "unfair dismissal", "termination", "just cause"},
 			Importance:  7,
 		},
@@ -314,7 +380,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "labor-02",
 			Name:        "Collective Bargaining",
 			Description: "Negotiation between employer and union",
-			Area:        models.AreaLabor,
+			Area:        models.AreaOfLawEmployment,
 			Keywords:    []string{"collective bargaining", "union", "labor agreement", "collective agreement"},
 			Importance:  6,
 		},
@@ -324,7 +390,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "evid-01",
 			Name:        "Hearsay",
 			Description: "Out-of-court statement offered for truth",
-			Area:        models.AreaEvidence,
+			Area:        models.AreaOfLawEvidence,
 			Keywords:    []string{"hearsay", "hearsay rule", "out of court statement", "second-hand evidence"},
 			Importance:  8,
 		},
@@ -332,7 +398,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "evid-02",
 			Name:        "Privilege",
 			Description: "Protection from disclosure of confidential communications",
-			Area:        models.AreaEvidence,
+			Area:        models.AreaOfLawEvidence,
 			Keywords:    []string{"privilege", "attorney-client privilege", "solicitor-client privilege", "confidential"},
 			Importance:  7,
 		},
@@ -342,7 +408,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "ip-01",
 			Name:        "Copyright Infringement",
 			Description: "Unauthorized use of copyrighted work",
-			Area:        models.AreaIntellectualProperty,
+			Area:        models.AreaOfLawIntellectualProperty,
 			Keywords:    []string{"copyright", "infringement", "fair use", "fair dealing", "reproduction"},
 			Importance:  7,
 		},
@@ -350,7 +416,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "ip-02",
 			Name:        "Patent",
 			Description: "Exclusive right to invention",
-			Area:        models.AreaIntellectualProperty,
+			Area:        models.AreaOfLawIntellectualProperty,
 			Keywords:    []string{"patent", "invention", "novelty", "non-obviousness", "utility"},
 			Importance:  6,
 		},
@@ -360,7 +426,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "tax-01",
 			Name:        "Tax Evasion",
 			Description: "Illegal non-payment of taxes",
-			Area:        models.AreaTax,
+			Area:        models.AreaOfLawTax,
 			Keywords:    []string{"tax evasion", "tax fraud", "tax avoidance", "evasion"},
 			Importance:  7,
 		},
@@ -370,7 +436,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "env-01",
 			Name:        "Environmental Impact Assessment",
 			Description: "Evaluation of environmental effects",
-			Area:        models.AreaEnvironmental,
+			Area:        models.AreaOfLawEnvironmental,
 			Keywords:    []string{"environmental impact", "assessment", "environmental assessment", "EIA"},
 			Importance:  6,
 		},
@@ -380,7 +446,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "hr-01",
 			Name:        "Discrimination",
 			Description: "Unfair treatment based on protected characteristics",
-			Area:        models.AreaHumanRights,
+			Area:        models.AreaOfLawHumanRights,
 			Keywords:    []string{"discrimination", "protected grounds", "equality", "human rights violation"},
 			Importance:  9,
 		},
@@ -388,7 +454,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "hr-02",
 			Name:        "Freedom from Torture",
 			Description: "Prohibition of cruel and unusual punishment",
-			Area:        models.AreaHumanRights,
+			Area:        models.AreaOfLawHumanRights,
 			Keywords:    []string{"torture", "cruel and unusual", "inhuman treatment", "degrading treatment"},
 			Importance:  10,
 		},
@@ -398,7 +464,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "corp-01",
 			Name:        "Fiduciary Duty",
 			Description: "Obligation to act in best interest of another",
-			Area:        models.AreaCorporate,
+			Area:        models.AreaOfLawCorporate,
 			Keywords:    []string{"fiduciary duty", "duty of loyalty", "duty of care", "fiduciary"},
 			Importance:  8,
 		},
@@ -406,7 +472,7 @@ func (t *Taxonomy) initializeDefaultConcepts() {
 			ID:          "corp-02",
 			Name:        "Piercing the Corporate Veil",
 			Description: "Holding shareholders personally liable",
-			Area:        models.AreaCorporate,
+			Area:        models.AreaOfLawCorporate,
 			Keywords:    []string{"piercing the veil", "corporate veil", "alter ego", "personal liability"},
 			Importance:  7,
 		},
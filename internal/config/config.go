@@ -16,6 +16,7 @@ type Config struct {
 	Queue         QueueConfig         `mapstructure:"queue"`
 	Worker        WorkerConfig        `mapstructure:"worker"`
 	Scraper       ScraperConfig       `mapstructure:"scraper"`
+	Validation    ValidationConfig    `mapstructure:"validation"`
 	Observability ObservabilityConfig `mapstructure:"observability"`
 	Auth          AuthConfig          `mapstructure:"auth"`
 	Security      SecurityConfig      `mapstructure:"security"`
@@ -32,6 +33,55 @@ type ServerConfig struct {
 	GRPCPort        int           `mapstructure:"grpc_port"`
 	EnableGraphQL   bool          `mapstructure:"enable_graphql"`
 	EnableWebSocket bool          `mapstructure:"enable_websocket"`
+
+	// Longest a GraphQL query runs before it is cancelled with a timeout
+	// error (0 leaves only the request's own deadline)
+	GraphQLTimeout time.Duration `mapstructure:"graphql_timeout"`
+	// Deepest citationNetwork query GraphQL accepts
+	GraphQLMaxNetworkDepth int `mapstructure:"graphql_max_network_depth"`
+
+	// gRPC server reflection, for tools like grpcurl
+	GRPCReflection bool `mapstructure:"grpc_reflection"`
+	// Largest gRPC message received and sent, in bytes
+	GRPCMaxRecvMsgSize int `mapstructure:"grpc_max_recv_msg_size"`
+	GRPCMaxSendMsgSize int `mapstructure:"grpc_max_send_msg_size"`
+
+	// Time allowed for a client to send the request line and headers, and to
+	// send its next request on a keep-alive connection
+	ReadHeaderTimeout time.Duration `mapstructure:"read_header_timeout"`
+	IdleTimeout       time.Duration `mapstructure:"idle_timeout"`
+	// Path prefixes of long-poll and streaming routes exempt from write_timeout
+	StreamPaths []string `mapstructure:"stream_paths"`
+
+	// Cache-Control max-age per endpoint type (0 disables caching)
+	CacheCaseTTL      time.Duration `mapstructure:"cache_case_ttl"`
+	CacheListTTL      time.Duration `mapstructure:"cache_list_ttl"`
+	CacheStatsTTL     time.Duration `mapstructure:"cache_stats_ttl"`
+	CacheReferenceTTL time.Duration `mapstructure:"cache_reference_ttl"`
+
+	// Page size of list and search requests that set no limit, across REST,
+	// GraphQL and gRPC
+	DefaultPageSize int `mapstructure:"default_page_size"`
+
+	// Largest page a list or search request may ask for, across REST, GraphQL
+	// and gRPC (0 is unlimited)
+	MaxResultLimit  int `mapstructure:"max_result_limit"`
+	MaxResultOffset int `mapstructure:"max_result_offset"`
+
+	// Collapse duplicate search results by case ID, and by the named
+	// validation dedup hash when set
+	SearchDedup     bool   `mapstructure:"search_dedup"`
+	SearchDedupHash string `mapstructure:"search_dedup_hash"`
+
+	// Boost search results whose matching legal concepts have at least this
+	// taxonomy importance (1-10; 0 disables), scaling the score by
+	// 1 + weight * importance / 10
+	SearchConceptMinImportance int     `mapstructure:"search_concept_min_importance"`
+	SearchConceptBoost         float64 `mapstructure:"search_concept_boost"`
+
+	// Key naming of JSON responses, snake_case or camelCase; a request may
+	// ask for the other with e.g. "Accept: application/json; naming=camel"
+	JSONNaming string `mapstructure:"json_naming"`
 }
 
 // DatabaseConfig holds database configuration
@@ -46,6 +96,71 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	Mongo           MongoConfig   `mapstructure:"mongo"`
+
+	// Retry the initial connection while the database comes up, doubling the
+	// interval after each attempt
+	ConnectRetries       int           `mapstructure:"connect_retries"`
+	ConnectRetryInterval time.Duration `mapstructure:"connect_retry_interval"`
+
+	// Bound every storage call, overridden per operation such as
+	// search_cases (0 leaves calls unbounded)
+	QueryTimeout  time.Duration            `mapstructure:"query_timeout"`
+	QueryTimeouts map[string]time.Duration `mapstructure:"query_timeouts"`
+
+	// Debug: log query plans for list/search queries slower than the threshold
+	ExplainSlowQueries bool          `mapstructure:"explain_slow_queries"`
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+
+	// Recompute search term statistics from the database this often, so
+	// cases written by other processes are counted (0 disables)
+	TermStatsRefresh time.Duration `mapstructure:"term_stats_refresh"`
+
+	// Buffer case saves in memory and write them in batches
+	WriteBehind WriteBehindConfig `mapstructure:"write_behind"`
+
+	// Reject case updates that make illegal status transitions
+	StatusLifecycle StatusLifecycleConfig `mapstructure:"status_lifecycle"`
+
+	// Isolate each tenant's cases, judges and citations
+	Tenancy TenancyConfig `mapstructure:"tenancy"`
+}
+
+// TenancyConfig holds multi-tenancy configuration
+type TenancyConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Clients map[string]string `mapstructure:"clients"` // client ID -> tenant ID; other clients see shared data only
+}
+
+// StatusLifecycleConfig holds case status transition configuration
+type StatusLifecycleConfig struct {
+	Enabled     bool                `mapstructure:"enabled"`
+	Transitions map[string][]string `mapstructure:"transitions"` // status -> statuses it may move to; empty uses the built-in lifecycle
+}
+
+// WriteBehindConfig holds write-behind buffer configuration
+type WriteBehindConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	BatchSize     int           `mapstructure:"batch_size"`     // flush once this many cases are waiting
+	FlushInterval time.Duration `mapstructure:"flush_interval"` // flush at least this often
+	BufferSize    int           `mapstructure:"buffer_size"`    // saves block once this many cases are waiting
+	WriteTimeout  time.Duration `mapstructure:"write_timeout"`  // bound on each batch write
+}
+
+// MongoConfig holds MongoDB-specific client configuration. Client options
+// left unset are taken from the connection URI, then from
+// storage.DefaultMongoConfig.
+type MongoConfig struct {
+	ReadPreference         string        `mapstructure:"read_preference"` // primary, primaryPreferred, secondary, secondaryPreferred, nearest
+	ReadConcern            string        `mapstructure:"read_concern"`    // local, available, majority, linearizable, snapshot
+	WriteConcern           string        `mapstructure:"write_concern"`   // majority or number of nodes
+	Journal                *bool         `mapstructure:"journal"`
+	WriteTimeout           time.Duration `mapstructure:"write_timeout"`
+	ConnectTimeout         time.Duration `mapstructure:"connect_timeout"`
+	ServerSelectionTimeout time.Duration `mapstructure:"server_selection_timeout"`
+	SocketTimeout          time.Duration `mapstructure:"socket_timeout"`
+	TextLanguage           string        `mapstructure:"text_language"`          // default text index language, or none
+	TextLanguageOverride   string        `mapstructure:"text_language_override"` // case field naming a per-case language
 }
 
 // RedisConfig holds Redis configuration
@@ -62,6 +177,15 @@ type QueueConfig struct {
 	URL         string `mapstructure:"url"`
 	MaxRetries  int    `mapstructure:"max_retries"`
 	RetryDelay  time.Duration `mapstructure:"retry_delay"`
+	MaxReplays  int    `mapstructure:"max_replays"` // max times a failed job can be replayed
+	DedupWindow time.Duration `mapstructure:"dedup_window"` // identical jobs within this window are coalesced, 0 disables
+
+	// Backoff before a failed job is retried: immediate, fixed (retry_delay
+	// each time) or exponential (retry_delay doubling up to retry_max_delay),
+	// varied randomly by up to retry_jitter of the delay
+	RetryStrategy string        `mapstructure:"retry_strategy"`
+	RetryMaxDelay time.Duration `mapstructure:"retry_max_delay"`
+	RetryJitter   float64       `mapstructure:"retry_jitter"`
 }
 
 // WorkerConfig holds worker pool configuration
@@ -69,6 +193,45 @@ type WorkerConfig struct {
 	Count          int           `mapstructure:"count"`
 	JobTimeout     time.Duration `mapstructure:"job_timeout"`
 	ShutdownGrace  time.Duration `mapstructure:"shutdown_grace"`
+
+	// Dedicated pool for enrich jobs; 0 handles them in the main pool
+	EnrichCount int `mapstructure:"enrich_count"`
+
+	// Order enrichment steps run in (metadata, concepts, citations,
+	// treatments, appeal), as far as their prerequisites allow; steps left
+	// out follow in that default order. EnrichRequires adds prerequisites by
+	// step, e.g. {"appeal": ["treatments"]}
+	EnrichOrder    []string            `mapstructure:"enrich_order"`
+	EnrichRequires map[string][]string `mapstructure:"enrich_requires"`
+
+	// Party normalization during enrichment: title-case names written all in
+	// one case, and extra company suffixes and government keywords used to
+	// detect party types, on top of the built-in ones
+	PartyTitleCase          bool     `mapstructure:"party_title_case"`
+	PartyCompanySuffixes    []string `mapstructure:"party_company_suffixes"`
+	PartyGovernmentKeywords []string `mapstructure:"party_government_keywords"`
+
+	// Directory export jobs write their files and status to, named after the
+	// job. The API serves the files from it, so it must be shared with the
+	// API, e.g. a volume both mount
+	ExportDir string `mapstructure:"export_dir"`
+
+	// Skip scraped cases whose ID is already stored instead of re-fetching
+	// them; scrape jobs with force_refresh set still fetch everything
+	ScrapeOnlyNew bool `mapstructure:"scrape_only_new"`
+
+	// Periodic re-fetch of stale cases from precedential courts
+	RefreshEnabled        bool          `mapstructure:"refresh_enabled"`
+	RefreshInterval       time.Duration `mapstructure:"refresh_interval"`
+	RefreshMaxAge         time.Duration `mapstructure:"refresh_max_age"`
+	RefreshBatchSize      int           `mapstructure:"refresh_batch_size"`
+	RefreshBudgetFraction float64       `mapstructure:"refresh_budget_fraction"`
+
+	// Periodic saved search alerts for newly scraped cases; the lookback
+	// should exceed the interval
+	AlertsEnabled  bool          `mapstructure:"alerts_enabled"`
+	AlertsInterval time.Duration `mapstructure:"alerts_interval"`
+	AlertsLookback time.Duration `mapstructure:"alerts_lookback"`
 }
 
 // ScraperConfig holds scraping configuration
@@ -80,6 +243,106 @@ type ScraperConfig struct {
 	RespectRobotsTxt  bool          `mapstructure:"respect_robots_txt"`
 	EnableProxies     bool          `mapstructure:"enable_proxies"`
 	ConcurrentLimit   int           `mapstructure:"concurrent_limit"`
+	IDStrategy        string        `mapstructure:"id_strategy"` // source, prefixed, hash
+
+	// Share each source's rate limit across workers through Redis, falling
+	// back to a per-process limit while Redis is unreachable
+	SharedRateLimit bool `mapstructure:"shared_rate_limit"`
+
+	// Retries of failed requests a whole scrape job may spend, on top of
+	// max_retries per request (0 for no limit), and the wait before each
+	RetryBudget int           `mapstructure:"retry_budget"`
+	RetryDelay  time.Duration `mapstructure:"retry_delay"`
+
+	// Jurisdictions to register, scrape and serve (empty enables all)
+	EnabledJurisdictions []string `mapstructure:"enabled_jurisdictions"`
+
+	// Court levels (1 supreme to 5 local) whose scraped cases are stored;
+	// empty stores every level. Scrape jobs may narrow this with court_levels.
+	CourtLevels []int `mapstructure:"court_levels"`
+
+	// Full text kept from each scraped case, in bytes (0 for no cap); longer
+	// text is truncated and the case flagged in its metadata
+	MaxFullTextBytes int `mapstructure:"max_full_text_bytes"`
+
+	// Version of the extraction logic stamped on scraped cases; raise it after
+	// a parsing change to find cases scraped earlier
+	ExtractionVersion int `mapstructure:"extraction_version"`
+
+	// Cron schedules for incremental scrapes, by scraper name, e.g.
+	// {"bailii": "0 2 * * *"}; run by the worker
+	Schedules map[string]string `mapstructure:"schedules"`
+
+	// Charset assumed for pages that declare none and aren't valid UTF-8, by
+	// scraper name, e.g. {"hklii": "gbk"}; otherwise windows-1252 is assumed
+	DefaultCharsets map[string]string `mapstructure:"default_charsets"`
+
+	// HTML tags whose structure is kept in full text, e.g. ["p", "br", "ol",
+	// "li"] for paragraph breaks and numbering; empty flattens the text
+	PreservedTags []string `mapstructure:"preserved_tags"`
+
+	// YAML file overriding the CSS selectors scrapers extract cases with, by
+	// scraper name, e.g. {"bailii": {"case_name": "h1.title"}}; empty keeps
+	// the built-in selectors
+	SelectorsFile string `mapstructure:"selectors_file"`
+
+	// Regular expressions matching URLs or paths never scraped, by scraper
+	// name, e.g. {"bailii": ["/index\\.html$"]}; added to the blocklist in
+	// each source's scraping policy
+	URLBlocklist map[string][]string `mapstructure:"url_blocklist"`
+
+	// Requests to a source in flight at once when fetching case details, by
+	// scraper name, e.g. {"courtlistener": 4}; others use ConcurrentLimit
+	ConcurrentLimits map[string]int `mapstructure:"concurrent_limits"`
+
+	// Times of day each source may be scraped, by scraper name, as
+	// "HH:MM-HH:MM" ranges in the source's timezone, e.g. {"bailii":
+	// ["20:00-07:00"]}; replaces the windows in its scraping policy
+	ScrapeWindows map[string][]string `mapstructure:"scrape_windows"`
+
+	// IANA timezone of each source, by scraper name, e.g. {"bailii":
+	// "Europe/London"}; overrides its scraping policy, and UTC if neither
+	// sets one
+	SourceTimezones map[string]string `mapstructure:"source_timezones"`
+
+	// Debug: save raw HTML when extraction yields an invalid case
+	HTMLDumpEnabled   bool          `mapstructure:"html_dump_enabled"`
+	HTMLDumpDir       string        `mapstructure:"html_dump_dir"`
+	HTMLDumpMaxBytes  int           `mapstructure:"html_dump_max_bytes"`
+	HTMLDumpRetention time.Duration `mapstructure:"html_dump_retention"`
+
+	// Archive the raw JSON of every scraped case, keyed by source, case ID
+	// and scrape time, to a local directory ("dir") or an S3-compatible
+	// bucket ("s3"). Writes happen in the background; cases are dropped from
+	// the archive when more than ArchiveBufferSize are waiting
+	ArchiveEnabled    bool   `mapstructure:"archive_enabled"`
+	ArchiveDriver     string `mapstructure:"archive_driver"`
+	ArchiveDir        string `mapstructure:"archive_dir"`
+	ArchiveBufferSize int    `mapstructure:"archive_buffer_size"`
+
+	ArchiveS3Endpoint        string `mapstructure:"archive_s3_endpoint"`
+	ArchiveS3Bucket          string `mapstructure:"archive_s3_bucket"`
+	ArchiveS3Region          string `mapstructure:"archive_s3_region"`
+	ArchiveS3AccessKeyID     string `mapstructure:"archive_s3_access_key_id"`
+	ArchiveS3SecretAccessKey string `mapstructure:"archive_s3_secret_access_key"`
+	ArchiveS3Prefix          string `mapstructure:"archive_s3_prefix"`
+}
+
+// ValidationConfig holds case validation configuration
+type ValidationConfig struct {
+	// Field combinations that identify duplicate cases (empty uses the
+	// built-in hashes)
+	DedupHashes []DedupHashConfig `mapstructure:"dedup_hashes"`
+}
+
+// DedupHashConfig is one field combination that identifies duplicate cases
+type DedupHashConfig struct {
+	Name       string   `mapstructure:"name"`
+	Fields     []string `mapstructure:"fields"`
+	Normalize  bool     `mapstructure:"normalize"`   // lowercase and strip punctuation first
+	AllowEmpty bool     `mapstructure:"allow_empty"` // hash cases missing some fields
+	MinLength  int      `mapstructure:"min_length"`  // skip values no longer than this
+	MaxLength  int      `mapstructure:"max_length"`  // hash only a prefix of longer values
 }
 
 // ObservabilityConfig holds observability configuration
@@ -98,6 +361,14 @@ type AuthConfig struct {
 	JWTExpiration   time.Duration `mapstructure:"jwt_expiration"`
 	APIKeyEnabled   bool          `mapstructure:"api_key_enabled"`
 	RateLimitPerMin int           `mapstructure:"rate_limit_per_min"`
+
+	// Client addresses (CIDR ranges or single IPs) allowed and denied on the
+	// admin endpoints; an empty allow list allows any address not denied
+	AdminAllowCIDRs []string `mapstructure:"admin_allow_cidrs"`
+	AdminDenyCIDRs  []string `mapstructure:"admin_deny_cidrs"`
+	// Proxies whose X-Forwarded-For header names the client; from any other
+	// peer the header is ignored
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // SecurityConfig holds security configuration (for main.go)
@@ -159,10 +430,30 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("server.read_timeout", "30s")
 	v.SetDefault("server.write_timeout", "30s")
 	v.SetDefault("server.shutdown_timeout", "10s")
+	v.SetDefault("server.read_header_timeout", "5s")
+	v.SetDefault("server.idle_timeout", "120s")
+	v.SetDefault("server.stream_paths", []string{})
 	v.SetDefault("server.enable_grpc", false)
 	v.SetDefault("server.grpc_port", 9090)
+	v.SetDefault("server.grpc_reflection", true)
+	v.SetDefault("server.grpc_max_recv_msg_size", 16777216)
+	v.SetDefault("server.grpc_max_send_msg_size", 16777216)
 	v.SetDefault("server.enable_graphql", false)
+	v.SetDefault("server.graphql_timeout", "30s")
+	v.SetDefault("server.graphql_max_network_depth", 3)
 	v.SetDefault("server.enable_websocket", false)
+	v.SetDefault("server.cache_case_ttl", "24h")
+	v.SetDefault("server.cache_list_ttl", "5m")
+	v.SetDefault("server.cache_stats_ttl", "1m")
+	v.SetDefault("server.cache_reference_ttl", "1h")
+	v.SetDefault("server.default_page_size", 20)
+	v.SetDefault("server.max_result_limit", 1000)
+	v.SetDefault("server.max_result_offset", 10000)
+	v.SetDefault("server.search_dedup", true)
+	v.SetDefault("server.search_dedup_hash", "")
+	v.SetDefault("server.search_concept_min_importance", 0)
+	v.SetDefault("server.search_concept_boost", 0.5)
+	v.SetDefault("server.json_naming", "snake_case")
 
 	// Database defaults
 	v.SetDefault("database.driver", "sqlite")
@@ -170,6 +461,22 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_open_conns", 25)
 	v.SetDefault("database.max_idle_conns", 5)
 	v.SetDefault("database.conn_max_lifetime", "5m")
+	v.SetDefault("database.connect_retries", 5)
+	v.SetDefault("database.connect_retry_interval", "2s")
+	v.SetDefault("database.mongo.text_language", "english")
+	v.SetDefault("database.mongo.text_language_override", "language")
+	v.SetDefault("database.query_timeout", "30s")
+	v.SetDefault("database.query_timeouts", map[string]string{})
+	v.SetDefault("database.explain_slow_queries", false)
+	v.SetDefault("database.slow_query_threshold", "500ms")
+	v.SetDefault("database.term_stats_refresh", "10m")
+	v.SetDefault("database.write_behind.enabled", false)
+	v.SetDefault("database.write_behind.batch_size", 100)
+	v.SetDefault("database.write_behind.flush_interval", "1s")
+	v.SetDefault("database.write_behind.buffer_size", 1000)
+	v.SetDefault("database.write_behind.write_timeout", "30s")
+	v.SetDefault("database.status_lifecycle.enabled", false)
+	v.SetDefault("database.tenancy.enabled", false)
 
 	// Redis defaults
 	v.SetDefault("redis.host", "localhost")
@@ -180,20 +487,69 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("queue.driver", "memory")
 	v.SetDefault("queue.max_retries", 3)
 	v.SetDefault("queue.retry_delay", "5s")
+	v.SetDefault("queue.max_replays", 3)
+	v.SetDefault("queue.dedup_window", "10m")
+	v.SetDefault("queue.retry_strategy", "exponential")
+	v.SetDefault("queue.retry_max_delay", "5m")
+	v.SetDefault("queue.retry_jitter", 0.2)
 
 	// Worker defaults
 	v.SetDefault("worker.count", 4)
 	v.SetDefault("worker.job_timeout", "5m")
 	v.SetDefault("worker.shutdown_grace", "30s")
+	v.SetDefault("worker.enrich_count", 0)
+	v.SetDefault("worker.enrich_order", []string{})
+	v.SetDefault("worker.enrich_requires", map[string][]string{})
+	v.SetDefault("worker.party_title_case", true)
+	v.SetDefault("worker.party_company_suffixes", []string{})
+	v.SetDefault("worker.party_government_keywords", []string{})
+	v.SetDefault("worker.export_dir", "./exports")
+	v.SetDefault("worker.scrape_only_new", false)
+	v.SetDefault("worker.refresh_enabled", false)
+	v.SetDefault("worker.refresh_interval", "6h")
+	v.SetDefault("worker.refresh_max_age", "720h")
+	v.SetDefault("worker.refresh_batch_size", 100)
+	v.SetDefault("worker.refresh_budget_fraction", 0.25)
+	v.SetDefault("worker.alerts_enabled", false)
+	v.SetDefault("worker.alerts_interval", "1h")
+	v.SetDefault("worker.alerts_lookback", "24h")
 
 	// Scraper defaults
 	v.SetDefault("scraper.user_agent", "Kite/4.0 (Legal Research Bot; +https://github.com/gongahkia/kite)")
 	v.SetDefault("scraper.request_timeout", "30s")
 	v.SetDefault("scraper.max_retries", 3)
+	v.SetDefault("scraper.retry_budget", 20)
+	v.SetDefault("scraper.retry_delay", "2s")
 	v.SetDefault("scraper.rate_limit_per_min", 20)
 	v.SetDefault("scraper.respect_robots_txt", true)
 	v.SetDefault("scraper.enable_proxies", false)
 	v.SetDefault("scraper.concurrent_limit", 10)
+	v.SetDefault("scraper.id_strategy", "source")
+	v.SetDefault("scraper.shared_rate_limit", false)
+	v.SetDefault("scraper.enabled_jurisdictions", []string{})
+	v.SetDefault("scraper.court_levels", []int{})
+	v.SetDefault("scraper.max_full_text_bytes", 5242880)
+	v.SetDefault("scraper.extraction_version", 1)
+	v.SetDefault("scraper.schedules", map[string]string{})
+	v.SetDefault("scraper.default_charsets", map[string]string{})
+	v.SetDefault("scraper.preserved_tags", []string{})
+	v.SetDefault("scraper.selectors_file", "")
+	v.SetDefault("scraper.url_blocklist", map[string][]string{})
+	v.SetDefault("scraper.concurrent_limits", map[string]int{})
+	v.SetDefault("scraper.scrape_windows", map[string][]string{})
+	v.SetDefault("scraper.source_timezones", map[string]string{})
+	v.SetDefault("scraper.html_dump_enabled", false)
+	v.SetDefault("scraper.html_dump_dir", "./debug/html")
+	v.SetDefault("scraper.html_dump_max_bytes", 1048576)
+	v.SetDefault("scraper.html_dump_retention", "72h")
+	v.SetDefault("scraper.archive_enabled", false)
+	v.SetDefault("scraper.archive_driver", "dir")
+	v.SetDefault("scraper.archive_dir", "./data/archive")
+	v.SetDefault("scraper.archive_buffer_size", 1000)
+	v.SetDefault("scraper.archive_s3_region", "us-east-1")
+
+	// Validation defaults
+	v.SetDefault("validation.dedup_hashes", []interface{}{})
 
 	// Observability defaults
 	v.SetDefault("observability.log_level", "info")
@@ -206,6 +562,9 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("auth.jwt_expiration", "24h")
 	v.SetDefault("auth.api_key_enabled", true)
 	v.SetDefault("auth.rate_limit_per_min", 100)
+	v.SetDefault("auth.admin_allow_cidrs", []string{})
+	v.SetDefault("auth.admin_deny_cidrs", []string{})
+	v.SetDefault("auth.trusted_proxies", []string{})
 
 	// Security defaults
 	v.SetDefault("security.jwt_secret", "change-this-secret-in-production")
@@ -218,16 +577,61 @@ func validate(cfg *Config) error {
 	if cfg.Server.Port < 1 || cfg.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", cfg.Server.Port)
 	}
+	if cfg.Server.MaxResultLimit < 0 || cfg.Server.MaxResultOffset < 0 {
+		return fmt.Errorf("server result window bounds must be non-negative")
+	}
+	if cfg.Server.DefaultPageSize < 1 {
+		return fmt.Errorf("server default page size must be at least 1")
+	}
+	if cfg.Server.MaxResultLimit > 0 && cfg.Server.DefaultPageSize > cfg.Server.MaxResultLimit {
+		return fmt.Errorf("server default page size %d exceeds max result limit %d", cfg.Server.DefaultPageSize, cfg.Server.MaxResultLimit)
+	}
+
+	// Validate database config
+	if cfg.Database.ConnectRetries < 1 {
+		return fmt.Errorf("database connect retries must be at least 1")
+	}
+	if wb := cfg.Database.WriteBehind; wb.Enabled {
+		if wb.BatchSize < 1 {
+			return fmt.Errorf("write-behind batch size must be at least 1")
+		}
+		if wb.BufferSize < wb.BatchSize {
+			return fmt.Errorf("write-behind buffer size must be at least the batch size")
+		}
+		if wb.FlushInterval <= 0 {
+			return fmt.Errorf("write-behind flush interval must be positive")
+		}
+	}
 
 	// Validate worker config
 	if cfg.Worker.Count < 1 {
 		return fmt.Errorf("worker count must be at least 1")
 	}
+	if cfg.Worker.EnrichCount < 0 {
+		return fmt.Errorf("worker enrich count cannot be negative")
+	}
 
 	// Validate scraper config
 	if cfg.Scraper.RateLimitPerMin < 1 {
 		return fmt.Errorf("scraper rate limit must be at least 1")
 	}
+	if cfg.Scraper.MaxFullTextBytes < 0 {
+		return fmt.Errorf("scraper max full text bytes cannot be negative")
+	}
+	if cfg.Scraper.ExtractionVersion < 1 {
+		return fmt.Errorf("scraper extraction version must be at least 1")
+	}
+	validIDStrategies := map[string]bool{
+		"source": true, "prefixed": true, "hash": true,
+	}
+	if !validIDStrategies[cfg.Scraper.IDStrategy] {
+		return fmt.Errorf("invalid scraper id strategy: %s", cfg.Scraper.IDStrategy)
+	}
+	for _, level := range cfg.Scraper.CourtLevels {
+		if level < 1 || level > 5 {
+			return fmt.Errorf("invalid scraper court level: %d", level)
+		}
+	}
 
 	// Validate log level
 	validLogLevels := map[string]bool{
@@ -39,6 +39,9 @@ const (
 	EventJobQueued     EventType = "job.queued"
 	EventJobCompleted  EventType = "job.completed"
 	EventJobFailed     EventType = "job.failed"
+
+	// Alert events
+	EventSearchAlert EventType = "search.alert"
 )
 
 // Event represents a system event
@@ -188,6 +191,27 @@ func JobFailedEvent(jobID string, err error) *Event {
 	})
 }
 
+// SearchAlertEvent creates a saved search alert event for newly scraped
+// cases matching the search
+func SearchAlertEvent(search *models.SavedSearch, cases []*models.Case) *Event {
+	matches := make([]map[string]interface{}, 0, len(cases))
+	for _, c := range cases {
+		matches = append(matches, map[string]interface{}{
+			"case_id":      c.ID,
+			"case_name":    c.CaseName,
+			"court":        c.Court,
+			"jurisdiction": c.Jurisdiction,
+		})
+	}
+
+	return NewEvent(EventSearchAlert, "alerts", map[string]interface{}{
+		"saved_search_id": search.ID,
+		"name":            search.Name,
+		"query":           search.Query,
+		"cases":           matches,
+	})
+}
+
 // generateEventID generates a unique event ID
 func generateEventID() string {
 	return time.Now().Format("20060102150405.000000000")
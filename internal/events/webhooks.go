@@ -5,8 +5,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -19,6 +23,7 @@ type Webhook struct {
 	MaxRetries  int         `json:"max_retries"`
 	Timeout     time.Duration `json:"timeout"`
 	Enabled     bool        `json:"enabled"`
+	PublicOnly bool          `json:"public_only,omitempty"` // refuse to deliver to loopback, link-local and private addresses
 	CreatedAt   time.Time   `json:"created_at"`
 }
 
@@ -192,6 +197,15 @@ func (wm *WebhookManager) handleEvent(ctx context.Context, event *Event) error {
 
 // deliverWebhook delivers an event to a webhook
 func (wm *WebhookManager) deliverWebhook(ctx context.Context, webhook *Webhook, event *Event) {
+	delivery := wm.Deliver(ctx, webhook, event)
+
+	// Log delivery (could be stored in database)
+	_ = delivery
+}
+
+// Deliver sends an event to a webhook whether or not it is registered,
+// retrying as for subscribed events, and returns the last attempt
+func (wm *WebhookManager) Deliver(ctx context.Context, webhook *Webhook, event *Event) *WebhookDelivery {
 	delivery := &WebhookDelivery{
 		ID:        generateEventID(),
 		WebhookID: webhook.ID,
@@ -222,8 +236,7 @@ func (wm *WebhookManager) deliverWebhook(ctx context.Context, webhook *Webhook,
 		}
 	}
 
-	// Log delivery (could be stored in database)
-	_ = delivery
+	return delivery
 }
 
 // sendWebhook sends a single webhook request
@@ -256,6 +269,9 @@ func (wm *WebhookManager) sendWebhook(ctx context.Context, webhook *Webhook, eve
 	client := &http.Client{
 		Timeout: webhook.Timeout,
 	}
+	if webhook.PublicOnly {
+		client.Transport = publicOnlyTransport
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -283,3 +299,50 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// publicOnlyTransport dials only public addresses. The check runs on the
+// address actually dialed, so a name that resolves to a private address
+// after its URL was accepted is still refused.
+var publicOnlyTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return fmt.Errorf("webhook address %s is not public", host)
+			}
+			return nil
+		},
+	}).DialContext,
+	TLSHandshakeTimeout: 10 * time.Second,
+}
+
+// publicIP reports whether ip is routable on the public internet, rather
+// than loopback, link-local, private or unspecified
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() && !ip.IsUnspecified()
+}
+
+// CheckPublicURL checks that a webhook URL is http or https and doesn't name
+// a loopback, link-local or private host. Names are checked as written;
+// deliveries with PublicOnly set check the addresses they resolve to.
+func CheckPublicURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("webhook URL must be http or https")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook host %s is not public", host)
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return fmt.Errorf("webhook host %s is not public", host)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package export
 
 import (
+	"compress/gzip"
 	"encoding/csv"
 	"encoding/json"
 	"encoding/xml"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gongahkia/kite/internal/compliance"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -23,24 +25,102 @@ const (
 	FormatBibTeX    ExportFormat = "bibtex"
 	FormatMarkdown  ExportFormat = "markdown"
 	FormatPlainText ExportFormat = "text"
+	FormatParquet   ExportFormat = "parquet"
 )
 
 // Exporter handles exporting cases in different formats
 type Exporter struct {
-	format ExportFormat
-	writer io.Writer
+	format        ExportFormat
+	writer        io.Writer
+	citations     *compliance.AttributionHandler
+	citationStyle compliance.CitationStyle
+	options       *ExportOptions
+	gzipWriter    *gzip.Writer
 }
 
-// NewExporter creates a new exporter
+// NewExporter creates a new exporter with DefaultExportOptions. Citations
+// follow each case's jurisdiction unless a style is set with SetCitationStyle.
 func NewExporter(format ExportFormat, writer io.Writer) *Exporter {
-	return &Exporter{
-		format: format,
-		writer: writer,
+	return newExporter(format, writer, DefaultExportOptions())
+}
+
+// NewExporterWithOptions creates a new exporter with options, or
+// DefaultExportOptions if options is nil. Options that fail Validate are
+// rejected, so a mistyped field is never exported unredacted. When
+// options.Compress is set the output is gzipped, and Close must be called to
+// finish it.
+func NewExporterWithOptions(format ExportFormat, writer io.Writer, options *ExportOptions) (*Exporter, error) {
+	if options == nil {
+		options = DefaultExportOptions()
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	return newExporter(format, writer, options), nil
+}
+
+// newExporter creates an exporter with options that have been validated
+func newExporter(format ExportFormat, writer io.Writer, options *ExportOptions) *Exporter {
+	e := &Exporter{
+		format:        format,
+		writer:        writer,
+		citations:     compliance.NewAttributionHandler(compliance.NewPolicyManager()),
+		citationStyle: compliance.CitationStyleAuto,
+		options:       options,
+	}
+	if options.Compress {
+		e.gzipWriter = gzip.NewWriter(writer)
+		e.writer = e.gzipWriter
+	}
+	return e
+}
+
+// SetOptions replaces the exporter's options, keeping the current ones if
+// the new ones fail Validate. Compression is fixed when the exporter is
+// created and is not changed.
+func (e *Exporter) SetOptions(options *ExportOptions) error {
+	if options == nil {
+		return nil
+	}
+	if err := options.Validate(); err != nil {
+		return err
 	}
+	e.options = options
+	return nil
+}
+
+// Close finishes compressed output. It does nothing for uncompressed output
+// and does not close the underlying writer.
+func (e *Exporter) Close() error {
+	if e.gzipWriter != nil {
+		return e.gzipWriter.Close()
+	}
+	return nil
+}
+
+// SetCitationStyle overrides the citation style for every exported case
+func (e *Exporter) SetCitationStyle(style compliance.CitationStyle) {
+	e.citationStyle = style
+}
+
+// citation formats a case citation in the exporter's style
+func (e *Exporter) citation(c *models.Case) string {
+	return e.citations.GenerateCitation(c, e.citationStyle)
 }
 
-// Export exports a slice of cases
+// Export exports a slice of cases, with the exporter's exclusions and
+// redactions applied
 func (e *Exporter) Export(cases []*models.Case) error {
+	switch e.format {
+	case FormatJSON, FormatJSONLines:
+	default:
+		prepared := make([]*models.Case, len(cases))
+		for i, c := range cases {
+			prepared[i] = e.options.Apply(c)
+		}
+		cases = prepared
+	}
+
 	switch e.format {
 	case FormatJSON:
 		return e.exportJSON(cases)
@@ -56,46 +136,120 @@ func (e *Exporter) Export(cases []*models.Case) error {
 		return e.exportMarkdown(cases)
 	case FormatPlainText:
 		return e.exportPlainText(cases)
+	case FormatParquet:
+		return e.exportParquet(cases)
 	default:
 		return fmt.Errorf("unsupported export format: %s", e.format)
 	}
 }
 
-// exportJSON exports cases as pretty-printed JSON array
+// exportJSON exports cases as a JSON array, indented unless Pretty is off
 func (e *Exporter) exportJSON(cases []*models.Case) error {
+	values := make([]interface{}, len(cases))
+	for i, c := range cases {
+		value, err := e.options.jsonValue(c)
+		if err != nil {
+			return err
+		}
+		values[i] = value
+	}
+
 	encoder := json.NewEncoder(e.writer)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(cases)
+	if e.options.Pretty {
+		encoder.SetIndent("", "  ")
+	}
+	return encoder.Encode(values)
 }
 
 // exportJSONLines exports cases as newline-delimited JSON (one case per line)
 func (e *Exporter) exportJSONLines(cases []*models.Case) error {
 	encoder := json.NewEncoder(e.writer)
 	for _, c := range cases {
-		if err := encoder.Encode(c); err != nil {
+		value, err := e.options.jsonValue(c)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(value); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// exportCSV exports cases as CSV
+// csvColumn is a CSV export column and the case field it holds
+type csvColumn struct {
+	header string
+	field  string                      // JSON name, for exclusion
+	value  func(c *models.Case) string // nil for date columns
+}
+
+var csvColumns = []csvColumn{
+	{"ID", "id", func(c *models.Case) string { return c.ID }},
+	{"CaseName", "case_name", func(c *models.Case) string { return c.CaseName }},
+	{"CaseNumber", "case_number", func(c *models.Case) string { return c.CaseNumber }},
+	{"Court", "court", func(c *models.Case) string { return c.Court }},
+	{"Jurisdiction", "jurisdiction", func(c *models.Case) string { return c.Jurisdiction }},
+	{"DecisionDate", "decision_date", nil},
+	{"URL", "url", func(c *models.Case) string { return c.URL }},
+	{"Judges", "judges", func(c *models.Case) string { return strings.Join(c.Judges, "; ") }},
+	{"Summary", "summary", func(c *models.Case) string { return c.Summary }},
+	{"SourceDatabase", "source_database", func(c *models.Case) string { return c.SourceDatabase }},
+}
+
+// csvDateColumns hold dates, written in the export's date layout
+var csvDateColumns = map[string]func(c *models.Case) *time.Time{
+	"decision_date": func(c *models.Case) *time.Time { return c.DecisionDate },
+}
+
+// csvColumns returns the columns to export: those named in Fields, in that
+// order, or every column, less the columns of excluded fields
+func (o *ExportOptions) csvColumns() ([]csvColumn, error) {
+	selected := csvColumns
+	if len(o.Fields) > 0 {
+		selected = make([]csvColumn, 0, len(o.Fields))
+		for _, field := range o.Fields {
+			col, ok := csvColumnFor(field)
+			if !ok {
+				return nil, fmt.Errorf("unknown CSV field: %s", field)
+			}
+			selected = append(selected, col)
+		}
+	}
+
+	columns := make([]csvColumn, 0, len(selected))
+	for _, col := range selected {
+		if !o.excluded(col.field) {
+			columns = append(columns, col)
+		}
+	}
+	return columns, nil
+}
+
+// csvColumnFor returns the column holding a field
+func csvColumnFor(field string) (csvColumn, bool) {
+	for _, col := range csvColumns {
+		if col.field == field {
+			return col, true
+		}
+	}
+	return csvColumn{}, false
+}
+
+// exportCSV exports cases as CSV, with the selected columns less those of
+// excluded fields
 func (e *Exporter) exportCSV(cases []*models.Case) error {
+	columns, err := e.options.csvColumns()
+	if err != nil {
+		return err
+	}
+
 	writer := csv.NewWriter(e.writer)
 	defer writer.Flush()
 
 	// Write header
-	header := []string{
-		"ID",
-		"CaseName",
-		"CaseNumber",
-		"Court",
-		"Jurisdiction",
-		"DecisionDate",
-		"URL",
-		"Judges",
-		"Summary",
-		"SourceDatabase",
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.header
 	}
 	if err := writer.Write(header); err != nil {
 		return err
@@ -103,24 +257,7 @@ func (e *Exporter) exportCSV(cases []*models.Case) error {
 
 	// Write rows
 	for _, c := range cases {
-		var decisionDate string
-		if c.DecisionDate != nil {
-			decisionDate = c.DecisionDate.Format("2006-01-02")
-		}
-
-		row := []string{
-			c.ID,
-			c.CaseName,
-			c.CaseNumber,
-			c.Court,
-			c.Jurisdiction,
-			decisionDate,
-			c.URL,
-			strings.Join(c.Judges, "; "),
-			c.Summary,
-			c.SourceDatabase,
-		}
-		if err := writer.Write(row); err != nil {
+		if err := writer.Write(csvRow(columns, c, e.options.DateFormat)); err != nil {
 			return err
 		}
 	}
@@ -128,6 +265,19 @@ func (e *Exporter) exportCSV(cases []*models.Case) error {
 	return nil
 }
 
+// csvRow returns the values of a case's CSV columns
+func csvRow(columns []csvColumn, c *models.Case, dateFormat string) []string {
+	row := make([]string, len(columns))
+	for i, col := range columns {
+		if date, ok := csvDateColumns[col.field]; ok {
+			row[i] = formatDate(date(c), dateFormat, "2006-01-02")
+		} else {
+			row[i] = col.value(c)
+		}
+	}
+	return row
+}
+
 // exportXML exports cases as XML
 func (e *Exporter) exportXML(cases []*models.Case) error {
 	type CasesWrapper struct {
@@ -137,7 +287,9 @@ func (e *Exporter) exportXML(cases []*models.Case) error {
 
 	wrapper := CasesWrapper{Cases: cases}
 	encoder := xml.NewEncoder(e.writer)
-	encoder.Indent("", "  ")
+	if e.options.Pretty {
+		encoder.Indent("", "  ")
+	}
 
 	// Write XML header
 	if _, err := e.writer.Write([]byte(xml.Header)); err != nil {
@@ -200,7 +352,7 @@ func formatBibTeXEntry(c *models.Case) string {
 // exportMarkdown exports cases as Markdown
 func (e *Exporter) exportMarkdown(cases []*models.Case) error {
 	for i, c := range cases {
-		md := formatMarkdown(c)
+		md := formatMarkdown(c, e.citation(c), e.options.DateFormat)
 		if _, err := e.writer.Write([]byte(md)); err != nil {
 			return err
 		}
@@ -213,8 +365,9 @@ func (e *Exporter) exportMarkdown(cases []*models.Case) error {
 	return nil
 }
 
-// formatMarkdown formats a single case as Markdown
-func formatMarkdown(c *models.Case) string {
+// formatMarkdown formats a single case as Markdown, with dates in dateLayout
+// if set
+func formatMarkdown(c *models.Case, citation, dateLayout string) string {
 	var sb strings.Builder
 
 	// Title
@@ -224,8 +377,8 @@ func formatMarkdown(c *models.Case) string {
 	sb.WriteString("| Field | Value |\n")
 	sb.WriteString("|-------|-------|\n")
 
-	if c.CaseNumber != "" {
-		sb.WriteString(fmt.Sprintf("| **Citation** | %s |\n", c.CaseNumber))
+	if citation != "" {
+		sb.WriteString(fmt.Sprintf("| **Citation** | %s |\n", citation))
 	}
 	if c.Court != "" {
 		sb.WriteString(fmt.Sprintf("| **Court** | %s |\n", c.Court))
@@ -234,7 +387,7 @@ func formatMarkdown(c *models.Case) string {
 		sb.WriteString(fmt.Sprintf("| **Jurisdiction** | %s |\n", c.Jurisdiction))
 	}
 	if c.DecisionDate != nil {
-		sb.WriteString(fmt.Sprintf("| **Date** | %s |\n", c.DecisionDate.Format("January 2, 2006")))
+		sb.WriteString(fmt.Sprintf("| **Date** | %s |\n", formatDate(c.DecisionDate, dateLayout, "January 2, 2006")))
 	}
 	if len(c.Judges) > 0 {
 		sb.WriteString(fmt.Sprintf("| **Judges** | %s |\n", strings.Join(c.Judges, ", ")))
@@ -284,7 +437,7 @@ func formatMarkdown(c *models.Case) string {
 // exportPlainText exports cases as plain text
 func (e *Exporter) exportPlainText(cases []*models.Case) error {
 	for i, c := range cases {
-		text := formatPlainText(c)
+		text := formatPlainText(c, e.citation(c), e.options.DateFormat)
 		if _, err := e.writer.Write([]byte(text)); err != nil {
 			return err
 		}
@@ -297,15 +450,16 @@ func (e *Exporter) exportPlainText(cases []*models.Case) error {
 	return nil
 }
 
-// formatPlainText formats a single case as plain text
-func formatPlainText(c *models.Case) string {
+// formatPlainText formats a single case as plain text, with dates in
+// dateLayout if set
+func formatPlainText(c *models.Case, citation, dateLayout string) string {
 	var sb strings.Builder
 
 	sb.WriteString(c.CaseName + "\n")
 	sb.WriteString(strings.Repeat("-", len(c.CaseName)) + "\n\n")
 
-	if c.CaseNumber != "" {
-		sb.WriteString(fmt.Sprintf("Citation: %s\n", c.CaseNumber))
+	if citation != "" {
+		sb.WriteString(fmt.Sprintf("Citation: %s\n", citation))
 	}
 	if c.Court != "" {
 		sb.WriteString(fmt.Sprintf("Court: %s\n", c.Court))
@@ -314,7 +468,7 @@ func formatPlainText(c *models.Case) string {
 		sb.WriteString(fmt.Sprintf("Jurisdiction: %s\n", c.Jurisdiction))
 	}
 	if c.DecisionDate != nil {
-		sb.WriteString(fmt.Sprintf("Date: %s\n", c.DecisionDate.Format("January 2, 2006")))
+		sb.WriteString(fmt.Sprintf("Date: %s\n", formatDate(c.DecisionDate, dateLayout, "January 2, 2006")))
 	}
 	if len(c.Judges) > 0 {
 		sb.WriteString(fmt.Sprintf("Judges: %s\n", strings.Join(c.Judges, ", ")))
@@ -341,14 +495,31 @@ func formatPlainText(c *models.Case) string {
 	return sb.String()
 }
 
+// formatDate formats t in layout, or fallback when layout is empty, and
+// returns "" for a missing date
+func formatDate(t *time.Time, layout, fallback string) string {
+	if t == nil {
+		return ""
+	}
+	if layout == "" {
+		layout = fallback
+	}
+	return t.Format(layout)
+}
+
 // ExportOptions holds options for export operations
 type ExportOptions struct {
 	Format      ExportFormat `json:"format"`
-	Fields      []string     `json:"fields,omitempty"`      // For CSV: custom fields
+	Fields      []string     `json:"fields,omitempty"`      // CSV columns and JSON fields to keep, by JSON name
 	Compress    bool         `json:"compress"`              // Whether to gzip compress
-	Pretty      bool         `json:"pretty"`                // For JSON: pretty print
+	Pretty      bool         `json:"pretty"`                // For JSON and XML: indent output
 	IncludeFullText bool     `json:"include_full_text"`    // Whether to include full case text
-	DateFormat  string       `json:"date_format,omitempty"` // Custom date format
+	DateFormat  string       `json:"date_format,omitempty"` // Date layout for CSV, Markdown and text; empty for each format's own
+
+	// Fields left out of every exported case, by JSON name, and masks applied
+	// to the cases a rule covers
+	ExcludeFields []string        `json:"exclude_fields,omitempty"`
+	Redactions    []RedactionRule `json:"redactions,omitempty"`
 }
 
 // DefaultExportOptions returns default export options
@@ -358,6 +529,5 @@ func DefaultExportOptions() *ExportOptions {
 		Compress:        false,
 		Pretty:          true,
 		IncludeFullText: true,
-		DateFormat:      time.RFC3339,
 	}
 }
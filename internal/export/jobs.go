@@ -0,0 +1,205 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// exportPageSize is how many cases an export reads from storage at a time
+const exportPageSize = 500
+
+// formatExtensions are the file extensions of export formats
+var formatExtensions = map[ExportFormat]string{
+	FormatJSON:      "json",
+	FormatJSONLines: "jsonl",
+	FormatCSV:       "csv",
+	FormatXML:       "xml",
+	FormatBibTeX:    "bib",
+	FormatMarkdown:  "md",
+	FormatPlainText: "txt",
+	FormatParquet:   "parquet",
+}
+
+// Extension returns the file extension of an export format, or "" if the
+// format is unsupported
+func (f ExportFormat) Extension() string {
+	return formatExtensions[f]
+}
+
+// CasesMatching returns the cases matching a search query and filter, with
+// their full text; an empty query lists every case the filter matches. It
+// returns at most limit cases, or every match if limit is 0.
+func CasesMatching(ctx context.Context, store storage.Storage, query string, filter storage.CaseFilter, limit int) ([]*models.Case, error) {
+	var cases []*models.Case
+
+	filter.Limit = limit
+	err := StreamCasesMatching(ctx, store, query, filter, func(c *models.Case) error {
+		cases = append(cases, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cases, nil
+}
+
+// StreamCasesMatching calls fn with each case matching a search query and
+// filter, with its full text, reading them from storage a page at a time; an
+// empty query streams every case the filter matches with storage.StreamCases.
+// The filter's limit caps how many cases are streamed, every match if it is 0.
+func StreamCasesMatching(ctx context.Context, store storage.Storage, query string, filter storage.CaseFilter, fn func(*models.Case) error) error {
+	filter.IncludeFullText = true
+	if query == "" {
+		return storage.StreamCases(ctx, store, filter, fn)
+	}
+
+	limit, sent := filter.Limit, 0
+	for {
+		page := filter
+		page.Offset = filter.Offset + sent
+		page.Limit = exportPageSize
+		if limit > 0 && limit-sent < page.Limit {
+			page.Limit = limit - sent
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, err := store.SearchCases(ctx, storage.SearchQuery{
+			Query:   query,
+			Filters: page,
+			Limit:   page.Limit,
+			Offset:  page.Offset,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find cases to export: %w", err)
+		}
+		for _, c := range batch {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+
+		sent += len(batch)
+		if len(batch) < page.Limit || (limit > 0 && sent >= limit) {
+			return nil
+		}
+	}
+}
+
+// Job is an export job: the cases matching a search query and filter,
+// written in a format with options. The API reads through storage scoped to
+// the caller's tenant and the enabled jurisdictions, but a filter's scopes are
+// not serialized and a worker's storage has neither, so the job carries them
+// and the worker scopes its reads with Scope.
+type Job struct {
+	Query   string
+	Filter  storage.CaseFilter
+	Format  ExportFormat
+	Options *ExportOptions
+
+	// TenantID is the tenant the job reads as, nil if unscoped; Jurisdictions
+	// are those it may read, every one if empty
+	TenantID      *string
+	Jurisdictions []string
+}
+
+// NewJob returns an export job reading as the tenant ctx is scoped to, with
+// the jurisdictions store serves (see storage.EnabledJurisdictions)
+func NewJob(ctx context.Context, store storage.Storage, query string, filter storage.CaseFilter, format ExportFormat, options *ExportOptions) Job {
+	job := Job{
+		Query:         query,
+		Filter:        filter,
+		Format:        format,
+		Options:       options,
+		Jurisdictions: storage.EnabledJurisdictions(store),
+	}
+	if tenantID, ok := storage.TenantFromContext(ctx); ok {
+		job.TenantID = &tenantID
+	}
+	return job
+}
+
+// Payload returns the job as the payload of a queued export job
+func (j Job) Payload() map[string]interface{} {
+	payload := map[string]interface{}{
+		"query":  j.Query,
+		"filter": j.Filter,
+		"format": string(j.Format),
+	}
+	if j.Options != nil {
+		payload["options"] = j.Options
+	}
+	if j.TenantID != nil {
+		payload["tenant_id"] = *j.TenantID
+	}
+	if len(j.Jurisdictions) > 0 {
+		payload["jurisdictions"] = j.Jurisdictions
+	}
+	return payload
+}
+
+// Scope returns ctx and store scoped to the job's tenant and jurisdictions,
+// as the API that created the job was
+func (j Job) Scope(ctx context.Context, store storage.Storage) (context.Context, storage.Storage) {
+	if j.TenantID != nil {
+		ctx = storage.WithTenant(ctx, *j.TenantID)
+		store = storage.NewTenantStorage(store)
+	}
+	if len(j.Jurisdictions) > 0 {
+		store = storage.NewJurisdictionStorage(store, j.Jurisdictions)
+	}
+	return ctx, store
+}
+
+// ParseJob reads an export job from its payload, as built by Job.Payload or
+// decoded from a queue
+func ParseJob(payload map[string]interface{}) (Job, error) {
+	var job Job
+
+	job.Query, _ = payload["query"].(string)
+	name, _ := payload["format"].(string)
+	job.Format = ExportFormat(name)
+	if job.Format.Extension() == "" {
+		return job, fmt.Errorf("unsupported export format: %s", name)
+	}
+	if tenantID, ok := payload["tenant_id"].(string); ok {
+		job.TenantID = &tenantID
+	}
+
+	// A queue may have decoded the rest into maps and slices; convert them back
+	if err := decodePayload(payload["filter"], &job.Filter); err != nil {
+		return job, fmt.Errorf("invalid export filter: %w", err)
+	}
+	if err := decodePayload(payload["jurisdictions"], &job.Jurisdictions); err != nil {
+		return job, fmt.Errorf("invalid export jurisdictions: %w", err)
+	}
+	if raw, ok := payload["options"]; ok && raw != nil {
+		job.Options = DefaultExportOptions()
+		if err := decodePayload(raw, job.Options); err != nil {
+			return job, fmt.Errorf("invalid export options: %w", err)
+		}
+		if err := job.Options.Validate(); err != nil {
+			return job, err
+		}
+	}
+
+	return job, nil
+}
+
+// decodePayload converts a payload value to v through JSON; a missing value
+// leaves v as it is
+func decodePayload(raw interface{}, v interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,181 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+const (
+	// parquetRowGroupSize bounds the rows buffered in memory before a row
+	// group is flushed to the output
+	parquetRowGroupSize = 16 * 1024 * 1024
+
+	// parquetParallelism is the number of goroutines marshalling each row group
+	parquetParallelism = 4
+)
+
+// ParquetCase is the Parquet schema for exported cases: scalar case fields as
+// columns, with list fields as Parquet lists
+type ParquetCase struct {
+	ID             string   `parquet:"name=id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CaseNumber     string   `parquet:"name=case_number, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CaseName       string   `parquet:"name=case_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	DecisionDate   *int32   `parquet:"name=decision_date, type=INT32, convertedtype=DATE, repetitiontype=OPTIONAL"`
+	Court          string   `parquet:"name=court, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CourtID        string   `parquet:"name=court_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CourtLevel     int32    `parquet:"name=court_level, type=INT32"`
+	CourtType      string   `parquet:"name=court_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Jurisdiction   string   `parquet:"name=jurisdiction, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Judges         []string `parquet:"name=judges, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Summary        string   `parquet:"name=summary, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Holding        string   `parquet:"name=holding, type=BYTE_ARRAY, convertedtype=UTF8"`
+	FullText       string   `parquet:"name=full_text, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Language       string   `parquet:"name=language, type=BYTE_ARRAY, convertedtype=UTF8"`
+	LegalConcepts  []string `parquet:"name=legal_concepts, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	AreasOfLaw     []string `parquet:"name=areas_of_law, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Keywords       []string `parquet:"name=keywords, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	CitedBy        []string `parquet:"name=cited_by, type=LIST, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Status         string   `parquet:"name=status, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Outcome        string   `parquet:"name=outcome, type=BYTE_ARRAY, convertedtype=UTF8"`
+	URL            string   `parquet:"name=url, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SourceDatabase string   `parquet:"name=source_database, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScrapedAt      *int64   `parquet:"name=scraped_at, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	LastUpdated    *int64   `parquet:"name=last_updated, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Version        int32    `parquet:"name=version, type=INT32"`
+	QualityScore   float64  `parquet:"name=quality_score, type=DOUBLE"`
+	ECLI           string   `parquet:"name=ecli, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Docket         string   `parquet:"name=docket, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+// NewParquetCase converts a case to a Parquet row
+func NewParquetCase(c *models.Case, includeFullText bool) *ParquetCase {
+	row := &ParquetCase{
+		ID:             c.ID,
+		CaseNumber:     c.CaseNumber,
+		CaseName:       c.CaseName,
+		Court:          c.Court,
+		CourtID:        c.CourtID,
+		CourtLevel:     int32(c.CourtLevel),
+		CourtType:      string(c.CourtType),
+		Jurisdiction:   c.Jurisdiction,
+		Judges:         c.Judges,
+		Summary:        c.Summary,
+		Holding:        c.Holding,
+		Language:       c.Language,
+		LegalConcepts:  c.LegalConcepts,
+		AreasOfLaw:     c.AreasOfLaw,
+		Keywords:       c.Keywords,
+		CitedBy:        c.CitedBy,
+		Status:         string(c.Status),
+		Outcome:        c.Outcome,
+		URL:            c.URL,
+		SourceDatabase: c.SourceDatabase,
+		ScrapedAt:      parquetTimestamp(c.ScrapedAt),
+		LastUpdated:    parquetTimestamp(c.LastUpdated),
+		Version:        int32(c.Version),
+		QualityScore:   c.QualityScore,
+		ECLI:           c.ECLI,
+		Docket:         c.Docket,
+	}
+	if includeFullText {
+		row.FullText = c.FullText
+	}
+	if c.DecisionDate != nil {
+		row.DecisionDate = parquetDate(*c.DecisionDate)
+	}
+	return row
+}
+
+// parquetDate returns t's calendar date as days since the Unix epoch
+func parquetDate(t time.Time) *int32 {
+	t = t.UTC()
+	days := int32(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400)
+	return &days
+}
+
+// parquetTimestamp returns t in milliseconds since the Unix epoch, or nil if unset
+func parquetTimestamp(t time.Time) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	millis := t.UnixMilli()
+	return &millis
+}
+
+// parquetCaseWriter writes cases to w as a Parquet file, flushing a row group
+// whenever parquetRowGroupSize is buffered
+type parquetCaseWriter struct {
+	pw              *writer.ParquetWriter
+	includeFullText bool
+}
+
+func newParquetCaseWriter(w io.Writer, includeFullText bool) (*parquetCaseWriter, error) {
+	pw, err := writer.NewParquetWriterFromWriter(w, new(ParquetCase), parquetParallelism)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet writer: %w", err)
+	}
+	pw.RowGroupSize = parquetRowGroupSize
+	pw.CompressionType = parquet.CompressionCodec_SNAPPY
+
+	return &parquetCaseWriter{pw: pw, includeFullText: includeFullText}, nil
+}
+
+// Write adds a case to the current row group
+func (w *parquetCaseWriter) Write(c *models.Case) error {
+	if err := w.pw.Write(NewParquetCase(c, w.includeFullText)); err != nil {
+		return fmt.Errorf("failed to write case %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// Close flushes the last row group and writes the file footer
+func (w *parquetCaseWriter) Close() error {
+	if err := w.pw.WriteStop(); err != nil {
+		return fmt.Errorf("failed to finish parquet file: %w", err)
+	}
+	return nil
+}
+
+// exportParquet exports cases as a Parquet file
+func (e *Exporter) exportParquet(cases []*models.Case) error {
+	pw, err := newParquetCaseWriter(e.writer, e.options.IncludeFullText)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cases {
+		if err := pw.Write(c); err != nil {
+			return err
+		}
+	}
+	return pw.Close()
+}
+
+// streamParquet streams cases into a Parquet file one row group at a time
+func (se *StreamExporter) streamParquet(ctx context.Context, cases <-chan *models.Case) error {
+	pw, err := newParquetCaseWriter(se.writer, se.options.IncludeFullText)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case c, ok := <-cases:
+			if !ok {
+				return pw.Close()
+			}
+
+			if err := pw.Write(se.options.Apply(c)); err != nil {
+				return err
+			}
+		}
+	}
+}
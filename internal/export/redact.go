@@ -0,0 +1,273 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultRedactionMask replaces redacted values when a rule sets no mask
+const DefaultRedactionMask = "[REDACTED]"
+
+// RedactionRule masks fields of the cases it applies to. A rule with no
+// sources or court types applies to every case.
+type RedactionRule struct {
+	Fields          []string           `json:"fields"` // JSON field names, e.g. "appellant"
+	SourceDatabases []string           `json:"source_databases,omitempty"`
+	CourtTypes      []models.CourtType `json:"court_types,omitempty"`
+	Mask            string             `json:"mask,omitempty"`
+}
+
+// appliesTo reports whether the rule covers a case
+func (r RedactionRule) appliesTo(c *models.Case) bool {
+	if len(r.SourceDatabases) > 0 {
+		found := false
+		for _, source := range r.SourceDatabases {
+			if source == c.SourceDatabase {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.CourtTypes) > 0 {
+		found := false
+		for _, courtType := range r.CourtTypes {
+			if courtType == c.CourtType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// caseField clears or masks one field of a case. mask is nil for fields that
+// can be excluded but not redacted.
+type caseField struct {
+	clear func(c *models.Case)
+	mask  func(c *models.Case, mask string)
+}
+
+// maskString replaces a non-empty value with mask
+func maskString(value *string, mask string) {
+	if *value != "" {
+		*value = mask
+	}
+}
+
+// maskStrings replaces each value with mask, in a new slice
+func maskStrings(values []string, mask string) []string {
+	if len(values) == 0 {
+		return values
+	}
+	masked := make([]string, len(values))
+	for i := range masked {
+		masked[i] = mask
+	}
+	return masked
+}
+
+// stringField is a caseField over a string field
+func stringField(get func(c *models.Case) *string) caseField {
+	return caseField{
+		clear: func(c *models.Case) { *get(c) = "" },
+		mask:  func(c *models.Case, mask string) { maskString(get(c), mask) },
+	}
+}
+
+// stringsField is a caseField over a string slice field
+func stringsField(get func(c *models.Case) *[]string) caseField {
+	return caseField{
+		clear: func(c *models.Case) { *get(c) = nil },
+		mask:  func(c *models.Case, mask string) { *get(c) = maskStrings(*get(c), mask) },
+	}
+}
+
+// exportFields are the case fields that can be excluded from or redacted in
+// exports, keyed by JSON name
+var exportFields = map[string]caseField{
+	"case_number":     stringField(func(c *models.Case) *string { return &c.CaseNumber }),
+	"case_name":       stringField(func(c *models.Case) *string { return &c.CaseName }),
+	"alternate_names": stringsField(func(c *models.Case) *[]string { return &c.AlternateNames }),
+	"appellant":       stringField(func(c *models.Case) *string { return &c.Appellant }),
+	"respondent":      stringField(func(c *models.Case) *string { return &c.Respondent }),
+	"judges":          stringsField(func(c *models.Case) *[]string { return &c.Judges }),
+	"chief_judge":     stringField(func(c *models.Case) *string { return &c.ChiefJudge }),
+	"summary":         stringField(func(c *models.Case) *string { return &c.Summary }),
+	"headnotes":       stringField(func(c *models.Case) *string { return &c.Headnotes }),
+	"holding":         stringField(func(c *models.Case) *string { return &c.Holding }),
+	"full_text":       stringField(func(c *models.Case) *string { return &c.FullText }),
+	"outcome":         stringField(func(c *models.Case) *string { return &c.Outcome }),
+	"disposition":     stringField(func(c *models.Case) *string { return &c.Disposition }),
+	"keywords":        stringsField(func(c *models.Case) *[]string { return &c.Keywords }),
+	"catchwords":      stringsField(func(c *models.Case) *[]string { return &c.Catchwords }),
+	"legal_concepts":  stringsField(func(c *models.Case) *[]string { return &c.LegalConcepts }),
+	"url":             stringField(func(c *models.Case) *string { return &c.URL }),
+	"source_database": stringField(func(c *models.Case) *string { return &c.SourceDatabase }),
+	"docket":          stringField(func(c *models.Case) *string { return &c.Docket }),
+	"parties": {
+		clear: func(c *models.Case) { c.Parties = nil },
+		mask: func(c *models.Case, mask string) {
+			if len(c.Parties) == 0 {
+				return
+			}
+			parties := make([]models.Party, len(c.Parties))
+			for i, p := range c.Parties {
+				p.Name = mask
+				p.Lawyers = maskStrings(p.Lawyers, mask)
+				parties[i] = p
+			}
+			c.Parties = parties
+		},
+	},
+	"citations": {clear: func(c *models.Case) { c.Citations = nil }},
+	"sections":  {clear: func(c *models.Case) { c.Sections = nil }},
+	"metadata":  {clear: func(c *models.Case) { c.Metadata = nil }},
+}
+
+// dateLayoutCheck is formatted and parsed back to check a date layout; its
+// day, month and year are all distinct
+var dateLayoutCheck = time.Date(1999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// validDateLayout reports whether a layout writes dates that read back as
+// the same day, so "2006-01-02" and "Jan 2, 2006" pass but "YYYY-MM-DD" and
+// "01/2006" don't
+func validDateLayout(layout string) bool {
+	parsed, err := time.Parse(layout, dateLayoutCheck.Format(layout))
+	if err != nil {
+		return false
+	}
+	y, m, d := parsed.Date()
+	return y == dateLayoutCheck.Year() && m == dateLayoutCheck.Month() && d == dateLayoutCheck.Day()
+}
+
+// Validate checks that excluded and redacted fields are known, that
+// redacted fields can be masked, and that the date layout writes whole dates
+func (o *ExportOptions) Validate() error {
+	if o.DateFormat != "" && !validDateLayout(o.DateFormat) {
+		return fmt.Errorf("invalid date format %q: use Go's reference date, e.g. 2006-01-02 or 2 Jan 2006", o.DateFormat)
+	}
+
+	for _, field := range o.ExcludeFields {
+		if _, ok := exportFields[field]; !ok {
+			return fmt.Errorf("cannot exclude unknown field %s", field)
+		}
+	}
+
+	for _, rule := range o.Redactions {
+		for _, field := range rule.Fields {
+			f, ok := exportFields[field]
+			if !ok {
+				return fmt.Errorf("cannot redact unknown field %s", field)
+			}
+			if f.mask == nil {
+				return fmt.Errorf("field %s can be excluded but not redacted", field)
+			}
+		}
+	}
+
+	return nil
+}
+
+// excluded reports whether a field is left out of exports. Sections are
+// judgment text, so they go with the full text.
+func (o *ExportOptions) excluded(field string) bool {
+	if (field == "full_text" || field == "sections") && !o.IncludeFullText {
+		return true
+	}
+	for _, f := range o.ExcludeFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// hasExclusions reports whether any field is left out of exports
+func (o *ExportOptions) hasExclusions() bool {
+	return len(o.ExcludeFields) > 0 || !o.IncludeFullText
+}
+
+// Apply returns a case as it should be exported: masked by every redaction
+// rule that applies to it, with excluded fields cleared. The case passed in
+// is not modified.
+func (o *ExportOptions) Apply(c *models.Case) *models.Case {
+	if !o.hasExclusions() && len(o.Redactions) == 0 {
+		return c
+	}
+
+	out := *c
+	for _, rule := range o.Redactions {
+		if !rule.appliesTo(c) {
+			continue
+		}
+		mask := rule.Mask
+		if mask == "" {
+			mask = DefaultRedactionMask
+		}
+		for _, field := range rule.Fields {
+			if f, ok := exportFields[field]; ok && f.mask != nil {
+				f.mask(&out, mask)
+			}
+		}
+	}
+
+	for field, f := range exportFields {
+		if o.excluded(field) {
+			f.clear(&out)
+		}
+	}
+
+	return &out
+}
+
+// selected reports whether a field is among those Fields keeps, which is
+// every field when Fields is empty
+func (o *ExportOptions) selected(field string) bool {
+	if len(o.Fields) == 0 {
+		return true
+	}
+	for _, f := range o.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonValue returns the value to encode for a case in JSON formats. Excluded
+// fields, and fields not selected by Fields, are dropped from the object
+// rather than written empty.
+func (o *ExportOptions) jsonValue(c *models.Case) (interface{}, error) {
+	c = o.Apply(c)
+	if !o.hasExclusions() && len(o.Fields) == 0 {
+		return c, nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for field := range object {
+		if o.excluded(field) || !o.selected(field) {
+			delete(object, field)
+		}
+	}
+
+	return object, nil
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+)
+
+// ErrJobNotFound is returned for export jobs a JobStore has no record of,
+// and for those of another tenant
+var ErrJobNotFound = errors.New("export job not found")
+
+// JobRecord is the status of an export job as a JobStore keeps it
+type JobRecord struct {
+	ID        string          `json:"id"`
+	Status    queue.JobStatus `json:"status"`
+	Format    ExportFormat    `json:"format"`
+	TenantID  *string         `json:"tenant_id,omitempty"`
+	Count     int             `json:"count"`
+	Error     string          `json:"error,omitempty"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// NewJobRecord returns the record of a queued export job
+func NewJobRecord(id string, job Job) *JobRecord {
+	return &JobRecord{
+		ID:        id,
+		Status:    queue.JobStatusPending,
+		Format:    job.Format,
+		TenantID:  job.TenantID,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// VisibleTo reports whether the record can be read in ctx: jobs of a tenant
+// only by that tenant, as TenantStorage hides other tenants' cases
+func (r *JobRecord) VisibleTo(ctx context.Context) bool {
+	if r.TenantID == nil || *r.TenantID == "" {
+		return true
+	}
+	tenantID, _ := storage.TenantFromContext(ctx)
+	return tenantID == *r.TenantID
+}
+
+// JobStore keeps the files export jobs write and their records, one JSON
+// file per job, in a directory shared by the workers that write them and the
+// API that reports on and serves them, such as a volume both mount. Files are
+// written under a temporary name and renamed when complete, so a partial
+// export is never served.
+type JobStore struct {
+	dir string
+}
+
+// NewJobStore returns a JobStore keeping its files in dir
+func NewJobStore(dir string) *JobStore {
+	return &JobStore{dir: dir}
+}
+
+// path returns the path of a job's file with the extension, rejecting IDs
+// that would reach outside the directory
+func (s *JobStore) path(id, ext string) (string, error) {
+	if id == "" || strings.HasPrefix(id, ".") || filepath.Base(id) != id {
+		return "", ErrJobNotFound
+	}
+	return filepath.Join(s.dir, id+"."+ext), nil
+}
+
+// Save writes a job's record
+func (s *JobStore) Save(record *JobRecord) error {
+	path, err := s.path(record.ID, "json")
+	if err != nil {
+		return err
+	}
+	record.UpdatedAt = time.Now()
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.write(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// Get reads a job's record
+func (s *JobStore) Get(id string) (*JobRecord, error) {
+	path, err := s.path(id, "json")
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export job: %w", err)
+	}
+
+	var record JobRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("invalid export job record: %w", err)
+	}
+	return &record, nil
+}
+
+// WriteFile writes a job's export file in format with fn
+func (s *JobStore) WriteFile(id string, format ExportFormat, fn func(io.Writer) error) error {
+	path, err := s.path(id, format.Extension())
+	if err != nil {
+		return err
+	}
+	return s.write(path, fn)
+}
+
+// Open opens the export file of a completed job
+func (s *JobStore) Open(record *JobRecord) (*os.File, error) {
+	if record.Status != queue.JobStatusCompleted {
+		return nil, fmt.Errorf("export job %s is %s", record.ID, record.Status)
+	}
+	path, err := s.path(record.ID, record.Format.Extension())
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrJobNotFound
+	}
+	return f, err
+}
+
+// write writes path with fn through a temporary file renamed into place
+func (s *JobStore) write(path string, fn func(io.Writer) error) error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create export dir: %w", err)
+	}
+	f, err := os.CreateTemp(s.dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(f.Name()) // fails once renamed
+
+	if err := fn(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	// CreateTemp makes the file private; the API may run as another user
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -3,10 +3,13 @@ package export
 import (
 	"compress/gzip"
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -19,11 +22,15 @@ type StreamExporter struct {
 	options    *ExportOptions
 }
 
-// NewStreamExporter creates a new streaming exporter
-func NewStreamExporter(format ExportFormat, writer io.Writer, options *ExportOptions) *StreamExporter {
+// NewStreamExporter creates a new streaming exporter, rejecting options
+// that fail Validate as NewExporterWithOptions does
+func NewStreamExporter(format ExportFormat, writer io.Writer, options *ExportOptions) (*StreamExporter, error) {
 	if options == nil {
 		options = DefaultExportOptions()
 	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
 
 	se := &StreamExporter{
 		format:  format,
@@ -38,7 +45,7 @@ func NewStreamExporter(format ExportFormat, writer io.Writer, options *ExportOpt
 		se.writer = se.gzipWriter
 	}
 
-	return se
+	return se, nil
 }
 
 // StreamCases streams cases one by one to the output
@@ -52,6 +59,8 @@ func (se *StreamExporter) StreamCases(ctx context.Context, cases <-chan *models.
 		return se.streamJSONLines(ctx, cases)
 	case FormatCSV:
 		return se.streamCSV(ctx, cases)
+	case FormatParquet:
+		return se.streamParquet(ctx, cases)
 	default:
 		return fmt.Errorf("streaming not supported for format: %s", se.format)
 	}
@@ -92,7 +101,11 @@ func (se *StreamExporter) streamJSON(ctx context.Context, cases <-chan *models.C
 			first = false
 
 			// Encode case
-			if err := encoder.Encode(c); err != nil {
+			value, err := se.options.jsonValue(c)
+			if err != nil {
+				return err
+			}
+			if err := encoder.Encode(value); err != nil {
 				return err
 			}
 		}
@@ -112,21 +125,35 @@ func (se *StreamExporter) streamJSONLines(ctx context.Context, cases <-chan *mod
 				return nil // Channel closed, done
 			}
 
-			if err := encoder.Encode(c); err != nil {
+			value, err := se.options.jsonValue(c)
+			if err != nil {
+				return err
+			}
+			if err := encoder.Encode(value); err != nil {
 				return err
 			}
 		}
 	}
 }
 
-// streamCSV streams cases as CSV
+// streamCSV streams cases as CSV, writing the header first and then a row
+// per case
 func (se *StreamExporter) streamCSV(ctx context.Context, cases <-chan *models.Case) error {
-	// Create a temporary exporter for CSV writing
-	exporter := NewExporter(FormatCSV, se.writer)
+	columns, err := se.options.csvColumns()
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(se.writer)
+	defer writer.Flush()
 
-	// Collect cases into a slice (CSV requires header first)
-	// For true streaming CSV, we'd need to write header first, then stream rows
-	var caseSlice []*models.Case
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.header
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
 
 	for {
 		select {
@@ -134,10 +161,12 @@ func (se *StreamExporter) streamCSV(ctx context.Context, cases <-chan *models.Ca
 			return ctx.Err()
 		case c, ok := <-cases:
 			if !ok {
-				// Export all collected cases
-				return exporter.Export(caseSlice)
+				writer.Flush()
+				return writer.Error()
+			}
+			if err := writer.Write(csvRow(columns, se.options.Apply(c), se.options.DateFormat)); err != nil {
+				return err
 			}
-			caseSlice = append(caseSlice, c)
 		}
 	}
 }
@@ -150,27 +179,62 @@ func (se *StreamExporter) Close() error {
 	return nil
 }
 
-// StreamFromStorage streams cases from storage adapter
-func StreamFromStorage(ctx context.Context, storage interface{}, query interface{}, format ExportFormat, writer io.Writer, options *ExportOptions) error {
+// Streams reports whether a StreamExporter can write the format
+func (f ExportFormat) Streams() bool {
+	switch f {
+	case FormatJSON, FormatJSONLines, FormatCSV, FormatParquet:
+		return true
+	}
+	return false
+}
+
+// StreamFromStorage streams the cases matching a search query and filter
+// (see StreamCasesMatching) from storage to writer, holding only a page of
+// them at a time, and returns how many it wrote
+func StreamFromStorage(ctx context.Context, store storage.Storage, query string, filter storage.CaseFilter, format ExportFormat, writer io.Writer, options *ExportOptions) (int, error) {
+	if !format.Streams() {
+		return 0, fmt.Errorf("streaming not supported for format: %s", format)
+	}
+
+	// Stop reading from storage if the export fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Create case channel
 	cases := make(chan *models.Case, 100)
 
-	// Create stream exporter
-	exporter := NewStreamExporter(format, writer, options)
-
 	// Start streaming in background
+	exporter, err := NewStreamExporter(format, writer, options)
+	if err != nil {
+		return 0, err
+	}
 	errCh := make(chan error, 1)
 	go func() {
-		errCh <- exporter.StreamCases(ctx, cases)
+		err := exporter.StreamCases(ctx, cases)
+		if err != nil {
+			cancel()
+		}
+		errCh <- err
 	}()
 
-	// Fetch cases and send to channel
-	// This is a placeholder - actual implementation would depend on storage adapter
-	// For now, we'll just close the channel to signal completion
+	// Fetch cases and send them to the exporter
+	count := 0
+	err = StreamCasesMatching(ctx, store, query, filter, func(c *models.Case) error {
+		select {
+		case cases <- c:
+			count++
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
 	close(cases)
 
-	// Wait for streaming to complete
-	return <-errCh
+	// Wait for streaming to complete; its error explains a cancelled fetch
+	if exportErr := <-errCh; exportErr != nil {
+		return count, fmt.Errorf("failed to export cases: %w", exportErr)
+	}
+	return count, err
 }
 
 // ChunkedExporter exports cases in chunks for better memory efficiency
@@ -201,7 +265,11 @@ func NewChunkedExporter(format ExportFormat, writer io.Writer, chunkSize int, op
 
 // ExportInChunks exports cases in chunks
 func (ce *ChunkedExporter) ExportInChunks(ctx context.Context, cases []*models.Case) error {
-	exporter := NewExporter(ce.format, ce.writer)
+	exporter, err := NewExporterWithOptions(ce.format, ce.writer, ce.options)
+	if err != nil {
+		return err
+	}
+	defer exporter.Close()
 
 	// Split into chunks
 	for i := 0; i < len(cases); i += ce.chunkSize {
@@ -237,17 +305,22 @@ type ExportProgress struct {
 // ProgressCallback is called periodically during export
 type ProgressCallback func(progress *ExportProgress)
 
-// ExportWithProgress exports cases with progress tracking
-func ExportWithProgress(ctx context.Context, cases []*models.Case, format ExportFormat, writer io.Writer, callback ProgressCallback) error {
+// ExportWithProgress exports cases with progress tracking. Nil options use
+// DefaultExportOptions.
+func ExportWithProgress(ctx context.Context, cases []*models.Case, format ExportFormat, writer io.Writer, options *ExportOptions, callback ProgressCallback) error {
 	progress := &ExportProgress{
 		Total:     len(cases),
 		Exported:  0,
 		Failed:    0,
-		StartTime: context.Background().Value("start_time").(int64),
+		StartTime: time.Now().Unix(),
 		Completed: false,
 	}
 
-	exporter := NewExporter(format, writer)
+	exporter, err := NewExporterWithOptions(format, writer, options)
+	if err != nil {
+		return err
+	}
+	defer exporter.Close()
 
 	// Export each case individually to track progress
 	for i, c := range cases {
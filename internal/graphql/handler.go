@@ -2,6 +2,7 @@ package graphql
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/graphql-go/graphql"
@@ -27,8 +28,9 @@ type GraphQLError struct {
 	Path    []string `json:"path,omitempty"`
 }
 
-// Handler creates a Fiber handler for GraphQL
-func Handler(schema graphql.Schema) fiber.Handler {
+// Handler creates a Fiber handler for GraphQL, cancelling queries that run
+// longer than timeout (see ExecuteQueryWithTimeout)
+func Handler(schema graphql.Schema, timeout time.Duration) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Parse request
 		var req GraphQLRequest
@@ -47,7 +49,7 @@ func Handler(schema graphql.Schema) fiber.Handler {
 			Msg("GraphQL query received")
 
 		// Execute query
-		result := ExecuteQuery(schema, req.Query, req.Variables, c.Context())
+		result := ExecuteQueryWithTimeout(schema, req.Query, req.Variables, c.Context(), timeout)
 
 		// Convert errors
 		var gqlErrors []GraphQLError
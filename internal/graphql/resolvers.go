@@ -1,25 +1,86 @@
 package graphql
 
 import (
+	"bytes"
 	"context"
-
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gongahkia/kite/internal/citation"
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/queue"
 	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/pkg/models"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 )
 
+// DefaultMaxCitationNetworkDepth is the default cap on the depth of
+// citationNetwork queries; the subgraph grows exponentially with each hop
+const DefaultMaxCitationNetworkDepth = 3
+
+// DefaultInlineExportLimit is the most cases exportCases returns inline;
+// larger exports run as a job
+const DefaultInlineExportLimit = 1000
+
+// ExportDownloadPath is where the API serves export job files, as
+// ExportDownloadPath + id + "/download"
+const ExportDownloadPath = "/api/v1/exports/"
+
+// DefaultExecutionTimeout is the longest a query runs before it is cancelled
+const DefaultExecutionTimeout = 30 * time.Second
+
+// ErrExecutionTimeout is reported for queries cancelled at their time limit
+var ErrExecutionTimeout = errors.New("query exceeded maximum execution time")
+
 // Resolver holds dependencies for GraphQL resolvers
 type Resolver struct {
-	storage storage.Storage
+	storage     storage.Storage
+	citations   *citation.Service
+	window      storage.ResultWindow
+	jobs        queue.Queue
+	exports     *export.JobStore
+	exportLimit int
+	maxDepth    int
 }
 
 // NewResolver creates a new resolver
-func NewResolver(storage storage.Storage) *Resolver {
+func NewResolver(store storage.Storage) *Resolver {
 	return &Resolver{
-		storage: storage,
+		storage:     store,
+		citations:   citation.NewService(store),
+		window:      storage.DefaultResultWindow(),
+		exportLimit: DefaultInlineExportLimit,
+		maxDepth:    DefaultMaxCitationNetworkDepth,
 	}
 }
 
+// SetResultWindow sets the default page size and the maximum limit and offset
+// accepted by list queries
+func (r *Resolver) SetResultWindow(window storage.ResultWindow) {
+	r.window = window
+}
+
+// SetJobQueue attaches the queue large exports are enqueued on, and the
+// store the workers write their files to, shared with the API that serves
+// them. Without them, exports beyond the inline limit are rejected.
+func (r *Resolver) SetJobQueue(q queue.Queue, exports *export.JobStore) {
+	r.jobs = q
+	r.exports = exports
+}
+
+// SetInlineExportLimit sets the most cases exportCases returns inline
+func (r *Resolver) SetInlineExportLimit(limit int) {
+	r.exportLimit = limit
+}
+
+// SetMaxCitationNetworkDepth sets the deepest citationNetwork query accepted
+func (r *Resolver) SetMaxCitationNetworkDepth(depth int) {
+	r.maxDepth = depth
+}
+
 // GetCaseResolver resolves a single case by ID
 func (r *Resolver) GetCaseResolver(params graphql.ResolveParams) (interface{}, error) {
 	id, ok := params.Args["id"].(string)
@@ -61,12 +122,9 @@ func (r *Resolver) SearchCasesResolver(params graphql.ResolveParams) (interface{
 		}
 	}
 
-	// Set defaults
-	if limit == 0 {
-		limit = 20
-	}
-	if limit > 100 {
-		limit = 100
+	limit = r.window.Limit(limit)
+	if err := r.window.Check(limit, offset); err != nil {
+		return nil, err
 	}
 
 	// Search cases
@@ -97,8 +155,9 @@ func (r *Resolver) GetCasesByJurisdictionResolver(params graphql.ResolveParams)
 	limit, _ := params.Args["limit"].(int)
 	offset, _ := params.Args["offset"].(int)
 
-	if limit == 0 {
-		limit = 20
+	limit = r.window.Limit(limit)
+	if err := r.window.Check(limit, offset); err != nil {
+		return nil, err
 	}
 
 	filters := storage.SearchFilters{
@@ -146,6 +205,53 @@ func (r *Resolver) GetStatsResolver(params graphql.ResolveParams) (interface{},
 	}, nil
 }
 
+// CitationNetworkResolver resolves the citation network around a case
+func (r *Resolver) CitationNetworkResolver(params graphql.ResolveParams) (interface{}, error) {
+	caseID, ok := params.Args["caseId"].(string)
+	if !ok {
+		return nil, nil
+	}
+
+	depth, ok := params.Args["depth"].(int)
+	if !ok || depth < 1 {
+		depth = 1
+	}
+	if depth > r.maxDepth {
+		return nil, fmt.Errorf("depth %d exceeds maximum of %d", depth, r.maxDepth)
+	}
+
+	network, err := r.citations.GetCitationNetwork(params.Context, caseID, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]map[string]interface{}, 0, len(network.Nodes))
+	for _, n := range network.Nodes {
+		nodes = append(nodes, map[string]interface{}{
+			"caseId":    n.CaseID,
+			"caseName":  n.CaseName,
+			"inDegree":  n.InDegree,
+			"outDegree": n.OutDegree,
+		})
+	}
+
+	edges := make([]map[string]interface{}, 0, len(network.Edges))
+	for _, e := range network.Edges {
+		edges = append(edges, map[string]interface{}{
+			"fromCaseId":    e.FromCaseID,
+			"toCaseId":      e.ToCaseID,
+			"treatmentType": e.TreatmentType,
+			"weight":        e.Weight,
+		})
+	}
+
+	return map[string]interface{}{
+		"nodes": nodes,
+		"edges": edges,
+		"depth": depth,
+	}, nil
+}
+
 // CreateCaseResolver creates a new case
 func (r *Resolver) CreateCaseResolver(params graphql.ResolveParams) (interface{}, error) {
 	ctx := params.Context
@@ -260,6 +366,167 @@ func (r *Resolver) DeleteCaseResolver(params graphql.ResolveParams) (interface{}
 	return true, nil
 }
 
+// ExportCasesResolver exports the cases matching a search query and filter.
+// Up to the inline limit, the exported file is returned base64 encoded;
+// larger exports are enqueued as an export job, whose ID is returned for
+// exportJob to report on.
+func (r *Resolver) ExportCasesResolver(params graphql.ResolveParams) (interface{}, error) {
+	ctx := params.Context
+
+	query, _ := params.Args["query"].(string)
+	name, _ := params.Args["format"].(string)
+	format := export.ExportFormat(name)
+	if format.Extension() == "" {
+		return nil, fmt.Errorf("unsupported export format: %s", name)
+	}
+	filterMap, _ := params.Args["filter"].(map[string]interface{})
+	filter := caseFilter(filterMap)
+	optionsMap, _ := params.Args["options"].(map[string]interface{})
+	options := exportOptions(format, optionsMap)
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	// One case beyond the limit tells an inline export from a job
+	cases, err := export.CasesMatching(ctx, r.storage, query, filter, r.exportLimit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cases) > r.exportLimit {
+		if r.jobs == nil || r.exports == nil {
+			return nil, fmt.Errorf("export exceeds the inline limit of %d cases", r.exportLimit)
+		}
+
+		// The job reads as this request does, scoped to its tenant and the
+		// jurisdictions served; recorded first so exportJob finds it at once
+		request := export.NewJob(ctx, r.storage, query, filter, format, options)
+		job := queue.NewJob(queue.JobTypeExport, request.Payload())
+		record := export.NewJobRecord(job.ID, request)
+		if err := r.exports.Save(record); err != nil {
+			return nil, fmt.Errorf("failed to record export job: %w", err)
+		}
+		if err := r.jobs.Enqueue(ctx, job); err != nil {
+			record.Status = queue.JobStatusFailed
+			record.Error = err.Error()
+			_ = r.exports.Save(record)
+			return nil, fmt.Errorf("failed to enqueue export job: %w", err)
+		}
+		return map[string]interface{}{
+			"format": string(format),
+			"status": string(job.Status),
+			"jobId":  job.ID,
+		}, nil
+	}
+
+	var buf bytes.Buffer
+	exporter, err := export.NewExporterWithOptions(format, &buf, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := exporter.Export(cases); err != nil {
+		return nil, err
+	}
+	if err := exporter.Close(); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"format":  string(format),
+		"status":  string(queue.JobStatusCompleted),
+		"count":   len(cases),
+		"payload": base64.StdEncoding.EncodeToString(buf.Bytes()),
+	}, nil
+}
+
+// ExportJobResolver reports on an export job: its status and, once it has
+// completed, how many cases it exported and where to download them. Jobs of
+// other tenants are not found.
+func (r *Resolver) ExportJobResolver(params graphql.ResolveParams) (interface{}, error) {
+	if r.exports == nil {
+		return nil, errors.New("export jobs are not available")
+	}
+
+	id, _ := params.Args["id"].(string)
+	record, err := r.exports.Get(id)
+	if err == nil && !record.VisibleTo(params.Context) {
+		err = export.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"id":     record.ID,
+		"format": string(record.Format),
+		"status": string(record.Status),
+	}
+	if record.Error != "" {
+		result["error"] = record.Error
+	}
+	if record.Status == queue.JobStatusCompleted {
+		result["count"] = record.Count
+		result["downloadUrl"] = ExportDownloadPath + record.ID + "/download"
+	}
+	return result, nil
+}
+
+// exportOptions converts an ExportOptionsInput to export options, starting
+// from DefaultExportOptions
+func exportOptions(format export.ExportFormat, optionsMap map[string]interface{}) *export.ExportOptions {
+	options := export.DefaultExportOptions()
+	options.Format = format
+	if fields, ok := optionsMap["fields"].([]interface{}); ok {
+		options.Fields = stringList(fields)
+	}
+	if fields, ok := optionsMap["excludeFields"].([]interface{}); ok {
+		options.ExcludeFields = stringList(fields)
+	}
+	if pretty, ok := optionsMap["pretty"].(bool); ok {
+		options.Pretty = pretty
+	}
+	if fullText, ok := optionsMap["includeFullText"].(bool); ok {
+		options.IncludeFullText = fullText
+	}
+	if dateFormat, ok := optionsMap["dateFormat"].(string); ok {
+		options.DateFormat = dateFormat
+	}
+	return options
+}
+
+// stringList converts a list argument to strings, skipping other values
+func stringList(values []interface{}) []string {
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// caseFilter converts a FilterInput to a storage filter. legalTopics has no
+// storage filter and is ignored.
+func caseFilter(filterMap map[string]interface{}) storage.CaseFilter {
+	var filter storage.CaseFilter
+	if jurisdiction, ok := filterMap["jurisdiction"].(string); ok {
+		filter.Jurisdiction = jurisdiction
+	}
+	if court, ok := filterMap["court"].(string); ok {
+		filter.Court = court
+	}
+	if startDate, ok := filterMap["startDate"].(time.Time); ok {
+		filter.StartDate = &startDate
+	}
+	if endDate, ok := filterMap["endDate"].(time.Time); ok {
+		filter.EndDate = &endDate
+	}
+	if minScore, ok := filterMap["minQualityScore"].(float64); ok {
+		filter.MinQuality = minScore
+	}
+	return filter
+}
+
 // BuildSchema builds the complete GraphQL schema
 func BuildSchema(resolver *Resolver) (graphql.Schema, error) {
 	// Query type
@@ -285,8 +552,7 @@ func BuildSchema(resolver *Resolver) (graphql.Schema, error) {
 						Type: FilterInputType,
 					},
 					"limit": &graphql.ArgumentConfig{
-						Type:         graphql.Int,
-						DefaultValue: 20,
+						Type: graphql.Int,
 					},
 					"offset": &graphql.ArgumentConfig{
 						Type:         graphql.Int,
@@ -302,8 +568,7 @@ func BuildSchema(resolver *Resolver) (graphql.Schema, error) {
 						Type: graphql.NewNonNull(graphql.String),
 					},
 					"limit": &graphql.ArgumentConfig{
-						Type:         graphql.Int,
-						DefaultValue: 20,
+						Type: graphql.Int,
 					},
 					"offset": &graphql.ArgumentConfig{
 						Type:         graphql.Int,
@@ -316,6 +581,46 @@ func BuildSchema(resolver *Resolver) (graphql.Schema, error) {
 				Type:    StatsType,
 				Resolve: resolver.GetStatsResolver,
 			},
+			"citationNetwork": &graphql.Field{
+				Type: CitationGraphType,
+				Args: graphql.FieldConfigArgument{
+					"caseId": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					"depth": &graphql.ArgumentConfig{
+						Type:         graphql.Int,
+						DefaultValue: 1,
+					},
+				},
+				Resolve: resolver.CitationNetworkResolver,
+			},
+			"exportCases": &graphql.Field{
+				Type: ExportResultType,
+				Args: graphql.FieldConfigArgument{
+					"query": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+					"filter": &graphql.ArgumentConfig{
+						Type: FilterInputType,
+					},
+					"format": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+					"options": &graphql.ArgumentConfig{
+						Type: ExportOptionsInputType,
+					},
+				},
+				Resolve: resolver.ExportCasesResolver,
+			},
+			"exportJob": &graphql.Field{
+				Type: ExportJobType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.NewNonNull(graphql.String),
+					},
+				},
+				Resolve: resolver.ExportJobResolver,
+			},
 		},
 	})
 
@@ -363,8 +668,23 @@ func BuildSchema(resolver *Resolver) (graphql.Schema, error) {
 	})
 }
 
-// ExecuteQuery executes a GraphQL query
+// ExecuteQuery executes a GraphQL query, cancelling it after
+// DefaultExecutionTimeout
 func ExecuteQuery(schema graphql.Schema, query string, variables map[string]interface{}, ctx context.Context) *graphql.Result {
+	return ExecuteQueryWithTimeout(schema, query, variables, ctx, DefaultExecutionTimeout)
+}
+
+// ExecuteQueryWithTimeout executes a GraphQL query, cancelling the context
+// resolvers run with once timeout has passed and returning ErrExecutionTimeout
+// in place of partial results. A timeout of zero or less leaves only ctx's
+// own deadline.
+func ExecuteQueryWithTimeout(schema graphql.Schema, query string, variables map[string]interface{}, ctx context.Context, timeout time.Duration) *graphql.Result {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
 	result := graphql.Do(graphql.Params{
 		Schema:         schema,
 		RequestString:  query,
@@ -372,5 +692,10 @@ func ExecuteQuery(schema graphql.Schema, query string, variables map[string]inte
 		Context:        ctx,
 	})
 
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return &graphql.Result{
+			Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(ErrExecutionTimeout)},
+		}
+	}
 	return result
 }
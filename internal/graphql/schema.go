@@ -78,6 +78,60 @@ var CitationType = graphql.NewObject(graphql.ObjectConfig{
 	},
 })
 
+// CitationGraphNodeType represents a case in a citation network
+var CitationGraphNodeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CitationGraphNode",
+	Fields: graphql.Fields{
+		"caseId": &graphql.Field{
+			Type: graphql.String,
+		},
+		"caseName": &graphql.Field{
+			Type: graphql.String,
+		},
+		"inDegree": &graphql.Field{
+			Type: graphql.Int,
+		},
+		"outDegree": &graphql.Field{
+			Type: graphql.Int,
+		},
+	},
+})
+
+// CitationGraphEdgeType represents a citation between two cases
+var CitationGraphEdgeType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CitationGraphEdge",
+	Fields: graphql.Fields{
+		"fromCaseId": &graphql.Field{
+			Type: graphql.String,
+		},
+		"toCaseId": &graphql.Field{
+			Type: graphql.String,
+		},
+		"treatmentType": &graphql.Field{
+			Type: graphql.String,
+		},
+		"weight": &graphql.Field{
+			Type: graphql.Float,
+		},
+	},
+})
+
+// CitationGraphType represents the citation network around a case
+var CitationGraphType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CitationGraph",
+	Fields: graphql.Fields{
+		"nodes": &graphql.Field{
+			Type: graphql.NewList(CitationGraphNodeType),
+		},
+		"edges": &graphql.Field{
+			Type: graphql.NewList(CitationGraphEdgeType),
+		},
+		"depth": &graphql.Field{
+			Type: graphql.Int,
+		},
+	},
+})
+
 // SearchResultType represents search results with pagination
 var SearchResultType = graphql.NewObject(graphql.ObjectConfig{
 	Name: "SearchResult",
@@ -163,6 +217,77 @@ var ScrapeJobType = graphql.NewObject(graphql.ObjectConfig{
 	},
 })
 
+// ExportResultType represents an export: the exported file inline, base64
+// encoded, or the job writing a larger export
+var ExportResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ExportResult",
+	Fields: graphql.Fields{
+		"format": &graphql.Field{
+			Type: graphql.String,
+		},
+		"status": &graphql.Field{
+			Type: graphql.String,
+		},
+		"count": &graphql.Field{
+			Type: graphql.Int,
+		},
+		"payload": &graphql.Field{
+			Type: graphql.String,
+		},
+		"jobId": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+// ExportJobType represents an export job and, once it has completed, where
+// to download its file
+var ExportJobType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "ExportJob",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.String,
+		},
+		"format": &graphql.Field{
+			Type: graphql.String,
+		},
+		"status": &graphql.Field{
+			Type: graphql.String,
+		},
+		"count": &graphql.Field{
+			Type: graphql.Int,
+		},
+		"error": &graphql.Field{
+			Type: graphql.String,
+		},
+		"downloadUrl": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+// ExportOptionsInputType for choosing the fields and layout of an export
+var ExportOptionsInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "ExportOptionsInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"fields": &graphql.InputObjectFieldConfig{
+			Type: graphql.NewList(graphql.String),
+		},
+		"excludeFields": &graphql.InputObjectFieldConfig{
+			Type: graphql.NewList(graphql.String),
+		},
+		"pretty": &graphql.InputObjectFieldConfig{
+			Type: graphql.Boolean,
+		},
+		"includeFullText": &graphql.InputObjectFieldConfig{
+			Type: graphql.Boolean,
+		},
+		"dateFormat": &graphql.InputObjectFieldConfig{
+			Type: graphql.String,
+		},
+	},
+})
+
 // FilterInputType for filtering cases
 var FilterInputType = graphql.NewInputObject(graphql.InputObjectConfig{
 	Name: "FilterInput",
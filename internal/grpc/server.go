@@ -1,10 +1,12 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 	"net"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/gongahkia/kite/internal/grpc/services"
@@ -21,14 +23,30 @@ type Server struct {
 	logger     *observability.Logger
 	storage    storage.Storage
 	queue      queue.Queue
+	window     storage.ResultWindow
 }
 
+// DefaultMaxMsgSize is the largest message the server receives or sends when
+// ServerConfig sets no limit. It is above gRPC's 4MB default so cases with
+// long judgments fit.
+const DefaultMaxMsgSize = 16 << 20 // 16MB
+
 // ServerConfig holds gRPC server configuration
 type ServerConfig struct {
 	Port    int
 	Storage storage.Storage
 	Queue   queue.Queue
 	Logger  *observability.Logger
+
+	// Maximum limit and offset for list and search calls
+	ResultWindow storage.ResultWindow
+
+	// Reflection lists the server's services to tools like grpcurl
+	Reflection bool
+
+	// Largest message received and sent, in bytes (0 uses DefaultMaxMsgSize)
+	MaxRecvMsgSize int
+	MaxSendMsgSize int
 }
 
 // NewServer creates a new gRPC server
@@ -39,8 +57,18 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		return nil, fmt.Errorf("failed to listen: %w", err)
 	}
 
+	maxRecv, maxSend := config.MaxRecvMsgSize, config.MaxSendMsgSize
+	if maxRecv <= 0 {
+		maxRecv = DefaultMaxMsgSize
+	}
+	if maxSend <= 0 {
+		maxSend = DefaultMaxMsgSize
+	}
+
 	// Create gRPC server with interceptors
 	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxRecv),
+		grpc.MaxSendMsgSize(maxSend),
 		grpc.ChainUnaryInterceptor(
 			loggingInterceptor(config.Logger),
 			recoveryInterceptor(config.Logger),
@@ -58,13 +86,16 @@ func NewServer(config *ServerConfig) (*Server, error) {
 		logger:     config.Logger,
 		storage:    config.Storage,
 		queue:      config.Queue,
+		window:     config.ResultWindow,
 	}
 
 	// Register services
 	server.registerServices()
 
 	// Enable reflection for tools like grpcurl
-	reflection.Register(grpcServer)
+	if config.Reflection {
+		reflection.Register(grpcServer)
+	}
 
 	return server, nil
 }
@@ -76,9 +107,16 @@ func (s *Server) registerServices() {
 	pb.RegisterScraperServiceServer(s.grpcServer, scraperSvc)
 
 	// Register SearchService
-	searchSvc := services.NewSearchService(s.storage, s.logger)
+	searchSvc := services.NewSearchService(s.storage, s.logger, s.window)
 	pb.RegisterSearchServiceServer(s.grpcServer, searchSvc)
 
+	// Register the standard health service, reporting storage and queue connectivity
+	healthSvc := services.NewHealthService(s.storage, s.queue,
+		pb.ScraperService_ServiceDesc.ServiceName,
+		pb.SearchService_ServiceDesc.ServiceName,
+	)
+	healthpb.RegisterHealthServer(s.grpcServer, healthSvc)
+
 	s.logger.Info("gRPC services registered")
 }
 
@@ -94,6 +132,25 @@ func (s *Server) Stop() {
 	s.grpcServer.GracefulStop()
 }
 
+// StopWithContext gracefully stops the gRPC server, waiting for in-flight
+// RPCs to finish. When ctx is done first, the remaining RPCs are cancelled.
+func (s *Server) StopWithContext(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
 // GetListener returns the server's listener
 func (s *Server) GetListener() net.Listener {
 	return s.listener
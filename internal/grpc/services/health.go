@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+)
+
+// HealthService implements the standard grpc.health.v1.Health service. The
+// server and each registered service are SERVING while storage answers a
+// ping and the queue reports its depth, and NOT_SERVING otherwise.
+type HealthService struct {
+	healthpb.UnimplementedHealthServer
+	storage  storage.Storage
+	queue    queue.Queue
+	services map[string]bool
+	timeout  time.Duration // for each dependency check
+	interval time.Duration // between checks while watching
+}
+
+// NewHealthService creates a health service for the named gRPC services
+// (e.g. "kite.api.v1.SearchService"); the empty name is the server overall
+func NewHealthService(storage storage.Storage, queue queue.Queue, services ...string) *HealthService {
+	known := map[string]bool{"": true}
+	for _, name := range services {
+		known[name] = true
+	}
+
+	return &HealthService{
+		storage:  storage,
+		queue:    queue,
+		services: known,
+		timeout:  2 * time.Second,
+		interval: 5 * time.Second,
+	}
+}
+
+// Check reports whether the service can serve requests
+func (h *HealthService) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !h.services[req.Service] {
+		return nil, status.Errorf(codes.NotFound, "unknown service: %s", req.Service)
+	}
+	return &healthpb.HealthCheckResponse{Status: h.status(ctx)}, nil
+}
+
+// Watch streams the service's status, sending it again whenever it changes
+func (h *HealthService) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	if !h.services[req.Service] {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN}); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		if current := h.status(ctx); current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// status checks storage and queue connectivity
+func (h *HealthService) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	if h.storage == nil || h.storage.Ping(ctx) != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	if h.queue != nil {
+		if _, err := h.queue.GetDepth(ctx); err != nil {
+			return healthpb.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return healthpb.HealthCheckResponse_SERVING
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
 	pb "github.com/gongahkia/kite/api/proto"
 )
@@ -19,13 +20,15 @@ type SearchService struct {
 	pb.UnimplementedSearchServiceServer
 	storage storage.Storage
 	logger  *observability.Logger
+	window  storage.ResultWindow
 }
 
 // NewSearchService creates a new search service
-func NewSearchService(storage storage.Storage, logger *observability.Logger) *SearchService {
+func NewSearchService(storage storage.Storage, logger *observability.Logger, window storage.ResultWindow) *SearchService {
 	return &SearchService{
 		storage: storage,
 		logger:  logger,
+		window:  window,
 	}
 }
 
@@ -35,12 +38,17 @@ func (s *SearchService) SearchCases(ctx context.Context, req *pb.SearchCasesRequ
 
 	s.logger.WithField("query", req.Query).Info("Searching cases")
 
+	limit := s.window.Limit(int(req.Limit))
+	if err := s.window.Check(limit, int(req.Offset)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, windowMessage(err))
+	}
+
 	// Build search query
 	searchQuery := storage.SearchQuery{
 		Query:  req.Query,
 		Fields: req.Fields,
 		Fuzzy:  req.Fuzzy,
-		Limit:  int(req.Limit),
+		Limit:  limit,
 		Offset: int(req.Offset),
 	}
 
@@ -72,8 +80,8 @@ func (s *SearchService) SearchCases(ctx context.Context, req *pb.SearchCasesRequ
 		TotalHits:    int32(len(results)),
 		SearchTimeMs: float64(searchTime.Milliseconds()),
 		Pagination: &pb.Pagination{
-			Page:       int32(req.Offset/req.Limit) + 1,
-			PageSize:   req.Limit,
+			Page:       int32(int(req.Offset)/limit) + 1,
+			PageSize:   int32(limit),
 			TotalCount: int32(len(results)),
 		},
 	}, nil
@@ -94,6 +102,10 @@ func (s *SearchService) GetCase(ctx context.Context, req *pb.GetCaseRequest) (*p
 // ListCases lists cases with filtering
 func (s *SearchService) ListCases(ctx context.Context, req *pb.ListCasesRequest) (*pb.ListCasesResponse, error) {
 	filter := protoFilterToStorageFilter(req.Filter)
+	filter.Limit = s.window.Limit(filter.Limit)
+	if err := s.window.Check(filter.Limit, filter.Offset); err != nil {
+		return nil, status.Error(codes.InvalidArgument, windowMessage(err))
+	}
 
 	cases, err := s.storage.ListCases(ctx, filter)
 	if err != nil {
@@ -119,6 +131,9 @@ func (s *SearchService) ListCases(ctx context.Context, req *pb.ListCasesRequest)
 // StreamCases streams cases matching criteria
 func (s *SearchService) StreamCases(req *pb.StreamCasesRequest, stream pb.SearchService_StreamCasesServer) error {
 	filter := protoFilterToStorageFilter(req.Filter)
+	if err := s.window.Check(filter.Limit, filter.Offset); err != nil {
+		return status.Error(codes.InvalidArgument, windowMessage(err))
+	}
 
 	cases, err := s.storage.ListCases(stream.Context(), filter)
 	if err != nil {
@@ -165,6 +180,14 @@ func (s *SearchService) GetCitationNetwork(ctx context.Context, req *pb.GetCitat
 
 // Helper functions
 
+// windowMessage returns the client-facing message of a result window error
+func windowMessage(err error) string {
+	if kiteErr, ok := err.(*errors.KiteError); ok {
+		return kiteErr.Message
+	}
+	return err.Error()
+}
+
 func protoFilterToStorageFilter(pf *pb.CaseFilter) storage.CaseFilter {
 	filter := storage.CaseFilter{}
 
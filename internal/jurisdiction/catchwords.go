@@ -0,0 +1,160 @@
+package jurisdiction
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+const (
+	// maxCatchwordWords drops clauses too long to be a topic, such as
+	// "whether the council owed a duty to subsequent purchasers"
+	maxCatchwordWords = 8
+
+	// maxCatchwordLines bounds the block when nothing marks its end
+	maxCatchwordLines = 40
+)
+
+var (
+	// catchwordHeadings introduce the editorial keyword block, as in
+	// AustLII's "CATCHWORDS:" or "Catchwords: Land law; adverse possession"
+	catchwordHeadings = []string{"catchwords", "catch words", "catchphrases", "keywords"}
+
+	// catchwordSeparator splits a chain such as "NEGLIGENCE – duty of care –
+	// pure economic loss" into its terms
+	catchwordSeparator = regexp.MustCompile(`\s+[–—]\s+|\s+--?\s+|;`)
+
+	// fieldLabel matches the label of the next field in a judgment's cover
+	// sheet, such as "LEGISLATION:" or "Cases cited:", which ends the block
+	fieldLabel = regexp.MustCompile(`^[A-Za-z][A-Za-z ()'/]{1,40}:`)
+)
+
+// CatchwordsExtractor parses the catchwords block that reporters and court
+// registries add to the cover sheet of a judgment, common in Australian, Hong
+// Kong and Singapore judgments
+type CatchwordsExtractor struct {
+	headings []string
+}
+
+// NewCatchwordsExtractor creates a new catchwords extractor
+func NewCatchwordsExtractor() *CatchwordsExtractor {
+	return &CatchwordsExtractor{
+		headings: catchwordHeadings,
+	}
+}
+
+// Extract returns the catchwords in text, in order and without duplicates.
+// Topic heads written in capitals ("ADMINISTRATIVE LAW") are title-cased and
+// clauses too long to be a topic are dropped.
+func (ce *CatchwordsExtractor) Extract(text string) []string {
+	block, ok := ce.block(strings.ReplaceAll(text, "\r\n", "\n"))
+	if !ok {
+		return nil
+	}
+
+	var catchwords []string
+	seen := make(map[string]bool)
+	for _, paragraph := range splitParagraphs(block) {
+		chain := strings.Join(strings.Fields(paragraph), " ")
+		for _, term := range catchwordSeparator.Split(chain, -1) {
+			term = cleanCatchword(term)
+			if term == "" || seen[strings.ToLower(term)] {
+				continue
+			}
+			seen[strings.ToLower(term)] = true
+			catchwords = append(catchwords, term)
+		}
+	}
+
+	return catchwords
+}
+
+// ExtractInto stores the catchwords on the case, reporting whether any were found
+func (ce *CatchwordsExtractor) ExtractInto(c *models.Case) bool {
+	catchwords := ce.Extract(c.FullText)
+	if len(catchwords) == 0 {
+		return false
+	}
+	c.Catchwords = catchwords
+	return true
+}
+
+// block returns the text of the catchwords block, which runs from its heading
+// to the next field label or heading. Blank lines between chains do not end
+// it, but a paragraph of prose after one does.
+func (ce *CatchwordsExtractor) block(text string) (string, bool) {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		inline, ok := matchHeading(line, ce.headings)
+		if !ok {
+			continue
+		}
+
+		var body []string
+		if inline != "" {
+			body = append(body, inline)
+		}
+		paragraphStart := true
+		for _, next := range lines[i+1:] {
+			trimmed := strings.TrimSpace(next)
+			if trimmed == "" {
+				paragraphStart = true
+				body = append(body, next)
+				continue
+			}
+			if fieldLabel.MatchString(trimmed) || (looksLikeHeading(trimmed) && !catchwordSeparator.MatchString(trimmed)) {
+				break
+			}
+			// Each chain opens with its topic, so a paragraph opening with a
+			// long clause is the judgment itself
+			topic := catchwordSeparator.Split(trimmed, 2)[0]
+			if paragraphStart && len(body) > 0 && len(strings.Fields(topic)) > maxCatchwordWords {
+				break
+			}
+			paragraphStart = false
+			body = append(body, next)
+			if len(body) >= maxCatchwordLines {
+				break
+			}
+		}
+
+		if block := strings.TrimSpace(strings.Join(body, "\n")); block != "" {
+			return block, true
+		}
+	}
+
+	return "", false
+}
+
+// cleanCatchword trims a term and title-cases it if written in capitals. It
+// returns "" for terms that are questions or clauses rather than topics.
+func cleanCatchword(term string) string {
+	term = strings.Trim(strings.TrimSpace(term), ".,:")
+	if term == "" {
+		return ""
+	}
+
+	lower := strings.ToLower(term)
+	if strings.HasPrefix(lower, "whether ") || strings.HasPrefix(lower, "held") ||
+		len(strings.Fields(term)) > maxCatchwordWords {
+		return ""
+	}
+
+	if strings.ToUpper(term) == term && strings.IndexFunc(term, unicode.IsLetter) >= 0 {
+		words := strings.Fields(lower)
+		for i, w := range words {
+			if i > 0 && (w == "of" || w == "and" || w == "the" || w == "in") {
+				continue
+			}
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			words[i] = string(r)
+		}
+		term = strings.Join(words, " ")
+	}
+
+	return term
+}
@@ -0,0 +1,131 @@
+package jurisdiction
+
+import (
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultMaxSummaryLength caps a derived summary, in characters
+const DefaultMaxSummaryLength = 600
+
+// minSummaryWords is the shortest paragraph taken as the opening of the
+// judgment rather than a cover sheet line such as a party name
+const minSummaryWords = 12
+
+// Summary sources, recorded in case metadata
+const (
+	SummarySourceHeadnote  = "headnote"  // a section headed e.g. "Headnote" or "Syllabus"
+	SummarySourceParagraph = "paragraph" // the first substantive paragraph
+)
+
+// defaultHeadnoteHeadings introduce a reporter's or registry's summary
+var defaultHeadnoteHeadings = []string{"headnote", "headnotes", "head note", "syllabus", "synopsis", "summary", "abstract"}
+
+// HeadnoteExtractor derives a short summary for cases whose source left it
+// empty, from a headnote section or else the first substantive paragraph of
+// the judgment. It only fills the gap; it does not summarize the judgment.
+type HeadnoteExtractor struct {
+	headings  []string
+	maxLength int
+}
+
+// NewHeadnoteExtractor creates a headnote extractor with the default headings
+// and length cap
+func NewHeadnoteExtractor() *HeadnoteExtractor {
+	return &HeadnoteExtractor{
+		headings:  defaultHeadnoteHeadings,
+		maxLength: DefaultMaxSummaryLength,
+	}
+}
+
+// SetHeadings sets the headings that introduce a headnote, matched case-insensitively
+func (he *HeadnoteExtractor) SetHeadings(headings []string) {
+	he.headings = make([]string, 0, len(headings))
+	for _, h := range headings {
+		he.headings = append(he.headings, strings.ToLower(strings.TrimSpace(h)))
+	}
+}
+
+// SetMaxLength sets the summary length cap, in characters; 0 or less restores the default
+func (he *HeadnoteExtractor) SetMaxLength(maxLength int) {
+	if maxLength <= 0 {
+		maxLength = DefaultMaxSummaryLength
+	}
+	he.maxLength = maxLength
+}
+
+// Extract derives a summary from the full text, returning it and its source
+func (he *HeadnoteExtractor) Extract(text string) (string, string, bool) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		return "", "", false
+	}
+
+	if section, ok := headedSection(text, he.headings); ok {
+		return he.truncate(section), SummarySourceHeadnote, true
+	}
+
+	for _, p := range splitParagraphs(text) {
+		if substantiveParagraph(p) {
+			return he.truncate(p), SummarySourceParagraph, true
+		}
+	}
+
+	return "", "", false
+}
+
+// ExtractInto stores a derived summary on a case without one, with its source
+// in metadata, reporting whether the summary was set
+func (he *HeadnoteExtractor) ExtractInto(c *models.Case) bool {
+	if strings.TrimSpace(c.Summary) != "" {
+		return false
+	}
+
+	summary, source, ok := he.Extract(c.FullText)
+	if !ok {
+		return false
+	}
+
+	c.Summary = summary
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	c.Metadata["summary_source"] = source
+	return true
+}
+
+// substantiveParagraph reports whether a paragraph is prose from the judgment
+// rather than a heading, cover sheet field or catchwords block
+func substantiveParagraph(p string) bool {
+	if len(strings.Fields(p)) < minSummaryWords || looksLikeHeading(p) {
+		return false
+	}
+	if fieldLabel.MatchString(p) {
+		return false
+	}
+	if _, ok := matchHeading(p, catchwordHeadings); ok {
+		return false
+	}
+	return true
+}
+
+// truncate collapses whitespace and caps text at the maximum length, ending
+// on the last full sentence that fits, or else at a word boundary
+func (he *HeadnoteExtractor) truncate(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) <= he.maxLength {
+		return text
+	}
+
+	head := text[:he.maxLength]
+	if end := strings.LastIndex(head, ". "); end > he.maxLength/2 {
+		return head[:end+1]
+	}
+
+	cut := strings.LastIndex(head, " ")
+	if cut <= 0 {
+		cut = he.maxLength
+	}
+	return strings.TrimSpace(text[:cut]) + "..."
+}
@@ -8,14 +8,26 @@ import (
 
 // CourtHierarchy represents the hierarchical structure of courts
 type CourtHierarchy struct {
-	levels map[string]models.CourtLevel
-	courts map[string]*CourtInfo
+	levels       map[string]models.CourtLevel
+	courts       map[string]*CourtInfo
+	defaultLevel models.CourtLevel // given to courts neither registered nor matched by pattern
 }
 
+// LevelConfidence is how a court's level was inferred, so that consumers can
+// treat guesses with caution
+type LevelConfidence string
+
+const (
+	LevelConfidenceExact   LevelConfidence = "exact"   // a registered court, by name, abbreviation or alias
+	LevelConfidencePattern LevelConfidence = "pattern" // a telling word in the name, such as "appeal"
+	LevelConfidenceDefault LevelConfidence = "default" // an unknown court, given the default level
+)
+
 // CourtInfo contains metadata about a specific court
 type CourtInfo struct {
 	Name         string            `json:"name"`
 	Abbreviation string            `json:"abbreviation"`
+	Aliases      []string          `json:"aliases,omitempty"`
 	Jurisdiction string            `json:"jurisdiction"`
 	Level        models.CourtLevel `json:"level"`
 	Type         CourtType         `json:"type"`
@@ -40,8 +52,9 @@ const (
 // NewCourtHierarchy creates a new court hierarchy system
 func NewCourtHierarchy() *CourtHierarchy {
 	ch := &CourtHierarchy{
-		levels: make(map[string]models.CourtLevel),
-		courts: make(map[string]*CourtInfo),
+		levels:       make(map[string]models.CourtLevel),
+		courts:       make(map[string]*CourtInfo),
+		defaultLevel: models.CourtLevelHigher,
 	}
 	ch.initializeDefaults()
 	return ch
@@ -53,6 +66,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 	ch.registerCourt(&CourtInfo{
 		Name:         "Supreme Court of the United States",
 		Abbreviation: "SCOTUS",
+		Aliases:      []string{"U.S. Supreme Court", "United States Supreme Court"},
 		Jurisdiction: "United States",
 		Level:        models.CourtLevelSupreme,
 		Type:         CourtTypeSupreme,
@@ -73,7 +87,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 		Name:         "U.S. District Court",
 		Abbreviation: "District",
 		Jurisdiction: "United States",
-		Level:        models.CourtLevelTrial,
+		Level:        models.CourtLevelHigher,
 		Type:         CourtTypeTrial,
 		ParentCourt:  "Circuit",
 		Precedential: false,
@@ -84,6 +98,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 	ch.registerCourt(&CourtInfo{
 		Name:         "UK Supreme Court",
 		Abbreviation: "UKSC",
+		Aliases:      []string{"Supreme Court of the United Kingdom", "United Kingdom Supreme Court"},
 		Jurisdiction: "United Kingdom",
 		Level:        models.CourtLevelSupreme,
 		Type:         CourtTypeSupreme,
@@ -93,6 +108,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 	ch.registerCourt(&CourtInfo{
 		Name:         "Court of Appeal (England & Wales)",
 		Abbreviation: "EWCA",
+		Aliases:      []string{"England and Wales Court of Appeal", "Court of Appeal of England and Wales"},
 		Jurisdiction: "United Kingdom",
 		Level:        models.CourtLevelAppellate,
 		Type:         CourtTypeAppellate,
@@ -103,8 +119,9 @@ func (ch *CourtHierarchy) initializeDefaults() {
 	ch.registerCourt(&CourtInfo{
 		Name:         "High Court (England & Wales)",
 		Abbreviation: "EWHC",
+		Aliases:      []string{"England and Wales High Court", "High Court of Justice"},
 		Jurisdiction: "United Kingdom",
-		Level:        models.CourtLevelTrial,
+		Level:        models.CourtLevelHigher,
 		Type:         CourtTypeTrial,
 		ParentCourt:  "EWCA",
 		Precedential: false,
@@ -241,6 +258,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 	ch.registerCourt(&CourtInfo{
 		Name:         "Court of Appeal of Singapore",
 		Abbreviation: "SGCA",
+		Aliases:      []string{"Singapore Court of Appeal"},
 		Jurisdiction: "Singapore",
 		Level:        models.CourtLevelSupreme,
 		Type:         CourtTypeSupreme,
@@ -251,7 +269,7 @@ func (ch *CourtHierarchy) initializeDefaults() {
 		Name:         "High Court of Singapore",
 		Abbreviation: "SGHC",
 		Jurisdiction: "Singapore",
-		Level:        models.CourtLevelTrial,
+		Level:        models.CourtLevelHigher,
 		Type:         CourtTypeTrial,
 		ParentCourt:  "SGCA",
 		Precedential: false,
@@ -264,39 +282,63 @@ func (ch *CourtHierarchy) registerCourt(info *CourtInfo) {
 	ch.courts[info.Abbreviation] = info
 	ch.courts[info.Name] = info
 	ch.levels[info.Name] = info.Level
+	for _, alias := range info.Aliases {
+		ch.courts[alias] = info
+	}
+}
+
+// SetDefaultLevel sets the level given to courts that are neither registered
+// nor matched by pattern; trial level unless set
+func (ch *CourtHierarchy) SetDefaultLevel(level models.CourtLevel) {
+	ch.defaultLevel = level
 }
 
 // GetCourtLevel determines the court level from court name or abbreviation
 func (ch *CourtHierarchy) GetCourtLevel(courtName string) models.CourtLevel {
+	level, _ := ch.InferCourtLevel(courtName)
+	return level
+}
+
+// InferCourtLevel determines the court level from court name or abbreviation,
+// and how it was determined: from a registered court, from a pattern in the
+// name, or by default for a court the hierarchy doesn't know
+func (ch *CourtHierarchy) InferCourtLevel(courtName string) (models.CourtLevel, LevelConfidence) {
 	// Try exact match first
 	if level, ok := ch.levels[courtName]; ok {
-		return level
+		return level, LevelConfidenceExact
 	}
 
 	// Try case-insensitive match
 	courtNameLower := strings.ToLower(courtName)
 	for name, level := range ch.levels {
 		if strings.ToLower(name) == courtNameLower {
-			return level
+			return level, LevelConfidenceExact
 		}
 	}
 
+	// Try abbreviations, aliases and variants of registered courts
+	if info, ok := ch.GetCourtInfo(courtName); ok {
+		return info.Level, LevelConfidenceExact
+	}
+
 	// Try pattern matching for common court names
 	if strings.Contains(courtNameLower, "supreme") || strings.Contains(courtNameLower, "final appeal") {
-		return models.CourtLevelSupreme
+		return models.CourtLevelSupreme, LevelConfidencePattern
 	}
 	if strings.Contains(courtNameLower, "appeal") || strings.Contains(courtNameLower, "appellate") {
-		return models.CourtLevelAppellate
+		return models.CourtLevelAppellate, LevelConfidencePattern
 	}
 	if strings.Contains(courtNameLower, "high court") && !strings.Contains(courtNameLower, "appeal") {
-		return models.CourtLevelTrial
+		return models.CourtLevelHigher, LevelConfidencePattern
 	}
-	if strings.Contains(courtNameLower, "district") || strings.Contains(courtNameLower, "magistrate") {
-		return models.CourtLevelLower
+	if strings.Contains(courtNameLower, "district") || strings.Contains(courtNameLower, "county") {
+		return models.CourtLevelDistrict, LevelConfidencePattern
+	}
+	if strings.Contains(courtNameLower, "magistrate") {
+		return models.CourtLevelLocal, LevelConfidencePattern
 	}
 
-	// Default to trial level
-	return models.CourtLevelTrial
+	return ch.defaultLevel, LevelConfidenceDefault
 }
 
 // GetCourtInfo retrieves detailed information about a court
@@ -314,9 +356,53 @@ func (ch *CourtHierarchy) GetCourtInfo(courtNameOrAbbr string) (*CourtInfo, bool
 		}
 	}
 
+	// Try ignoring punctuation, "&"/"and" and a leading "The"
+	key := courtKey(courtNameOrAbbr)
+	if key == "" {
+		return nil, false
+	}
+	for name, info := range ch.courts {
+		if courtKey(name) == key {
+			return info, true
+		}
+	}
+
 	return nil, false
 }
 
+// ResolveCourtID returns the canonical abbreviation for a court name or variant
+func (ch *CourtHierarchy) ResolveCourtID(court string) (string, bool) {
+	info, ok := ch.GetCourtInfo(strings.TrimSpace(court))
+	if !ok {
+		return "", false
+	}
+	return info.Abbreviation, true
+}
+
+// CourtNames returns the given court name with the registered name,
+// abbreviation and aliases of the court it resolves to, if any
+func (ch *CourtHierarchy) CourtNames(court string) []string {
+	names := []string{court}
+	if info, ok := ch.GetCourtInfo(strings.TrimSpace(court)); ok {
+		names = append(names, info.Name, info.Abbreviation)
+		names = append(names, info.Aliases...)
+	}
+	return names
+}
+
+// courtKey reduces a court name to lowercase words for loose comparison
+func courtKey(name string) string {
+	name = strings.ReplaceAll(strings.ReplaceAll(name, ".", ""), "&", " and ")
+	name = strings.ToLower(name)
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+	if len(words) > 0 && words[0] == "the" {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
 // GetCourtType determines the court type from court name
 func (ch *CourtHierarchy) GetCourtType(courtName string) CourtType {
 	if info, ok := ch.GetCourtInfo(courtName); ok {
@@ -364,6 +450,33 @@ func (ch *CourtHierarchy) GetParentCourt(courtName string) string {
 	return ""
 }
 
+// HearsAppealsFrom reports whether appeals from lower can reach appellate.
+// Registered courts must share a jurisdiction, and appellate must be on
+// lower's chain of parent courts if that chain leads to it; otherwise
+// appellate must be at a higher level.
+func (ch *CourtHierarchy) HearsAppealsFrom(appellate, lower string) bool {
+	upper, upperKnown := ch.GetCourtInfo(appellate)
+	info, lowerKnown := ch.GetCourtInfo(lower)
+	if upperKnown && lowerKnown {
+		if upper.Jurisdiction != info.Jurisdiction {
+			return false
+		}
+		for seen := map[*CourtInfo]bool{info: true}; info.ParentCourt != ""; {
+			parent, ok := ch.GetCourtInfo(info.ParentCourt)
+			if !ok || seen[parent] {
+				break
+			}
+			if parent == upper {
+				return true
+			}
+			seen[parent] = true
+			info = parent
+		}
+	}
+
+	return ch.GetCourtLevel(appellate) < ch.GetCourtLevel(lower)
+}
+
 // GetCourtsByJurisdiction returns all courts for a jurisdiction
 func (ch *CourtHierarchy) GetCourtsByJurisdiction(jurisdiction string) []*CourtInfo {
 	var courts []*CourtInfo
@@ -0,0 +1,280 @@
+package jurisdiction
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// maxHoldingLength caps the extracted holding, in characters
+const maxHoldingLength = 2000
+
+// Holding sources, recorded in case metadata alongside the confidence
+const (
+	HoldingSourceHeading  = "heading"  // a section headed e.g. "Holding" or "Ratio decidendi"
+	HoldingSourcePhrase   = "phrase"   // a paragraph with e.g. "we hold that"
+	HoldingSourcePosition = "position" // the closing paragraph, as a last resort
+)
+
+// HoldingStyle describes how judgments in a jurisdiction state their holding
+type HoldingStyle struct {
+	Name string
+
+	// Headings that introduce the holding itself, and headings of the
+	// concluding section that usually contains it
+	HoldingHeadings    []string
+	ConclusionHeadings []string
+
+	// Phrases that open a statement of the holding
+	Phrases []string
+
+	// Whether the holding is usually stated early (US opinions announce it up
+	// front) or at the end (Commonwealth judgments build up to it)
+	PhraseFromStart bool
+}
+
+// HoldingMatch is a best-effort holding found in a judgment
+type HoldingMatch struct {
+	Text       string
+	Confidence float64
+	Source     string
+}
+
+// HoldingExtractor finds the holding or ratio decidendi of a judgment
+type HoldingExtractor struct {
+	styles       map[string]*HoldingStyle // keyed by lowercase jurisdiction
+	defaultStyle *HoldingStyle
+}
+
+// usHoldingStyle covers US opinions, which announce the holding and carry a
+// "Held:" line in the syllabus
+var usHoldingStyle = &HoldingStyle{
+	Name:               "us",
+	HoldingHeadings:    []string{"holding", "holdings", "held"},
+	ConclusionHeadings: []string{"conclusion", "conclusions", "disposition"},
+	Phrases:            []string{"we hold that", "we therefore hold", "we hold", "we conclude that"},
+	PhraseFromStart:    true,
+}
+
+// commonwealthHoldingStyle covers UK and Commonwealth judgments, which reason
+// towards a conclusion and disposal at the end
+var commonwealthHoldingStyle = &HoldingStyle{
+	Name:               "commonwealth",
+	HoldingHeadings:    []string{"ratio decidendi", "ratio", "holding", "held"},
+	ConclusionHeadings: []string{"conclusion", "conclusions", "disposal", "disposition", "decision", "determination", "outcome", "result"},
+	Phrases: []string{
+		"for these reasons", "for the reasons given", "i would hold that", "i hold that",
+		"i would dismiss", "i would allow", "we would dismiss", "we would allow",
+		"the appeal is dismissed", "the appeal is allowed",
+	},
+	PhraseFromStart: false,
+}
+
+var (
+	headingNumbering = regexp.MustCompile(`^(?:part\s+)?(?:[ivxlc]+|\d+|[a-z])[.)]\s*|^\(\w+\)\s*`)
+	paragraphBreak   = regexp.MustCompile(`\n\s*\n`)
+)
+
+// NewHoldingExtractor creates a holding extractor with US and Commonwealth styles
+func NewHoldingExtractor() *HoldingExtractor {
+	he := &HoldingExtractor{
+		styles:       make(map[string]*HoldingStyle),
+		defaultStyle: commonwealthHoldingStyle,
+	}
+	for _, j := range []string{"united states", "us", "usa", "united states of america"} {
+		he.styles[j] = usHoldingStyle
+	}
+	return he
+}
+
+// SetStyle sets the holding style used for a jurisdiction
+func (he *HoldingExtractor) SetStyle(jurisdiction string, style *HoldingStyle) {
+	he.styles[strings.ToLower(strings.TrimSpace(jurisdiction))] = style
+}
+
+// StyleFor returns the holding style for a jurisdiction
+func (he *HoldingExtractor) StyleFor(jurisdiction string) *HoldingStyle {
+	if style, ok := he.styles[strings.ToLower(strings.TrimSpace(jurisdiction))]; ok {
+		return style
+	}
+	return he.defaultStyle
+}
+
+// Extract finds the holding in a case's full text. Headed sections are
+// preferred, then holding phrases, then the closing paragraph.
+func (he *HoldingExtractor) Extract(c *models.Case) (HoldingMatch, bool) {
+	text := strings.ReplaceAll(c.FullText, "\r\n", "\n")
+	if strings.TrimSpace(text) == "" {
+		return HoldingMatch{}, false
+	}
+
+	style := he.StyleFor(c.Jurisdiction)
+
+	if body, ok := headedSection(text, style.HoldingHeadings); ok {
+		return HoldingMatch{Text: body, Confidence: 0.9, Source: HoldingSourceHeading}, true
+	}
+	if body, ok := headedSection(text, style.ConclusionHeadings); ok {
+		return HoldingMatch{Text: body, Confidence: 0.7, Source: HoldingSourceHeading}, true
+	}
+
+	paragraphs := splitParagraphs(text)
+	if p, ok := phraseParagraph(paragraphs, style); ok {
+		return HoldingMatch{Text: p, Confidence: 0.5, Source: HoldingSourcePhrase}, true
+	}
+
+	// Judgments usually end on their disposal
+	for i := len(paragraphs) - 1; i >= 0; i-- {
+		if len(strings.Fields(paragraphs[i])) >= 8 {
+			return HoldingMatch{Text: truncateHolding(paragraphs[i]), Confidence: 0.2, Source: HoldingSourcePosition}, true
+		}
+	}
+
+	return HoldingMatch{}, false
+}
+
+// ExtractInto stores the holding on the case, with its confidence and source in metadata
+func (he *HoldingExtractor) ExtractInto(c *models.Case) bool {
+	match, ok := he.Extract(c)
+	if !ok {
+		return false
+	}
+
+	c.Holding = match.Text
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	c.Metadata["holding_confidence"] = match.Confidence
+	c.Metadata["holding_source"] = match.Source
+	return true
+}
+
+// headedSection returns the text under the first heading matching one of
+// headings, up to the next heading. A heading may carry its text inline, as in
+// "Held: the appeal is dismissed", in which case the section ends with that
+// paragraph.
+func headedSection(text string, headings []string) (string, bool) {
+	lines := strings.Split(text, "\n")
+
+	for i, line := range lines {
+		inline, ok := matchHeading(line, headings)
+		if !ok {
+			continue
+		}
+
+		var body []string
+		if inline != "" {
+			body = append(body, inline)
+		}
+		for _, next := range lines[i+1:] {
+			if looksLikeHeading(next) || (inline != "" && strings.TrimSpace(next) == "") {
+				break
+			}
+			body = append(body, next)
+		}
+
+		if section := strings.TrimSpace(strings.Join(body, "\n")); section != "" {
+			return truncateHolding(section), true
+		}
+	}
+
+	return "", false
+}
+
+// matchHeading reports whether line is one of headings, optionally numbered
+// and followed by a colon and inline text, which is returned
+func matchHeading(line string, headings []string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	lower := strings.ToLower(trimmed)
+	lower = headingNumbering.ReplaceAllString(lower, "")
+
+	for _, h := range headings {
+		if !strings.HasPrefix(lower, h) {
+			continue
+		}
+		rest := lower[len(h):]
+
+		// A bare heading line, e.g. "III. CONCLUSION" or "Ratio decidendi"
+		if strings.TrimSpace(strings.TrimRight(rest, ":.")) == "" {
+			return "", true
+		}
+
+		// An inline heading, e.g. "Held: ..."
+		if strings.HasPrefix(rest, ":") {
+			offset := len(trimmed) - len(rest) + 1
+			return strings.TrimSpace(trimmed[offset:]), true
+		}
+	}
+
+	return "", false
+}
+
+// looksLikeHeading reports whether a line is a short title rather than prose
+func looksLikeHeading(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return false
+	}
+
+	words := strings.Fields(trimmed)
+	if len(words) > 6 || strings.ContainsAny(trimmed[len(trimmed)-1:], ".,;") {
+		return false
+	}
+
+	first := []rune(trimmed)[0]
+	return strings.ToUpper(string(first)) == string(first)
+}
+
+// phraseParagraph returns the paragraph with a holding phrase, the first one
+// for styles that state the holding up front and the last one otherwise
+func phraseParagraph(paragraphs []string, style *HoldingStyle) (string, bool) {
+	found := -1
+	for i, p := range paragraphs {
+		lower := strings.ToLower(p)
+		for _, phrase := range style.Phrases {
+			if strings.Contains(lower, phrase) {
+				found = i
+				break
+			}
+		}
+		if found == i && style.PhraseFromStart {
+			break
+		}
+	}
+
+	if found < 0 {
+		return "", false
+	}
+	return truncateHolding(paragraphs[found]), true
+}
+
+// splitParagraphs splits text on blank lines, or on line breaks when the text
+// has no blank lines
+func splitParagraphs(text string) []string {
+	parts := paragraphBreak.Split(text, -1)
+	if len(parts) == 1 {
+		parts = strings.Split(text, "\n")
+	}
+
+	var paragraphs []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs
+}
+
+// truncateHolding caps a holding at maxHoldingLength, cutting at a word boundary
+func truncateHolding(text string) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= maxHoldingLength {
+		return text
+	}
+
+	cut := strings.LastIndex(text[:maxHoldingLength], " ")
+	if cut <= 0 {
+		cut = maxHoldingLength
+	}
+	return strings.TrimSpace(text[:cut]) + "..."
+}
@@ -1,32 +1,86 @@
 package jurisdiction
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/opinions"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
 // MetadataEnricher handles jurisdiction-specific metadata enrichment
 type MetadataEnricher struct {
-	hierarchy *CourtHierarchy
-	rules     *JurisdictionRules
+	hierarchy  *CourtHierarchy
+	rules      *JurisdictionRules
+	holdings   *HoldingExtractor
+	catchwords *CatchwordsExtractor
+	headnotes  *HeadnoteExtractor
+	sections   *opinions.SectionParser
+	parties    *PartyNormalizer
+	metrics    *observability.Metrics
 }
 
 // NewMetadataEnricher creates a new metadata enricher
 func NewMetadataEnricher() *MetadataEnricher {
 	return &MetadataEnricher{
-		hierarchy: NewCourtHierarchy(),
-		rules:     NewJurisdictionRules(),
+		hierarchy:  NewCourtHierarchy(),
+		rules:      NewJurisdictionRules(),
+		holdings:   NewHoldingExtractor(),
+		catchwords: NewCatchwordsExtractor(),
+		headnotes:  NewHeadnoteExtractor(),
+		sections:   opinions.NewSectionParser(),
+		parties:    NewPartyNormalizer(),
 	}
 }
 
-// EnrichCase enriches a case with jurisdiction-specific metadata
+// SetSectionParser sets the parser that splits judgments into sections
+func (me *MetadataEnricher) SetSectionParser(parser *opinions.SectionParser) {
+	me.sections = parser
+}
+
+// SetHeadnoteExtractor sets the extractor that fills in missing summaries
+func (me *MetadataEnricher) SetHeadnoteExtractor(extractor *HeadnoteExtractor) {
+	me.headnotes = extractor
+}
+
+// SetPartyNormalizer sets the normalizer that tidies party names, types and
+// roles; nil leaves parties as scraped
+func (me *MetadataEnricher) SetPartyNormalizer(normalizer *PartyNormalizer) {
+	me.parties = normalizer
+}
+
+// SetMetrics sets the metrics that court levels inferred by default are counted in
+func (me *MetadataEnricher) SetMetrics(m *observability.Metrics) {
+	me.metrics = m
+}
+
+// SetDefaultCourtLevel sets the level given to courts the hierarchy doesn't know
+func (me *MetadataEnricher) SetDefaultCourtLevel(level models.CourtLevel) {
+	me.hierarchy.SetDefaultLevel(level)
+}
+
+// EnrichCase enriches a case with jurisdiction-specific metadata. The court
+// level is recorded with its confidence in metadata court_level_confidence;
+// a "default" level is a guess for a court the hierarchy doesn't know.
 func (me *MetadataEnricher) EnrichCase(c *models.Case) error {
-	// Determine court level
+	// Determine court level and canonical court identifier
+	var levelConfidence LevelConfidence
 	if c.Court != "" {
-		c.CourtLevel = me.hierarchy.GetCourtLevel(c.Court)
+		c.CourtLevel, levelConfidence = me.hierarchy.InferCourtLevel(c.Court)
+		if levelConfidence == LevelConfidenceDefault && me.metrics != nil {
+			me.metrics.RecordCourtLevelDefault(c.Jurisdiction)
+		}
+		if courtID, ok := me.hierarchy.ResolveCourtID(c.Court); ok {
+			c.CourtID = courtID
+		}
+	}
+
+	// Tidy party names and tag their types
+	if me.parties != nil {
+		me.parties.NormalizeInto(c)
 	}
 
 	// Determine court type
@@ -40,6 +94,9 @@ func (me *MetadataEnricher) EnrichCase(c *models.Case) error {
 	}
 	c.Metadata["case_type"] = caseType
 	c.Metadata["court_type"] = string(courtType)
+	if levelConfidence != "" {
+		c.Metadata["court_level_confidence"] = string(levelConfidence)
+	}
 
 	// Check if precedential
 	isPrecedential := me.hierarchy.IsPrecedential(c.Court)
@@ -54,6 +111,24 @@ func (me *MetadataEnricher) EnrichCase(c *models.Case) error {
 	// Apply jurisdiction-specific rules
 	me.ApplyJurisdictionRules(c)
 
+	// Extract the holding unless the source already provided one
+	if c.Holding == "" {
+		me.holdings.ExtractInto(c)
+	}
+
+	// Parse the catchwords block unless the source already provided them
+	if len(c.Catchwords) == 0 {
+		me.catchwords.ExtractInto(c)
+	}
+
+	// Derive a summary from the headnote or opening unless the source provided one
+	me.headnotes.ExtractInto(c)
+
+	// Split the judgment into sections unless the source already did
+	if len(c.Sections) == 0 {
+		me.sections.ParseInto(c)
+	}
+
 	return nil
 }
 
@@ -273,6 +348,11 @@ func (me *MetadataEnricher) ApplyJurisdictionRules(c *models.Case) {
 		return
 	}
 
+	// Tag the language when the source did not
+	if c.Language == "" {
+		c.Language = rules.DefaultLanguage
+	}
+
 	// Apply citation format validation
 	if rules.CitationPattern != "" {
 		matched, _ := regexp.MatchString(rules.CitationPattern, c.CaseNumber)
@@ -350,6 +430,29 @@ type RuleSet struct {
 	MaxDate         *time.Time `yaml:"max_date,omitempty"`
 	RequiredFields  []string  `yaml:"required_fields,omitempty"`
 	CourtAbbreviations map[string]string `yaml:"court_abbreviations,omitempty"`
+	DefaultLanguage string    `yaml:"default_language,omitempty"`
+	KnownReporters  []string  `yaml:"known_reporters,omitempty"` // empty accepts any reporter
+}
+
+// IsKnownReporter reports whether reporter is one of the jurisdiction's known
+// reporters, ignoring case, spacing and periods. Rule sets without known
+// reporters accept every reporter.
+func (rs *RuleSet) IsKnownReporter(reporter string) bool {
+	if len(rs.KnownReporters) == 0 {
+		return true
+	}
+	key := reporterKey(reporter)
+	for _, known := range rs.KnownReporters {
+		if reporterKey(known) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// reporterKey reduces a reporter abbreviation to a comparable form
+func reporterKey(reporter string) string {
+	return strings.ToLower(strings.NewReplacer(".", "", " ", "").Replace(reporter))
 }
 
 // NewJurisdictionRules creates a new jurisdiction rules system
@@ -369,6 +472,12 @@ func (jr *JurisdictionRules) initializeDefaults() {
 		CitationPattern: `\d+\s+[A-Z]\.?\s*\d+`,
 		DateFormat:      "2006-01-02",
 		RequiredFields:  []string{"case_number", "court"},
+		DefaultLanguage: "en",
+		KnownReporters: []string{
+			"U.S.", "S. Ct.", "L. Ed.", "L. Ed. 2d",
+			"F.", "F.2d", "F.3d", "F.4th",
+			"F. Supp.", "F. Supp. 2d", "F. Supp. 3d", "F. App'x",
+		},
 	}
 
 	// United Kingdom
@@ -377,6 +486,11 @@ func (jr *JurisdictionRules) initializeDefaults() {
 		CitationPattern: `\[\d{4}\]\s+[A-Z]+\s+\d+`,
 		DateFormat:      "2006-01-02",
 		RequiredFields:  []string{"case_number", "court"},
+		DefaultLanguage: "en",
+		KnownReporters: []string{
+			"A.C.", "Q.B.", "K.B.", "Ch.", "Fam.",
+			"W.L.R.", "All E.R.", "Cr. App. R.", "Lloyd's Rep.",
+		},
 	}
 
 	// Canada
@@ -385,6 +499,10 @@ func (jr *JurisdictionRules) initializeDefaults() {
 		CitationPattern: `\d{4}\s+[A-Z]+\s+\d+`,
 		DateFormat:      "2006-01-02",
 		RequiredFields:  []string{"case_number", "court"},
+		DefaultLanguage: "en",
+		KnownReporters: []string{
+			"S.C.R.", "F.C.", "D.L.R.", "O.R.", "B.C.L.R.", "C.C.C.",
+		},
 	}
 
 	// Australia
@@ -393,6 +511,10 @@ func (jr *JurisdictionRules) initializeDefaults() {
 		CitationPattern: `\[\d{4}\]\s+[A-Z]+\s+\d+`,
 		DateFormat:      "2006-01-02",
 		RequiredFields:  []string{"case_number", "court"},
+		DefaultLanguage: "en",
+		KnownReporters: []string{
+			"CLR", "ALR", "ALJR", "FCR", "NSWLR", "VR", "Qd R",
+		},
 	}
 
 	// India
@@ -400,6 +522,10 @@ func (jr *JurisdictionRules) initializeDefaults() {
 		Jurisdiction:    "India",
 		DateFormat:      "02.01.2006",
 		RequiredFields:  []string{"case_number"},
+		DefaultLanguage: "en",
+		KnownReporters: []string{
+			"SCC", "AIR", "SCR", "SCALE",
+		},
 	}
 }
 
@@ -408,6 +534,23 @@ func (jr *JurisdictionRules) GetRulesForJurisdiction(jurisdiction string) *RuleS
 	return jr.rules[jurisdiction]
 }
 
+// ValidateCitation checks a citation made in a case from jurisdiction against
+// that jurisdiction's known reporters, recording the outcome on the citation.
+// Citations without a reporter, such as neutral citations, and jurisdictions
+// without rules are accepted.
+func (jr *JurisdictionRules) ValidateCitation(jurisdiction string, citation *models.Citation) bool {
+	rules := jr.GetRulesForJurisdiction(jurisdiction)
+	if citation.Reporter != "" && rules != nil && !rules.IsKnownReporter(citation.Reporter) {
+		citation.IsValid = false
+		citation.ValidationError = fmt.Sprintf("unknown reporter %q for %s", citation.Reporter, jurisdiction)
+		return false
+	}
+
+	citation.IsValid = true
+	citation.ValidationError = ""
+	return true
+}
+
 // AddRules adds custom rules for a jurisdiction
 func (jr *JurisdictionRules) AddRules(rules *RuleSet) {
 	jr.rules[rules.Jurisdiction] = rules
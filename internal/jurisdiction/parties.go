@@ -0,0 +1,234 @@
+package jurisdiction
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// Party types detected from party names, as stored in models.Party.Type
+const (
+	PartyTypeIndividual  = "individual"
+	PartyTypeCorporation = "corporation"
+	PartyTypeGovernment  = "government"
+)
+
+var (
+	// defaultCompanySuffixes end the names of companies and firms, as in
+	// "Acme Pty Ltd" or "Widget Holdings, Inc."
+	defaultCompanySuffixes = []string{
+		"ltd", "limited", "inc", "incorporated", "corp", "corporation", "co", "company",
+		"llc", "llp", "lp", "plc", "pty", "pte", "bhd", "gmbh", "ag", "sa", "nv", "bv",
+	}
+
+	// defaultGovernmentKeywords mark the Crown, states and public officers
+	// and bodies, as in "Regina", "Commissioner of Taxation" or "Minister for
+	// Immigration". The Crown's "R" is matched separately, as a single letter
+	// is also an initial.
+	defaultGovernmentKeywords = []string{
+		"regina", "rex", "the queen", "the king", "the crown", "his majesty", "her majesty",
+		"commissioner", "commission", "minister", "secretary of state", "attorney-general",
+		"attorney general", "director of public prosecutions", "public prosecutor", "solicitor-general",
+		"commonwealth of", "state of", "government", "department", "council", "united states",
+		"people of", "police", "crown prosecution service",
+	}
+
+	// partyRoles maps role spellings to their canonical form
+	partyRoles = map[string]string{
+		"appellants":  "appellant",
+		"respondents": "respondent",
+		"plaintiffs":  "plaintiff",
+		"defendants":  "defendant",
+		"applicants":  "applicant",
+		"petitioners": "petitioner",
+		"claimants":   "claimant",
+		"appellees":   "appellee",
+		"pltf":        "plaintiff",
+		"deft":        "defendant",
+		"interveners": "intervener",
+		"intervenor":  "intervener",
+		"intervenors": "intervener",
+	}
+
+	// lowerPartyWords stay lower case inside a title-cased party name
+	lowerPartyWords = map[string]bool{
+		"of": true, "and": true, "the": true, "for": true, "in": true, "on": true,
+		"v": true, "de": true, "van": true, "von": true, "der": true,
+	}
+
+	// upperPartyWords are written in capitals in a title-cased party name
+	upperPartyWords = map[string]bool{
+		"llc": true, "llp": true, "lp": true, "plc": true, "ag": true, "sa": true,
+		"nv": true, "bv": true, "usa": true, "uk": true, "nsw": true, "ii": true, "iii": true,
+	}
+)
+
+// PartyNormalizer tidies the parties scrapers report: it title-cases names
+// written all in capitals or all in lower case, detects whether each party is
+// an individual, a corporation or government from its name, and gives roles
+// one spelling. Types reported by the source are kept.
+type PartyNormalizer struct {
+	titleCase          bool
+	companySuffixes    map[string]bool
+	governmentKeywords []string
+}
+
+// NewPartyNormalizer creates a party normalizer with the default suffixes and keywords
+func NewPartyNormalizer() *PartyNormalizer {
+	pn := &PartyNormalizer{
+		titleCase:       true,
+		companySuffixes: make(map[string]bool),
+	}
+	pn.AddCompanySuffixes(defaultCompanySuffixes...)
+	pn.AddGovernmentKeywords(defaultGovernmentKeywords...)
+	return pn
+}
+
+// SetTitleCase sets whether names written all in one case are title-cased
+func (pn *PartyNormalizer) SetTitleCase(enabled bool) {
+	pn.titleCase = enabled
+}
+
+// AddCompanySuffixes adds words that end a company's name, matched case-insensitively
+func (pn *PartyNormalizer) AddCompanySuffixes(suffixes ...string) {
+	for _, s := range suffixes {
+		if s = strings.Join(partyWords(s), ""); s != "" {
+			pn.companySuffixes[s] = true
+		}
+	}
+}
+
+// AddGovernmentKeywords adds words or phrases that mark a government party,
+// matched case-insensitively as whole words
+func (pn *PartyNormalizer) AddGovernmentKeywords(keywords ...string) {
+	for _, k := range keywords {
+		if k = strings.Join(partyWords(k), " "); k != "" {
+			pn.governmentKeywords = append(pn.governmentKeywords, k)
+		}
+	}
+}
+
+// Normalize returns a normalized copy of a party
+func (pn *PartyNormalizer) Normalize(p models.Party) models.Party {
+	p.Name = strings.Join(strings.Fields(p.Name), " ")
+	if pn.titleCase && isSingleCase(p.Name) {
+		p.Name = titleCaseParty(p.Name)
+	}
+
+	role := strings.ToLower(strings.TrimSpace(p.Role))
+	if canonical, ok := partyRoles[role]; ok {
+		role = canonical
+	}
+	p.Role = role
+
+	if p.Type == "" {
+		p.Type = pn.DetectType(p.Name)
+	} else {
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+	}
+	return p
+}
+
+// NormalizeInto normalizes the case's parties in place, reporting whether any changed
+func (pn *PartyNormalizer) NormalizeInto(c *models.Case) bool {
+	changed := false
+	for i, p := range c.Parties {
+		normalized := pn.Normalize(p)
+		if normalized.Name != p.Name || normalized.Role != p.Role || normalized.Type != p.Type {
+			c.Parties[i] = normalized
+			changed = true
+		}
+	}
+	return changed
+}
+
+// DetectType guesses a party's type from its name. A company suffix wins
+// over a government keyword, so "Commonwealth Bank of Australia Ltd" is a
+// corporation; names with neither are individuals.
+func (pn *PartyNormalizer) DetectType(name string) string {
+	words := partyWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+
+	if len(words) > 1 && pn.companySuffixes[words[len(words)-1]] {
+		return PartyTypeCorporation
+	}
+
+	// "R", alone or as in "R (on the application of Smith)"
+	if words[0] == "r" && (len(words) == 1 || strings.HasPrefix(strings.TrimSpace(name)[1:], " (")) {
+		return PartyTypeGovernment
+	}
+
+	joined := " " + strings.Join(words, " ") + " "
+	for _, keyword := range pn.governmentKeywords {
+		if strings.Contains(joined, " "+keyword+" ") {
+			return PartyTypeGovernment
+		}
+	}
+
+	return PartyTypeIndividual
+}
+
+// partyWords splits a name into lower-case words, dropping full stops and
+// splitting on other punctuation but hyphens and ampersands, so "Widget Co.,
+// Inc." gives widget, co, inc and "S.p.A." gives spa
+func partyWords(name string) []string {
+	name = strings.ReplaceAll(strings.ToLower(name), ".", "")
+	return strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '&'
+	})
+}
+
+// isSingleCase reports whether a name has letters and all of them are
+// capitals, or all lower case
+func isSingleCase(name string) bool {
+	hasLetter := false
+	for _, r := range name {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+	return hasLetter && (name == strings.ToUpper(name) || name == strings.ToLower(name))
+}
+
+// titleCaseParty title-cases a name, keeping joining words in lower case and
+// abbreviations such as PLC in capitals
+func titleCaseParty(name string) string {
+	words := strings.Fields(strings.ToLower(name))
+	for i, w := range words {
+		bare := strings.Trim(w, ".,()")
+		switch {
+		case upperPartyWords[bare]:
+			words[i] = strings.ToUpper(w)
+		case i > 0 && lowerPartyWords[bare]:
+			continue
+		default:
+			words[i] = capitalizeParts(w)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalizeParts capitalizes each hyphen or apostrophe separated part of a
+// word, and the letter after a "Mc" prefix, as in "O'Brien-McDonald"
+func capitalizeParts(word string) string {
+	r := []rune(word)
+	start := true
+	for i, c := range r {
+		if !unicode.IsLetter(c) {
+			start = c == '-' || c == '\'' || c == '(' || c == '.'
+			continue
+		}
+		if start {
+			r[i] = unicode.ToUpper(c)
+			start = false
+			if c == 'm' && i+3 < len(r) && r[i+1] == 'c' && unicode.IsLetter(r[i+2]) {
+				r[i+2] = unicode.ToUpper(r[i+2])
+			}
+		}
+	}
+	return string(r)
+}
@@ -2,6 +2,7 @@ package observability
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,6 +22,9 @@ type Metrics struct {
 	ScrapingDuration     *prometheus.HistogramVec
 	ScrapingErrors       *prometheus.CounterVec
 	CasesScraped         *prometheus.CounterVec
+	CasesUnchanged       *prometheus.CounterVec
+	CasesArchived        *prometheus.CounterVec
+	ExtractionFallbacks  *prometheus.CounterVec
 	ScrapingQueueDepth   prometheus.Gauge
 
 	// Worker metrics
@@ -28,6 +32,8 @@ type Metrics struct {
 	WorkerJobsProcessed  *prometheus.CounterVec
 	WorkerJobDuration    *prometheus.HistogramVec
 	WorkerJobErrors      *prometheus.CounterVec
+	WorkerDrainDuration  *prometheus.HistogramVec
+	WorkerJobsRequeued   prometheus.Counter
 
 	// Queue metrics
 	QueueDepth           *prometheus.GaugeVec
@@ -54,6 +60,7 @@ type Metrics struct {
 	ValidationTotal      *prometheus.CounterVec
 	ValidationErrors     *prometheus.CounterVec
 	QualityScores        *prometheus.HistogramVec
+	CourtLevelDefaults   *prometheus.CounterVec
 
 	// Search metrics
 	SearchQueriesTotal   *prometheus.CounterVec
@@ -117,6 +124,27 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"jurisdiction", "source"},
 		),
+		CasesUnchanged: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kite_cases_unchanged_total",
+				Help: "Total number of re-scraped cases not saved because their content was unchanged",
+			},
+			[]string{"jurisdiction", "source"},
+		),
+		CasesArchived: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kite_cases_archived_total",
+				Help: "Total number of scraped cases sent to the raw JSON archive, by outcome",
+			},
+			[]string{"source", "status"},
+		),
+		ExtractionFallbacks: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kite_extraction_fallbacks_total",
+				Help: "Total number of case fields extracted, by the fallback level that found them (0 for the primary selector)",
+			},
+			[]string{"source", "field", "level"},
+		),
 		ScrapingQueueDepth: promauto.NewGauge(
 			prometheus.GaugeOpts{
 				Name: "kite_scraping_queue_depth",
@@ -153,6 +181,20 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"job_type", "error_type"},
 		),
+		WorkerDrainDuration: promauto.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "kite_worker_drain_duration_seconds",
+				Help:    "Time taken to drain in-flight jobs on worker pool shutdown",
+				Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60},
+			},
+			[]string{"outcome"},
+		),
+		WorkerJobsRequeued: promauto.NewCounter(
+			prometheus.CounterOpts{
+				Name: "kite_worker_jobs_requeued_total",
+				Help: "Total number of in-flight jobs requeued because shutdown timed out",
+			},
+		),
 
 		// Queue metrics
 		QueueDepth: promauto.NewGaugeVec(
@@ -275,6 +317,13 @@ func NewMetrics() *Metrics {
 			},
 			[]string{"model_type"},
 		),
+		CourtLevelDefaults: promauto.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "kite_court_level_defaults_total",
+				Help: "Total number of cases whose court was unknown and given the default court level",
+			},
+			[]string{"jurisdiction"},
+		),
 
 		// Search metrics
 		SearchQueriesTotal: promauto.NewCounterVec(
@@ -320,6 +369,29 @@ func (m *Metrics) RecordScraping(jurisdiction, source, status string, duration t
 	}
 }
 
+// RecordUnchangedCases records re-scraped cases skipped as unchanged
+func (m *Metrics) RecordUnchangedCases(jurisdiction, source string, count int) {
+	if count > 0 {
+		m.CasesUnchanged.WithLabelValues(jurisdiction, source).Add(float64(count))
+	}
+}
+
+// RecordArchivedCase records a scraped case written to, failed or dropped from
+// the raw JSON archive
+func (m *Metrics) RecordArchivedCase(source, status string) {
+	m.CasesArchived.WithLabelValues(source, status).Inc()
+}
+
+// RecordExtractionFallback records a case field extracted at a fallback level
+func (m *Metrics) RecordExtractionFallback(source, field string, level int) {
+	m.ExtractionFallbacks.WithLabelValues(source, field, strconv.Itoa(level)).Inc()
+}
+
+// RecordStorageError records a failed storage operation
+func (m *Metrics) RecordStorageError(operation, errorType string) {
+	m.StorageErrors.WithLabelValues(operation, errorType).Inc()
+}
+
 // RecordScrapingError records a scraping error
 func (m *Metrics) RecordScrapingError(jurisdiction, source, errorType string) {
 	m.ScrapingErrors.WithLabelValues(jurisdiction, source, errorType).Inc()
@@ -331,6 +403,22 @@ func (m *Metrics) RecordWorkerJob(workerID string, jobType, status string, durat
 	m.WorkerJobDuration.WithLabelValues(jobType).Observe(duration.Seconds())
 }
 
+// RecordWorkerDrain records how long a worker pool took to drain on shutdown.
+// Outcome is "drained" when every in-flight job finished, or "timeout" when
+// the remaining jobs were cancelled and requeued.
+func (m *Metrics) RecordWorkerDrain(outcome string, duration time.Duration, requeued int) {
+	m.WorkerDrainDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+	if requeued > 0 {
+		m.WorkerJobsRequeued.Add(float64(requeued))
+	}
+}
+
+// RecordCourtLevelDefault records a case from an unknown court given the
+// default court level, a gap in the court hierarchy
+func (m *Metrics) RecordCourtLevelDefault(jurisdiction string) {
+	m.CourtLevelDefaults.WithLabelValues(jurisdiction).Inc()
+}
+
 // RecordSearchQuery records a search query metric
 func (m *Metrics) RecordSearchQuery(duration time.Duration, resultCount int) {
 	queryType := "fulltext" // Default type
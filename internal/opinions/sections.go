@@ -0,0 +1,226 @@
+package opinions
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// Section labels
+const (
+	SectionIntroduction = "introduction"
+	SectionFacts        = "facts"
+	SectionIssues       = "issues"
+	SectionReasoning    = "reasoning"
+	SectionDisposition  = "disposition"
+)
+
+const (
+	// maxHeadingWords bounds how long a line may be and still be a heading
+	maxHeadingWords = 8
+
+	// headingPunctuation is trimmed from the end of a heading, as in "Facts:"
+	headingPunctuation = ":.-–— "
+)
+
+// headingNumbering strips "III.", "2)", "(a)" and "Part B." from headings
+var headingNumbering = regexp.MustCompile(`^(?i:part\s+)?(?:(?i:[ivxlc]+)|\d+|[A-Za-z])[.)]\s*|^\(\w+\)\s*`)
+
+// SectionStyle holds the heading patterns that open each section of a
+// judgment in one drafting tradition
+type SectionStyle struct {
+	Name     string
+	sections []sectionHeadings
+}
+
+// sectionHeadings are the compiled heading patterns of one section
+type sectionHeadings struct {
+	label    string
+	patterns []*regexp.Regexp
+}
+
+// standardSections fixes the order headings are tried in, so a heading
+// matching patterns of two sections is labelled consistently. Labels beyond
+// these are tried afterwards, in alphabetical order.
+var standardSections = []string{SectionIntroduction, SectionFacts, SectionIssues, SectionReasoning, SectionDisposition}
+
+// NewSectionStyle compiles heading patterns, keyed by section label, into a
+// style. Patterns are regular expressions matched case-insensitively against
+// the whole heading, once numbering and trailing punctuation are stripped.
+func NewSectionStyle(name string, headings map[string][]string) (*SectionStyle, error) {
+	labels := make([]string, 0, len(headings))
+	for _, label := range standardSections {
+		if _, ok := headings[label]; ok {
+			labels = append(labels, label)
+		}
+	}
+	var custom []string
+	for label := range headings {
+		if !isStandardSection(label) {
+			custom = append(custom, label)
+		}
+	}
+	sort.Strings(custom)
+	labels = append(labels, custom...)
+
+	style := &SectionStyle{Name: name}
+	for _, label := range labels {
+		section := sectionHeadings{label: label}
+		for _, pattern := range headings[label] {
+			re, err := regexp.Compile(`^(?i:` + pattern + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("invalid heading pattern %q for section %s: %w", pattern, label, err)
+			}
+			section.patterns = append(section.patterns, re)
+		}
+		style.sections = append(style.sections, section)
+	}
+
+	return style, nil
+}
+
+// isStandardSection reports whether label is one of the standard sections
+func isStandardSection(label string) bool {
+	for _, standard := range standardSections {
+		if label == standard {
+			return true
+		}
+	}
+	return false
+}
+
+// mustSectionStyle compiles a built-in style
+func mustSectionStyle(name string, headings map[string][]string) *SectionStyle {
+	style, err := NewSectionStyle(name, headings)
+	if err != nil {
+		panic(err)
+	}
+	return style
+}
+
+// commonwealthSectionStyle covers UK and Commonwealth judgments
+var commonwealthSectionStyle = mustSectionStyle("commonwealth", map[string][]string{
+	SectionIntroduction: {`introduction`, `overview`, `preliminary( matters)?`, `the (application|appeal|proceedings)`},
+	SectionFacts:        {`(the )?facts`, `(the )?(factual )?background( facts)?`, `(the )?material facts`, `facts and (procedural )?history`},
+	SectionIssues:       {`(the )?issues?( (for|to be) determin(ed|ation)| on appeal| arising)?`, `(the )?questions?( for (decision|determination))?`, `(the )?grounds of appeal`},
+	SectionReasoning:    {`(the )?(analysis|discussion|reasoning|reasons)( and (analysis|decision))?`, `consideration`, `(my|our) (analysis|decision|assessment)`, `(the )?law`},
+	SectionDisposition:  {`conclusions?`, `disposal`, `disposition`, `orders?`, `(the )?(outcome|result|decision)`},
+})
+
+// usSectionStyle covers US opinions
+var usSectionStyle = mustSectionStyle("us", map[string][]string{
+	SectionIntroduction: {`introduction`, `overview`},
+	SectionFacts:        {`(the )?facts`, `(factual )?background`, `(factual and )?procedural (history|background)`, `statement of (the )?(facts|case)`},
+	SectionIssues:       {`(the )?(questions?|issues?) presented`, `(the )?issues?`},
+	SectionReasoning:    {`discussion`, `analysis`, `standard of review`, `reasoning`},
+	SectionDisposition:  {`conclusion`, `disposition`},
+})
+
+// SectionParser segments judgments into labelled sections using the heading
+// style of their jurisdiction
+type SectionParser struct {
+	styles       map[string]*SectionStyle // keyed by lowercase jurisdiction
+	defaultStyle *SectionStyle
+}
+
+// NewSectionParser creates a section parser with US and Commonwealth styles
+func NewSectionParser() *SectionParser {
+	sp := &SectionParser{
+		styles:       make(map[string]*SectionStyle),
+		defaultStyle: commonwealthSectionStyle,
+	}
+	for _, j := range []string{"united states", "us", "usa", "united states of america"} {
+		sp.styles[j] = usSectionStyle
+	}
+	return sp
+}
+
+// SetStyle sets the section style used for a jurisdiction
+func (sp *SectionParser) SetStyle(jurisdiction string, style *SectionStyle) {
+	sp.styles[strings.ToLower(strings.TrimSpace(jurisdiction))] = style
+}
+
+// StyleFor returns the section style for a jurisdiction
+func (sp *SectionParser) StyleFor(jurisdiction string) *SectionStyle {
+	if style, ok := sp.styles[strings.ToLower(strings.TrimSpace(jurisdiction))]; ok {
+		return style
+	}
+	return sp.defaultStyle
+}
+
+// Parse splits a judgment into sections keyed by label. Each section runs
+// from its heading to the next recognised heading, so subheadings stay in
+// their section, and a section headed more than once is joined up. Text before
+// the first heading is the introduction unless the judgment heads one itself.
+// Judgments without recognised headings have no sections.
+func (sp *SectionParser) Parse(text, jurisdiction string) map[string]string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	style := sp.StyleFor(jurisdiction)
+
+	parts := make(map[string][]string)
+	var preamble []string
+	current := ""
+	for _, line := range strings.Split(text, "\n") {
+		if section, ok := style.match(line); ok {
+			current = section
+			continue
+		}
+		if current == "" {
+			preamble = append(preamble, line)
+		} else {
+			parts[current] = append(parts[current], line)
+		}
+	}
+
+	if current == "" {
+		return nil
+	}
+	if _, ok := parts[SectionIntroduction]; !ok {
+		parts[SectionIntroduction] = preamble
+	}
+
+	sections := make(map[string]string, len(parts))
+	for section, lines := range parts {
+		if body := strings.TrimSpace(strings.Join(lines, "\n")); body != "" {
+			sections[section] = body
+		}
+	}
+	return sections
+}
+
+// ParseInto stores the sections of a case's full text on the case, reporting
+// whether any were found
+func (sp *SectionParser) ParseInto(c *models.Case) bool {
+	sections := sp.Parse(c.FullText, c.Jurisdiction)
+	if len(sections) == 0 {
+		return false
+	}
+	c.Sections = sections
+	return true
+}
+
+// match returns the section a heading line opens
+func (s *SectionStyle) match(line string) (string, bool) {
+	heading := strings.TrimSpace(line)
+	if heading == "" || len(strings.Fields(heading)) > maxHeadingWords {
+		return "", false
+	}
+	heading = headingNumbering.ReplaceAllString(heading, "")
+	heading = strings.Join(strings.Fields(strings.TrimRight(heading, headingPunctuation)), " ")
+	if heading == "" {
+		return "", false
+	}
+
+	for _, section := range s.sections {
+		for _, re := range section.patterns {
+			if re.MatchString(heading) {
+				return section.label, true
+			}
+		}
+	}
+
+	return "", false
+}
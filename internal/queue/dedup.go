@@ -0,0 +1,209 @@
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultDedupWindow is how long an enqueued job suppresses identical jobs
+const DefaultDedupWindow = 10 * time.Minute
+
+// DedupStore remembers job fingerprints for a short window
+type DedupStore interface {
+	// Claim records jobID under key for ttl. If the key is already held it
+	// returns the ID of the job holding it and claimed is false.
+	Claim(ctx context.Context, key, jobID string, ttl time.Duration) (holder string, claimed bool, err error)
+	// Release drops the claim on key if jobID still holds it
+	Release(ctx context.Context, key, jobID string) error
+}
+
+// JobFingerprint identifies the work a job does: its operation, source and
+// target case (case_id or url payload). Jobs without a target case return ""
+// and are never deduplicated.
+func JobFingerprint(job *Job) string {
+	target := payloadString(job.Payload, "case_id", "url")
+	if target == "" {
+		return ""
+	}
+	source := payloadString(job.Payload, "source", "jurisdiction")
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s", job.Type, source, target)))
+	return hex.EncodeToString(sum[:])
+}
+
+// payloadString returns the first non-empty string payload value among keys
+func payloadString(payload map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := payload[key].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// DedupQueue wraps a Queue and coalesces identical jobs enqueued within the
+// dedup window. A duplicate is not enqueued; instead its ID is set to the ID
+// of the pending job so callers can poll that job's status.
+type DedupQueue struct {
+	Queue
+	store  DedupStore
+	window time.Duration
+}
+
+// NewDedupQueue wraps q with enqueue-time deduplication. A window of zero or
+// less disables deduplication and returns q unchanged.
+func NewDedupQueue(q Queue, store DedupStore, window time.Duration) Queue {
+	if window <= 0 {
+		return q
+	}
+	if store == nil {
+		store = NewMemoryDedupStore()
+	}
+	return &DedupQueue{
+		Queue:  q,
+		store:  store,
+		window: window,
+	}
+}
+
+// Enqueue adds the job unless an identical job was enqueued within the window.
+// Replayed jobs are always enqueued.
+func (dq *DedupQueue) Enqueue(ctx context.Context, job *Job) error {
+	fingerprint := JobFingerprint(job)
+	if fingerprint == "" || job.ReplayCount > 0 {
+		return dq.Queue.Enqueue(ctx, job)
+	}
+
+	key := "kite:dedup:" + fingerprint
+	holder, claimed, err := dq.store.Claim(ctx, key, job.ID, dq.window)
+	if err != nil {
+		return fmt.Errorf("failed to check job fingerprint: %w", err)
+	}
+	if !claimed {
+		job.ID = holder
+		return nil
+	}
+
+	if err := dq.Queue.Enqueue(ctx, job); err != nil {
+		// Nothing was enqueued, so the fingerprint must not suppress retries
+		if releaseErr := dq.store.Release(context.WithoutCancel(ctx), key, job.ID); releaseErr != nil {
+			return fmt.Errorf("%w (releasing job fingerprint: %v)", err, releaseErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// GetDLQ returns the wrapped queue's dead letter queue, or nil if it has none
+func (dq *DedupQueue) GetDLQ() DeadLetterQueue {
+	if provider, ok := dq.Queue.(DLQProvider); ok {
+		return provider.GetDLQ()
+	}
+	return nil
+}
+
+// MemoryDedupStore is an in-process DedupStore
+type MemoryDedupStore struct {
+	mu      sync.Mutex
+	entries map[string]dedupEntry
+}
+
+type dedupEntry struct {
+	jobID     string
+	expiresAt time.Time
+}
+
+// NewMemoryDedupStore creates a new MemoryDedupStore
+func NewMemoryDedupStore() *MemoryDedupStore {
+	return &MemoryDedupStore{
+		entries: make(map[string]dedupEntry),
+	}
+}
+
+// Claim records jobID under key unless an unexpired entry exists
+func (s *MemoryDedupStore) Claim(ctx context.Context, key, jobID string, ttl time.Duration) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range s.entries {
+		if now.After(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+
+	if e, ok := s.entries[key]; ok {
+		return e.jobID, false, nil
+	}
+
+	s.entries[key] = dedupEntry{jobID: jobID, expiresAt: now.Add(ttl)}
+	return jobID, true, nil
+}
+
+// Release drops the entry for key if jobID holds it
+func (s *MemoryDedupStore) Release(ctx context.Context, key, jobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if e, ok := s.entries[key]; ok && e.jobID == jobID {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// releaseClaimScript deletes KEYS[1] only while it still holds ARGV[1], so a
+// claim taken over after expiry is left alone
+var releaseClaimScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisDedupStore is a DedupStore shared by every process using the same Redis
+type RedisDedupStore struct {
+	client *redis.Client
+}
+
+// NewRedisDedupStore creates a DedupStore backed by SET NX with expiry
+func NewRedisDedupStore(client *redis.Client) *RedisDedupStore {
+	return &RedisDedupStore{client: client}
+}
+
+// Claim records jobID under key unless the key already exists
+func (s *RedisDedupStore) Claim(ctx context.Context, key, jobID string, ttl time.Duration) (string, bool, error) {
+	claimed, err := s.client.SetNX(ctx, key, jobID, ttl).Result()
+	if err != nil {
+		return "", false, err
+	}
+	if claimed {
+		return jobID, true, nil
+	}
+
+	holder, err := s.client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		// Expired between SETNX and GET; treat as a fresh claim
+		return s.Claim(ctx, key, jobID, ttl)
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return holder, false, nil
+}
+
+// Release deletes key if jobID still holds it
+func (s *RedisDedupStore) Release(ctx context.Context, key, jobID string) error {
+	return releaseClaimScript.Run(ctx, s.client, []string{key}, jobID).Err()
+}
+
+// DedupStore returns a DedupStore sharing this queue's Redis connection
+func (rq *RedisQueue) DedupStore() DedupStore {
+	return NewRedisDedupStore(rq.client)
+}
@@ -0,0 +1,175 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/redis/go-redis/v9"
+)
+
+// QueueStatus is a live snapshot of a queue read from its backend
+type QueueStatus struct {
+	Backend    string `json:"backend"`
+	Name       string `json:"name"`
+	Depth      int64  `json:"depth"`
+	InFlight   int64  `json:"in_flight"`
+	DeadLetter int64  `json:"dead_letter"`
+
+	// OldestMessageAge is zero when the queue is empty
+	OldestMessageAge time.Duration `json:"oldest_message_age"`
+}
+
+// Inspector is implemented by queues that can report live status from their backend
+type Inspector interface {
+	Inspect(ctx context.Context) ([]QueueStatus, error)
+}
+
+// Inspect reports queued, running and dead-lettered jobs
+func (mq *MemoryQueue) Inspect(ctx context.Context) ([]QueueStatus, error) {
+	mq.mu.RLock()
+	defer mq.mu.RUnlock()
+
+	status := QueueStatus{
+		Backend:    "memory",
+		Name:       "jobs",
+		Depth:      int64(len(mq.jobs)),
+		DeadLetter: int64(mq.dlq.GetSize()),
+	}
+
+	for _, job := range mq.jobsMap {
+		if job.Status == JobStatusRunning {
+			status.InFlight++
+		}
+	}
+
+	now := time.Now()
+	for _, job := range mq.jobs {
+		if age := now.Sub(job.CreatedAt); age > status.OldestMessageAge {
+			status.OldestMessageAge = age
+		}
+	}
+
+	return []QueueStatus{status}, nil
+}
+
+// RedisStreamReader is the subset of the Redis client used to inspect streams
+type RedisStreamReader interface {
+	XLen(ctx context.Context, stream string) *redis.IntCmd
+	XInfoGroups(ctx context.Context, stream string) *redis.XInfoGroupsCmd
+	XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd
+}
+
+// InspectRedisStreams reports the entries claimed by the consumer group but
+// not yet acknowledged (XINFO GROUPS pending), the entries waiting for
+// delivery, the dead letter stream's length and the age of the oldest entry in
+// the job stream. RedisQueue deletes entries once they are acknowledged, so
+// the waiting entries are the stream length (XLEN) less the pending ones. The
+// group's lag is not used because Redis stops reporting it once entries have
+// been deleted.
+func InspectRedisStreams(ctx context.Context, client RedisStreamReader, stream, group, dlqStream string) ([]QueueStatus, error) {
+	status := QueueStatus{
+		Backend: "redis",
+		Name:    stream,
+	}
+
+	length, err := client.XLen(ctx, stream).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream length: %w", err)
+	}
+
+	// An empty or missing stream has nothing pending, and XINFO on a missing
+	// stream is an error
+	if length > 0 {
+		groups, err := client.XInfoGroups(ctx, stream).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read consumer groups: %w", err)
+		}
+		for _, g := range groups {
+			if g.Name == group {
+				status.InFlight = g.Pending
+			}
+		}
+	}
+	status.Depth = length - status.InFlight
+	if status.Depth < 0 {
+		status.Depth = 0
+	}
+
+	if dlqStream != "" {
+		dead, err := client.XLen(ctx, dlqStream).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter stream length: %w", err)
+		}
+		status.DeadLetter = dead
+	}
+
+	oldest, err := client.XRangeN(ctx, stream, "-", "+", 1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oldest entry: %w", err)
+	}
+	if len(oldest) > 0 {
+		if enqueued, ok := streamIDTime(oldest[0].ID); ok {
+			status.OldestMessageAge = time.Since(enqueued)
+		}
+	}
+
+	return []QueueStatus{status}, nil
+}
+
+// streamIDTime returns the time encoded in a Redis stream entry ID ("<ms>-<seq>")
+func streamIDTime(id string) (time.Time, bool) {
+	ms, err := strconv.ParseInt(strings.SplitN(id, "-", 2)[0], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+// Inspect reports the job stream's status from Redis
+func (rq *RedisQueue) Inspect(ctx context.Context) ([]QueueStatus, error) {
+	return InspectRedisStreams(ctx, rq.client, rq.stream, rq.group, rq.dlqStream)
+}
+
+// Inspect reports the job stream's status from the JetStream stream and consumer info
+func (nq *NATSQueue) Inspect(ctx context.Context) ([]QueueStatus, error) {
+	status := QueueStatus{
+		Backend: "nats",
+		Name:    nq.stream,
+	}
+
+	consumer, err := nq.js.ConsumerInfo(nq.stream, nq.consumer, nats.Context(ctx))
+	if err != nil && err != nats.ErrConsumerNotFound {
+		return nil, fmt.Errorf("failed to read consumer info: %w", err)
+	}
+
+	info, err := nq.js.StreamInfo(nq.stream, nats.Context(ctx), &nats.StreamInfoRequest{SubjectsFilter: nq.dlqSubject})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream info: %w", err)
+	}
+
+	status.DeadLetter = int64(info.State.Subjects[nq.dlqSubject])
+	if consumer != nil {
+		status.Depth = int64(consumer.NumPending)
+		status.InFlight = int64(consumer.NumAckPending)
+	} else {
+		status.Depth = int64(info.State.Msgs) - status.DeadLetter
+	}
+	if info.State.Msgs > 0 && !info.State.FirstTime.IsZero() {
+		status.OldestMessageAge = time.Since(info.State.FirstTime)
+	}
+
+	return []QueueStatus{status}, nil
+}
+
+// Inspect reports the wrapped queue's status, if it supports inspection
+func (dq *DedupQueue) Inspect(ctx context.Context) ([]QueueStatus, error) {
+	inspector, ok := dq.Queue.(Inspector)
+	if !ok {
+		return nil, fmt.Errorf("queue backend does not support inspection")
+	}
+	return inspector.Inspect(ctx)
+}
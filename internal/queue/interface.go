@@ -10,7 +10,8 @@ type Queue interface {
 	// Enqueue adds a job to the queue
 	Enqueue(ctx context.Context, job *Job) error
 
-	// Dequeue retrieves the next job from the queue
+	// Dequeue retrieves the next job from the queue, holding back jobs
+	// scheduled for later until they are due
 	Dequeue(ctx context.Context) (*Job, error)
 
 	// Ack acknowledges successful completion of a job
@@ -40,6 +41,7 @@ type Job struct {
 	CompletedAt *time.Time             `json:"completed_at,omitempty"`
 	Attempts    int                    `json:"attempts"`
 	MaxAttempts int                    `json:"max_attempts"`
+	ReplayCount int                    `json:"replay_count"`
 	Error       string                 `json:"error,omitempty"`
 	Result      map[string]interface{} `json:"result,omitempty"`
 }
@@ -54,6 +56,9 @@ const (
 	JobTypeAnalyze    JobType = "analyze"
 	JobTypeExport     JobType = "export"
 	JobTypeCleanup    JobType = "cleanup"
+	JobTypeRefresh    JobType = "refresh"
+	JobTypeEnrich     JobType = "enrich"
+	JobTypeAlert      JobType = "alert"
 )
 
 // Priority represents job priority
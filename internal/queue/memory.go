@@ -16,6 +16,7 @@ type MemoryQueue struct {
 	mu       sync.RWMutex
 	notEmpty chan struct{}
 	closed   bool
+	dlq      DeadLetterQueue
 }
 
 // NewMemoryQueue creates a new MemoryQueue
@@ -25,6 +26,7 @@ func NewMemoryQueue() *MemoryQueue {
 		jobsMap:  make(map[string]*Job),
 		notEmpty: make(chan struct{}, 1),
 		closed:   false,
+		dlq:      NewMemoryDLQ(),
 	}
 }
 
@@ -53,22 +55,15 @@ func (mq *MemoryQueue) Enqueue(ctx context.Context, job *Job) error {
 	return nil
 }
 
-// Dequeue retrieves the next job from the queue
+// Dequeue retrieves the next job that is due from the queue. Jobs scheduled
+// for later stay where they are until their time comes.
 func (mq *MemoryQueue) Dequeue(ctx context.Context) (*Job, error) {
 	for {
 		mq.mu.Lock()
 
-		// Check if closed
-		if mq.closed && len(mq.jobs) == 0 {
-			mq.mu.Unlock()
-			return nil, errors.QueueError("queue is closed", errors.ErrQueueEmpty)
-		}
-
-		// Try to get a job
-		if len(mq.jobs) > 0 {
-			// Get highest priority job
-			job := mq.jobs[0]
-			mq.jobs = mq.jobs[1:]
+		// Try to get the highest priority job that is due
+		job, wait := mq.takeDue()
+		if job != nil {
 			mq.mu.Unlock()
 
 			// Mark as started
@@ -76,18 +71,52 @@ func (mq *MemoryQueue) Dequeue(ctx context.Context) (*Job, error) {
 			return job, nil
 		}
 
+		// Check if closed
+		if mq.closed {
+			mq.mu.Unlock()
+			return nil, errors.QueueError("queue is closed", errors.ErrQueueEmpty)
+		}
+
 		mq.mu.Unlock()
 
-		// Wait for a job or context cancellation
+		// Wait for a job, the earliest scheduled job to fall due, or context
+		// cancellation
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
 		select {
 		case <-mq.notEmpty:
-			continue
+		case <-due:
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		}
+		if timer != nil {
+			timer.Stop()
+		}
 	}
 }
 
+// takeDue removes and returns the first job that is due. If none is, it
+// returns the wait until the earliest scheduled job is, or 0 if there are no
+// scheduled jobs. Callers must hold mq.mu.
+func (mq *MemoryQueue) takeDue() (*Job, time.Duration) {
+	now := time.Now()
+	var wait time.Duration
+	for i, job := range mq.jobs {
+		if job.ScheduledAt == nil || !job.ScheduledAt.After(now) {
+			mq.jobs = append(mq.jobs[:i], mq.jobs[i+1:]...)
+			return job, 0
+		}
+		if until := job.ScheduledAt.Sub(now); wait == 0 || until < wait {
+			wait = until
+		}
+	}
+	return nil, wait
+}
+
 // Ack acknowledges successful completion of a job
 func (mq *MemoryQueue) Ack(ctx context.Context, jobID string) error {
 	mq.mu.Lock()
@@ -125,8 +154,10 @@ func (mq *MemoryQueue) Nack(ctx context.Context, jobID string, requeue bool) err
 		default:
 		}
 	} else {
-		// Mark as failed and remove
+		// Mark as failed, keep it in the DLQ for replay and remove
 		job.MarkFailed(fmt.Errorf("job failed after %d attempts", job.Attempts))
+		job.Status = JobStatusFailed
+		mq.dlq.Add(job)
 		delete(mq.jobsMap, jobID)
 	}
 
@@ -141,6 +172,11 @@ func (mq *MemoryQueue) GetDepth(ctx context.Context) (int, error) {
 	return len(mq.jobs), nil
 }
 
+// GetDLQ returns the dead letter queue
+func (mq *MemoryQueue) GetDLQ() DeadLetterQueue {
+	return mq.dlq
+}
+
 // Close closes the queue
 func (mq *MemoryQueue) Close() error {
 	mq.mu.Lock()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,7 +21,9 @@ type NATSQueue struct {
 	dlqSubject  string
 	consumer    string
 	mu          sync.RWMutex
+	sub         *nats.Subscription
 	jobsMap     map[string]*Job
+	msgs        map[string]*nats.Msg // delivered message of each dequeued job, by job ID
 	stats       QueueStats
 	dlq         DeadLetterQueue
 	metrics     *QueueMetrics
@@ -83,6 +86,7 @@ func NewNATSQueue(config *NATSQueueConfig) (*NATSQueue, error) {
 		dlqSubject: config.DLQSubject,
 		consumer:   config.Consumer,
 		jobsMap:    make(map[string]*Job),
+		msgs:       make(map[string]*nats.Msg),
 		dlq:        NewMemoryDLQ(),
 	}
 
@@ -132,11 +136,8 @@ func (nq *NATSQueue) Enqueue(ctx context.Context, job *Job) error {
 		return fmt.Errorf("failed to marshal job: %w", err)
 	}
 
-	// Determine subject based on priority
-	subject := fmt.Sprintf("jobs.%s", job.Type)
-
 	// Publish to NATS
-	_, err = nq.js.Publish(subject, data)
+	_, err = nq.js.Publish(nq.publishSubject(job.Type), data)
 	if err != nil {
 		nq.mu.Lock()
 		delete(nq.jobsMap, job.ID)
@@ -158,54 +159,88 @@ func (nq *NATSQueue) Enqueue(ctx context.Context, job *Job) error {
 	return nil
 }
 
-// Dequeue retrieves the next job from the queue
+// publishSubject returns the subject for jobs of a type, filling the wildcard
+// of the consumed subject, e.g. "jobs.*" publishes scrape jobs on "jobs.scrape"
+func (nq *NATSQueue) publishSubject(jobType JobType) string {
+	if strings.HasSuffix(nq.subject, ".*") {
+		return strings.TrimSuffix(nq.subject, "*") + string(jobType)
+	}
+	return nq.subject
+}
+
+// Dequeue retrieves the next job from the queue. A job delivered before it is
+// scheduled to run is handed back for JetStream to redeliver once it is due.
 func (nq *NATSQueue) Dequeue(ctx context.Context) (*Job, error) {
-	// Subscribe to all job subjects
-	sub, err := nq.js.PullSubscribe(nq.subject, nq.consumer)
+	sub, err := nq.subscription()
 	if err != nil {
-		return nil, fmt.Errorf("failed to subscribe: %w", err)
+		return nil, err
 	}
-	defer sub.Unsubscribe()
 
-	// Fetch one message
-	msgs, err := sub.Fetch(1, nats.Context(ctx))
-	if err != nil {
-		if err == context.Canceled || err == context.DeadlineExceeded {
-			return nil, err
+	for {
+		// Fetch one message
+		msgs, err := sub.Fetch(1, nats.Context(ctx))
+		if err != nil {
+			if err == context.Canceled || err == context.DeadlineExceeded {
+				return nil, err
+			}
+			return nil, errors.QueueError("failed to fetch message", err)
 		}
-		return nil, errors.QueueError("failed to fetch message", err)
-	}
 
-	if len(msgs) == 0 {
-		return nil, errors.QueueError("no messages available", errors.ErrQueueEmpty)
-	}
+		if len(msgs) == 0 {
+			return nil, errors.QueueError("no messages available", errors.ErrQueueEmpty)
+		}
 
-	msg := msgs[0]
+		msg := msgs[0]
 
-	// Deserialize job
-	var job Job
-	if err := json.Unmarshal(msg.Data, &job); err != nil {
-		msg.Nak()
-		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
-	}
+		// Deserialize job
+		var job Job
+		if err := json.Unmarshal(msg.Data, &job); err != nil {
+			msg.Nak()
+			return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+		}
 
-	// Mark as started
-	job.MarkStarted()
+		if job.IsScheduled() {
+			if err := msg.NakWithDelay(time.Until(*job.ScheduledAt)); err != nil {
+				return nil, fmt.Errorf("failed to delay scheduled job: %w", err)
+			}
+			continue
+		}
 
-	// Store message metadata for later ack/nack
-	nq.mu.Lock()
-	nq.jobsMap[job.ID] = &job
-	nq.stats.LastDequeued = time.Now()
-	nq.stats.Pending--
-	nq.stats.Running++
-	nq.mu.Unlock()
+		// Mark as started
+		job.MarkStarted()
 
-	// Update metrics
-	if nq.metrics != nil {
-		nq.metrics.RecordDequeue(&job)
+		// Store message for later ack/nack
+		nq.mu.Lock()
+		nq.jobsMap[job.ID] = &job
+		nq.msgs[job.ID] = msg
+		nq.stats.LastDequeued = time.Now()
+		nq.stats.Pending--
+		nq.stats.Running++
+		nq.mu.Unlock()
+
+		// Update metrics
+		if nq.metrics != nil {
+			nq.metrics.RecordDequeue(&job)
+		}
+
+		return &job, nil
 	}
+}
+
+// subscription returns the pull subscription Dequeue fetches from, binding it
+// to the durable consumer on first use
+func (nq *NATSQueue) subscription() (*nats.Subscription, error) {
+	nq.mu.Lock()
+	defer nq.mu.Unlock()
 
-	return &job, nil
+	if nq.sub == nil {
+		sub, err := nq.js.PullSubscribe(nq.subject, nq.consumer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe: %w", err)
+		}
+		nq.sub = sub
+	}
+	return nq.sub, nil
 }
 
 // Ack acknowledges successful completion of a job
@@ -219,6 +254,7 @@ func (nq *NATSQueue) Ack(ctx context.Context, jobID string) error {
 
 	job.MarkCompleted(nil)
 	delete(nq.jobsMap, jobID)
+	msg := nq.takeMessage(jobID)
 	nq.stats.Running--
 	nq.stats.Completed++
 	nq.mu.Unlock()
@@ -228,6 +264,26 @@ func (nq *NATSQueue) Ack(ctx context.Context, jobID string) error {
 		nq.metrics.RecordCompletion(job)
 	}
 
+	return nq.ackMessage(msg)
+}
+
+// takeMessage removes and returns the delivered message of a dequeued job.
+// Callers must hold nq.mu.
+func (nq *NATSQueue) takeMessage(jobID string) *nats.Msg {
+	msg := nq.msgs[jobID]
+	delete(nq.msgs, jobID)
+	return msg
+}
+
+// ackMessage acknowledges a delivered message, removing it from the work queue
+func (nq *NATSQueue) ackMessage(msg *nats.Msg) error {
+	if msg == nil {
+		return nil
+	}
+
+	if err := msg.Ack(); err != nil {
+		return fmt.Errorf("failed to acknowledge message: %w", err)
+	}
 	return nil
 }
 
@@ -240,15 +296,24 @@ func (nq *NATSQueue) Nack(ctx context.Context, jobID string, requeue bool) error
 		return errors.QueueError("job not found", errors.ErrNotFound)
 	}
 
+	msg := nq.takeMessage(jobID)
+
 	if requeue && job.ShouldRetry() {
-		// Re-enqueue the job
+		// Re-publish the job as it now stands, then drop the delivered message
 		nq.mu.Unlock()
-		return nq.Enqueue(ctx, job)
+		if err := nq.Enqueue(ctx, job); err != nil {
+			nq.mu.Lock()
+			nq.msgs[jobID] = msg
+			nq.mu.Unlock()
+			return err
+		}
+		return nq.ackMessage(msg)
 	}
 
 	// Send to DLQ
 	job.MarkFailed(fmt.Errorf("job failed after %d attempts", job.Attempts))
 	if err := nq.dlq.Add(job); err != nil {
+		nq.msgs[jobID] = msg
 		nq.mu.Unlock()
 		return fmt.Errorf("failed to add to DLQ: %w", err)
 	}
@@ -267,7 +332,7 @@ func (nq *NATSQueue) Nack(ctx context.Context, jobID string, requeue bool) error
 	data, _ := json.Marshal(job)
 	nq.js.Publish(nq.dlqSubject, data)
 
-	return nil
+	return nq.ackMessage(msg)
 }
 
 // GetDepth returns the current queue depth
@@ -11,15 +11,34 @@ import (
 	"github.com/gongahkia/kite/pkg/errors"
 )
 
-// RedisQueue implements Queue using Redis Streams
+// promoteBatch is the most scheduled jobs moved to the stream per Dequeue
+const promoteBatch = 100
+
+// promoteDueScript moves the jobs in the sorted set KEYS[1] whose score, the
+// time they are scheduled for in Unix milliseconds, is at most ARGV[1] onto
+// the stream KEYS[2], at most ARGV[2] at a time. Returns the number moved.
+var promoteDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, data in ipairs(due) do
+	local job = cjson.decode(data)
+	redis.call('XADD', KEYS[2], '*', 'id', job.id, 'type', job.type, 'priority', job.priority, 'data', data)
+	redis.call('ZREM', KEYS[1], data)
+end
+return #due
+`)
+
+// RedisQueue implements Queue using Redis Streams. Jobs scheduled for later
+// wait in a sorted set beside the stream and are moved onto it once due.
 type RedisQueue struct {
 	client      *redis.Client
 	stream      string
+	delayed     string // sorted set of scheduled jobs, by due time
 	group       string
 	consumer    string
 	dlqStream   string
 	mu          sync.RWMutex
 	jobsMap     map[string]*Job
+	msgIDs      map[string]string // stream entry of each dequeued job, by job ID
 	stats       QueueStats
 	dlq         DeadLetterQueue
 	metrics     *QueueMetrics
@@ -77,10 +96,12 @@ func NewRedisQueue(config *RedisQueueConfig) (*RedisQueue, error) {
 	queue := &RedisQueue{
 		client:    client,
 		stream:    config.Stream,
+		delayed:   config.Stream + ":delayed",
 		group:     config.Group,
 		consumer:  config.Consumer,
 		dlqStream: config.DLQStream,
 		jobsMap:   make(map[string]*Job),
+		msgIDs:    make(map[string]string),
 		dlq:       NewMemoryDLQ(),
 	}
 
@@ -107,7 +128,8 @@ func (rq *RedisQueue) createGroup(ctx context.Context) error {
 	return nil
 }
 
-// Enqueue adds a job to the queue
+// Enqueue adds a job to the queue. A job scheduled for later is held in the
+// delayed set until it is due.
 func (rq *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
 	rq.mu.Lock()
 	rq.jobsMap[job.ID] = job
@@ -119,18 +141,25 @@ func (rq *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
 		return fmt.Errorf("failed to marshal job: %w", err)
 	}
 
-	// Add to Redis Stream
-	values := map[string]interface{}{
-		"id":       job.ID,
-		"type":     string(job.Type),
-		"priority": job.Priority,
-		"data":     string(data),
-	}
+	if job.IsScheduled() {
+		err = rq.client.ZAdd(ctx, rq.delayed, redis.Z{
+			Score:  float64(job.ScheduledAt.UnixMilli()),
+			Member: string(data),
+		}).Err()
+	} else {
+		// Add to Redis Stream
+		values := map[string]interface{}{
+			"id":       job.ID,
+			"type":     string(job.Type),
+			"priority": job.Priority,
+			"data":     string(data),
+		}
 
-	_, err = rq.client.XAdd(ctx, &redis.XAddArgs{
-		Stream: rq.stream,
-		Values: values,
-	}).Result()
+		err = rq.client.XAdd(ctx, &redis.XAddArgs{
+			Stream: rq.stream,
+			Values: values,
+		}).Err()
+	}
 
 	if err != nil {
 		rq.mu.Lock()
@@ -153,8 +182,13 @@ func (rq *RedisQueue) Enqueue(ctx context.Context, job *Job) error {
 	return nil
 }
 
-// Dequeue retrieves the next job from the queue
+// Dequeue retrieves the next job from the queue, first moving scheduled jobs
+// that have fallen due onto the stream
 func (rq *RedisQueue) Dequeue(ctx context.Context) (*Job, error) {
+	if err := rq.promoteDue(ctx); err != nil {
+		return nil, err
+	}
+
 	// Read from stream using consumer group
 	streams, err := rq.client.XReadGroup(ctx, &redis.XReadGroupArgs{
 		Group:    rq.group,
@@ -195,6 +229,7 @@ func (rq *RedisQueue) Dequeue(ctx context.Context) (*Job, error) {
 	// Store message ID for later ack
 	rq.mu.Lock()
 	rq.jobsMap[job.ID] = &job
+	rq.msgIDs[job.ID] = msg.ID
 	rq.stats.LastDequeued = time.Now()
 	rq.stats.Pending--
 	rq.stats.Running++
@@ -208,6 +243,17 @@ func (rq *RedisQueue) Dequeue(ctx context.Context) (*Job, error) {
 	return &job, nil
 }
 
+// promoteDue moves scheduled jobs that are due from the delayed set onto the
+// stream. The script runs atomically, so each job is moved by one consumer.
+func (rq *RedisQueue) promoteDue(ctx context.Context) error {
+	keys := []string{rq.delayed, rq.stream}
+	err := promoteDueScript.Run(ctx, rq.client, keys, time.Now().UnixMilli(), promoteBatch).Err()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to move due jobs to stream: %w", err)
+	}
+	return nil
+}
+
 // Ack acknowledges successful completion of a job
 func (rq *RedisQueue) Ack(ctx context.Context, jobID string) error {
 	rq.mu.Lock()
@@ -219,6 +265,7 @@ func (rq *RedisQueue) Ack(ctx context.Context, jobID string) error {
 
 	job.MarkCompleted(nil)
 	delete(rq.jobsMap, jobID)
+	msgID := rq.takeMessageID(jobID)
 	rq.stats.Running--
 	rq.stats.Completed++
 	rq.mu.Unlock()
@@ -228,9 +275,30 @@ func (rq *RedisQueue) Ack(ctx context.Context, jobID string) error {
 		rq.metrics.RecordCompletion(job)
 	}
 
-	// In production, you'd also XAck the message ID here
-	// For simplicity, we're omitting that in this implementation
+	return rq.ackMessage(ctx, msgID)
+}
+
+// takeMessageID removes and returns the stream entry ID of a dequeued job.
+// Callers must hold rq.mu.
+func (rq *RedisQueue) takeMessageID(jobID string) string {
+	msgID := rq.msgIDs[jobID]
+	delete(rq.msgIDs, jobID)
+	return msgID
+}
+
+// ackMessage acknowledges a stream entry and deletes it, so the stream only
+// holds jobs that are waiting or in flight
+func (rq *RedisQueue) ackMessage(ctx context.Context, msgID string) error {
+	if msgID == "" {
+		return nil
+	}
 
+	pipe := rq.client.TxPipeline()
+	pipe.XAck(ctx, rq.stream, rq.group, msgID)
+	pipe.XDel(ctx, rq.stream, msgID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to acknowledge stream entry: %w", err)
+	}
 	return nil
 }
 
@@ -243,15 +311,24 @@ func (rq *RedisQueue) Nack(ctx context.Context, jobID string, requeue bool) erro
 		return errors.QueueError("job not found", errors.ErrNotFound)
 	}
 
+	msgID := rq.takeMessageID(jobID)
+
 	if requeue && job.ShouldRetry() {
-		// Re-enqueue the job
+		// Re-enqueue the job as a new entry, then drop the delivered one
 		rq.mu.Unlock()
-		return rq.Enqueue(ctx, job)
+		if err := rq.Enqueue(ctx, job); err != nil {
+			rq.mu.Lock()
+			rq.msgIDs[jobID] = msgID
+			rq.mu.Unlock()
+			return err
+		}
+		return rq.ackMessage(ctx, msgID)
 	}
 
 	// Send to DLQ
 	job.MarkFailed(fmt.Errorf("job failed after %d attempts", job.Attempts))
 	if err := rq.dlq.Add(job); err != nil {
+		rq.msgIDs[jobID] = msgID
 		rq.mu.Unlock()
 		return fmt.Errorf("failed to add to DLQ: %w", err)
 	}
@@ -276,17 +353,19 @@ func (rq *RedisQueue) Nack(ctx context.Context, jobID string, requeue bool) erro
 		},
 	})
 
-	return nil
+	return rq.ackMessage(ctx, msgID)
 }
 
-// GetDepth returns the current queue depth
+// GetDepth returns the current queue depth, counting scheduled jobs
 func (rq *RedisQueue) GetDepth(ctx context.Context) (int, error) {
-	length, err := rq.client.XLen(ctx, rq.stream).Result()
-	if err != nil {
+	pipe := rq.client.Pipeline()
+	length := pipe.XLen(ctx, rq.stream)
+	delayed := pipe.ZCard(ctx, rq.delayed)
+	if _, err := pipe.Exec(ctx); err != nil {
 		return 0, err
 	}
 
-	return int(length), nil
+	return int(length.Val() + delayed.Val()), nil
 }
 
 // GetStats returns queue statistics
@@ -300,8 +379,8 @@ func (rq *RedisQueue) GetStats() QueueStats {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	if length, err := rq.client.XLen(ctx, rq.stream).Result(); err == nil {
-		stats.Depth = int(length)
+	if depth, err := rq.GetDepth(ctx); err == nil {
+		stats.Depth = depth
 	}
 
 	return stats
@@ -324,5 +403,5 @@ func (rq *RedisQueue) Close() error {
 
 // Purge removes all messages from the queue
 func (rq *RedisQueue) Purge(ctx context.Context) error {
-	return rq.client.Del(ctx, rq.stream).Err()
+	return rq.client.Del(ctx, rq.stream, rq.delayed).Err()
 }
@@ -0,0 +1,91 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DLQProvider is implemented by queues that keep failed jobs in a dead letter queue
+type DLQProvider interface {
+	GetDLQ() DeadLetterQueue
+}
+
+// ReplayFilter selects which dead-lettered jobs are replayed
+type ReplayFilter struct {
+	Status     JobStatus
+	Type       JobType
+	Since      *time.Time
+	MaxReplays int
+}
+
+// ReplayResult summarizes a replay run
+type ReplayResult struct {
+	Replayed []string `json:"replayed"`
+	Skipped  []string `json:"skipped"`
+}
+
+// Matches reports whether a job satisfies the filter
+func (f ReplayFilter) Matches(job *Job) bool {
+	if f.Status != "" && job.Status != f.Status {
+		return false
+	}
+	if f.Type != "" && job.Type != f.Type {
+		return false
+	}
+	if f.Since != nil && job.UpdatedAt.Before(*f.Since) {
+		return false
+	}
+	return true
+}
+
+// ReplayJobs moves matching jobs from the DLQ back onto the queue with their
+// original payload. Jobs that have already been replayed MaxReplays times are
+// left in the DLQ and reported as skipped.
+func ReplayJobs(ctx context.Context, q Queue, dlq DeadLetterQueue, filter ReplayFilter) (*ReplayResult, error) {
+	result := &ReplayResult{
+		Replayed: make([]string, 0),
+		Skipped:  make([]string, 0),
+	}
+
+	jobs, err := dlq.List(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letter queue: %w", err)
+	}
+
+	// Copy the list, Retry mutates the DLQ while we iterate
+	candidates := make([]*Job, len(jobs))
+	copy(candidates, jobs)
+
+	for _, job := range candidates {
+		if !filter.Matches(job) {
+			continue
+		}
+
+		if filter.MaxReplays > 0 && job.ReplayCount >= filter.MaxReplays {
+			result.Skipped = append(result.Skipped, job.ID)
+			continue
+		}
+
+		retried, err := dlq.Retry(job.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to retry job %s: %w", job.ID, err)
+		}
+		if retried == nil {
+			continue
+		}
+
+		retried.ReplayCount++
+		if err := q.Enqueue(ctx, retried); err != nil {
+			// Put the job back so it is not lost
+			retried.ReplayCount--
+			retried.Status = JobStatusFailed
+			dlq.Add(retried)
+			return result, fmt.Errorf("failed to enqueue job %s: %w", job.ID, err)
+		}
+
+		result.Replayed = append(result.Replayed, retried.ID)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,170 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryStrategy selects how the delay before retrying a failed job grows
+type RetryStrategy string
+
+const (
+	RetryImmediate   RetryStrategy = "immediate"
+	RetryFixed       RetryStrategy = "fixed"
+	RetryExponential RetryStrategy = "exponential"
+)
+
+// RetryPolicy decides how long a failed job waits before it is requeued
+type RetryPolicy struct {
+	Strategy  RetryStrategy
+	BaseDelay time.Duration // delay before the first retry
+	MaxDelay  time.Duration // cap on any delay, 0 for no cap
+	Jitter    float64       // fraction (0 to 1) the delay is randomly varied by
+}
+
+// DefaultRetryPolicy returns exponential backoff from 5s up to 5m with 20% jitter
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		Strategy:  RetryExponential,
+		BaseDelay: 5 * time.Second,
+		MaxDelay:  5 * time.Minute,
+		Jitter:    0.2,
+	}
+}
+
+// Validate checks the strategy is known and the delays and jitter are in range
+func (p RetryPolicy) Validate() error {
+	switch p.Strategy {
+	case RetryImmediate, RetryFixed, RetryExponential:
+	default:
+		return fmt.Errorf("unknown retry strategy: %q", p.Strategy)
+	}
+	if p.BaseDelay < 0 || p.MaxDelay < 0 {
+		return fmt.Errorf("retry delays must not be negative")
+	}
+	if p.Jitter < 0 || p.Jitter > 1 {
+		return fmt.Errorf("retry jitter must be between 0 and 1, got %v", p.Jitter)
+	}
+	return nil
+}
+
+// Delay returns the wait before retrying a job that has failed attempts times.
+// Exponential delays double with each attempt from BaseDelay; jitter then
+// varies the delay by up to Jitter of itself, and MaxDelay caps the result.
+func (p RetryPolicy) Delay(attempts int) time.Duration {
+	var delay float64
+	switch p.Strategy {
+	case RetryFixed:
+		delay = float64(p.BaseDelay)
+	case RetryExponential:
+		if attempts < 1 {
+			attempts = 1
+		}
+		delay = float64(p.BaseDelay) * math.Pow(2, float64(attempts-1))
+	default:
+		return 0
+	}
+
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+// RetryQueue wraps a Queue and holds back failed jobs for the retry policy's
+// delay, so every backend retries on the same schedule. A failed job is
+// requeued at once, scheduled for when its delay ends, and the wrapped queue
+// holds it back until then, so a queue that keeps its jobs keeps the retry too.
+type RetryQueue struct {
+	Queue
+	policy RetryPolicy
+	mu     sync.Mutex
+	jobs   map[string]*Job
+}
+
+// NewRetryQueue wraps q with the retry policy. The immediate strategy returns
+// q unchanged.
+func NewRetryQueue(q Queue, policy RetryPolicy) Queue {
+	if policy.Strategy == RetryImmediate {
+		return q
+	}
+	return &RetryQueue{
+		Queue:  q,
+		policy: policy,
+		jobs:   make(map[string]*Job),
+	}
+}
+
+// Dequeue retrieves the next job that is due, remembering it until it is
+// acked or nacked
+func (rq *RetryQueue) Dequeue(ctx context.Context) (*Job, error) {
+	job, err := rq.Queue.Dequeue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rq.mu.Lock()
+	rq.jobs[job.ID] = job
+	rq.mu.Unlock()
+	return job, nil
+}
+
+// Ack acknowledges successful completion of a job
+func (rq *RetryQueue) Ack(ctx context.Context, jobID string) error {
+	rq.mu.Lock()
+	delete(rq.jobs, jobID)
+	rq.mu.Unlock()
+
+	return rq.Queue.Ack(ctx, jobID)
+}
+
+// Nack requeues a job that should be retried, scheduled for after the
+// policy's delay, and passes every other nack straight through
+func (rq *RetryQueue) Nack(ctx context.Context, jobID string, requeue bool) error {
+	rq.mu.Lock()
+	job, ok := rq.jobs[jobID]
+	delete(rq.jobs, jobID)
+	rq.mu.Unlock()
+
+	if ok && requeue && job.ShouldRetry() {
+		if delay := rq.policy.Delay(job.Attempts); delay > 0 {
+			job.Schedule(time.Now().Add(delay))
+		}
+	}
+	return rq.Queue.Nack(ctx, jobID, requeue)
+}
+
+// Deferrer is implemented by queues that can hold a job back until a set time
+type Deferrer interface {
+	// Defer requeues a dequeued job to run once until has passed
+	Defer(ctx context.Context, jobID string, until time.Time) error
+}
+
+// Defer requeues a job scheduled for the given time, whatever the retry
+// policy. The job must be ready to requeue, as for Nack.
+func (rq *RetryQueue) Defer(ctx context.Context, jobID string, until time.Time) error {
+	rq.mu.Lock()
+	job, ok := rq.jobs[jobID]
+	delete(rq.jobs, jobID)
+	rq.mu.Unlock()
+
+	if ok {
+		job.Schedule(until)
+	}
+	return rq.Queue.Nack(ctx, jobID, true)
+}
+
+// GetDLQ returns the wrapped queue's dead letter queue, or nil if it has none
+func (rq *RetryQueue) GetDLQ() DeadLetterQueue {
+	if provider, ok := rq.Queue.(DLQProvider); ok {
+		return provider.GetDLQ()
+	}
+	return nil
+}
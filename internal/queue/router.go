@@ -0,0 +1,59 @@
+package queue
+
+import "context"
+
+// RoutedQueue wraps a Queue and enqueues jobs of routed types onto their own
+// queues, so that separate worker pools can consume them. Jobs of other types,
+// and every Dequeue, Ack and Nack, go to the wrapped queue; a pool for a
+// routed type consumes that type's queue directly.
+type RoutedQueue struct {
+	Queue
+	routes map[JobType]Queue
+}
+
+// NewRoutedQueue wraps q with per-type routing. With no routes it returns q
+// unchanged.
+func NewRoutedQueue(q Queue, routes map[JobType]Queue) Queue {
+	if len(routes) == 0 {
+		return q
+	}
+	return &RoutedQueue{
+		Queue:  q,
+		routes: routes,
+	}
+}
+
+// Enqueue adds the job to the queue for its type
+func (rq *RoutedQueue) Enqueue(ctx context.Context, job *Job) error {
+	return rq.QueueFor(job.Type).Enqueue(ctx, job)
+}
+
+// QueueFor returns the queue jobs of the given type are enqueued on
+func (rq *RoutedQueue) QueueFor(jobType JobType) Queue {
+	if q, ok := rq.routes[jobType]; ok {
+		return q
+	}
+	return rq.Queue
+}
+
+// GetDLQ returns the wrapped queue's dead letter queue, or nil if it has none
+func (rq *RoutedQueue) GetDLQ() DeadLetterQueue {
+	if provider, ok := rq.Queue.(DLQProvider); ok {
+		return provider.GetDLQ()
+	}
+	return nil
+}
+
+// Close closes the routed queues and then the wrapped queue
+func (rq *RoutedQueue) Close() error {
+	var firstErr error
+	for _, q := range rq.routes {
+		if err := q.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := rq.Queue.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -0,0 +1,209 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// ArchiveSink stores archived scrape results by key
+type ArchiveSink interface {
+	Put(ctx context.Context, key string, data []byte) error
+}
+
+// ArchiveLogger receives archive write failures (satisfied by observability.Logger)
+type ArchiveLogger interface {
+	Warnf(format string, args ...interface{})
+}
+
+// ArchiveMetrics counts archived cases by source and status (satisfied by
+// observability.Metrics)
+type ArchiveMetrics interface {
+	RecordArchivedCase(source, status string)
+}
+
+// Archive statuses reported to ArchiveMetrics
+const (
+	ArchiveStatusWritten = "written"
+	ArchiveStatusFailed  = "failed"
+	ArchiveStatusDropped = "dropped"
+)
+
+// DefaultArchiveBufferSize is the number of cases queued for archival before
+// new ones are dropped
+const DefaultArchiveBufferSize = 1000
+
+// ArchiveKey builds the key a case is archived under: source, case ID and the
+// scrape time, e.g. "bailii/ewca-civ-2024-1/20240501T120000.000000000.json"
+func ArchiveKey(source, id string, ts time.Time) string {
+	return fmt.Sprintf("%s/%s/%s.json", archiveKeyPart(source), archiveKeyPart(id), ts.UTC().Format("20060102T150405.000000000"))
+}
+
+// archiveKeyPart makes one key segment safe for paths and object names
+func archiveKeyPart(s string) string {
+	s = strings.Trim(unsafeFileChars.ReplaceAllString(s, "_"), "_.")
+	if s == "" {
+		return "unknown"
+	}
+	if len(s) > 150 {
+		s = s[:150]
+	}
+	return s
+}
+
+// DirArchiveSink writes archived results to files under a local directory
+type DirArchiveSink struct {
+	dir string
+}
+
+// NewDirArchiveSink creates a DirArchiveSink rooted at dir
+func NewDirArchiveSink(dir string) *DirArchiveSink {
+	return &DirArchiveSink{dir: dir}
+}
+
+// Put writes data to the key's file. Archives are write-once: an existing
+// file is never replaced.
+func (s *DirArchiveSink) Put(ctx context.Context, key string, data []byte) error {
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create archive directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write archive file: %w", err)
+	}
+
+	// Link rather than rename so an existing archive is left alone
+	if err := os.Link(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", key, err)
+	}
+	return nil
+}
+
+type archiveRecord struct {
+	source string
+	key    string
+	data   []byte
+}
+
+// ResultArchiver writes the raw JSON of scraped cases to an ArchiveSink in
+// the background, independent of primary storage. Archiving never blocks a
+// scrape: when the queue is full the case is dropped from the archive.
+type ResultArchiver struct {
+	sink    ArchiveSink
+	timeout time.Duration
+	logger  ArchiveLogger
+	metrics ArchiveMetrics
+	now     func() time.Time
+
+	queue     chan archiveRecord
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewResultArchiver creates a ResultArchiver queueing up to bufferSize cases
+// for sink and starts writing them
+func NewResultArchiver(sink ArchiveSink, bufferSize int) *ResultArchiver {
+	if bufferSize <= 0 {
+		bufferSize = DefaultArchiveBufferSize
+	}
+
+	a := &ResultArchiver{
+		sink:    sink,
+		timeout: 30 * time.Second,
+		now:     time.Now,
+		queue:   make(chan archiveRecord, bufferSize),
+		done:    make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+// SetLogger sets the logger for archive write failures
+func (a *ResultArchiver) SetLogger(logger ArchiveLogger) {
+	a.logger = logger
+}
+
+// SetMetrics sets where archived, failed and dropped cases are counted
+func (a *ResultArchiver) SetMetrics(metrics ArchiveMetrics) {
+	a.metrics = metrics
+}
+
+// Archive queues a scraped case for archival under its source, ID and the
+// current time. It is a no-op on a nil ResultArchiver.
+func (a *ResultArchiver) Archive(source string, c *models.Case) {
+	if a == nil || c == nil {
+		return
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		a.warnf("Failed to encode case %s for archive: %v", c.ID, err)
+		a.record(source, ArchiveStatusFailed)
+		return
+	}
+
+	rec := archiveRecord{source: source, key: ArchiveKey(source, c.ID, a.now()), data: data}
+	select {
+	case a.queue <- rec:
+	default:
+		a.warnf("Archive queue full, dropping case %s from %s", c.ID, source)
+		a.record(source, ArchiveStatusDropped)
+	}
+}
+
+// Close stops accepting cases and waits for the queued ones to be written.
+// Archive must not be called after Close.
+func (a *ResultArchiver) Close() {
+	if a == nil {
+		return
+	}
+	a.closeOnce.Do(func() {
+		close(a.queue)
+	})
+	<-a.done
+}
+
+func (a *ResultArchiver) run() {
+	defer close(a.done)
+	for rec := range a.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), a.timeout)
+		err := a.sink.Put(ctx, rec.key, rec.data)
+		cancel()
+		if err != nil {
+			a.warnf("Failed to archive %s: %v", rec.key, err)
+			a.record(rec.source, ArchiveStatusFailed)
+			continue
+		}
+		a.record(rec.source, ArchiveStatusWritten)
+	}
+}
+
+func (a *ResultArchiver) warnf(format string, args ...interface{}) {
+	if a.logger != nil {
+		a.logger.Warnf(format, args...)
+	}
+}
+
+func (a *ResultArchiver) record(source, status string) {
+	if a.metrics != nil {
+		a.metrics.RecordArchivedCase(source, status)
+	}
+}
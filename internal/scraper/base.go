@@ -2,8 +2,10 @@ package scraper
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/gongahkia/kite/internal/clock"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -32,6 +34,10 @@ type Scraper interface {
 
 	// GetMetadata returns metadata about this scraper
 	GetMetadata() ScraperMetadata
+
+	// Validate fetches a known sample case and checks that its core fields
+	// were extracted
+	Validate(ctx context.Context) error
 }
 
 // SearchQuery represents a search query for cases
@@ -46,6 +52,7 @@ type SearchQuery struct {
 	Judges      []string   `json:"judges,omitempty"`
 	Concepts    []string   `json:"concepts,omitempty"`
 	CourtLevel  *models.CourtLevel `json:"court_level,omitempty"`
+	CourtLevels []models.CourtLevel `json:"court_levels,omitempty"` // keep only cases from courts at these levels
 }
 
 // ScraperMetadata contains metadata about a scraper
@@ -79,6 +86,17 @@ type BaseScraper struct {
 	client       *ScraperHTTPClient
 	logger       interface{}
 	metrics      interface{}
+	dumper       *HTMLDumper
+	clock        clock.Clock
+	charset      string // assumed for pages that declare no charset
+	structure    *TextStructure
+	selectors    map[string]string // overrides of built-in selectors, by name
+	blocklist    *URLBlocklist
+	concurrency  int // requests to the source in flight at once, 0 if unset
+	window       *ScrapeWindow
+
+	fallbackLogger  FallbackLogger
+	fallbackMetrics FallbackMetrics
 }
 
 // NewBaseScraper creates a new BaseScraper
@@ -89,6 +107,7 @@ func NewBaseScraper(name, jurisdiction, baseURL string, rateLimit int) *BaseScra
 		baseURL:      baseURL,
 		rateLimit:    rateLimit,
 		client:       NewScraperHTTPClient(baseURL, rateLimit),
+		clock:        clock.Real(),
 	}
 }
 
@@ -115,8 +134,38 @@ func (bs *BaseScraper) GetMetadata() ScraperMetadata {
 		BaseURL:      bs.baseURL,
 		RateLimit:    bs.rateLimit,
 		Status:       ScraperStatusActive,
-		LastChecked:  time.Now(),
+		LastChecked:  bs.clock.Now(),
+	}
+}
+
+// SetClock sets the clock used to stamp scraped cases
+func (bs *BaseScraper) SetClock(c clock.Clock) {
+	bs.clock = c
+}
+
+// Now returns the current time on the scraper's clock. Scrapers stamp
+// ScrapedAt and LastUpdated with it.
+func (bs *BaseScraper) Now() time.Time {
+	return bs.clock.Now()
+}
+
+// SetHTMLDumper enables saving raw HTML when extraction fails
+func (bs *BaseScraper) SetHTMLDumper(dumper *HTMLDumper) {
+	bs.dumper = dumper
+}
+
+// SetSharedRateLimit makes the scraper's rate limit a budget shared through
+// bucket with every other worker scraping the same source
+func (bs *BaseScraper) SetSharedRateLimit(bucket SharedBucket, logger RateLimitLogger) {
+	bs.client.rateLimiter.SetShared(bucket, SharedRateLimitKeyPrefix+bs.name, logger)
+}
+
+// DumpOnParseFailure saves the raw HTML for a page if HTML dumping is enabled
+func (bs *BaseScraper) DumpOnParseFailure(pageURL string, html []byte) {
+	if bs.dumper == nil || len(html) == 0 {
+		return
 	}
+	bs.dumper.Dump(pageURL, html)
 }
 
 // ScraperHTTPClient is a specialized HTTP client for scraping
@@ -170,19 +219,124 @@ type ScraperConfig struct {
 
 // ScraperRegistry manages all available scrapers
 type ScraperRegistry struct {
-	scrapers map[string]Scraper
+	scrapers      map[string]Scraper
+	dumper        *HTMLDumper
+	archiver      *ResultArchiver
+	idGenerator   *CaseIDGenerator
+	jurisdictions *JurisdictionFilter
+	sharedLimit   SharedBucket
+	limitLogger   RateLimitLogger
+	charsets      map[string]string
+	maxFullText   int
+	textLogger    FullTextLogger
+	version       int
+	structure     *TextStructure
+	selectors     SelectorOverrides
+	blocklists    map[string]*URLBlocklist
+	concurrency   int
+	concurrencies map[string]int
+	windows       map[string]*ScrapeWindow
+	retry         RetryConfig
+
+	fallbackLogger  FallbackLogger
+	fallbackMetrics FallbackMetrics
 }
 
 // NewScraperRegistry creates a new ScraperRegistry
 func NewScraperRegistry() *ScraperRegistry {
 	return &ScraperRegistry{
 		scrapers: make(map[string]Scraper),
+		version:  DefaultExtractionVersion,
 	}
 }
 
-// Register registers a scraper
+// Register registers a scraper. Scrapers for disabled jurisdictions are ignored.
 func (sr *ScraperRegistry) Register(name string, scraper Scraper) {
-	sr.scrapers[name] = scraper
+	if !sr.jurisdictions.Enabled(scraper.GetJurisdiction()) {
+		return
+	}
+	if sr.dumper != nil {
+		if d, ok := scraper.(interface{ SetHTMLDumper(*HTMLDumper) }); ok {
+			d.SetHTMLDumper(sr.dumper)
+		}
+	}
+	if sr.sharedLimit != nil {
+		if l, ok := scraper.(sharedRateLimited); ok {
+			l.SetSharedRateLimit(sr.sharedLimit, sr.limitLogger)
+		}
+	}
+	if cs, ok := sr.charsets[name]; ok {
+		if d, ok := scraper.(defaultCharsetter); ok {
+			d.SetDefaultCharset(cs)
+		}
+	}
+	if sr.structure != nil {
+		if s, ok := scraper.(textStructurer); ok {
+			s.SetTextStructure(sr.structure)
+		}
+	}
+	if selectors, ok := sr.selectors[name]; ok {
+		if s, ok := scraper.(selectorOverrider); ok {
+			s.SetSelectors(selectors)
+		}
+	}
+	if blocklist, ok := sr.blocklists[name]; ok {
+		if b, ok := scraper.(urlBlocklister); ok {
+			b.SetURLBlocklist(blocklist)
+		}
+	}
+	if limit := sr.concurrencyLimit(name); limit > 0 {
+		if l, ok := scraper.(concurrencyLimited); ok {
+			l.SetConcurrencyLimit(limit)
+		}
+	}
+	if window, ok := sr.windows[name]; ok {
+		if w, ok := scraper.(scrapeWindowed); ok {
+			w.SetScrapeWindow(window)
+		}
+	}
+	if sr.fallbackLogger != nil || sr.fallbackMetrics != nil {
+		if f, ok := scraper.(fallbackReporter); ok {
+			f.SetFallbackReporting(sr.fallbackLogger, sr.fallbackMetrics)
+		}
+	}
+	wrapped := WithIDGenerator(name, scraper, sr.idGenerator)
+	wrapped = WithFullTextLimit(wrapped, sr.maxFullText, sr.textLogger)
+	sr.scrapers[name] = WithExtractionVersion(wrapped, sr.version)
+}
+
+// SetExtractionVersion sets the extraction version stamped on cases returned
+// by scrapers registered afterwards
+func (sr *ScraperRegistry) SetExtractionVersion(version int) {
+	sr.version = version
+}
+
+// SetIDGenerator sets the case ID strategy for scrapers registered afterwards
+func (sr *ScraperRegistry) SetIDGenerator(generator *CaseIDGenerator) {
+	sr.idGenerator = generator
+}
+
+// SetMaxFullTextBytes caps the full text kept from each case returned by
+// scrapers registered afterwards, logging truncations to logger
+func (sr *ScraperRegistry) SetMaxFullTextBytes(maxBytes int, logger FullTextLogger) {
+	sr.maxFullText = maxBytes
+	sr.textLogger = logger
+}
+
+// SetJurisdictionFilter restricts registration to enabled jurisdictions and
+// removes already-registered scrapers for disabled ones
+func (sr *ScraperRegistry) SetJurisdictionFilter(filter *JurisdictionFilter) {
+	sr.jurisdictions = filter
+	for name, s := range sr.scrapers {
+		if !filter.Enabled(s.GetJurisdiction()) {
+			delete(sr.scrapers, name)
+		}
+	}
+}
+
+// JurisdictionFilter returns the registry's jurisdiction filter (nil enables all)
+func (sr *ScraperRegistry) JurisdictionFilter() *JurisdictionFilter {
+	return sr.jurisdictions
 }
 
 // Get retrieves a scraper by name
@@ -196,6 +350,142 @@ func (sr *ScraperRegistry) GetAll() map[string]Scraper {
 	return sr.scrapers
 }
 
+// SetHTMLDumper enables HTML dumps on every registered scraper that supports it
+func (sr *ScraperRegistry) SetHTMLDumper(dumper *HTMLDumper) {
+	sr.dumper = dumper
+	for _, s := range sr.scrapers {
+		if d, ok := unwrapScraper(s).(interface{ SetHTMLDumper(*HTMLDumper) }); ok {
+			d.SetHTMLDumper(dumper)
+		}
+	}
+}
+
+// SetArchiver sets where the raw JSON of scraped cases is archived (nil
+// disables archival)
+func (sr *ScraperRegistry) SetArchiver(archiver *ResultArchiver) {
+	sr.archiver = archiver
+}
+
+// Archiver returns the registry's result archiver, nil if archival is disabled
+func (sr *ScraperRegistry) Archiver() *ResultArchiver {
+	return sr.archiver
+}
+
+// unwrapScraper returns the scraper beneath any registry wrappers
+func unwrapScraper(s Scraper) Scraper {
+	for {
+		w, ok := s.(interface{ Unwrap() Scraper })
+		if !ok {
+			return s
+		}
+		s = w.Unwrap()
+	}
+}
+
+// fallbackReporter is implemented by scrapers that report the fallback level
+// of extracted fields
+type fallbackReporter interface {
+	SetFallbackReporting(logger FallbackLogger, metrics FallbackMetrics)
+}
+
+// SetFallbackReporting makes registered scrapers, and those registered
+// afterwards, log and count the fallback level each case field is extracted
+// at, so that selector drift can be spotted
+func (sr *ScraperRegistry) SetFallbackReporting(logger FallbackLogger, metrics FallbackMetrics) {
+	sr.fallbackLogger = logger
+	sr.fallbackMetrics = metrics
+	for _, s := range sr.scrapers {
+		if f, ok := unwrapScraper(s).(fallbackReporter); ok {
+			f.SetFallbackReporting(logger, metrics)
+		}
+	}
+}
+
+// sharedRateLimited is implemented by scrapers whose rate limit can be shared
+// across workers
+type sharedRateLimited interface {
+	SetSharedRateLimit(bucket SharedBucket, logger RateLimitLogger)
+}
+
+// SetSharedRateLimit shares every registered scraper's rate limit through
+// bucket, so that workers together stay within each source's limit
+func (sr *ScraperRegistry) SetSharedRateLimit(bucket SharedBucket, logger RateLimitLogger) {
+	sr.sharedLimit = bucket
+	sr.limitLogger = logger
+	for _, s := range sr.scrapers {
+		if l, ok := unwrapScraper(s).(sharedRateLimited); ok {
+			l.SetSharedRateLimit(bucket, logger)
+		}
+	}
+}
+
+// defaultCharsetter is implemented by scrapers that can assume a charset for
+// pages that declare none
+type defaultCharsetter interface {
+	SetDefaultCharset(name string)
+}
+
+// SetDefaultCharsets sets the charset assumed for undeclared pages, keyed by
+// registered scraper name, on registered scrapers and those registered
+// afterwards. Unknown charsets are rejected.
+func (sr *ScraperRegistry) SetDefaultCharsets(charsets map[string]string) error {
+	for name, cs := range charsets {
+		if !ValidCharset(cs) {
+			return fmt.Errorf("unknown charset %q for scraper %s", cs, name)
+		}
+	}
+
+	sr.charsets = charsets
+	for name, s := range sr.scrapers {
+		cs, ok := charsets[name]
+		if !ok {
+			continue
+		}
+		if d, ok := unwrapScraper(s).(defaultCharsetter); ok {
+			d.SetDefaultCharset(cs)
+		}
+	}
+	return nil
+}
+
+// textStructurer is implemented by scrapers that can keep the structure of
+// full text
+type textStructurer interface {
+	SetTextStructure(ts *TextStructure)
+}
+
+// SetTextStructure sets the structure kept in the full text extracted by
+// registered scrapers and those registered afterwards
+func (sr *ScraperRegistry) SetTextStructure(ts *TextStructure) {
+	sr.structure = ts
+	for _, s := range sr.scrapers {
+		if t, ok := unwrapScraper(s).(textStructurer); ok {
+			t.SetTextStructure(ts)
+		}
+	}
+}
+
+// selectorOverrider is implemented by scrapers whose extraction selectors can
+// be overridden
+type selectorOverrider interface {
+	SetSelectors(selectors map[string]string)
+}
+
+// SetSelectors overrides the extraction selectors of registered scrapers and
+// those registered afterwards, keyed by registered scraper name
+func (sr *ScraperRegistry) SetSelectors(overrides SelectorOverrides) {
+	sr.selectors = overrides
+	for name, s := range sr.scrapers {
+		selectors, ok := overrides[name]
+		if !ok {
+			continue
+		}
+		if o, ok := unwrapScraper(s).(selectorOverrider); ok {
+			o.SetSelectors(selectors)
+		}
+	}
+}
+
 // GetByJurisdiction returns all scrapers for a jurisdiction
 func (sr *ScraperRegistry) GetByJurisdiction(jurisdiction string) []Scraper {
 	var result []Scraper
@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// URLBlocklist matches URLs a source must not scrape, such as index pages,
+// consolidated judgments and other non-case content that would otherwise be
+// stored as bogus cases. Each pattern is a regular expression matched against
+// both the full URL and its path, so "^/cgi-bin/" and "bailii\.org/.*/index"
+// both work.
+type URLBlocklist struct {
+	patterns []*regexp.Regexp
+}
+
+// NewURLBlocklist compiles a blocklist from regular expressions
+func NewURLBlocklist(patterns []string) (*URLBlocklist, error) {
+	b := &URLBlocklist{patterns: make([]*regexp.Regexp, 0, len(patterns))}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist pattern %q: %w", p, err)
+		}
+		b.patterns = append(b.patterns, re)
+	}
+	return b, nil
+}
+
+// Blocked reports whether rawURL matches a pattern. A nil blocklist blocks nothing.
+func (b *URLBlocklist) Blocked(rawURL string) bool {
+	if b == nil || rawURL == "" {
+		return false
+	}
+
+	path := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		path = u.Path
+	}
+	for _, re := range b.patterns {
+		if re.MatchString(rawURL) || (path != "" && re.MatchString(path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetURLBlocklist sets the URLs the scraper skips in search results and
+// refuses to fetch
+func (bs *BaseScraper) SetURLBlocklist(blocklist *URLBlocklist) {
+	bs.blocklist = blocklist
+}
+
+// IsBlocked reports whether the scraper's blocklist matches rawURL
+func (bs *BaseScraper) IsBlocked(rawURL string) bool {
+	return bs.blocklist.Blocked(rawURL)
+}
+
+// urlBlocklister is implemented by scrapers that consult a URL blocklist
+type urlBlocklister interface {
+	SetURLBlocklist(blocklist *URLBlocklist)
+	IsBlocked(rawURL string) bool
+}
+
+// IsURLBlocked reports whether s, beneath any registry wrappers, blocks rawURL
+func IsURLBlocked(s Scraper, rawURL string) bool {
+	if b, ok := unwrapScraper(s).(urlBlocklister); ok {
+		return b.IsBlocked(rawURL)
+	}
+	return false
+}
+
+// SetURLBlocklists sets the URL blocklists of registered scrapers and those
+// registered afterwards, keyed by registered scraper name
+func (sr *ScraperRegistry) SetURLBlocklists(blocklists map[string]*URLBlocklist) {
+	sr.blocklists = blocklists
+	for name, s := range sr.scrapers {
+		blocklist, ok := blocklists[name]
+		if !ok {
+			continue
+		}
+		if b, ok := unwrapScraper(s).(urlBlocklister); ok {
+			b.SetURLBlocklist(blocklist)
+		}
+	}
+}
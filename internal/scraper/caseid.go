@@ -0,0 +1,192 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// IDStrategy determines how case IDs are assigned to scraped cases
+type IDStrategy string
+
+const (
+	// IDStrategySource keeps the source-derived ID unchanged (legacy behaviour)
+	IDStrategySource IDStrategy = "source"
+	// IDStrategyPrefixed prefixes the source ID with the source name (e.g. "bailii:UKSC/2023/15")
+	// and falls back to a content hash when the source ID is missing
+	IDStrategyPrefixed IDStrategy = "prefixed"
+	// IDStrategyHash always derives the ID from citation, court and decision date
+	IDStrategyHash IDStrategy = "hash"
+)
+
+// MetadataSourceID is the metadata key under which Assign keeps the ID a case
+// has at its source, which a hash ID can't be turned back into
+const MetadataSourceID = "source_id"
+
+// CaseLookup loads a stored case by ID
+type CaseLookup func(ctx context.Context, id string) (*models.Case, error)
+
+// CaseIDGenerator assigns case IDs according to an IDStrategy
+type CaseIDGenerator struct {
+	strategy IDStrategy
+	lookup   CaseLookup
+}
+
+// NewCaseIDGenerator creates a new CaseIDGenerator
+func NewCaseIDGenerator(strategy IDStrategy) *CaseIDGenerator {
+	if strategy == "" {
+		strategy = IDStrategySource
+	}
+	return &CaseIDGenerator{strategy: strategy}
+}
+
+// Strategy returns the configured strategy
+func (g *CaseIDGenerator) Strategy() IDStrategy {
+	return g.strategy
+}
+
+// SetCaseLookup sets where hash IDs are looked up to find the source ID of
+// the stored case
+func (g *CaseIDGenerator) SetCaseLookup(lookup CaseLookup) {
+	g.lookup = lookup
+}
+
+// Assign sets the case ID for a case scraped from the given source, keeping
+// the source's own ID in the case metadata
+func (g *CaseIDGenerator) Assign(source string, c *models.Case) {
+	if c == nil {
+		return
+	}
+
+	if g.strategy != IDStrategySource && c.ID != "" && SourceCaseID(c) == "" {
+		if c.Metadata == nil {
+			c.Metadata = make(map[string]interface{})
+		}
+		c.Metadata[MetadataSourceID] = g.SourceID(source, c.ID)
+	}
+
+	switch g.strategy {
+	case IDStrategyPrefixed:
+		if id := strings.TrimSpace(c.ID); id != "" {
+			c.ID = prefixID(source, id)
+			return
+		}
+		c.ID = hashID(source, c)
+	case IDStrategyHash:
+		c.ID = hashID(source, c)
+	}
+}
+
+// SourceID strips the source prefix from an ID produced by Assign. A hash ID
+// has no source ID in it; use SourceCaseID on the case instead.
+func (g *CaseIDGenerator) SourceID(source, id string) string {
+	if g.strategy == IDStrategySource {
+		return id
+	}
+	return strings.TrimPrefix(id, strings.ToLower(source)+":")
+}
+
+// SourceCaseID returns the source's own ID kept on a case by Assign, or an
+// empty string for cases whose ID was left unchanged or had none
+func SourceCaseID(c *models.Case) string {
+	id, _ := c.Metadata[MetadataSourceID].(string)
+	return id
+}
+
+// prefixID builds a globally unique ID from a source and source-local ID
+func prefixID(source, id string) string {
+	prefix := strings.ToLower(source) + ":"
+	if strings.HasPrefix(id, prefix) {
+		return id
+	}
+	return prefix + id
+}
+
+// hashID derives a deterministic ID from citation, court and decision date,
+// using the URL when none of those were extracted
+func hashID(source string, c *models.Case) string {
+	parts := []string{
+		strings.ToLower(strings.TrimSpace(c.CaseNumber)),
+		strings.ToLower(strings.TrimSpace(c.Court)),
+	}
+	if c.DecisionDate != nil {
+		parts = append(parts, c.DecisionDate.Format("2006-01-02"))
+	} else {
+		parts = append(parts, "")
+	}
+
+	key := strings.Join(parts, "|")
+	if key == "||" {
+		if c.URL == "" {
+			return ""
+		}
+		key = c.URL
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return prefixID(source, hex.EncodeToString(sum[:8]))
+}
+
+// idAssigningScraper wraps a Scraper and assigns IDs to every returned case
+type idAssigningScraper struct {
+	Scraper
+	source    string
+	generator *CaseIDGenerator
+}
+
+// WithIDGenerator wraps a scraper so returned cases get IDs from the generator.
+// The source-derived strategy returns the scraper unchanged.
+func WithIDGenerator(source string, s Scraper, generator *CaseIDGenerator) Scraper {
+	if generator == nil || generator.Strategy() == IDStrategySource {
+		return s
+	}
+	return &idAssigningScraper{Scraper: s, source: source, generator: generator}
+}
+
+// Unwrap returns the underlying scraper
+func (s *idAssigningScraper) Unwrap() Scraper {
+	return s.Scraper
+}
+
+// SearchCases searches for cases and assigns IDs to the results
+func (s *idAssigningScraper) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
+	cases, err := s.Scraper.SearchCases(ctx, query)
+	s.assignAll(cases)
+	return cases, err
+}
+
+// GetCaseByID retrieves a case by its source ID or the ID assigned to it. A
+// hash ID is resolved through the source ID kept on the stored case.
+func (s *idAssigningScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	sourceID := s.generator.SourceID(s.source, caseID)
+	if s.generator.strategy == IDStrategyHash && s.generator.lookup != nil {
+		if stored, err := s.generator.lookup(ctx, caseID); err == nil {
+			if id := SourceCaseID(stored); id != "" {
+				sourceID = id
+			}
+		}
+	}
+
+	c, err := s.Scraper.GetCaseByID(ctx, sourceID)
+	if c != nil {
+		s.generator.Assign(s.source, c)
+	}
+	return c, err
+}
+
+// GetCasesByDateRange retrieves cases within a date range and assigns IDs to the results
+func (s *idAssigningScraper) GetCasesByDateRange(ctx context.Context, startDate, endDate time.Time, limit int) ([]*models.Case, error) {
+	cases, err := s.Scraper.GetCasesByDateRange(ctx, startDate, endDate, limit)
+	s.assignAll(cases)
+	return cases, err
+}
+
+func (s *idAssigningScraper) assignAll(cases []*models.Case) {
+	for _, c := range cases {
+		s.generator.Assign(s.source, c)
+	}
+}
@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html/charset"
+)
+
+// charsetPrescanBytes is how far into a page a <meta> charset declaration is
+// looked for, as in the HTML encoding sniffing algorithm
+const charsetPrescanBytes = 1024
+
+// DecodeHTML converts a page to UTF-8. The charset comes from the
+// Content-Type header, then a byte order mark or <meta> declaration in the
+// page. A page that declares none and isn't valid UTF-8 is decoded as
+// fallback, or as windows-1252 if fallback is empty.
+func DecodeHTML(raw []byte, contentType, fallback string) ([]byte, error) {
+	enc, name, certain := charset.DetermineEncoding(raw, contentType)
+	if !certain && name == "windows-1252" && fallback != "" && !declaresCharset(raw) {
+		if e, _ := charset.Lookup(fallback); e != nil {
+			enc = e
+		}
+	}
+	return enc.NewDecoder().Bytes(raw)
+}
+
+// declaresCharset reports whether the start of a page may hold a <meta>
+// charset declaration
+func declaresCharset(raw []byte) bool {
+	if len(raw) > charsetPrescanBytes {
+		raw = raw[:charsetPrescanBytes]
+	}
+	return bytes.Contains(bytes.ToLower(raw), []byte("charset"))
+}
+
+// ReadDocumentWithCharset reads a body served with contentType, decodes it to
+// UTF-8 and parses it. The raw HTML is returned undecoded for dumping.
+func ReadDocumentWithCharset(body io.Reader, contentType, fallback string) (*goquery.Document, []byte, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoded, err := DecodeHTML(raw, contentType, fallback)
+	if err != nil {
+		return nil, raw, fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(decoded))
+	if err != nil {
+		return nil, raw, err
+	}
+
+	return doc, raw, nil
+}
+
+// ValidCharset reports whether name is a charset pages can be decoded from
+func ValidCharset(name string) bool {
+	e, _ := charset.Lookup(name)
+	return e != nil
+}
+
+// SetDefaultCharset sets the charset assumed for pages that declare none and
+// aren't valid UTF-8, e.g. gbk for a source serving undeclared Chinese pages
+func (bs *BaseScraper) SetDefaultCharset(name string) {
+	bs.charset = name
+}
+
+// ReadResponse reads an HTML response and parses it, decoding it to UTF-8
+// first. The raw HTML is returned alongside the document so it can be dumped
+// if extraction fails.
+func (bs *BaseScraper) ReadResponse(resp *http.Response) (*goquery.Document, []byte, error) {
+	return ReadDocumentWithCharset(resp.Body, resp.Header.Get("Content-Type"), bs.charset)
+}
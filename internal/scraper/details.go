@@ -0,0 +1,160 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultDetailConcurrency bounds concurrent detail fetches from sources
+// without a concurrency limit of their own
+const DefaultDetailConcurrency = 4
+
+// DetailError is a case whose full details could not be fetched
+type DetailError struct {
+	CaseID string `json:"case_id"`
+	Error  string `json:"error"`
+}
+
+// DetailResult holds the cases from a detail fetch, in their original order,
+// and the cases that failed. A case that failed is kept as it was found. Err
+// is set when the fetch gave up early because the run's retry budget was
+// spent; the cases it did not get to are reported as failed.
+type DetailResult struct {
+	Cases  []*models.Case `json:"cases"`
+	Errors []DetailError  `json:"errors,omitempty"`
+	Err    error          `json:"-"`
+}
+
+// concurrencyLimited is implemented by scrapers with a limit on concurrent
+// requests to their source
+type concurrencyLimited interface {
+	SetConcurrencyLimit(limit int)
+	GetConcurrencyLimit() int
+}
+
+// SetConcurrencyLimit sets how many requests to the source may be in flight at once
+func (bs *BaseScraper) SetConcurrencyLimit(limit int) {
+	bs.concurrency = limit
+}
+
+// GetConcurrencyLimit returns the concurrent request limit, 0 if unset
+func (bs *BaseScraper) GetConcurrencyLimit() int {
+	return bs.concurrency
+}
+
+// ConcurrencyLimit returns the concurrency limit of s, beneath any registry
+// wrappers, or DefaultDetailConcurrency if it has none
+func ConcurrencyLimit(s Scraper) int {
+	if l, ok := unwrapScraper(s).(concurrencyLimited); ok && l.GetConcurrencyLimit() > 0 {
+		return l.GetConcurrencyLimit()
+	}
+	return DefaultDetailConcurrency
+}
+
+// FetchDetails replaces cases found in search results with their full
+// details from GetCaseByID, fetching up to the source's concurrency limit at
+// once. Each fetch still waits on the scraper's rate limiter, which the
+// concurrent fetches share, so the bound caps requests in flight without
+// raising the request rate.
+func FetchDetails(ctx context.Context, s Scraper, cases []*models.Case) *DetailResult {
+	return FetchDetailsWithRetry(ctx, s, cases, nil)
+}
+
+// FetchDetailsWithRetry fetches details as FetchDetails does, retrying failed
+// fetches with retrier. Once the run's retry budget is spent, the remaining
+// fetches are abandoned and the result's Err is set.
+func FetchDetailsWithRetry(ctx context.Context, s Scraper, cases []*models.Case, retrier *Retrier) *DetailResult {
+	result := &DetailResult{Cases: make([]*models.Case, len(cases))}
+	failures := make([]error, len(cases))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var exhausted sync.Once
+
+	sem := make(chan struct{}, ConcurrencyLimit(s))
+	var wg sync.WaitGroup
+	for i, c := range cases {
+		result.Cases[i] = c
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			failures[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, c *models.Case) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := ctx.Err(); err != nil {
+				failures[i] = err
+				return
+			}
+
+			// A hash ID can't be fetched, the source's own ID can
+			id := c.ID
+			if sourceID := SourceCaseID(c); sourceID != "" {
+				id = sourceID
+			}
+
+			var detail *models.Case
+			err := retrier.Do(ctx, func() error {
+				var err error
+				detail, err = s.GetCaseByID(ctx, id)
+				return err
+			})
+			if err != nil {
+				failures[i] = err
+				if errors.Is(err, kiteerrors.ErrRetryBudgetExhausted) {
+					exhausted.Do(func() {
+						result.Err = err
+						cancel()
+					})
+				}
+				return
+			}
+			if detail == nil {
+				return
+			}
+			if detail.URL == "" {
+				detail.URL = c.URL
+			}
+			result.Cases[i] = detail
+		}(i, c)
+	}
+	wg.Wait()
+
+	for i, err := range failures {
+		if err != nil {
+			result.Errors = append(result.Errors, DetailError{CaseID: cases[i].ID, Error: err.Error()})
+		}
+	}
+	return result
+}
+
+// SetConcurrencyLimits sets the concurrent request limit of registered
+// scrapers and those registered afterwards: limits[name] where set, and
+// otherwise defaultLimit
+func (sr *ScraperRegistry) SetConcurrencyLimits(defaultLimit int, limits map[string]int) {
+	sr.concurrency = defaultLimit
+	sr.concurrencies = limits
+	for name, s := range sr.scrapers {
+		if l, ok := unwrapScraper(s).(concurrencyLimited); ok {
+			l.SetConcurrencyLimit(sr.concurrencyLimit(name))
+		}
+	}
+}
+
+// concurrencyLimit returns the concurrent request limit for a scraper name
+func (sr *ScraperRegistry) concurrencyLimit(name string) int {
+	if limit, ok := sr.concurrencies[name]; ok && limit > 0 {
+		return limit
+	}
+	return sr.concurrency
+}
@@ -0,0 +1,21 @@
+package scraper
+
+import (
+	"context"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// EachUntilDone calls fn for each element of sel, like Selection.EachWithBreak,
+// but stops once ctx is done so that a cancelled scrape doesn't keep parsing
+// a large page. It returns ctx.Err() if extraction was cut short by the
+// context.
+func EachUntilDone(ctx context.Context, sel *goquery.Selection, fn func(i int, s *goquery.Selection) bool) error {
+	sel.EachWithBreak(func(i int, s *goquery.Selection) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		return fn(i, s)
+	})
+	return ctx.Err()
+}
@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// MetadataExtractionFallbacks is the metadata key under which scrapers
+// record, by field, the fallback level a case field was extracted at: 0 when
+// the primary selector matched, 1 for the first fallback and so on. Fields no
+// selector found are left out.
+const MetadataExtractionFallbacks = "extraction_fallbacks"
+
+// FallbackLogger receives debug messages about fields extracted by fallbacks
+type FallbackLogger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// FallbackMetrics records the fallback level each extracted field came from
+type FallbackMetrics interface {
+	RecordExtractionFallback(source, field string, level int)
+}
+
+// SetFallbackReporting sets where the scraper logs and counts fields
+// extracted by fallbacks (nil disables either)
+func (bs *BaseScraper) SetFallbackReporting(logger FallbackLogger, metrics FallbackMetrics) {
+	bs.fallbackLogger = logger
+	bs.fallbackMetrics = metrics
+}
+
+// RecordFallback records on c that field was extracted at fallback level, so
+// that a primary selector that stops matching shows up in case metadata,
+// logs and metrics rather than only as worse data. Nothing is recorded when
+// value is empty.
+func (bs *BaseScraper) RecordFallback(c *models.Case, field, value string, level int) {
+	if value == "" {
+		return
+	}
+
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	levels, ok := c.Metadata[MetadataExtractionFallbacks].(map[string]int)
+	if !ok {
+		levels = make(map[string]int)
+		c.Metadata[MetadataExtractionFallbacks] = levels
+	}
+	levels[field] = level
+
+	if bs.fallbackMetrics != nil {
+		bs.fallbackMetrics.RecordExtractionFallback(bs.name, field, level)
+	}
+	if level > 0 && bs.fallbackLogger != nil {
+		bs.fallbackLogger.Debugf("%s: %s of %s: fallback level %d", bs.name, field, c.URL, level)
+	}
+}
@@ -0,0 +1,110 @@
+package scraper
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultMaxFullTextBytes is the default cap on the full text kept from a
+// scraped case
+const DefaultMaxFullTextBytes = 5 << 20
+
+// Metadata keys set on cases whose full text was cut at scrape time. The
+// stored text itself is shortened, unlike truncation in API responses and
+// exports, which leaves storage intact.
+const (
+	MetadataFullTextTruncated     = "scrape_full_text_truncated"
+	MetadataFullTextOriginalBytes = "scrape_full_text_original_bytes"
+)
+
+// FullTextLogger receives warnings about truncated full text
+type FullTextLogger interface {
+	Warnf(format string, args ...interface{})
+}
+
+// TruncateFullText cuts a case's full text to at most maxBytes, on a UTF-8
+// character boundary, and flags the case in its metadata. It reports whether
+// the text was cut; a maxBytes of zero or less leaves the case unchanged.
+func TruncateFullText(c *models.Case, maxBytes int) bool {
+	if c == nil || maxBytes <= 0 || len(c.FullText) <= maxBytes {
+		return false
+	}
+
+	original := len(c.FullText)
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(c.FullText[cut]) {
+		cut--
+	}
+	c.FullText = c.FullText[:cut]
+
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]interface{})
+	}
+	c.Metadata[MetadataFullTextTruncated] = true
+	c.Metadata[MetadataFullTextOriginalBytes] = original
+	return true
+}
+
+// fullTextCappingScraper wraps a Scraper and truncates oversized full text
+// in every returned case
+type fullTextCappingScraper struct {
+	Scraper
+	maxBytes int
+	logger   FullTextLogger
+}
+
+// WithFullTextLimit wraps a scraper so returned cases keep at most maxBytes
+// of full text, logging a warning with the case URL whenever text is cut. A
+// maxBytes of zero or less returns the scraper unchanged.
+func WithFullTextLimit(s Scraper, maxBytes int, logger FullTextLogger) Scraper {
+	if maxBytes <= 0 {
+		return s
+	}
+	return &fullTextCappingScraper{Scraper: s, maxBytes: maxBytes, logger: logger}
+}
+
+// Unwrap returns the underlying scraper
+func (s *fullTextCappingScraper) Unwrap() Scraper {
+	return s.Scraper
+}
+
+// SearchCases searches for cases and caps their full text
+func (s *fullTextCappingScraper) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
+	cases, err := s.Scraper.SearchCases(ctx, query)
+	s.capAll(cases)
+	return cases, err
+}
+
+// GetCaseByID retrieves a case and caps its full text
+func (s *fullTextCappingScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	c, err := s.Scraper.GetCaseByID(ctx, caseID)
+	s.capFullText(c)
+	return c, err
+}
+
+// GetCasesByDateRange retrieves cases within a date range and caps their full text
+func (s *fullTextCappingScraper) GetCasesByDateRange(ctx context.Context, startDate, endDate time.Time, limit int) ([]*models.Case, error) {
+	cases, err := s.Scraper.GetCasesByDateRange(ctx, startDate, endDate, limit)
+	s.capAll(cases)
+	return cases, err
+}
+
+func (s *fullTextCappingScraper) capAll(cases []*models.Case) {
+	for _, c := range cases {
+		s.capFullText(c)
+	}
+}
+
+func (s *fullTextCappingScraper) capFullText(c *models.Case) {
+	if c == nil {
+		return
+	}
+	original := len(c.FullText)
+	if TruncateFullText(c, s.maxBytes) && s.logger != nil {
+		s.logger.Warnf("%s: truncated full text of case %s from %d to %d bytes (%s)",
+			s.GetName(), c.ID, original, len(c.FullText), c.URL)
+	}
+}
@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// HTMLDumpConfig configures saving raw HTML when extraction fails
+type HTMLDumpConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Dir       string        `yaml:"dir"`
+	MaxBytes  int           `yaml:"max_bytes"`
+	Retention time.Duration `yaml:"retention"`
+}
+
+// DefaultHTMLDumpConfig returns the default HTML dump configuration (disabled)
+func DefaultHTMLDumpConfig() HTMLDumpConfig {
+	return HTMLDumpConfig{
+		Enabled:   false,
+		Dir:       "./debug/html",
+		MaxBytes:  1 << 20, // 1MB
+		Retention: 72 * time.Hour,
+	}
+}
+
+// HTMLDumper writes raw response HTML to disk for debugging parse failures
+type HTMLDumper struct {
+	config HTMLDumpConfig
+	now    func() time.Time
+}
+
+// NewHTMLDumper creates a new HTMLDumper
+func NewHTMLDumper(config HTMLDumpConfig) *HTMLDumper {
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultHTMLDumpConfig().MaxBytes
+	}
+	if config.Dir == "" {
+		config.Dir = DefaultHTMLDumpConfig().Dir
+	}
+
+	return &HTMLDumper{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Dump saves the HTML for a URL, keyed by URL and timestamp, and returns the file path.
+// HTML larger than MaxBytes is truncated.
+func (d *HTMLDumper) Dump(pageURL string, html []byte) (string, error) {
+	if d == nil || !d.config.Enabled {
+		return "", nil
+	}
+
+	if err := os.MkdirAll(d.config.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create dump directory: %w", err)
+	}
+
+	if len(html) > d.config.MaxBytes {
+		html = html[:d.config.MaxBytes]
+	}
+
+	path := filepath.Join(d.config.Dir, dumpFileName(pageURL, d.now()))
+	if err := os.WriteFile(path, html, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write HTML dump: %w", err)
+	}
+
+	d.Cleanup()
+
+	return path, nil
+}
+
+// Cleanup removes dumps older than the retention period
+func (d *HTMLDumper) Cleanup() {
+	if d == nil || d.config.Retention <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(d.config.Dir)
+	if err != nil {
+		return
+	}
+
+	cutoff := d.now().Add(-d.config.Retention)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".html") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(d.config.Dir, entry.Name()))
+		}
+	}
+}
+
+// dumpFileName builds a filesystem-safe name from the URL and timestamp
+func dumpFileName(pageURL string, ts time.Time) string {
+	key := pageURL
+	if u, err := url.Parse(pageURL); err == nil && u.Host != "" {
+		key = u.Host + u.Path
+	}
+
+	key = strings.Trim(unsafeFileChars.ReplaceAllString(key, "_"), "_")
+	if len(key) > 150 {
+		key = key[:150]
+	}
+
+	return fmt.Sprintf("%s_%s.html", key, ts.UTC().Format("20060102T150405.000000000"))
+}
+
+// ReadDocument reads a response body and parses it, returning the raw HTML alongside
+// the document so it can be dumped if extraction fails. The page is decoded to
+// UTF-8 from the charset its <meta> tags declare.
+func ReadDocument(body io.Reader) (*goquery.Document, []byte, error) {
+	return ReadDocumentWithCharset(body, "", "")
+}
+
+// MeetsMinimumValidity reports whether an extracted case has the fields needed to be useful
+func MeetsMinimumValidity(c *models.Case) bool {
+	if c == nil {
+		return false
+	}
+	if strings.TrimSpace(c.CaseName) == "" {
+		return false
+	}
+	return strings.TrimSpace(c.CaseNumber) != "" || c.DecisionDate != nil
+}
@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"sort"
+	"strings"
+)
+
+// JurisdictionFilter restricts which jurisdictions are enabled in a deployment.
+// A nil or empty filter enables every jurisdiction.
+type JurisdictionFilter struct {
+	enabled map[string]string
+}
+
+// NewJurisdictionFilter creates a filter enabling only the given jurisdictions (matched case-insensitively)
+func NewJurisdictionFilter(jurisdictions []string) *JurisdictionFilter {
+	f := &JurisdictionFilter{enabled: make(map[string]string)}
+	for _, j := range jurisdictions {
+		if key := normalizeJurisdiction(j); key != "" {
+			f.enabled[key] = strings.TrimSpace(j)
+		}
+	}
+	return f
+}
+
+// Enabled reports whether a jurisdiction is enabled
+func (f *JurisdictionFilter) Enabled(jurisdiction string) bool {
+	if f == nil || len(f.enabled) == 0 {
+		return true
+	}
+	_, ok := f.enabled[normalizeJurisdiction(jurisdiction)]
+	return ok
+}
+
+// All reports whether every jurisdiction is enabled
+func (f *JurisdictionFilter) All() bool {
+	return f == nil || len(f.enabled) == 0
+}
+
+// List returns the enabled jurisdictions, or nil when all are enabled
+func (f *JurisdictionFilter) List() []string {
+	if f.All() {
+		return nil
+	}
+	list := make([]string, 0, len(f.enabled))
+	for _, j := range f.enabled {
+		list = append(list, j)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func normalizeJurisdiction(jurisdiction string) string {
+	return strings.ToLower(strings.TrimSpace(jurisdiction))
+}
@@ -78,7 +78,7 @@ func (as *AustLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQ
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := as.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
@@ -86,19 +86,23 @@ func (as *AustLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQ
 	// Extract cases from search results
 	cases := make([]*models.Case, 0)
 
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 
 		// Check if this is a result item
 		if s.Find("a").Length() > 0 {
 			caseData := as.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !as.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -107,6 +111,9 @@ func (as *AustLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQ
 func (as *AustLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	// AustLII case URLs are typically: /au/cases/cth/HCA/2023/15.html
 	caseURL := as.buildCaseURL(caseID)
+	if as.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	// Check robots.txt
 	allowed, err := as.BaseScraper.client.CheckRobots(ctx, "/")
@@ -142,13 +149,19 @@ func (as *AustLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*mode
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := as.ReadResponse(resp)
 	if err != nil {
+		as.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	// Extract case details
-	return as.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := as.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		as.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -178,6 +191,14 @@ func (as *AustLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// austliiSampleCaseID identifies the High Court's decision in Mabo v Queensland (No 2), fetched by Validate
+const austliiSampleCaseID = "/au/cases/cth/HCA/1992/23.html"
+
+// Validate checks that a known AustLII case still scrapes with its core fields
+func (as *AustLIIScraper) Validate(ctx context.Context) error {
+	return as.ValidateSample(ctx, as.GetCaseByID, austliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (as *AustLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -227,7 +248,7 @@ func (as *AustLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mod
 	c := models.NewCase()
 
 	// Extract case name and URL from first link
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(as.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -283,8 +304,8 @@ func (as *AustLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mod
 
 	// Set metadata
 	c.SourceDatabase = "AustLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = as.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -299,19 +320,21 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 	c.URL = caseURL
 
 	// Extract case name
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(as.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	as.RecordFallback(c, "case_name", c.CaseName, level)
 
 	// Extract neutral citation
-	citation := doc.Find("center").First().Text()
+	citation, level := doc.Find(as.Selector("citation", "center")).First().Text(), 0
 	if citation == "" {
 		// Try to find in title or first paragraph
-		citation = doc.Find("p").First().Text()
+		citation, level = doc.Find("p").First().Text(), 1
 	}
 	c.CaseNumber = strings.TrimSpace(citation)
+	as.RecordFallback(c, "citation", c.CaseNumber, level)
 
 	// Extract court from URL
 	if strings.Contains(caseURL, "/HCA/") {
@@ -331,7 +354,7 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 	}
 
 	// Extract date
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(as.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Hearing date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := text
@@ -360,7 +383,7 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 	})
 
 	// Extract judges
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(as.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Before:") || strings.Contains(text, "Judges:") {
 			// Extract judge names after "Before:" or "Judges:"
@@ -382,7 +405,7 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 	})
 
 	// Extract docket/case number
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(as.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Case No") || strings.Contains(text, "Matter No") {
 			c.Docket = strings.TrimSpace(text)
@@ -391,7 +414,7 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 	})
 
 	// Extract full judgment text
-	fullText := doc.Find("body").Text()
+	fullText := as.ExtractText(doc.Find(as.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	// Set jurisdiction
@@ -399,8 +422,8 @@ func (as *AustLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, case
 
 	// Set metadata
 	c.SourceDatabase = "AustLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = as.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
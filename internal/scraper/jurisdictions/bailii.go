@@ -78,7 +78,7 @@ func (bs *BAILIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := bs.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
@@ -86,16 +86,20 @@ func (bs *BAILIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 	// Extract cases from search results
 	cases := make([]*models.Case, 0)
 
-	doc.Find("li.resultItem").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li.resultItem"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 
 		caseData := bs.extractCaseFromSearchResult(s)
-		if caseData != nil {
+		if caseData != nil && !bs.IsBlocked(caseData.URL) {
 			cases = append(cases, caseData)
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -105,6 +109,9 @@ func (bs *BAILIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 	// BAILII case IDs are typically in format: UKSC/2023/15
 	// Build case URL from ID
 	caseURL := bs.buildCaseURL(caseID)
+	if bs.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	// Check robots.txt
 	allowed, err := bs.BaseScraper.client.CheckRobots(ctx, "/")
@@ -140,13 +147,19 @@ func (bs *BAILIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := bs.ReadResponse(resp)
 	if err != nil {
+		bs.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	// Extract case details
-	return bs.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := bs.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		bs.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -176,6 +189,14 @@ func (bs *BAILIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// bailiiSampleCaseID identifies the Supreme Court's decision in R (Miller) v The Prime Minister, fetched by Validate
+const bailiiSampleCaseID = "UKSC/2019/41"
+
+// Validate checks that a known BAILII case still scrapes with its core fields
+func (bs *BAILIIScraper) Validate(ctx context.Context) error {
+	return bs.ValidateSample(ctx, bs.GetCaseByID, bailiiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (bs *BAILIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -242,7 +263,7 @@ func (bs *BAILIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	c := models.NewCase()
 
 	// Extract case name and URL
-	titleLink := s.Find("a.resultTitle")
+	titleLink := s.Find(bs.Selector("result_link", "a.resultTitle"))
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -266,15 +287,15 @@ func (bs *BAILIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	}
 
 	// Extract citation
-	citation := s.Find(".resultCitation").Text()
+	citation := s.Find(bs.Selector("result_citation", ".resultCitation")).Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court from metadata
-	court := s.Find(".resultCourt").Text()
+	court := s.Find(bs.Selector("result_court", ".resultCourt")).Text()
 	c.Court = strings.TrimSpace(court)
 
 	// Extract date from metadata
-	dateStr := s.Find(".resultDate").Text()
+	dateStr := s.Find(bs.Selector("result_date", ".resultDate")).Text()
 	if dateStr != "" {
 		// Try to parse various date formats used by BAILII
 		formats := []string{
@@ -292,7 +313,7 @@ func (bs *BAILIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	}
 
 	// Extract snippet/summary
-	snippet := s.Find(".resultSnippet").Text()
+	snippet := s.Find(bs.Selector("result_snippet", ".resultSnippet")).Text()
 	c.Summary = strings.TrimSpace(snippet)
 
 	// Determine jurisdiction from URL or court
@@ -304,8 +325,8 @@ func (bs *BAILIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 
 	// Set metadata
 	c.SourceDatabase = "BAILII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = bs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -320,27 +341,30 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	c.URL = caseURL
 
 	// Extract case name - BAILII uses various selectors
-	caseName := doc.Find("h1.case-title").First().Text()
+	caseName, level := doc.Find(bs.Selector("case_name", "h1.case-title")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("blockquote > p > b").First().Text()
+		caseName, level = doc.Find("blockquote > p > b").First().Text(), 1
 	}
 	if caseName == "" {
-		caseName = doc.Find("h2").First().Text()
+		caseName, level = doc.Find("h2").First().Text(), 2
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	bs.RecordFallback(c, "case_name", c.CaseName, level)
 
 	// Extract neutral citation
-	citation := doc.Find(".citation").Text()
+	citation, level := doc.Find(bs.Selector("citation", ".citation")).Text(), 0
 	if citation == "" {
 		// Try alternative selectors
-		citation = doc.Find("p:contains('[')").First().Text()
+		citation, level = doc.Find("p:contains('[')").First().Text(), 1
 	}
 	c.CaseNumber = strings.TrimSpace(citation)
+	bs.RecordFallback(c, "citation", c.CaseNumber, level)
 
 	// Extract court
-	court := doc.Find(".court").Text()
+	court, level := doc.Find(bs.Selector("court", ".court")).Text(), 0
 	if court == "" {
 		// Extract from citation/URL
+		level = 1
 		if strings.Contains(caseID, "UKSC") {
 			court = "UK Supreme Court"
 		} else if strings.Contains(caseID, "EWCA") {
@@ -350,12 +374,14 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		}
 	}
 	c.Court = strings.TrimSpace(court)
+	bs.RecordFallback(c, "court", c.Court, level)
 
 	// Extract date
-	dateStr := doc.Find(".judgment-date").Text()
+	dateStr, level := doc.Find(bs.Selector("date", ".judgment-date")).Text(), 0
 	if dateStr == "" {
 		// Try to find date in various formats
-		doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		level = 1
+		doc.Find(bs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 			text := s.Text()
 			if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment Date:") {
 				dateStr = text
@@ -386,9 +412,11 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 			}
 		}
 	}
+	bs.RecordFallback(c, "date", dateStr, level)
 
 	// Extract judges
-	doc.Find(".judge").Each(func(i int, s *goquery.Selection) {
+	level = 0
+	doc.Find(bs.Selector("judges", ".judge")).Each(func(i int, s *goquery.Selection) {
 		judge := strings.TrimSpace(s.Text())
 		if judge != "" {
 			c.Judges = append(c.Judges, judge)
@@ -397,6 +425,7 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 
 	// If no judges found with .judge selector, try alternative
 	if len(c.Judges) == 0 {
+		level = 1
 		doc.Find("p:contains('Before:')").Each(func(i int, s *goquery.Selection) {
 			text := s.Text()
 			// Extract judge names after "Before:"
@@ -416,11 +445,13 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 			}
 		})
 	}
+	bs.RecordFallback(c, "judges", strings.Join(c.Judges, ", "), level)
 
 	// Extract docket/case number
-	docket := doc.Find(".docket-number").Text()
+	docket, level := doc.Find(bs.Selector("docket", ".docket-number")).Text(), 0
 	if docket == "" {
-		doc.Find("p").Each(func(i int, s *goquery.Selection) {
+		level = 1
+		doc.Find(bs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 			text := s.Text()
 			if strings.Contains(text, "Case No:") || strings.Contains(text, "Case Number:") {
 				docket = text
@@ -429,17 +460,19 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		})
 	}
 	c.Docket = strings.TrimSpace(docket)
+	bs.RecordFallback(c, "docket", c.Docket, level)
 
 	// Extract full judgment text
-	fullText := doc.Find(".judgment-body").Text()
+	fullText, level := bs.ExtractText(doc.Find(bs.Selector("full_text", ".judgment-body"))), 0
 	if fullText == "" {
 		// Try alternative selectors
-		fullText = doc.Find("ol[type='1']").Text()
+		fullText, level = bs.ExtractText(doc.Find("ol[type='1']")), 1
 		if fullText == "" {
-			fullText = doc.Find("blockquote").Text()
+			fullText, level = bs.ExtractText(doc.Find("blockquote")), 2
 		}
 	}
 	c.FullText = strings.TrimSpace(fullText)
+	bs.RecordFallback(c, "full_text", c.FullText, level)
 
 	// Determine jurisdiction from URL
 	if strings.Contains(caseURL, "/ie/") {
@@ -450,8 +483,8 @@ func (bs *BAILIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 
 	// Set metadata
 	c.SourceDatabase = "BAILII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = bs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
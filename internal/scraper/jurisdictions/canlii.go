@@ -78,7 +78,7 @@ func (cs *CanLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := cs.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
@@ -86,16 +86,20 @@ func (cs *CanLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 	// Extract cases from search results
 	cases := make([]*models.Case, 0)
 
-	doc.Find(".result").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find(".result"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 
 		caseData := cs.extractCaseFromSearchResult(s)
-		if caseData != nil {
+		if caseData != nil && !cs.IsBlocked(caseData.URL) {
 			cases = append(cases, caseData)
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -105,6 +109,9 @@ func (cs *CanLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 	// CanLII case IDs are typically in format: 2023scc15
 	// Parse to determine court and build URL
 	caseURL := cs.buildCaseURL(caseID)
+	if cs.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	// Check robots.txt
 	allowed, err := cs.BaseScraper.client.CheckRobots(ctx, "/en/ca/")
@@ -140,13 +147,19 @@ func (cs *CanLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := cs.ReadResponse(resp)
 	if err != nil {
+		cs.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	// Extract case details
-	return cs.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := cs.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		cs.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -176,6 +189,14 @@ func (cs *CanLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// canliiSampleCaseID identifies the Supreme Court's decision in Canada v Vavilov, fetched by Validate
+const canliiSampleCaseID = "2019scc65"
+
+// Validate checks that a known CanLII case still scrapes with its core fields
+func (cs *CanLIIScraper) Validate(ctx context.Context) error {
+	return cs.ValidateSample(ctx, cs.GetCaseByID, canliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (cs *CanLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -221,11 +242,11 @@ func (cs *CanLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	c := models.NewCase()
 
 	// Extract case name
-	caseName := s.Find(".resultTitle a").Text()
+	caseName := s.Find(cs.Selector("result_link", ".resultTitle a")).Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
 	// Extract case URL and ID
-	caseURL, exists := s.Find(".resultTitle a").Attr("href")
+	caseURL, exists := s.Find(cs.Selector("result_link", ".resultTitle a")).Attr("href")
 	if exists {
 		if !strings.HasPrefix(caseURL, "http") {
 			caseURL = cs.baseURL + caseURL
@@ -240,18 +261,18 @@ func (cs *CanLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	}
 
 	// Extract citation
-	citation := s.Find(".resultCitation").Text()
+	citation := s.Find(cs.Selector("result_citation", ".resultCitation")).Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court and date from metadata
-	metadata := s.Find(".resultMeta").Text()
+	metadata := s.Find(cs.Selector("result_metadata", ".resultMeta")).Text()
 	c.Summary = strings.TrimSpace(metadata)
 
 	// Set basic metadata
 	c.Jurisdiction = "Canada"
 	c.SourceDatabase = "CanLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -266,19 +287,19 @@ func (cs *CanLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	c.URL = caseURL
 
 	// Extract case name
-	caseName := doc.Find("h1.documentTitle").Text()
+	caseName := doc.Find(cs.Selector("case_name", "h1.documentTitle")).Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
 	// Extract citation
-	citation := doc.Find(".documentCitation").Text()
+	citation := doc.Find(cs.Selector("citation", ".documentCitation")).Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court
-	court := doc.Find(".court").Text()
+	court := doc.Find(cs.Selector("court", ".court")).Text()
 	c.Court = strings.TrimSpace(court)
 
 	// Extract date
-	dateStr := doc.Find(".documentDate").Text()
+	dateStr := doc.Find(cs.Selector("date", ".documentDate")).Text()
 	if dateStr != "" {
 		// Try to parse various date formats
 		formats := []string{"2006-01-02", "January 2, 2006", "02-01-2006"}
@@ -291,15 +312,15 @@ func (cs *CanLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	}
 
 	// Extract docket number
-	docket := doc.Find(".docketNumber").Text()
+	docket := doc.Find(cs.Selector("docket", ".docketNumber")).Text()
 	c.Docket = strings.TrimSpace(docket)
 
 	// Extract full text
-	fullText := doc.Find(".documentContent").Text()
+	fullText := cs.ExtractText(doc.Find(cs.Selector("full_text", ".documentContent")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	// Extract judges
-	doc.Find(".judge").Each(func(i int, s *goquery.Selection) {
+	doc.Find(cs.Selector("judges", ".judge")).Each(func(i int, s *goquery.Selection) {
 		judge := strings.TrimSpace(s.Text())
 		if judge != "" {
 			c.Judges = append(c.Judges, judge)
@@ -309,8 +330,8 @@ func (cs *CanLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	// Set metadata
 	c.Jurisdiction = "Canada"
 	c.SourceDatabase = "CanLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
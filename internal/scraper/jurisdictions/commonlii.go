@@ -73,23 +73,27 @@ func (cs *CommonLIIScraper) SearchCases(ctx context.Context, query scraper.Searc
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := cs.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		if s.Find("a").Length() > 0 {
 			caseData := cs.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !cs.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -97,6 +101,9 @@ func (cs *CommonLIIScraper) SearchCases(ctx context.Context, query scraper.Searc
 // GetCaseByID retrieves a specific case by its ID
 func (cs *CommonLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := cs.buildCaseURL(caseID)
+	if cs.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := cs.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -128,12 +135,18 @@ func (cs *CommonLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*mo
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := cs.ReadResponse(resp)
 	if err != nil {
+		cs.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return cs.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := cs.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		cs.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -160,6 +173,14 @@ func (cs *CommonLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// commonliiSampleCaseID identifies the House of Lords' decision in Donoghue v Stevenson, fetched by Validate
+const commonliiSampleCaseID = "/uk/cases/UKHL/1932/100.html"
+
+// Validate checks that a known CommonLII case still scrapes with its core fields
+func (cs *CommonLIIScraper) Validate(ctx context.Context) error {
+	return cs.ValidateSample(ctx, cs.GetCaseByID, commonliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (cs *CommonLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -193,7 +214,7 @@ func (cs *CommonLIIScraper) buildCaseURL(caseID string) string {
 func (cs *CommonLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(cs.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -223,8 +244,8 @@ func (cs *CommonLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *m
 
 	c.Jurisdiction = "Commonwealth"
 	c.SourceDatabase = "CommonLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -237,16 +258,17 @@ func (cs *CommonLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, ca
 	c.ID = caseID
 	c.URL = caseURL
 
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(cs.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	cs.RecordFallback(c, "case_name", c.CaseName, level)
 
-	citation := doc.Find("center").First().Text()
+	citation := doc.Find(cs.Selector("citation", "center")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(cs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -278,13 +300,13 @@ func (cs *CommonLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, ca
 		}
 	})
 
-	fullText := doc.Find("body").Text()
+	fullText := cs.ExtractText(doc.Find(cs.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.Jurisdiction = "Commonwealth"
 	c.SourceDatabase = "CommonLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -78,7 +78,7 @@ func (cls *CourtListenerScraper) SearchCases(ctx context.Context, query scraper.
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := cls.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
@@ -86,16 +86,20 @@ func (cls *CourtListenerScraper) SearchCases(ctx context.Context, query scraper.
 	// Extract cases from search results
 	cases := make([]*models.Case, 0)
 
-	doc.Find("article.search-document").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("article.search-document"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 
 		caseData := cls.extractCaseFromSearchResult(s)
-		if caseData != nil {
+		if caseData != nil && !cls.IsBlocked(caseData.URL) {
 			cases = append(cases, caseData)
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -104,6 +108,9 @@ func (cls *CourtListenerScraper) SearchCases(ctx context.Context, query scraper.
 func (cls *CourtListenerScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	// CourtListener uses opinion IDs in URLs
 	caseURL := fmt.Sprintf("%s/opinion/%s/", cls.baseURL, caseID)
+	if cls.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	// Check robots.txt
 	allowed, err := cls.BaseScraper.client.CheckRobots(ctx, "/opinion/")
@@ -139,13 +146,19 @@ func (cls *CourtListenerScraper) GetCaseByID(ctx context.Context, caseID string)
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := cls.ReadResponse(resp)
 	if err != nil {
+		cls.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	// Extract case details
-	return cls.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := cls.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		cls.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -175,6 +188,14 @@ func (cls *CourtListenerScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// courtlistenerSampleCaseID identifies the Supreme Court's opinion in Brown v. Board of Education, fetched by Validate
+const courtlistenerSampleCaseID = "105221"
+
+// Validate checks that a known CourtListener case still scrapes with its core fields
+func (cls *CourtListenerScraper) Validate(ctx context.Context) error {
+	return cls.ValidateSample(ctx, cls.GetCaseByID, courtlistenerSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (cls *CourtListenerScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -207,11 +228,11 @@ func (cls *CourtListenerScraper) extractCaseFromSearchResult(s *goquery.Selectio
 	c := models.NewCase()
 
 	// Extract case name
-	caseName := s.Find("h3.bottom a").Text()
+	caseName := s.Find(cls.Selector("result_link", "h3.bottom a")).Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
 	// Extract case URL and ID
-	caseURL, exists := s.Find("h3.bottom a").Attr("href")
+	caseURL, exists := s.Find(cls.Selector("result_link", "h3.bottom a")).Attr("href")
 	if exists {
 		c.URL = cls.baseURL + caseURL
 		// Extract ID from URL (e.g., /opinion/123456/case-name/)
@@ -222,11 +243,11 @@ func (cls *CourtListenerScraper) extractCaseFromSearchResult(s *goquery.Selectio
 	}
 
 	// Extract court
-	court := s.Find(".meta-data-header").Text()
+	court := s.Find(cls.Selector("result_court", ".meta-data-header")).Text()
 	c.Court = strings.TrimSpace(court)
 
 	// Extract date
-	dateStr := s.Find(".meta-data-header time").AttrOr("datetime", "")
+	dateStr := s.Find(cls.Selector("result_date", ".meta-data-header time")).AttrOr("datetime", "")
 	if dateStr != "" {
 		if date, err := time.Parse("2006-01-02", dateStr); err == nil {
 			c.DecisionDate = &date
@@ -234,14 +255,14 @@ func (cls *CourtListenerScraper) extractCaseFromSearchResult(s *goquery.Selectio
 	}
 
 	// Extract snippet/summary
-	snippet := s.Find(".snippet").Text()
+	snippet := s.Find(cls.Selector("result_snippet", ".snippet")).Text()
 	c.Summary = strings.TrimSpace(snippet)
 
 	// Set metadata
 	c.Jurisdiction = "United States"
 	c.SourceDatabase = "CourtListener"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cls.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -256,15 +277,15 @@ func (cls *CourtListenerScraper) extractCaseDetails(doc *goquery.Document, caseI
 	c.URL = caseURL
 
 	// Extract case name
-	caseName := doc.Find("h1.text-center").Text()
+	caseName := doc.Find(cls.Selector("case_name", "h1.text-center")).Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
 	// Extract court
-	court := doc.Find(".meta-data-header a").First().Text()
+	court := doc.Find(cls.Selector("court", ".meta-data-header a")).First().Text()
 	c.Court = strings.TrimSpace(court)
 
 	// Extract date
-	dateStr := doc.Find("time").AttrOr("datetime", "")
+	dateStr := doc.Find(cls.Selector("date", "time")).AttrOr("datetime", "")
 	if dateStr != "" {
 		if date, err := time.Parse("2006-01-02", dateStr); err == nil {
 			c.DecisionDate = &date
@@ -272,7 +293,7 @@ func (cls *CourtListenerScraper) extractCaseDetails(doc *goquery.Document, caseI
 	}
 
 	// Extract judges
-	doc.Find(".author a").Each(func(i int, s *goquery.Selection) {
+	doc.Find(cls.Selector("judges", ".author a")).Each(func(i int, s *goquery.Selection) {
 		judge := strings.TrimSpace(s.Text())
 		if judge != "" {
 			c.Judges = append(c.Judges, judge)
@@ -280,18 +301,18 @@ func (cls *CourtListenerScraper) extractCaseDetails(doc *goquery.Document, caseI
 	})
 
 	// Extract docket number
-	docket := doc.Find(".docket-number").Text()
+	docket := doc.Find(cls.Selector("docket", ".docket-number")).Text()
 	c.Docket = strings.TrimSpace(docket)
 
 	// Extract case text
-	fullText := doc.Find("#opinion-content").Text()
+	fullText := cls.ExtractText(doc.Find(cls.Selector("full_text", "#opinion-content")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	// Set metadata
 	c.Jurisdiction = "United States"
 	c.SourceDatabase = "CourtListener"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = cls.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -78,7 +78,7 @@ func (hs *HKLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQue
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := hs.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
@@ -86,18 +86,22 @@ func (hs *HKLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQue
 	// Extract cases from search results
 	cases := make([]*models.Case, 0)
 
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 
 		if s.Find("a").Length() > 0 {
 			caseData := hs.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !hs.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -105,6 +109,9 @@ func (hs *HKLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQue
 // GetCaseByID retrieves a specific case by its ID
 func (hs *HKLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := hs.buildCaseURL(caseID)
+	if hs.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	// Check robots.txt
 	allowed, err := hs.BaseScraper.client.CheckRobots(ctx, "/")
@@ -140,13 +147,19 @@ func (hs *HKLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models
 	}
 
 	// Parse HTML
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := hs.ReadResponse(resp)
 	if err != nil {
+		hs.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	// Extract case details
-	return hs.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := hs.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		hs.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -176,6 +189,14 @@ func (hs *HKLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// hkliiSampleCaseID identifies the Court of Final Appeal's decision in Ng Ka Ling v Director of Immigration, fetched by Validate
+const hkliiSampleCaseID = "/eng/hk/cases/hkcfa/1999/72.html"
+
+// Validate checks that a known HKLII case still scrapes with its core fields
+func (hs *HKLIIScraper) Validate(ctx context.Context) error {
+	return hs.ValidateSample(ctx, hs.GetCaseByID, hkliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (hs *HKLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -221,7 +242,7 @@ func (hs *HKLIIScraper) buildCaseURL(caseID string) string {
 func (hs *HKLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(hs.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -266,8 +287,8 @@ func (hs *HKLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *model
 
 	c.Jurisdiction = "Hong Kong"
 	c.SourceDatabase = "HKLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = hs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -282,18 +303,20 @@ func (hs *HKLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	c.URL = caseURL
 
 	// Extract case name
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(hs.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	hs.RecordFallback(c, "case_name", c.CaseName, level)
 
 	// Extract neutral citation
-	citation := doc.Find("center").First().Text()
+	citation, level := doc.Find(hs.Selector("citation", "center")).First().Text(), 0
 	if citation == "" {
-		citation = doc.Find("p").First().Text()
+		citation, level = doc.Find("p").First().Text(), 1
 	}
 	c.CaseNumber = strings.TrimSpace(citation)
+	hs.RecordFallback(c, "citation", c.CaseNumber, level)
 
 	// Extract court from URL
 	if strings.Contains(caseURL, "/HKCFA/") {
@@ -309,7 +332,7 @@ func (hs *HKLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	}
 
 	// Extract date
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(hs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := text
@@ -334,7 +357,7 @@ func (hs *HKLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	})
 
 	// Extract judges
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(hs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Before:") || strings.Contains(text, "Judges:") {
 			parts := strings.Split(text, ":")
@@ -354,7 +377,7 @@ func (hs *HKLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	})
 
 	// Extract docket/case number
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(hs.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Case No") || strings.Contains(text, "HCAL") {
 			c.Docket = strings.TrimSpace(text)
@@ -363,13 +386,13 @@ func (hs *HKLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	})
 
 	// Extract full judgment text
-	fullText := doc.Find("body").Text()
+	fullText := hs.ExtractText(doc.Find(hs.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.Jurisdiction = "Hong Kong"
 	c.SourceDatabase = "HKLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = hs.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
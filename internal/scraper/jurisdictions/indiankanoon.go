@@ -73,21 +73,25 @@ func (iks *IndianKanoonScraper) SearchCases(ctx context.Context, query scraper.S
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := iks.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("div.result").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("div.result"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		caseData := iks.extractCaseFromSearchResult(s)
-		if caseData != nil {
+		if caseData != nil && !iks.IsBlocked(caseData.URL) {
 			cases = append(cases, caseData)
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -95,6 +99,9 @@ func (iks *IndianKanoonScraper) SearchCases(ctx context.Context, query scraper.S
 // GetCaseByID retrieves a specific case by its ID
 func (iks *IndianKanoonScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := iks.buildCaseURL(caseID)
+	if iks.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := iks.BaseScraper.client.CheckRobots(ctx, "/doc/")
 	if err != nil || !allowed {
@@ -126,12 +133,18 @@ func (iks *IndianKanoonScraper) GetCaseByID(ctx context.Context, caseID string)
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := iks.ReadResponse(resp)
 	if err != nil {
+		iks.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return iks.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := iks.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		iks.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -158,6 +171,14 @@ func (iks *IndianKanoonScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// indiankanoonSampleCaseID identifies the Supreme Court's decision in Kesavananda Bharati v State of Kerala, fetched by Validate
+const indiankanoonSampleCaseID = "257876"
+
+// Validate checks that a known Indian Kanoon case still scrapes with its core fields
+func (iks *IndianKanoonScraper) Validate(ctx context.Context) error {
+	return iks.ValidateSample(ctx, iks.GetCaseByID, indiankanoonSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (iks *IndianKanoonScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -196,7 +217,7 @@ func (iks *IndianKanoonScraper) buildCaseURL(caseID string) string {
 func (iks *IndianKanoonScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("div.result_title a").First()
+	titleLink := s.Find(iks.Selector("result_link", "div.result_title a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -218,7 +239,7 @@ func (iks *IndianKanoonScraper) extractCaseFromSearchResult(s *goquery.Selection
 	}
 
 	// Extract court and date from metadata
-	metadata := s.Find("div.doc_cite").Text()
+	metadata := s.Find(iks.Selector("result_metadata", "div.doc_cite")).Text()
 	if metadata != "" {
 		// Try to extract court
 		if strings.Contains(metadata, "Supreme Court") {
@@ -230,13 +251,13 @@ func (iks *IndianKanoonScraper) extractCaseFromSearchResult(s *goquery.Selection
 	}
 
 	// Extract snippet
-	snippet := s.Find("div.result_highlight").Text()
+	snippet := s.Find(iks.Selector("result_snippet", "div.result_highlight")).Text()
 	c.Summary = strings.TrimSpace(snippet)
 
 	c.Jurisdiction = "India"
 	c.SourceDatabase = "IndianKanoon"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = iks.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -250,14 +271,15 @@ func (iks *IndianKanoonScraper) extractCaseDetails(doc *goquery.Document, caseID
 	c.URL = caseURL
 
 	// Extract case name from title
-	caseName := doc.Find("h1.doc_heading").First().Text()
+	caseName, level := doc.Find(iks.Selector("case_name", "h1.doc_heading")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	iks.RecordFallback(c, "case_name", c.CaseName, level)
 
 	// Extract citation
-	citation := doc.Find("div.doc_cite").First().Text()
+	citation := doc.Find(iks.Selector("citation", "div.doc_cite")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court from citation
@@ -272,7 +294,7 @@ func (iks *IndianKanoonScraper) extractCaseDetails(doc *goquery.Document, caseID
 	}
 
 	// Extract date
-	doc.Find("p, div").Each(func(i int, s *goquery.Selection) {
+	doc.Find(iks.Selector("metadata", "p, div")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Decided On:") || strings.Contains(text, "Date:") {
 			dateStr := text
@@ -296,7 +318,7 @@ func (iks *IndianKanoonScraper) extractCaseDetails(doc *goquery.Document, caseID
 	})
 
 	// Extract judges
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(iks.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Bench:") || strings.Contains(text, "Judge:") {
 			parts := strings.Split(text, ":")
@@ -315,16 +337,17 @@ func (iks *IndianKanoonScraper) extractCaseDetails(doc *goquery.Document, caseID
 	})
 
 	// Extract full judgment text
-	fullText := doc.Find("div.judgments").Text()
+	fullText, level := iks.ExtractText(doc.Find(iks.Selector("full_text", "div.judgments"))), 0
 	if fullText == "" {
-		fullText = doc.Find("div.doc_content").Text()
+		fullText, level = iks.ExtractText(doc.Find("div.doc_content")), 1
 	}
 	c.FullText = strings.TrimSpace(fullText)
+	iks.RecordFallback(c, "full_text", c.FullText, level)
 
 	c.Jurisdiction = "India"
 	c.SourceDatabase = "IndianKanoon"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = iks.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
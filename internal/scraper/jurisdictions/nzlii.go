@@ -73,23 +73,27 @@ func (ns *NZLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQue
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := ns.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		if s.Find("a").Length() > 0 {
 			caseData := ns.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !ns.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -97,6 +101,9 @@ func (ns *NZLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQue
 // GetCaseByID retrieves a specific case by its ID
 func (ns *NZLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := ns.buildCaseURL(caseID)
+	if ns.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := ns.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -128,12 +135,18 @@ func (ns *NZLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := ns.ReadResponse(resp)
 	if err != nil {
+		ns.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return ns.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := ns.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		ns.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -160,6 +173,14 @@ func (ns *NZLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// nzliiSampleCaseID identifies the Supreme Court's decision in Hosking v Runting, fetched by Validate
+const nzliiSampleCaseID = "/nz/cases/NZCA/2004/34.html"
+
+// Validate checks that a known NZLII case still scrapes with its core fields
+func (ns *NZLIIScraper) Validate(ctx context.Context) error {
+	return ns.ValidateSample(ctx, ns.GetCaseByID, nzliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (ns *NZLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -197,7 +218,7 @@ func (ns *NZLIIScraper) buildCaseURL(caseID string) string {
 func (ns *NZLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(ns.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -238,8 +259,8 @@ func (ns *NZLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *model
 
 	c.Jurisdiction = "New Zealand"
 	c.SourceDatabase = "NZLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ns.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -252,13 +273,14 @@ func (ns *NZLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	c.ID = caseID
 	c.URL = caseURL
 
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(ns.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	ns.RecordFallback(c, "case_name", c.CaseName, level)
 
-	citation := doc.Find("center").First().Text()
+	citation := doc.Find(ns.Selector("citation", "center")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court from URL
@@ -273,7 +295,7 @@ func (ns *NZLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 	}
 
 	// Extract date and judges
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(ns.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -305,13 +327,13 @@ func (ns *NZLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseUR
 		}
 	})
 
-	fullText := doc.Find("body").Text()
+	fullText := ns.ExtractText(doc.Find(ns.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.Jurisdiction = "New Zealand"
 	c.SourceDatabase = "NZLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ns.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -73,23 +73,27 @@ func (ps *PacLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := ps.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		if s.Find("a").Length() > 0 {
 			caseData := ps.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !ps.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -97,6 +101,9 @@ func (ps *PacLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 // GetCaseByID retrieves a specific case by its ID
 func (ps *PacLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := ps.buildCaseURL(caseID)
+	if ps.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := ps.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -128,12 +135,18 @@ func (ps *PacLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := ps.ReadResponse(resp)
 	if err != nil {
+		ps.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return ps.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := ps.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		ps.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -160,6 +173,14 @@ func (ps *PacLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// pacliiSampleCaseID identifies the Court of Appeal's decision in Republic of Fiji v Prasad, fetched by Validate
+const pacliiSampleCaseID = "/fj/cases/FJCA/2001/2.html"
+
+// Validate checks that a known PacLII case still scrapes with its core fields
+func (ps *PacLIIScraper) Validate(ctx context.Context) error {
+	return ps.ValidateSample(ctx, ps.GetCaseByID, pacliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (ps *PacLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -193,7 +214,7 @@ func (ps *PacLIIScraper) buildCaseURL(caseID string) string {
 func (ps *PacLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(ps.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -238,8 +259,8 @@ func (ps *PacLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 	}
 
 	c.SourceDatabase = "PacLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ps.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -252,13 +273,14 @@ func (ps *PacLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	c.ID = caseID
 	c.URL = caseURL
 
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(ps.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	ps.RecordFallback(c, "case_name", c.CaseName, level)
 
-	citation := doc.Find("center").First().Text()
+	citation := doc.Find(ps.Selector("citation", "center")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Determine jurisdiction from URL
@@ -281,7 +303,7 @@ func (ps *PacLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		c.Jurisdiction = "Pacific Islands"
 	}
 
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(ps.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -298,12 +320,12 @@ func (ps *PacLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		}
 	})
 
-	fullText := doc.Find("body").Text()
+	fullText := ps.ExtractText(doc.Find(ps.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.SourceDatabase = "PacLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ps.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -0,0 +1,21 @@
+package jurisdictions
+
+import (
+	"github.com/gongahkia/kite/internal/scraper"
+)
+
+// RegisterAll registers every built-in jurisdiction scraper with the registry
+func RegisterAll(registry *scraper.ScraperRegistry) {
+	registry.Register("austlii", NewAustLIIScraper())
+	registry.Register("bailii", NewBAILIIScraper())
+	registry.Register("canlii", NewCanLIIScraper())
+	registry.Register("commonlii", NewCommonLIIScraper())
+	registry.Register("courtlistener", NewCourtListenerScraper())
+	registry.Register("hklii", NewHKLIIScraper())
+	registry.Register("indiankanoon", NewIndianKanoonScraper())
+	registry.Register("nzlii", NewNZLIIScraper())
+	registry.Register("paclii", NewPacLIIScraper())
+	registry.Register("saflii", NewSAFLIIScraper())
+	registry.Register("singapore", NewSingaporeLawWatchScraper())
+	registry.Register("worldlii", NewWorldLIIScraper())
+}
@@ -73,23 +73,27 @@ func (ss *SAFLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := ss.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		if s.Find("a").Length() > 0 {
 			caseData := ss.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !ss.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -97,6 +101,9 @@ func (ss *SAFLIIScraper) SearchCases(ctx context.Context, query scraper.SearchQu
 // GetCaseByID retrieves a specific case by its ID
 func (ss *SAFLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := ss.buildCaseURL(caseID)
+	if ss.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := ss.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -128,12 +135,18 @@ func (ss *SAFLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*model
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := ss.ReadResponse(resp)
 	if err != nil {
+		ss.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return ss.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := ss.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		ss.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -160,6 +173,14 @@ func (ss *SAFLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// safliiSampleCaseID identifies the Constitutional Court's decision in S v Makwanyane, fetched by Validate
+const safliiSampleCaseID = "/za/cases/ZACC/1995/3.html"
+
+// Validate checks that a known SAFLII case still scrapes with its core fields
+func (ss *SAFLIIScraper) Validate(ctx context.Context) error {
+	return ss.ValidateSample(ctx, ss.GetCaseByID, safliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (ss *SAFLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -197,7 +218,7 @@ func (ss *SAFLIIScraper) buildCaseURL(caseID string) string {
 func (ss *SAFLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(ss.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -238,8 +259,8 @@ func (ss *SAFLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mode
 
 	c.Jurisdiction = "South Africa"
 	c.SourceDatabase = "SAFLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ss.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -252,13 +273,14 @@ func (ss *SAFLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 	c.ID = caseID
 	c.URL = caseURL
 
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(ss.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	ss.RecordFallback(c, "case_name", c.CaseName, level)
 
-	citation := doc.Find("center").First().Text()
+	citation := doc.Find(ss.Selector("citation", "center")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
 	// Extract court from URL
@@ -272,7 +294,7 @@ func (ss *SAFLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		c.Court = "Western Cape Division, Cape Town (High Court)"
 	}
 
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(ss.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -304,13 +326,13 @@ func (ss *SAFLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, caseU
 		}
 	})
 
-	fullText := doc.Find("body").Text()
+	fullText := ss.ExtractText(doc.Find(ss.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.Jurisdiction = "South Africa"
 	c.SourceDatabase = "SAFLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ss.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
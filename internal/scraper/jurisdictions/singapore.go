@@ -62,6 +62,9 @@ func (sls *SingaporeLawWatchScraper) SearchCases(ctx context.Context, query scra
 func (sls *SingaporeLawWatchScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	// Singapore cases use neutral citations like [2023] SGCA 15
 	caseURL := sls.buildCaseURL(caseID)
+	if sls.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := sls.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -97,12 +100,18 @@ func (sls *SingaporeLawWatchScraper) GetCaseByID(ctx context.Context, caseID str
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := sls.ReadResponse(resp)
 	if err != nil {
+		sls.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return sls.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := sls.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		sls.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -129,6 +138,14 @@ func (sls *SingaporeLawWatchScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// singaporeSampleCaseID identifies the Court of Appeal's decision in Ong Ming Johnson v Attorney-General, fetched by Validate
+const singaporeSampleCaseID = "[2022] SGCA 1"
+
+// Validate checks that a known Singapore Law Watch case still scrapes with its core fields
+func (sls *SingaporeLawWatchScraper) Validate(ctx context.Context) error {
+	return sls.ValidateSample(ctx, sls.GetCaseByID, singaporeSampleCaseID)
+}
+
 // buildCaseURL builds a case URL from a case citation
 func (sls *SingaporeLawWatchScraper) buildCaseURL(caseID string) string {
 	caseID = strings.TrimSpace(caseID)
@@ -146,11 +163,12 @@ func (sls *SingaporeLawWatchScraper) extractCaseDetails(doc *goquery.Document, c
 	c.URL = caseURL
 
 	// Extract case name
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(sls.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	sls.RecordFallback(c, "case_name", c.CaseName, level)
 
 	// Extract neutral citation
 	c.CaseNumber = caseID
@@ -167,7 +185,7 @@ func (sls *SingaporeLawWatchScraper) extractCaseDetails(doc *goquery.Document, c
 	}
 
 	// Extract date and other metadata
-	doc.Find("p, div").Each(func(i int, s *goquery.Selection) {
+	doc.Find(sls.Selector("metadata", "p, div")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment Date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -200,16 +218,17 @@ func (sls *SingaporeLawWatchScraper) extractCaseDetails(doc *goquery.Document, c
 	})
 
 	// Extract full judgment text
-	fullText := doc.Find("div.judgment-text").Text()
+	fullText, level := sls.ExtractText(doc.Find(sls.Selector("full_text", "div.judgment-text"))), 0
 	if fullText == "" {
-		fullText = doc.Find("body").Text()
+		fullText, level = sls.ExtractText(doc.Find("body")), 1
 	}
 	c.FullText = strings.TrimSpace(fullText)
+	sls.RecordFallback(c, "full_text", c.FullText, level)
 
 	c.Jurisdiction = "Singapore"
 	c.SourceDatabase = "SingaporeLawWatch"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = sls.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
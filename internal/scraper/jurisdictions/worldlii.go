@@ -73,23 +73,27 @@ func (ws *WorldLIIScraper) SearchCases(ctx context.Context, query scraper.Search
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, _, err := ws.ReadResponse(resp)
 	if err != nil {
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
 	cases := make([]*models.Case, 0)
-	doc.Find("li").Each(func(i int, s *goquery.Selection) {
+	err = scraper.EachUntilDone(ctx, doc.Find("li"), func(i int, s *goquery.Selection) bool {
 		if query.Limit > 0 && len(cases) >= query.Limit {
-			return
+			return false
 		}
 		if s.Find("a").Length() > 0 {
 			caseData := ws.extractCaseFromSearchResult(s)
-			if caseData != nil {
+			if caseData != nil && !ws.IsBlocked(caseData.URL) {
 				cases = append(cases, caseData)
 			}
 		}
+		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return cases, nil
 }
@@ -97,6 +101,9 @@ func (ws *WorldLIIScraper) SearchCases(ctx context.Context, query scraper.Search
 // GetCaseByID retrieves a specific case by its ID
 func (ws *WorldLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
 	caseURL := ws.buildCaseURL(caseID)
+	if ws.IsBlocked(caseURL) {
+		return nil, errors.ErrURLBlocked
+	}
 
 	allowed, err := ws.BaseScraper.client.CheckRobots(ctx, "/")
 	if err != nil || !allowed {
@@ -128,12 +135,18 @@ func (ws *WorldLIIScraper) GetCaseByID(ctx context.Context, caseID string) (*mod
 		return nil, errors.NetworkError(fmt.Sprintf("unexpected status code: %d", resp.StatusCode), nil)
 	}
 
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, raw, err := ws.ReadResponse(resp)
 	if err != nil {
+		ws.DumpOnParseFailure(caseURL, raw)
 		return nil, errors.ParsingError("failed to parse HTML", err)
 	}
 
-	return ws.extractCaseDetails(doc, caseID, caseURL)
+	caseData, err := ws.extractCaseDetails(doc, caseID, caseURL)
+	if err != nil || !scraper.MeetsMinimumValidity(caseData) {
+		ws.DumpOnParseFailure(caseURL, raw)
+	}
+
+	return caseData, err
 }
 
 // GetCasesByDateRange retrieves cases within a date range
@@ -160,6 +173,14 @@ func (ws *WorldLIIScraper) IsAvailable(ctx context.Context) bool {
 	return resp.StatusCode == http.StatusOK
 }
 
+// worldliiSampleCaseID identifies the International Court of Justice's decision in Nicaragua v United States, fetched by Validate
+const worldliiSampleCaseID = "/int/cases/ICJ/1986/1.html"
+
+// Validate checks that a known WorldLII case still scrapes with its core fields
+func (ws *WorldLIIScraper) Validate(ctx context.Context) error {
+	return ws.ValidateSample(ctx, ws.GetCaseByID, worldliiSampleCaseID)
+}
+
 // buildSearchURL builds the search URL with query parameters
 func (ws *WorldLIIScraper) buildSearchURL(query scraper.SearchQuery) (string, error) {
 	params := url.Values{}
@@ -193,7 +214,7 @@ func (ws *WorldLIIScraper) buildCaseURL(caseID string) string {
 func (ws *WorldLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *models.Case {
 	c := models.NewCase()
 
-	titleLink := s.Find("a").First()
+	titleLink := s.Find(ws.Selector("result_link", "a")).First()
 	caseName := titleLink.Text()
 	c.CaseName = strings.TrimSpace(caseName)
 
@@ -223,8 +244,8 @@ func (ws *WorldLIIScraper) extractCaseFromSearchResult(s *goquery.Selection) *mo
 
 	c.Jurisdiction = "International"
 	c.SourceDatabase = "WorldLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ws.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -237,16 +258,17 @@ func (ws *WorldLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, cas
 	c.ID = caseID
 	c.URL = caseURL
 
-	caseName := doc.Find("h1").First().Text()
+	caseName, level := doc.Find(ws.Selector("case_name", "h1")).First().Text(), 0
 	if caseName == "" {
-		caseName = doc.Find("title").First().Text()
+		caseName, level = doc.Find("title").First().Text(), 1
 	}
 	c.CaseName = strings.TrimSpace(caseName)
+	ws.RecordFallback(c, "case_name", c.CaseName, level)
 
-	citation := doc.Find("center").First().Text()
+	citation := doc.Find(ws.Selector("citation", "center")).First().Text()
 	c.CaseNumber = strings.TrimSpace(citation)
 
-	doc.Find("p").Each(func(i int, s *goquery.Selection) {
+	doc.Find(ws.Selector("metadata", "p")).Each(func(i int, s *goquery.Selection) {
 		text := s.Text()
 		if strings.Contains(text, "Date:") || strings.Contains(text, "Judgment date:") {
 			dateStr := strings.TrimSpace(strings.ReplaceAll(text, "Date:", ""))
@@ -278,13 +300,13 @@ func (ws *WorldLIIScraper) extractCaseDetails(doc *goquery.Document, caseID, cas
 		}
 	})
 
-	fullText := doc.Find("body").Text()
+	fullText := ws.ExtractText(doc.Find(ws.Selector("full_text", "body")))
 	c.FullText = strings.TrimSpace(fullText)
 
 	c.Jurisdiction = "International"
 	c.SourceDatabase = "WorldLII"
-	c.ScrapedAt = time.Now()
-	c.LastUpdated = time.Now()
+	c.ScrapedAt = ws.Now()
+	c.LastUpdated = c.ScrapedAt
 	c.Language = "en"
 	c.Status = models.CaseStatusActive
 
@@ -0,0 +1,141 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// SourceBreaker reports whether a source's circuit breaker is open, in which
+// case requests to it are skipped rather than attempted
+type SourceBreaker interface {
+	IsOpen(source string) bool
+}
+
+// FanOutSource is the outcome of one source in a fan-out search
+type FanOutSource struct {
+	Source string        `json:"source"`
+	Status ScraperStatus `json:"status"`
+	Cases  int           `json:"cases"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// FanOutResult holds the cases found by a fan-out search and how each
+// source fared
+type FanOutResult struct {
+	Cases   []*models.Case `json:"cases"`
+	Sources []FanOutSource `json:"sources"`
+}
+
+// MultiScraper searches several sources at once, routing only to those that
+// are healthy. A source is skipped, and reported unavailable, while its
+// circuit breaker is open or its last availability check failed; sources
+// not yet checked are tried.
+type MultiScraper struct {
+	sources  map[string]Scraper
+	statuses *SourceStatusCache
+	breaker  SourceBreaker
+	mu       sync.RWMutex
+}
+
+// NewMultiScraper creates a MultiScraper over scrapers keyed by source name
+func NewMultiScraper(sources map[string]Scraper) *MultiScraper {
+	return &MultiScraper{
+		sources:  sources,
+		statuses: NewSourceStatusCache(),
+	}
+}
+
+// SetBreaker sets the circuit breaker consulted before each source is queried
+func (m *MultiScraper) SetBreaker(breaker SourceBreaker) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.breaker = breaker
+}
+
+// SetStatusCache sets the cache of availability checks consulted before each
+// source is queried, such as one shared with the sources endpoint
+func (m *MultiScraper) SetStatusCache(statuses *SourceStatusCache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = statuses
+}
+
+// StartAvailabilityRefresh keeps the sources' availability current by
+// re-checking them every interval until the context is cancelled
+func (m *MultiScraper) StartAvailabilityRefresh(ctx context.Context, interval time.Duration) {
+	m.mu.RLock()
+	statuses := m.statuses
+	m.mu.RUnlock()
+
+	statuses.StartRefresh(ctx, m.sources, interval)
+}
+
+// unavailable returns why a source should not be queried, or "" if it may be
+func (m *MultiScraper) unavailable(name string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.breaker != nil && m.breaker.IsOpen(name) {
+		return "circuit breaker open"
+	}
+	if status, ok := m.statuses.Cached(name); ok && status.Status == ScraperStatusUnavailable {
+		return fmt.Sprintf("unavailable when checked at %s", status.LastChecked.Format(time.RFC3339))
+	}
+	return ""
+}
+
+// SearchCases searches every healthy source concurrently and merges their
+// cases in source order. Sources that fail are reported without failing the
+// search; an error is returned only when no source could be queried.
+func (m *MultiScraper) SearchCases(ctx context.Context, query SearchQuery) (*FanOutResult, error) {
+	names := make([]string, 0, len(m.sources))
+	for name := range m.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	outcomes := make([]FanOutSource, len(names))
+	found := make([][]*models.Case, len(names))
+	routed := 0
+
+	var wg sync.WaitGroup
+	for i, name := range names {
+		outcomes[i] = FanOutSource{Source: name}
+		if reason := m.unavailable(name); reason != "" {
+			outcomes[i].Status = ScraperStatusUnavailable
+			outcomes[i].Error = reason
+			continue
+		}
+
+		routed++
+		wg.Add(1)
+		go func(i int, s Scraper) {
+			defer wg.Done()
+			cases, err := s.SearchCases(ctx, query)
+			if err != nil {
+				outcomes[i].Status = ScraperStatusDegraded
+				outcomes[i].Error = err.Error()
+				return
+			}
+			outcomes[i].Status = ScraperStatusActive
+			outcomes[i].Cases = len(cases)
+			found[i] = cases
+		}(i, m.sources[name])
+	}
+	wg.Wait()
+
+	result := &FanOutResult{Cases: make([]*models.Case, 0), Sources: outcomes}
+	for _, cases := range found {
+		result.Cases = append(result.Cases, cases...)
+	}
+
+	if routed == 0 && len(names) > 0 {
+		return result, fmt.Errorf("no available source among %d", len(names))
+	}
+	return result, nil
+}
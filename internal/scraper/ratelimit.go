@@ -8,10 +8,30 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// SharedBucket is a token bucket held outside the process, so that every
+// worker scraping a source draws from one budget
+type SharedBucket interface {
+	// Take removes a token from the bucket at key, which refills at
+	// ratePerSecond up to burst tokens. It returns zero if a token was taken,
+	// otherwise how long until one will be available.
+	Take(ctx context.Context, key string, ratePerSecond float64, burst int) (time.Duration, error)
+}
+
+// RateLimitLogger receives shared bucket failures (satisfied by observability.Logger)
+type RateLimitLogger interface {
+	Warnf(format string, args ...interface{})
+}
+
 // RateLimiter implements rate limiting using token bucket algorithm
 type RateLimiter struct {
 	limiter *rate.Limiter
 	mu      sync.Mutex
+
+	// Optional shared bucket, used in place of limiter while it is reachable
+	shared    SharedBucket
+	sharedKey string
+	logger    RateLimitLogger
+	degraded  bool
 }
 
 // NewRateLimiter creates a new RateLimiter
@@ -31,21 +51,98 @@ func NewRateLimiter(requestsPerMinute int) *RateLimiter {
 	}
 }
 
+// SetShared makes the limiter draw from bucket under key, enforcing the rate
+// across every process sharing the bucket. If the bucket fails, requests fall
+// back to the local limiter until it recovers. A nil bucket reverts to local
+// limiting; logger may be nil.
+func (rl *RateLimiter) SetShared(bucket SharedBucket, key string, logger RateLimitLogger) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.shared = bucket
+	rl.sharedKey = key
+	rl.logger = logger
+	rl.degraded = false
+}
+
 // Wait blocks until request is allowed under rate limit
 func (rl *RateLimiter) Wait(ctx context.Context) error {
-	return rl.limiter.Wait(ctx)
+	bucket, key := rl.sharedBucket()
+	if bucket == nil {
+		return rl.limiter.Wait(ctx)
+	}
+
+	for {
+		wait, err := bucket.Take(ctx, key, float64(rl.limiter.Limit()), rl.limiter.Burst())
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			rl.sharedFailed(err)
+			return rl.limiter.Wait(ctx)
+		}
+		rl.sharedRecovered()
+
+		if wait <= 0 {
+			return nil
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 // Allow returns true if request is allowed immediately
 func (rl *RateLimiter) Allow() bool {
-	return rl.limiter.Allow()
+	bucket, key := rl.sharedBucket()
+	if bucket == nil {
+		return rl.limiter.Allow()
+	}
+
+	wait, err := bucket.Take(context.Background(), key, float64(rl.limiter.Limit()), rl.limiter.Burst())
+	if err != nil {
+		rl.sharedFailed(err)
+		return rl.limiter.Allow()
+	}
+	rl.sharedRecovered()
+	return wait <= 0
 }
 
-// Reserve reserves a request and returns a Reservation
+// Reserve reserves a request against the local limiter and returns a Reservation
 func (rl *RateLimiter) Reserve() *rate.Reservation {
 	return rl.limiter.Reserve()
 }
 
+func (rl *RateLimiter) sharedBucket() (SharedBucket, string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	return rl.shared, rl.sharedKey
+}
+
+// sharedFailed logs the first failure of the shared bucket in a run of failures
+func (rl *RateLimiter) sharedFailed(err error) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if !rl.degraded && rl.logger != nil {
+		rl.logger.Warnf("shared rate limit %s unavailable, falling back to local limit: %v", rl.sharedKey, err)
+	}
+	rl.degraded = true
+}
+
+// sharedRecovered logs the shared bucket becoming reachable again
+func (rl *RateLimiter) sharedRecovered() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.degraded && rl.logger != nil {
+		rl.logger.Warnf("shared rate limit %s recovered", rl.sharedKey)
+	}
+	rl.degraded = false
+}
+
 // SetLimit updates the rate limit
 func (rl *RateLimiter) SetLimit(requestsPerMinute int) {
 	rl.mu.Lock()
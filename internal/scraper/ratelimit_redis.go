@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SharedRateLimitKeyPrefix prefixes the Redis keys of shared source rate limits
+const SharedRateLimitKeyPrefix = "kite:ratelimit:"
+
+// tokenBucketScript refills the bucket for the time elapsed since it was last
+// touched, then takes a token if one is available. Time comes from the Redis
+// server so that workers with skewed clocks agree. Returns 0 if a token was
+// taken, otherwise the milliseconds until the next one.
+var tokenBucketScript = redis.NewScript(`
+redis.replicate_commands() -- allow writes after TIME on Redis < 5
+
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return wait
+`)
+
+// RedisTokenBucket is a SharedBucket shared by every process using the same Redis
+type RedisTokenBucket struct {
+	client *redis.Client
+}
+
+// NewRedisTokenBucket creates a SharedBucket backed by a Lua token bucket
+func NewRedisTokenBucket(client *redis.Client) *RedisTokenBucket {
+	return &RedisTokenBucket{client: client}
+}
+
+// Take removes a token from the bucket at key, or returns how long until one
+// will be available
+func (b *RedisTokenBucket) Take(ctx context.Context, key string, ratePerSecond float64, burst int) (time.Duration, error) {
+	if math.IsInf(ratePerSecond, 1) {
+		return 0, nil
+	}
+	if ratePerSecond <= 0 {
+		return 0, fmt.Errorf("invalid shared rate limit for %s: %v per second", key, ratePerSecond)
+	}
+
+	waitMs, err := tokenBucketScript.Run(ctx, b.client, []string{key}, ratePerSecond, burst).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(waitMs) * time.Millisecond, nil
+}
@@ -0,0 +1,122 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+)
+
+// RetryConfig bounds retries of failed requests to a source. MaxRetries caps
+// the retries of any one request and Budget those of a whole scrape run, so
+// a run failing across the board gives up early instead of retrying every
+// case. A Budget of 0 leaves runs unbounded but for MaxRetries.
+type RetryConfig struct {
+	MaxRetries int
+	Budget     int
+	Delay      time.Duration // wait before each retry
+}
+
+// RetryBudget counts the retries a scrape run has spent against its limit.
+// It is shared by every request in the run.
+type RetryBudget struct {
+	limit int
+	used  int
+	mu    sync.Mutex
+}
+
+// NewRetryBudget creates a budget of limit retries (0 for no limit)
+func NewRetryBudget(limit int) *RetryBudget {
+	return &RetryBudget{limit: limit}
+}
+
+// take spends one retry, reporting false when the budget is spent
+func (b *RetryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit > 0 && b.used >= b.limit {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// Used returns how many retries have been spent
+func (b *RetryBudget) Used() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Retrier retries the failed requests of one scrape run within the per-request
+// cap and the run's shared budget. A nil Retrier makes each request once.
+type Retrier struct {
+	config RetryConfig
+	budget *RetryBudget
+}
+
+// NewRetrier creates a retrier with a fresh budget, for one scrape run
+func NewRetrier(config RetryConfig) *Retrier {
+	return &Retrier{config: config, budget: NewRetryBudget(config.Budget)}
+}
+
+// Do calls fn, retrying it while it fails with a retryable error. When a
+// retry is due but the run's budget is spent, it gives up with an error
+// wrapping ErrRetryBudgetExhausted from pkg/errors.
+func (r *Retrier) Do(ctx context.Context, fn func() error) error {
+	err := fn()
+	if r == nil {
+		return err
+	}
+
+	for attempt := 0; err != nil && attempt < r.config.MaxRetries && IsRetryable(err); attempt++ {
+		if !r.budget.take() {
+			return fmt.Errorf("%w after %d retries: %v", kiteerrors.ErrRetryBudgetExhausted, r.budget.Used(), err)
+		}
+		if r.config.Delay > 0 {
+			select {
+			case <-time.After(r.config.Delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		err = fn()
+	}
+	return err
+}
+
+// Used returns how many retries the run has spent
+func (r *Retrier) Used() int {
+	if r == nil {
+		return 0
+	}
+	return r.budget.Used()
+}
+
+// IsRetryable reports whether a failed request may succeed if made again:
+// network failures, timeouts and rate limiting, but not pages that are
+// missing, disallowed or unparseable
+func IsRetryable(err error) bool {
+	var ke *kiteerrors.KiteError
+	if errors.As(err, &ke) && (ke.Code == "NETWORK_ERROR" || ke.Code == "RATE_LIMIT_ERROR") {
+		return true
+	}
+	return errors.Is(err, kiteerrors.ErrNetworkFailure) ||
+		errors.Is(err, kiteerrors.ErrTimeout) ||
+		errors.Is(err, kiteerrors.ErrRateLimitExceeded)
+}
+
+// SetRetryConfig sets how scrape runs retry failed requests
+func (sr *ScraperRegistry) SetRetryConfig(config RetryConfig) {
+	sr.retry = config
+}
+
+// NewRetrier returns a retrier for one scrape run under the registry's retry
+// configuration
+func (sr *ScraperRegistry) NewRetrier() *Retrier {
+	return NewRetrier(sr.retry)
+}
@@ -0,0 +1,69 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andybalholm/cascadia"
+	"gopkg.in/yaml.v3"
+)
+
+// SelectorOverrides replace the CSS selectors scrapers extract cases with,
+// keyed by registered scraper name and then by selector name, so that a
+// source's changed markup can be followed without a rebuild:
+//
+//	bailii:
+//	  case_name: "h1.judgment-title"
+//	  full_text: "div#judgment"
+//
+// Selectors are named by the field they read: result_link, result_citation,
+// result_court, result_date, result_snippet and result_metadata in search
+// results, and case_name, citation, court, date, judges, docket and
+// full_text on case pages, where metadata selects the blocks scanned for
+// labelled fields such as "Date:" and "Before:". Names a scraper doesn't use
+// are ignored.
+type SelectorOverrides map[string]map[string]string
+
+// LoadSelectorOverrides loads selector overrides from a YAML file
+func LoadSelectorOverrides(path string) (SelectorOverrides, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open selector file: %w", err)
+	}
+	defer f.Close()
+
+	return LoadSelectorOverridesFromReader(f)
+}
+
+// LoadSelectorOverridesFromReader loads selector overrides from YAML,
+// rejecting selectors that don't parse
+func LoadSelectorOverridesFromReader(r io.Reader) (SelectorOverrides, error) {
+	overrides := make(SelectorOverrides)
+	if err := yaml.NewDecoder(r).Decode(&overrides); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse selector YAML: %w", err)
+	}
+
+	for scraperName, selectors := range overrides {
+		for name, selector := range selectors {
+			if _, err := cascadia.ParseGroup(selector); err != nil {
+				return nil, fmt.Errorf("invalid %s selector %q for scraper %s: %w", name, selector, scraperName, err)
+			}
+		}
+	}
+	return overrides, nil
+}
+
+// SetSelectors overrides the scraper's built-in selectors by name
+func (bs *BaseScraper) SetSelectors(selectors map[string]string) {
+	bs.selectors = selectors
+}
+
+// Selector returns the CSS selector the scraper reads a field with: its
+// override if one is set, otherwise the built-in fallback
+func (bs *BaseScraper) Selector(name, fallback string) string {
+	if selector, ok := bs.selectors[name]; ok && selector != "" {
+		return selector
+	}
+	return fallback
+}
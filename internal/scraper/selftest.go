@@ -0,0 +1,106 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// CaseFetcher retrieves a case by its ID, as Scraper.GetCaseByID does
+type CaseFetcher func(ctx context.Context, caseID string) (*models.Case, error)
+
+// ValidateSampleCase checks that a scraped case has the core fields every
+// scraper must extract: name, decision date and court
+func ValidateSampleCase(c *models.Case) error {
+	if c == nil {
+		return errors.ValidationError("no case returned", errors.ErrMissingRequired)
+	}
+
+	var missing []string
+	if strings.TrimSpace(c.CaseName) == "" {
+		missing = append(missing, "case_name")
+	}
+	if c.DecisionDate == nil || c.DecisionDate.IsZero() {
+		missing = append(missing, "decision_date")
+	}
+	if strings.TrimSpace(c.Court) == "" {
+		missing = append(missing, "court")
+	}
+
+	if len(missing) > 0 {
+		return errors.ValidationError(
+			fmt.Sprintf("case %s is missing %s", c.ID, strings.Join(missing, ", ")),
+			errors.ErrMissingRequired)
+	}
+	return nil
+}
+
+// ValidateSample fetches a known case with fetch and checks its core fields,
+// so that a change in the source's markup shows up as a failed self-test
+// rather than as empty cases in storage
+func (bs *BaseScraper) ValidateSample(ctx context.Context, fetch CaseFetcher, caseID string) error {
+	c, err := fetch(ctx, caseID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to fetch sample case %s: %w", bs.name, caseID, err)
+	}
+	if err := ValidateSampleCase(c); err != nil {
+		return fmt.Errorf("%s: sample case %s: %w", bs.name, caseID, err)
+	}
+	return nil
+}
+
+// SelfTestResult is the outcome of validating one source
+type SelfTestResult struct {
+	Source       string        `json:"source"`
+	Jurisdiction string        `json:"jurisdiction"`
+	Passed       bool          `json:"passed"`
+	Error        string        `json:"error,omitempty"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// SelfTest validates every scraper concurrently, each within timeout (zero
+// for no limit), and returns the results ordered by source name
+func SelfTest(ctx context.Context, scrapers map[string]Scraper, timeout time.Duration) []SelfTestResult {
+	results := make([]SelfTestResult, 0, len(scrapers))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for name, s := range scrapers {
+		wg.Add(1)
+		go func(name string, s Scraper) {
+			defer wg.Done()
+
+			testCtx, cancel := ctx, context.CancelFunc(func() {})
+			if timeout > 0 {
+				testCtx, cancel = context.WithTimeout(ctx, timeout)
+			}
+			defer cancel()
+
+			start := time.Now()
+			err := s.Validate(testCtx)
+			result := SelfTestResult{
+				Source:       name,
+				Jurisdiction: s.GetJurisdiction(),
+				Passed:       err == nil,
+				Duration:     time.Since(start),
+			}
+			if err != nil {
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(name, s)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Source < results[j].Source })
+	return results
+}
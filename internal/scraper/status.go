@@ -0,0 +1,120 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SourceStatus is the last observed availability of a source
+type SourceStatus struct {
+	Status      ScraperStatus `json:"status"`
+	LastChecked time.Time     `json:"last_checked"`
+}
+
+// SourceStatusCache caches source availability checks so that listing sources
+// doesn't probe every upstream site on each request
+type SourceStatusCache struct {
+	statuses map[string]SourceStatus
+	mu       sync.RWMutex
+	ttl      time.Duration
+	timeout  time.Duration
+}
+
+// NewSourceStatusCache creates a new SourceStatusCache
+func NewSourceStatusCache() *SourceStatusCache {
+	return &SourceStatusCache{
+		statuses: make(map[string]SourceStatus),
+		ttl:      5 * time.Minute,
+		timeout:  10 * time.Second,
+	}
+}
+
+// SetTTL sets how long a status is reused before the source is checked again
+func (sc *SourceStatusCache) SetTTL(ttl time.Duration) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.ttl = ttl
+}
+
+// Cached returns a source's last observed status without checking it
+func (sc *SourceStatusCache) Cached(name string) (SourceStatus, bool) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	status, ok := sc.statuses[name]
+	return status, ok
+}
+
+// StartRefresh re-checks scrapers whose cached status has expired straight
+// away and then every interval until the context is cancelled, so that Cached
+// stays current without callers waiting on upstream sites
+func (sc *SourceStatusCache) StartRefresh(ctx context.Context, scrapers map[string]Scraper, interval time.Duration) {
+	sc.Statuses(ctx, scrapers)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sc.Statuses(ctx, scrapers)
+		}
+	}
+}
+
+// Statuses returns the status of every scraper, keyed like scrapers. Sources
+// whose cached status has expired are checked concurrently.
+func (sc *SourceStatusCache) Statuses(ctx context.Context, scrapers map[string]Scraper) map[string]SourceStatus {
+	now := time.Now()
+	result := make(map[string]SourceStatus, len(scrapers))
+	stale := make(map[string]Scraper)
+
+	sc.mu.RLock()
+	for name, s := range scrapers {
+		if status, ok := sc.statuses[name]; ok && now.Sub(status.LastChecked) < sc.ttl {
+			result[name] = status
+		} else {
+			stale[name] = s
+		}
+	}
+	sc.mu.RUnlock()
+
+	if len(stale) == 0 {
+		return result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, sc.timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var resultMu sync.Mutex
+	for name, s := range stale {
+		wg.Add(1)
+		go func(name string, s Scraper) {
+			defer wg.Done()
+			status := SourceStatus{Status: ScraperStatusUnavailable, LastChecked: time.Now()}
+			if s.IsAvailable(checkCtx) {
+				status.Status = ScraperStatusActive
+			}
+			resultMu.Lock()
+			result[name] = status
+			resultMu.Unlock()
+		}(name, s)
+	}
+	wg.Wait()
+
+	// Don't cache checks cut short by the caller going away
+	if ctx.Err() != nil {
+		return result
+	}
+
+	sc.mu.Lock()
+	for name := range stale {
+		sc.statuses[name] = result[name]
+	}
+	sc.mu.Unlock()
+
+	return result
+}
@@ -0,0 +1,180 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// structureTags are the HTML tags whose structure full text can keep. Block
+// tags start a new paragraph, br starts a new line within one, and items of
+// an ordered list keep their number, as in BAILII's numbered paragraphs.
+var structureTags = map[string]bool{
+	"p": true, "br": true, "div": true, "blockquote": true,
+	"ol": true, "ul": true, "li": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"table": true, "tr": true,
+}
+
+// StructureTags returns the tags that can be preserved in full text, sorted
+func StructureTags() []string {
+	tags := make([]string, 0, len(structureTags))
+	for tag := range structureTags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// TextStructure extracts text keeping the paragraph breaks and list numbering
+// of an allowlist of HTML tags, so that pinpoint citations to a paragraph
+// still resolve. Paragraphs are separated by a blank line; every other tag is
+// flattened as by Selection.Text.
+type TextStructure struct {
+	tags map[string]bool
+}
+
+// NewTextStructure creates a TextStructure preserving the given tags, which
+// must be among StructureTags. With no tags, text is flattened.
+func NewTextStructure(tags []string) (*TextStructure, error) {
+	ts := &TextStructure{tags: make(map[string]bool, len(tags))}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !structureTags[tag] {
+			return nil, fmt.Errorf("unsupported structure tag %q: must be one of %s", tag, strings.Join(StructureTags(), ", "))
+		}
+		ts.tags[tag] = true
+	}
+	return ts, nil
+}
+
+// Text returns the text of sel, with the structure of the preserved tags
+func (ts *TextStructure) Text(sel *goquery.Selection) string {
+	if ts == nil || len(ts.tags) == 0 {
+		return strings.TrimSpace(sel.Text())
+	}
+	return strings.Join(ts.Paragraphs(sel), "\n\n")
+}
+
+// Paragraphs returns the paragraphs of sel split at the preserved tags, with
+// whitespace collapsed and ordered list items prefixed by their number
+func (ts *TextStructure) Paragraphs(sel *goquery.Selection) []string {
+	w := &paragraphWriter{}
+	for _, n := range sel.Nodes {
+		ts.walk(w, n)
+	}
+	w.flush()
+	return w.paragraphs
+}
+
+func (ts *TextStructure) walk(w *paragraphWriter, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.write(n.Data)
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "script", "style":
+			return
+		case "br":
+			if ts.tags["br"] {
+				w.lineBreak()
+			}
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && ts.tags[n.Data]
+	if block {
+		w.flush()
+		if n.Data == "li" {
+			w.prefix = listItemNumber(n)
+		}
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		ts.walk(w, child)
+	}
+	if block {
+		w.flush()
+		w.prefix = ""
+	}
+}
+
+// listItemNumber returns the "12. " prefix of an ordered list item, from its
+// value attribute or its position after the list's start attribute, or "" for
+// items of an unordered list
+func listItemNumber(li *html.Node) string {
+	if li.Parent == nil || li.Parent.Data != "ol" {
+		return ""
+	}
+	if v, err := strconv.Atoi(attr(li, "value")); err == nil {
+		return fmt.Sprintf("%d. ", v)
+	}
+
+	number, err := strconv.Atoi(attr(li.Parent, "start"))
+	if err != nil {
+		number = 1
+	}
+	for sib := li.PrevSibling; sib != nil; sib = sib.PrevSibling {
+		if sib.Type == html.ElementNode && sib.Data == "li" {
+			number++
+		}
+	}
+	return fmt.Sprintf("%d. ", number)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+// paragraphWriter collects text into paragraphs. A list item's number is held
+// until its first text, so that an item wrapping a <p> isn't split from it.
+type paragraphWriter struct {
+	paragraphs []string
+	lines      []string
+	current    strings.Builder
+	prefix     string
+}
+
+func (w *paragraphWriter) write(text string) {
+	if w.prefix != "" && strings.TrimSpace(text) != "" {
+		w.current.WriteString(w.prefix)
+		w.prefix = ""
+	}
+	w.current.WriteString(text)
+}
+
+func (w *paragraphWriter) lineBreak() {
+	if line := strings.Join(strings.Fields(w.current.String()), " "); line != "" {
+		w.lines = append(w.lines, line)
+	}
+	w.current.Reset()
+}
+
+func (w *paragraphWriter) flush() {
+	w.lineBreak()
+	if len(w.lines) > 0 {
+		w.paragraphs = append(w.paragraphs, strings.Join(w.lines, "\n"))
+		w.lines = nil
+	}
+}
+
+// SetTextStructure sets the structure kept in extracted full text; nil
+// flattens it
+func (bs *BaseScraper) SetTextStructure(ts *TextStructure) {
+	bs.structure = ts
+}
+
+// ExtractText returns the full text of sel, keeping the configured structure
+func (bs *BaseScraper) ExtractText(sel *goquery.Selection) string {
+	return bs.structure.Text(sel)
+}
@@ -0,0 +1,68 @@
+package scraper
+
+import (
+	"context"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultExtractionVersion is the version of the extraction logic stamped on
+// scraped cases. Bump it when parsing changes in a way that makes cases
+// scraped earlier worth re-processing.
+const DefaultExtractionVersion = 1
+
+// versionStampingScraper wraps a Scraper and stamps the extraction version
+// on every returned case
+type versionStampingScraper struct {
+	Scraper
+	version int
+}
+
+// WithExtractionVersion wraps a scraper so returned cases record the
+// extraction version that produced them. Cases the scraper already stamped
+// keep their version; a version of zero or less returns the scraper unchanged.
+func WithExtractionVersion(s Scraper, version int) Scraper {
+	if version <= 0 {
+		return s
+	}
+	return &versionStampingScraper{Scraper: s, version: version}
+}
+
+// Unwrap returns the underlying scraper
+func (s *versionStampingScraper) Unwrap() Scraper {
+	return s.Scraper
+}
+
+// SearchCases searches for cases and stamps their extraction version
+func (s *versionStampingScraper) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
+	cases, err := s.Scraper.SearchCases(ctx, query)
+	s.stampAll(cases)
+	return cases, err
+}
+
+// GetCaseByID retrieves a case and stamps its extraction version
+func (s *versionStampingScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	c, err := s.Scraper.GetCaseByID(ctx, caseID)
+	s.stamp(c)
+	return c, err
+}
+
+// GetCasesByDateRange retrieves cases within a date range and stamps their extraction version
+func (s *versionStampingScraper) GetCasesByDateRange(ctx context.Context, startDate, endDate time.Time, limit int) ([]*models.Case, error) {
+	cases, err := s.Scraper.GetCasesByDateRange(ctx, startDate, endDate, limit)
+	s.stampAll(cases)
+	return cases, err
+}
+
+func (s *versionStampingScraper) stampAll(cases []*models.Case) {
+	for _, c := range cases {
+		s.stamp(c)
+	}
+}
+
+func (s *versionStampingScraper) stamp(c *models.Case) {
+	if c != nil && c.ExtractionVersion == 0 {
+		c.ExtractionVersion = s.version
+	}
+}
@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScrapeWindow is the times of day a source may be scraped, in the source's
+// own timezone, such as the overnight hours when its servers are quiet. A
+// range whose end is before its start runs past midnight, so "22:00-06:00"
+// is overnight.
+type ScrapeWindow struct {
+	location *time.Location
+	ranges   []windowRange
+}
+
+// windowRange is one allowed range, as offsets from midnight
+type windowRange struct {
+	start time.Duration
+	end   time.Duration
+}
+
+// NewScrapeWindow parses "HH:MM-HH:MM" ranges in an IANA timezone such as
+// "Europe/London"; an empty timezone is UTC. With no ranges the window is
+// always open.
+func NewScrapeWindow(timezone string, ranges []string) (*ScrapeWindow, error) {
+	location := time.UTC
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrape window timezone %q: %w", timezone, err)
+		}
+		location = loc
+	}
+
+	w := &ScrapeWindow{location: location, ranges: make([]windowRange, 0, len(ranges))}
+	for _, r := range ranges {
+		from, to, ok := strings.Cut(r, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid scrape window %q: want HH:MM-HH:MM", r)
+		}
+		start, err := parseTimeOfDay(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrape window %q: %w", r, err)
+		}
+		end, err := parseTimeOfDay(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scrape window %q: %w", r, err)
+		}
+		if start == end {
+			return nil, fmt.Errorf("invalid scrape window %q: empty range", r)
+		}
+		w.ranges = append(w.ranges, windowRange{start: start, end: end})
+	}
+	return w, nil
+}
+
+// parseTimeOfDay parses "HH:MM", from 00:00 to 24:00, as an offset from midnight
+func parseTimeOfDay(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Open reports whether t falls inside the window. A nil window is always open.
+func (w *ScrapeWindow) Open(t time.Time) bool {
+	if w == nil || len(w.ranges) == 0 {
+		return true
+	}
+
+	local := t.In(w.location)
+	offset := time.Duration(local.Hour())*time.Hour +
+		time.Duration(local.Minute())*time.Minute +
+		time.Duration(local.Second())*time.Second
+	for _, r := range w.ranges {
+		if r.start < r.end {
+			if offset >= r.start && offset < r.end {
+				return true
+			}
+		} else if offset >= r.start || offset < r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns t if the window is open at t, and otherwise when it next opens
+func (w *ScrapeWindow) NextOpen(t time.Time) time.Time {
+	if w.Open(t) {
+		return t
+	}
+
+	local := t.In(w.location)
+	var next time.Time
+	for day := 0; day <= 1; day++ {
+		for _, r := range w.ranges {
+			opens := time.Date(local.Year(), local.Month(), local.Day()+day,
+				int(r.start/time.Hour), int(r.start%time.Hour/time.Minute), 0, 0, w.location)
+			if opens.After(t) && (next.IsZero() || opens.Before(next)) {
+				next = opens
+			}
+		}
+	}
+	return next
+}
+
+// SetScrapeWindow limits when the source may be scraped; nil lifts the limit
+func (bs *BaseScraper) SetScrapeWindow(window *ScrapeWindow) {
+	bs.window = window
+}
+
+// GetScrapeWindow returns the scraper's scraping window, nil if it has none
+func (bs *BaseScraper) GetScrapeWindow() *ScrapeWindow {
+	return bs.window
+}
+
+// scrapeWindowed is implemented by scrapers limited to a scraping window
+type scrapeWindowed interface {
+	SetScrapeWindow(window *ScrapeWindow)
+	GetScrapeWindow() *ScrapeWindow
+}
+
+// ScrapeWindowOf returns the scraping window of s, beneath any registry
+// wrappers, or nil if s may be scraped at any time
+func ScrapeWindowOf(s Scraper) *ScrapeWindow {
+	if w, ok := unwrapScraper(s).(scrapeWindowed); ok {
+		return w.GetScrapeWindow()
+	}
+	return nil
+}
+
+// OutsideScrapeWindow reports whether s is outside its scraping window now,
+// by the scraper's own clock, and if so when the window next opens
+func OutsideScrapeWindow(s Scraper) (time.Time, bool) {
+	window := ScrapeWindowOf(s)
+	if window == nil {
+		return time.Time{}, false
+	}
+
+	now := time.Now()
+	if c, ok := unwrapScraper(s).(interface{ Now() time.Time }); ok {
+		now = c.Now()
+	}
+	if window.Open(now) {
+		return time.Time{}, false
+	}
+	return window.NextOpen(now), true
+}
+
+// SetScrapeWindows sets the scraping windows of registered scrapers and those
+// registered afterwards, keyed by registered scraper name
+func (sr *ScraperRegistry) SetScrapeWindows(windows map[string]*ScrapeWindow) {
+	sr.windows = windows
+	for name, s := range sr.scrapers {
+		window, ok := windows[name]
+		if !ok {
+			continue
+		}
+		if w, ok := unwrapScraper(s).(scrapeWindowed); ok {
+			w.SetScrapeWindow(window)
+		}
+	}
+}
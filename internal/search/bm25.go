@@ -0,0 +1,99 @@
+package search
+
+import (
+	"math"
+
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// BM25Scorer ranks cases with BM25 over corpus term statistics. Term
+// frequencies are weighted per field by the RankingConfig (BM25F), so a term
+// in the case name counts for more than one buried in the full text.
+type BM25Scorer struct {
+	K1 float64 // term frequency saturation
+	B  float64 // document length normalization
+}
+
+// DefaultBM25Scorer returns a scorer with the usual k1 = 1.2, b = 0.75
+func DefaultBM25Scorer() BM25Scorer {
+	return BM25Scorer{K1: 1.2, B: 0.75}
+}
+
+// IDF returns the inverse document frequency of a term. Rarer terms score
+// higher; the +1 keeps terms present in every case from going negative.
+func (s BM25Scorer) IDF(stats *storage.TermStats, term string) float64 {
+	n := float64(stats.DocCount())
+	df := float64(stats.DocFreq(term))
+	return math.Log(1 + (n-df+0.5)/(df+0.5))
+}
+
+// Score returns the BM25 score of a case for the given query text
+func (s BM25Scorer) Score(stats *storage.TermStats, c *models.Case, text string, ranking RankingConfig) float64 {
+	return s.score(stats, c, text, ranking, nil)
+}
+
+// score computes Score, recording each matching term's contribution in explain
+func (s BM25Scorer) score(stats *storage.TermStats, c *models.Case, text string, ranking RankingConfig, explain *scoreExplanation) float64 {
+	queryTerms := uniqueTerms(storage.Tokenize(text))
+	if len(queryTerms) == 0 {
+		return 0
+	}
+
+	weighted := make(map[string]float64)
+	var fields map[string]*fieldWeights
+	if explain != nil {
+		fields = make(map[string]*fieldWeights)
+	}
+	addField := func(field, value string, weight float64) {
+		for _, t := range storage.Tokenize(value) {
+			weighted[t] += weight
+			if fields != nil {
+				if fields[t] == nil {
+					fields[t] = &fieldWeights{}
+				}
+				fields[t].add(field, weight)
+			}
+		}
+	}
+	addField("case name", c.CaseName, ranking.CaseNameWeight)
+	addField("summary", c.Summary, ranking.SummaryWeight)
+	addField("full text", c.FullText, ranking.FullTextWeight)
+	for _, concept := range c.LegalConcepts {
+		addField("concept", concept, ranking.ConceptWeight)
+	}
+
+	norm := 1.0
+	if avg := stats.AvgDocLength(); avg > 0 {
+		norm = 1 - s.B + s.B*float64(len(storage.CaseTokens(c)))/avg
+	}
+
+	score := 0.0
+	for _, term := range queryTerms {
+		tf := weighted[term]
+		if tf == 0 {
+			continue
+		}
+		idf := s.IDF(stats, term)
+		contribution := idf * tf * (s.K1 + 1) / (tf + s.K1*norm)
+		score += contribution
+		if explain != nil {
+			explain.add("%q in %s: tf %.1f, idf %.2f, length norm %.2f, +%.3f", term, fields[term], tf, idf, norm, contribution)
+		}
+	}
+
+	return score
+}
+
+// uniqueTerms drops repeated query terms so they aren't counted twice
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,68 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// ConceptImportance looks up how important a legal concept is, on the
+// taxonomy's 1-10 scale, by the name cases are tagged with
+type ConceptImportance interface {
+	ImportanceOf(name string) (int, bool)
+}
+
+// ConceptBoostConfig controls how Search favours cases whose matching legal
+// concepts are important ones. When a query term matches one of a case's
+// concepts rated at least MinImportance, the score is scaled by
+// (1 + Weight * importance / 10) for the most important such concept. It
+// relies on cases having been tagged with concepts; a zero MinImportance or a
+// nil Taxonomy disables it.
+type ConceptBoostConfig struct {
+	MinImportance int
+	Weight        float64
+	Taxonomy      ConceptImportance
+}
+
+// DefaultConceptBoostConfig returns the default concept boost: disabled, with
+// a weight ready for when a threshold is set
+func DefaultConceptBoostConfig() ConceptBoostConfig {
+	return ConceptBoostConfig{Weight: 0.5}
+}
+
+// Validate checks that the threshold is on the importance scale and the
+// weight is not negative
+func (cb ConceptBoostConfig) Validate() error {
+	if cb.MinImportance < 0 || cb.MinImportance > 10 {
+		return fmt.Errorf("concept boost min importance must be between 0 and 10")
+	}
+	if cb.Weight < 0 {
+		return fmt.Errorf("concept boost weight must be non-negative")
+	}
+	return nil
+}
+
+// boost returns the factor to scale a case's score by for the query text,
+// and the concept it comes from; 1 when no important concept matches
+func (cb ConceptBoostConfig) boost(c *models.Case, text string) (float64, string) {
+	if cb.MinImportance <= 0 || cb.Taxonomy == nil || len(c.LegalConcepts) == 0 {
+		return 1.0, ""
+	}
+
+	queryTerms := strings.Fields(strings.ToLower(text))
+	best, bestConcept := 0, ""
+	for _, concept := range c.LegalConcepts {
+		importance, ok := cb.Taxonomy.ImportanceOf(concept)
+		if !ok || importance < cb.MinImportance || importance <= best {
+			continue
+		}
+		if containsAny(strings.ToLower(concept), queryTerms) {
+			best, bestConcept = importance, concept
+		}
+	}
+	if best == 0 {
+		return 1.0, ""
+	}
+	return 1.0 + cb.Weight*float64(best)/10.0, bestConcept
+}
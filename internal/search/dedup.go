@@ -0,0 +1,68 @@
+package search
+
+import (
+	"github.com/gongahkia/kite/internal/validation"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DedupConfig controls how Search collapses duplicate results. The same case
+// can match more than once when results are merged across sources; each
+// duplicate collapses into its highest-scoring instance.
+type DedupConfig struct {
+	Enabled bool
+	// ContentHash also collapses cases stored under different IDs whose
+	// fields hash alike, such as one decision scraped from two sources. When
+	// nil, results are deduplicated by case ID only.
+	ContentHash *validation.HashTemplate
+}
+
+// DefaultDedupConfig returns the default deduplication: by case ID only
+func DefaultDedupConfig() DedupConfig {
+	return DedupConfig{Enabled: true}
+}
+
+// keys returns the keys identifying a case as a duplicate of another
+func (dc DedupConfig) keys(c *models.Case) []string {
+	keys := []string{"id:" + c.ID}
+	if dc.ContentHash != nil {
+		if hash, ok := dc.ContentHash.Hash(c); ok {
+			keys = append(keys, "hash:"+hash)
+		}
+	}
+	return keys
+}
+
+// dedupResults collapses results sharing a case ID or content hash, keeping
+// the highest-scoring instance at the position of the first
+func dedupResults(results []*SearchResult, config DedupConfig) []*SearchResult {
+	if !config.Enabled {
+		return results
+	}
+
+	kept := make([]*SearchResult, 0, len(results))
+	seen := make(map[string]int)
+	for _, r := range results {
+		keys := config.keys(r.Case)
+
+		pos := -1
+		for _, key := range keys {
+			if i, ok := seen[key]; ok {
+				pos = i
+				break
+			}
+		}
+		if pos < 0 {
+			pos = len(kept)
+			kept = append(kept, r)
+		} else if r.Score > kept[pos].Score {
+			kept[pos] = r
+		}
+
+		for _, key := range keys {
+			if _, ok := seen[key]; !ok {
+				seen[key] = pos
+			}
+		}
+	}
+	return kept
+}
@@ -17,6 +17,11 @@ type SearchEngine struct {
 	storage storage.Storage
 	logger  *observability.Logger
 	metrics *observability.Metrics
+	ranking RankingConfig
+	bm25    BM25Scorer
+	window  storage.ResultWindow
+	dedup   DedupConfig
+	concept ConceptBoostConfig
 }
 
 // SearchResult represents a single search result
@@ -24,7 +29,7 @@ type SearchResult struct {
 	Case       *models.Case
 	Score      float64
 	Highlights []string
-	Explain    string
+	Explain    string // how the score was reached, when the query asks for it
 }
 
 // SearchResponse represents search results with metadata
@@ -33,6 +38,7 @@ type SearchResponse struct {
 	TotalHits  int
 	SearchTime time.Duration
 	Facets     map[string]*Facet
+	Timeline   []storage.TimelineBucket
 	Cursor     *string
 }
 
@@ -49,14 +55,35 @@ type FacetValue struct {
 }
 
 // NewSearchEngine creates a new search engine
-func NewSearchEngine(storage storage.Storage, logger *observability.Logger, metrics *observability.Metrics) *SearchEngine {
+func NewSearchEngine(store storage.Storage, logger *observability.Logger, metrics *observability.Metrics, ranking RankingConfig) *SearchEngine {
 	return &SearchEngine{
-		storage: storage,
+		storage: store,
 		logger:  logger,
 		metrics: metrics,
+		ranking: ranking,
+		bm25:    DefaultBM25Scorer(),
+		window:  storage.DefaultResultWindow(),
+		dedup:   DefaultDedupConfig(),
+		concept: DefaultConceptBoostConfig(),
 	}
 }
 
+// SetResultWindow sets the default page size and the maximum limit and offset
+// a query may request
+func (se *SearchEngine) SetResultWindow(window storage.ResultWindow) {
+	se.window = window
+}
+
+// SetDedup sets how duplicate results are collapsed
+func (se *SearchEngine) SetDedup(config DedupConfig) {
+	se.dedup = config
+}
+
+// SetConceptBoost sets how matches on important legal concepts are boosted
+func (se *SearchEngine) SetConceptBoost(config ConceptBoostConfig) {
+	se.concept = config
+}
+
 // Search executes a search query
 func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchResponse, error) {
 	start := time.Now()
@@ -65,9 +92,19 @@ func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchRespon
 	if err := query.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid query: %w", err)
 	}
+	if query.Page != nil {
+		query.Page.Limit = se.window.Limit(query.Page.Limit)
+		if err := se.window.Check(query.Page.Limit, query.Page.Offset); err != nil {
+			return nil, err
+		}
+	}
 
 	se.logger.WithField("query", query.String()).Info("Executing search")
 
+	if query.FacetsOnly {
+		return se.Aggregate(ctx, query)
+	}
+
 	// Convert query to storage query
 	storageQuery := se.convertToStorageQuery(query)
 
@@ -78,15 +115,27 @@ func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchRespon
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
+	// Rank with BM25 when the backend keeps corpus term statistics
+	var stats *storage.TermStats
+	if query.Text != "" {
+		if stats, err = storage.CorpusTermStats(ctx, se.storage); err != nil {
+			se.logger.WithField("error", err).Warn("Term stats unavailable, falling back to basic scoring")
+			stats = nil
+		}
+	}
+
 	// Build results
 	results := make([]*SearchResult, len(cases))
 	for i, c := range cases {
+		score, explain := se.calculateRelevanceScore(c, query, stats)
 		results[i] = &SearchResult{
 			Case:       c,
-			Score:      se.calculateRelevanceScore(c, query),
+			Score:      score,
 			Highlights: se.extractHighlights(c, query),
+			Explain:    explain,
 		}
 	}
+	results = dedupResults(results, se.dedup)
 
 	// Sort by score if not already sorted
 	if query.Sort.Field == "relevance" || query.Sort.Field == "" {
@@ -98,10 +147,21 @@ func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchRespon
 		})
 	}
 
-	// Calculate facets if requested
+	// Calculate facets over the full matching set, not just this page
 	facets := make(map[string]*Facet)
 	if len(query.Facets) > 0 {
-		facets = se.calculateFacets(cases, query.Facets)
+		facets, _, err = se.aggregateFacets(ctx, storageQuery, query.Facets)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var timeline []storage.TimelineBucket
+	if query.Timeline != "" {
+		timeline, err = se.aggregateTimeline(ctx, storageQuery, query.Timeline)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	searchTime := time.Since(start)
@@ -114,6 +174,7 @@ func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchRespon
 		TotalHits:  len(results),
 		SearchTime: searchTime,
 		Facets:     facets,
+		Timeline:   timeline,
 	}
 
 	se.logger.WithFields(map[string]interface{}{
@@ -124,6 +185,78 @@ func (se *SearchEngine) Search(ctx context.Context, query *Query) (*SearchRespon
 	return response, nil
 }
 
+// Aggregate computes facet counts and the timeline over every case matching
+// the query without returning result documents
+func (se *SearchEngine) Aggregate(ctx context.Context, query *Query) (*SearchResponse, error) {
+	start := time.Now()
+	storageQuery := se.convertToStorageQuery(query)
+
+	facets, total, err := se.aggregateFacets(ctx, storageQuery, query.Facets)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeline []storage.TimelineBucket
+	if query.Timeline != "" {
+		timeline, err = se.aggregateTimeline(ctx, storageQuery, query.Timeline)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	searchTime := time.Since(start)
+	se.metrics.RecordSearchQuery(searchTime, 0)
+
+	return &SearchResponse{
+		Results:    []*SearchResult{},
+		TotalHits:  total,
+		SearchTime: searchTime,
+		Facets:     facets,
+		Timeline:   timeline,
+	}, nil
+}
+
+// aggregateFacets counts facet values and matches over every case matching the
+// storage query, ignoring pagination; see storage.AggregateFacets
+func (se *SearchEngine) aggregateFacets(ctx context.Context, storageQuery storage.SearchQuery, fields []string) (map[string]*Facet, int, error) {
+	storageQuery.Limit = 0
+	storageQuery.Offset = 0
+	storageQuery.Filters.Limit = 0
+	storageQuery.Filters.Offset = 0
+	storageQuery.Filters.IncludeFullText = false
+
+	agg, err := storage.AggregateFacets(ctx, se.storage, storageQuery, fields)
+	if err != nil {
+		se.logger.WithField("error", err).Error("Facet aggregation failed")
+		return nil, 0, fmt.Errorf("facet aggregation failed: %w", err)
+	}
+
+	facets := make(map[string]*Facet, len(agg.Counts))
+	for field, counts := range agg.Counts {
+		facets[field] = buildFacet(field, counts)
+	}
+
+	return facets, int(agg.Total), nil
+}
+
+// aggregateTimeline counts the cases matching the storage query per decision
+// period, ignoring pagination; see storage.AggregateTimeline
+func (se *SearchEngine) aggregateTimeline(ctx context.Context, storageQuery storage.SearchQuery, period storage.TimelinePeriod) ([]storage.TimelineBucket, error) {
+	storageQuery.Limit = 0
+	storageQuery.Offset = 0
+	storageQuery.Filters.Limit = 0
+	storageQuery.Filters.Offset = 0
+	storageQuery.Filters.IncludeFullText = false
+
+	timeline, err := storage.AggregateTimeline(ctx, se.storage, storageQuery, period)
+	if err != nil {
+		se.logger.WithField("error", err).Error("Timeline aggregation failed")
+		return nil, fmt.Errorf("timeline aggregation failed: %w", err)
+	}
+
+	return timeline, nil
+}
+
 // convertToStorageQuery converts a search query to storage query
 func (se *SearchEngine) convertToStorageQuery(query *Query) storage.SearchQuery {
 	sq := storage.SearchQuery{
@@ -139,14 +272,17 @@ func (se *SearchEngine) convertToStorageQuery(query *Query) storage.SearchQuery
 	// Convert filters
 	if query.Filters != nil {
 		sq.Filters = storage.CaseFilter{
-			IDs:          query.Filters.IDs,
-			Jurisdiction: ptrToString(query.Filters.Jurisdiction),
-			Court:        ptrToString(query.Filters.Court),
-			Judges:       query.Filters.Judges,
-			Concepts:     query.Filters.Concepts,
-			MinQuality:   ptrToFloat(query.Filters.MinQuality),
-			Limit:        query.Page.Limit,
-			Offset:       query.Page.Offset,
+			IDs:               query.Filters.IDs,
+			Jurisdiction:      ptrToString(query.Filters.Jurisdiction),
+			Court:             ptrToString(query.Filters.Court),
+			Jurisdictions:     query.Filters.Jurisdictions,
+			Courts:            query.Filters.Courts,
+			Judges:            query.Filters.Judges,
+			Concepts:          query.Filters.Concepts,
+			MinQuality:        ptrToFloat(query.Filters.MinQuality),
+			MinFullTextLength: query.Filters.MinFullTextLength,
+			Limit:             query.Page.Limit,
+			Offset:            query.Page.Offset,
 		}
 
 		if query.Filters.CourtLevel != nil {
@@ -172,44 +308,93 @@ func (se *SearchEngine) convertToStorageQuery(query *Query) storage.SearchQuery
 		}
 	}
 
+	// Results are ranked and highlighted on their full text
+	sq.Filters.IncludeFullText = true
+
 	return sq
 }
 
-// calculateRelevanceScore calculates relevance score for a case
-func (se *SearchEngine) calculateRelevanceScore(c *models.Case, query *Query) float64 {
+// calculateRelevanceScore calculates relevance score for a case, using BM25
+// when term statistics are available. When the query sets Explain it also
+// returns the contributions that made up the score.
+func (se *SearchEngine) calculateRelevanceScore(c *models.Case, query *Query, stats *storage.TermStats) (float64, string) {
+	var explain *scoreExplanation
+	if query.Explain {
+		explain = &scoreExplanation{}
+	}
+
 	if query.Text == "" {
-		return 1.0
+		explain.add("no query text, constant score 1.0")
+		return 1.0, explain.String()
+	}
+
+	// Per-request weights override the engine's
+	ranking := se.ranking
+	if query.Ranking != nil {
+		ranking = *query.Ranking
 	}
 
+	var score float64
+	if stats != nil && stats.DocCount() > 0 {
+		explain.add("BM25 over %d cases", stats.DocCount())
+		score = se.bm25.score(stats, c, query.Text, ranking, explain)
+	} else {
+		score = matchScore(c, query.Text, ranking, explain)
+	}
+
+	// Boost by quality score
+	if c.QualityScore > 0 {
+		boost := 1.0 + ranking.QualityBoost*c.QualityScore
+		score *= boost
+		explain.add("quality boost ×%.2f", boost)
+	}
+
+	// Boost by the importance of the matching concepts
+	if boost, concept := se.concept.boost(c, query.Text); boost != 1.0 {
+		score *= boost
+		explain.add("concept %q importance boost ×%.2f", concept, boost)
+	}
+
+	explain.add("= %.3f", score)
+	return score, explain.String()
+}
+
+// matchScore adds the field weights for each query term found in a case, for
+// backends without term statistics, recording each match in explain
+func matchScore(c *models.Case, text string, ranking RankingConfig, explain *scoreExplanation) float64 {
 	score := 0.0
-	queryTerms := strings.Fields(strings.ToLower(query.Text))
+	queryTerms := strings.Fields(strings.ToLower(text))
 
-	// Check case name (weight: 3.0)
+	// Check case name
 	for _, term := range queryTerms {
 		if strings.Contains(strings.ToLower(c.CaseName), term) {
-			score += 3.0
+			score += ranking.CaseNameWeight
+			explain.add("case name matches %q +%.1f", term, ranking.CaseNameWeight)
 		}
 	}
 
-	// Check summary (weight: 2.0)
+	// Check summary
 	for _, term := range queryTerms {
 		if strings.Contains(strings.ToLower(c.Summary), term) {
-			score += 2.0
+			score += ranking.SummaryWeight
+			explain.add("summary matches %q +%.1f", term, ranking.SummaryWeight)
 		}
 	}
 
-	// Check full text (weight: 1.0)
+	// Check full text
 	for _, term := range queryTerms {
 		if strings.Contains(strings.ToLower(c.FullText), term) {
-			score += 1.0
+			score += ranking.FullTextWeight
+			explain.add("full text matches %q +%.1f", term, ranking.FullTextWeight)
 		}
 	}
 
-	// Check legal concepts (weight: 2.5)
+	// Check legal concepts
 	for _, term := range queryTerms {
 		for _, concept := range c.LegalConcepts {
 			if strings.Contains(strings.ToLower(concept), term) {
-				score += 2.5
+				score += ranking.ConceptWeight
+				explain.add("concept %q matches %q +%.1f", concept, term, ranking.ConceptWeight)
 			}
 		}
 	}
@@ -217,11 +402,7 @@ func (se *SearchEngine) calculateRelevanceScore(c *models.Case, query *Query) fl
 	// Normalize by query length
 	if len(queryTerms) > 0 {
 		score = score / float64(len(queryTerms))
-	}
-
-	// Boost by quality score
-	if c.QualityScore != nil {
-		score *= (1.0 + *c.QualityScore)
+		explain.add("÷ %d query terms", len(queryTerms))
 	}
 
 	return score
@@ -236,25 +417,34 @@ func (se *SearchEngine) extractHighlights(c *models.Case, query *Query) []string
 	highlights := []string{}
 	queryTerms := strings.Fields(strings.ToLower(query.Text))
 
+	nameLen, summaryLen := 100, DefaultSnippetLength
+	if query.SnippetLength > 0 {
+		nameLen, summaryLen = query.SnippetLength, query.SnippetLength
+	}
+	maxHighlights := DefaultMaxHighlights
+	if query.MaxHighlights > 0 {
+		maxHighlights = query.MaxHighlights
+	}
+
 	// Highlight in case name
 	if containsAny(strings.ToLower(c.CaseName), queryTerms) {
-		highlights = append(highlights, se.highlightText(c.CaseName, queryTerms, 100))
+		highlights = append(highlights, se.highlightText(c.CaseName, queryTerms, nameLen))
 	}
 
 	// Highlight in summary
 	if containsAny(strings.ToLower(c.Summary), queryTerms) {
-		highlights = append(highlights, se.highlightText(c.Summary, queryTerms, 200))
+		highlights = append(highlights, se.highlightText(c.Summary, queryTerms, summaryLen))
 	}
 
-	// Limit to top 5 highlights
-	if len(highlights) > 5 {
-		highlights = highlights[:5]
+	if len(highlights) > maxHighlights {
+		highlights = highlights[:maxHighlights]
 	}
 
 	return highlights
 }
 
-// highlightText highlights query terms in text
+// highlightText highlights query terms in a snippet of at most maxLen
+// characters of text, starting a quarter of the way before the first match
 func (se *SearchEngine) highlightText(text string, terms []string, maxLen int) string {
 	lowerText := strings.ToLower(text)
 
@@ -276,12 +466,12 @@ func (se *SearchEngine) highlightText(text string, terms []string, maxLen int) s
 	}
 
 	// Extract context around the match
-	start := firstIdx - 50
+	start := firstIdx - maxLen/4
 	if start < 0 {
 		start = 0
 	}
 
-	end := firstIdx + maxLen
+	end := start + maxLen
 	if end > len(text) {
 		end = len(text)
 	}
@@ -305,89 +495,6 @@ func (se *SearchEngine) highlightText(text string, terms []string, maxLen int) s
 	return snippet
 }
 
-// calculateFacets calculates faceted search results
-func (se *SearchEngine) calculateFacets(cases []*models.Case, facetFields []string) map[string]*Facet {
-	facets := make(map[string]*Facet)
-
-	for _, field := range facetFields {
-		switch field {
-		case "jurisdiction":
-			facets["jurisdiction"] = se.calculateJurisdictionFacet(cases)
-		case "court":
-			facets["court"] = se.calculateCourtFacet(cases)
-		case "court_level":
-			facets["court_level"] = se.calculateCourtLevelFacet(cases)
-		case "year":
-			facets["year"] = se.calculateYearFacet(cases)
-		case "concepts":
-			facets["concepts"] = se.calculateConceptsFacet(cases)
-		}
-	}
-
-	return facets
-}
-
-// calculateJurisdictionFacet calculates jurisdiction facet
-func (se *SearchEngine) calculateJurisdictionFacet(cases []*models.Case) *Facet {
-	counts := make(map[string]int)
-
-	for _, c := range cases {
-		counts[c.Jurisdiction]++
-	}
-
-	return buildFacet("jurisdiction", counts)
-}
-
-// calculateCourtFacet calculates court facet
-func (se *SearchEngine) calculateCourtFacet(cases []*models.Case) *Facet {
-	counts := make(map[string]int)
-
-	for _, c := range cases {
-		counts[c.Court]++
-	}
-
-	return buildFacet("court", counts)
-}
-
-// calculateCourtLevelFacet calculates court level facet
-func (se *SearchEngine) calculateCourtLevelFacet(cases []*models.Case) *Facet {
-	counts := make(map[string]int)
-
-	for _, c := range cases {
-		level := fmt.Sprintf("%d", c.CourtLevel)
-		counts[level]++
-	}
-
-	return buildFacet("court_level", counts)
-}
-
-// calculateYearFacet calculates year facet
-func (se *SearchEngine) calculateYearFacet(cases []*models.Case) *Facet {
-	counts := make(map[string]int)
-
-	for _, c := range cases {
-		if c.DecisionDate != nil {
-			year := fmt.Sprintf("%d", c.DecisionDate.Year())
-			counts[year]++
-		}
-	}
-
-	return buildFacet("year", counts)
-}
-
-// calculateConceptsFacet calculates legal concepts facet
-func (se *SearchEngine) calculateConceptsFacet(cases []*models.Case) *Facet {
-	counts := make(map[string]int)
-
-	for _, c := range cases {
-		for _, concept := range c.LegalConcepts {
-			counts[concept]++
-		}
-	}
-
-	return buildFacet("concepts", counts)
-}
-
 // buildFacet builds a facet from counts
 func buildFacet(field string, counts map[string]int) *Facet {
 	values := make([]*FacetValue, 0, len(counts))
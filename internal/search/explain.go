@@ -0,0 +1,56 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scoreExplanation collects the contributions to a relevance score as it is
+// computed, so the explanation always matches the score. A nil explanation
+// records nothing, which keeps scoring free of it unless a query asks.
+type scoreExplanation struct {
+	parts []string
+}
+
+// add records one contribution to the score
+func (e *scoreExplanation) add(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	e.parts = append(e.parts, fmt.Sprintf(format, args...))
+}
+
+// String joins the contributions in the order they were applied, e.g.
+// `case name matches "negligence" +3.0; quality boost ×1.50; = 4.500`
+func (e *scoreExplanation) String() string {
+	if e == nil {
+		return ""
+	}
+	return strings.Join(e.parts, "; ")
+}
+
+// fieldWeights lists the fields a term was found in with the weight each
+// added, e.g. "case name +3.0, concept +2.5"
+type fieldWeights struct {
+	names   []string
+	weights map[string]float64
+}
+
+// add adds weight to a field, remembering the order fields were first seen
+func (fw *fieldWeights) add(field string, weight float64) {
+	if fw.weights == nil {
+		fw.weights = make(map[string]float64)
+	}
+	if _, ok := fw.weights[field]; !ok {
+		fw.names = append(fw.names, field)
+	}
+	fw.weights[field] += weight
+}
+
+func (fw *fieldWeights) String() string {
+	parts := make([]string, len(fw.names))
+	for i, name := range fw.names {
+		parts[i] = fmt.Sprintf("%s +%.1f", name, fw.weights[name])
+	}
+	return strings.Join(parts, ", ")
+}
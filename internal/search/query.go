@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -18,6 +19,15 @@ const (
 	QueryTypeRegex    QueryType = "regex"
 )
 
+// Snippet limits. A query that sets no snippet length gets case name
+// snippets of 100 characters and summary snippets of DefaultSnippetLength.
+const (
+	DefaultSnippetLength = 200
+	MaxSnippetLength     = 1000
+	DefaultMaxHighlights = 5
+	MaxHighlightsLimit   = 20
+)
+
 // Query represents a structured search query
 type Query struct {
 	Type    QueryType
@@ -27,22 +37,46 @@ type Query struct {
 	Sort    *SortOptions
 	Page    *Pagination
 	Facets  []string
+
+	// Timeline counts matching cases per decision period when set
+	Timeline storage.TimelinePeriod
+
+	// FacetsOnly returns facet counts and the timeline without result documents
+	FacetsOnly bool
+
+	// Ranking overrides the engine's relevance weights for this query
+	Ranking *RankingConfig
+
+	// Explain fills each result's Explain with the contributions to its
+	// score. It is off by default, as building the explanation costs time.
+	Explain bool
+
+	// SnippetLength is the most characters of case text in each highlight,
+	// up to MaxSnippetLength; zero uses the defaults
+	SnippetLength int
+
+	// MaxHighlights is the most highlights returned per result, up to
+	// MaxHighlightsLimit; zero uses DefaultMaxHighlights
+	MaxHighlights int
 }
 
 // Filters represents search filters
 type Filters struct {
-	IDs          []string
-	Jurisdiction *string
-	Court        *string
-	CourtLevel   *models.CourtLevel
-	Status       *models.CaseStatus
-	StartDate    *time.Time
-	EndDate      *time.Time
-	Judges       []string
-	Parties      []string
-	Concepts     []string
-	MinQuality   *float64
-	HasPDF       *bool
+	IDs               []string
+	Jurisdiction      *string
+	Court             *string
+	Jurisdictions     []string
+	Courts            []string
+	CourtLevel        *models.CourtLevel
+	Status            *models.CaseStatus
+	StartDate         *time.Time
+	EndDate           *time.Time
+	Judges            []string
+	Parties           []string
+	Concepts          []string
+	MinQuality        *float64
+	MinFullTextLength int
+	HasPDF            *bool
 }
 
 // SortOptions represents sorting options
@@ -71,8 +105,9 @@ func NewQuery() *QueryBuilder {
 			Fields:  []string{},
 			Filters: &Filters{},
 			Sort:    &SortOptions{},
+			// A zero limit gets the engine's default page size
 			Page: &Pagination{
-				Limit:  10,
+				Limit:  0,
 				Offset: 0,
 			},
 		},
@@ -131,6 +166,18 @@ func (qb *QueryBuilder) FilterByCourt(court string) *QueryBuilder {
 	return qb
 }
 
+// FilterByJurisdictions filters to cases in any of the jurisdictions
+func (qb *QueryBuilder) FilterByJurisdictions(jurisdictions ...string) *QueryBuilder {
+	qb.query.Filters.Jurisdictions = append(qb.query.Filters.Jurisdictions, jurisdictions...)
+	return qb
+}
+
+// FilterByCourts filters to cases from any of the courts
+func (qb *QueryBuilder) FilterByCourts(courts ...string) *QueryBuilder {
+	qb.query.Filters.Courts = append(qb.query.Filters.Courts, courts...)
+	return qb
+}
+
 // FilterByCourtLevel filters by court level
 func (qb *QueryBuilder) FilterByCourtLevel(level models.CourtLevel) *QueryBuilder {
 	qb.query.Filters.CourtLevel = &level
@@ -174,6 +221,12 @@ func (qb *QueryBuilder) FilterByMinQuality(score float64) *QueryBuilder {
 	return qb
 }
 
+// FilterByMinFullTextLength excludes cases with less full text than length characters
+func (qb *QueryBuilder) FilterByMinFullTextLength(length int) *QueryBuilder {
+	qb.query.Filters.MinFullTextLength = length
+	return qb
+}
+
 // FilterByHasPDF filters cases with/without PDF
 func (qb *QueryBuilder) FilterByHasPDF(hasPDF bool) *QueryBuilder {
 	qb.query.Filters.HasPDF = &hasPDF
@@ -208,6 +261,14 @@ func (qb *QueryBuilder) SortByQuality(desc bool) *QueryBuilder {
 	return qb
 }
 
+// SortByAuthority sorts cases from the highest courts first, then by
+// recency. Backends order by it, so it pages like the other sorts.
+func (qb *QueryBuilder) SortByAuthority() *QueryBuilder {
+	qb.query.Sort.Field = storage.OrderByAuthority
+	qb.query.Sort.Desc = false
+	return qb
+}
+
 // Limit sets result limit
 func (qb *QueryBuilder) Limit(limit int) *QueryBuilder {
 	qb.query.Page.Limit = limit
@@ -232,6 +293,42 @@ func (qb *QueryBuilder) WithFacets(facets ...string) *QueryBuilder {
 	return qb
 }
 
+// WithTimeline requests counts of matching cases per decision year, quarter or month
+func (qb *QueryBuilder) WithTimeline(period storage.TimelinePeriod) *QueryBuilder {
+	qb.query.Timeline = period
+	return qb
+}
+
+// FacetsOnly requests facet counts and the timeline without result documents
+func (qb *QueryBuilder) FacetsOnly() *QueryBuilder {
+	qb.query.FacetsOnly = true
+	return qb
+}
+
+// WithRanking overrides the engine's relevance weights for this query
+func (qb *QueryBuilder) WithRanking(ranking RankingConfig) *QueryBuilder {
+	qb.query.Ranking = &ranking
+	return qb
+}
+
+// WithExplain explains how each result's score was reached
+func (qb *QueryBuilder) WithExplain() *QueryBuilder {
+	qb.query.Explain = true
+	return qb
+}
+
+// WithSnippetLength sets the most characters of case text in each highlight
+func (qb *QueryBuilder) WithSnippetLength(length int) *QueryBuilder {
+	qb.query.SnippetLength = length
+	return qb
+}
+
+// WithMaxHighlights sets the most highlights returned per result
+func (qb *QueryBuilder) WithMaxHighlights(n int) *QueryBuilder {
+	qb.query.MaxHighlights = n
+	return qb
+}
+
 // Build returns the constructed query
 func (qb *QueryBuilder) Build() *Query {
 	return qb.query
@@ -258,6 +355,12 @@ func (q *Query) String() string {
 		if q.Filters.Court != nil {
 			parts = append(parts, fmt.Sprintf("Court: %s", *q.Filters.Court))
 		}
+		if len(q.Filters.Jurisdictions) > 0 {
+			parts = append(parts, fmt.Sprintf("Jurisdictions: %v", q.Filters.Jurisdictions))
+		}
+		if len(q.Filters.Courts) > 0 {
+			parts = append(parts, fmt.Sprintf("Courts: %v", q.Filters.Courts))
+		}
 		if len(q.Filters.Judges) > 0 {
 			parts = append(parts, fmt.Sprintf("Judges: %v", q.Filters.Judges))
 		}
@@ -284,9 +387,35 @@ func (q *Query) Validate() error {
 		return fmt.Errorf("query must have either text or ID filters")
 	}
 
+	if q.FacetsOnly && len(q.Facets) == 0 && q.Timeline == "" {
+		return fmt.Errorf("facets-only queries must request at least one facet or a timeline")
+	}
+
+	if q.Timeline != "" && !q.Timeline.Valid() {
+		return fmt.Errorf("timeline period must be year, quarter or month")
+	}
+
+	if q.Filters != nil && q.Filters.MinFullTextLength < 0 {
+		return fmt.Errorf("min_full_text_length must be non-negative")
+	}
+
+	if q.SnippetLength < 0 || q.SnippetLength > MaxSnippetLength {
+		return fmt.Errorf("snippet_length must be between 0 and %d", MaxSnippetLength)
+	}
+
+	if q.MaxHighlights < 0 || q.MaxHighlights > MaxHighlightsLimit {
+		return fmt.Errorf("max_highlights must be between 0 and %d", MaxHighlightsLimit)
+	}
+
+	if q.Ranking != nil {
+		if err := q.Ranking.Validate(); err != nil {
+			return err
+		}
+	}
+
 	if q.Page != nil {
-		if q.Page.Limit < 1 || q.Page.Limit > 1000 {
-			return fmt.Errorf("limit must be between 1 and 1000")
+		if q.Page.Limit < 0 {
+			return fmt.Errorf("limit must be non-negative")
 		}
 		if q.Page.Offset < 0 {
 			return fmt.Errorf("offset must be non-negative")
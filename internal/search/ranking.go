@@ -0,0 +1,44 @@
+package search
+
+import "fmt"
+
+// RankingConfig holds the weights used to score relevance. Under BM25 each
+// weight scales how much an occurrence in the field counts toward a term's
+// frequency; without term statistics it is added once per query term found in
+// the field. The quality boost scales the final score by
+// (1 + QualityBoost * quality score).
+type RankingConfig struct {
+	CaseNameWeight float64 `json:"case_name_weight"`
+	SummaryWeight  float64 `json:"summary_weight"`
+	FullTextWeight float64 `json:"full_text_weight"`
+	ConceptWeight  float64 `json:"concept_weight"` // per matching legal concept
+	QualityBoost   float64 `json:"quality_boost"`
+}
+
+// DefaultRankingConfig returns the default relevance weights
+func DefaultRankingConfig() RankingConfig {
+	return RankingConfig{
+		CaseNameWeight: 3.0,
+		SummaryWeight:  2.0,
+		FullTextWeight: 1.0,
+		ConceptWeight:  2.5,
+		QualityBoost:   1.0,
+	}
+}
+
+// Validate checks that no weight is negative
+func (rc RankingConfig) Validate() error {
+	weights := map[string]float64{
+		"case_name_weight": rc.CaseNameWeight,
+		"summary_weight":   rc.SummaryWeight,
+		"full_text_weight": rc.FullTextWeight,
+		"concept_weight":   rc.ConceptWeight,
+		"quality_boost":    rc.QualityBoost,
+	}
+	for name, weight := range weights {
+		if weight < 0 {
+			return fmt.Errorf("ranking %s must be non-negative", name)
+		}
+	}
+	return nil
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	"github.com/gongahkia/kite/internal/storage"
-	"github.com/gongahkia/kite/pkg/models"
 )
 
 // Suggestion represents a query suggestion
@@ -55,8 +54,10 @@ func (se *SuggestionEngine) Suggest(ctx context.Context, partial string, limit i
 	}
 
 	// Get concept suggestions
-	conceptSuggestions := se.suggestConcepts(partial, limit)
-	suggestions = append(suggestions, conceptSuggestions...)
+	conceptSuggestions, err := se.suggestConcepts(ctx, partial, limit)
+	if err == nil {
+		suggestions = append(suggestions, conceptSuggestions...)
+	}
 
 	// Sort by score
 	sort.Slice(suggestions, func(i, j int) bool {
@@ -71,55 +72,45 @@ func (se *SuggestionEngine) Suggest(ctx context.Context, partial string, limit i
 	return suggestions, nil
 }
 
-// suggestCaseNames suggests case names
+// suggestCaseNames suggests case names starting with the partial input
 func (se *SuggestionEngine) suggestCaseNames(ctx context.Context, partial string, limit int) ([]*Suggestion, error) {
-	// Simple prefix search for case names
-	filter := storage.CaseFilter{
-		Limit: limit * 2,
-	}
-
-	cases, err := se.storage.ListCases(ctx, filter)
+	names, err := se.storage.SuggestCaseNames(ctx, partial, storage.CaseFilter{}, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	suggestions := make([]*Suggestion, 0)
+	suggestions := make([]*Suggestion, 0, len(names))
 	partialLower := strings.ToLower(partial)
 
-	for _, c := range cases {
-		if strings.Contains(strings.ToLower(c.CaseName), partialLower) {
-			score := calculateSimilarity(partialLower, strings.ToLower(c.CaseName))
-			suggestions = append(suggestions, &Suggestion{
-				Text:  c.CaseName,
-				Score: score * 2.0, // Boost case names
-				Type:  "case_name",
-			})
-		}
+	for _, name := range names {
+		score := calculateSimilarity(partialLower, strings.ToLower(name))
+		suggestions = append(suggestions, &Suggestion{
+			Text:  name,
+			Score: score * 2.0, // Boost case names
+			Type:  "case_name",
+		})
 	}
 
 	return suggestions, nil
 }
 
-// suggestJudges suggests judge names
+// suggestJudges suggests judge names starting with the partial input
 func (se *SuggestionEngine) suggestJudges(ctx context.Context, partial string, limit int) ([]*Suggestion, error) {
-	// Get all judges
-	judges, err := se.storage.ListJudges(ctx, storage.JudgeFilter{Limit: limit * 5})
+	names, err := se.storage.SuggestJudgeNames(ctx, partial, storage.JudgeFilter{}, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	suggestions := make([]*Suggestion, 0)
+	suggestions := make([]*Suggestion, 0, len(names))
 	partialLower := strings.ToLower(partial)
 
-	for _, j := range judges {
-		if strings.Contains(strings.ToLower(j.Name), partialLower) {
-			score := calculateSimilarity(partialLower, strings.ToLower(j.Name))
-			suggestions = append(suggestions, &Suggestion{
-				Text:  j.Name,
-				Score: score * 1.5, // Boost judges
-				Type:  "judge",
-			})
-		}
+	for _, name := range names {
+		score := calculateSimilarity(partialLower, strings.ToLower(name))
+		suggestions = append(suggestions, &Suggestion{
+			Text:  name,
+			Score: score * 1.5, // Boost judges
+			Type:  "judge",
+		})
 	}
 
 	return suggestions, nil
@@ -157,8 +148,13 @@ func (se *SuggestionEngine) suggestCourts(ctx context.Context, partial string, l
 	return suggestions, nil
 }
 
-// suggestConcepts suggests legal concepts
-func (se *SuggestionEngine) suggestConcepts(partial string, limit int) []*Suggestion {
+// suggestConcepts suggests stored and common legal concepts
+func (se *SuggestionEngine) suggestConcepts(ctx context.Context, partial string, limit int) ([]*Suggestion, error) {
+	stored, err := se.storage.SuggestConcepts(ctx, partial, storage.CaseFilter{}, limit)
+	if err != nil {
+		return nil, err
+	}
+
 	// Common legal concepts (this should ideally come from a taxonomy)
 	commonConcepts := []string{
 		"constitutional law",
@@ -185,9 +181,20 @@ func (se *SuggestionEngine) suggestConcepts(partial string, limit int) []*Sugges
 
 	suggestions := make([]*Suggestion, 0)
 	partialLower := strings.ToLower(partial)
+	seen := make(map[string]bool)
+
+	for _, concept := range stored {
+		conceptLower := strings.ToLower(concept)
+		seen[conceptLower] = true
+		suggestions = append(suggestions, &Suggestion{
+			Text:  concept,
+			Score: calculateSimilarity(partialLower, conceptLower),
+			Type:  "concept",
+		})
+	}
 
 	for _, concept := range commonConcepts {
-		if strings.Contains(concept, partialLower) {
+		if !seen[concept] && strings.Contains(concept, partialLower) {
 			score := calculateSimilarity(partialLower, concept)
 			suggestions = append(suggestions, &Suggestion{
 				Text:  concept,
@@ -197,7 +204,7 @@ func (se *SuggestionEngine) suggestConcepts(partial string, limit int) []*Sugges
 		}
 	}
 
-	return suggestions
+	return suggestions, nil
 }
 
 // calculateSimilarity calculates string similarity (simple version)
@@ -0,0 +1,106 @@
+package search
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Synonym file formats
+const (
+	SynonymFormatYAML = "yaml"
+	SynonymFormatCSV  = "csv"
+)
+
+// LoadSynonyms loads synonym groups from a YAML or CSV thesaurus file, picking
+// the format from the file extension. When replace is true the loaded groups
+// replace the defaults, otherwise they are merged into them.
+func (qe *QueryExpander) LoadSynonyms(path string, replace bool) error {
+	var format string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = SynonymFormatYAML
+	case ".csv":
+		format = SynonymFormatCSV
+	default:
+		return fmt.Errorf("unsupported synonym file extension: %s", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open synonym file: %w", err)
+	}
+	defer f.Close()
+
+	return qe.LoadSynonymsFromReader(f, format, replace)
+}
+
+// LoadSynonymsFromReader loads synonym groups in the given format. YAML input
+// is a list of groups, e.g. [[tort, delict], [solicitor, lawyer]]; CSV input
+// has one group per line. Every term in a group expands to all the others.
+func (qe *QueryExpander) LoadSynonymsFromReader(r io.Reader, format string, replace bool) error {
+	var groups [][]string
+
+	switch format {
+	case SynonymFormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&groups); err != nil && err != io.EOF {
+			return fmt.Errorf("failed to parse synonym YAML: %w", err)
+		}
+	case SynonymFormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		reader.Comment = '#'
+
+		records, err := reader.ReadAll()
+		if err != nil {
+			return fmt.Errorf("failed to parse synonym CSV: %w", err)
+		}
+		groups = records
+	default:
+		return fmt.Errorf("unsupported synonym format: %s", format)
+	}
+
+	if replace {
+		qe.synonyms = make(map[string][]string)
+	}
+
+	for _, group := range groups {
+		qe.addSynonymGroup(group)
+	}
+
+	return nil
+}
+
+// addSynonymGroup links every term in the group to every other term
+func (qe *QueryExpander) addSynonymGroup(group []string) {
+	terms := make([]string, 0, len(group))
+	for _, term := range group {
+		term = strings.ToLower(strings.TrimSpace(term))
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	for _, term := range terms {
+		for _, synonym := range terms {
+			if synonym != term && !containsString(qe.synonyms[term], synonym) {
+				qe.synonyms[term] = append(qe.synonyms[term], synonym)
+			}
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
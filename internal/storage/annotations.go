@@ -0,0 +1,306 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AnnotationFilter selects one owner's annotations on a case. Every field
+// must match, so one user never lists another's annotations.
+type AnnotationFilter struct {
+	CaseID   string
+	UserID   string
+	TenantID string
+}
+
+// AnnotationStore is implemented by backends that keep case annotations.
+// SaveAnnotation is an upsert by annotation ID; annotations are listed
+// oldest first.
+type AnnotationStore interface {
+	SaveAnnotation(ctx context.Context, a *models.Annotation) error
+	GetAnnotation(ctx context.Context, id string) (*models.Annotation, error)
+	ListAnnotations(ctx context.Context, filter AnnotationFilter) ([]*models.Annotation, error)
+	DeleteAnnotation(ctx context.Context, id string) error
+}
+
+// Annotations returns the annotation store behind store, looking through the
+// tenancy, lifecycle, timeout and write-behind wrappers, and false if the
+// backend keeps no annotations
+func Annotations(store Storage) (AnnotationStore, bool) {
+	for {
+		if annotations, ok := store.(AnnotationStore); ok {
+			return annotations, true
+		}
+		switch s := store.(type) {
+		case *TenantStorage:
+			store = s.Storage
+		case *LifecycleStorage:
+			store = s.Storage
+		case *TimeoutStorage:
+			store = s.Storage
+		case *WriteBehindStorage:
+			store = s.Storage
+		default:
+			return nil, false
+		}
+	}
+}
+
+// annotationNotFound reports a missing annotation
+func annotationNotFound(id string) error {
+	return errors.StorageError(fmt.Sprintf("annotation not found: %s", id), errors.ErrNotFound)
+}
+
+// sqlAnnotationsSchema creates the annotations table, shared by the SQL
+// backends. Tags are kept as a JSON array.
+const sqlAnnotationsSchema = `
+	CREATE TABLE IF NOT EXISTS case_annotations (
+		id TEXT PRIMARY KEY,
+		case_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		tenant_id TEXT NOT NULL DEFAULT '',
+		note TEXT NOT NULL DEFAULT '',
+		tags TEXT,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_case_annotations_owner ON case_annotations(case_id, user_id, tenant_id);
+`
+
+const sqlAnnotationColumns = `id, case_id, user_id, tenant_id, note, tags, created_at, updated_at`
+
+// saveSQLAnnotation upserts an annotation
+func saveSQLAnnotation(ctx context.Context, db *sql.DB, a *models.Annotation, placeholder func(int) string) error {
+	tags, err := json.Marshal(a.Tags)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO case_annotations (%s)
+		VALUES (%s, %s, %s, %s, %s, %s, %s, %s)
+		ON CONFLICT(id) DO UPDATE SET
+			note = excluded.note, tags = excluded.tags, updated_at = excluded.updated_at
+	`, sqlAnnotationColumns, placeholder(1), placeholder(2), placeholder(3), placeholder(4),
+		placeholder(5), placeholder(6), placeholder(7), placeholder(8))
+
+	_, err = db.ExecContext(ctx, query, a.ID, a.CaseID, a.UserID, a.TenantID, a.Note, string(tags), a.CreatedAt, a.UpdatedAt)
+	return err
+}
+
+// scanSQLAnnotation reads an annotation row selected with sqlAnnotationColumns
+func scanSQLAnnotation(scan func(dest ...interface{}) error) (*models.Annotation, error) {
+	var a models.Annotation
+	var tags sql.NullString
+	if err := scan(&a.ID, &a.CaseID, &a.UserID, &a.TenantID, &a.Note, &tags, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if tags.Valid && tags.String != "" {
+		if err := json.Unmarshal([]byte(tags.String), &a.Tags); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+// getSQLAnnotation reads one annotation by ID
+func getSQLAnnotation(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) (*models.Annotation, error) {
+	query := fmt.Sprintf(`SELECT %s FROM case_annotations WHERE id = %s`, sqlAnnotationColumns, placeholder(1))
+	a, err := scanSQLAnnotation(db.QueryRowContext(ctx, query, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, annotationNotFound(id)
+	}
+	return a, err
+}
+
+// listSQLAnnotations reads one owner's annotations on a case
+func listSQLAnnotations(ctx context.Context, db *sql.DB, filter AnnotationFilter, placeholder func(int) string) ([]*models.Annotation, error) {
+	query := fmt.Sprintf(`
+		SELECT %s FROM case_annotations
+		WHERE case_id = %s AND user_id = %s AND tenant_id = %s
+		ORDER BY created_at, id
+	`, sqlAnnotationColumns, placeholder(1), placeholder(2), placeholder(3))
+
+	rows, err := db.QueryContext(ctx, query, filter.CaseID, filter.UserID, filter.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	annotations := make([]*models.Annotation, 0)
+	for rows.Next() {
+		a, err := scanSQLAnnotation(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
+
+// deleteSQLAnnotation deletes one annotation by ID
+func deleteSQLAnnotation(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM case_annotations WHERE id = `+placeholder(1), id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return annotationNotFound(id)
+	}
+	return nil
+}
+
+// SaveAnnotation saves an annotation, replacing any with the same ID
+func (ss *SQLiteStorage) SaveAnnotation(ctx context.Context, a *models.Annotation) error {
+	return saveSQLAnnotation(ctx, ss.db, a, positionalPlaceholder)
+}
+
+// GetAnnotation retrieves an annotation by ID
+func (ss *SQLiteStorage) GetAnnotation(ctx context.Context, id string) (*models.Annotation, error) {
+	return getSQLAnnotation(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// ListAnnotations lists one owner's annotations on a case
+func (ss *SQLiteStorage) ListAnnotations(ctx context.Context, filter AnnotationFilter) ([]*models.Annotation, error) {
+	return listSQLAnnotations(ctx, ss.db, filter, positionalPlaceholder)
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (ss *SQLiteStorage) DeleteAnnotation(ctx context.Context, id string) error {
+	return deleteSQLAnnotation(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// SaveAnnotation saves an annotation, replacing any with the same ID
+func (ps *PostgresStorage) SaveAnnotation(ctx context.Context, a *models.Annotation) error {
+	return saveSQLAnnotation(ctx, ps.db, a, postgresPlaceholder)
+}
+
+// GetAnnotation retrieves an annotation by ID
+func (ps *PostgresStorage) GetAnnotation(ctx context.Context, id string) (*models.Annotation, error) {
+	return getSQLAnnotation(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// ListAnnotations lists one owner's annotations on a case
+func (ps *PostgresStorage) ListAnnotations(ctx context.Context, filter AnnotationFilter) ([]*models.Annotation, error) {
+	return listSQLAnnotations(ctx, ps.db, filter, postgresPlaceholder)
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (ps *PostgresStorage) DeleteAnnotation(ctx context.Context, id string) error {
+	return deleteSQLAnnotation(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// SaveAnnotation saves a copy of an annotation, replacing any with the same ID
+func (ms *MemoryStorage) SaveAnnotation(ctx context.Context, a *models.Annotation) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	stored := *a
+	stored.Tags = append([]string(nil), a.Tags...)
+	ms.annotations[a.ID] = &stored
+	return nil
+}
+
+// GetAnnotation retrieves a copy of an annotation by ID
+func (ms *MemoryStorage) GetAnnotation(ctx context.Context, id string) (*models.Annotation, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	a, ok := ms.annotations[id]
+	if !ok {
+		return nil, annotationNotFound(id)
+	}
+	copied := *a
+	return &copied, nil
+}
+
+// ListAnnotations lists one owner's annotations on a case
+func (ms *MemoryStorage) ListAnnotations(ctx context.Context, filter AnnotationFilter) ([]*models.Annotation, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	annotations := make([]*models.Annotation, 0)
+	for _, a := range ms.annotations {
+		if a.CaseID == filter.CaseID && a.UserID == filter.UserID && a.TenantID == filter.TenantID {
+			copied := *a
+			annotations = append(annotations, &copied)
+		}
+	}
+	sort.Slice(annotations, func(i, j int) bool {
+		if !annotations[i].CreatedAt.Equal(annotations[j].CreatedAt) {
+			return annotations[i].CreatedAt.Before(annotations[j].CreatedAt)
+		}
+		return annotations[i].ID < annotations[j].ID
+	})
+	return annotations, nil
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (ms *MemoryStorage) DeleteAnnotation(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.annotations[id]; !ok {
+		return annotationNotFound(id)
+	}
+	delete(ms.annotations, id)
+	return nil
+}
+
+// SaveAnnotation saves an annotation, replacing any with the same ID
+func (ms *MongoStorage) SaveAnnotation(ctx context.Context, a *models.Annotation) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := ms.database.Collection("case_annotations").ReplaceOne(ctx, bson.M{"id": a.ID}, a, opts)
+	return err
+}
+
+// GetAnnotation retrieves an annotation by ID
+func (ms *MongoStorage) GetAnnotation(ctx context.Context, id string) (*models.Annotation, error) {
+	var a models.Annotation
+	err := ms.database.Collection("case_annotations").FindOne(ctx, bson.M{"id": id}).Decode(&a)
+	if err == mongo.ErrNoDocuments {
+		return nil, annotationNotFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListAnnotations lists one owner's annotations on a case
+func (ms *MongoStorage) ListAnnotations(ctx context.Context, filter AnnotationFilter) ([]*models.Annotation, error) {
+	query := bson.M{"case_id": filter.CaseID, "user_id": filter.UserID, "tenant_id": filter.TenantID}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}})
+
+	cursor, err := ms.database.Collection("case_annotations").Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	annotations := make([]*models.Annotation, 0)
+	if err := cursor.All(ctx, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
+// DeleteAnnotation deletes an annotation by ID
+func (ms *MongoStorage) DeleteAnnotation(ctx context.Context, id string) error {
+	result, err := ms.database.Collection("case_annotations").DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return annotationNotFound(id)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrEmptyDeleteFilter is returned when a bulk delete filter has no conditions
+// and DeleteOptions.Force is not set
+var ErrEmptyDeleteFilter = errors.New("delete filter has no conditions; set force to delete every case")
+
+// ErrUnsupportedDeleteFilter is returned when a bulk delete filter uses
+// conditions the database backends cannot express
+var ErrUnsupportedDeleteFilter = errors.New("judges, concepts and min_quality are not supported when deleting cases")
+
+// checkDeleteFilter guards bulk deletes against wiping every case by accident
+// and against conditions the database backends cannot express
+func checkDeleteFilter(filter CaseFilter, opts DeleteOptions, inMemory bool) error {
+	if !inMemory && (len(filter.Judges) > 0 || len(filter.Concepts) > 0 || filter.MinQuality > 0) {
+		return ErrUnsupportedDeleteFilter
+	}
+	if !opts.Force && !filter.HasConditions() {
+		return ErrEmptyDeleteFilter
+	}
+	return nil
+}
+
+// deleteSQLCases deletes matching cases in one transaction. Citations made by
+// the deleted cases are removed and citations pointing at them are unlinked.
+func deleteSQLCases(ctx context.Context, db *sql.DB, where string, args []interface{}) (int64, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	matching := "SELECT id FROM cases WHERE " + where
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM citations WHERE citing_case_id IN ("+matching+")", args...); err != nil {
+		return 0, fmt.Errorf("failed to delete citations: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE citations SET cited_case_id = NULL WHERE cited_case_id IN ("+matching+")", args...); err != nil {
+		return 0, fmt.Errorf("failed to unlink citations: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM cases WHERE "+where, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete cases: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit delete: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// DeleteCasesByFilter deletes every case matching the filter
+func (ss *SQLiteStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	if err := checkDeleteFilter(filter, opts, false); err != nil {
+		return 0, err
+	}
+
+	where, args := sqlCaseConditions(filter, "cases", nil, positionalPlaceholder)
+	deleted, err := deleteSQLCases(ctx, ss.db, where, args)
+	if deleted > 0 {
+		ss.terms.reset()
+	}
+	return deleted, err
+}
+
+// DeleteCasesByFilter deletes every case matching the filter
+func (ps *PostgresStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	if err := checkDeleteFilter(filter, opts, false); err != nil {
+		return 0, err
+	}
+
+	where, args := sqlCaseConditions(filter, "cases", nil, postgresPlaceholder)
+	return deleteSQLCases(ctx, ps.db, where, args)
+}
+
+// DeleteCasesByFilter deletes every case matching the filter. Citations made
+// by the deleted cases are removed and citations pointing at them are
+// unlinked, as in the SQL backends; without a transaction these run after the
+// cases are deleted.
+func (ms *MongoStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	if err := checkDeleteFilter(filter, opts, false); err != nil {
+		return 0, err
+	}
+
+	query := mongoCaseConditions(filter)
+
+	ids, err := ms.cases.Distinct(ctx, "id", query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find cases to delete: %w", err)
+	}
+
+	result, err := ms.cases.DeleteMany(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete cases: %w", err)
+	}
+	if _, err := ms.fullTexts.DeleteMany(ctx, bson.M{"case_id": bson.M{"$in": ids}}); err != nil {
+		return result.DeletedCount, fmt.Errorf("failed to delete full text: %w", err)
+	}
+	if _, err := ms.citations.DeleteMany(ctx, bson.M{"citing_case_id": bson.M{"$in": ids}}); err != nil {
+		return result.DeletedCount, fmt.Errorf("failed to delete citations: %w", err)
+	}
+	unlink := bson.M{"$unset": bson.M{"cited_case_id": ""}}
+	if _, err := ms.citations.UpdateMany(ctx, bson.M{"cited_case_id": bson.M{"$in": ids}}, unlink); err != nil {
+		return result.DeletedCount, fmt.Errorf("failed to unlink citations: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// DeleteCasesByFilter deletes every case matching the filter. Citations made
+// by the deleted cases are removed and citations pointing at them are unlinked.
+func (ms *MemoryStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	if err := checkDeleteFilter(filter, opts, true); err != nil {
+		return 0, err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	removed := make(map[string]bool)
+	for id, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			delete(ms.cases, id)
+			ms.terms.Remove(id)
+			removed[id] = true
+		}
+	}
+
+	for id, cit := range ms.citations {
+		if removed[cit.CitingCaseID] {
+			delete(ms.citations, id)
+		} else if removed[cit.CaseID] {
+			cit.CaseID = ""
+		}
+	}
+
+	return int64(len(removed)), nil
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CaseVersionStore is implemented by backends that keep the versions a case
+// had before it was refreshed from its source. SaveCaseVersion keeps a copy
+// of the case as it is, replacing any copy of the same version; versions are
+// listed oldest first.
+type CaseVersionStore interface {
+	SaveCaseVersion(ctx context.Context, c *models.Case) error
+	ListCaseVersions(ctx context.Context, caseID string) ([]*models.Case, error)
+}
+
+// CaseVersions returns the case version store behind store, looking through
+// the tenancy, lifecycle, timeout and write-behind wrappers, and false if the
+// backend keeps no earlier versions
+func CaseVersions(store Storage) (CaseVersionStore, bool) {
+	for {
+		if versions, ok := store.(CaseVersionStore); ok {
+			return versions, true
+		}
+		switch s := store.(type) {
+		case *TenantStorage:
+			store = s.Storage
+		case *LifecycleStorage:
+			store = s.Storage
+		case *TimeoutStorage:
+			store = s.Storage
+		case *WriteBehindStorage:
+			store = s.Storage
+		default:
+			return nil, false
+		}
+	}
+}
+
+// sqlCaseVersionsSchema creates the case version table, shared by the SQL
+// backends. Each version is kept as the case's JSON.
+const sqlCaseVersionsSchema = `
+	CREATE TABLE IF NOT EXISTS case_versions (
+		case_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		data TEXT NOT NULL,
+		saved_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (case_id, version)
+	);
+`
+
+// saveSQLCaseVersion upserts a case version
+func saveSQLCaseVersion(ctx context.Context, db *sql.DB, c *models.Case, placeholder func(int) string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO case_versions (case_id, version, data, saved_at)
+		VALUES (%s, %s, %s, %s)
+		ON CONFLICT(case_id, version) DO UPDATE SET data = excluded.data, saved_at = excluded.saved_at
+	`, placeholder(1), placeholder(2), placeholder(3), placeholder(4))
+
+	_, err = db.ExecContext(ctx, query, c.ID, c.Version, string(data), time.Now().UTC())
+	return err
+}
+
+// listSQLCaseVersions reads a case's earlier versions, oldest first
+func listSQLCaseVersions(ctx context.Context, db *sql.DB, caseID string, placeholder func(int) string) ([]*models.Case, error) {
+	query := `SELECT data FROM case_versions WHERE case_id = ` + placeholder(1) + ` ORDER BY version`
+
+	rows, err := db.QueryContext(ctx, query, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make([]*models.Case, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var c models.Case
+		if err := json.Unmarshal([]byte(data), &c); err != nil {
+			return nil, err
+		}
+		versions = append(versions, &c)
+	}
+	return versions, rows.Err()
+}
+
+// SaveCaseVersion keeps a copy of a case as it is
+func (ss *SQLiteStorage) SaveCaseVersion(ctx context.Context, c *models.Case) error {
+	return saveSQLCaseVersion(ctx, ss.db, c, positionalPlaceholder)
+}
+
+// ListCaseVersions lists a case's earlier versions, oldest first
+func (ss *SQLiteStorage) ListCaseVersions(ctx context.Context, caseID string) ([]*models.Case, error) {
+	return listSQLCaseVersions(ctx, ss.db, caseID, positionalPlaceholder)
+}
+
+// SaveCaseVersion keeps a copy of a case as it is
+func (ps *PostgresStorage) SaveCaseVersion(ctx context.Context, c *models.Case) error {
+	return saveSQLCaseVersion(ctx, ps.db, c, postgresPlaceholder)
+}
+
+// ListCaseVersions lists a case's earlier versions, oldest first
+func (ps *PostgresStorage) ListCaseVersions(ctx context.Context, caseID string) ([]*models.Case, error) {
+	return listSQLCaseVersions(ctx, ps.db, caseID, postgresPlaceholder)
+}
+
+// SaveCaseVersion keeps a copy of a case as it is
+func (ms *MemoryStorage) SaveCaseVersion(ctx context.Context, c *models.Case) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	copied := *c
+	if ms.caseVersions[c.ID] == nil {
+		ms.caseVersions[c.ID] = make(map[int]*models.Case)
+	}
+	ms.caseVersions[c.ID][c.Version] = &copied
+	return nil
+}
+
+// ListCaseVersions lists a case's earlier versions, oldest first
+func (ms *MemoryStorage) ListCaseVersions(ctx context.Context, caseID string) ([]*models.Case, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	versions := make([]*models.Case, 0, len(ms.caseVersions[caseID]))
+	for _, c := range ms.caseVersions[caseID] {
+		copied := *c
+		versions = append(versions, &copied)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version < versions[j].Version })
+	return versions, nil
+}
+
+// mongoCaseVersion is an earlier version of a case, kept in the case_versions
+// collection
+type mongoCaseVersion struct {
+	CaseID  string       `bson:"case_id"`
+	Version int          `bson:"version"`
+	Case    *models.Case `bson:"case"`
+	SavedAt time.Time    `bson:"saved_at"`
+}
+
+// SaveCaseVersion keeps a copy of a case as it is
+func (ms *MongoStorage) SaveCaseVersion(ctx context.Context, c *models.Case) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := ms.database.Collection("case_versions").ReplaceOne(ctx,
+		bson.M{"case_id": c.ID, "version": c.Version},
+		mongoCaseVersion{CaseID: c.ID, Version: c.Version, Case: c, SavedAt: time.Now().UTC()},
+		opts)
+	return err
+}
+
+// ListCaseVersions lists a case's earlier versions, oldest first
+func (ms *MongoStorage) ListCaseVersions(ctx context.Context, caseID string) ([]*models.Case, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := ms.database.Collection("case_versions").Find(ctx, bson.M{"case_id": caseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var stored []mongoCaseVersion
+	if err := cursor.All(ctx, &stored); err != nil {
+		return nil, err
+	}
+
+	versions := make([]*models.Case, 0, len(stored))
+	for _, v := range stored {
+		versions = append(versions, v.Case)
+	}
+	return versions, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CitationBatchSaver is implemented by backends that can save many citations
+// in one round trip
+type CitationBatchSaver interface {
+	SaveCitations(ctx context.Context, citations []*models.Citation) error
+}
+
+// SaveCitations saves citations together where the backend implements
+// CitationBatchSaver, and one at a time otherwise
+func SaveCitations(ctx context.Context, store Storage, citations []*models.Citation) error {
+	if len(citations) == 0 {
+		return nil
+	}
+	if saver, ok := store.(CitationBatchSaver); ok {
+		return saver.SaveCitations(ctx, citations)
+	}
+
+	for _, c := range citations {
+		if err := store.SaveCitation(ctx, c); err != nil {
+			return fmt.Errorf("failed to save citation %q: %w", c.RawCitation, err)
+		}
+	}
+	return nil
+}
+
+// FindCaseByCitation returns the stored case a citation names, matched
+// exactly against case numbers (where neutral citations are kept) and then
+// docket numbers, or nil if no case matches
+func FindCaseByCitation(ctx context.Context, store Storage, citation string) (*models.Case, error) {
+	citation = strings.Join(strings.Fields(citation), " ")
+	if citation == "" {
+		return nil, nil
+	}
+
+	for _, filter := range []CaseFilter{
+		{CaseNumber: citation, Limit: 1},
+		{Docket: citation, Limit: 1},
+	} {
+		cases, err := store.ListCases(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find case by citation %q: %w", citation, err)
+		}
+		if len(cases) > 0 {
+			return cases[0], nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveCitations saves citations under a single lock
+func (ms *MemoryStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, c := range citations {
+		if c.ID == "" {
+			c.ID = c.CaseID + "-" + c.RawCitation
+		}
+		ms.citations[c.ID] = c
+	}
+	return nil
+}
+
+// SaveCitations inserts new citations in a single write; citations that
+// already have an ID are updated one at a time
+func (ms *MongoStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	var docs []interface{}
+	var inserted []*models.Citation
+	for _, c := range citations {
+		if c.ID != "" {
+			if err := ms.SaveCitation(ctx, c); err != nil {
+				return err
+			}
+			continue
+		}
+
+		oid := primitive.NewObjectID()
+		c.ID = oid.Hex()
+		docs = append(docs, mongoCitation{ObjectID: oid, Citation: *c})
+		inserted = append(inserted, c)
+	}
+	if len(docs) == 0 {
+		return nil
+	}
+
+	if _, err := ms.citations.InsertMany(ctx, docs); err != nil {
+		for _, c := range inserted {
+			c.ID = ""
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ConceptCaseLister is implemented by backends that can page through the cases
+// tagged with a concept in the database. filter narrows the cases as it does
+// for ListCases, including to a tenant's scope, and sets the page.
+type ConceptCaseLister interface {
+	ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error)
+}
+
+// CasesByConcept returns a page of the cases tagged with concept and matching
+// filter, ordered by ID, along with the number of such cases in total. A
+// filter limit of zero returns every case from its offset. Backends
+// implementing ConceptCaseLister page in the database; others fall back to
+// filtering the listed cases in memory.
+func CasesByConcept(ctx context.Context, store Storage, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	if lister, ok := store.(ConceptCaseLister); ok {
+		return lister.ListCasesByConcept(ctx, concept, filter)
+	}
+
+	limit, offset := filter.Limit, filter.Offset
+	filter.Concepts = []string{concept}
+	filter.Limit, filter.Offset = 0, 0
+	cases, err := store.ListCases(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cases for concept %s: %w", concept, err)
+	}
+
+	// Not every backend applies the concept filter, so check each case
+	tagged := make([]*models.Case, 0, len(cases))
+	for _, c := range cases {
+		if containsValue(c.LegalConcepts, concept) {
+			tagged = append(tagged, c)
+		}
+	}
+
+	return pageCasesByID(tagged, limit, offset), int64(len(tagged)), nil
+}
+
+// pageCasesByID orders cases by ID and returns the page at offset
+func pageCasesByID(cases []*models.Case, limit, offset int) []*models.Case {
+	sort.Slice(cases, func(i, j int) bool { return cases[i].ID < cases[j].ID })
+	return pageCases(cases, limit, offset)
+}
+
+// pageCases returns the page of cases at offset. A limit of zero returns
+// every case from offset.
+func pageCases(cases []*models.Case, limit, offset int) []*models.Case {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(cases) {
+		offset = len(cases)
+	}
+	end := len(cases)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	return cases[offset:end]
+}
+
+// caseGetter loads a case by ID
+type caseGetter interface {
+	GetCase(ctx context.Context, id string) (*models.Case, error)
+}
+
+// queryCaseIDs runs a query returning case IDs and loads each case
+func queryCaseIDs(ctx context.Context, db *sql.DB, getter caseGetter, query string, args ...interface{}) ([]*models.Case, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cases: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	cases := make([]*models.Case, 0, len(ids))
+	for _, id := range ids {
+		c, err := getter.GetCase(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+// ListCasesByConcept pages through the cases whose concept list contains concept
+func (ss *SQLiteStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	where, args := sqlCaseConditions(filter, "c", []interface{}{concept}, positionalPlaceholder)
+
+	var total int64
+	err := ss.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT c.id)
+		FROM cases c, json_each(c.legal_concepts) AS tag
+		WHERE tag.value = ? AND `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by concept: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	cases, err := queryCaseIDs(ctx, ss.db, ss, `
+		SELECT DISTINCT c.id
+		FROM cases c, json_each(c.legal_concepts) AS tag
+		WHERE tag.value = ? AND `+where+`
+		ORDER BY c.id
+		LIMIT ? OFFSET ?
+	`, append(args, limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByConcept pages through the cases whose concept list contains concept
+func (ps *PostgresStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	where, args := sqlCaseConditions(filter, "cases", []interface{}{concept}, postgresPlaceholder)
+	where = `legal_concepts @> jsonb_build_array($1::text) AND ` + where
+
+	var total int64
+	err := ps.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM cases WHERE `+where, args...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by concept: %w", err)
+	}
+
+	query, args := postgresPage(`
+		SELECT id FROM cases
+		WHERE `+where+`
+		ORDER BY id`, args, filter.Limit, filter.Offset)
+
+	cases, err := queryCaseIDs(ctx, ps.db, ps, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByConcept pages through the cases whose legal_concepts contain concept
+func (ms *MongoStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	query := mongoCaseConditions(filter)
+	query["legal_concepts"] = concept
+
+	total, err := ms.cases.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by concept: %w", err)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "id", Value: 1}}).SetSkip(int64(filter.Offset))
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := ms.cases.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cases by concept: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	cases := make([]*models.Case, 0)
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByConcept pages through the stored cases tagged with concept
+func (ms *MemoryStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	filter.Concepts = []string{concept}
+	tagged := make([]*models.Case, 0)
+	for _, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			tagged = append(tagged, c)
+		}
+	}
+
+	return pageCasesByID(tagged, filter.Limit, filter.Offset), int64(len(tagged)), nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// maxConnectRetryInterval caps the backoff between connection attempts
+const maxConnectRetryInterval = 30 * time.Second
+
+// ConnectLogger receives failed connection attempts (satisfied by observability.Logger)
+type ConnectLogger interface {
+	Warnf(format string, args ...interface{})
+}
+
+// ConnectRetry bounds retries of the initial connection to a database, so the
+// service can wait for a database that is still starting up
+type ConnectRetry struct {
+	// Attempts is the total number of tries; zero or one tries once
+	Attempts int
+	// Interval is the wait before the first retry, doubled after each one
+	Interval time.Duration
+	// Logger receives each failed attempt, and may be nil
+	Logger ConnectLogger
+}
+
+// DefaultConnectRetry returns the default retry policy, which tries once
+func DefaultConnectRetry() ConnectRetry {
+	return ConnectRetry{
+		Attempts: 1,
+		Interval: time.Second,
+	}
+}
+
+// connect calls ping until it succeeds, attempts run out or ctx is done, and
+// returns the last error
+func (r ConnectRetry) connect(ctx context.Context, name string, ping func(ctx context.Context) error) error {
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	wait := r.Interval
+
+	for attempt := 1; ; attempt++ {
+		err := ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			r.logf("%s connection attempt %d/%d failed, giving up: %v", name, attempt, attempts, err)
+			return err
+		}
+		r.logf("%s connection attempt %d/%d failed, retrying in %s: %v", name, attempt, attempts, wait, err)
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return err
+		}
+
+		wait *= 2
+		if wait > maxConnectRetryInterval {
+			wait = maxConnectRetryInterval
+		}
+	}
+}
+
+func (r ConnectRetry) logf(format string, args ...interface{}) {
+	if r.Logger != nil {
+		r.Logger.Warnf(format, args...)
+	}
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ContentHashLoader is implemented by backends that can read the content hash
+// stored with a case without loading the case
+type ContentHashLoader interface {
+	GetContentHash(ctx context.Context, id string) (string, error)
+}
+
+// StoredContentHash returns the content hash stored with a case, so that a
+// re-scraped case can be compared with it before it is written. A case that
+// isn't stored, or was saved without a hash, has an empty hash. Backends
+// implementing ContentHashLoader read only the hash; others fall back to
+// loading the whole case.
+func StoredContentHash(ctx context.Context, store Storage, id string) (string, error) {
+	if loader, ok := store.(ContentHashLoader); ok {
+		return loader.GetContentHash(ctx, id)
+	}
+	return loadedContentHash(ctx, store, id)
+}
+
+// loadedContentHash returns the content hash of the case store loads
+func loadedContentHash(ctx context.Context, store Storage, id string) (string, error) {
+	c, err := store.GetCase(ctx, id)
+	if err != nil || c == nil {
+		// Not stored, or unreadable; either way it is written again
+		return "", nil
+	}
+	return c.ContentHash, nil
+}
+
+// getSQLContentHash reads a case's content hash, empty if the case doesn't exist
+func getSQLContentHash(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) (string, error) {
+	query := `SELECT COALESCE(content_hash, '') FROM cases WHERE id = ` + placeholder(1)
+
+	var hash string
+	if err := db.QueryRowContext(ctx, query, id).Scan(&hash); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+// GetContentHash reads the content hash stored with a case
+func (ss *SQLiteStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	return getSQLContentHash(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// GetContentHash reads the content hash stored with a case
+func (ps *PostgresStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	return getSQLContentHash(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// GetContentHash returns the content hash stored with a case
+func (ms *MemoryStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if c, ok := ms.cases[id]; ok {
+		return c.ContentHash, nil
+	}
+	return "", nil
+}
+
+// GetContentHash reads the content hash stored with a case, projecting out
+// every other field
+func (ms *MongoStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	var doc struct {
+		ContentHash string `bson:"content_hash"`
+	}
+	opts := options.FindOne().SetProjection(bson.M{"content_hash": 1})
+	err := ms.cases.FindOne(ctx, bson.M{"id": id}, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return doc.ContentHash, nil
+}
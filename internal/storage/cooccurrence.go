@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ConceptPair counts the cases in which a related concept appears alongside a concept
+type ConceptPair struct {
+	Concept string `json:"concept"`
+	Related string `json:"related"`
+	Count   int    `json:"count"`
+}
+
+// ConceptCooccurrenceCounter is implemented by backends that can count concept
+// co-occurrence without loading the matching cases. Only the cases matching
+// filter are counted, as for ListCases, including a tenant's scope.
+type ConceptCooccurrenceCounter interface {
+	CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error)
+}
+
+// ConceptCooccurrence returns the concepts most often stored alongside concept
+// in the cases matching filter, by number of cases, ties broken by name.
+// Backends implementing ConceptCooccurrenceCounter count in the database;
+// others fall back to counting the cases tagged with concept in memory.
+func ConceptCooccurrence(ctx context.Context, store Storage, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	if counter, ok := store.(ConceptCooccurrenceCounter); ok {
+		return counter.CountConceptCooccurrence(ctx, concept, filter, limit)
+	}
+
+	filter.Concepts = []string{concept}
+	filter.Limit, filter.Offset = 0, 0
+	cases, err := store.ListCases(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cases for concept %s: %w", concept, err)
+	}
+
+	counts := make(map[string]int)
+	for _, c := range cases {
+		countCooccurring(counts, c.LegalConcepts, concept)
+	}
+
+	return rankConceptPairs(concept, counts, limit), nil
+}
+
+// countCooccurring adds one to the count of every other concept in concepts,
+// if concepts contains concept. Each case counts once per related concept.
+func countCooccurring(counts map[string]int, concepts []string, concept string) {
+	found := false
+	for _, c := range concepts {
+		if c == concept {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return
+	}
+
+	seen := make(map[string]bool, len(concepts))
+	for _, c := range concepts {
+		if c != concept && !seen[c] {
+			seen[c] = true
+			counts[c]++
+		}
+	}
+}
+
+// rankConceptPairs orders counts by count descending then name, keeping the top limit
+func rankConceptPairs(concept string, counts map[string]int, limit int) []ConceptPair {
+	pairs := make([]ConceptPair, 0, len(counts))
+	for related, count := range counts {
+		pairs = append(pairs, ConceptPair{Concept: concept, Related: related, Count: count})
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].Count != pairs[j].Count {
+			return pairs[i].Count > pairs[j].Count
+		}
+		return pairs[i].Related < pairs[j].Related
+	})
+
+	if limit > 0 && len(pairs) > limit {
+		pairs = pairs[:limit]
+	}
+
+	return pairs
+}
+
+// queryConceptPairs runs a co-occurrence query returning related concept and count columns
+func queryConceptPairs(ctx context.Context, db *sql.DB, concept string, query string, args ...interface{}) ([]ConceptPair, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count co-occurring concepts: %w", err)
+	}
+	defer rows.Close()
+
+	pairs := make([]ConceptPair, 0)
+	for rows.Next() {
+		pair := ConceptPair{Concept: concept}
+		if err := rows.Scan(&pair.Related, &pair.Count); err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return pairs, rows.Err()
+}
+
+// CountConceptCooccurrence counts related concepts by joining each case's
+// concept list against itself
+func (ss *SQLiteStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+
+	where, args := sqlCaseConditions(filter, "c", []interface{}{concept}, positionalPlaceholder)
+
+	return queryConceptPairs(ctx, ss.db, concept, `
+		SELECT related.value, COUNT(DISTINCT c.id) AS n
+		FROM cases c, json_each(c.legal_concepts) AS target, json_each(c.legal_concepts) AS related
+		WHERE target.value = ? AND related.value != target.value AND `+where+`
+		GROUP BY related.value
+		ORDER BY n DESC, related.value
+		LIMIT ?
+	`, append(args, limit)...)
+}
+
+// CountConceptCooccurrence counts related concepts over the cases whose
+// concept list contains concept
+func (ps *PostgresStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	where, args := sqlCaseConditions(filter, "c", []interface{}{concept}, postgresPlaceholder)
+
+	query := `
+		SELECT related.value, COUNT(DISTINCT c.id) AS n
+		FROM cases c, jsonb_array_elements_text(c.legal_concepts) AS related(value)
+		WHERE c.legal_concepts @> jsonb_build_array($1::text) AND related.value <> $1 AND ` + where + `
+		GROUP BY related.value
+		ORDER BY n DESC, related.value
+	`
+	query, args = postgresPage(query, args, limit, 0)
+	return queryConceptPairs(ctx, ps.db, concept, query, args...)
+}
+
+// CountConceptCooccurrence counts related concepts with an aggregation
+// pipeline over the cases tagged with concept
+func (ms *MongoStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	match := mongoCaseConditions(filter)
+	match["legal_concepts"] = concept
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		// Drop repeated concepts so each case counts once
+		bson.M{"$project": bson.M{"legal_concepts": bson.M{"$setUnion": bson.A{"$legal_concepts", bson.A{}}}}},
+		bson.M{"$unwind": "$legal_concepts"},
+		bson.M{"$match": bson.M{"legal_concepts": bson.M{"$ne": concept}}},
+		bson.M{"$group": bson.M{"_id": "$legal_concepts", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": limit})
+	}
+
+	cursor, err := ms.cases.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count co-occurring concepts: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Related string `bson:"_id"`
+		Count   int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	pairs := make([]ConceptPair, len(results))
+	for i, r := range results {
+		pairs[i] = ConceptPair{Concept: concept, Related: r.Related, Count: r.Count}
+	}
+
+	return pairs, nil
+}
+
+// CountConceptCooccurrence counts related concepts over the stored cases
+// matching filter
+func (ms *MemoryStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			countCooccurring(counts, c.LegalConcepts, concept)
+		}
+	}
+
+	return rankConceptPairs(concept, counts, limit), nil
+}
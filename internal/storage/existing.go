@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// existingIDsBatchSize bounds the IDs looked up in one query, keeping SQL
+// statements under SQLite's bound parameter limit
+const existingIDsBatchSize = 500
+
+// CaseExistenceChecker is implemented by backends that can tell which of many
+// case IDs are stored without loading the cases
+type CaseExistenceChecker interface {
+	ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error)
+}
+
+// StoredCaseIDs returns the set of ids that are stored as cases, so that a
+// scrape can skip cases it already has. Backends implementing
+// CaseExistenceChecker look the IDs up in batches; others fall back to
+// listing the cases by ID.
+func StoredCaseIDs(ctx context.Context, store Storage, ids []string) (map[string]bool, error) {
+	if len(ids) == 0 {
+		return map[string]bool{}, nil
+	}
+	if checker, ok := store.(CaseExistenceChecker); ok {
+		return checker.ExistingIDs(ctx, ids)
+	}
+	return listedCaseIDs(ctx, store, ids)
+}
+
+// listedCaseIDs returns the set of ids that store lists as cases
+func listedCaseIDs(ctx context.Context, store Storage, ids []string) (map[string]bool, error) {
+	if len(ids) == 0 {
+		return map[string]bool{}, nil
+	}
+	cases, err := store.ListCases(ctx, CaseFilter{IDs: ids, Limit: len(ids)})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(cases))
+	for _, c := range cases {
+		existing[c.ID] = true
+	}
+	return existing, nil
+}
+
+// existingSQLIDs returns which of ids are in the cases table
+func existingSQLIDs(ctx context.Context, db *sql.DB, ids []string, placeholder func(int) string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for start := 0; start < len(ids); start += existingIDsBatchSize {
+		end := start + existingIDsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		cond, args := sqlIn("id", ids[start:end], nil, placeholder)
+		rows, err := db.QueryContext(ctx, "SELECT id FROM cases WHERE "+cond, args...)
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			existing[id] = true
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return existing, nil
+}
+
+// ExistingIDs returns which of ids are stored as cases
+func (ss *SQLiteStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	return existingSQLIDs(ctx, ss.db, ids, positionalPlaceholder)
+}
+
+// ExistingIDs returns which of ids are stored as cases
+func (ps *PostgresStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	return existingSQLIDs(ctx, ps.db, ids, postgresPlaceholder)
+}
+
+// ExistingIDs returns which of ids are stored as cases
+func (ms *MemoryStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	existing := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := ms.cases[id]; ok {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// ExistingIDs returns which of ids are stored as cases, projecting out every
+// field but the ID
+func (ms *MongoStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for start := 0; start < len(ids); start += existingIDsBatchSize {
+		end := start + existingIDsBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		opts := options.Find().SetProjection(bson.M{"id": 1})
+		cursor, err := ms.cases.Find(ctx, bson.M{"id": bson.M{"$in": ids[start:end]}}, opts)
+		if err != nil {
+			return nil, err
+		}
+		var docs []struct {
+			ID string `bson:"id"`
+		}
+		if err := cursor.All(ctx, &docs); err != nil {
+			return nil, err
+		}
+		for _, doc := range docs {
+			existing[doc.ID] = true
+		}
+	}
+	return existing, nil
+}
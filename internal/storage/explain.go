@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PlanLogger receives query plans for slow queries (satisfied by observability.Logger)
+type PlanLogger interface {
+	Debugf(format string, args ...interface{})
+}
+
+// ExplainConfig configures query plan logging for slow queries
+type ExplainConfig struct {
+	Enabled   bool
+	Threshold time.Duration
+	Timeout   time.Duration // bounds each explain; defaults to defaultExplainTimeout
+	Logger    PlanLogger
+}
+
+// defaultExplainTimeout bounds an explain when ExplainConfig.Timeout is unset
+const defaultExplainTimeout = 5 * time.Second
+
+// SlowQueryExplainerSetter is implemented by backends that can log the plans
+// of their slow queries
+type SlowQueryExplainerSetter interface {
+	SetSlowQueryExplainer(explainer *SlowQueryExplainer)
+}
+
+// SlowQueryExplainer logs the query plan of storage queries exceeding a latency threshold
+type SlowQueryExplainer struct {
+	threshold time.Duration
+	timeout   time.Duration
+	logger    PlanLogger
+}
+
+// NewSlowQueryExplainer creates a new SlowQueryExplainer, or nil when disabled
+func NewSlowQueryExplainer(config ExplainConfig) *SlowQueryExplainer {
+	if !config.Enabled || config.Logger == nil {
+		return nil
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultExplainTimeout
+	}
+	return &SlowQueryExplainer{
+		threshold: config.Threshold,
+		timeout:   timeout,
+		logger:    config.Logger,
+	}
+}
+
+// Observe runs explain and logs the plan if the query started at start exceeded the threshold.
+// A nil explainer does nothing, so backends can call it unconditionally. The
+// explain runs after the query, when the caller's context may already be
+// done, so it gets a bounded context of its own.
+func (e *SlowQueryExplainer) Observe(ctx context.Context, name string, start time.Time, explain func(ctx context.Context) (string, error)) {
+	if e == nil {
+		return
+	}
+
+	elapsed := time.Since(start)
+	if elapsed < e.threshold {
+		return
+	}
+
+	explainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), e.timeout)
+	defer cancel()
+
+	plan, err := explain(explainCtx)
+	if err != nil {
+		e.logger.Debugf("slow query %s took %s; explain failed: %v", name, elapsed, err)
+		return
+	}
+
+	e.logger.Debugf("slow query %s took %s; plan:\n%s", name, elapsed, plan)
+}
+
+// explainSQL runs the query prefixed with an EXPLAIN statement and renders the
+// result rows. The prefix must only plan the query, not run it again as
+// EXPLAIN ANALYZE would.
+func explainSQL(ctx context.Context, db *sql.DB, prefix, query string, args []interface{}) (string, error) {
+	rows, err := db.QueryContext(ctx, prefix+" "+query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return "", err
+		}
+
+		parts := make([]string, len(values))
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				v = string(b)
+			}
+			parts[i] = fmt.Sprint(v)
+		}
+		lines = append(lines, strings.Join(parts, " | "))
+	}
+
+	return strings.Join(lines, "\n"), rows.Err()
+}
+
+// explainMongoFind runs the explain command for a find on the collection,
+// planning it without executing it
+func explainMongoFind(ctx context.Context, coll *mongo.Collection, filter interface{}, opts *options.FindOptions) (string, error) {
+	find := bson.D{
+		{Key: "find", Value: coll.Name()},
+		{Key: "filter", Value: filter},
+	}
+	if opts != nil {
+		if opts.Sort != nil {
+			find = append(find, bson.E{Key: "sort", Value: opts.Sort})
+		}
+		if opts.Projection != nil {
+			find = append(find, bson.E{Key: "projection", Value: opts.Projection})
+		}
+		if opts.Limit != nil {
+			find = append(find, bson.E{Key: "limit", Value: *opts.Limit})
+		}
+		if opts.Skip != nil {
+			find = append(find, bson.E{Key: "skip", Value: *opts.Skip})
+		}
+	}
+
+	cmd := bson.D{
+		{Key: "explain", Value: find},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}
+
+	var result bson.M
+	if err := coll.Database().RunCommand(ctx, cmd).Decode(&result); err != nil {
+		return "", err
+	}
+
+	plan, err := bson.MarshalExtJSON(bson.M{"queryPlanner": result["queryPlanner"]}, false, false)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plan), nil
+}
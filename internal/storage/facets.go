@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FacetAggregation holds facet value counts over every case matching a search
+type FacetAggregation struct {
+	Total  int64                     `json:"total"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// FacetAggregator is implemented by backends that can count facet values for a
+// search without loading the matching cases
+type FacetAggregator interface {
+	AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error)
+}
+
+// AggregateFacets counts facet values over every case matching query,
+// ignoring its pagination. Backends implementing FacetAggregator count in the
+// database; others fall back to counting the searched cases in memory.
+func AggregateFacets(ctx context.Context, store Storage, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	if aggregator, ok := store.(FacetAggregator); ok {
+		return aggregator.AggregateFacets(ctx, query, fields)
+	}
+
+	query.Limit, query.Offset = 0, 0
+	query.Filters.Limit, query.Filters.Offset = 0, 0
+	cases, err := store.SearchCases(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return CountFacets(cases, fields), nil
+}
+
+// CountFacets counts the values of each facet field over cases. Unknown
+// fields are skipped, and cases without a decision date have no year.
+func CountFacets(cases []*models.Case, fields []string) *FacetAggregation {
+	agg := &FacetAggregation{Total: int64(len(cases)), Counts: make(map[string]map[string]int)}
+	for _, field := range fields {
+		if _, ok := mongoFacetStages[field]; !ok {
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, c := range cases {
+			for _, value := range facetValues(c, field) {
+				counts[value]++
+			}
+		}
+		agg.Counts[field] = counts
+	}
+	return agg
+}
+
+// facetValues returns the values a case counts towards in a facet field
+func facetValues(c *models.Case, field string) []string {
+	switch field {
+	case "jurisdiction":
+		return []string{c.Jurisdiction}
+	case "court":
+		return []string{c.Court}
+	case "court_level":
+		return []string{strconv.Itoa(int(c.CourtLevel))}
+	case "year":
+		if c.DecisionDate != nil {
+			return []string{strconv.Itoa(c.DecisionDate.Year())}
+		}
+	case "concepts":
+		return c.LegalConcepts
+	}
+	return nil
+}
+
+// facetExpressions maps facet fields to SQL grouping expressions per dialect
+var facetExpressions = map[string]map[string]string{
+	"sqlite": {
+		"jurisdiction": "c.jurisdiction",
+		"court":        "c.court",
+		"court_level":  "CAST(c.court_level AS TEXT)",
+		"year":         "strftime('%Y', c.decision_date)",
+		"concepts":     "concept.value",
+	},
+	"postgres": {
+		"jurisdiction": "c.jurisdiction",
+		"court":        "c.court",
+		"court_level":  "c.court_level::text",
+		"year":         "EXTRACT(YEAR FROM c.decision_date)::int::text",
+		"concepts":     "concept.value",
+	},
+}
+
+// facetJoins adds the join needed to group on array elements
+var facetJoins = map[string]map[string]string{
+	"sqlite": {
+		"concepts": ", json_each(c.legal_concepts) AS concept",
+	},
+	"postgres": {
+		"concepts": ", jsonb_array_elements_text(c.legal_concepts) AS concept(value)",
+	},
+}
+
+// mongoFacetStages maps facet fields to the pipeline stages that group on them
+var mongoFacetStages = map[string]bson.A{
+	"jurisdiction": {
+		bson.M{"$group": bson.M{"_id": "$jurisdiction", "count": bson.M{"$sum": 1}}},
+	},
+	"court": {
+		bson.M{"$group": bson.M{"_id": "$court", "count": bson.M{"$sum": 1}}},
+	},
+	"court_level": {
+		bson.M{"$group": bson.M{"_id": bson.M{"$toString": "$court_level"}, "count": bson.M{"$sum": 1}}},
+	},
+	"year": {
+		bson.M{"$match": bson.M{"decision_date": bson.M{"$type": "date"}}},
+		bson.M{"$group": bson.M{"_id": bson.M{"$toString": bson.M{"$year": "$decision_date"}}, "count": bson.M{"$sum": 1}}},
+	},
+	"concepts": {
+		bson.M{"$unwind": "$legal_concepts"},
+		bson.M{"$group": bson.M{"_id": "$legal_concepts", "count": bson.M{"$sum": 1}}},
+	},
+}
+
+// aggregateSQLFacets runs one COUNT query and one GROUP BY query per facet field.
+// from and where select the matching cases aliased as c.
+func aggregateSQLFacets(ctx context.Context, db *sql.DB, dialect, from, where string, args []interface{}, fields []string) (*FacetAggregation, error) {
+	agg := &FacetAggregation{Counts: make(map[string]map[string]int)}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", from, where)
+	if err := db.QueryRowContext(ctx, countQuery, args...).Scan(&agg.Total); err != nil {
+		return nil, fmt.Errorf("failed to count matching cases: %w", err)
+	}
+
+	for _, field := range fields {
+		expr, ok := facetExpressions[dialect][field]
+		if !ok {
+			continue
+		}
+
+		query := fmt.Sprintf(
+			"SELECT %s AS value, COUNT(*) FROM %s%s WHERE %s AND %s IS NOT NULL GROUP BY 1",
+			expr, from, facetJoins[dialect][field], where, expr,
+		)
+
+		counts, err := scanFacetCounts(ctx, db, query, args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to aggregate %s facet: %w", field, err)
+		}
+		agg.Counts[field] = counts
+	}
+
+	return agg, nil
+}
+
+func scanFacetCounts(ctx context.Context, db *sql.DB, query string, args []interface{}) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var value string
+		var count int
+		if err := rows.Scan(&value, &count); err != nil {
+			return nil, err
+		}
+		counts[value] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// sqliteSearchScope returns the FROM and WHERE clauses selecting the cases
+// matching a search, aliased as c. SearchCases and the aggregations share it,
+// so counts always match the results.
+func sqliteSearchScope(query SearchQuery) (string, string, []interface{}) {
+	from := "cases c"
+	where := "1=1"
+	var args []interface{}
+
+	if query.Query != "" {
+		from = "cases c JOIN cases_fts fts ON c.id = fts.id"
+		where = "cases_fts MATCH ?"
+		args = append(args, query.Query)
+	}
+
+	cond, args := sqlCaseConditions(query.Filters, "c", args, positionalPlaceholder)
+	return from, where + " AND " + cond, args
+}
+
+// AggregateFacets counts facet values over all cases matching the search
+func (ss *SQLiteStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	from, where, args := sqliteSearchScope(query)
+	return aggregateSQLFacets(ctx, ss.db, "sqlite", from, where, args, fields)
+}
+
+// postgresSearchScope returns the WHERE clause selecting the cases matching a
+// search from table, which names or aliases the cases table. SearchCases and
+// the aggregations share it, so counts always match the results.
+func postgresSearchScope(query SearchQuery, table string) (string, []interface{}) {
+	where := fmt.Sprintf("(%[1]s.case_name ILIKE $1 OR %[1]s.case_number ILIKE $1 OR %[2]s ILIKE $1)",
+		table, sqlFullText(table+".id", true))
+	args := []interface{}{"%" + query.Query + "%"}
+
+	cond, args := sqlCaseConditions(query.Filters, table, args, postgresPlaceholder)
+	return where + " AND " + cond, args
+}
+
+// AggregateFacets counts facet values over all cases matching the search
+func (ps *PostgresStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	where, args := postgresSearchScope(query, "c")
+	return aggregateSQLFacets(ctx, ps.db, "postgres", "cases c", where, args, fields)
+}
+
+// mongoSearchMatch returns the filter selecting the cases matching a search,
+// given the IDs of those whose full text matches. SearchCases and the
+// aggregations share it, so counts always match the results.
+func mongoSearchMatch(query SearchQuery, fullTextIDs []string) bson.M {
+	match := mongoCaseConditions(query.Filters)
+	if query.Query == "" {
+		return match
+	}
+
+	// The filter may already hold alternatives of its own under $or
+	text := bson.M{"$or": mongoTextSearch(query.Query, fullTextIDs)}
+	if _, ok := match["$or"]; ok {
+		return bson.M{"$and": []bson.M{match, text}}
+	}
+	match["$or"] = text["$or"]
+	return match
+}
+
+// searchMatch returns the $match filter selecting the cases matching a
+// search, first looking up those whose full text matches
+func (ms *MongoStorage) searchMatch(ctx context.Context, query SearchQuery) (bson.M, error) {
+	var fullTextIDs []string
+	if query.Query != "" {
+		var err error
+		if fullTextIDs, err = ms.fullTextMatches(ctx, query.Query); err != nil {
+			return nil, err
+		}
+	}
+	return mongoSearchMatch(query, fullTextIDs), nil
+}
+
+// AggregateFacets counts facet values over all cases matching the search in a
+// single $facet aggregation
+func (ms *MongoStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	branches := bson.M{
+		"_total": bson.A{bson.M{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": 1}}}},
+	}
+	for _, field := range fields {
+		if stages, ok := mongoFacetStages[field]; ok {
+			branches[field] = stages
+		}
+	}
+
+	match, err := ms.searchMatch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$facet": branches},
+	}
+
+	cursor, err := ms.cases.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate facets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []map[string][]struct {
+		Value interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode facets: %w", err)
+	}
+
+	agg := &FacetAggregation{Counts: make(map[string]map[string]int)}
+	if len(results) == 0 {
+		return agg, nil
+	}
+
+	for field, buckets := range results[0] {
+		if field == "_total" {
+			for _, b := range buckets {
+				agg.Total = int64(b.Count)
+			}
+			continue
+		}
+
+		counts := make(map[string]int, len(buckets))
+		for _, b := range buckets {
+			if b.Value == nil {
+				continue
+			}
+			counts[fmt.Sprint(b.Value)] = b.Count
+		}
+		agg.Counts[field] = counts
+	}
+
+	return agg, nil
+}
@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// JurisdictionValues returns every jurisdiction the filter accepts, combining
+// the single Jurisdiction field with Jurisdictions
+func (f CaseFilter) JurisdictionValues() []string {
+	return mergeFilterValues(f.Jurisdiction, f.Jurisdictions)
+}
+
+// CourtValues returns every court the filter accepts, combining the single
+// Court field with Courts
+func (f CaseFilter) CourtValues() []string {
+	return mergeFilterValues(f.Court, f.Courts)
+}
+
+// CourtIDValues returns every normalized court identifier the filter accepts,
+// combining the single CourtID field with CourtIDs. Court names and
+// identifiers are alternatives: a case matches if its court is one of
+// CourtValues or its identifier one of these, so a list of courts can be
+// matched by identifier where it resolves and by name where it doesn't.
+func (f CaseFilter) CourtIDValues() []string {
+	return mergeFilterValues(f.CourtID, f.CourtIDs)
+}
+
+// matchesCourt reports whether a case with the given court and court
+// identifier satisfies the filter's court conditions
+func (f CaseFilter) matchesCourt(court, courtID string) bool {
+	courts, courtIDs := f.CourtValues(), f.CourtIDValues()
+	if len(courts) == 0 && len(courtIDs) == 0 {
+		return true
+	}
+	return containsValue(courts, court) || containsValue(courtIDs, courtID)
+}
+
+// HasConditions reports whether the filter restricts which cases match.
+// Pagination and ordering are not conditions.
+func (f CaseFilter) HasConditions() bool {
+	return len(f.IDs) > 0 ||
+		f.CaseNumber != "" ||
+		f.Docket != "" ||
+		len(f.JurisdictionValues()) > 0 ||
+		len(f.CourtValues()) > 0 ||
+		len(f.CourtIDValues()) > 0 ||
+		f.CourtLevel != nil ||
+		f.StartDate != nil ||
+		f.EndDate != nil ||
+		f.Status != "" ||
+		len(f.Judges) > 0 ||
+		len(f.Concepts) > 0 ||
+		f.MinQuality > 0 ||
+		f.MinFullTextLength > 0 ||
+		f.ExtractionVersionBelow > 0 ||
+		f.StalerThan > 0 ||
+		f.UpdatedBefore != nil ||
+		f.ScrapedAfter != nil
+}
+
+func mergeFilterValues(single string, multi []string) []string {
+	values := make([]string, 0, len(multi)+1)
+	seen := make(map[string]bool)
+
+	for _, v := range append([]string{single}, multi...) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
+// containsValue reports whether value is one of values
+func containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoExtractionVersionBelow matches cases extracted by a version older than
+// version, including those with no version recorded
+func mongoExtractionVersionBelow(version int) bson.M {
+	return bson.M{"$not": bson.M{"$gte": version}}
+}
+
+// staleCutoff returns the last update time before which a case is staler
+// than maxAge
+func staleCutoff(maxAge time.Duration) time.Time {
+	return time.Now().Add(-maxAge)
+}
+
+// updatedCutoff returns the last update time before which a case matches
+// both StalerThan and UpdatedBefore, or nil if neither is set
+func (f CaseFilter) updatedCutoff() *time.Time {
+	var cutoff *time.Time
+	if f.StalerThan > 0 {
+		stale := staleCutoff(f.StalerThan)
+		cutoff = &stale
+	}
+	if f.UpdatedBefore != nil && (cutoff == nil || f.UpdatedBefore.Before(*cutoff)) {
+		cutoff = f.UpdatedBefore
+	}
+	return cutoff
+}
+
+// mongoMinFullTextLength returns a full_text_length condition matching cases
+// whose full text has at least length characters
+func mongoMinFullTextLength(length int) bson.M {
+	return bson.M{"$gte": length}
+}
+
+// sqlIn appends values to args and returns a "column IN (...)" condition.
+// placeholder renders the bind parameter for a 1-based argument position.
+func sqlIn(column string, values []string, args []interface{}, placeholder func(int) string) (string, []interface{}) {
+	marks := make([]string, len(values))
+	for i, v := range values {
+		args = append(args, v)
+		marks[i] = placeholder(len(args))
+	}
+
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(marks, ", ")), args
+}
+
+// sqlCaseConditions renders the filter as a WHERE clause over table, which
+// names or aliases the cases table. Its bind parameters are appended to args,
+// so the clause can follow conditions of the caller's own. Every SQL query
+// selecting cases by a CaseFilter builds its conditions here, so that lists,
+// searches, aggregations and deletes agree on what matches.
+func sqlCaseConditions(filter CaseFilter, table string, args []interface{}, placeholder func(int) string) (string, []interface{}) {
+	conds := []string{"1=1"}
+	column := func(name string) string { return table + "." + name }
+
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, placeholder(len(args))))
+	}
+	addIn := func(name string, values []string) {
+		if len(values) > 0 {
+			var cond string
+			cond, args = sqlIn(column(name), values, args, placeholder)
+			conds = append(conds, cond)
+		}
+	}
+
+	addIn("id", filter.IDs)
+	if filter.CaseNumber != "" {
+		add(column("case_number")+" = %s", filter.CaseNumber)
+	}
+	if filter.Docket != "" {
+		add(column("docket")+" = %s", filter.Docket)
+	}
+	addIn("jurisdiction", filter.JurisdictionValues())
+	if len(filter.JurisdictionScope) > 0 {
+		var cond string
+		cond, args = sqlIn("LOWER("+column("jurisdiction")+")", sqlJurisdictionScope(filter.JurisdictionScope), args, placeholder)
+		conds = append(conds, cond)
+	}
+	if courts, courtIDs := filter.CourtValues(), filter.CourtIDValues(); len(courts) > 0 || len(courtIDs) > 0 {
+		var alternatives []string
+		var cond string
+		if len(courts) > 0 {
+			cond, args = sqlIn(column("court"), courts, args, placeholder)
+			alternatives = append(alternatives, cond)
+		}
+		if len(courtIDs) > 0 {
+			cond, args = sqlIn(column("court_id"), courtIDs, args, placeholder)
+			alternatives = append(alternatives, cond)
+		}
+		conds = append(conds, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	if filter.CourtLevel != nil {
+		add(column("court_level")+" = %s", *filter.CourtLevel)
+	}
+	if filter.Status != "" {
+		add(column("status")+" = %s", filter.Status)
+	}
+	if filter.StartDate != nil {
+		add(column("decision_date")+" >= %s", filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		add(column("decision_date")+" <= %s", filter.EndDate)
+	}
+	if filter.MinFullTextLength > 0 {
+		add(sqlFullTextLength(column("id"))+" >= %s", filter.MinFullTextLength)
+	}
+	if filter.ExtractionVersionBelow > 0 {
+		add("COALESCE("+column("extraction_version")+", 0) < %s", filter.ExtractionVersionBelow)
+	}
+	if cutoff := filter.updatedCutoff(); cutoff != nil {
+		add(column("last_updated")+" < %s", *cutoff)
+	}
+	if filter.ScrapedAfter != nil {
+		add(column("scraped_at")+" > %s", *filter.ScrapedAfter)
+	}
+	if filter.TenantScope != nil {
+		add("COALESCE("+column("tenant_id")+", '') IN ('', %s)", *filter.TenantScope)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// sqlJudgeConditions renders a judge filter's court, jurisdiction and tenant
+// scope as a WHERE clause over the judges table, for the judge queries that
+// select by them alone; see sqlCaseConditions
+func sqlJudgeConditions(filter JudgeFilter, args []interface{}, placeholder func(int) string) (string, []interface{}) {
+	conds := []string{"1=1"}
+	add := func(cond string, value interface{}) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(cond, placeholder(len(args))))
+	}
+
+	if filter.Court != "" {
+		add("court = %s", filter.Court)
+	}
+	if filter.Jurisdiction != "" {
+		add("jurisdiction = %s", filter.Jurisdiction)
+	}
+	if filter.TenantScope != nil {
+		add("COALESCE(tenant_id, '') IN ('', %s)", *filter.TenantScope)
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// mongoJudgeConditions renders a judge filter's court, jurisdiction and
+// tenant scope as a query over the judges collection; see sqlJudgeConditions
+func mongoJudgeConditions(filter JudgeFilter) bson.M {
+	query := bson.M{}
+	if filter.Court != "" {
+		query["court"] = filter.Court
+	}
+	if filter.Jurisdiction != "" {
+		query["jurisdiction"] = filter.Jurisdiction
+	}
+	if filter.TenantScope != nil {
+		query["tenant_id"] = mongoTenantScope(*filter.TenantScope)
+	}
+	return query
+}
+
+// matchesJudge reports whether a judge satisfies the filter's court,
+// jurisdiction and tenant scope; see sqlJudgeConditions
+func (f JudgeFilter) matchesJudge(j *models.Judge) bool {
+	return (f.Court == "" || j.Court == f.Court) &&
+		(f.Jurisdiction == "" || j.Jurisdiction == f.Jurisdiction) &&
+		visibleToTenant(j.TenantID, f.TenantScope)
+}
+
+// mongoCaseConditions renders the filter as a query over the cases
+// collection; see sqlCaseConditions
+func mongoCaseConditions(filter CaseFilter) bson.M {
+	query := bson.M{}
+	if len(filter.IDs) > 0 {
+		query["id"] = bson.M{"$in": filter.IDs}
+	}
+	if jurisdictions := filter.JurisdictionValues(); len(jurisdictions) > 0 {
+		scoped := make([]string, 0, len(jurisdictions))
+		for _, j := range jurisdictions {
+			if filter.inJurisdictionScope(j) {
+				scoped = append(scoped, j)
+			}
+		}
+		query["jurisdiction"] = bson.M{"$in": scoped}
+	} else if len(filter.JurisdictionScope) > 0 {
+		query["jurisdiction"] = mongoJurisdictionScope(filter.JurisdictionScope)
+	}
+	if courts := mongoCourtConditions(filter); len(courts) == 1 {
+		for field, cond := range courts[0] {
+			query[field] = cond
+		}
+	} else if len(courts) > 1 {
+		query["$or"] = courts
+	}
+	if filter.CaseNumber != "" {
+		query["case_number"] = filter.CaseNumber
+	}
+	if filter.Docket != "" {
+		query["docket"] = filter.Docket
+	}
+	if filter.CourtLevel != nil {
+		query["court_level"] = *filter.CourtLevel
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.StartDate != nil || filter.EndDate != nil {
+		dateQuery := bson.M{}
+		if filter.StartDate != nil {
+			dateQuery["$gte"] = filter.StartDate
+		}
+		if filter.EndDate != nil {
+			dateQuery["$lte"] = filter.EndDate
+		}
+		query["decision_date"] = dateQuery
+	}
+	if filter.MinFullTextLength > 0 {
+		query["full_text_length"] = mongoMinFullTextLength(filter.MinFullTextLength)
+	}
+	if filter.ExtractionVersionBelow > 0 {
+		query["extraction_version"] = mongoExtractionVersionBelow(filter.ExtractionVersionBelow)
+	}
+	if cutoff := filter.updatedCutoff(); cutoff != nil {
+		query["last_updated"] = bson.M{"$lt": *cutoff}
+	}
+	if filter.ScrapedAfter != nil {
+		query["scraped_at"] = bson.M{"$gt": *filter.ScrapedAfter}
+	}
+	if filter.TenantScope != nil {
+		query["tenant_id"] = mongoTenantScope(*filter.TenantScope)
+	}
+	return query
+}
+
+// mongoCourtConditions returns the alternative conditions matching the
+// filter's courts by name or by identifier; see CourtIDValues
+func mongoCourtConditions(filter CaseFilter) []bson.M {
+	var conds []bson.M
+	if courts := filter.CourtValues(); len(courts) > 0 {
+		conds = append(conds, bson.M{"court": bson.M{"$in": courts}})
+	}
+	if courtIDs := filter.CourtIDValues(); len(courtIDs) > 0 {
+		conds = append(conds, bson.M{"court_id": bson.M{"$in": courtIDs}})
+	}
+	return conds
+}
+
+// Placeholder styles for sqlIn
+var (
+	positionalPlaceholder = func(int) string { return "?" }
+	postgresPlaceholder   = func(n int) string { return fmt.Sprintf("$%d", n) }
+)
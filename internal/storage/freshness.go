@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// freshnessPageSize is how many cases CaseFreshness lists at a time
+const freshnessPageSize = 500
+
+// SourceFreshness summarizes how recently one source's stored cases were
+// updated
+type SourceFreshness struct {
+	Source       string        `json:"source"`
+	Cases        int           `json:"cases"`
+	Stale        int           `json:"stale"` // cases not updated within the max age
+	OldestUpdate time.Time     `json:"oldest_update"`
+	OldestAge    time.Duration `json:"oldest_age"` // how long ago the oldest update was
+}
+
+// CaseFreshness reports, by source database, how many stored cases there are,
+// how many were not updated within maxAge of now, and the oldest update
+func CaseFreshness(ctx context.Context, store Storage, maxAge time.Duration, now time.Time) ([]SourceFreshness, error) {
+	sources := make(map[string]*SourceFreshness)
+
+	for offset := 0; ; offset += freshnessPageSize {
+		page, err := store.ListCases(ctx, CaseFilter{Limit: freshnessPageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range page {
+			f, ok := sources[c.SourceDatabase]
+			if !ok {
+				f = &SourceFreshness{Source: c.SourceDatabase, OldestUpdate: c.LastUpdated}
+				sources[c.SourceDatabase] = f
+			}
+			f.Cases++
+			if c.IsStale(now, maxAge) {
+				f.Stale++
+			}
+			if c.LastUpdated.Before(f.OldestUpdate) {
+				f.OldestUpdate = c.LastUpdated
+			}
+		}
+
+		if len(page) < freshnessPageSize {
+			break
+		}
+	}
+
+	report := make([]SourceFreshness, 0, len(sources))
+	for _, f := range sources {
+		f.OldestAge = now.Sub(f.OldestUpdate)
+		report = append(report, *f)
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Source < report[j].Source })
+	return report, nil
+}
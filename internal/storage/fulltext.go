@@ -0,0 +1,263 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FullTextLoader is implemented by backends that keep full text apart from
+// case metadata and can load it on its own
+type FullTextLoader interface {
+	GetCaseFullText(ctx context.Context, id string) (string, error)
+}
+
+// CaseFullText returns the full text of a stored case. Listed and searched
+// cases carry no full text unless CaseFilter.IncludeFullText is set; this
+// loads it for one case. Backends implementing FullTextLoader read only the
+// text; others fall back to loading the whole case.
+func CaseFullText(ctx context.Context, store Storage, id string) (string, error) {
+	if loader, ok := store.(FullTextLoader); ok {
+		return loader.GetCaseFullText(ctx, id)
+	}
+	return loadedFullText(ctx, store, id)
+}
+
+// loadedFullText returns the full text of the case store loads
+func loadedFullText(ctx context.Context, store Storage, id string) (string, error) {
+	c, err := store.GetCase(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return c.FullText, nil
+}
+
+// withoutFullText returns a copy of c with its full text left out
+func withoutFullText(c *models.Case) *models.Case {
+	stripped := *c
+	stripped.FullText = ""
+	return &stripped
+}
+
+// sqlFullText selects the full text of the case whose ID is the column
+// caseID from case_full_text, or an empty string without reading the table
+// when include is false
+func sqlFullText(caseID string, include bool) string {
+	if !include {
+		return "''"
+	}
+	return "COALESCE((SELECT full_text FROM case_full_text WHERE case_id = " + caseID + "), '')"
+}
+
+// sqlFullTextLength is the length in characters of the full text of the case
+// whose ID is the column caseID
+func sqlFullTextLength(caseID string) string {
+	return "LENGTH(" + sqlFullText(caseID, true) + ")"
+}
+
+// saveSQLFullText writes a case's full text to case_full_text, replacing any
+// text stored before. The statement suits SQLite and Postgres alike.
+func saveSQLFullText(ctx context.Context, db sqlExecer, c *models.Case, placeholder func(int) string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO case_full_text (case_id, full_text) VALUES (%s, %s)
+		ON CONFLICT(case_id) DO UPDATE SET full_text = excluded.full_text
+	`, placeholder(1), placeholder(2))
+
+	if _, err := db.ExecContext(ctx, query, c.ID, c.FullText); err != nil {
+		return fmt.Errorf("failed to save full text of case %s: %w", c.ID, err)
+	}
+	return nil
+}
+
+// getSQLFullText reads a case's full text, failing if the case doesn't exist
+func getSQLFullText(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) (string, error) {
+	query := `SELECT ` + sqlFullText("c.id", true) + ` FROM cases c WHERE c.id = ` + placeholder(1)
+
+	var fullText string
+	if err := db.QueryRowContext(ctx, query, id).Scan(&fullText); err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("case not found: %s", id)
+		}
+		return "", err
+	}
+	return fullText, nil
+}
+
+// GetCaseFullText loads the full text of a case
+func (ss *SQLiteStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	return getSQLFullText(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// GetCaseFullText loads the full text of a case
+func (ps *PostgresStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	return getSQLFullText(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// GetCaseFullText returns the full text of a stored case
+func (ms *MemoryStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	c, err := ms.GetCase(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return c.FullText, nil
+}
+
+// mongoFullText is a case's full text, kept in the case_full_text collection
+type mongoFullText struct {
+	CaseID   string `bson:"case_id"`
+	FullText string `bson:"full_text"`
+}
+
+// mongoCase is the stored form of a case: its metadata, and the length of
+// the full text kept apart so that it can be filtered on
+type mongoCase struct {
+	models.Case    `bson:",inline"`
+	FullTextLength int `bson:"full_text_length"`
+}
+
+// newMongoCase returns the document stored for c, without its full text
+func newMongoCase(c *models.Case) *mongoCase {
+	return &mongoCase{
+		Case:           *withoutFullText(c),
+		FullTextLength: len([]rune(c.FullText)),
+	}
+}
+
+// fullTextWrite upserts a case's full text in a bulk write
+func fullTextWrite(c *models.Case) mongo.WriteModel {
+	return mongo.NewUpdateOneModel().
+		SetFilter(bson.M{"case_id": c.ID}).
+		SetUpdate(bson.M{"$set": mongoFullText{CaseID: c.ID, FullText: c.FullText}}).
+		SetUpsert(true)
+}
+
+// saveFullTexts writes the full text of each case to the full text collection
+func (ms *MongoStorage) saveFullTexts(ctx context.Context, cases ...*models.Case) error {
+	writes := make([]mongo.WriteModel, 0, len(cases))
+	for _, c := range cases {
+		writes = append(writes, fullTextWrite(c))
+	}
+
+	if _, err := ms.fullTexts.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return fmt.Errorf("failed to save full text: %w", err)
+	}
+	return nil
+}
+
+// GetCaseFullText loads the full text of a case
+func (ms *MongoStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	var doc mongoFullText
+	err := ms.fullTexts.FindOne(ctx, bson.M{"case_id": id}).Decode(&doc)
+	if err == nil {
+		return doc.FullText, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return "", err
+	}
+
+	// A case saved with no full text has none stored
+	if _, err := ms.GetCase(ctx, id); err != nil {
+		return "", err
+	}
+	return "", nil
+}
+
+// hydrateFullTexts loads the full text of each case in one query
+func (ms *MongoStorage) hydrateFullTexts(ctx context.Context, cases []*models.Case) error {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.Case, len(cases))
+	ids := make([]string, 0, len(cases))
+	for _, c := range cases {
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+
+	cursor, err := ms.fullTexts.Find(ctx, bson.M{"case_id": bson.M{"$in": ids}})
+	if err != nil {
+		return fmt.Errorf("failed to load full text: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []mongoFullText
+	if err := cursor.All(ctx, &docs); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if c, ok := byID[doc.CaseID]; ok {
+			c.FullText = doc.FullText
+		}
+	}
+	return nil
+}
+
+// fullTextMatches returns the IDs of cases whose full text matches a text
+// search, using the full text collection's own text index
+func (ms *MongoStorage) fullTextMatches(ctx context.Context, text string) ([]string, error) {
+	ids, err := ms.fullTexts.Distinct(ctx, "case_id", bson.M{"$text": bson.M{"$search": text}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search full text: %w", err)
+	}
+
+	matches := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if s, ok := id.(string); ok {
+			matches = append(matches, s)
+		}
+	}
+	return matches, nil
+}
+
+// mongoTextSearch matches cases whose name or summary match a text search,
+// through the case text index, or whose full text does, by ID
+func mongoTextSearch(text string, fullTextIDs []string) bson.A {
+	return bson.A{
+		bson.M{"$text": bson.M{"$search": text}},
+		bson.M{"id": bson.M{"$in": fullTextIDs}},
+	}
+}
+
+// MigrateFullText moves full text still stored inline on case documents into
+// the case_full_text collection, recording its length on each case. It
+// returns the number of cases moved and is safe to run again.
+func (ms *MongoStorage) MigrateFullText(ctx context.Context) (int64, error) {
+	cursor, err := ms.cases.Find(ctx, bson.M{"full_text": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"id": 1, "full_text": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to find inline full text: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var moved int64
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       string `bson:"id"`
+			FullText string `bson:"full_text"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return moved, err
+		}
+
+		c := &models.Case{ID: doc.ID, FullText: doc.FullText}
+		if err := ms.saveFullTexts(ctx, c); err != nil {
+			return moved, err
+		}
+		update := bson.M{
+			"$set":   bson.M{"full_text_length": len([]rune(doc.FullText))},
+			"$unset": bson.M{"full_text": ""},
+		}
+		if _, err := ms.cases.UpdateOne(ctx, bson.M{"id": doc.ID}, update); err != nil {
+			return moved, fmt.Errorf("failed to move full text of case %s: %w", doc.ID, err)
+		}
+		moved++
+	}
+
+	return moved, cursor.Err()
+}
@@ -9,11 +9,14 @@ import (
 
 // Storage defines the interface for data storage implementations
 type Storage interface {
-	// Case operations
+	// Case operations. SaveCase is an upsert, so retrying it is safe;
+	// CreateCase fails with errors.ErrAlreadyExists if the ID is taken.
 	SaveCase(ctx context.Context, c *models.Case) error
+	CreateCase(ctx context.Context, c *models.Case) error
 	GetCase(ctx context.Context, id string) (*models.Case, error)
 	UpdateCase(ctx context.Context, c *models.Case) error
 	DeleteCase(ctx context.Context, id string) error
+	DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error)
 	ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error)
 	CountCases(ctx context.Context, filter CaseFilter) (int64, error)
 
@@ -31,6 +34,14 @@ type Storage interface {
 	// Search operations
 	SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error)
 
+	// Autocomplete operations, matching a case-insensitive prefix among the
+	// cases or judges a filter's conditions select, so that scoped callers
+	// are answered by the prefix query itself. The filters' pagination is
+	// not used.
+	SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error)
+	SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error)
+	SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error)
+
 	// Transaction operations (optional, nil if not supported)
 	BeginTx(ctx context.Context) (Transaction, error)
 
@@ -56,8 +67,14 @@ type Transaction interface {
 // CaseFilter represents filters for case queries
 type CaseFilter struct {
 	IDs          []string               `json:"ids,omitempty"`
+	CaseNumber   string                 `json:"case_number,omitempty"` // exact case number, e.g. a neutral citation
+	Docket       string                 `json:"docket,omitempty"` // exact docket number
 	Jurisdiction string                 `json:"jurisdiction,omitempty"`
 	Court        string                 `json:"court,omitempty"`
+	Jurisdictions []string              `json:"jurisdictions,omitempty"` // any of, together with Jurisdiction
+	Courts       []string               `json:"courts,omitempty"` // any of, together with Court
+	CourtID      string                 `json:"court_id,omitempty"` // normalized court abbreviation, e.g. UKSC
+	CourtIDs     []string               `json:"court_ids,omitempty"` // any of, together with CourtID; see CourtIDValues
 	CourtLevel   *models.CourtLevel     `json:"court_level,omitempty"`
 	StartDate    *time.Time             `json:"start_date,omitempty"`
 	EndDate      *time.Time             `json:"end_date,omitempty"`
@@ -65,10 +82,23 @@ type CaseFilter struct {
 	Judges       []string               `json:"judges,omitempty"`
 	Concepts     []string               `json:"concepts,omitempty"`
 	MinQuality   float64                `json:"min_quality,omitempty"`
+	MinFullTextLength int                `json:"min_full_text_length,omitempty"` // excludes stub cases with less full text, in characters
+	ExtractionVersionBelow int           `json:"extraction_version_below,omitempty"` // only cases extracted by an older version, or none recorded
+	StalerThan   time.Duration          `json:"staler_than,omitempty"` // only cases not updated within this long
+	UpdatedBefore          *time.Time         `json:"updated_before,omitempty"`           // only cases last updated before this time
+	ScrapedAfter           *time.Time         `json:"scraped_after,omitempty"`            // only cases scraped after this time
+	IncludeFullText bool                 `json:"include_full_text,omitempty"` // load full text with listed and searched cases; see CaseFullText
 	Limit        int                    `json:"limit,omitempty"`
 	Offset       int                    `json:"offset,omitempty"`
 	OrderBy      string                 `json:"order_by,omitempty"`
 	OrderDesc    bool                   `json:"order_desc,omitempty"`
+	TenantScope  *string                `json:"-"` // only this tenant's and shared cases ("" for shared only); set by TenantStorage
+	JurisdictionScope []string          `json:"-"` // only cases of these jurisdictions, matched case-insensitively; set by JurisdictionStorage
+}
+
+// DeleteOptions controls DeleteCasesByFilter
+type DeleteOptions struct {
+	Force bool `json:"force,omitempty"` // allow a filter with no conditions, deleting every case
 }
 
 // JudgeFilter represents filters for judge queries
@@ -78,16 +108,19 @@ type JudgeFilter struct {
 	Jurisdiction string     `json:"jurisdiction,omitempty"`
 	Limit        int        `json:"limit,omitempty"`
 	Offset       int        `json:"offset,omitempty"`
+	TenantScope  *string    `json:"-"` // only this tenant's and shared judges; see CaseFilter
 }
 
 // CitationFilter represents filters for citation queries
 type CitationFilter struct {
 	CaseID       string     `json:"case_id,omitempty"`
+	CaseIDs      []string   `json:"case_ids,omitempty"` // made by or pointing at any of these cases
 	Format       string     `json:"format,omitempty"`
 	Year         int        `json:"year,omitempty"`
 	Valid        *bool      `json:"valid,omitempty"`
 	Limit        int        `json:"limit,omitempty"`
 	Offset       int        `json:"offset,omitempty"`
+	TenantScope  *string    `json:"-"` // only this tenant's and shared citations; see CaseFilter
 }
 
 // SearchQuery represents a search query
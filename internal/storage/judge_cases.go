@@ -0,0 +1,229 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JudgeCaseLister is implemented by backends that can page through a judge's
+// cases in the database. filter narrows the cases as it does for ListCases,
+// including to a tenant's scope, and sets the page.
+type JudgeCaseLister interface {
+	ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error)
+}
+
+// CasesByJudge returns a page of the cases decided by a judge, newest first,
+// along with the number of matching cases in total. A case is the judge's if
+// its judges list holds the judge's ID, as set when cases are linked to judges,
+// or the judge's name or full name. Backends implementing JudgeCaseLister page
+// in the database; others fall back to filtering the listed cases in memory.
+func CasesByJudge(ctx context.Context, store Storage, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	if lister, ok := store.(JudgeCaseLister); ok {
+		return lister.ListCasesByJudge(ctx, judgeID, filter)
+	}
+
+	aliases, err := judgeAliases(ctx, store, judgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	limit, offset := filter.Limit, filter.Offset
+	filter.Judges = aliases
+	filter.Limit, filter.Offset = 0, 0
+	cases, err := store.ListCases(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cases for judge %s: %w", judgeID, err)
+	}
+
+	// Not every backend applies the judges filter, so check each case
+	decided := make([]*models.Case, 0, len(cases))
+	for _, c := range cases {
+		if decidedBy(c, aliases) {
+			decided = append(decided, c)
+		}
+	}
+
+	return pageCasesByDecision(decided, limit, offset), int64(len(decided)), nil
+}
+
+// judgeGetter loads a judge by ID
+type judgeGetter interface {
+	GetJudge(ctx context.Context, id string) (*models.Judge, error)
+}
+
+// judgeAliases returns the values a case's judges list may hold for a judge
+func judgeAliases(ctx context.Context, store judgeGetter, judgeID string) ([]string, error) {
+	judge, err := store.GetJudge(ctx, judgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases []string
+	for _, alias := range []string{judge.ID, judge.Name, judge.FullName} {
+		alias = strings.TrimSpace(alias)
+		if alias != "" && !containsValue(aliases, alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases, nil
+}
+
+// decidedBy reports whether a case's judges include any of aliases
+func decidedBy(c *models.Case, aliases []string) bool {
+	for _, judge := range c.Judges {
+		if containsValue(aliases, judge) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageCasesByDecision orders cases newest first, undated cases last and ties
+// by ID, and returns the page at offset
+func pageCasesByDecision(cases []*models.Case, limit, offset int) []*models.Case {
+	sort.Slice(cases, func(i, j int) bool {
+		a, b := cases[i].DecisionDate, cases[j].DecisionDate
+		switch {
+		case a != nil && b != nil && !a.Equal(*b):
+			return a.After(*b)
+		case (a == nil) != (b == nil):
+			return a != nil
+		}
+		return cases[i].ID < cases[j].ID
+	})
+
+	return pageCases(cases, limit, offset)
+}
+
+// ListCasesByJudge pages through the cases whose judges list names the judge
+func (ss *SQLiteStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	aliases, err := judgeAliases(ctx, ss, judgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cond, args := sqlIn("j.value", aliases, nil, positionalPlaceholder)
+	where := ` WHERE EXISTS (SELECT 1 FROM json_each(c.judges) AS j WHERE ` + cond + `)`
+
+	cond, args = sqlCaseConditions(filter, "c", args, positionalPlaceholder)
+	where += " AND " + cond
+
+	var total int64
+	if err := ss.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM cases c`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by judge: %w", err)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+
+	cases, err := queryCaseIDs(ctx, ss.db, ss,
+		`SELECT c.id FROM cases c`+where+`
+		ORDER BY c.decision_date IS NULL, c.decision_date DESC, c.id
+		LIMIT ? OFFSET ?`,
+		append(args, limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByJudge pages through the cases whose judges list names the judge
+func (ps *PostgresStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	aliases, err := judgeAliases(ctx, ps, judgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// judges ?| matches a JSONB array holding any of the aliases, using the GIN index
+	var args []interface{}
+	marks := make([]string, len(aliases))
+	for i, alias := range aliases {
+		args = append(args, alias)
+		marks[i] = postgresPlaceholder(len(args))
+	}
+	where := ` WHERE judges ?| ARRAY[` + strings.Join(marks, ", ") + `]::text[]`
+
+	cond, args := sqlCaseConditions(filter, "cases", args, postgresPlaceholder)
+	where += " AND " + cond
+
+	var total int64
+	if err := ps.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM cases`+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by judge: %w", err)
+	}
+
+	query, args := postgresPage(`SELECT id FROM cases`+where+`
+		ORDER BY decision_date DESC NULLS LAST, id`, args, filter.Limit, filter.Offset)
+
+	cases, err := queryCaseIDs(ctx, ps.db, ps, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByJudge pages through the cases whose judges list names the judge
+func (ms *MongoStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	aliases, err := judgeAliases(ctx, ms, judgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := mongoCaseConditions(filter)
+	query["judges"] = bson.M{"$in": aliases}
+
+	total, err := ms.cases.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count cases by judge: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "decision_date", Value: -1}, {Key: "id", Value: 1}}).
+		SetSkip(int64(filter.Offset))
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := ms.cases.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cases by judge: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	cases := make([]*models.Case, 0)
+	if err := cursor.All(ctx, &cases); err != nil {
+		return nil, 0, err
+	}
+
+	return cases, total, nil
+}
+
+// ListCasesByJudge pages through the stored cases whose judges list names the judge
+func (ms *MemoryStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	aliases, err := judgeAliases(ctx, ms, judgeID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	filter.Judges = aliases
+	decided := make([]*models.Case, 0)
+	for _, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			decided = append(decided, c)
+		}
+	}
+
+	return pageCasesByDecision(decided, filter.Limit, filter.Offset), int64(len(decided)), nil
+}
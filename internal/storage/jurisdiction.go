@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// inJurisdictionScope reports whether a case of the jurisdiction is within the
+// filter's JurisdictionScope. Jurisdictions are matched case-insensitively.
+func (f CaseFilter) inJurisdictionScope(jurisdiction string) bool {
+	if len(f.JurisdictionScope) == 0 {
+		return true
+	}
+	for _, j := range f.JurisdictionScope {
+		if strings.EqualFold(j, jurisdiction) {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlJurisdictionScope returns the scope's jurisdictions lowercased, to match
+// LOWER(jurisdiction)
+func sqlJurisdictionScope(scope []string) []string {
+	lowered := make([]string, len(scope))
+	for i, j := range scope {
+		lowered[i] = strings.ToLower(j)
+	}
+	return lowered
+}
+
+// mongoJurisdictionScope returns a jurisdiction condition matching any of the
+// scope's jurisdictions, case-insensitively
+func mongoJurisdictionScope(scope []string) bson.M {
+	patterns := make(bson.A, len(scope))
+	for i, j := range scope {
+		patterns[i] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(j) + "$", Options: "i"}
+	}
+	return bson.M{"$in": patterns}
+}
+
+// JurisdictionStorage serves only the cases of the jurisdictions enabled in a
+// deployment. Cases of other jurisdictions are left out of lists, searches
+// and aggregations, and read by ID they are reported as not found. Writes
+// pass straight through, so disabling a jurisdiction keeps its stored cases.
+type JurisdictionStorage struct {
+	Storage
+	enabled []string
+}
+
+// NewJurisdictionStorage wraps storage to serve only the enabled
+// jurisdictions. An empty list enables every jurisdiction.
+func NewJurisdictionStorage(store Storage, enabled []string) *JurisdictionStorage {
+	j := &JurisdictionStorage{Storage: store}
+	for _, jurisdiction := range enabled {
+		if jurisdiction = strings.TrimSpace(jurisdiction); jurisdiction != "" {
+			j.enabled = append(j.enabled, jurisdiction)
+		}
+	}
+	return j
+}
+
+// Jurisdictions returns the enabled jurisdictions, empty if every one is
+func (j *JurisdictionStorage) Jurisdictions() []string {
+	return j.enabled
+}
+
+// EnabledJurisdictions returns the jurisdictions a JurisdictionStorage serves,
+// or nil for other storage, which serves every jurisdiction. Only the
+// outermost wrapper is checked, where the API places it.
+func EnabledJurisdictions(store Storage) []string {
+	if j, ok := store.(*JurisdictionStorage); ok {
+		return j.Jurisdictions()
+	}
+	return nil
+}
+
+// scope restricts a filter to the enabled jurisdictions
+func (j *JurisdictionStorage) scope(filter CaseFilter) CaseFilter {
+	filter.JurisdictionScope = j.enabled
+	return filter
+}
+
+// GetCase returns a case of an enabled jurisdiction
+func (j *JurisdictionStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	c, err := j.Storage.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !(CaseFilter{JurisdictionScope: j.enabled}).inJurisdictionScope(c.Jurisdiction) {
+		return nil, notVisible("case", id)
+	}
+	return c, nil
+}
+
+// ListCases lists the cases of enabled jurisdictions
+func (j *JurisdictionStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
+	return j.Storage.ListCases(ctx, j.scope(filter))
+}
+
+// CountCases counts the cases of enabled jurisdictions
+func (j *JurisdictionStorage) CountCases(ctx context.Context, filter CaseFilter) (int64, error) {
+	return j.Storage.CountCases(ctx, j.scope(filter))
+}
+
+// SearchCases searches the cases of enabled jurisdictions
+func (j *JurisdictionStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
+	query.Filters = j.scope(query.Filters)
+	return j.Storage.SearchCases(ctx, query)
+}
+
+// SuggestCaseNames suggests the names of cases of enabled jurisdictions
+func (j *JurisdictionStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	return j.Storage.SuggestCaseNames(ctx, prefix, j.scope(filter), limit)
+}
+
+// SuggestConcepts suggests the legal concepts of cases of enabled
+// jurisdictions
+func (j *JurisdictionStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	return j.Storage.SuggestConcepts(ctx, prefix, j.scope(filter), limit)
+}
+
+// AggregateFacets counts facet values over the cases of enabled jurisdictions
+func (j *JurisdictionStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	query.Filters = j.scope(query.Filters)
+	return AggregateFacets(ctx, j.Storage, query, fields)
+}
+
+// AggregateTimeline counts the cases of enabled jurisdictions per period
+func (j *JurisdictionStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	query.Filters = j.scope(query.Filters)
+	return AggregateTimeline(ctx, j.Storage, query, period)
+}
+
+// TermStats returns the corpus-wide term statistics. They only weight
+// ranking, so they cover every jurisdiction.
+func (j *JurisdictionStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return CorpusTermStats(ctx, j.Storage)
+}
+
+// StreamCases streams the matching cases of enabled jurisdictions
+func (j *JurisdictionStorage) StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error {
+	return StreamCases(ctx, j.Storage, j.scope(filter), fn)
+}
+
+// ExistingIDs returns which of ids are stored, in any jurisdiction, so that
+// imports don't collide with hidden cases; see storage.StoredCaseIDs
+func (j *JurisdictionStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	return StoredCaseIDs(ctx, j.Storage, ids)
+}
+
+// GetContentHash reads a case's content hash; see storage.StoredContentHash
+func (j *JurisdictionStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	return StoredContentHash(ctx, j.Storage, id)
+}
+
+// GetCaseFullText loads the full text of a case of an enabled jurisdiction
+func (j *JurisdictionStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	if len(j.enabled) == 0 {
+		return CaseFullText(ctx, j.Storage, id)
+	}
+	return loadedFullText(ctx, j, id)
+}
+
+// ListCasesByJudge pages through a judge's cases of enabled jurisdictions
+func (j *JurisdictionStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByJudge(ctx, j.Storage, judgeID, j.scope(filter))
+}
+
+// ListCasesByConcept pages through a concept's cases of enabled jurisdictions
+func (j *JurisdictionStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByConcept(ctx, j.Storage, concept, j.scope(filter))
+}
+
+// CountConceptCooccurrence counts related concepts over the cases of enabled
+// jurisdictions
+func (j *JurisdictionStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	return ConceptCooccurrence(ctx, j.Storage, concept, j.scope(filter), limit)
+}
+
+// SaveCitations saves a batch of citations; see storage.SaveCitations
+func (j *JurisdictionStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	return SaveCitations(ctx, j.Storage, citations)
+}
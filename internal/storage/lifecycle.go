@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/internal/clock"
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// ErrIllegalStatusTransition is returned when an update moves a case to a
+// status its current status may not move to
+var ErrIllegalStatusTransition = errors.New("illegal case status transition")
+
+// StatusTransitions maps each case status to the statuses it may move to. A
+// status missing from the map is final.
+type StatusTransitions map[models.CaseStatus][]models.CaseStatus
+
+// DefaultStatusTransitions returns the default case lifecycle. Overruled and
+// overturned cases only go back to pending, so they are reviewed before being
+// treated as good law again.
+func DefaultStatusTransitions() StatusTransitions {
+	return StatusTransitions{
+		models.CaseStatusPending:    {models.CaseStatusActive, models.CaseStatusClosed},
+		models.CaseStatusActive:     {models.CaseStatusClosed, models.CaseStatusAppealed, models.CaseStatusOverturned, models.CaseStatusOverruled},
+		models.CaseStatusClosed:     {models.CaseStatusAppealed, models.CaseStatusOverturned, models.CaseStatusOverruled},
+		models.CaseStatusAppealed:   {models.CaseStatusActive, models.CaseStatusClosed, models.CaseStatusOverturned},
+		models.CaseStatusOverturned: {models.CaseStatusPending},
+		models.CaseStatusOverruled:  {models.CaseStatusPending},
+	}
+}
+
+// ParseStatusTransitions builds a transition map from configuration, keyed by
+// status name. Unknown statuses are rejected.
+func ParseStatusTransitions(config map[string][]string) (StatusTransitions, error) {
+	known := make(map[models.CaseStatus]bool)
+	for from := range DefaultStatusTransitions() {
+		known[from] = true
+	}
+
+	transitions := make(StatusTransitions, len(config))
+	for from, targets := range config {
+		status := models.CaseStatus(from)
+		if !known[status] {
+			return nil, fmt.Errorf("unknown case status: %s", from)
+		}
+		for _, to := range targets {
+			if !known[models.CaseStatus(to)] {
+				return nil, fmt.Errorf("unknown case status: %s", to)
+			}
+			transitions[status] = append(transitions[status], models.CaseStatus(to))
+		}
+	}
+	return transitions, nil
+}
+
+// Allows reports whether a case may move from one status to another. Keeping
+// the same status is always allowed.
+func (t StatusTransitions) Allows(from, to models.CaseStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range t[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Known reports whether a status is part of the lifecycle, either moving to
+// or from another status. A new case must start in a known status.
+func (t StatusTransitions) Known(status models.CaseStatus) bool {
+	for from, targets := range t {
+		if from == status {
+			return true
+		}
+		for _, to := range targets {
+			if to == status {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StatusChange is an audit log entry for a case moving between statuses
+type StatusChange struct {
+	CaseID string            `json:"case_id" bson:"case_id"`
+	From   models.CaseStatus `json:"from" bson:"from"`
+	To     models.CaseStatus `json:"to" bson:"to"`
+	At     time.Time         `json:"at" bson:"at"`
+}
+
+// AuditLog records case status changes
+type AuditLog interface {
+	RecordStatusChange(ctx context.Context, change StatusChange) error
+	StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error)
+}
+
+// StatusAuditLog returns the audit log kept by the backend behind store,
+// looking through the tenancy, lifecycle, timeout and write-behind wrappers,
+// and false if the backend keeps none
+func StatusAuditLog(store Storage) (AuditLog, bool) {
+	for {
+		if audit, ok := store.(AuditLog); ok {
+			return audit, true
+		}
+		switch s := store.(type) {
+		case *TenantStorage:
+			store = s.Storage
+		case *LifecycleStorage:
+			store = s.Storage
+		case *TimeoutStorage:
+			store = s.Storage
+		case *WriteBehindStorage:
+			store = s.Storage
+		default:
+			return nil, false
+		}
+	}
+}
+
+// MemoryAuditLog keeps status changes in memory
+type MemoryAuditLog struct {
+	mu      sync.RWMutex
+	changes map[string][]StatusChange
+}
+
+// NewMemoryAuditLog creates an empty in-memory audit log
+func NewMemoryAuditLog() *MemoryAuditLog {
+	return &MemoryAuditLog{
+		changes: make(map[string][]StatusChange),
+	}
+}
+
+// RecordStatusChange appends a status change to the case's history
+func (l *MemoryAuditLog) RecordStatusChange(ctx context.Context, change StatusChange) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.changes[change.CaseID] = append(l.changes[change.CaseID], change)
+	return nil
+}
+
+// StatusHistory returns a case's status changes, oldest first
+func (l *MemoryAuditLog) StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]StatusChange(nil), l.changes[caseID]...), nil
+}
+
+// LifecycleStorage validates case status transitions on SaveCase and
+// UpdateCase, and the starting status on CreateCase, and records each change
+// in an audit log. Writes are serialised so that two concurrent writes can't
+// both pass the check against the same status.
+type LifecycleStorage struct {
+	Storage
+	transitions StatusTransitions
+	audit       AuditLog
+	clock       clock.Clock
+
+	mu sync.Mutex
+}
+
+// NewLifecycleStorage wraps storage with status transition checks. A nil
+// audit log records changes in the backend's own log, so history survives a
+// restart, or in memory if the backend keeps none.
+func NewLifecycleStorage(store Storage, transitions StatusTransitions, audit AuditLog) *LifecycleStorage {
+	if transitions == nil {
+		transitions = DefaultStatusTransitions()
+	}
+	if audit == nil {
+		if stored, ok := StatusAuditLog(store); ok {
+			audit = stored
+		} else {
+			audit = NewMemoryAuditLog()
+		}
+	}
+	return &LifecycleStorage{
+		Storage:     store,
+		transitions: transitions,
+		audit:       audit,
+		clock:       clock.Real(),
+	}
+}
+
+// SetClock sets the clock used to timestamp status changes
+func (l *LifecycleStorage) SetClock(c clock.Clock) {
+	l.clock = c
+}
+
+// AuditLog returns the log status changes are recorded in
+func (l *LifecycleStorage) AuditLog() AuditLog {
+	return l.audit
+}
+
+// SaveCase rejects a save that moves an existing case to a status its
+// current status may not move to, or starts a new case in a status outside
+// the lifecycle, and records any status change
+func (l *LifecycleStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, err := l.Storage.GetCase(ctx, c.ID)
+	if errors.Is(err, kiteerrors.ErrNotFound) {
+		if err := l.checkInitial(c); err != nil {
+			return err
+		}
+		return l.Storage.SaveCase(ctx, c)
+	}
+	if err != nil {
+		return err
+	}
+	return l.write(ctx, current.Status, c, l.Storage.SaveCase)
+}
+
+// CreateCase rejects a new case whose status is outside the lifecycle
+func (l *LifecycleStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	if err := l.checkInitial(c); err != nil {
+		return err
+	}
+	return l.Storage.CreateCase(ctx, c)
+}
+
+// UpdateCase rejects an update that moves the case to a status its current
+// status may not move to, and records any status change
+func (l *LifecycleStorage) UpdateCase(ctx context.Context, c *models.Case) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	current, err := l.Storage.GetCase(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	return l.write(ctx, current.Status, c, l.Storage.UpdateCase)
+}
+
+// checkInitial rejects a new case starting in a status outside the lifecycle
+func (l *LifecycleStorage) checkInitial(c *models.Case) error {
+	if !l.transitions.Known(c.Status) {
+		return fmt.Errorf("%w: case %s created as %q", ErrIllegalStatusTransition, c.ID, c.Status)
+	}
+	return nil
+}
+
+// write checks the move from the stored status, writes the case and records
+// the change. The caller holds l.mu.
+func (l *LifecycleStorage) write(ctx context.Context, from models.CaseStatus, c *models.Case, store func(context.Context, *models.Case) error) error {
+	to := c.Status
+	if !l.transitions.Allows(from, to) {
+		return fmt.Errorf("%w: case %s from %q to %q", ErrIllegalStatusTransition, c.ID, from, to)
+	}
+
+	if err := store(ctx, c); err != nil {
+		return err
+	}
+
+	if from == to {
+		return nil
+	}
+	return l.audit.RecordStatusChange(ctx, StatusChange{
+		CaseID: c.ID,
+		From:   from,
+		To:     to,
+		At:     l.clock.Now(),
+	})
+}
+
+// AggregateFacets counts facet values; see storage.AggregateFacets
+func (l *LifecycleStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	return AggregateFacets(ctx, l.Storage, query, fields)
+}
+
+// AggregateTimeline counts cases per period; see storage.AggregateTimeline
+func (l *LifecycleStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	return AggregateTimeline(ctx, l.Storage, query, period)
+}
+
+// TermStats returns the term statistics; see storage.CorpusTermStats
+func (l *LifecycleStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return CorpusTermStats(ctx, l.Storage)
+}
+
+// StreamCases streams matching cases; see storage.StreamCases
+func (l *LifecycleStorage) StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error {
+	return StreamCases(ctx, l.Storage, filter, fn)
+}
+
+// ExistingIDs returns which of ids are stored; see storage.StoredCaseIDs
+func (l *LifecycleStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	return StoredCaseIDs(ctx, l.Storage, ids)
+}
+
+// GetContentHash reads a case's content hash; see storage.StoredContentHash
+func (l *LifecycleStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	return StoredContentHash(ctx, l.Storage, id)
+}
+
+// GetCaseFullText loads a case's full text; see storage.CaseFullText
+func (l *LifecycleStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	return CaseFullText(ctx, l.Storage, id)
+}
+
+// ListCasesByJudge pages through a judge's cases; see storage.CasesByJudge
+func (l *LifecycleStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByJudge(ctx, l.Storage, judgeID, filter)
+}
+
+// ListCasesByConcept pages through a concept's cases; see
+// storage.CasesByConcept
+func (l *LifecycleStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByConcept(ctx, l.Storage, concept, filter)
+}
+
+// CountConceptCooccurrence counts related concepts; see
+// storage.ConceptCooccurrence
+func (l *LifecycleStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	return ConceptCooccurrence(ctx, l.Storage, concept, filter, limit)
+}
+
+// SaveCitations saves a batch of citations; see storage.SaveCitations
+func (l *LifecycleStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	return SaveCitations(ctx, l.Storage, citations)
+}
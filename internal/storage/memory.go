@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
@@ -15,7 +16,14 @@ type MemoryStorage struct {
 	cases     map[string]*models.Case
 	judges    map[string]*models.Judge
 	citations map[string]*models.Citation
+	terms     *TermStats
 	mu        sync.RWMutex
+
+	annotations   map[string]*models.Annotation
+	savedSearches map[string]*models.SavedSearch
+	alerts        map[string]map[string]bool // case IDs alerted, by saved search
+	statusChanges map[string][]StatusChange
+	caseVersions  map[string]map[int]*models.Case // earlier versions, by case ID and version
 }
 
 // NewMemoryStorage creates a new MemoryStorage
@@ -24,19 +32,49 @@ func NewMemoryStorage() *MemoryStorage {
 		cases:     make(map[string]*models.Case),
 		judges:    make(map[string]*models.Judge),
 		citations: make(map[string]*models.Citation),
+		terms:     NewTermStats(),
+
+		annotations:   make(map[string]*models.Annotation),
+		savedSearches: make(map[string]*models.SavedSearch),
+		alerts:        make(map[string]map[string]bool),
+		statusChanges: make(map[string][]StatusChange),
+		caseVersions:  make(map[string]map[int]*models.Case),
 	}
 }
 
-// SaveCase saves a case
+// SaveCase saves a case, replacing any existing case with the same ID
 func (ms *MemoryStorage) SaveCase(ctx context.Context, c *models.Case) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
+	ms.cases[c.ID] = c
+	ms.terms.Add(c)
+	return nil
+}
+
+// CreateCase saves a new case, failing if the ID is already taken
+func (ms *MemoryStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
 	if _, exists := ms.cases[c.ID]; exists {
 		return errors.StorageError("case already exists", errors.ErrAlreadyExists)
 	}
 
 	ms.cases[c.ID] = c
+	ms.terms.Add(c)
+	return nil
+}
+
+// SaveCases saves cases together, replacing any existing cases with the same IDs
+func (ms *MemoryStorage) SaveCases(ctx context.Context, cases []*models.Case) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, c := range cases {
+		ms.cases[c.ID] = c
+		ms.terms.Add(c)
+	}
 	return nil
 }
 
@@ -64,6 +102,7 @@ func (ms *MemoryStorage) UpdateCase(ctx context.Context, c *models.Case) error {
 
 	c.LastUpdated = time.Now()
 	ms.cases[c.ID] = c
+	ms.terms.Add(c)
 	return nil
 }
 
@@ -77,6 +116,7 @@ func (ms *MemoryStorage) DeleteCase(ctx context.Context, id string) error {
 	}
 
 	delete(ms.cases, id)
+	ms.terms.Remove(id)
 	return nil
 }
 
@@ -88,23 +128,22 @@ func (ms *MemoryStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*m
 	var results []*models.Case
 
 	for _, c := range ms.cases {
-		if ms.matchesFilter(c, filter) {
-			results = append(results, c)
+		if !ms.matchesFilter(c, filter) {
+			continue
 		}
+		if !filter.IncludeFullText {
+			c = withoutFullText(c)
+		}
+		results = append(results, c)
 	}
 
-	// Apply limit and offset
-	start := filter.Offset
-	if start > len(results) {
-		start = len(results)
-	}
-
-	end := start + filter.Limit
-	if filter.Limit == 0 || end > len(results) {
-		end = len(results)
+	if filter.OrderBy == OrderByAuthority {
+		sortCasesByAuthority(results)
+		return pageCases(results, filter.Limit, filter.Offset), nil
 	}
 
-	return results[start:end], nil
+	// Order like the SQL backends, newest first, so pages don't overlap
+	return pageCasesByDecision(results, filter.Limit, filter.Offset), nil
 }
 
 // CountCases counts cases matching the filter
@@ -138,13 +177,23 @@ func (ms *MemoryStorage) matchesFilter(c *models.Case, filter CaseFilter) bool {
 		}
 	}
 
+	// Check case number
+	if filter.CaseNumber != "" && c.CaseNumber != filter.CaseNumber {
+		return false
+	}
+
+	// Check docket
+	if filter.Docket != "" && c.Docket != filter.Docket {
+		return false
+	}
+
 	// Check jurisdiction
-	if filter.Jurisdiction != "" && c.Jurisdiction != filter.Jurisdiction {
+	if jurisdictions := filter.JurisdictionValues(); len(jurisdictions) > 0 && !containsValue(jurisdictions, c.Jurisdiction) {
 		return false
 	}
 
-	// Check court
-	if filter.Court != "" && c.Court != filter.Court {
+	// Check court, by name or normalized identifier
+	if !filter.matchesCourt(c.Court, c.CourtID) {
 		return false
 	}
 
@@ -210,6 +259,36 @@ func (ms *MemoryStorage) matchesFilter(c *models.Case, filter CaseFilter) bool {
 		return false
 	}
 
+	// Check full text length
+	if filter.MinFullTextLength > 0 && utf8.RuneCountInString(c.FullText) < filter.MinFullTextLength {
+		return false
+	}
+
+	// Check extraction version
+	if filter.ExtractionVersionBelow > 0 && c.ExtractionVersion >= filter.ExtractionVersionBelow {
+		return false
+	}
+
+	// Check last update
+	if cutoff := filter.updatedCutoff(); cutoff != nil && !c.LastUpdated.Before(*cutoff) {
+		return false
+	}
+
+	// Check scrape time
+	if filter.ScrapedAfter != nil && !c.ScrapedAt.After(*filter.ScrapedAfter) {
+		return false
+	}
+
+	// Check tenant
+	if !visibleToTenant(c.TenantID, filter.TenantScope) {
+		return false
+	}
+
+	// Check enabled jurisdictions
+	if !filter.inJurisdictionScope(c.Jurisdiction) {
+		return false
+	}
+
 	return true
 }
 
@@ -275,6 +354,10 @@ func (ms *MemoryStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]
 			match = false
 		}
 
+		if !visibleToTenant(j.TenantID, filter.TenantScope) {
+			match = false
+		}
+
 		if match {
 			results = append(results, j)
 		}
@@ -300,8 +383,10 @@ func (ms *MemoryStorage) SaveCitation(ctx context.Context, c *models.Citation) e
 	defer ms.mu.Unlock()
 
 	// Generate ID if not set
-	id := c.CaseID + "-" + c.RawCitation
-	ms.citations[id] = c
+	if c.ID == "" {
+		c.ID = c.CaseID + "-" + c.RawCitation
+	}
+	ms.citations[c.ID] = c
 	return nil
 }
 
@@ -325,6 +410,14 @@ func (ms *MemoryStorage) ListCitations(ctx context.Context, filter CitationFilte
 
 	var results []*models.Citation
 
+	var related map[string]bool
+	if len(filter.CaseIDs) > 0 {
+		related = make(map[string]bool, len(filter.CaseIDs))
+		for _, id := range filter.CaseIDs {
+			related[id] = true
+		}
+	}
+
 	for _, c := range ms.citations {
 		match := true
 
@@ -332,6 +425,10 @@ func (ms *MemoryStorage) ListCitations(ctx context.Context, filter CitationFilte
 			match = false
 		}
 
+		if related != nil && !related[c.CaseID] && !related[c.CitingCaseID] {
+			match = false
+		}
+
 		if filter.Format != "" && string(c.Format) != filter.Format {
 			match = false
 		}
@@ -344,6 +441,10 @@ func (ms *MemoryStorage) ListCitations(ctx context.Context, filter CitationFilte
 			match = false
 		}
 
+		if !visibleToTenant(c.TenantID, filter.TenantScope) {
+			match = false
+		}
+
 		if match {
 			results = append(results, c)
 		}
@@ -377,24 +478,27 @@ func (ms *MemoryStorage) SearchCases(ctx context.Context, query SearchQuery) ([]
 			strings.Contains(strings.ToLower(c.Summary), queryLower) {
 
 			// Also check against filters
-			if ms.matchesFilter(c, query.Filters) {
-				results = append(results, c)
+			if !ms.matchesFilter(c, query.Filters) {
+				continue
+			}
+			if !query.Filters.IncludeFullText {
+				c = withoutFullText(c)
 			}
+			results = append(results, c)
 		}
 	}
-
-	// Apply limit and offset
-	start := query.Offset
-	if start > len(results) {
-		start = len(results)
+	if query.Filters.OrderBy == OrderByAuthority {
+		sortCasesByAuthority(results)
+		return pageCases(results, query.Limit, query.Offset), nil
 	}
 
-	end := start + query.Limit
-	if query.Limit == 0 || end > len(results) {
-		end = len(results)
-	}
+	// Order like ListCases so pages don't overlap
+	return pageCasesByDecision(results, query.Limit, query.Offset), nil
+}
 
-	return results[start:end], nil
+// TermStats returns the term statistics maintained over stored cases
+func (ms *MemoryStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return ms.terms, nil
 }
 
 // Ping checks if the storage is available
@@ -428,4 +532,5 @@ func (ms *MemoryStorage) Clear() {
 	ms.cases = make(map[string]*models.Case)
 	ms.judges = make(map[string]*models.Judge)
 	ms.citations = make(map[string]*models.Citation)
+	ms.terms = NewTermStats()
 }
@@ -253,6 +253,17 @@ type MigrationStatus struct {
 	AppliedAt   time.Time `json:"applied_at,omitempty"`
 }
 
+// addColumnIfMissing adds a column to table unless it is already there, as it
+// is in databases whose schema was created after the column was introduced
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	if _, err := db.Exec(fmt.Sprintf(`SELECT %s FROM %s LIMIT 0`, column, table)); err == nil {
+		return nil
+	}
+
+	_, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, definition))
+	return err
+}
+
 // DefaultMigrations returns the default migrations for Kite
 func DefaultMigrations() []Migration {
 	return []Migration{
@@ -307,5 +318,141 @@ func DefaultMigrations() []Migration {
 				return fmt.Errorf("rollback not supported for this migration in SQLite")
 			},
 		},
+		{
+			Version:     4,
+			Description: "Add normalized court identifier",
+			Up: func(db *sql.DB) error {
+				if err := addColumnIfMissing(db, "cases", "court_id", "TEXT"); err != nil {
+					return err
+				}
+				_, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_cases_court_id ON cases(court_id);`)
+				return err
+			},
+			Down: func(db *sql.DB) error {
+				_, err := db.Exec(`DROP INDEX IF EXISTS idx_cases_court_id;`)
+				return err
+			},
+		},
+		{
+			Version:     5,
+			Description: "Add case-insensitive prefix indexes for autocomplete",
+			Up: func(db *sql.DB) error {
+				_, err := db.Exec(`
+					CREATE INDEX IF NOT EXISTS idx_cases_case_name_nocase ON cases(case_name COLLATE NOCASE);
+					CREATE INDEX IF NOT EXISTS idx_judges_name_nocase ON judges(name COLLATE NOCASE);
+				`)
+				return err
+			},
+			Down: func(db *sql.DB) error {
+				_, err := db.Exec(`
+					DROP INDEX IF EXISTS idx_cases_case_name_nocase;
+					DROP INDEX IF EXISTS idx_judges_name_nocase;
+				`)
+				return err
+			},
+		},
+		{
+			Version:     6,
+			Description: "Add extracted holding",
+			Up: func(db *sql.DB) error {
+				return addColumnIfMissing(db, "cases", "holding", "TEXT")
+			},
+			Down: func(db *sql.DB) error {
+				// SQLite doesn't support DROP COLUMN
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
+		{
+			Version:     7,
+			Description: "Add appellate chain links",
+			Up: func(db *sql.DB) error {
+				if err := addColumnIfMissing(db, "cases", "lower_court_case_id", "TEXT"); err != nil {
+					return err
+				}
+				return addColumnIfMissing(db, "cases", "appealed_to_case_id", "TEXT")
+			},
+			Down: func(db *sql.DB) error {
+				// SQLite doesn't support DROP COLUMN
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
+		{
+			Version:     8,
+			Description: "Add extraction version",
+			Up: func(db *sql.DB) error {
+				return addColumnIfMissing(db, "cases", "extraction_version", "INTEGER")
+			},
+			Down: func(db *sql.DB) error {
+				// SQLite doesn't support DROP COLUMN
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
+		{
+			Version:     9,
+			Description: "Move full text to case_full_text",
+			Up: func(db *sql.DB) error {
+				// Copy full text out of the cases table, emptying its
+				// column; SQLite doesn't support DROP COLUMN
+				_, err := db.Exec(`
+					CREATE TABLE IF NOT EXISTS case_full_text (
+						case_id TEXT PRIMARY KEY REFERENCES cases(id) ON DELETE CASCADE,
+						full_text TEXT
+					);
+					INSERT INTO case_full_text (case_id, full_text)
+						SELECT id, full_text FROM cases WHERE full_text IS NOT NULL
+						ON CONFLICT(case_id) DO NOTHING;
+					UPDATE cases SET full_text = NULL;
+				`)
+				if err != nil {
+					return err
+				}
+
+				// The search index read full text from the cases table;
+				// rebuild it over both
+				_, err = db.Exec(`
+					DROP TRIGGER IF EXISTS cases_fts_update;
+					DROP TRIGGER IF EXISTS cases_fts_delete;
+					DROP TRIGGER IF EXISTS cases_fts_insert;
+					DROP TABLE IF EXISTS cases_fts;
+				` + sqliteSearchSchema + `
+					INSERT INTO cases_fts(id, case_name, summary, full_text)
+						SELECT c.id, c.case_name, c.summary, t.full_text
+						FROM cases c LEFT JOIN case_full_text t ON t.case_id = c.id;
+				`)
+				return err
+			},
+			Down: func(db *sql.DB) error {
+				// The search triggers read from case_full_text
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
+		{
+			Version:     10,
+			Description: "Add content hash",
+			Up: func(db *sql.DB) error {
+				return addColumnIfMissing(db, "cases", "content_hash", "TEXT")
+			},
+			Down: func(db *sql.DB) error {
+				// SQLite doesn't support DROP COLUMN
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
+		{
+			Version:     11,
+			Description: "Add tenant ownership",
+			Up: func(db *sql.DB) error {
+				for _, table := range []string{"cases", "judges", "citations"} {
+					if err := addColumnIfMissing(db, table, "tenant_id", "TEXT"); err != nil {
+						return err
+					}
+				}
+				_, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_cases_tenant ON cases(tenant_id);`)
+				return err
+			},
+			Down: func(db *sql.DB) error {
+				// SQLite doesn't support DROP COLUMN
+				return fmt.Errorf("rollback not supported for this migration in SQLite")
+			},
+		},
 	}
 }
@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// MongoConfig holds MongoDB client options. Client options left unset (zero)
+// are taken from the connection URI, and those the URI doesn't set either
+// from DefaultMongoConfig.
+type MongoConfig struct {
+	// ReadPreference is one of primary, primaryPreferred, secondary, secondaryPreferred, nearest
+	ReadPreference string
+	// ReadConcern is one of local, available, majority, linearizable, snapshot
+	ReadConcern string
+	// WriteConcern is "majority" or a number of acknowledging nodes
+	WriteConcern string
+	// Journal requires writes to be committed to the on-disk journal
+	Journal *bool
+	// WriteTimeout bounds how long the write concern waits for acknowledgement
+	WriteTimeout time.Duration
+
+	ConnectTimeout         time.Duration
+	ServerSelectionTimeout time.Duration
+	SocketTimeout          time.Duration
+
+	// ConnectRetry retries the initial ping while the server comes up
+	ConnectRetry ConnectRetry
+
+	// TextLanguage is the default stemming language of the case text index,
+	// e.g. english or french; "none" indexes words without stemming, which
+	// suits mixed-language corpora (empty uses english)
+	TextLanguage string
+	// TextLanguageOverride names the case field whose value, when set to a
+	// supported language, overrides TextLanguage for that case (empty uses
+	// the case's language field)
+	TextLanguageOverride string
+}
+
+// caseTextIndexName names the case text index so that it can be found and
+// rebuilt when its language settings change
+const caseTextIndexName = "cases_text"
+
+// caseFullTextIndexName names the text index of the case_full_text collection
+const caseFullTextIndexName = "case_full_text_text"
+
+// mongoTextLanguages are the languages MongoDB text indexes support, by name
+// and ISO 639-1 code
+var mongoTextLanguages = map[string]bool{
+	"none":   true,
+	"danish": true, "da": true, "dutch": true, "nl": true, "english": true, "en": true,
+	"finnish": true, "fi": true, "french": true, "fr": true, "german": true, "de": true,
+	"hungarian": true, "hu": true, "italian": true, "it": true, "norwegian": true, "nb": true,
+	"portuguese": true, "pt": true, "romanian": true, "ro": true, "russian": true, "ru": true,
+	"spanish": true, "es": true, "swedish": true, "sv": true, "turkish": true, "tr": true,
+}
+
+// DefaultMongoConfig returns the default MongoDB configuration
+func DefaultMongoConfig() MongoConfig {
+	journal := true
+	return MongoConfig{
+		ReadPreference:         "primary",
+		ReadConcern:            "majority",
+		WriteConcern:           "majority",
+		Journal:                &journal,
+		WriteTimeout:           5 * time.Second,
+		ConnectTimeout:         10 * time.Second,
+		ServerSelectionTimeout: 30 * time.Second,
+		SocketTimeout:          30 * time.Second,
+		ConnectRetry:           DefaultConnectRetry(),
+		TextLanguage:           "english",
+		TextLanguageOverride:   "language",
+	}
+}
+
+// Validate checks the configuration for invalid or conflicting settings
+func (c MongoConfig) Validate() error {
+	if _, err := c.readPref(); err != nil {
+		return err
+	}
+
+	switch c.ReadConcern {
+	case "", "local", "available", "majority", "linearizable", "snapshot":
+	default:
+		return fmt.Errorf("invalid mongo read concern: %s", c.ReadConcern)
+	}
+
+	// Linearizable reads are only served by the primary
+	if c.ReadConcern == "linearizable" && c.ReadPreference != "" && c.ReadPreference != "primary" {
+		return fmt.Errorf("mongo read concern linearizable requires read preference primary, got %s", c.ReadPreference)
+	}
+
+	w, err := c.writeConcern(nil)
+	if err != nil {
+		return err
+	}
+	if !acknowledged(w) && w.Journal != nil && *w.Journal {
+		return fmt.Errorf("mongo journaled writes require an acknowledged write concern")
+	}
+
+	if c.WriteTimeout < 0 || c.ConnectTimeout < 0 || c.ServerSelectionTimeout < 0 || c.SocketTimeout < 0 {
+		return fmt.Errorf("mongo timeouts must not be negative")
+	}
+
+	if c.TextLanguage != "" && !mongoTextLanguages[strings.ToLower(c.TextLanguage)] {
+		return fmt.Errorf("unsupported mongo text index language: %s", c.TextLanguage)
+	}
+
+	return nil
+}
+
+// ClientOptions builds driver client options for the URI from the
+// configuration. Options set in the configuration override the URI; the
+// defaults only fill in options neither of them sets.
+func (c MongoConfig) ClientOptions(uri string) (*options.ClientOptions, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	opts := options.Client().ApplyURI(uri)
+	defaults := DefaultMongoConfig()
+
+	// Store models under their JSON field names (court_id, not courtid), which
+	// the queries and indexes use, keeping any other BSON options already set
+	bsonOpts := &options.BSONOptions{}
+	if opts.BSONOptions != nil {
+		copied := *opts.BSONOptions
+		bsonOpts = &copied
+	}
+	bsonOpts.UseJSONStructTags = true
+	opts.SetBSONOptions(bsonOpts)
+
+	// An empty read preference parses as primary, the default
+	if c.ReadPreference != "" || opts.ReadPreference == nil {
+		rp, _ := c.readPref()
+		opts.SetReadPreference(rp)
+	}
+
+	level := c.ReadConcern
+	if level == "" && opts.ReadConcern == nil {
+		level = defaults.ReadConcern
+	}
+	if level != "" {
+		opts.SetReadConcern(readconcern.New(readconcern.Level(level)))
+	}
+
+	w, _ := c.writeConcern(opts.WriteConcern)
+	opts.SetWriteConcern(w)
+
+	if c.ConnectTimeout > 0 {
+		opts.SetConnectTimeout(c.ConnectTimeout)
+	} else if opts.ConnectTimeout == nil {
+		opts.SetConnectTimeout(defaults.ConnectTimeout)
+	}
+	if c.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(c.ServerSelectionTimeout)
+	} else if opts.ServerSelectionTimeout == nil {
+		opts.SetServerSelectionTimeout(defaults.ServerSelectionTimeout)
+	}
+	if c.SocketTimeout > 0 {
+		opts.SetSocketTimeout(c.SocketTimeout)
+	} else if opts.SocketTimeout == nil {
+		opts.SetSocketTimeout(defaults.SocketTimeout)
+	}
+
+	return opts, nil
+}
+
+// TextIndexModel builds the case text index with the configured languages
+func (c MongoConfig) TextIndexModel() mongo.IndexModel {
+	language := strings.ToLower(c.TextLanguage)
+	if language == "" {
+		language = "english"
+	}
+	override := c.TextLanguageOverride
+	if override == "" {
+		override = "language"
+	}
+
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "case_name", Value: "text"},
+			{Key: "summary", Value: "text"},
+			{Key: "full_text", Value: "text"},
+		},
+		Options: options.Index().
+			SetName(caseTextIndexName).
+			SetDefaultLanguage(language).
+			SetLanguageOverride(override),
+	}
+}
+
+// FullTextIndexModel builds the text index of the case_full_text collection,
+// with the languages of the case text index
+func (c MongoConfig) FullTextIndexModel() mongo.IndexModel {
+	model := c.TextIndexModel()
+	model.Keys = bson.D{{Key: "full_text", Value: "text"}}
+	model.Options.SetName(caseFullTextIndexName)
+	return model
+}
+
+// readPref parses the configured read preference
+func (c MongoConfig) readPref() (*readpref.ReadPref, error) {
+	if c.ReadPreference == "" {
+		return readpref.Primary(), nil
+	}
+
+	mode, err := readpref.ModeFromString(c.ReadPreference)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mongo read preference: %s", c.ReadPreference)
+	}
+
+	return readpref.New(mode)
+}
+
+// writeConcern builds the configured write concern over base, the one from the
+// URI (nil if it sets none). When the URI sets no write concern, parts not
+// configured take their defaults; the journal default only applies to
+// acknowledged writes. The driver drops journal=false from a URI, so a URI
+// write concern's unset parts are left unset rather than defaulted.
+func (c MongoConfig) writeConcern(base *writeconcern.WriteConcern) (*writeconcern.WriteConcern, error) {
+	defaults := DefaultMongoConfig()
+
+	concern := &writeconcern.WriteConcern{}
+	fromURI := base != nil
+	if fromURI {
+		copied := *base
+		concern = &copied
+	}
+
+	switch w := strings.TrimSpace(c.WriteConcern); {
+	case w == "majority":
+		concern.W = "majority"
+	case w != "":
+		n, err := strconv.Atoi(w)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid mongo write concern: %s", c.WriteConcern)
+		}
+		concern.W = n
+	case !fromURI:
+		concern.W = defaults.WriteConcern
+	}
+
+	if c.WriteTimeout > 0 {
+		concern.WTimeout = c.WriteTimeout
+	} else if !fromURI {
+		concern.WTimeout = defaults.WriteTimeout
+	}
+
+	if c.Journal != nil {
+		journal := *c.Journal
+		concern.Journal = &journal
+	} else if !fromURI && acknowledged(concern) {
+		concern.Journal = defaults.Journal
+	}
+
+	return concern, nil
+}
+
+// acknowledged reports whether a write concern waits for any node (w is not 0)
+func acknowledged(w *writeconcern.WriteConcern) bool {
+	n, ok := w.W.(int)
+	return !ok || n != 0
+}
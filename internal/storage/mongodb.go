@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
@@ -19,23 +20,50 @@ type MongoStorage struct {
 	cases      *mongo.Collection
 	judges     *mongo.Collection
 	citations  *mongo.Collection
+	fullTexts  *mongo.Collection
+	explainer  *SlowQueryExplainer
 }
 
-// NewMongoStorage creates a new MongoDB storage adapter
+// SetSlowQueryExplainer enables query plan logging for slow list and search queries
+func (ms *MongoStorage) SetSlowQueryExplainer(explainer *SlowQueryExplainer) {
+	ms.explainer = explainer
+}
+
+// NewMongoStorage creates a new MongoDB storage adapter configured by the URI,
+// with defaults for the options it doesn't set
 func NewMongoStorage(uri, dbName string) (*MongoStorage, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	return NewMongoStorageWithConfig(uri, dbName, MongoConfig{ConnectRetry: DefaultConnectRetry()})
+}
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+// NewMongoStorageWithConfig creates a new MongoDB storage adapter with the given
+// read preference, read/write concerns and timeouts
+func NewMongoStorageWithConfig(uri, dbName string, config MongoConfig) (*MongoStorage, error) {
+	clientOpts, err := config.ClientOptions(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MongoDB configuration: %w", err)
+	}
+
+	connectTimeout := *clientOpts.ConnectTimeout
+
+	client, err := mongo.Connect(context.Background(), clientOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
 	}
 
-	// Ping to verify connection
-	if err := client.Ping(ctx, nil); err != nil {
+	// Ping to verify connection, each attempt bounded by the connect timeout
+	ping := func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+		return client.Ping(ctx, nil)
+	}
+	if err := config.ConnectRetry.connect(context.Background(), "MongoDB", ping); err != nil {
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %w", err)
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
 	database := client.Database(dbName)
 
 	storage := &MongoStorage{
@@ -44,10 +72,11 @@ func NewMongoStorage(uri, dbName string) (*MongoStorage, error) {
 		cases:     database.Collection("cases"),
 		judges:    database.Collection("judges"),
 		citations: database.Collection("citations"),
+		fullTexts: database.Collection("case_full_text"),
 	}
 
 	// Create indexes
-	if err := storage.createIndexes(ctx); err != nil {
+	if err := storage.createIndexes(ctx, config.TextIndexModel(), config.FullTextIndexModel()); err != nil {
 		return nil, fmt.Errorf("failed to create indexes: %w", err)
 	}
 
@@ -55,7 +84,7 @@ func NewMongoStorage(uri, dbName string) (*MongoStorage, error) {
 }
 
 // createIndexes creates necessary indexes
-func (ms *MongoStorage) createIndexes(ctx context.Context) error {
+func (ms *MongoStorage) createIndexes(ctx context.Context, textIndex, fullTextIndex mongo.IndexModel) error {
 	// Cases indexes
 	caseIndexes := []mongo.IndexModel{
 		{
@@ -75,12 +104,16 @@ func (ms *MongoStorage) createIndexes(ctx context.Context) error {
 			Keys: bson.D{{Key: "status", Value: 1}},
 		},
 		{
-			// Text index for full-text search
-			Keys: bson.D{
-				{Key: "case_name", Value: "text"},
-				{Key: "summary", Value: "text"},
-				{Key: "full_text", Value: "text"},
-			},
+			Keys: bson.D{{Key: "case_name", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "case_number", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "legal_concepts", Value: 1}},
+		},
+		{
+			Keys: bson.D{{Key: "judges", Value: 1}},
 		},
 	}
 
@@ -89,6 +122,49 @@ func (ms *MongoStorage) createIndexes(ctx context.Context) error {
 		return fmt.Errorf("failed to create case indexes: %w", err)
 	}
 
+	// Text index for full-text search
+	if err := ms.ensureTextIndex(ctx, ms.cases, textIndex); err != nil {
+		return fmt.Errorf("failed to create case text index: %w", err)
+	}
+
+	// Full text is kept apart from case metadata, one document per case
+	_, err = ms.fullTexts.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "case_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create full text indexes: %w", err)
+	}
+	if err := ms.ensureTextIndex(ctx, ms.fullTexts, fullTextIndex); err != nil {
+		return fmt.Errorf("failed to create full text index: %w", err)
+	}
+
+	// Saved search alerts are recorded once per search and case
+	_, err = ms.database.Collection("saved_search_alerts").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "search_id", Value: 1}, {Key: "case_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create saved search alert indexes: %w", err)
+	}
+
+	// Status changes are read back per case, oldest first
+	_, err = ms.database.Collection("case_status_changes").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "case_id", Value: 1}, {Key: "at", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create status change indexes: %w", err)
+	}
+
+	// One copy is kept of each earlier version of a case
+	_, err = ms.database.Collection("case_versions").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "case_id", Value: 1}, {Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create case version indexes: %w", err)
+	}
+
 	// Judges indexes
 	judgeIndexes := []mongo.IndexModel{
 		{
@@ -132,6 +208,49 @@ func (ms *MongoStorage) createIndexes(ctx context.Context) error {
 	return nil
 }
 
+// ensureTextIndex creates a collection's text index, rebuilding an existing
+// one whose name or language settings differ. A collection holds at most one
+// text index, so a changed language can't be created alongside the old index.
+func (ms *MongoStorage) ensureTextIndex(ctx context.Context, coll *mongo.Collection, model mongo.IndexModel) error {
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return err
+	}
+	var existing []bson.M
+	if err := cursor.All(ctx, &existing); err != nil {
+		return err
+	}
+
+	for _, index := range existing {
+		key, ok := index["key"].(bson.M)
+		if !ok || key["_fts"] != "text" {
+			continue
+		}
+		if TextIndexMatches(index, model) {
+			return nil
+		}
+		name, _ := index["name"].(string)
+		if _, err := coll.Indexes().DropOne(ctx, name); err != nil {
+			return fmt.Errorf("failed to drop text index %s: %w", name, err)
+		}
+	}
+
+	_, err = coll.Indexes().CreateOne(ctx, model)
+	return err
+}
+
+// TextIndexMatches reports whether an index specification, as listed by the
+// server, has the name and language settings of model
+func TextIndexMatches(index bson.M, model mongo.IndexModel) bool {
+	opts := model.Options
+	if opts == nil || opts.Name == nil || opts.DefaultLanguage == nil || opts.LanguageOverride == nil {
+		return false
+	}
+	return index["name"] == *opts.Name &&
+		index["default_language"] == *opts.DefaultLanguage &&
+		index["language_override"] == *opts.LanguageOverride
+}
+
 // Close closes the database connection
 func (ms *MongoStorage) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -139,14 +258,48 @@ func (ms *MongoStorage) Close() error {
 	return ms.client.Disconnect(ctx)
 }
 
-// SaveCase saves or updates a case
+// SaveCase saves a case, replacing any existing case with the same ID
 func (ms *MongoStorage) SaveCase(ctx context.Context, c *models.Case) error {
 	filter := bson.M{"id": c.ID}
-	update := bson.M{"$set": c}
+	update := bson.M{"$set": newMongoCase(c)}
 	opts := options.Update().SetUpsert(true)
 
-	_, err := ms.cases.UpdateOne(ctx, filter, update, opts)
-	return err
+	if _, err := ms.cases.UpdateOne(ctx, filter, update, opts); err != nil {
+		return err
+	}
+	return ms.saveFullTexts(ctx, c)
+}
+
+// CreateCase saves a new case, failing if the ID is already taken
+func (ms *MongoStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	_, err := ms.cases.InsertOne(ctx, newMongoCase(c))
+	if mongo.IsDuplicateKeyError(err) {
+		return errors.StorageError("case already exists", errors.ErrAlreadyExists)
+	}
+	if err != nil {
+		return err
+	}
+	return ms.saveFullTexts(ctx, c)
+}
+
+// SaveCases saves or updates cases in a single bulk write
+func (ms *MongoStorage) SaveCases(ctx context.Context, cases []*models.Case) error {
+	if len(cases) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(cases))
+	for _, c := range cases {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": c.ID}).
+			SetUpdate(bson.M{"$set": newMongoCase(c)}).
+			SetUpsert(true))
+	}
+
+	if _, err := ms.cases.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+		return err
+	}
+	return ms.saveFullTexts(ctx, cases...)
 }
 
 // GetCase retrieves a case by ID
@@ -157,11 +310,14 @@ func (ms *MongoStorage) GetCase(ctx context.Context, id string) (*models.Case, e
 	err := ms.cases.FindOne(ctx, filter).Decode(&c)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("case not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("case not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
 
+	if err := ms.hydrateFullTexts(ctx, []*models.Case{&c}); err != nil {
+		return nil, err
+	}
 	return &c, nil
 }
 
@@ -183,40 +339,22 @@ func (ms *MongoStorage) DeleteCase(ctx context.Context, id string) error {
 		return fmt.Errorf("case not found: %s", id)
 	}
 
+	if _, err := ms.fullTexts.DeleteOne(ctx, bson.M{"case_id": id}); err != nil {
+		return fmt.Errorf("failed to delete full text: %w", err)
+	}
 	return nil
 }
 
 // ListCases lists cases with filtering
 func (ms *MongoStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
-	query := bson.M{}
-
-	if filter.Jurisdiction != "" {
-		query["jurisdiction"] = filter.Jurisdiction
-	}
-	if filter.Court != "" {
-		query["court"] = filter.Court
-	}
-	if filter.CourtLevel != nil {
-		query["court_level"] = *filter.CourtLevel
-	}
-	if filter.Status != "" {
-		query["status"] = filter.Status
-	}
-	if filter.StartDate != nil || filter.EndDate != nil {
-		dateQuery := bson.M{}
-		if filter.StartDate != nil {
-			dateQuery["$gte"] = filter.StartDate
-		}
-		if filter.EndDate != nil {
-			dateQuery["$lte"] = filter.EndDate
-		}
-		query["decision_date"] = dateQuery
-	}
+	query := mongoCaseConditions(filter)
 
 	// Options
 	opts := options.Find()
 
-	if filter.OrderBy != "" {
+	if filter.OrderBy == OrderByAuthority {
+		opts.SetSort(mongoAuthoritySort(false))
+	} else if filter.OrderBy != "" {
 		sortOrder := 1
 		if filter.OrderDesc {
 			sortOrder = -1
@@ -233,6 +371,10 @@ func (ms *MongoStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*mo
 		opts.SetSkip(int64(filter.Offset))
 	}
 
+	defer ms.explainer.Observe(ctx, "mongo.ListCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainMongoFind(ctx, ms.cases, query, opts)
+	})
+
 	cursor, err := ms.cases.Find(ctx, query, opts)
 	if err != nil {
 		return nil, err
@@ -244,23 +386,17 @@ func (ms *MongoStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*mo
 		return nil, err
 	}
 
+	if filter.IncludeFullText {
+		if err := ms.hydrateFullTexts(ctx, cases); err != nil {
+			return nil, err
+		}
+	}
 	return cases, nil
 }
 
 // CountCases counts cases matching filter
 func (ms *MongoStorage) CountCases(ctx context.Context, filter CaseFilter) (int64, error) {
-	query := bson.M{}
-
-	if filter.Jurisdiction != "" {
-		query["jurisdiction"] = filter.Jurisdiction
-	}
-	if filter.Court != "" {
-		query["court"] = filter.Court
-	}
-	if filter.Status != "" {
-		query["status"] = filter.Status
-	}
-
+	query := mongoCaseConditions(filter)
 	return ms.cases.CountDocuments(ctx, query)
 }
 
@@ -282,7 +418,7 @@ func (ms *MongoStorage) GetJudge(ctx context.Context, id string) (*models.Judge,
 	err := ms.judges.FindOne(ctx, filter).Decode(&j)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("judge not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("judge not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -308,6 +444,9 @@ func (ms *MongoStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]*
 	if filter.Jurisdiction != "" {
 		query["jurisdiction"] = filter.Jurisdiction
 	}
+	if filter.TenantScope != nil {
+		query["tenant_id"] = mongoTenantScope(*filter.TenantScope)
+	}
 
 	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
 
@@ -334,25 +473,29 @@ func (ms *MongoStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]*
 
 // SaveCitation saves a citation
 func (ms *MongoStorage) SaveCitation(ctx context.Context, c *models.Citation) error {
-	// Generate ObjectID for new citations
-	if c.ID == 0 {
-		result, err := ms.citations.InsertOne(ctx, c)
-		if err != nil {
+	// Generate ObjectID for new citations; its hex form is the citation ID
+	if c.ID == "" {
+		oid := primitive.NewObjectID()
+		c.ID = oid.Hex()
+
+		if _, err := ms.citations.InsertOne(ctx, mongoCitation{ObjectID: oid, Citation: *c}); err != nil {
+			c.ID = ""
 			return err
 		}
-		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-			// Convert ObjectID to int (simplified - in production use string IDs)
-			c.ID = int(oid.Timestamp().Unix())
-		}
 		return nil
 	}
 
 	// Update existing citation
-	filter := bson.M{"_id": primitive.ObjectID{}}
+	oid, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return fmt.Errorf("invalid citation ID: %s", c.ID)
+	}
+
+	filter := bson.M{"_id": oid}
 	update := bson.M{"$set": c}
 	opts := options.Update().SetUpsert(true)
 
-	_, err := ms.citations.UpdateOne(ctx, filter, update, opts)
+	_, err = ms.citations.UpdateOne(ctx, filter, update, opts)
 	return err
 }
 
@@ -364,17 +507,17 @@ func (ms *MongoStorage) GetCitation(ctx context.Context, id string) (*models.Cit
 	}
 
 	filter := bson.M{"_id": objID}
-	var c models.Citation
+	var doc mongoCitation
 
-	err = ms.citations.FindOne(ctx, filter).Decode(&c)
+	err = ms.citations.FindOne(ctx, filter).Decode(&doc)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("citation not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("citation not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
 
-	return &c, nil
+	return doc.toModel(), nil
 }
 
 // ListCitations lists citations with filtering
@@ -387,12 +530,21 @@ func (ms *MongoStorage) ListCitations(ctx context.Context, filter CitationFilter
 			{"cited_case_id": filter.CaseID},
 		}
 	}
+	if len(filter.CaseIDs) > 0 {
+		query["$and"] = []bson.M{{"$or": []bson.M{
+			{"citing_case_id": bson.M{"$in": filter.CaseIDs}},
+			{"cited_case_id": bson.M{"$in": filter.CaseIDs}},
+		}}}
+	}
 	if filter.Format != "" {
 		query["format"] = filter.Format
 	}
 	if filter.Valid != nil {
 		query["is_normalized"] = *filter.Valid
 	}
+	if filter.TenantScope != nil {
+		query["tenant_id"] = mongoTenantScope(*filter.TenantScope)
+	}
 
 	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
 
@@ -409,39 +561,55 @@ func (ms *MongoStorage) ListCitations(ctx context.Context, filter CitationFilter
 	}
 	defer cursor.Close(ctx)
 
-	var citations []*models.Citation
-	if err := cursor.All(ctx, &citations); err != nil {
+	var docs []mongoCitation
+	if err := cursor.All(ctx, &docs); err != nil {
 		return nil, err
 	}
 
+	citations := make([]*models.Citation, 0, len(docs))
+	for i := range docs {
+		citations = append(citations, docs[i].toModel())
+	}
+
 	return citations, nil
 }
 
+// mongoCitation is the stored form of a citation, keyed by ObjectID
+type mongoCitation struct {
+	ObjectID        primitive.ObjectID `bson:"_id"`
+	models.Citation `bson:",inline"`
+}
+
+// toModel returns the citation with its ID set from the ObjectID
+func (d *mongoCitation) toModel() *models.Citation {
+	c := d.Citation
+	c.ID = d.ObjectID.Hex()
+	return &c
+}
+
 // SearchCases performs full-text search on cases
 func (ms *MongoStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
-	filter := bson.M{
-		"$text": bson.M{
-			"$search": query.Query,
-		},
-	}
-
-	// Additional filters
-	if query.Filters.Jurisdiction != "" {
-		filter["jurisdiction"] = query.Filters.Jurisdiction
-	}
-	if query.Filters.Court != "" {
-		filter["court"] = query.Filters.Court
+	// Name and summary match through the case text index, full text
+	// through its own collection's, filtered as the aggregations are
+	filter, err := ms.searchMatch(ctx, query)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := options.Find()
 
 	// Sort by text score for relevance
-	opts.SetProjection(bson.M{
-		"score": bson.M{"$meta": "textScore"},
-	})
-	opts.SetSort(bson.M{
-		"score": bson.M{"$meta": "textScore"},
-	})
+	if query.Query != "" {
+		opts.SetProjection(bson.M{
+			"score": bson.M{"$meta": "textScore"},
+		})
+		opts.SetSort(bson.M{
+			"score": bson.M{"$meta": "textScore"},
+		})
+	}
+	if query.Filters.OrderBy == OrderByAuthority {
+		opts.SetSort(mongoAuthoritySort(query.Query != ""))
+	}
 
 	if query.Limit > 0 {
 		opts.SetLimit(int64(query.Limit))
@@ -450,6 +618,10 @@ func (ms *MongoStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*
 		opts.SetSkip(int64(query.Offset))
 	}
 
+	defer ms.explainer.Observe(ctx, "mongo.SearchCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainMongoFind(ctx, ms.cases, filter, opts)
+	})
+
 	cursor, err := ms.cases.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
@@ -461,6 +633,11 @@ func (ms *MongoStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*
 		return nil, err
 	}
 
+	if query.Filters.IncludeFullText {
+		if err := ms.hydrateFullTexts(ctx, cases); err != nil {
+			return nil, err
+		}
+	}
 	return cases, nil
 }
 
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// OrderByAuthority orders cases by the authority of the court that decided
+// them, highest first, then by recency. Backends order by the stored court
+// level, which scraping sets from jurisdiction.CourtHierarchy, so the
+// ordering pages like any other.
+const OrderByAuthority = "authority"
+
+// sqlAuthorityOrder returns the ORDER BY terms of OrderByAuthority over
+// table's columns. Undated cases sort after dated ones at their level.
+func sqlAuthorityOrder(table string) string {
+	return table + ".court_level, " + table + ".decision_date IS NULL, " + table + ".decision_date DESC"
+}
+
+// mongoAuthoritySort returns the sort document of OrderByAuthority, with
+// the text score breaking ties when the search has a query
+func mongoAuthoritySort(byScore bool) bson.D {
+	order := bson.D{{Key: "court_level", Value: 1}, {Key: "decision_date", Value: -1}}
+	if byScore {
+		order = append(order, bson.E{Key: "score", Value: bson.M{"$meta": "textScore"}})
+	}
+	return append(order, bson.E{Key: "id", Value: 1})
+}
+
+// sortCasesByAuthority sorts cases in OrderByAuthority order, ties by ID
+func sortCasesByAuthority(cases []*models.Case) {
+	sort.Slice(cases, func(i, j int) bool {
+		a, b := cases[i], cases[j]
+		if a.CourtLevel != b.CourtLevel {
+			return a.CourtLevel < b.CourtLevel
+		}
+		switch {
+		case a.DecisionDate != nil && b.DecisionDate != nil && !a.DecisionDate.Equal(*b.DecisionDate):
+			return a.DecisionDate.After(*b.DecisionDate)
+		case (a.DecisionDate == nil) != (b.DecisionDate == nil):
+			return a.DecisionDate != nil
+		}
+		return a.ID < b.ID
+	})
+}
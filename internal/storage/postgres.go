@@ -4,34 +4,77 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
+	"strconv"
 	"time"
 
 	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
 )
 
 // PostgresStorage implements the Storage interface using PostgreSQL
 type PostgresStorage struct {
-	db *sql.DB
+	db        *sql.DB
+	explainer *SlowQueryExplainer
 }
 
-// NewPostgresStorage creates a new PostgreSQL storage adapter
+// SetSlowQueryExplainer enables query plan logging for slow list and search queries
+func (ps *PostgresStorage) SetSlowQueryExplainer(explainer *SlowQueryExplainer) {
+	ps.explainer = explainer
+}
+
+// PostgresConfig holds PostgreSQL connection pool and startup options
+type PostgresConfig struct {
+	// Driver is the database/sql driver name
+	Driver string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// ConnectRetry retries the initial ping while the database comes up
+	ConnectRetry ConnectRetry
+}
+
+// DefaultPostgresConfig returns the default PostgreSQL configuration
+func DefaultPostgresConfig() PostgresConfig {
+	return PostgresConfig{
+		Driver:          "postgres",
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnectRetry:    DefaultConnectRetry(),
+	}
+}
+
+// NewPostgresStorage creates a new PostgreSQL storage adapter with the default configuration
 func NewPostgresStorage(connStr string) (*PostgresStorage, error) {
-	db, err := sql.Open("postgres", connStr)
+	return NewPostgresStorageWithConfig(connStr, DefaultPostgresConfig())
+}
+
+// NewPostgresStorageWithConfig creates a new PostgreSQL storage adapter with
+// the given pool settings, retrying the initial connection as configured
+func NewPostgresStorageWithConfig(connStr string, config PostgresConfig) (*PostgresStorage, error) {
+	driver := config.Driver
+	if driver == "" {
+		driver = "postgres"
+	}
+
+	db, err := sql.Open(driver, connStr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	// Test connection
-	if err := db.Ping(); err != nil {
+	if err := config.ConnectRetry.connect(context.Background(), "PostgreSQL", db.PingContext); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	// Set connection pool settings
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
 
 	storage := &PostgresStorage{db: db}
 
@@ -59,12 +102,18 @@ func (ps *PostgresStorage) initSchema() error {
 		court TEXT,
 		court_level INTEGER,
 		court_type TEXT,
+		court_id TEXT,
 		jurisdiction TEXT,
 		docket TEXT,
 		parties JSONB,
 		judges JSONB,
 		summary TEXT,
-		full_text TEXT,
+		holding TEXT,
+		lower_court_case_id TEXT,
+		appealed_to_case_id TEXT,
+		extraction_version INTEGER,
+		content_hash TEXT,
+		tenant_id TEXT,
 		key_issues JSONB,
 		legal_concepts JSONB,
 		outcome TEXT,
@@ -82,8 +131,18 @@ func (ps *PostgresStorage) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_cases_jurisdiction ON cases(jurisdiction);
 	CREATE INDEX IF NOT EXISTS idx_cases_court ON cases(court);
+	CREATE INDEX IF NOT EXISTS idx_cases_court_id ON cases(court_id);
+	CREATE INDEX IF NOT EXISTS idx_cases_case_number ON cases(case_number);
 	CREATE INDEX IF NOT EXISTS idx_cases_decision_date ON cases(decision_date);
 	CREATE INDEX IF NOT EXISTS idx_cases_case_name ON cases(case_name);
+	CREATE INDEX IF NOT EXISTS idx_cases_case_name_prefix ON cases(lower(case_name) text_pattern_ops);
+	CREATE INDEX IF NOT EXISTS idx_cases_judges ON cases USING GIN (judges);
+
+	-- Full text is kept apart so that listing cases reads only their metadata
+	CREATE TABLE IF NOT EXISTS case_full_text (
+		case_id TEXT PRIMARY KEY REFERENCES cases(id) ON DELETE CASCADE,
+		full_text TEXT
+	);
 
 	CREATE TABLE IF NOT EXISTS judges (
 		id TEXT PRIMARY KEY,
@@ -98,10 +157,12 @@ func (ps *PostgresStorage) initSchema() error {
 		career JSONB,
 		notable_cases JSONB,
 		total_cases INTEGER DEFAULT 0,
+		tenant_id TEXT,
 		created_at TIMESTAMP DEFAULT NOW()
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_judges_name ON judges(name);
+	CREATE INDEX IF NOT EXISTS idx_judges_name_prefix ON judges(lower(name) text_pattern_ops);
 	CREATE INDEX IF NOT EXISTS idx_judges_court ON judges(court);
 
 	CREATE TABLE IF NOT EXISTS citations (
@@ -119,68 +180,127 @@ func (ps *PostgresStorage) initSchema() error {
 		citing_case_id TEXT,
 		cited_case_id TEXT,
 		is_normalized BOOLEAN DEFAULT FALSE,
+		tenant_id TEXT,
 		created_at TIMESTAMP DEFAULT NOW()
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_citations_citing_case ON citations(citing_case_id);
 	CREATE INDEX IF NOT EXISTS idx_citations_cited_case ON citations(cited_case_id);
-	`
+	` + sqlAnnotationsSchema + sqlSavedSearchesSchema + sqlStatusChangesSchema + sqlCaseVersionsSchema
 
 	_, err := ps.db.Exec(schema)
 	return err
 }
 
-// CreateCase creates a new case
+// postgresUniqueViolation is the SQLSTATE of a unique constraint violation
+const postgresUniqueViolation = "23505"
+
+// postgresCaseUpsert updates every column of an existing case row in place
+const postgresCaseUpsert = `
+		ON CONFLICT (id) DO UPDATE SET
+			case_number = EXCLUDED.case_number, case_name = EXCLUDED.case_name,
+			decision_date = EXCLUDED.decision_date, court = EXCLUDED.court,
+			court_level = EXCLUDED.court_level, court_type = EXCLUDED.court_type,
+			jurisdiction = EXCLUDED.jurisdiction, docket = EXCLUDED.docket,
+			parties = EXCLUDED.parties, judges = EXCLUDED.judges, summary = EXCLUDED.summary,
+			key_issues = EXCLUDED.key_issues,
+			legal_concepts = EXCLUDED.legal_concepts, outcome = EXCLUDED.outcome,
+			procedural_history = EXCLUDED.procedural_history, citations = EXCLUDED.citations,
+			url = EXCLUDED.url, pdf_url = EXCLUDED.pdf_url, source_database = EXCLUDED.source_database,
+			scraped_at = EXCLUDED.scraped_at, last_updated = EXCLUDED.last_updated,
+			language = EXCLUDED.language, status = EXCLUDED.status, court_id = EXCLUDED.court_id,
+			holding = EXCLUDED.holding, lower_court_case_id = EXCLUDED.lower_court_case_id,
+			appealed_to_case_id = EXCLUDED.appealed_to_case_id,
+			extraction_version = EXCLUDED.extraction_version,
+			content_hash = EXCLUDED.content_hash,
+			tenant_id = EXCLUDED.tenant_id
+	`
+
+// SaveCase saves a case, replacing any existing case with the same ID
+func (ps *PostgresStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	return ps.insertCase(ctx, c, postgresCaseUpsert)
+}
+
+// CreateCase creates a new case, failing if the ID is already taken
 func (ps *PostgresStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	err := ps.insertCase(ctx, c, "")
+	if pgErr, ok := err.(interface{ SQLState() string }); ok && pgErr.SQLState() == postgresUniqueViolation {
+		return errors.StorageError("case already exists", errors.ErrAlreadyExists)
+	}
+	return err
+}
+
+// insertCase inserts a case row and its full text in one transaction, with
+// onConflict appended to the insert
+func (ps *PostgresStorage) insertCase(ctx context.Context, c *models.Case, onConflict string) error {
 	query := `
 		INSERT INTO cases (
 			id, case_number, case_name, decision_date, court, court_level, court_type,
-			jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+			jurisdiction, docket, parties, judges, summary, key_issues,
 			legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-			source_database, scraped_at, last_updated, language, status
+			source_database, scraped_at, last_updated, language, status, court_id, holding,
+			lower_court_case_id, appealed_to_case_id, extraction_version, content_hash, tenant_id
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
-			$19, $20, $21, $22, $23, $24, $25
+			$19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31
 		)
-	`
-
-	_, err := ps.db.ExecContext(ctx, query,
-		c.ID, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
-		c.Jurisdiction, c.Docket, toJSON(c.Parties), toJSON(c.Judges), c.Summary, c.FullText,
-		toJSON(c.KeyIssues), toJSON(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
-		toJSON(c.CitedCases), c.URL, c.PDFURL, c.SourceDatabase, c.ScrapedAt, c.LastUpdated,
-		c.Language, c.Status,
-	)
+	` + onConflict
+
+	return runSQLTx(ctx, ps.db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			c.ID, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
+			c.Jurisdiction, c.Docket, toJSON(c.Parties), toJSON(c.Judges), c.Summary,
+			toJSON(c.KeyIssues), toJSON(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
+			toJSON(c.CitedCases), c.URL, c.PDFURL, c.SourceDatabase, c.ScrapedAt, c.LastUpdated,
+			c.Language, c.Status, c.CourtID, c.Holding, c.LowerCourtCaseID, c.AppealedToCaseID,
+			c.ExtractionVersion, c.ContentHash, c.TenantID,
+		)
+		if err != nil {
+			return err
+		}
 
-	return err
+		return saveSQLFullText(ctx, tx, c, postgresPlaceholder)
+	})
 }
 
-// GetCase retrieves a case by ID
-func (ps *PostgresStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
-	query := `
-		SELECT id, case_number, case_name, decision_date, court, court_level, court_type,
-			jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+// postgresCaseColumns returns the case columns read by scanPostgresCase, with
+// the full text blank unless includeFullText is set
+func postgresCaseColumns(includeFullText bool) string {
+	return `id, case_number, case_name, decision_date, court, court_level, court_type,
+			jurisdiction, docket, parties, judges, summary, ` + sqlFullText("cases.id", includeFullText) + `, key_issues,
 			legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-			source_database, scraped_at, last_updated, language, status
-		FROM cases
-		WHERE id = $1
-	`
+			source_database, scraped_at, last_updated, language, status, court_id, holding,
+			lower_court_case_id, appealed_to_case_id, extraction_version, content_hash, tenant_id`
+}
+
+// postgresCaseOrderColumns are the case columns ListCases can order by
+var postgresCaseOrderColumns = map[string]bool{
+	"id": true, "case_number": true, "case_name": true, "decision_date": true,
+	"court": true, "court_level": true, "jurisdiction": true, "status": true,
+	"scraped_at": true, "last_updated": true, "created_at": true,
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanPostgresCase scans a row selected with postgresCaseColumns
+func scanPostgresCase(row rowScanner) (*models.Case, error) {
 	c := &models.Case{}
 	var decisionDate sql.NullTime
 	var scrapedAt, lastUpdated sql.NullTime
+	var courtID, holding, lowerCourtCaseID, appealedToCaseID, contentHash, tenantID sql.NullString
+	var extractionVersion sql.NullInt64
 	var parties, judges, keyIssues, legalConcepts, citations []byte
 
-	err := ps.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.CourtLevel, &c.CourtType,
 		&c.Jurisdiction, &c.Docket, &parties, &judges, &c.Summary, &c.FullText, &keyIssues,
 		&legalConcepts, &c.Outcome, &c.ProceduralHistory, &citations, &c.URL, &c.PDFURL,
-		&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status,
+		&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &courtID, &holding,
+		&lowerCourtCaseID, &appealedToCaseID, &extractionVersion, &contentHash, &tenantID,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, ErrNotFound
-	}
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +315,13 @@ func (ps *PostgresStorage) GetCase(ctx context.Context, id string) (*models.Case
 	if lastUpdated.Valid {
 		c.LastUpdated = lastUpdated.Time
 	}
+	c.CourtID = courtID.String
+	c.Holding = holding.String
+	c.LowerCourtCaseID = lowerCourtCaseID.String
+	c.AppealedToCaseID = appealedToCaseID.String
+	c.ExtractionVersion = int(extractionVersion.Int64)
+	c.ContentHash = contentHash.String
+	c.TenantID = tenantID.String
 
 	// Parse JSON fields
 	fromJSON(parties, &c.Parties)
@@ -206,40 +333,61 @@ func (ps *PostgresStorage) GetCase(ctx context.Context, id string) (*models.Case
 	return c, nil
 }
 
-// UpdateCase updates an existing case
+// GetCase retrieves a case by ID
+func (ps *PostgresStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	query := `SELECT ` + postgresCaseColumns(true) + ` FROM cases WHERE id = $1`
+
+	c, err := scanPostgresCase(ps.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UpdateCase updates an existing case and its full text in one transaction
 func (ps *PostgresStorage) UpdateCase(ctx context.Context, id string, c *models.Case) error {
 	query := `
 		UPDATE cases SET
 			case_number = $2, case_name = $3, decision_date = $4, court = $5,
 			court_level = $6, court_type = $7, jurisdiction = $8, docket = $9,
-			parties = $10, judges = $11, summary = $12, full_text = $13,
-			key_issues = $14, legal_concepts = $15, outcome = $16,
-			procedural_history = $17, citations = $18, url = $19, pdf_url = $20,
-			source_database = $21, last_updated = $22, language = $23, status = $24
+			parties = $10, judges = $11, summary = $12,
+			key_issues = $13, legal_concepts = $14, outcome = $15,
+			procedural_history = $16, citations = $17, url = $18, pdf_url = $19,
+			source_database = $20, last_updated = $21, language = $22, status = $23,
+			court_id = $24, holding = $25, lower_court_case_id = $26, appealed_to_case_id = $27,
+			extraction_version = $28, content_hash = $29, tenant_id = $30
 		WHERE id = $1
 	`
 
-	result, err := ps.db.ExecContext(ctx, query,
-		id, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
-		c.Jurisdiction, c.Docket, toJSON(c.Parties), toJSON(c.Judges), c.Summary, c.FullText,
-		toJSON(c.KeyIssues), toJSON(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
-		toJSON(c.CitedCases), c.URL, c.PDFURL, c.SourceDatabase, time.Now(), c.Language, c.Status,
-	)
+	return runSQLTx(ctx, ps.db, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			id, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
+			c.Jurisdiction, c.Docket, toJSON(c.Parties), toJSON(c.Judges), c.Summary,
+			toJSON(c.KeyIssues), toJSON(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
+			toJSON(c.CitedCases), c.URL, c.PDFURL, c.SourceDatabase, time.Now(), c.Language, c.Status,
+			c.CourtID, c.Holding, c.LowerCourtCaseID, c.AppealedToCaseID, c.ExtractionVersion,
+			c.ContentHash, c.TenantID,
+		)
 
-	if err != nil {
-		return err
-	}
+		if err != nil {
+			return err
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
 
-	if rowsAffected == 0 {
-		return ErrNotFound
-	}
+		if rowsAffected == 0 {
+			return ErrNotFound
+		}
 
-	return nil
+		c.ID = id
+		return saveSQLFullText(ctx, tx, c, postgresPlaceholder)
+	})
 }
 
 // DeleteCase deletes a case
@@ -263,38 +411,43 @@ func (ps *PostgresStorage) DeleteCase(ctx context.Context, id string) error {
 	return nil
 }
 
-// ListCases lists cases with optional filtering
-func (ps *PostgresStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
-	query := `SELECT id, case_number, case_name, decision_date, court, jurisdiction FROM cases WHERE 1=1`
-	args := []interface{}{}
-	argCount := 1
-
-	// Add filters
-	if filter.Jurisdiction != "" {
-		query += fmt.Sprintf(" AND jurisdiction = $%d", argCount)
-		args = append(args, filter.Jurisdiction)
-		argCount++
-	}
-
-	if filter.Court != "" {
-		query += fmt.Sprintf(" AND court = $%d", argCount)
-		args = append(args, filter.Court)
-		argCount++
+// postgresPage appends LIMIT and OFFSET clauses for a page of results. A zero
+// limit returns every row, as on the other backends.
+func postgresPage(query string, args []interface{}, limit, offset int) (string, []interface{}) {
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
-
-	if filter.StartDate != nil {
-		query += fmt.Sprintf(" AND decision_date >= $%d", argCount)
-		args = append(args, filter.StartDate)
-		argCount++
+	if offset > 0 {
+		args = append(args, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
 	}
+	return query, args
+}
 
-	if filter.EndDate != nil {
-		query += fmt.Sprintf(" AND decision_date <= $%d", argCount)
-		args = append(args, filter.EndDate)
-		argCount++
+// ListCases lists cases with optional filtering
+func (ps *PostgresStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
+	where, args := sqlCaseConditions(filter, "cases", nil, postgresPlaceholder)
+	query := `SELECT ` + postgresCaseColumns(filter.IncludeFullText) + ` FROM cases WHERE ` + where
+
+	// Order and page. The column is interpolated, so only known columns are
+	// accepted; ties fall back to id so pages don't overlap.
+	if filter.OrderBy == OrderByAuthority {
+		query += " ORDER BY " + sqlAuthorityOrder("cases") + ", id"
+	} else if postgresCaseOrderColumns[filter.OrderBy] {
+		direction := "ASC"
+		if filter.OrderDesc {
+			direction = "DESC"
+		}
+		query += fmt.Sprintf(" ORDER BY %s %s, id", filter.OrderBy, direction)
+	} else {
+		query += " ORDER BY decision_date DESC, id"
 	}
+	query, args = postgresPage(query, args, filter.Limit, filter.Offset)
 
-	query += " ORDER BY decision_date DESC LIMIT 100"
+	defer ps.explainer.Observe(ctx, "postgres.ListCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainSQL(ctx, ps.db, "EXPLAIN", query, args)
+	})
 
 	rows, err := ps.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -304,36 +457,30 @@ func (ps *PostgresStorage) ListCases(ctx context.Context, filter CaseFilter) ([]
 
 	cases := make([]*models.Case, 0)
 	for rows.Next() {
-		c := &models.Case{}
-		var decisionDate sql.NullTime
-
-		err := rows.Scan(&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.Jurisdiction)
+		c, err := scanPostgresCase(rows)
 		if err != nil {
-			continue
-		}
-
-		if decisionDate.Valid {
-			c.DecisionDate = &decisionDate.Time
+			return nil, err
 		}
-
 		cases = append(cases, c)
 	}
 
-	return cases, nil
+	return cases, rows.Err()
 }
 
 // SearchCases searches for cases
 func (ps *PostgresStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
-	sqlQuery := `
-		SELECT id, case_number, case_name, decision_date, court, jurisdiction
-		FROM cases
-		WHERE case_name ILIKE $1 OR case_number ILIKE $1 OR full_text ILIKE $1
-		LIMIT 50
-	`
+	where, args := postgresSearchScope(query, "cases")
+	sqlQuery := `SELECT ` + postgresCaseColumns(query.Filters.IncludeFullText) + ` FROM cases WHERE ` + where
+	if query.Filters.OrderBy == OrderByAuthority {
+		sqlQuery += " ORDER BY " + sqlAuthorityOrder("cases") + ", id"
+	}
+	sqlQuery, args = postgresPage(sqlQuery, args, query.Limit, query.Offset)
 
-	searchTerm := "%" + query.Query + "%"
+	defer ps.explainer.Observe(ctx, "postgres.SearchCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainSQL(ctx, ps.db, "EXPLAIN", sqlQuery, args)
+	})
 
-	rows, err := ps.db.QueryContext(ctx, sqlQuery, searchTerm)
+	rows, err := ps.db.QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -341,22 +488,14 @@ func (ps *PostgresStorage) SearchCases(ctx context.Context, query SearchQuery) (
 
 	cases := make([]*models.Case, 0)
 	for rows.Next() {
-		c := &models.Case{}
-		var decisionDate sql.NullTime
-
-		err := rows.Scan(&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.Jurisdiction)
+		c, err := scanPostgresCase(rows)
 		if err != nil {
-			continue
+			return nil, err
 		}
-
-		if decisionDate.Valid {
-			c.DecisionDate = &decisionDate.Time
-		}
-
 		cases = append(cases, c)
 	}
 
-	return cases, nil
+	return cases, rows.Err()
 }
 
 // CreateJudge creates a new judge
@@ -364,13 +503,14 @@ func (ps *PostgresStorage) CreateJudge(ctx context.Context, j *models.Judge) err
 	query := `
 		INSERT INTO judges (
 			id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			biography, education, career, notable_cases, total_cases, tenant_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 	`
 
 	_, err := ps.db.ExecContext(ctx, query,
 		j.ID, j.Name, j.FullName, j.Title, j.Court, j.Jurisdiction, j.AppointedDate,
 		j.Biography, toJSON(j.Education), toJSON(j.Career), toJSON(j.NotableCases), j.TotalCases,
+		j.TenantID,
 	)
 
 	return err
@@ -380,18 +520,19 @@ func (ps *PostgresStorage) CreateJudge(ctx context.Context, j *models.Judge) err
 func (ps *PostgresStorage) GetJudge(ctx context.Context, id string) (*models.Judge, error) {
 	query := `
 		SELECT id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases
+			biography, education, career, notable_cases, total_cases, tenant_id
 		FROM judges
 		WHERE id = $1
 	`
 
 	j := &models.Judge{}
 	var appointedDate sql.NullTime
+	var tenantID sql.NullString
 	var education, career, notableCases []byte
 
 	err := ps.db.QueryRowContext(ctx, query, id).Scan(
 		&j.ID, &j.Name, &j.FullName, &j.Title, &j.Court, &j.Jurisdiction, &appointedDate,
-		&j.Biography, &education, &career, &notableCases, &j.TotalCases,
+		&j.Biography, &education, &career, &notableCases, &j.TotalCases, &tenantID,
 	)
 
 	if err == sql.ErrNoRows {
@@ -401,6 +542,7 @@ func (ps *PostgresStorage) GetJudge(ctx context.Context, id string) (*models.Jud
 		return nil, err
 	}
 
+	j.TenantID = tenantID.String
 	if appointedDate.Valid {
 		j.AppointedDate = &appointedDate.Time
 	}
@@ -418,13 +560,14 @@ func (ps *PostgresStorage) UpdateJudge(ctx context.Context, id string, j *models
 		UPDATE judges SET
 			name = $2, full_name = $3, title = $4, court = $5, jurisdiction = $6,
 			appointed_date = $7, biography = $8, education = $9, career = $10,
-			notable_cases = $11, total_cases = $12
+			notable_cases = $11, total_cases = $12, tenant_id = $13
 		WHERE id = $1
 	`
 
 	result, err := ps.db.ExecContext(ctx, query,
 		id, j.Name, j.FullName, j.Title, j.Court, j.Jurisdiction, j.AppointedDate,
 		j.Biography, toJSON(j.Education), toJSON(j.Career), toJSON(j.NotableCases), j.TotalCases,
+		j.TenantID,
 	)
 
 	if err != nil {
@@ -461,7 +604,14 @@ func (ps *PostgresStorage) ListJudges(ctx context.Context, filter JudgeFilter) (
 		argCount++
 	}
 
-	query += " ORDER BY name LIMIT 100"
+	if filter.TenantScope != nil {
+		query += fmt.Sprintf(" AND COALESCE(tenant_id, '') IN ('', $%d)", argCount)
+		args = append(args, *filter.TenantScope)
+		argCount++
+	}
+
+	query += " ORDER BY name"
+	query, args = postgresPage(query, args, filter.Limit, filter.Offset)
 
 	rows, err := ps.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -487,16 +637,24 @@ func (ps *PostgresStorage) CreateCitation(ctx context.Context, c *models.Citatio
 	query := `
 		INSERT INTO citations (
 			format, raw_citation, normalized_citation, volume, reporter, page,
-			year, court, case_number, country, citing_case_id, cited_case_id, is_normalized
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			year, court, case_number, country, citing_case_id, cited_case_id, is_normalized, tenant_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
 	`
 
-	_, err := ps.db.ExecContext(ctx, query,
+	// Row IDs are exposed as strings to match the other backends
+	var id int64
+	err := ps.db.QueryRowContext(ctx, query,
 		c.Format, c.RawCitation, c.NormalizedCitation, c.Volume, c.Reporter, c.Page,
 		c.Year, c.Court, c.CaseNumber, c.Country, c.CitingCaseID, c.CitedCaseID, c.IsNormalized,
-	)
+		c.TenantID,
+	).Scan(&id)
+	if err != nil {
+		return err
+	}
 
-	return err
+	c.ID = strconv.FormatInt(id, 10)
+	return nil
 }
 
 // GetCitation retrieves a citation by ID
@@ -522,7 +680,21 @@ func (ps *PostgresStorage) ListCitations(ctx context.Context, filter CitationFil
 		argCount++
 	}
 
-	query += " LIMIT 100"
+	if len(filter.CaseIDs) > 0 {
+		var citing, cited string
+		citing, args = sqlIn("citing_case_id", filter.CaseIDs, args, postgresPlaceholder)
+		cited, args = sqlIn("cited_case_id", filter.CaseIDs, args, postgresPlaceholder)
+		query += " AND (" + citing + " OR " + cited + ")"
+		argCount = len(args) + 1
+	}
+
+	if filter.TenantScope != nil {
+		query += fmt.Sprintf(" AND COALESCE(tenant_id, '') IN ('', $%d)", argCount)
+		args = append(args, *filter.TenantScope)
+		argCount++
+	}
+
+	query, args = postgresPage(query, args, filter.Limit, filter.Offset)
 
 	rows, err := ps.db.QueryContext(ctx, query, args...)
 	if err != nil {
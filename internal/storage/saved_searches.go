@@ -0,0 +1,450 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SavedSearchFilter selects one owner's saved searches. An empty UserID lists
+// every user's saved searches, which only the alert job does; handlers always
+// set it.
+type SavedSearchFilter struct {
+	UserID   string
+	TenantID string
+}
+
+// SavedSearchStore is implemented by backends that keep saved searches and
+// the alerts sent for them. SaveSavedSearch is an upsert by ID; saved
+// searches are listed oldest first. RecordAlerts records that cases were
+// alerted for a search and returns those not alerted before, so a case
+// alerts once per search even with several workers running the alert job;
+// ForgetAlerts undoes it for alerts that could not be delivered.
+type SavedSearchStore interface {
+	SaveSavedSearch(ctx context.Context, s *models.SavedSearch) error
+	GetSavedSearch(ctx context.Context, id string) (*models.SavedSearch, error)
+	ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, id string) error
+	RecordAlerts(ctx context.Context, searchID string, caseIDs []string) ([]string, error)
+	ForgetAlerts(ctx context.Context, searchID string, caseIDs []string) error
+}
+
+// SavedSearches returns the saved search store behind store, looking through
+// the tenancy, lifecycle, timeout and write-behind wrappers, and false if the
+// backend keeps no saved searches
+func SavedSearches(store Storage) (SavedSearchStore, bool) {
+	for {
+		if searches, ok := store.(SavedSearchStore); ok {
+			return searches, true
+		}
+		switch s := store.(type) {
+		case *TenantStorage:
+			store = s.Storage
+		case *LifecycleStorage:
+			store = s.Storage
+		case *TimeoutStorage:
+			store = s.Storage
+		case *WriteBehindStorage:
+			store = s.Storage
+		default:
+			return nil, false
+		}
+	}
+}
+
+// savedSearchNotFound reports a missing saved search
+func savedSearchNotFound(id string) error {
+	return errors.StorageError(fmt.Sprintf("saved search not found: %s", id), errors.ErrNotFound)
+}
+
+// sqlSavedSearchesSchema creates the saved search and alert tables, shared by
+// the SQL backends. Filters are kept as JSON.
+const sqlSavedSearchesSchema = `
+	CREATE TABLE IF NOT EXISTS saved_searches (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		tenant_id TEXT NOT NULL DEFAULT '',
+		name TEXT NOT NULL DEFAULT '',
+		query TEXT NOT NULL DEFAULT '',
+		filters TEXT,
+		webhook_url TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_saved_searches_owner ON saved_searches(user_id, tenant_id);
+
+	CREATE TABLE IF NOT EXISTS saved_search_alerts (
+		search_id TEXT NOT NULL,
+		case_id TEXT NOT NULL,
+		alerted_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (search_id, case_id)
+	);
+`
+
+const sqlSavedSearchColumns = `id, user_id, tenant_id, name, query, filters, webhook_url, created_at, updated_at`
+
+// saveSQLSavedSearch upserts a saved search
+func saveSQLSavedSearch(ctx context.Context, db *sql.DB, s *models.SavedSearch, placeholder func(int) string) error {
+	filters, err := json.Marshal(s.Filters)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO saved_searches (%s)
+		VALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name, query = excluded.query, filters = excluded.filters,
+			webhook_url = excluded.webhook_url, updated_at = excluded.updated_at
+	`, sqlSavedSearchColumns, placeholder(1), placeholder(2), placeholder(3), placeholder(4),
+		placeholder(5), placeholder(6), placeholder(7), placeholder(8), placeholder(9))
+
+	_, err = db.ExecContext(ctx, query, s.ID, s.UserID, s.TenantID, s.Name, s.Query, string(filters),
+		s.WebhookURL, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+// scanSQLSavedSearch reads a saved search row selected with sqlSavedSearchColumns
+func scanSQLSavedSearch(scan func(dest ...interface{}) error) (*models.SavedSearch, error) {
+	var s models.SavedSearch
+	var filters sql.NullString
+	if err := scan(&s.ID, &s.UserID, &s.TenantID, &s.Name, &s.Query, &filters, &s.WebhookURL, &s.CreatedAt, &s.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if filters.Valid && filters.String != "" {
+		if err := json.Unmarshal([]byte(filters.String), &s.Filters); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// getSQLSavedSearch reads one saved search by ID
+func getSQLSavedSearch(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) (*models.SavedSearch, error) {
+	query := fmt.Sprintf(`SELECT %s FROM saved_searches WHERE id = %s`, sqlSavedSearchColumns, placeholder(1))
+	s, err := scanSQLSavedSearch(db.QueryRowContext(ctx, query, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, savedSearchNotFound(id)
+	}
+	return s, err
+}
+
+// listSQLSavedSearches reads one owner's saved searches, or everyone's
+func listSQLSavedSearches(ctx context.Context, db *sql.DB, filter SavedSearchFilter, placeholder func(int) string) ([]*models.SavedSearch, error) {
+	query := fmt.Sprintf(`SELECT %s FROM saved_searches`, sqlSavedSearchColumns)
+	var args []interface{}
+	if filter.UserID != "" {
+		query += fmt.Sprintf(` WHERE user_id = %s AND tenant_id = %s`, placeholder(1), placeholder(2))
+		args = append(args, filter.UserID, filter.TenantID)
+	}
+	query += ` ORDER BY created_at, id`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	searches := make([]*models.SavedSearch, 0)
+	for rows.Next() {
+		s, err := scanSQLSavedSearch(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, s)
+	}
+	return searches, rows.Err()
+}
+
+// deleteSQLSavedSearch deletes one saved search by ID with its alert records
+func deleteSQLSavedSearch(ctx context.Context, db *sql.DB, id string, placeholder func(int) string) error {
+	result, err := db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = `+placeholder(1), id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return savedSearchNotFound(id)
+	}
+	_, err = db.ExecContext(ctx, `DELETE FROM saved_search_alerts WHERE search_id = `+placeholder(1), id)
+	return err
+}
+
+// recordSQLAlerts inserts alert records, returning the cases whose record
+// did not exist yet
+func recordSQLAlerts(ctx context.Context, db *sql.DB, searchID string, caseIDs []string, placeholder func(int) string) ([]string, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO saved_search_alerts (search_id, case_id, alerted_at)
+		VALUES (%s, %s, %s)
+		ON CONFLICT(search_id, case_id) DO NOTHING
+	`, placeholder(1), placeholder(2), placeholder(3))
+
+	now := time.Now().UTC()
+	recorded := make([]string, 0, len(caseIDs))
+	for _, caseID := range caseIDs {
+		result, err := db.ExecContext(ctx, query, searchID, caseID, now)
+		if err != nil {
+			return recorded, err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 1 {
+			recorded = append(recorded, caseID)
+		}
+	}
+	return recorded, nil
+}
+
+// forgetSQLAlerts deletes alert records
+func forgetSQLAlerts(ctx context.Context, db *sql.DB, searchID string, caseIDs []string, placeholder func(int) string) error {
+	query := fmt.Sprintf(`DELETE FROM saved_search_alerts WHERE search_id = %s AND case_id = %s`, placeholder(1), placeholder(2))
+	for _, caseID := range caseIDs {
+		if _, err := db.ExecContext(ctx, query, searchID, caseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveSavedSearch saves a saved search, replacing any with the same ID
+func (ss *SQLiteStorage) SaveSavedSearch(ctx context.Context, s *models.SavedSearch) error {
+	return saveSQLSavedSearch(ctx, ss.db, s, positionalPlaceholder)
+}
+
+// GetSavedSearch retrieves a saved search by ID
+func (ss *SQLiteStorage) GetSavedSearch(ctx context.Context, id string) (*models.SavedSearch, error) {
+	return getSQLSavedSearch(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// ListSavedSearches lists one owner's saved searches, or everyone's
+func (ss *SQLiteStorage) ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]*models.SavedSearch, error) {
+	return listSQLSavedSearches(ctx, ss.db, filter, positionalPlaceholder)
+}
+
+// DeleteSavedSearch deletes a saved search and its alert records
+func (ss *SQLiteStorage) DeleteSavedSearch(ctx context.Context, id string) error {
+	return deleteSQLSavedSearch(ctx, ss.db, id, positionalPlaceholder)
+}
+
+// RecordAlerts records alerts for a saved search, returning the new ones
+func (ss *SQLiteStorage) RecordAlerts(ctx context.Context, searchID string, caseIDs []string) ([]string, error) {
+	return recordSQLAlerts(ctx, ss.db, searchID, caseIDs, positionalPlaceholder)
+}
+
+// ForgetAlerts deletes alert records so the cases can alert again
+func (ss *SQLiteStorage) ForgetAlerts(ctx context.Context, searchID string, caseIDs []string) error {
+	return forgetSQLAlerts(ctx, ss.db, searchID, caseIDs, positionalPlaceholder)
+}
+
+// SaveSavedSearch saves a saved search, replacing any with the same ID
+func (ps *PostgresStorage) SaveSavedSearch(ctx context.Context, s *models.SavedSearch) error {
+	return saveSQLSavedSearch(ctx, ps.db, s, postgresPlaceholder)
+}
+
+// GetSavedSearch retrieves a saved search by ID
+func (ps *PostgresStorage) GetSavedSearch(ctx context.Context, id string) (*models.SavedSearch, error) {
+	return getSQLSavedSearch(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// ListSavedSearches lists one owner's saved searches, or everyone's
+func (ps *PostgresStorage) ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]*models.SavedSearch, error) {
+	return listSQLSavedSearches(ctx, ps.db, filter, postgresPlaceholder)
+}
+
+// DeleteSavedSearch deletes a saved search and its alert records
+func (ps *PostgresStorage) DeleteSavedSearch(ctx context.Context, id string) error {
+	return deleteSQLSavedSearch(ctx, ps.db, id, postgresPlaceholder)
+}
+
+// RecordAlerts records alerts for a saved search, returning the new ones
+func (ps *PostgresStorage) RecordAlerts(ctx context.Context, searchID string, caseIDs []string) ([]string, error) {
+	return recordSQLAlerts(ctx, ps.db, searchID, caseIDs, postgresPlaceholder)
+}
+
+// ForgetAlerts deletes alert records so the cases can alert again
+func (ps *PostgresStorage) ForgetAlerts(ctx context.Context, searchID string, caseIDs []string) error {
+	return forgetSQLAlerts(ctx, ps.db, searchID, caseIDs, postgresPlaceholder)
+}
+
+// SaveSavedSearch saves a copy of a saved search, replacing any with the same ID
+func (ms *MemoryStorage) SaveSavedSearch(ctx context.Context, s *models.SavedSearch) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	stored := *s
+	stored.Filters.Concepts = append([]string(nil), s.Filters.Concepts...)
+	ms.savedSearches[s.ID] = &stored
+	return nil
+}
+
+// GetSavedSearch retrieves a copy of a saved search by ID
+func (ms *MemoryStorage) GetSavedSearch(ctx context.Context, id string) (*models.SavedSearch, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	s, ok := ms.savedSearches[id]
+	if !ok {
+		return nil, savedSearchNotFound(id)
+	}
+	copied := *s
+	return &copied, nil
+}
+
+// ListSavedSearches lists one owner's saved searches, or everyone's
+func (ms *MemoryStorage) ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]*models.SavedSearch, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	searches := make([]*models.SavedSearch, 0)
+	for _, s := range ms.savedSearches {
+		if filter.UserID == "" || (s.UserID == filter.UserID && s.TenantID == filter.TenantID) {
+			copied := *s
+			searches = append(searches, &copied)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool {
+		if !searches[i].CreatedAt.Equal(searches[j].CreatedAt) {
+			return searches[i].CreatedAt.Before(searches[j].CreatedAt)
+		}
+		return searches[i].ID < searches[j].ID
+	})
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes a saved search and its alert records
+func (ms *MemoryStorage) DeleteSavedSearch(ctx context.Context, id string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, ok := ms.savedSearches[id]; !ok {
+		return savedSearchNotFound(id)
+	}
+	delete(ms.savedSearches, id)
+	delete(ms.alerts, id)
+	return nil
+}
+
+// RecordAlerts records alerts for a saved search, returning the new ones
+func (ms *MemoryStorage) RecordAlerts(ctx context.Context, searchID string, caseIDs []string) ([]string, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	alerted, ok := ms.alerts[searchID]
+	if !ok {
+		alerted = make(map[string]bool)
+		ms.alerts[searchID] = alerted
+	}
+
+	recorded := make([]string, 0, len(caseIDs))
+	for _, caseID := range caseIDs {
+		if !alerted[caseID] {
+			alerted[caseID] = true
+			recorded = append(recorded, caseID)
+		}
+	}
+	return recorded, nil
+}
+
+// ForgetAlerts deletes alert records so the cases can alert again
+func (ms *MemoryStorage) ForgetAlerts(ctx context.Context, searchID string, caseIDs []string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, caseID := range caseIDs {
+		delete(ms.alerts[searchID], caseID)
+	}
+	return nil
+}
+
+// SaveSavedSearch saves a saved search, replacing any with the same ID
+func (ms *MongoStorage) SaveSavedSearch(ctx context.Context, s *models.SavedSearch) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := ms.database.Collection("saved_searches").ReplaceOne(ctx, bson.M{"id": s.ID}, s, opts)
+	return err
+}
+
+// GetSavedSearch retrieves a saved search by ID
+func (ms *MongoStorage) GetSavedSearch(ctx context.Context, id string) (*models.SavedSearch, error) {
+	var s models.SavedSearch
+	err := ms.database.Collection("saved_searches").FindOne(ctx, bson.M{"id": id}).Decode(&s)
+	if err == mongo.ErrNoDocuments {
+		return nil, savedSearchNotFound(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSavedSearches lists one owner's saved searches, or everyone's
+func (ms *MongoStorage) ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]*models.SavedSearch, error) {
+	query := bson.M{}
+	if filter.UserID != "" {
+		query = bson.M{"user_id": filter.UserID, "tenant_id": filter.TenantID}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "id", Value: 1}})
+
+	cursor, err := ms.database.Collection("saved_searches").Find(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	searches := make([]*models.SavedSearch, 0)
+	if err := cursor.All(ctx, &searches); err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+// DeleteSavedSearch deletes a saved search and its alert records
+func (ms *MongoStorage) DeleteSavedSearch(ctx context.Context, id string) error {
+	result, err := ms.database.Collection("saved_searches").DeleteOne(ctx, bson.M{"id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return savedSearchNotFound(id)
+	}
+	_, err = ms.database.Collection("saved_search_alerts").DeleteMany(ctx, bson.M{"search_id": id})
+	return err
+}
+
+// RecordAlerts records alerts for a saved search, returning the new ones.
+// The unique index on search and case makes a concurrent duplicate fail
+// rather than alert twice.
+func (ms *MongoStorage) RecordAlerts(ctx context.Context, searchID string, caseIDs []string) ([]string, error) {
+	alerts := ms.database.Collection("saved_search_alerts")
+	opts := options.Update().SetUpsert(true)
+	now := time.Now().UTC()
+
+	recorded := make([]string, 0, len(caseIDs))
+	for _, caseID := range caseIDs {
+		result, err := alerts.UpdateOne(ctx,
+			bson.M{"search_id": searchID, "case_id": caseID},
+			bson.M{"$setOnInsert": bson.M{"alerted_at": now}},
+			opts)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return recorded, err
+		}
+		if result.UpsertedCount == 1 {
+			recorded = append(recorded, caseID)
+		}
+	}
+	return recorded, nil
+}
+
+// ForgetAlerts deletes alert records so the cases can alert again
+func (ms *MongoStorage) ForgetAlerts(ctx context.Context, searchID string, caseIDs []string) error {
+	_, err := ms.database.Collection("saved_search_alerts").DeleteMany(ctx,
+		bson.M{"search_id": searchID, "case_id": bson.M{"$in": caseIDs}})
+	return err
+}
@@ -5,16 +5,24 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"strconv"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/gongahkia/kite/pkg/errors"
 	"github.com/gongahkia/kite/pkg/models"
+	"github.com/mattn/go-sqlite3" // SQLite driver
 )
 
 // SQLiteStorage implements the Storage interface using SQLite
 type SQLiteStorage struct {
-	db *sql.DB
+	db        *sql.DB
+	explainer *SlowQueryExplainer
+	terms     termIndex
+}
+
+// SetSlowQueryExplainer enables query plan logging for slow list and search queries
+func (ss *SQLiteStorage) SetSlowQueryExplainer(explainer *SlowQueryExplainer) {
+	ss.explainer = explainer
 }
 
 // NewSQLiteStorage creates a new SQLite storage adapter
@@ -63,12 +71,18 @@ func (ss *SQLiteStorage) initSchema() error {
 		court TEXT,
 		court_level INTEGER,
 		court_type TEXT,
+		court_id TEXT,
 		jurisdiction TEXT,
 		docket TEXT,
 		parties TEXT, -- JSON
 		judges TEXT, -- JSON
 		summary TEXT,
-		full_text TEXT,
+		holding TEXT,
+		lower_court_case_id TEXT,
+		appealed_to_case_id TEXT,
+		extraction_version INTEGER,
+		content_hash TEXT,
+		tenant_id TEXT,
 		key_issues TEXT, -- JSON
 		legal_concepts TEXT, -- JSON
 		outcome TEXT,
@@ -86,10 +100,19 @@ func (ss *SQLiteStorage) initSchema() error {
 
 	CREATE INDEX IF NOT EXISTS idx_cases_jurisdiction ON cases(jurisdiction);
 	CREATE INDEX IF NOT EXISTS idx_cases_court ON cases(court);
+	CREATE INDEX IF NOT EXISTS idx_cases_court_id ON cases(court_id);
+	CREATE INDEX IF NOT EXISTS idx_cases_case_number ON cases(case_number);
 	CREATE INDEX IF NOT EXISTS idx_cases_decision_date ON cases(decision_date);
 	CREATE INDEX IF NOT EXISTS idx_cases_case_name ON cases(case_name);
+	CREATE INDEX IF NOT EXISTS idx_cases_case_name_nocase ON cases(case_name COLLATE NOCASE);
 	CREATE INDEX IF NOT EXISTS idx_cases_status ON cases(status);
 
+	-- Full text is kept apart so that listing cases reads only their metadata
+	CREATE TABLE IF NOT EXISTS case_full_text (
+		case_id TEXT PRIMARY KEY REFERENCES cases(id) ON DELETE CASCADE,
+		full_text TEXT
+	);
+
 	CREATE TABLE IF NOT EXISTS judges (
 		id TEXT PRIMARY KEY,
 		name TEXT NOT NULL,
@@ -103,10 +126,12 @@ func (ss *SQLiteStorage) initSchema() error {
 		career TEXT, -- JSON
 		notable_cases TEXT, -- JSON
 		total_cases INTEGER DEFAULT 0,
+		tenant_id TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_judges_name ON judges(name);
+	CREATE INDEX IF NOT EXISTS idx_judges_name_nocase ON judges(name COLLATE NOCASE);
 	CREATE INDEX IF NOT EXISTS idx_judges_court ON judges(court);
 	CREATE INDEX IF NOT EXISTS idx_judges_jurisdiction ON judges(jurisdiction);
 
@@ -125,6 +150,7 @@ func (ss *SQLiteStorage) initSchema() error {
 		citing_case_id TEXT,
 		cited_case_id TEXT,
 		is_normalized INTEGER DEFAULT 0, -- SQLite uses INTEGER for boolean
+		tenant_id TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (citing_case_id) REFERENCES cases(id),
 		FOREIGN KEY (cited_case_id) REFERENCES cases(id)
@@ -133,21 +159,29 @@ func (ss *SQLiteStorage) initSchema() error {
 	CREATE INDEX IF NOT EXISTS idx_citations_citing_case ON citations(citing_case_id);
 	CREATE INDEX IF NOT EXISTS idx_citations_cited_case ON citations(cited_case_id);
 	CREATE INDEX IF NOT EXISTS idx_citations_format ON citations(format);
+	` + sqlAnnotationsSchema + sqlSavedSearchesSchema + sqlStatusChangesSchema + sqlCaseVersionsSchema + sqliteSearchSchema
+
+	_, err := ss.db.Exec(schema)
+	return err
+}
 
+// sqliteSearchSchema creates the full-text search index over case names,
+// summaries and full text, and the triggers keeping it current as either
+// table changes
+const sqliteSearchSchema = `
 	-- Full-text search support
 	CREATE VIRTUAL TABLE IF NOT EXISTS cases_fts USING fts5(
 		id UNINDEXED,
 		case_name,
 		summary,
-		full_text,
-		content=cases,
-		content_rowid=rowid
+		full_text
 	);
 
 	-- Triggers to keep FTS index updated
 	CREATE TRIGGER IF NOT EXISTS cases_fts_insert AFTER INSERT ON cases BEGIN
 		INSERT INTO cases_fts(id, case_name, summary, full_text)
-		VALUES (new.id, new.case_name, new.summary, new.full_text);
+		VALUES (new.id, new.case_name, new.summary,
+			(SELECT full_text FROM case_full_text WHERE case_id = new.id));
 	END;
 
 	CREATE TRIGGER IF NOT EXISTS cases_fts_delete AFTER DELETE ON cases BEGIN
@@ -157,62 +191,164 @@ func (ss *SQLiteStorage) initSchema() error {
 	CREATE TRIGGER IF NOT EXISTS cases_fts_update AFTER UPDATE ON cases BEGIN
 		DELETE FROM cases_fts WHERE id = old.id;
 		INSERT INTO cases_fts(id, case_name, summary, full_text)
-		VALUES (new.id, new.case_name, new.summary, new.full_text);
+		VALUES (new.id, new.case_name, new.summary,
+			(SELECT full_text FROM case_full_text WHERE case_id = new.id));
 	END;
-	`
 
-	_, err := ss.db.Exec(schema)
-	return err
+	CREATE TRIGGER IF NOT EXISTS case_full_text_fts_insert AFTER INSERT ON case_full_text BEGIN
+		UPDATE cases_fts SET full_text = new.full_text WHERE id = new.case_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS case_full_text_fts_update AFTER UPDATE ON case_full_text BEGIN
+		UPDATE cases_fts SET full_text = new.full_text WHERE id = new.case_id;
+	END;
+`
+
+// sqlExecer runs statements on the database or within a transaction
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
 
-// SaveCase saves or updates a case
+// SaveCase saves a case, replacing any existing case with the same ID. The
+// case row and its full text are written in one transaction.
 func (ss *SQLiteStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	err := runSQLTx(ctx, ss.db, func(tx *sql.Tx) error {
+		return saveSQLiteCase(ctx, tx, c)
+	})
+	if err != nil {
+		return err
+	}
+
+	ss.terms.add(c)
+	return nil
+}
+
+// CreateCase saves a new case, failing if the ID is already taken
+func (ss *SQLiteStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	err := runSQLTx(ctx, ss.db, func(tx *sql.Tx) error {
+		return insertSQLiteCase(ctx, tx, c, "")
+	})
+	if sqliteErr, ok := err.(sqlite3.Error); ok && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return errors.StorageError("case already exists", errors.ErrAlreadyExists)
+	}
+	if err != nil {
+		return err
+	}
+
+	ss.terms.add(c)
+	return nil
+}
+
+// SaveCases saves or updates cases in a single transaction
+func (ss *SQLiteStorage) SaveCases(ctx context.Context, cases []*models.Case) error {
+	tx, err := ss.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, c := range cases {
+		if err := saveSQLiteCase(ctx, tx, c); err != nil {
+			return fmt.Errorf("failed to save case %s: %w", c.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	for _, c := range cases {
+		ss.terms.add(c)
+	}
+	return nil
+}
+
+// sqliteCaseUpsert updates every column of an existing case row in place,
+// which keeps created_at and fires the FTS update trigger
+const sqliteCaseUpsert = `
+		ON CONFLICT(id) DO UPDATE SET
+			case_number = excluded.case_number, case_name = excluded.case_name,
+			decision_date = excluded.decision_date, court = excluded.court,
+			court_level = excluded.court_level, court_type = excluded.court_type,
+			jurisdiction = excluded.jurisdiction, docket = excluded.docket,
+			parties = excluded.parties, judges = excluded.judges, summary = excluded.summary,
+			key_issues = excluded.key_issues,
+			legal_concepts = excluded.legal_concepts, outcome = excluded.outcome,
+			procedural_history = excluded.procedural_history, citations = excluded.citations,
+			url = excluded.url, pdf_url = excluded.pdf_url, source_database = excluded.source_database,
+			scraped_at = excluded.scraped_at, last_updated = excluded.last_updated,
+			language = excluded.language, status = excluded.status, court_id = excluded.court_id,
+			holding = excluded.holding, lower_court_case_id = excluded.lower_court_case_id,
+			appealed_to_case_id = excluded.appealed_to_case_id,
+			extraction_version = excluded.extraction_version,
+			content_hash = excluded.content_hash,
+			tenant_id = excluded.tenant_id
+	`
+
+// saveSQLiteCase inserts a case row, or updates it if the ID exists
+func saveSQLiteCase(ctx context.Context, db sqlExecer, c *models.Case) error {
+	return insertSQLiteCase(ctx, db, c, sqliteCaseUpsert)
+}
+
+// insertSQLiteCase inserts a case row and its full text, with onConflict
+// appended to the insert. db should be a transaction, so that the row is not
+// kept without its text.
+func insertSQLiteCase(ctx context.Context, db sqlExecer, c *models.Case, onConflict string) error {
 	query := `
-		INSERT OR REPLACE INTO cases (
+		INSERT INTO cases (
 			id, case_number, case_name, decision_date, court, court_level, court_type,
-			jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+			jurisdiction, docket, parties, judges, summary, key_issues,
 			legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-			source_database, scraped_at, last_updated, language, status
+			source_database, scraped_at, last_updated, language, status, court_id, holding,
+			lower_court_case_id, appealed_to_case_id, extraction_version, content_hash, tenant_id
 		) VALUES (
-			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)
-	`
+	` + onConflict
 
-	_, err := ss.db.ExecContext(ctx, query,
+	_, err := db.ExecContext(ctx, query,
 		c.ID, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
-		c.Jurisdiction, c.Docket, toJSONString(c.Parties), toJSONString(c.Judges), c.Summary, c.FullText,
+		c.Jurisdiction, c.Docket, toJSONString(c.Parties), toJSONString(c.Judges), c.Summary,
 		toJSONString(c.KeyIssues), toJSONString(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
 		toJSONString(c.Citations), c.URL, c.PDFURL, c.SourceDatabase, c.ScrapedAt, c.LastUpdated,
-		c.Language, c.Status,
+		c.Language, c.Status, c.CourtID, c.Holding, c.LowerCourtCaseID, c.AppealedToCaseID,
+		c.ExtractionVersion, c.ContentHash, c.TenantID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return saveSQLFullText(ctx, db, c, positionalPlaceholder)
 }
 
 // GetCase retrieves a case by ID
 func (ss *SQLiteStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
 	query := `
 		SELECT id, case_number, case_name, decision_date, court, court_level, court_type,
-			jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+			jurisdiction, docket, parties, judges, summary, ` + sqlFullText("cases.id", true) + `, key_issues,
 			legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-			source_database, scraped_at, last_updated, language, status, created_at
+			source_database, scraped_at, last_updated, language, status, court_id, holding,
+			lower_court_case_id, appealed_to_case_id, extraction_version, content_hash, tenant_id, created_at
 		FROM cases WHERE id = ?
 	`
 
 	var c models.Case
-	var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON sql.NullString
+	var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON, holding sql.NullString
+	var courtID, lowerCourtCaseID, appealedToCaseID, contentHash, tenantID sql.NullString
+	var extractionVersion sql.NullInt64
 	var decisionDate, scrapedAt, lastUpdated, createdAt sql.NullTime
 
 	err := ss.db.QueryRowContext(ctx, query, id).Scan(
 		&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.CourtLevel, &c.CourtType,
 		&c.Jurisdiction, &c.Docket, &partiesJSON, &judgesJSON, &c.Summary, &c.FullText, &keyIssuesJSON,
 		&legalConceptsJSON, &c.Outcome, &c.ProceduralHistory, &citationsJSON, &c.URL, &c.PDFURL,
-		&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &createdAt,
+		&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &courtID, &holding,
+		&lowerCourtCaseID, &appealedToCaseID, &extractionVersion, &contentHash, &tenantID, &createdAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("case not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("case not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
@@ -230,6 +366,13 @@ func (ss *SQLiteStorage) GetCase(ctx context.Context, id string) (*models.Case,
 	if createdAt.Valid {
 		c.CreatedAt = &createdAt.Time
 	}
+	c.CourtID = courtID.String
+	c.Holding = holding.String
+	c.LowerCourtCaseID = lowerCourtCaseID.String
+	c.AppealedToCaseID = appealedToCaseID.String
+	c.ExtractionVersion = int(extractionVersion.Int64)
+	c.ContentHash = contentHash.String
+	c.TenantID = tenantID.String
 
 	if partiesJSON.Valid {
 		json.Unmarshal([]byte(partiesJSON.String), &c.Parties)
@@ -273,53 +416,38 @@ func (ss *SQLiteStorage) DeleteCase(ctx context.Context, id string) error {
 		return fmt.Errorf("case not found: %s", id)
 	}
 
+	ss.terms.remove(id)
 	return nil
 }
 
+// SetTermStatsRefresh sets how often term statistics are recomputed from the
+// database; 0 keeps them for the life of the process
+func (ss *SQLiteStorage) SetTermStatsRefresh(interval time.Duration) {
+	ss.terms.setRefresh(interval)
+}
+
+// TermStats returns term statistics over stored cases, seeding them from the
+// database on first use and recomputing them every refresh interval
+func (ss *SQLiteStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return ss.terms.load(ctx, ss.ListCases)
+}
+
 // ListCases lists cases with filtering
 func (ss *SQLiteStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
 	query := `SELECT id, case_number, case_name, decision_date, court, court_level, court_type,
-		jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+		jurisdiction, docket, parties, judges, summary, ` + sqlFullText("cases.id", filter.IncludeFullText) + `, key_issues,
 		legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-		source_database, scraped_at, last_updated, language, status, created_at
-		FROM cases WHERE 1=1`
-
-	var args []interface{}
-	argIndex := 1
+		source_database, scraped_at, last_updated, language, status, court_id, holding,
+		lower_court_case_id, appealed_to_case_id, extraction_version, content_hash, tenant_id, created_at
+		FROM cases WHERE `
 
-	if filter.Jurisdiction != "" {
-		query += fmt.Sprintf(" AND jurisdiction = ?%d", argIndex)
-		args = append(args, filter.Jurisdiction)
-		argIndex++
-	}
-	if filter.Court != "" {
-		query += fmt.Sprintf(" AND court = ?%d", argIndex)
-		args = append(args, filter.Court)
-		argIndex++
-	}
-	if filter.CourtLevel != nil {
-		query += fmt.Sprintf(" AND court_level = ?%d", argIndex)
-		args = append(args, *filter.CourtLevel)
-		argIndex++
-	}
-	if filter.Status != "" {
-		query += fmt.Sprintf(" AND status = ?%d", argIndex)
-		args = append(args, filter.Status)
-		argIndex++
-	}
-	if filter.StartDate != nil {
-		query += fmt.Sprintf(" AND decision_date >= ?%d", argIndex)
-		args = append(args, filter.StartDate)
-		argIndex++
-	}
-	if filter.EndDate != nil {
-		query += fmt.Sprintf(" AND decision_date <= ?%d", argIndex)
-		args = append(args, filter.EndDate)
-		argIndex++
-	}
+	where, args := sqlCaseConditions(filter, "cases", nil, positionalPlaceholder)
+	query += where
 
 	// Order and limit
-	if filter.OrderBy != "" {
+	if filter.OrderBy == OrderByAuthority {
+		query += " ORDER BY " + sqlAuthorityOrder("cases") + ", id"
+	} else if filter.OrderBy != "" {
 		direction := "ASC"
 		if filter.OrderDesc {
 			direction = "DESC"
@@ -330,20 +458,17 @@ func (ss *SQLiteStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*m
 	}
 
 	if filter.Limit > 0 {
-		query += fmt.Sprintf(" LIMIT ?%d", argIndex)
+		query += " LIMIT ?"
 		args = append(args, filter.Limit)
-		argIndex++
 	}
 	if filter.Offset > 0 {
-		query += fmt.Sprintf(" OFFSET ?%d", argIndex)
+		query += " OFFSET ?"
 		args = append(args, filter.Offset)
-		argIndex++
 	}
 
-	// Replace placeholders
-	for i := 1; i <= len(args); i++ {
-		query = strings.Replace(query, fmt.Sprintf("?%d", i), "?", 1)
-	}
+	defer ss.explainer.Observe(ctx, "sqlite.ListCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainSQL(ctx, ss.db, "EXPLAIN QUERY PLAN", query, args)
+	})
 
 	rows, err := ss.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -354,14 +479,17 @@ func (ss *SQLiteStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*m
 	var cases []*models.Case
 	for rows.Next() {
 		var c models.Case
-		var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON sql.NullString
+		var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON, holding sql.NullString
+		var courtID, lowerCourtCaseID, appealedToCaseID, contentHash, tenantID sql.NullString
+		var extractionVersion sql.NullInt64
 		var decisionDate, scrapedAt, lastUpdated, createdAt sql.NullTime
 
 		err := rows.Scan(
 			&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.CourtLevel, &c.CourtType,
 			&c.Jurisdiction, &c.Docket, &partiesJSON, &judgesJSON, &c.Summary, &c.FullText, &keyIssuesJSON,
 			&legalConceptsJSON, &c.Outcome, &c.ProceduralHistory, &citationsJSON, &c.URL, &c.PDFURL,
-			&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &createdAt,
+			&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &courtID, &holding,
+			&lowerCourtCaseID, &appealedToCaseID, &extractionVersion, &contentHash, &tenantID, &createdAt,
 		)
 		if err != nil {
 			return nil, err
@@ -380,6 +508,13 @@ func (ss *SQLiteStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*m
 		if createdAt.Valid {
 			c.CreatedAt = &createdAt.Time
 		}
+		c.CourtID = courtID.String
+		c.Holding = holding.String
+		c.LowerCourtCaseID = lowerCourtCaseID.String
+		c.AppealedToCaseID = appealedToCaseID.String
+		c.ExtractionVersion = int(extractionVersion.Int64)
+		c.ContentHash = contentHash.String
+		c.TenantID = tenantID.String
 
 		// Parse JSON
 		if partiesJSON.Valid {
@@ -406,21 +541,8 @@ func (ss *SQLiteStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*m
 
 // CountCases counts cases matching filter
 func (ss *SQLiteStorage) CountCases(ctx context.Context, filter CaseFilter) (int64, error) {
-	query := `SELECT COUNT(*) FROM cases WHERE 1=1`
-	var args []interface{}
-
-	if filter.Jurisdiction != "" {
-		query += " AND jurisdiction = ?"
-		args = append(args, filter.Jurisdiction)
-	}
-	if filter.Court != "" {
-		query += " AND court = ?"
-		args = append(args, filter.Court)
-	}
-	if filter.Status != "" {
-		query += " AND status = ?"
-		args = append(args, filter.Status)
-	}
+	where, args := sqlCaseConditions(filter, "cases", nil, positionalPlaceholder)
+	query := `SELECT COUNT(*) FROM cases WHERE ` + where
 
 	var count int64
 	err := ss.db.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -432,13 +554,14 @@ func (ss *SQLiteStorage) SaveJudge(ctx context.Context, j *models.Judge) error {
 	query := `
 		INSERT OR REPLACE INTO judges (
 			id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			biography, education, career, notable_cases, total_cases, tenant_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := ss.db.ExecContext(ctx, query,
 		j.ID, j.Name, j.FullName, j.Title, j.Court, j.Jurisdiction, j.AppointedDate,
 		j.Biography, toJSONString(j.Education), toJSONString(j.Career), toJSONString(j.NotableCases), j.TotalCases,
+		j.TenantID,
 	)
 
 	return err
@@ -448,26 +571,27 @@ func (ss *SQLiteStorage) SaveJudge(ctx context.Context, j *models.Judge) error {
 func (ss *SQLiteStorage) GetJudge(ctx context.Context, id string) (*models.Judge, error) {
 	query := `
 		SELECT id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases, created_at
+			biography, education, career, notable_cases, total_cases, tenant_id, created_at
 		FROM judges WHERE id = ?
 	`
 
 	var j models.Judge
 	var appointedDate, createdAt sql.NullTime
-	var educationJSON, careerJSON, notableCasesJSON sql.NullString
+	var educationJSON, careerJSON, notableCasesJSON, tenantID sql.NullString
 
 	err := ss.db.QueryRowContext(ctx, query, id).Scan(
 		&j.ID, &j.Name, &j.FullName, &j.Title, &j.Court, &j.Jurisdiction, &appointedDate,
-		&j.Biography, &educationJSON, &careerJSON, &notableCasesJSON, &j.TotalCases, &createdAt,
+		&j.Biography, &educationJSON, &careerJSON, &notableCasesJSON, &j.TotalCases, &tenantID, &createdAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("judge not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("judge not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
 
+	j.TenantID = tenantID.String
 	if appointedDate.Valid {
 		j.AppointedDate = &appointedDate.Time
 	}
@@ -496,7 +620,7 @@ func (ss *SQLiteStorage) UpdateJudge(ctx context.Context, j *models.Judge) error
 func (ss *SQLiteStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]*models.Judge, error) {
 	query := `
 		SELECT id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases, created_at
+			biography, education, career, notable_cases, total_cases, tenant_id, created_at
 		FROM judges WHERE 1=1
 	`
 
@@ -513,6 +637,10 @@ func (ss *SQLiteStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]
 		query += " AND jurisdiction = ?"
 		args = append(args, filter.Jurisdiction)
 	}
+	if filter.TenantScope != nil {
+		query += " AND COALESCE(tenant_id, '') IN ('', ?)"
+		args = append(args, *filter.TenantScope)
+	}
 
 	query += " ORDER BY name"
 
@@ -535,16 +663,17 @@ func (ss *SQLiteStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]
 	for rows.Next() {
 		var j models.Judge
 		var appointedDate, createdAt sql.NullTime
-		var educationJSON, careerJSON, notableCasesJSON sql.NullString
+		var educationJSON, careerJSON, notableCasesJSON, tenantID sql.NullString
 
 		err := rows.Scan(
 			&j.ID, &j.Name, &j.FullName, &j.Title, &j.Court, &j.Jurisdiction, &appointedDate,
-			&j.Biography, &educationJSON, &careerJSON, &notableCasesJSON, &j.TotalCases, &createdAt,
+			&j.Biography, &educationJSON, &careerJSON, &notableCasesJSON, &j.TotalCases, &tenantID, &createdAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
+		j.TenantID = tenantID.String
 		if appointedDate.Valid {
 			j.AppointedDate = &appointedDate.Time
 		}
@@ -572,13 +701,14 @@ func (ss *SQLiteStorage) SaveCitation(ctx context.Context, c *models.Citation) e
 	query := `
 		INSERT INTO citations (
 			format, raw_citation, normalized_citation, volume, reporter, page, year,
-			court, case_number, country, citing_case_id, cited_case_id, is_normalized
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			court, case_number, country, citing_case_id, cited_case_id, is_normalized, tenant_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := ss.db.ExecContext(ctx, query,
 		c.Format, c.RawCitation, c.NormalizedCitation, c.Volume, c.Reporter, c.Page, c.Year,
 		c.Court, c.CaseNumber, c.Country, c.CitingCaseID, c.CitedCaseID, boolToInt(c.IsNormalized),
+		c.TenantID,
 	)
 
 	if err != nil {
@@ -587,7 +717,7 @@ func (ss *SQLiteStorage) SaveCitation(ctx context.Context, c *models.Citation) e
 
 	id, err := result.LastInsertId()
 	if err == nil {
-		c.ID = int(id)
+		c.ID = strconv.FormatInt(id, 10)
 	}
 
 	return nil
@@ -597,27 +727,29 @@ func (ss *SQLiteStorage) SaveCitation(ctx context.Context, c *models.Citation) e
 func (ss *SQLiteStorage) GetCitation(ctx context.Context, id string) (*models.Citation, error) {
 	query := `
 		SELECT id, format, raw_citation, normalized_citation, volume, reporter, page, year,
-			court, case_number, country, citing_case_id, cited_case_id, is_normalized, created_at
+			court, case_number, country, citing_case_id, cited_case_id, is_normalized, tenant_id, created_at
 		FROM citations WHERE id = ?
 	`
 
 	var c models.Citation
 	var isNormalized int
+	var tenantID sql.NullString
 	var createdAt sql.NullTime
 
 	err := ss.db.QueryRowContext(ctx, query, id).Scan(
 		&c.ID, &c.Format, &c.RawCitation, &c.NormalizedCitation, &c.Volume, &c.Reporter, &c.Page, &c.Year,
-		&c.Court, &c.CaseNumber, &c.Country, &c.CitingCaseID, &c.CitedCaseID, &isNormalized, &createdAt,
+		&c.Court, &c.CaseNumber, &c.Country, &c.CitingCaseID, &c.CitedCaseID, &isNormalized, &tenantID, &createdAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("citation not found: %s", id)
+			return nil, errors.StorageError(fmt.Sprintf("citation not found: %s", id), errors.ErrNotFound)
 		}
 		return nil, err
 	}
 
 	c.IsNormalized = isNormalized == 1
+	c.TenantID = tenantID.String
 	if createdAt.Valid {
 		c.CreatedAt = &createdAt.Time
 	}
@@ -629,7 +761,7 @@ func (ss *SQLiteStorage) GetCitation(ctx context.Context, id string) (*models.Ci
 func (ss *SQLiteStorage) ListCitations(ctx context.Context, filter CitationFilter) ([]*models.Citation, error) {
 	query := `
 		SELECT id, format, raw_citation, normalized_citation, volume, reporter, page, year,
-			court, case_number, country, citing_case_id, cited_case_id, is_normalized, created_at
+			court, case_number, country, citing_case_id, cited_case_id, is_normalized, tenant_id, created_at
 		FROM citations WHERE 1=1
 	`
 
@@ -638,6 +770,12 @@ func (ss *SQLiteStorage) ListCitations(ctx context.Context, filter CitationFilte
 		query += " AND (citing_case_id = ? OR cited_case_id = ?)"
 		args = append(args, filter.CaseID, filter.CaseID)
 	}
+	if len(filter.CaseIDs) > 0 {
+		var citing, cited string
+		citing, args = sqlIn("citing_case_id", filter.CaseIDs, args, positionalPlaceholder)
+		cited, args = sqlIn("cited_case_id", filter.CaseIDs, args, positionalPlaceholder)
+		query += " AND (" + citing + " OR " + cited + ")"
+	}
 	if filter.Format != "" {
 		query += " AND format = ?"
 		args = append(args, filter.Format)
@@ -646,6 +784,10 @@ func (ss *SQLiteStorage) ListCitations(ctx context.Context, filter CitationFilte
 		query += " AND is_normalized = ?"
 		args = append(args, boolToInt(*filter.Valid))
 	}
+	if filter.TenantScope != nil {
+		query += " AND COALESCE(tenant_id, '') IN ('', ?)"
+		args = append(args, *filter.TenantScope)
+	}
 
 	query += " ORDER BY created_at DESC"
 
@@ -668,17 +810,19 @@ func (ss *SQLiteStorage) ListCitations(ctx context.Context, filter CitationFilte
 	for rows.Next() {
 		var c models.Citation
 		var isNormalized int
+		var tenantID sql.NullString
 		var createdAt sql.NullTime
 
 		err := rows.Scan(
 			&c.ID, &c.Format, &c.RawCitation, &c.NormalizedCitation, &c.Volume, &c.Reporter, &c.Page, &c.Year,
-			&c.Court, &c.CaseNumber, &c.Country, &c.CitingCaseID, &c.CitedCaseID, &isNormalized, &createdAt,
+			&c.Court, &c.CaseNumber, &c.Country, &c.CitingCaseID, &c.CitedCaseID, &isNormalized, &tenantID, &createdAt,
 		)
 		if err != nil {
 			return nil, err
 		}
 
 		c.IsNormalized = isNormalized == 1
+		c.TenantID = tenantID.String
 		if createdAt.Valid {
 			c.CreatedAt = &createdAt.Time
 		}
@@ -691,32 +835,29 @@ func (ss *SQLiteStorage) ListCitations(ctx context.Context, filter CitationFilte
 
 // SearchCases performs full-text search on cases
 func (ss *SQLiteStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
-	// Use FTS5 for full-text search
+	// Use FTS5 for full-text search, filtered as the aggregations are
+	from, where, args := sqliteSearchScope(query)
 	ftsQuery := `
 		SELECT c.id, c.case_number, c.case_name, c.decision_date, c.court, c.court_level, c.court_type,
-			c.jurisdiction, c.docket, c.parties, c.judges, c.summary, c.full_text, c.key_issues,
+			c.jurisdiction, c.docket, c.parties, c.judges, c.summary, ` + sqlFullText("c.id", query.Filters.IncludeFullText) + `, c.key_issues,
 			c.legal_concepts, c.outcome, c.procedural_history, c.citations, c.url, c.pdf_url,
-			c.source_database, c.scraped_at, c.last_updated, c.language, c.status, c.created_at
-		FROM cases c
-		JOIN cases_fts fts ON c.id = fts.id
-		WHERE cases_fts MATCH ?
-	`
-
-	// Additional filters
-	var args []interface{}
-	args = append(args, query.Query)
-
-	if query.Filters.Jurisdiction != "" {
-		ftsQuery += " AND c.jurisdiction = ?"
-		args = append(args, query.Filters.Jurisdiction)
-	}
-	if query.Filters.Court != "" {
-		ftsQuery += " AND c.court = ?"
-		args = append(args, query.Filters.Court)
+			c.source_database, c.scraped_at, c.last_updated, c.language, c.status, c.court_id, c.holding,
+			c.lower_court_case_id, c.appealed_to_case_id, c.extraction_version, c.content_hash, c.tenant_id, c.created_at
+		FROM ` + from + `
+		WHERE ` + where
+
+	if query.Filters.OrderBy == OrderByAuthority {
+		ftsQuery += " ORDER BY " + sqlAuthorityOrder("c")
+		if query.Query != "" {
+			ftsQuery += ", rank"
+		}
+		ftsQuery += ", c.id"
+	} else if query.Query != "" {
+		ftsQuery += " ORDER BY rank"
+	} else {
+		ftsQuery += " ORDER BY c.decision_date DESC"
 	}
 
-	ftsQuery += " ORDER BY rank"
-
 	if query.Limit > 0 {
 		ftsQuery += " LIMIT ?"
 		args = append(args, query.Limit)
@@ -726,6 +867,10 @@ func (ss *SQLiteStorage) SearchCases(ctx context.Context, query SearchQuery) ([]
 		args = append(args, query.Offset)
 	}
 
+	defer ss.explainer.Observe(ctx, "sqlite.SearchCases", time.Now(), func(ctx context.Context) (string, error) {
+		return explainSQL(ctx, ss.db, "EXPLAIN QUERY PLAN", ftsQuery, args)
+	})
+
 	rows, err := ss.db.QueryContext(ctx, ftsQuery, args...)
 	if err != nil {
 		return nil, err
@@ -735,14 +880,17 @@ func (ss *SQLiteStorage) SearchCases(ctx context.Context, query SearchQuery) ([]
 	var cases []*models.Case
 	for rows.Next() {
 		var c models.Case
-		var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON sql.NullString
+		var partiesJSON, judgesJSON, keyIssuesJSON, legalConceptsJSON, citationsJSON, holding sql.NullString
+		var courtID, lowerCourtCaseID, appealedToCaseID, contentHash, tenantID sql.NullString
+		var extractionVersion sql.NullInt64
 		var decisionDate, scrapedAt, lastUpdated, createdAt sql.NullTime
 
 		err := rows.Scan(
 			&c.ID, &c.CaseNumber, &c.CaseName, &decisionDate, &c.Court, &c.CourtLevel, &c.CourtType,
 			&c.Jurisdiction, &c.Docket, &partiesJSON, &judgesJSON, &c.Summary, &c.FullText, &keyIssuesJSON,
 			&legalConceptsJSON, &c.Outcome, &c.ProceduralHistory, &citationsJSON, &c.URL, &c.PDFURL,
-			&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &createdAt,
+			&c.SourceDatabase, &scrapedAt, &lastUpdated, &c.Language, &c.Status, &courtID, &holding,
+			&lowerCourtCaseID, &appealedToCaseID, &extractionVersion, &contentHash, &tenantID, &createdAt,
 		)
 		if err != nil {
 			return nil, err
@@ -761,6 +909,13 @@ func (ss *SQLiteStorage) SearchCases(ctx context.Context, query SearchQuery) ([]
 		if createdAt.Valid {
 			c.CreatedAt = &createdAt.Time
 		}
+		c.CourtID = courtID.String
+		c.Holding = holding.String
+		c.LowerCourtCaseID = lowerCourtCaseID.String
+		c.AppealedToCaseID = appealedToCaseID.String
+		c.ExtractionVersion = int(extractionVersion.Int64)
+		c.ContentHash = contentHash.String
+		c.TenantID = tenantID.String
 
 		// Parse JSON
 		if partiesJSON.Valid {
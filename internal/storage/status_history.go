@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// sqlStatusChangesSchema creates the case status audit table, shared by the
+// SQL backends
+const sqlStatusChangesSchema = `
+	CREATE TABLE IF NOT EXISTS case_status_changes (
+		case_id TEXT NOT NULL,
+		from_status TEXT NOT NULL,
+		to_status TEXT NOT NULL,
+		changed_at TIMESTAMP NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_case_status_changes_case ON case_status_changes(case_id, changed_at);
+`
+
+// recordSQLStatusChange appends a status change to the audit table
+func recordSQLStatusChange(ctx context.Context, db *sql.DB, change StatusChange, placeholder func(int) string) error {
+	query := fmt.Sprintf(`
+		INSERT INTO case_status_changes (case_id, from_status, to_status, changed_at)
+		VALUES (%s, %s, %s, %s)
+	`, placeholder(1), placeholder(2), placeholder(3), placeholder(4))
+
+	_, err := db.ExecContext(ctx, query, change.CaseID, change.From, change.To, change.At)
+	return err
+}
+
+// listSQLStatusChanges reads a case's status changes, oldest first
+func listSQLStatusChanges(ctx context.Context, db *sql.DB, caseID string, placeholder func(int) string) ([]StatusChange, error) {
+	query := fmt.Sprintf(`
+		SELECT case_id, from_status, to_status, changed_at FROM case_status_changes
+		WHERE case_id = %s
+		ORDER BY changed_at
+	`, placeholder(1))
+
+	rows, err := db.QueryContext(ctx, query, caseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := make([]StatusChange, 0)
+	for rows.Next() {
+		var change StatusChange
+		if err := rows.Scan(&change.CaseID, &change.From, &change.To, &change.At); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// RecordStatusChange appends a status change to the case's history
+func (ss *SQLiteStorage) RecordStatusChange(ctx context.Context, change StatusChange) error {
+	return recordSQLStatusChange(ctx, ss.db, change, positionalPlaceholder)
+}
+
+// StatusHistory returns a case's status changes, oldest first
+func (ss *SQLiteStorage) StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error) {
+	return listSQLStatusChanges(ctx, ss.db, caseID, positionalPlaceholder)
+}
+
+// RecordStatusChange appends a status change to the case's history
+func (ps *PostgresStorage) RecordStatusChange(ctx context.Context, change StatusChange) error {
+	return recordSQLStatusChange(ctx, ps.db, change, postgresPlaceholder)
+}
+
+// StatusHistory returns a case's status changes, oldest first
+func (ps *PostgresStorage) StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error) {
+	return listSQLStatusChanges(ctx, ps.db, caseID, postgresPlaceholder)
+}
+
+// RecordStatusChange appends a status change to the case's history
+func (ms *MemoryStorage) RecordStatusChange(ctx context.Context, change StatusChange) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.statusChanges[change.CaseID] = append(ms.statusChanges[change.CaseID], change)
+	return nil
+}
+
+// StatusHistory returns a case's status changes, oldest first
+func (ms *MemoryStorage) StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return append([]StatusChange(nil), ms.statusChanges[caseID]...), nil
+}
+
+// RecordStatusChange appends a status change to the case's history
+func (ms *MongoStorage) RecordStatusChange(ctx context.Context, change StatusChange) error {
+	_, err := ms.database.Collection("case_status_changes").InsertOne(ctx, change)
+	return err
+}
+
+// StatusHistory returns a case's status changes, oldest first
+func (ms *MongoStorage) StatusHistory(ctx context.Context, caseID string) ([]StatusChange, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}})
+	cursor, err := ms.database.Collection("case_status_changes").Find(ctx, bson.M{"case_id": caseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]StatusChange, 0)
+	if err := cursor.All(ctx, &changes); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// StreamPageSize is how many cases StreamCases lists from storage at a time
+const StreamPageSize = 100
+
+// CaseStreamer is implemented by backends that can iterate over matching
+// cases themselves, such as with a database cursor
+type CaseStreamer interface {
+	StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error
+}
+
+// StreamCases calls fn with each case matching filter, in list order, as the
+// cases are fetched from storage, so the whole result is never held in
+// memory. The filter's Limit caps the cases streamed (0 streams every match)
+// and its Offset skips the first. Backends implementing CaseStreamer stream
+// the cases themselves; others are listed a page at a time. Streaming stops
+// at the first error from fn or storage, which is returned.
+func StreamCases(ctx context.Context, store Storage, filter CaseFilter, fn func(*models.Case) error) error {
+	if streamer, ok := store.(CaseStreamer); ok {
+		return streamer.StreamCases(ctx, filter, fn)
+	}
+	return streamPages(ctx, store, filter, fn)
+}
+
+// streamPages streams the cases matching filter by listing them from store a
+// page at a time
+func streamPages(ctx context.Context, store Storage, filter CaseFilter, fn func(*models.Case) error) error {
+	limit, sent := filter.Limit, 0
+	page := filter
+	page.Limit = StreamPageSize
+	for {
+		if limit > 0 && limit-sent < page.Limit {
+			page.Limit = limit - sent
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		cases, err := store.ListCases(ctx, page)
+		if err != nil {
+			return err
+		}
+		for _, c := range cases {
+			if err := fn(c); err != nil {
+				return err
+			}
+		}
+
+		sent += len(cases)
+		if len(cases) < page.Limit || (limit > 0 && sent >= limit) {
+			return nil
+		}
+		page.Offset += len(cases)
+	}
+}
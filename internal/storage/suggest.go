@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// escapeLike escapes LIKE wildcards so the prefix matches literally
+func escapeLike(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix)
+}
+
+// querySuggestions runs a prefix query returning one string column
+func querySuggestions(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := make([]string, 0)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, value)
+	}
+
+	return suggestions, rows.Err()
+}
+
+// SuggestCaseNames returns distinct names of the filter's cases starting with
+// prefix, using the NOCASE case name index
+func (ss *SQLiteStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	where, args := sqlCaseConditions(filter, "cases", []interface{}{escapeLike(prefix) + "%"}, positionalPlaceholder)
+	return querySuggestions(ctx, ss.db, `
+		SELECT DISTINCT case_name FROM cases
+		WHERE case_name LIKE ? ESCAPE '\' AND `+where+`
+		ORDER BY case_name COLLATE NOCASE
+		LIMIT ?
+	`, append(args, limit)...)
+}
+
+// SuggestJudgeNames returns distinct names of the filter's judges starting
+// with prefix
+func (ss *SQLiteStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error) {
+	where, args := sqlJudgeConditions(filter, []interface{}{escapeLike(prefix) + "%"}, positionalPlaceholder)
+	return querySuggestions(ctx, ss.db, `
+		SELECT DISTINCT name FROM judges
+		WHERE name LIKE ? ESCAPE '\' AND `+where+`
+		ORDER BY name COLLATE NOCASE
+		LIMIT ?
+	`, append(args, limit)...)
+}
+
+// SuggestConcepts returns distinct legal concepts of the filter's cases
+// starting with prefix
+func (ss *SQLiteStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	where, args := sqlCaseConditions(filter, "c", []interface{}{escapeLike(prefix) + "%"}, positionalPlaceholder)
+	return querySuggestions(ctx, ss.db, `
+		SELECT DISTINCT concept.value FROM cases c, json_each(c.legal_concepts) AS concept
+		WHERE concept.value LIKE ? ESCAPE '\' AND `+where+`
+		ORDER BY concept.value COLLATE NOCASE
+		LIMIT ?
+	`, append(args, limit)...)
+}
+
+// SuggestCaseNames returns distinct names of the filter's cases starting with
+// prefix, using the lower(case_name) pattern index
+func (ps *PostgresStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	where, args := sqlCaseConditions(filter, "cases", []interface{}{strings.ToLower(escapeLike(prefix)) + "%"}, postgresPlaceholder)
+	args = append(args, limit)
+	return querySuggestions(ctx, ps.db, fmt.Sprintf(`
+		SELECT case_name FROM cases
+		WHERE lower(case_name) LIKE $1 AND %s
+		GROUP BY case_name
+		ORDER BY lower(case_name)
+		LIMIT $%d
+	`, where, len(args)), args...)
+}
+
+// SuggestJudgeNames returns distinct names of the filter's judges starting
+// with prefix
+func (ps *PostgresStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error) {
+	where, args := sqlJudgeConditions(filter, []interface{}{strings.ToLower(escapeLike(prefix)) + "%"}, postgresPlaceholder)
+	args = append(args, limit)
+	return querySuggestions(ctx, ps.db, fmt.Sprintf(`
+		SELECT name FROM judges
+		WHERE lower(name) LIKE $1 AND %s
+		GROUP BY name
+		ORDER BY lower(name)
+		LIMIT $%d
+	`, where, len(args)), args...)
+}
+
+// SuggestConcepts returns distinct legal concepts of the filter's cases
+// starting with prefix
+func (ps *PostgresStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	where, args := sqlCaseConditions(filter, "c", []interface{}{strings.ToLower(escapeLike(prefix)) + "%"}, postgresPlaceholder)
+	args = append(args, limit)
+	return querySuggestions(ctx, ps.db, fmt.Sprintf(`
+		SELECT concept.value FROM cases c, jsonb_array_elements_text(c.legal_concepts) AS concept(value)
+		WHERE lower(concept.value) LIKE $1 AND %s
+		GROUP BY concept.value
+		ORDER BY lower(concept.value)
+		LIMIT $%d
+	`, where, len(args)), args...)
+}
+
+// mongoSuggestions groups distinct values of field matching an anchored
+// prefix regex, among the documents matching conditions
+func mongoSuggestions(ctx context.Context, coll *mongo.Collection, conditions bson.M, field string, unwind bool, prefix string, limit int) ([]string, error) {
+	match := bson.M{"$match": bson.M{
+		field: primitive.Regex{Pattern: "^" + regexp.QuoteMeta(prefix), Options: "i"},
+	}}
+
+	// Select the documents before unwinding their values
+	pipeline := bson.A{}
+	if len(conditions) > 0 {
+		pipeline = append(pipeline, bson.M{"$match": conditions})
+	}
+	if unwind {
+		pipeline = append(pipeline, bson.M{"$unwind": "$" + field})
+	}
+	pipeline = append(pipeline,
+		match,
+		bson.M{"$group": bson.M{"_id": "$" + field}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+		bson.M{"$limit": limit},
+	)
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest %s: %w", field, err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Value string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]string, len(results))
+	for i, r := range results {
+		suggestions[i] = r.Value
+	}
+
+	return suggestions, nil
+}
+
+// SuggestCaseNames returns distinct names of the filter's cases starting with
+// prefix
+func (ms *MongoStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	return mongoSuggestions(ctx, ms.cases, mongoCaseConditions(filter), "case_name", false, prefix, limit)
+}
+
+// SuggestJudgeNames returns distinct names of the filter's judges starting
+// with prefix
+func (ms *MongoStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error) {
+	return mongoSuggestions(ctx, ms.judges, mongoJudgeConditions(filter), "name", false, prefix, limit)
+}
+
+// SuggestConcepts returns distinct legal concepts of the filter's cases
+// starting with prefix
+func (ms *MongoStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	return mongoSuggestions(ctx, ms.cases, mongoCaseConditions(filter), "legal_concepts", true, prefix, limit)
+}
+
+// prefixMatches returns the sorted distinct values starting with prefix, ignoring case
+func prefixMatches(values []string, prefix string, limit int) []string {
+	prefixLower := strings.ToLower(prefix)
+	seen := make(map[string]bool)
+	matches := make([]string, 0)
+
+	for _, v := range values {
+		if !seen[v] && strings.HasPrefix(strings.ToLower(v), prefixLower) {
+			seen[v] = true
+			matches = append(matches, v)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return strings.ToLower(matches[i]) < strings.ToLower(matches[j])
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
+
+// SuggestCaseNames returns distinct names of the filter's cases starting with
+// prefix
+func (ms *MemoryStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	names := make([]string, 0, len(ms.cases))
+	for _, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			names = append(names, c.CaseName)
+		}
+	}
+
+	return prefixMatches(names, prefix, limit), nil
+}
+
+// SuggestJudgeNames returns distinct names of the filter's judges starting
+// with prefix
+func (ms *MemoryStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	names := make([]string, 0, len(ms.judges))
+	for _, j := range ms.judges {
+		if filter.matchesJudge(j) {
+			names = append(names, j.Name)
+		}
+	}
+
+	return prefixMatches(names, prefix, limit), nil
+}
+
+// SuggestConcepts returns distinct legal concepts of the filter's cases
+// starting with prefix
+func (ms *MemoryStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	concepts := make([]string, 0)
+	for _, c := range ms.cases {
+		if ms.matchesFilter(c, filter) {
+			concepts = append(concepts, c.LegalConcepts...)
+		}
+	}
+
+	return prefixMatches(concepts, prefix, limit), nil
+}
@@ -0,0 +1,524 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	kiteerrors "github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrNotTenantOwned is returned when a tenant writes to a case, judge or
+// citation it does not own, including shared public data
+var ErrNotTenantOwned = errors.New("record is not owned by this tenant")
+
+type tenantContextKey struct{}
+
+// TenantScopeKey is the context key holding the tenant a request is scoped
+// to. The API sets it as a request local, which fasthttp exposes as a
+// context value.
+var TenantScopeKey = tenantContextKey{}
+
+// WithTenant returns a context scoped to a tenant. The empty tenant scopes
+// to shared public data only.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, TenantScopeKey, tenantID)
+}
+
+// TenantFromContext returns the tenant a context is scoped to, and false if
+// it is unscoped, as for workers and other internal callers
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(TenantScopeKey).(string)
+	return tenantID, ok
+}
+
+// tenantScope returns the context's tenant as a filter scope, nil if unscoped
+func tenantScope(ctx context.Context) *string {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return &tenantID
+}
+
+// visibleToTenant reports whether a record owned by owner can be read in
+// scope. Shared records (no owner) are visible to every tenant; a nil scope
+// sees everything.
+func visibleToTenant(owner string, scope *string) bool {
+	return scope == nil || owner == "" || owner == *scope
+}
+
+// mongoTenantScope returns a tenant_id condition matching the tenant's own
+// documents and shared ones, including documents stored before tenancy
+func mongoTenantScope(tenantID string) bson.M {
+	return bson.M{"$in": bson.A{tenantID, "", nil}}
+}
+
+// TenantStorage isolates tenants' data. Reads from a tenant-scoped context
+// see the tenant's own records and shared ones; records owned by other
+// tenants are reported as not found. Writes are stamped with the tenant and
+// may only replace records the tenant already owns. Unscoped contexts pass
+// straight through.
+type TenantStorage struct {
+	Storage
+}
+
+// NewTenantStorage wraps storage with per-tenant isolation
+func NewTenantStorage(store Storage) *TenantStorage {
+	return &TenantStorage{Storage: store}
+}
+
+// notVisible reports a record hidden from the tenant exactly as a missing one
+func notVisible(kind, id string) error {
+	return kiteerrors.NewKiteError("NOT_FOUND", fmt.Sprintf("%s not found: %s", kind, id), kiteerrors.ErrNotFound)
+}
+
+// notOwned reports a write to a record the tenant does not own
+func notOwned(kind, id string) error {
+	return kiteerrors.NewKiteError("FORBIDDEN", fmt.Sprintf("%s %s is not owned by this tenant", kind, id), ErrNotTenantOwned)
+}
+
+// checkOwner checks that a write in scope may replace a record owned by
+// owner. A record another tenant owns is hidden, so it is not found.
+func checkOwner(kind, id, owner string, scope *string) error {
+	if !visibleToTenant(owner, scope) {
+		return notVisible(kind, id)
+	}
+	if owner != *scope {
+		return notOwned(kind, id)
+	}
+	return nil
+}
+
+// caseOwner returns the owner of a stored case, and false if there is none
+func (t *TenantStorage) caseOwner(ctx context.Context, id string) (string, bool, error) {
+	if id == "" {
+		return "", false, nil
+	}
+	existing, err := t.Storage.GetCase(ctx, id)
+	if errors.Is(err, kiteerrors.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return existing.TenantID, true, nil
+}
+
+// checkCaseWrite stamps a case with the tenant and checks it may replace any
+// stored case with the same ID
+func (t *TenantStorage) checkCaseWrite(ctx context.Context, c *models.Case, scope *string) error {
+	owner, exists, err := t.caseOwner(ctx, c.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := checkOwner("case", c.ID, owner, scope); err != nil {
+			return err
+		}
+	}
+	c.TenantID = *scope
+	return nil
+}
+
+// SaveCase saves a case owned by the tenant
+func (t *TenantStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := t.checkCaseWrite(ctx, c, scope); err != nil {
+			return err
+		}
+	}
+	return t.Storage.SaveCase(ctx, c)
+}
+
+// CreateCase creates a case owned by the tenant. An ID taken by another
+// tenant's case is reported as taken, as for any other case.
+func (t *TenantStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	if scope := tenantScope(ctx); scope != nil {
+		c.TenantID = *scope
+	}
+	return t.Storage.CreateCase(ctx, c)
+}
+
+// GetCase returns a case visible to the tenant
+func (t *TenantStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	c, err := t.Storage.GetCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleToTenant(c.TenantID, tenantScope(ctx)) {
+		return nil, notVisible("case", id)
+	}
+	return c, nil
+}
+
+// UpdateCase updates a case the tenant owns
+func (t *TenantStorage) UpdateCase(ctx context.Context, c *models.Case) error {
+	if scope := tenantScope(ctx); scope != nil {
+		owner, exists, err := t.caseOwner(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return notVisible("case", c.ID)
+		}
+		if err := checkOwner("case", c.ID, owner, scope); err != nil {
+			return err
+		}
+		c.TenantID = *scope
+	}
+	return t.Storage.UpdateCase(ctx, c)
+}
+
+// DeleteCase deletes a case the tenant owns
+func (t *TenantStorage) DeleteCase(ctx context.Context, id string) error {
+	if scope := tenantScope(ctx); scope != nil {
+		owner, exists, err := t.caseOwner(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return notVisible("case", id)
+		}
+		if err := checkOwner("case", id, owner, scope); err != nil {
+			return err
+		}
+	}
+	return t.Storage.DeleteCase(ctx, id)
+}
+
+// DeleteCasesByFilter deletes the matching cases the tenant owns. Shared
+// cases are left alone; the backends don't scope bulk deletes themselves,
+// so the tenant's cases are listed first and deleted by ID.
+func (t *TenantStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	scope := tenantScope(ctx)
+	if scope == nil {
+		return t.Storage.DeleteCasesByFilter(ctx, filter, opts)
+	}
+	if !opts.Force && !filter.HasConditions() {
+		return 0, ErrEmptyDeleteFilter
+	}
+
+	filter.TenantScope = scope
+	filter.Limit, filter.Offset = 0, 0
+	cases, err := t.Storage.ListCases(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var owned []string
+	for _, c := range cases {
+		if c.TenantID == *scope {
+			owned = append(owned, c.ID)
+		}
+	}
+	if len(owned) == 0 {
+		return 0, nil
+	}
+	return t.Storage.DeleteCasesByFilter(ctx, CaseFilter{IDs: owned}, DeleteOptions{})
+}
+
+// ListCases lists the cases visible to the tenant
+func (t *TenantStorage) ListCases(ctx context.Context, filter CaseFilter) ([]*models.Case, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.ListCases(ctx, filter)
+}
+
+// CountCases counts the cases visible to the tenant
+func (t *TenantStorage) CountCases(ctx context.Context, filter CaseFilter) (int64, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.CountCases(ctx, filter)
+}
+
+// SearchCases searches the cases visible to the tenant
+func (t *TenantStorage) SearchCases(ctx context.Context, query SearchQuery) ([]*models.Case, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		query.Filters.TenantScope = scope
+	}
+	return t.Storage.SearchCases(ctx, query)
+}
+
+// checkJudgeWrite stamps a judge with the tenant and checks it may replace
+// any stored judge with the same ID
+func (t *TenantStorage) checkJudgeWrite(ctx context.Context, j *models.Judge, scope *string) error {
+	if j.ID != "" {
+		existing, err := t.Storage.GetJudge(ctx, j.ID)
+		if err == nil {
+			if err := checkOwner("judge", j.ID, existing.TenantID, scope); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, kiteerrors.ErrNotFound) {
+			return err
+		}
+	}
+	j.TenantID = *scope
+	return nil
+}
+
+// SaveJudge saves a judge owned by the tenant
+func (t *TenantStorage) SaveJudge(ctx context.Context, j *models.Judge) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := t.checkJudgeWrite(ctx, j, scope); err != nil {
+			return err
+		}
+	}
+	return t.Storage.SaveJudge(ctx, j)
+}
+
+// GetJudge returns a judge visible to the tenant
+func (t *TenantStorage) GetJudge(ctx context.Context, id string) (*models.Judge, error) {
+	j, err := t.Storage.GetJudge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleToTenant(j.TenantID, tenantScope(ctx)) {
+		return nil, notVisible("judge", id)
+	}
+	return j, nil
+}
+
+// UpdateJudge updates a judge the tenant owns
+func (t *TenantStorage) UpdateJudge(ctx context.Context, j *models.Judge) error {
+	if scope := tenantScope(ctx); scope != nil {
+		existing, err := t.Storage.GetJudge(ctx, j.ID)
+		if err != nil {
+			return err
+		}
+		if err := checkOwner("judge", j.ID, existing.TenantID, scope); err != nil {
+			return err
+		}
+		j.TenantID = *scope
+	}
+	return t.Storage.UpdateJudge(ctx, j)
+}
+
+// ListJudges lists the judges visible to the tenant
+func (t *TenantStorage) ListJudges(ctx context.Context, filter JudgeFilter) ([]*models.Judge, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.ListJudges(ctx, filter)
+}
+
+// checkCitationWrite stamps a citation with the tenant and checks it may
+// replace any stored citation with the same ID
+func (t *TenantStorage) checkCitationWrite(ctx context.Context, c *models.Citation, scope *string) error {
+	if c.ID != "" {
+		existing, err := t.Storage.GetCitation(ctx, c.ID)
+		if err == nil {
+			if err := checkOwner("citation", c.ID, existing.TenantID, scope); err != nil {
+				return err
+			}
+		} else if !errors.Is(err, kiteerrors.ErrNotFound) {
+			return err
+		}
+	}
+	c.TenantID = *scope
+	return nil
+}
+
+// SaveCitation saves a citation owned by the tenant
+func (t *TenantStorage) SaveCitation(ctx context.Context, c *models.Citation) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := t.checkCitationWrite(ctx, c, scope); err != nil {
+			return err
+		}
+	}
+	return t.Storage.SaveCitation(ctx, c)
+}
+
+// GetCitation returns a citation visible to the tenant
+func (t *TenantStorage) GetCitation(ctx context.Context, id string) (*models.Citation, error) {
+	c, err := t.Storage.GetCitation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleToTenant(c.TenantID, tenantScope(ctx)) {
+		return nil, notVisible("citation", id)
+	}
+	return c, nil
+}
+
+// ListCitations lists the citations visible to the tenant
+func (t *TenantStorage) ListCitations(ctx context.Context, filter CitationFilter) ([]*models.Citation, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.ListCitations(ctx, filter)
+}
+
+// SuggestCaseNames suggests the names of cases visible to the tenant
+func (t *TenantStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.SuggestCaseNames(ctx, prefix, filter, limit)
+}
+
+// SuggestJudgeNames suggests the names of judges visible to the tenant
+func (t *TenantStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) ([]string, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.SuggestJudgeNames(ctx, prefix, filter, limit)
+}
+
+// SuggestConcepts suggests the legal concepts of cases visible to the tenant
+func (t *TenantStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) ([]string, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return t.Storage.SuggestConcepts(ctx, prefix, filter, limit)
+}
+
+// BeginTx begins a transaction whose writes are stamped with the tenant and
+// checked against stored records the same way
+func (t *TenantStorage) BeginTx(ctx context.Context) (Transaction, error) {
+	tx, err := t.Storage.BeginTx(ctx)
+	if err != nil || tx == nil {
+		return tx, err
+	}
+	if tenantScope(ctx) == nil {
+		return tx, nil
+	}
+	return &tenantTx{Transaction: tx, storage: t}, nil
+}
+
+// tenantTx stamps writes within a transaction with the context's tenant
+type tenantTx struct {
+	Transaction
+	storage *TenantStorage
+}
+
+// SaveCase saves a case owned by the tenant within the transaction
+func (tx *tenantTx) SaveCase(ctx context.Context, c *models.Case) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := tx.storage.checkCaseWrite(ctx, c, scope); err != nil {
+			return err
+		}
+	}
+	return tx.Transaction.SaveCase(ctx, c)
+}
+
+// SaveJudge saves a judge owned by the tenant within the transaction
+func (tx *tenantTx) SaveJudge(ctx context.Context, j *models.Judge) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := tx.storage.checkJudgeWrite(ctx, j, scope); err != nil {
+			return err
+		}
+	}
+	return tx.Transaction.SaveJudge(ctx, j)
+}
+
+// SaveCitation saves a citation owned by the tenant within the transaction
+func (tx *tenantTx) SaveCitation(ctx context.Context, c *models.Citation) error {
+	if scope := tenantScope(ctx); scope != nil {
+		if err := tx.storage.checkCitationWrite(ctx, c, scope); err != nil {
+			return err
+		}
+	}
+	return tx.Transaction.SaveCitation(ctx, c)
+}
+
+// AggregateFacets counts facet values over the cases visible to the tenant
+func (t *TenantStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		query.Filters.TenantScope = scope
+	}
+	return AggregateFacets(ctx, t.Storage, query, fields)
+}
+
+// AggregateTimeline counts the cases visible to the tenant per period
+func (t *TenantStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		query.Filters.TenantScope = scope
+	}
+	return AggregateTimeline(ctx, t.Storage, query, period)
+}
+
+// TermStats returns the corpus-wide term statistics. They only weight
+// ranking, so they are shared across tenants.
+func (t *TenantStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return CorpusTermStats(ctx, t.Storage)
+}
+
+// StreamCases streams the matching cases visible to the tenant
+func (t *TenantStorage) StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return StreamCases(ctx, t.Storage, filter, fn)
+}
+
+// ExistingIDs returns which of ids are cases visible to the tenant. Scoped
+// lookups list the cases, as other tenants' cases must not be reported.
+func (t *TenantStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	if tenantScope(ctx) == nil {
+		return StoredCaseIDs(ctx, t.Storage, ids)
+	}
+	return listedCaseIDs(ctx, t, ids)
+}
+
+// GetContentHash reads the content hash of a case visible to the tenant
+func (t *TenantStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	if tenantScope(ctx) == nil {
+		return StoredContentHash(ctx, t.Storage, id)
+	}
+	return loadedContentHash(ctx, t, id)
+}
+
+// GetCaseFullText loads the full text of a case visible to the tenant
+func (t *TenantStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	if tenantScope(ctx) == nil {
+		return CaseFullText(ctx, t.Storage, id)
+	}
+	return loadedFullText(ctx, t, id)
+}
+
+// ListCasesByJudge pages through the cases visible to the tenant decided by a
+// judge visible to the tenant
+func (t *TenantStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		if _, err := t.GetJudge(ctx, judgeID); err != nil {
+			return nil, 0, err
+		}
+		filter.TenantScope = scope
+	}
+	return CasesByJudge(ctx, t.Storage, judgeID, filter)
+}
+
+// ListCasesByConcept pages through the cases visible to the tenant tagged
+// with a concept
+func (t *TenantStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return CasesByConcept(ctx, t.Storage, concept, filter)
+}
+
+// CountConceptCooccurrence counts related concepts over the cases visible to
+// the tenant
+func (t *TenantStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	if scope := tenantScope(ctx); scope != nil {
+		filter.TenantScope = scope
+	}
+	return ConceptCooccurrence(ctx, t.Storage, concept, filter, limit)
+}
+
+// SaveCitations saves a batch of citations owned by the tenant
+func (t *TenantStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	if scope := tenantScope(ctx); scope != nil {
+		for _, c := range citations {
+			if err := t.checkCitationWrite(ctx, c, scope); err != nil {
+				return err
+			}
+		}
+	}
+	return SaveCitations(ctx, t.Storage, citations)
+}
@@ -0,0 +1,328 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// termStatsPageSize is how many cases are read per page when seeding stats
+const termStatsPageSize = 500
+
+// termStatsScanTimeout bounds a full scan recomputing term stats
+const termStatsScanTimeout = 10 * time.Minute
+
+// TermStats holds corpus-wide document frequency statistics for ranking
+type TermStats struct {
+	mu          sync.RWMutex
+	totalLength int64
+	docFreq     map[string]int
+	docs        map[string]termDoc
+}
+
+// termDoc records what a single case contributed to the stats so it can be
+// withdrawn when the case is replaced or deleted
+type termDoc struct {
+	length int
+	terms  []string
+}
+
+// TermStatsProvider is implemented by backends that maintain term statistics
+// over their stored cases
+type TermStatsProvider interface {
+	TermStats(ctx context.Context) (*TermStats, error)
+}
+
+// TermStatsRefresher is implemented by backends that recompute their term
+// statistics periodically to pick up cases written by other processes
+type TermStatsRefresher interface {
+	SetTermStatsRefresh(interval time.Duration)
+}
+
+// CorpusTermStats returns the term statistics kept by backends implementing
+// TermStatsProvider, and nil for others
+func CorpusTermStats(ctx context.Context, store Storage) (*TermStats, error) {
+	if provider, ok := store.(TermStatsProvider); ok {
+		return provider.TermStats(ctx)
+	}
+	return nil, nil
+}
+
+// NewTermStats creates empty term statistics
+func NewTermStats() *TermStats {
+	return &TermStats{
+		docFreq: make(map[string]int),
+		docs:    make(map[string]termDoc),
+	}
+}
+
+// Tokenize lowercases text and splits it into letter and digit runs
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// CaseTokens returns the searchable tokens of a case across all ranked fields
+func CaseTokens(c *models.Case) []string {
+	tokens := Tokenize(c.CaseName)
+	tokens = append(tokens, Tokenize(c.Summary)...)
+	tokens = append(tokens, Tokenize(c.FullText)...)
+	for _, concept := range c.LegalConcepts {
+		tokens = append(tokens, Tokenize(concept)...)
+	}
+	return tokens
+}
+
+// caseTermDoc returns what a case contributes to the stats
+func caseTermDoc(c *models.Case) termDoc {
+	tokens := CaseTokens(c)
+
+	seen := make(map[string]bool, len(tokens))
+	terms := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return termDoc{length: len(tokens), terms: terms}
+}
+
+// Add records a case, replacing anything previously recorded under its ID
+func (ts *TermStats) Add(c *models.Case) {
+	ts.put(c.ID, caseTermDoc(c))
+}
+
+func (ts *TermStats) put(id string, doc termDoc) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.remove(id)
+	for _, t := range doc.terms {
+		ts.docFreq[t]++
+	}
+	ts.totalLength += int64(doc.length)
+	ts.docs[id] = doc
+}
+
+// Remove withdraws a case from the stats
+func (ts *TermStats) Remove(id string) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.remove(id)
+}
+
+func (ts *TermStats) remove(id string) {
+	doc, ok := ts.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, t := range doc.terms {
+		if ts.docFreq[t] <= 1 {
+			delete(ts.docFreq, t)
+		} else {
+			ts.docFreq[t]--
+		}
+	}
+	ts.totalLength -= int64(doc.length)
+	delete(ts.docs, id)
+}
+
+// DocCount returns the number of cases recorded
+func (ts *TermStats) DocCount() int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return len(ts.docs)
+}
+
+// DocFreq returns the number of cases containing term
+func (ts *TermStats) DocFreq(term string) int {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	return ts.docFreq[strings.ToLower(term)]
+}
+
+// AvgDocLength returns the mean token count of recorded cases
+func (ts *TermStats) AvgDocLength() float64 {
+	ts.mu.RLock()
+	defer ts.mu.RUnlock()
+
+	if len(ts.docs) == 0 {
+		return 0
+	}
+	return float64(ts.totalLength) / float64(len(ts.docs))
+}
+
+// termIndex lazily seeds term stats from a backend's existing cases and keeps
+// them current as cases are written. The stats only see this process's
+// writes, so they are recomputed every refresh interval to pick up cases
+// written by other processes. Scans run outside the lock: writes made during a
+// scan are replayed onto its result, and a periodic refresh keeps serving the
+// previous stats until it completes.
+type termIndex struct {
+	mu       sync.Mutex
+	stats    *TermStats
+	seededAt time.Time
+	refresh  time.Duration // 0 disables periodic recomputation
+	stale    bool          // a bulk write landed during the running scan
+
+	loading chan struct{} // closed when the running scan finishes, nil when idle
+	loadErr error
+	pending []termWrite // writes made during the running scan
+}
+
+// termWrite is a case write to replay onto stats from a scan; doc is nil for
+// a removal
+type termWrite struct {
+	id  string
+	doc *termDoc
+}
+
+// setRefresh sets how often the stats are recomputed from the backend
+func (ti *termIndex) setRefresh(interval time.Duration) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.refresh = interval
+}
+
+// load returns the stats, seeding them from list on first use or after a reset.
+// Stats older than the refresh interval are returned while a scan recomputes
+// them in the background.
+func (ti *termIndex) load(ctx context.Context, list func(context.Context, CaseFilter) ([]*models.Case, error)) (*TermStats, error) {
+	ti.mu.Lock()
+	if ti.stats != nil {
+		stats := ti.stats
+		if ti.loading == nil && (ti.stale || (ti.refresh > 0 && time.Since(ti.seededAt) >= ti.refresh)) {
+			ti.startScan(ctx, list)
+		}
+		ti.mu.Unlock()
+		return stats, nil
+	}
+	if ti.loading == nil {
+		ti.startScan(ctx, list)
+	}
+	done := ti.loading
+	ti.mu.Unlock()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if ti.stats == nil {
+		return nil, ti.loadErr
+	}
+	return ti.stats, nil
+}
+
+// startScan recomputes the stats in the background. The scan outlives the
+// caller that triggered it so that callers giving up don't waste it; it is
+// bounded by termStatsScanTimeout instead. Callers must hold ti.mu.
+func (ti *termIndex) startScan(ctx context.Context, list func(context.Context, CaseFilter) ([]*models.Case, error)) {
+	ti.loading = make(chan struct{})
+	ti.stale = false
+	ti.pending = nil
+
+	scanCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), termStatsScanTimeout)
+	go func() {
+		defer cancel()
+		ti.finishScan(scanTermStats(scanCtx, list))
+	}()
+}
+
+// finishScan installs the stats from a scan, after replaying the writes made
+// while it ran
+func (ti *termIndex) finishScan(stats *TermStats, err error) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.loadErr = err
+	if err == nil {
+		for _, w := range ti.pending {
+			if w.doc != nil {
+				stats.put(w.id, *w.doc)
+			} else {
+				stats.Remove(w.id)
+			}
+		}
+		ti.stats = stats
+		ti.seededAt = time.Now()
+	}
+
+	ti.pending = nil
+	close(ti.loading)
+	ti.loading = nil
+}
+
+// scanTermStats computes term stats over every case list returns
+func scanTermStats(ctx context.Context, list func(context.Context, CaseFilter) ([]*models.Case, error)) (*TermStats, error) {
+	// Page in ID order so rows sharing a timestamp aren't skipped or repeated
+	stats := NewTermStats()
+	for offset := 0; ; offset += termStatsPageSize {
+		cases, err := list(ctx, CaseFilter{Limit: termStatsPageSize, Offset: offset, OrderBy: "id", IncludeFullText: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cases {
+			stats.Add(c)
+		}
+		if len(cases) < termStatsPageSize {
+			break
+		}
+	}
+	return stats, nil
+}
+
+// add records a written case if the stats have been seeded or are being scanned
+func (ti *termIndex) add(c *models.Case) {
+	doc := caseTermDoc(c)
+
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if ti.stats != nil {
+		ti.stats.put(c.ID, doc)
+	}
+	if ti.loading != nil {
+		ti.pending = append(ti.pending, termWrite{id: c.ID, doc: &doc})
+	}
+}
+
+// remove withdraws a deleted case if the stats have been seeded or are being
+// scanned
+func (ti *termIndex) remove(id string) {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	if ti.stats != nil {
+		ti.stats.Remove(id)
+	}
+	if ti.loading != nil {
+		ti.pending = append(ti.pending, termWrite{id: id})
+	}
+}
+
+// reset drops the stats so the next load reseeds them, used after bulk writes
+// that don't report which cases they touched. A scan already running may have
+// missed the writes, so its result is recomputed once it lands.
+func (ti *termIndex) reset() {
+	ti.mu.Lock()
+	defer ti.mu.Unlock()
+
+	ti.stats = nil
+	ti.stale = true
+}
@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TimelinePeriod is the width of the buckets in a timeline aggregation
+type TimelinePeriod string
+
+const (
+	TimelineYear    TimelinePeriod = "year"
+	TimelineQuarter TimelinePeriod = "quarter"
+	TimelineMonth   TimelinePeriod = "month"
+)
+
+// Valid reports whether p is a supported timeline period
+func (p TimelinePeriod) Valid() bool {
+	switch p {
+	case TimelineYear, TimelineQuarter, TimelineMonth:
+		return true
+	}
+	return false
+}
+
+// Bucket returns the label of the bucket containing t: "2019", "2019-Q2" or "2019-04"
+func (p TimelinePeriod) Bucket(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case TimelineQuarter:
+		return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())+2)/3)
+	case TimelineMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006")
+	}
+}
+
+// TimelineBucket counts the matching cases decided in one period
+type TimelineBucket struct {
+	Period string `json:"period"`
+	Count  int    `json:"count"`
+}
+
+// TimelineAggregator is implemented by backends that can count the cases
+// matching a search per decision period without loading them
+type TimelineAggregator interface {
+	AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error)
+}
+
+// AggregateTimeline counts the cases matching query per decision period,
+// ignoring its pagination. Backends implementing TimelineAggregator group by
+// date in the database; others fall back to bucketing the searched cases in
+// memory.
+func AggregateTimeline(ctx context.Context, store Storage, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	if aggregator, ok := store.(TimelineAggregator); ok {
+		return aggregator.AggregateTimeline(ctx, query, period)
+	}
+
+	query.Limit, query.Offset = 0, 0
+	query.Filters.Limit, query.Filters.Offset = 0, 0
+	cases, err := store.SearchCases(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return CountTimeline(cases, period), nil
+}
+
+// CountTimeline buckets cases by decision date, in period order. Cases without
+// a decision date are left out.
+func CountTimeline(cases []*models.Case, period TimelinePeriod) []TimelineBucket {
+	counts := make(map[string]int)
+	for _, c := range cases {
+		if c.DecisionDate != nil {
+			counts[period.Bucket(*c.DecisionDate)]++
+		}
+	}
+
+	buckets := make([]TimelineBucket, 0, len(counts))
+	for label, count := range counts {
+		buckets = append(buckets, TimelineBucket{Period: label, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Period < buckets[j].Period
+	})
+
+	return buckets
+}
+
+// timelineExpressions maps timeline periods to SQL bucket labels per dialect
+var timelineExpressions = map[string]map[TimelinePeriod]string{
+	"sqlite": {
+		TimelineYear:    "strftime('%Y', c.decision_date)",
+		TimelineQuarter: "strftime('%Y', c.decision_date) || '-Q' || ((CAST(strftime('%m', c.decision_date) AS INTEGER) + 2) / 3)",
+		TimelineMonth:   "strftime('%Y-%m', c.decision_date)",
+	},
+	"postgres": {
+		TimelineYear:    `to_char(c.decision_date, 'YYYY')`,
+		TimelineQuarter: `to_char(c.decision_date, 'YYYY-"Q"Q')`,
+		TimelineMonth:   `to_char(c.decision_date, 'YYYY-MM')`,
+	},
+}
+
+// mongoTimelineExpressions maps timeline periods to $group keys
+var mongoTimelineExpressions = map[TimelinePeriod]interface{}{
+	TimelineYear: bson.M{"$dateToString": bson.M{"format": "%Y", "date": "$decision_date"}},
+	TimelineQuarter: bson.M{"$concat": bson.A{
+		bson.M{"$dateToString": bson.M{"format": "%Y", "date": "$decision_date"}},
+		"-Q",
+		bson.M{"$toString": bson.M{"$toInt": bson.M{"$ceil": bson.M{"$divide": bson.A{bson.M{"$month": "$decision_date"}, 3}}}}},
+	}},
+	TimelineMonth: bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$decision_date"}},
+}
+
+// aggregateSQLTimeline counts the cases selected by from and where per
+// period, in period order
+func aggregateSQLTimeline(ctx context.Context, db *sql.DB, dialect, from, where string, args []interface{}, period TimelinePeriod) ([]TimelineBucket, error) {
+	expr, ok := timelineExpressions[dialect][period]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeline period: %q", period)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT %s AS period, COUNT(*) FROM %s WHERE %s AND c.decision_date IS NOT NULL GROUP BY 1 ORDER BY 1",
+		expr, from, where,
+	)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate timeline: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := make([]TimelineBucket, 0)
+	for rows.Next() {
+		var b TimelineBucket
+		if err := rows.Scan(&b.Period, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
+
+// AggregateTimeline counts the cases matching the search per decision period
+func (ss *SQLiteStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	from, where, args := sqliteSearchScope(query)
+	return aggregateSQLTimeline(ctx, ss.db, "sqlite", from, where, args, period)
+}
+
+// AggregateTimeline counts the cases matching the search per decision period
+func (ps *PostgresStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	where, args := postgresSearchScope(query, "c")
+	return aggregateSQLTimeline(ctx, ps.db, "postgres", "cases c", where, args, period)
+}
+
+// AggregateTimeline counts the cases matching the search per decision period
+func (ms *MongoStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	expr, ok := mongoTimelineExpressions[period]
+	if !ok {
+		return nil, fmt.Errorf("unsupported timeline period: %q", period)
+	}
+
+	match, err := ms.searchMatch(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	match["decision_date"] = bson.M{"$type": "date"}
+
+	pipeline := bson.A{
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{"_id": expr, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+	}
+
+	cursor, err := ms.cases.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate timeline: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Period string `bson:"_id"`
+		Count  int    `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode timeline: %w", err)
+	}
+
+	buckets := make([]TimelineBucket, len(results))
+	for i, r := range results {
+		buckets[i] = TimelineBucket{Period: r.Period, Count: r.Count}
+	}
+
+	return buckets, nil
+}
@@ -0,0 +1,402 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// DefaultQueryTimeout is the default bound on a storage call
+const DefaultQueryTimeout = 30 * time.Second
+
+// ErrQueryTimeout is returned when a storage call runs past its timeout
+var ErrQueryTimeout = errors.New("storage operation timed out")
+
+// TimeoutOperations names the operations TimeoutConfig.Operations may bound,
+// one per Storage method plus one per method of the optional interfaces, such
+// as save_cases for batch saves
+var TimeoutOperations = []string{
+	"save_case", "create_case", "get_case", "update_case", "delete_case",
+	"delete_cases_by_filter", "list_cases", "count_cases",
+	"save_judge", "get_judge", "update_judge", "list_judges",
+	"save_citation", "get_citation", "list_citations",
+	"search_cases", "suggest_case_names", "suggest_judge_names", "suggest_concepts",
+	"ping", "save_cases",
+	"aggregate_facets", "aggregate_timeline", "term_stats", "stream_cases",
+	"existing_ids", "get_content_hash", "get_case_full_text",
+	"list_cases_by_judge", "list_cases_by_concept", "count_concept_cooccurrence",
+	"save_citations",
+}
+
+// TimeoutMetrics records storage calls that timed out (satisfied by
+// observability.Metrics)
+type TimeoutMetrics interface {
+	RecordStorageError(operation, errorType string)
+}
+
+// TimeoutConfig bounds how long storage calls may run
+type TimeoutConfig struct {
+	// Default bounds operations without their own timeout (0 leaves them
+	// unbounded)
+	Default time.Duration
+	// Operations overrides Default by operation name, e.g. search_cases
+	Operations map[string]time.Duration
+	// Metrics counts timeouts, and may be nil
+	Metrics TimeoutMetrics
+}
+
+// TimeoutStorage runs each storage call with a deadline, so that a hung query
+// can't block a worker or request indefinitely. A call that overruns returns
+// ErrQueryTimeout; deadlines and cancellation from the caller's own context
+// are passed through unchanged. Transactions are not bounded, as they outlive
+// the BeginTx call.
+type TimeoutStorage struct {
+	Storage
+	config TimeoutConfig
+}
+
+// NewTimeoutStorage wraps storage with per-operation timeouts, rejecting
+// unknown operation names and negative timeouts
+func NewTimeoutStorage(store Storage, config TimeoutConfig) (*TimeoutStorage, error) {
+	if config.Default < 0 {
+		return nil, fmt.Errorf("storage timeout cannot be negative")
+	}
+	for op, timeout := range config.Operations {
+		if !knownTimeoutOperation(op) {
+			return nil, fmt.Errorf("unknown storage operation %q", op)
+		}
+		if timeout < 0 {
+			return nil, fmt.Errorf("storage timeout for %s cannot be negative", op)
+		}
+	}
+	return &TimeoutStorage{Storage: store, config: config}, nil
+}
+
+func knownTimeoutOperation(op string) bool {
+	for _, known := range TimeoutOperations {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Timeout returns the bound on an operation, 0 if it is unbounded
+func (t *TimeoutStorage) Timeout(op string) time.Duration {
+	if timeout, ok := t.config.Operations[op]; ok {
+		return timeout
+	}
+	return t.config.Default
+}
+
+// run calls fn with ctx bounded by the operation's timeout, replacing the
+// error of a call that overran it with ErrQueryTimeout
+func (t *TimeoutStorage) run(ctx context.Context, op string, fn func(ctx context.Context) error) error {
+	timeout := t.Timeout(op)
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	bounded, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(bounded)
+	if err != nil && ctx.Err() == nil && errors.Is(bounded.Err(), context.DeadlineExceeded) {
+		if t.config.Metrics != nil {
+			t.config.Metrics.RecordStorageError(op, "timeout")
+		}
+		return fmt.Errorf("%w: %s after %s", ErrQueryTimeout, op, timeout)
+	}
+	return err
+}
+
+// SaveCase saves a case within the save_case timeout
+func (t *TimeoutStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	return t.run(ctx, "save_case", func(ctx context.Context) error {
+		return t.Storage.SaveCase(ctx, c)
+	})
+}
+
+// SaveCases saves a batch of cases within the save_cases timeout, one at a
+// time if the underlying storage has no batch save
+func (t *TimeoutStorage) SaveCases(ctx context.Context, cases []*models.Case) error {
+	return t.run(ctx, "save_cases", func(ctx context.Context) error {
+		if saver, ok := t.Storage.(CaseBatchSaver); ok {
+			return saver.SaveCases(ctx, cases)
+		}
+		for _, c := range cases {
+			if err := t.Storage.SaveCase(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// CreateCase creates a case within the create_case timeout
+func (t *TimeoutStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	return t.run(ctx, "create_case", func(ctx context.Context) error {
+		return t.Storage.CreateCase(ctx, c)
+	})
+}
+
+// GetCase loads a case within the get_case timeout
+func (t *TimeoutStorage) GetCase(ctx context.Context, id string) (c *models.Case, err error) {
+	err = t.run(ctx, "get_case", func(ctx context.Context) error {
+		c, err = t.Storage.GetCase(ctx, id)
+		return err
+	})
+	return c, err
+}
+
+// UpdateCase updates a case within the update_case timeout
+func (t *TimeoutStorage) UpdateCase(ctx context.Context, c *models.Case) error {
+	return t.run(ctx, "update_case", func(ctx context.Context) error {
+		return t.Storage.UpdateCase(ctx, c)
+	})
+}
+
+// DeleteCase deletes a case within the delete_case timeout
+func (t *TimeoutStorage) DeleteCase(ctx context.Context, id string) error {
+	return t.run(ctx, "delete_case", func(ctx context.Context) error {
+		return t.Storage.DeleteCase(ctx, id)
+	})
+}
+
+// DeleteCasesByFilter deletes matching cases within the
+// delete_cases_by_filter timeout
+func (t *TimeoutStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (n int64, err error) {
+	err = t.run(ctx, "delete_cases_by_filter", func(ctx context.Context) error {
+		n, err = t.Storage.DeleteCasesByFilter(ctx, filter, opts)
+		return err
+	})
+	return n, err
+}
+
+// ListCases lists cases within the list_cases timeout
+func (t *TimeoutStorage) ListCases(ctx context.Context, filter CaseFilter) (cases []*models.Case, err error) {
+	err = t.run(ctx, "list_cases", func(ctx context.Context) error {
+		cases, err = t.Storage.ListCases(ctx, filter)
+		return err
+	})
+	return cases, err
+}
+
+// CountCases counts cases within the count_cases timeout
+func (t *TimeoutStorage) CountCases(ctx context.Context, filter CaseFilter) (n int64, err error) {
+	err = t.run(ctx, "count_cases", func(ctx context.Context) error {
+		n, err = t.Storage.CountCases(ctx, filter)
+		return err
+	})
+	return n, err
+}
+
+// SaveJudge saves a judge within the save_judge timeout
+func (t *TimeoutStorage) SaveJudge(ctx context.Context, j *models.Judge) error {
+	return t.run(ctx, "save_judge", func(ctx context.Context) error {
+		return t.Storage.SaveJudge(ctx, j)
+	})
+}
+
+// GetJudge loads a judge within the get_judge timeout
+func (t *TimeoutStorage) GetJudge(ctx context.Context, id string) (j *models.Judge, err error) {
+	err = t.run(ctx, "get_judge", func(ctx context.Context) error {
+		j, err = t.Storage.GetJudge(ctx, id)
+		return err
+	})
+	return j, err
+}
+
+// UpdateJudge updates a judge within the update_judge timeout
+func (t *TimeoutStorage) UpdateJudge(ctx context.Context, j *models.Judge) error {
+	return t.run(ctx, "update_judge", func(ctx context.Context) error {
+		return t.Storage.UpdateJudge(ctx, j)
+	})
+}
+
+// ListJudges lists judges within the list_judges timeout
+func (t *TimeoutStorage) ListJudges(ctx context.Context, filter JudgeFilter) (judges []*models.Judge, err error) {
+	err = t.run(ctx, "list_judges", func(ctx context.Context) error {
+		judges, err = t.Storage.ListJudges(ctx, filter)
+		return err
+	})
+	return judges, err
+}
+
+// SaveCitation saves a citation within the save_citation timeout
+func (t *TimeoutStorage) SaveCitation(ctx context.Context, c *models.Citation) error {
+	return t.run(ctx, "save_citation", func(ctx context.Context) error {
+		return t.Storage.SaveCitation(ctx, c)
+	})
+}
+
+// GetCitation loads a citation within the get_citation timeout
+func (t *TimeoutStorage) GetCitation(ctx context.Context, id string) (c *models.Citation, err error) {
+	err = t.run(ctx, "get_citation", func(ctx context.Context) error {
+		c, err = t.Storage.GetCitation(ctx, id)
+		return err
+	})
+	return c, err
+}
+
+// ListCitations lists citations within the list_citations timeout
+func (t *TimeoutStorage) ListCitations(ctx context.Context, filter CitationFilter) (citations []*models.Citation, err error) {
+	err = t.run(ctx, "list_citations", func(ctx context.Context) error {
+		citations, err = t.Storage.ListCitations(ctx, filter)
+		return err
+	})
+	return citations, err
+}
+
+// SearchCases searches cases within the search_cases timeout
+func (t *TimeoutStorage) SearchCases(ctx context.Context, query SearchQuery) (cases []*models.Case, err error) {
+	err = t.run(ctx, "search_cases", func(ctx context.Context) error {
+		cases, err = t.Storage.SearchCases(ctx, query)
+		return err
+	})
+	return cases, err
+}
+
+// SuggestCaseNames suggests case names within the suggest_case_names timeout
+func (t *TimeoutStorage) SuggestCaseNames(ctx context.Context, prefix string, filter CaseFilter, limit int) (names []string, err error) {
+	err = t.run(ctx, "suggest_case_names", func(ctx context.Context) error {
+		names, err = t.Storage.SuggestCaseNames(ctx, prefix, filter, limit)
+		return err
+	})
+	return names, err
+}
+
+// SuggestJudgeNames suggests judge names within the suggest_judge_names
+// timeout
+func (t *TimeoutStorage) SuggestJudgeNames(ctx context.Context, prefix string, filter JudgeFilter, limit int) (names []string, err error) {
+	err = t.run(ctx, "suggest_judge_names", func(ctx context.Context) error {
+		names, err = t.Storage.SuggestJudgeNames(ctx, prefix, filter, limit)
+		return err
+	})
+	return names, err
+}
+
+// SuggestConcepts suggests concepts within the suggest_concepts timeout
+func (t *TimeoutStorage) SuggestConcepts(ctx context.Context, prefix string, filter CaseFilter, limit int) (concepts []string, err error) {
+	err = t.run(ctx, "suggest_concepts", func(ctx context.Context) error {
+		concepts, err = t.Storage.SuggestConcepts(ctx, prefix, filter, limit)
+		return err
+	})
+	return concepts, err
+}
+
+// Ping checks the storage within the ping timeout
+func (t *TimeoutStorage) Ping(ctx context.Context) error {
+	return t.run(ctx, "ping", func(ctx context.Context) error {
+		return t.Storage.Ping(ctx)
+	})
+}
+
+// AggregateFacets counts facet values within the aggregate_facets timeout
+func (t *TimeoutStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (agg *FacetAggregation, err error) {
+	err = t.run(ctx, "aggregate_facets", func(ctx context.Context) error {
+		agg, err = AggregateFacets(ctx, t.Storage, query, fields)
+		return err
+	})
+	return agg, err
+}
+
+// AggregateTimeline counts cases per period within the aggregate_timeline
+// timeout
+func (t *TimeoutStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) (buckets []TimelineBucket, err error) {
+	err = t.run(ctx, "aggregate_timeline", func(ctx context.Context) error {
+		buckets, err = AggregateTimeline(ctx, t.Storage, query, period)
+		return err
+	})
+	return buckets, err
+}
+
+// TermStats loads term statistics within the term_stats timeout
+func (t *TimeoutStorage) TermStats(ctx context.Context) (stats *TermStats, err error) {
+	err = t.run(ctx, "term_stats", func(ctx context.Context) error {
+		stats, err = CorpusTermStats(ctx, t.Storage)
+		return err
+	})
+	return stats, err
+}
+
+// StreamCases streams matching cases within the stream_cases timeout if the
+// underlying storage streams them itself. Otherwise each page is listed within
+// the list_cases timeout, so a long stream isn't cut short as a whole.
+func (t *TimeoutStorage) StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error {
+	if streamer, ok := t.Storage.(CaseStreamer); ok {
+		return t.run(ctx, "stream_cases", func(ctx context.Context) error {
+			return streamer.StreamCases(ctx, filter, fn)
+		})
+	}
+	return streamPages(ctx, t, filter, fn)
+}
+
+// ExistingIDs looks up stored case IDs within the existing_ids timeout
+func (t *TimeoutStorage) ExistingIDs(ctx context.Context, ids []string) (existing map[string]bool, err error) {
+	err = t.run(ctx, "existing_ids", func(ctx context.Context) error {
+		existing, err = StoredCaseIDs(ctx, t.Storage, ids)
+		return err
+	})
+	return existing, err
+}
+
+// GetContentHash reads a case's content hash within the get_content_hash
+// timeout
+func (t *TimeoutStorage) GetContentHash(ctx context.Context, id string) (hash string, err error) {
+	err = t.run(ctx, "get_content_hash", func(ctx context.Context) error {
+		hash, err = StoredContentHash(ctx, t.Storage, id)
+		return err
+	})
+	return hash, err
+}
+
+// GetCaseFullText loads a case's full text within the get_case_full_text
+// timeout
+func (t *TimeoutStorage) GetCaseFullText(ctx context.Context, id string) (text string, err error) {
+	err = t.run(ctx, "get_case_full_text", func(ctx context.Context) error {
+		text, err = CaseFullText(ctx, t.Storage, id)
+		return err
+	})
+	return text, err
+}
+
+// ListCasesByJudge pages through a judge's cases within the
+// list_cases_by_judge timeout
+func (t *TimeoutStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) (cases []*models.Case, total int64, err error) {
+	err = t.run(ctx, "list_cases_by_judge", func(ctx context.Context) error {
+		cases, total, err = CasesByJudge(ctx, t.Storage, judgeID, filter)
+		return err
+	})
+	return cases, total, err
+}
+
+// ListCasesByConcept pages through a concept's cases within the
+// list_cases_by_concept timeout
+func (t *TimeoutStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) (cases []*models.Case, total int64, err error) {
+	err = t.run(ctx, "list_cases_by_concept", func(ctx context.Context) error {
+		cases, total, err = CasesByConcept(ctx, t.Storage, concept, filter)
+		return err
+	})
+	return cases, total, err
+}
+
+// CountConceptCooccurrence counts related concepts within the
+// count_concept_cooccurrence timeout
+func (t *TimeoutStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) (pairs []ConceptPair, err error) {
+	err = t.run(ctx, "count_concept_cooccurrence", func(ctx context.Context) error {
+		pairs, err = ConceptCooccurrence(ctx, t.Storage, concept, filter, limit)
+		return err
+	})
+	return pairs, err
+}
+
+// SaveCitations saves a batch of citations within the save_citations timeout
+func (t *TimeoutStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	return t.run(ctx, "save_citations", func(ctx context.Context) error {
+		return SaveCitations(ctx, t.Storage, citations)
+	})
+}
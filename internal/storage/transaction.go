@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
 
 	"github.com/gongahkia/kite/pkg/models"
 )
@@ -27,9 +28,9 @@ func (t *SQLTransaction) SaveCase(ctx context.Context, c *models.Case) error {
 	query := `
 		INSERT OR REPLACE INTO cases (
 			id, case_number, case_name, decision_date, court, court_level, court_type,
-			jurisdiction, docket, parties, judges, summary, full_text, key_issues,
+			jurisdiction, docket, parties, judges, summary, key_issues,
 			legal_concepts, outcome, procedural_history, citations, url, pdf_url,
-			source_database, scraped_at, last_updated, language, status
+			source_database, scraped_at, last_updated, language, status, tenant_id
 		) VALUES (
 			?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 		)
@@ -37,13 +38,16 @@ func (t *SQLTransaction) SaveCase(ctx context.Context, c *models.Case) error {
 
 	_, err := t.tx.ExecContext(ctx, query,
 		c.ID, c.CaseNumber, c.CaseName, c.DecisionDate, c.Court, c.CourtLevel, c.CourtType,
-		c.Jurisdiction, c.Docket, toJSONString(c.Parties), toJSONString(c.Judges), c.Summary, c.FullText,
+		c.Jurisdiction, c.Docket, toJSONString(c.Parties), toJSONString(c.Judges), c.Summary,
 		toJSONString(c.KeyIssues), toJSONString(c.LegalConcepts), c.Outcome, c.ProceduralHistory,
 		toJSONString(c.Citations), c.URL, c.PDFURL, c.SourceDatabase, c.ScrapedAt, c.LastUpdated,
-		c.Language, c.Status,
+		c.Language, c.Status, c.TenantID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return saveSQLFullText(ctx, t.tx, c, positionalPlaceholder)
 }
 
 // SaveJudge saves a judge within the transaction
@@ -51,13 +55,14 @@ func (t *SQLTransaction) SaveJudge(ctx context.Context, j *models.Judge) error {
 	query := `
 		INSERT OR REPLACE INTO judges (
 			id, name, full_name, title, court, jurisdiction, appointed_date,
-			biography, education, career, notable_cases, total_cases
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			biography, education, career, notable_cases, total_cases, tenant_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := t.tx.ExecContext(ctx, query,
 		j.ID, j.Name, j.FullName, j.Title, j.Court, j.Jurisdiction, j.AppointedDate,
 		j.Biography, toJSONString(j.Education), toJSONString(j.Career), toJSONString(j.NotableCases), j.TotalCases,
+		j.TenantID,
 	)
 
 	return err
@@ -68,13 +73,14 @@ func (t *SQLTransaction) SaveCitation(ctx context.Context, c *models.Citation) e
 	query := `
 		INSERT INTO citations (
 			format, raw_citation, normalized_citation, volume, reporter, page, year,
-			court, case_number, country, citing_case_id, cited_case_id, is_normalized
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			court, case_number, country, citing_case_id, cited_case_id, is_normalized, tenant_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := t.tx.ExecContext(ctx, query,
 		c.Format, c.RawCitation, c.NormalizedCitation, c.Volume, c.Reporter, c.Page, c.Year,
 		c.Court, c.CaseNumber, c.Country, c.CitingCaseID, c.CitedCaseID, boolToInt(c.IsNormalized),
+		c.TenantID,
 	)
 
 	if err != nil {
@@ -83,7 +89,7 @@ func (t *SQLTransaction) SaveCitation(ctx context.Context, c *models.Citation) e
 
 	id, err := result.LastInsertId()
 	if err == nil {
-		c.ID = int(id)
+		c.ID = strconv.FormatInt(id, 10)
 	}
 
 	return nil
@@ -91,7 +97,15 @@ func (t *SQLTransaction) SaveCitation(ctx context.Context, c *models.Citation) e
 
 // Commit commits the transaction
 func (t *SQLTransaction) Commit() error {
-	return t.tx.Commit()
+	if err := t.tx.Commit(); err != nil {
+		return err
+	}
+
+	// Cases written inside the transaction bypass the term index
+	if ss, ok := t.storage.(*SQLiteStorage); ok {
+		ss.terms.reset()
+	}
+	return nil
 }
 
 // Rollback rolls back the transaction
@@ -99,6 +113,24 @@ func (t *SQLTransaction) Rollback() error {
 	return t.tx.Rollback()
 }
 
+// runSQLTx runs fn in a transaction on db, committing if fn succeeds. fn's
+// error is returned as is, so callers can still inspect driver errors.
+func runSQLTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // BeginTx starts a transaction for SQLiteStorage
 func (ss *SQLiteStorage) BeginTx(ctx context.Context) (Transaction, error) {
 	tx, err := ss.db.BeginTx(ctx, nil)
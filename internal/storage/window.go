@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/gongahkia/kite/pkg/errors"
+)
+
+// Default result window bounds
+const (
+	DefaultPageSize  = 20
+	DefaultMaxLimit  = 1000
+	DefaultMaxOffset = 10000
+)
+
+// ResultWindow bounds how many results a single list or search request may
+// return and how deep it may page. A zero bound is unlimited.
+type ResultWindow struct {
+	// DefaultLimit is the page size of requests that set no limit, or zero to
+	// pass them through unlimited. Storage backends return every match for a
+	// zero limit, so API layers apply it through Limit before querying.
+	DefaultLimit int `json:"default_limit"`
+	MaxLimit     int `json:"max_limit"`
+	MaxOffset    int `json:"max_offset"`
+}
+
+// DefaultResultWindow returns the default result window
+func DefaultResultWindow() ResultWindow {
+	return ResultWindow{
+		DefaultLimit: DefaultPageSize,
+		MaxLimit:     DefaultMaxLimit,
+		MaxOffset:    DefaultMaxOffset,
+	}
+}
+
+// Limit returns the page size for a requested limit: the default page size,
+// capped at MaxLimit, if no limit was requested
+func (w ResultWindow) Limit(limit int) int {
+	if limit != 0 || w.DefaultLimit <= 0 {
+		return limit
+	}
+	if w.MaxLimit > 0 && w.DefaultLimit > w.MaxLimit {
+		return w.MaxLimit
+	}
+	return w.DefaultLimit
+}
+
+// Check returns a validation error if limit or offset falls outside the window
+func (w ResultWindow) Check(limit, offset int) error {
+	if limit < 0 {
+		return errors.ValidationError("limit must be non-negative", errors.ErrInvalidData)
+	}
+	if offset < 0 {
+		return errors.ValidationError("offset must be non-negative", errors.ErrInvalidData)
+	}
+	if w.MaxLimit > 0 && limit > w.MaxLimit {
+		return errors.ValidationError(fmt.Sprintf("limit %d exceeds maximum of %d", limit, w.MaxLimit), errors.ErrResultWindow)
+	}
+	if w.MaxOffset > 0 && offset > w.MaxOffset {
+		return errors.ValidationError(fmt.Sprintf("offset %d exceeds maximum of %d", offset, w.MaxOffset), errors.ErrResultWindow)
+	}
+	return nil
+}
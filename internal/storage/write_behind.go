@@ -0,0 +1,401 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// ErrWriteBehindClosed is returned when saving to a write-behind buffer that has been closed
+var ErrWriteBehindClosed = errors.New("write-behind buffer is closed")
+
+// CaseBatchSaver is implemented by backends that can save many cases in one
+// round trip
+type CaseBatchSaver interface {
+	SaveCases(ctx context.Context, cases []*models.Case) error
+}
+
+// WriteBehindLogger receives failed writes (satisfied by observability.Logger)
+type WriteBehindLogger interface {
+	Errorf(format string, args ...interface{})
+}
+
+// WriteBehindMetrics counts cases the buffer failed to write (satisfied by
+// observability.Metrics)
+type WriteBehindMetrics interface {
+	RecordStorageError(operation, errorType string)
+}
+
+// WriteBehindConfig configures the write-behind buffer
+type WriteBehindConfig struct {
+	// BatchSize flushes the buffer once this many cases are waiting
+	BatchSize int
+	// FlushInterval flushes whatever is waiting at least this often
+	FlushInterval time.Duration
+	// BufferSize bounds the cases held in memory; saves block once it is full
+	BufferSize int
+	// WriteTimeout bounds each batch write, and each single-case retry of a
+	// failed batch
+	WriteTimeout time.Duration
+	// Logger receives cases that could not be written, and may be nil
+	Logger WriteBehindLogger
+	// Metrics counts cases that could not be written, and may be nil
+	Metrics WriteBehindMetrics
+}
+
+// DefaultWriteBehindConfig returns the default write-behind configuration
+func DefaultWriteBehindConfig() WriteBehindConfig {
+	return WriteBehindConfig{
+		BatchSize:     100,
+		FlushInterval: time.Second,
+		BufferSize:    1000,
+		WriteTimeout:  30 * time.Second,
+	}
+}
+
+// WriteBehindStorage buffers SaveCase calls in memory and writes them to the
+// underlying storage in batches, on a size or time trigger. SaveCase blocks
+// while the buffer is full. GetCase sees buffered cases; other reads may lag
+// by up to FlushInterval. Updates and deletes flush the buffer first so they
+// apply in order. Close flushes everything before closing the storage.
+type WriteBehindStorage struct {
+	Storage
+	config WriteBehindConfig
+
+	queue   chan *models.Case
+	flushes chan chan struct{}
+	closing chan struct{}
+	stopped chan struct{}
+
+	sendMu    sync.RWMutex // held by senders; taken exclusively to close
+	closed    bool
+	closeOnce sync.Once
+
+	pendingMu sync.Mutex
+	pending   map[string]*models.Case
+
+	failed atomic.Int64
+}
+
+// NewWriteBehindStorage wraps storage with a write-behind buffer
+func NewWriteBehindStorage(store Storage, config WriteBehindConfig) *WriteBehindStorage {
+	defaults := DefaultWriteBehindConfig()
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaults.BatchSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = defaults.FlushInterval
+	}
+	if config.BufferSize < config.BatchSize {
+		config.BufferSize = config.BatchSize
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = defaults.WriteTimeout
+	}
+
+	w := &WriteBehindStorage{
+		Storage: store,
+		config:  config,
+		queue:   make(chan *models.Case, config.BufferSize),
+		flushes: make(chan chan struct{}),
+		closing: make(chan struct{}),
+		stopped: make(chan struct{}),
+		pending: make(map[string]*models.Case),
+	}
+	go w.run()
+	return w
+}
+
+// SaveCase buffers a case for the next flush, blocking while the buffer is full
+func (w *WriteBehindStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	w.sendMu.RLock()
+	defer w.sendMu.RUnlock()
+
+	if w.closed {
+		return ErrWriteBehindClosed
+	}
+
+	w.pendingMu.Lock()
+	w.pending[c.ID] = c
+	w.pendingMu.Unlock()
+
+	select {
+	case w.queue <- c:
+		return nil
+	case <-ctx.Done():
+		w.forget([]*models.Case{c})
+		return ctx.Err()
+	}
+}
+
+// buffered returns the case waiting to be written under id, if any
+func (w *WriteBehindStorage) buffered(id string) (*models.Case, bool) {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+
+	c, ok := w.pending[id]
+	return c, ok
+}
+
+// GetCase returns a buffered case if one is waiting, otherwise reads from storage
+func (w *WriteBehindStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	if c, ok := w.buffered(id); ok {
+		return c, nil
+	}
+	return w.Storage.GetCase(ctx, id)
+}
+
+// ExistingIDs reports buffered cases as stored, and looks the rest up in storage
+func (w *WriteBehindStorage) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	existing, err := StoredCaseIDs(ctx, w.Storage, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, ok := w.buffered(id); ok {
+			existing[id] = true
+		}
+	}
+	return existing, nil
+}
+
+// GetContentHash returns a buffered case's content hash if one is waiting,
+// otherwise reads it from storage
+func (w *WriteBehindStorage) GetContentHash(ctx context.Context, id string) (string, error) {
+	if c, ok := w.buffered(id); ok {
+		return c.ContentHash, nil
+	}
+	return StoredContentHash(ctx, w.Storage, id)
+}
+
+// GetCaseFullText returns a buffered case's full text if one is waiting,
+// otherwise loads it from storage
+func (w *WriteBehindStorage) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	if c, ok := w.buffered(id); ok {
+		return c.FullText, nil
+	}
+	return CaseFullText(ctx, w.Storage, id)
+}
+
+// AggregateFacets counts facet values in storage; see storage.AggregateFacets
+func (w *WriteBehindStorage) AggregateFacets(ctx context.Context, query SearchQuery, fields []string) (*FacetAggregation, error) {
+	return AggregateFacets(ctx, w.Storage, query, fields)
+}
+
+// AggregateTimeline counts cases per period in storage; see
+// storage.AggregateTimeline
+func (w *WriteBehindStorage) AggregateTimeline(ctx context.Context, query SearchQuery, period TimelinePeriod) ([]TimelineBucket, error) {
+	return AggregateTimeline(ctx, w.Storage, query, period)
+}
+
+// TermStats returns the storage's term statistics; see storage.CorpusTermStats
+func (w *WriteBehindStorage) TermStats(ctx context.Context) (*TermStats, error) {
+	return CorpusTermStats(ctx, w.Storage)
+}
+
+// StreamCases streams matching cases from storage; see storage.StreamCases
+func (w *WriteBehindStorage) StreamCases(ctx context.Context, filter CaseFilter, fn func(*models.Case) error) error {
+	return StreamCases(ctx, w.Storage, filter, fn)
+}
+
+// ListCasesByJudge pages through a judge's cases in storage; see
+// storage.CasesByJudge
+func (w *WriteBehindStorage) ListCasesByJudge(ctx context.Context, judgeID string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByJudge(ctx, w.Storage, judgeID, filter)
+}
+
+// ListCasesByConcept pages through a concept's cases in storage; see
+// storage.CasesByConcept
+func (w *WriteBehindStorage) ListCasesByConcept(ctx context.Context, concept string, filter CaseFilter) ([]*models.Case, int64, error) {
+	return CasesByConcept(ctx, w.Storage, concept, filter)
+}
+
+// CountConceptCooccurrence counts related concepts in storage; see
+// storage.ConceptCooccurrence
+func (w *WriteBehindStorage) CountConceptCooccurrence(ctx context.Context, concept string, filter CaseFilter, limit int) ([]ConceptPair, error) {
+	return ConceptCooccurrence(ctx, w.Storage, concept, filter, limit)
+}
+
+// SaveCitations saves a batch of citations; see storage.SaveCitations
+func (w *WriteBehindStorage) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	return SaveCitations(ctx, w.Storage, citations)
+}
+
+// CreateCase flushes buffered saves, then creates the case, so a buffered save
+// of the same ID is seen as a conflict
+func (w *WriteBehindStorage) CreateCase(ctx context.Context, c *models.Case) error {
+	if err := w.Flush(ctx); err != nil {
+		return err
+	}
+	return w.Storage.CreateCase(ctx, c)
+}
+
+// UpdateCase flushes buffered saves, then updates the case
+func (w *WriteBehindStorage) UpdateCase(ctx context.Context, c *models.Case) error {
+	if err := w.Flush(ctx); err != nil {
+		return err
+	}
+	return w.Storage.UpdateCase(ctx, c)
+}
+
+// DeleteCase flushes buffered saves, then deletes the case
+func (w *WriteBehindStorage) DeleteCase(ctx context.Context, id string) error {
+	if err := w.Flush(ctx); err != nil {
+		return err
+	}
+	return w.Storage.DeleteCase(ctx, id)
+}
+
+// DeleteCasesByFilter flushes buffered saves, then deletes matching cases
+func (w *WriteBehindStorage) DeleteCasesByFilter(ctx context.Context, filter CaseFilter, opts DeleteOptions) (int64, error) {
+	if err := w.Flush(ctx); err != nil {
+		return 0, err
+	}
+	return w.Storage.DeleteCasesByFilter(ctx, filter, opts)
+}
+
+// Flush writes every buffered case and waits for the writes to finish
+func (w *WriteBehindStorage) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+
+	select {
+	case w.flushes <- done:
+	case <-w.stopped:
+		return nil // the final flush has already run
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes every buffered case and closes the underlying storage
+func (w *WriteBehindStorage) Close() error {
+	w.closeOnce.Do(func() {
+		// Wait out senders so nothing is queued after the final drain
+		w.sendMu.Lock()
+		w.closed = true
+		w.sendMu.Unlock()
+
+		close(w.closing)
+		<-w.stopped
+	})
+	return w.Storage.Close()
+}
+
+// run collects buffered cases and writes them in batches
+func (w *WriteBehindStorage) run() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.Case, 0, w.config.BatchSize)
+	for {
+		select {
+		case c := <-w.queue:
+			batch = append(batch, c)
+			if len(batch) >= w.config.BatchSize {
+				w.write(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			w.write(batch)
+			batch = batch[:0]
+		case done := <-w.flushes:
+			w.write(w.drain(batch))
+			batch = batch[:0]
+			close(done)
+		case <-w.closing:
+			w.write(w.drain(batch))
+			return
+		}
+	}
+}
+
+// drain appends every case waiting in the queue to batch
+func (w *WriteBehindStorage) drain(batch []*models.Case) []*models.Case {
+	for {
+		select {
+		case c := <-w.queue:
+			batch = append(batch, c)
+		default:
+			return batch
+		}
+	}
+}
+
+// FailedWrites returns the number of buffered cases that could not be written
+func (w *WriteBehindStorage) FailedWrites() int64 {
+	return w.failed.Load()
+}
+
+// write saves cases in chunks of BatchSize. A failed batch is retried one case
+// at a time so a single bad case doesn't take the rest of the batch with it.
+// Each write is bounded by WriteTimeout so a stalled backend can't hold up the
+// flush, and with it Flush and Close, indefinitely.
+func (w *WriteBehindStorage) write(cases []*models.Case) {
+	for start := 0; start < len(cases); start += w.config.BatchSize {
+		end := start + w.config.BatchSize
+		if end > len(cases) {
+			end = len(cases)
+		}
+		chunk := cases[start:end]
+
+		saver, ok := w.Storage.(CaseBatchSaver)
+		if !ok || w.bounded(func(ctx context.Context) error { return saver.SaveCases(ctx, chunk) }) != nil {
+			for _, c := range chunk {
+				if err := w.bounded(func(ctx context.Context) error { return w.Storage.SaveCase(ctx, c) }); err != nil {
+					w.fail(c, err)
+				}
+			}
+		}
+
+		w.forget(chunk)
+	}
+}
+
+// bounded runs a write with a context bounded by WriteTimeout
+func (w *WriteBehindStorage) bounded(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.WriteTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// fail counts, logs and records a case that could not be written
+func (w *WriteBehindStorage) fail(c *models.Case, err error) {
+	w.failed.Add(1)
+
+	if w.config.Logger != nil {
+		w.config.Logger.Errorf("write-behind: failed to save case %s: %v", c.ID, err)
+	}
+	if w.config.Metrics != nil {
+		errorType := "error"
+		if errors.Is(err, context.DeadlineExceeded) {
+			errorType = "timeout"
+		}
+		w.config.Metrics.RecordStorageError("write_behind_save", errorType)
+	}
+}
+
+// forget drops written cases from the pending set, unless they have since
+// been replaced by a newer save
+func (w *WriteBehindStorage) forget(cases []*models.Case) {
+	w.pendingMu.Lock()
+	defer w.pendingMu.Unlock()
+
+	for _, c := range cases {
+		if w.pending[c.ID] == c {
+			delete(w.pending, c.ID)
+		}
+	}
+}
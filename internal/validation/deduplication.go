@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,15 +13,153 @@ import (
 	"github.com/gongahkia/kite/pkg/models"
 )
 
+// HashTemplate is a combination of case fields that identifies duplicates:
+// cases whose fields hash alike are flagged as potential duplicates
+type HashTemplate struct {
+	Name   string   `yaml:"name"`
+	Fields []string `yaml:"fields"` // see HashFields; values are joined with "|"
+	// Normalize lowercases the value and strips punctuation before hashing
+	Normalize bool `yaml:"normalize"`
+	// AllowEmpty hashes the case even when some fields are empty; otherwise
+	// the template is skipped for it
+	AllowEmpty bool `yaml:"allow_empty"`
+	// MinLength skips values no longer than this; MaxLength hashes only a
+	// prefix of longer values (0 disables either)
+	MinLength int `yaml:"min_length"`
+	MaxLength int `yaml:"max_length"`
+}
+
+// hashFields reads the case fields hash templates may combine
+var hashFields = map[string]func(c *models.Case) string{
+	"case_number":     func(c *models.Case) string { return c.CaseNumber },
+	"case_name":       func(c *models.Case) string { return c.CaseName },
+	"court":           func(c *models.Case) string { return c.Court },
+	"court_id":        func(c *models.Case) string { return c.CourtID },
+	"court_level":     func(c *models.Case) string { return strconv.Itoa(int(c.CourtLevel)) },
+	"jurisdiction":    func(c *models.Case) string { return c.Jurisdiction },
+	"summary":         func(c *models.Case) string { return c.Summary },
+	"docket":          func(c *models.Case) string { return c.Docket },
+	"ecli":            func(c *models.Case) string { return c.ECLI },
+	"url":             func(c *models.Case) string { return c.URL },
+	"source_database": func(c *models.Case) string { return c.SourceDatabase },
+	"judges":          func(c *models.Case) string { return strings.Join(c.Judges, ",") },
+	"decision_date": func(c *models.Case) string {
+		if c.DecisionDate == nil {
+			return ""
+		}
+		return c.DecisionDate.Format("2006-01-02")
+	},
+}
+
+// HashFields returns the case fields hash templates may combine
+func HashFields() []string {
+	fields := make([]string, 0, len(hashFields))
+	for field := range hashFields {
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// DefaultHashTemplates returns the default duplicate hashes: case number,
+// normalized case name, court and case number, a summary fingerprint, and a
+// structural fingerprint of jurisdiction, court, name and level
+func DefaultHashTemplates() []HashTemplate {
+	return []HashTemplate{
+		{Name: "case_number", Fields: []string{"case_number"}},
+		{Name: "case_name", Fields: []string{"case_name"}, Normalize: true},
+		{Name: "court_case_number", Fields: []string{"court", "case_number"}},
+		{Name: "content_fingerprint", Fields: []string{"summary"}, MinLength: 100, MaxLength: 500},
+		{Name: "structural", Fields: []string{"jurisdiction", "court", "case_name", "court_level"}, AllowEmpty: true},
+	}
+}
+
+// ValidateHashTemplates checks that templates are named uniquely and combine
+// known fields
+func ValidateHashTemplates(templates []HashTemplate) error {
+	names := make(map[string]bool)
+	for _, t := range templates {
+		if t.Name == "" {
+			return fmt.Errorf("hash template needs a name")
+		}
+		if names[t.Name] {
+			return fmt.Errorf("duplicate hash template: %s", t.Name)
+		}
+		names[t.Name] = true
+
+		if len(t.Fields) == 0 {
+			return fmt.Errorf("hash template %s has no fields", t.Name)
+		}
+		for _, field := range t.Fields {
+			if _, ok := hashFields[field]; !ok {
+				return fmt.Errorf("hash template %s: unknown field %s", t.Name, field)
+			}
+		}
+		if t.MinLength < 0 || t.MaxLength < 0 {
+			return fmt.Errorf("hash template %s: lengths must not be negative", t.Name)
+		}
+	}
+	return nil
+}
+
+// value builds the string a template hashes for a case, reporting false when
+// the case doesn't have the fields the template needs
+func (t HashTemplate) value(c *models.Case) (string, bool) {
+	values := make([]string, 0, len(t.Fields))
+	for _, field := range t.Fields {
+		read, ok := hashFields[field]
+		if !ok {
+			return "", false
+		}
+		v := read(c)
+		if v == "" && !t.AllowEmpty {
+			return "", false
+		}
+		values = append(values, v)
+	}
+
+	value := strings.Join(values, "|")
+	if t.MinLength > 0 && len(value) <= t.MinLength {
+		return "", false
+	}
+	if t.MaxLength > 0 && len(value) > t.MaxLength {
+		value = value[:t.MaxLength]
+	}
+	if t.Normalize {
+		value = normalizeText(value)
+	}
+	return value, true
+}
+
+// Hash returns the template's hash of a case, reporting false when the case
+// doesn't have the fields the template needs
+func (t HashTemplate) Hash(c *models.Case) (string, bool) {
+	value, ok := t.value(c)
+	if !ok {
+		return "", false
+	}
+	return hashString(value), true
+}
+
 // DuplicationValidator detects duplicate cases
 type DuplicationValidator struct {
-	cache map[string]string // hash -> case_id
-	mu    sync.RWMutex
+	templates []HashTemplate
+	cache     map[string]string // hash -> case_id
+	mu        sync.RWMutex
 }
 
 func NewDuplicationValidator() *DuplicationValidator {
+	return NewDuplicationValidatorWithTemplates(nil)
+}
+
+// NewDuplicationValidatorWithTemplates creates a duplication validator that
+// hashes cases with the given templates, or the defaults if there are none
+func NewDuplicationValidatorWithTemplates(templates []HashTemplate) *DuplicationValidator {
+	if len(templates) == 0 {
+		templates = DefaultHashTemplates()
+	}
 	return &DuplicationValidator{
-		cache: make(map[string]string),
+		templates: templates,
+		cache:     make(map[string]string),
 	}
 }
 
@@ -76,45 +215,15 @@ func (v *DuplicationValidator) Validate(ctx context.Context, c *models.Case) (*V
 	return result, nil
 }
 
-// generateHashes generates multiple hashes for duplicate detection
+// generateHashes hashes a case with each template it has the fields for,
+// keyed by template name
 func (v *DuplicationValidator) generateHashes(c *models.Case) map[string]string {
 	hashes := make(map[string]string)
-
-	// Hash 1: Exact case number match
-	if c.CaseNumber != "" {
-		hashes["case_number"] = hashString(c.CaseNumber)
-	}
-
-	// Hash 2: Case name similarity (normalized)
-	if c.CaseName != "" {
-		normalized := normalizeText(c.CaseName)
-		hashes["case_name"] = hashString(normalized)
-	}
-
-	// Hash 3: Court + case number combination
-	if c.Court != "" && c.CaseNumber != "" {
-		combined := c.Court + "|" + c.CaseNumber
-		hashes["court_case_number"] = hashString(combined)
-	}
-
-	// Hash 4: Content fingerprint (first 500 chars of summary)
-	if c.Summary != "" && len(c.Summary) > 100 {
-		fingerprint := c.Summary
-		if len(fingerprint) > 500 {
-			fingerprint = fingerprint[:500]
+	for _, t := range v.templates {
+		if hash, ok := t.Hash(c); ok {
+			hashes[t.Name] = hash
 		}
-		hashes["content_fingerprint"] = hashString(fingerprint)
 	}
-
-	// Hash 5: Structural fingerprint
-	structural := fmt.Sprintf("%s|%s|%s|%d",
-		c.Jurisdiction,
-		c.Court,
-		c.CaseName,
-		c.CourtLevel,
-	)
-	hashes["structural"] = hashString(structural)
-
 	return hashes
 }
 
@@ -171,13 +280,21 @@ func normalizeText(text string) string {
 // DuplicateDetector provides advanced duplicate detection
 type DuplicateDetector struct {
 	validators map[string]*DuplicationValidator
+	templates  []HashTemplate
 	mu         sync.RWMutex
 }
 
 // NewDuplicateDetector creates a new duplicate detector
 func NewDuplicateDetector() *DuplicateDetector {
+	return NewDuplicateDetectorWithTemplates(nil)
+}
+
+// NewDuplicateDetectorWithTemplates creates a duplicate detector that hashes
+// cases with the given templates, or the defaults if there are none
+func NewDuplicateDetectorWithTemplates(templates []HashTemplate) *DuplicateDetector {
 	return &DuplicateDetector{
 		validators: make(map[string]*DuplicationValidator),
+		templates:  templates,
 	}
 }
 
@@ -188,7 +305,7 @@ func (dd *DuplicateDetector) DetectDuplicates(cases []*models.Case) map[string][
 	hashToCases := make(map[string][]string)
 
 	for _, c := range cases {
-		validator := NewDuplicationValidator()
+		validator := NewDuplicationValidatorWithTemplates(dd.templates)
 		hashes := validator.generateHashes(c)
 
 		for _, hash := range hashes {
@@ -66,6 +66,9 @@ type Pipeline struct {
 type PipelineConfig struct {
 	Concurrent bool
 	Stages     []ValidationStage
+	// DedupHashes are the field combinations the duplication stage compares
+	// (empty uses DefaultHashTemplates)
+	DedupHashes []HashTemplate
 }
 
 // NewPipeline creates a new validation pipeline
@@ -89,16 +92,16 @@ func NewPipeline(logger *observability.Logger, metrics *observability.Metrics, c
 		case StageQuality:
 			p.AddValidator(NewQualityValidator())
 		case StageDuplication:
-			p.AddValidator(NewDuplicationValidator())
+			p.AddValidator(NewDuplicationValidatorWithTemplates(config.DedupHashes))
 		}
 	}
 
 	return p
 }
 
-// DefaultPipeline creates a pipeline with all stages
-func DefaultPipeline(logger *observability.Logger, metrics *observability.Metrics) *Pipeline {
-	return NewPipeline(logger, metrics, &PipelineConfig{
+// DefaultPipelineConfig returns the configuration of a pipeline with all stages
+func DefaultPipelineConfig() *PipelineConfig {
+	return &PipelineConfig{
 		Concurrent: true,
 		Stages: []ValidationStage{
 			StageStructural,
@@ -107,7 +110,12 @@ func DefaultPipeline(logger *observability.Logger, metrics *observability.Metric
 			StageQuality,
 			StageDuplication,
 		},
-	})
+	}
+}
+
+// DefaultPipeline creates a pipeline with all stages
+func DefaultPipeline(logger *observability.Logger, metrics *observability.Metrics) *Pipeline {
+	return NewPipeline(logger, metrics, DefaultPipelineConfig())
 }
 
 // AddValidator adds a validator to the pipeline
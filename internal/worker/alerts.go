@@ -0,0 +1,272 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/events"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// AlertConfig configures the saved search alert job
+type AlertConfig struct {
+	// Lookback is how far back scraped cases are checked against saved
+	// searches on each run; it should exceed the run interval
+	Lookback time.Duration
+	// PageSize is the number of a search's matching cases read at a time
+	PageSize int
+}
+
+// DefaultAlertConfig returns the default alert configuration
+func DefaultAlertConfig() AlertConfig {
+	return AlertConfig{
+		Lookback: 24 * time.Hour,
+		PageSize: 500,
+	}
+}
+
+// AlertResult summarizes an alert run
+type AlertResult struct {
+	Searches int `json:"searches"`
+	Alerts   int `json:"alerts"`
+	Failed   int `json:"failed"`
+}
+
+// Notifier delivers a saved search's newly matching cases to its owner
+type Notifier interface {
+	Notify(ctx context.Context, search *models.SavedSearch, cases []*models.Case) error
+}
+
+// WebhookNotifier posts alerts as search.alert events to the saved search's
+// webhook URL
+type WebhookNotifier struct {
+	webhooks *events.WebhookManager
+}
+
+// NewWebhookNotifier creates a WebhookNotifier
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{webhooks: events.NewWebhookManager(nil)}
+}
+
+// Notify posts an alert, failing once the webhook's retries are used up
+func (n *WebhookNotifier) Notify(ctx context.Context, search *models.SavedSearch, cases []*models.Case) error {
+	webhook := &events.Webhook{
+		ID:         search.ID,
+		URL:        search.WebhookURL,
+		EventTypes: []events.EventType{events.EventSearchAlert},
+		MaxRetries: 3,
+		Timeout:    10 * time.Second,
+		Enabled:    true,
+		PublicOnly: true,
+	}
+
+	delivery := n.webhooks.Deliver(ctx, webhook, events.SearchAlertEvent(search, cases))
+	if !delivery.Success {
+		if delivery.Error != "" {
+			return fmt.Errorf("alert delivery to %s failed: %s", search.WebhookURL, delivery.Error)
+		}
+		return fmt.Errorf("alert delivery to %s failed with status %d", search.WebhookURL, delivery.StatusCode)
+	}
+	return nil
+}
+
+// AlertRunner re-runs saved searches against newly scraped cases and
+// notifies their owners of matches, once per case and search
+type AlertRunner struct {
+	storage  storage.Storage
+	searches storage.SavedSearchStore
+	notifier Notifier
+	config   AlertConfig
+	clock    clock.Clock
+	mu       sync.Mutex
+}
+
+// NewAlertRunner creates a new AlertRunner. It fails if the storage backend
+// keeps no saved searches.
+func NewAlertRunner(store storage.Storage, notifier Notifier, config AlertConfig) (*AlertRunner, error) {
+	searches, ok := storage.SavedSearches(store)
+	if !ok {
+		return nil, fmt.Errorf("storage backend does not support saved searches")
+	}
+	if config.Lookback <= 0 {
+		config.Lookback = DefaultAlertConfig().Lookback
+	}
+	if config.PageSize <= 0 {
+		config.PageSize = DefaultAlertConfig().PageSize
+	}
+
+	return &AlertRunner{
+		storage:  store,
+		searches: searches,
+		notifier: notifier,
+		config:   config,
+		clock:    clock.Real(),
+	}, nil
+}
+
+// SetClock sets the clock the lookback window is measured with
+func (r *AlertRunner) SetClock(c clock.Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = c
+}
+
+// Run checks cases scraped within the lookback window against every saved
+// search. Only cases scraped after a search was saved alert for it. Cases
+// whose alert could not be delivered are left to alert on a later run.
+func (r *AlertRunner) Run(ctx context.Context) (*AlertResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &AlertResult{}
+
+	searches, err := r.searches.ListSavedSearches(ctx, storage.SavedSearchFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list saved searches: %w", err)
+	}
+	if len(searches) == 0 {
+		return result, nil
+	}
+
+	cutoff := r.clock.Now().Add(-r.config.Lookback)
+	for _, search := range searches {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Searches++
+
+		found, err := r.matchingCases(ctx, search, cutoff)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if len(found) == 0 {
+			continue
+		}
+		matches := make(map[string]*models.Case, len(found))
+		ids := make([]string, 0, len(found))
+		for _, c := range found {
+			matches[c.ID] = c
+			ids = append(ids, c.ID)
+		}
+
+		// Claim the alerts first so concurrent runs never both send one
+		fresh, err := r.searches.RecordAlerts(ctx, search.ID, ids)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+
+		cases := make([]*models.Case, 0, len(fresh))
+		for _, id := range fresh {
+			cases = append(cases, matches[id])
+		}
+		if err := r.notifier.Notify(ctx, search, cases); err != nil {
+			r.searches.ForgetAlerts(ctx, search.ID, fresh)
+			result.Failed++
+			continue
+		}
+		result.Alerts += len(cases)
+	}
+
+	return result, nil
+}
+
+// Start runs the alert job every interval until the context is cancelled
+func (r *AlertRunner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Run(ctx)
+		}
+	}
+}
+
+// NewAlertJobHandler returns a JobHandler that runs the alert job for alert jobs
+func NewAlertJobHandler(r *AlertRunner) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if job.Type != queue.JobTypeAlert {
+			return fmt.Errorf("unexpected job type: %s", job.Type)
+		}
+
+		result, err := r.Run(ctx)
+		if err != nil {
+			return err
+		}
+
+		job.Result = map[string]interface{}{
+			"searches": result.Searches,
+			"alerts":   result.Alerts,
+			"failed":   result.Failed,
+		}
+		return nil
+	}
+}
+
+// matchingCases returns the cases a saved search alerts on: those scraped
+// within the lookback window and after the search was saved that its query
+// and filters find, visible to its tenant. The query is run as a case search
+// restricted to that scrape window, a page at a time.
+func (r *AlertRunner) matchingCases(ctx context.Context, search *models.SavedSearch, cutoff time.Time) ([]*models.Case, error) {
+	after := cutoff
+	if search.CreatedAt.After(after) {
+		after = search.CreatedAt
+	}
+	tenant := search.TenantID
+	filter := storage.CaseFilter{
+		Jurisdiction: search.Filters.Jurisdiction,
+		Court:        search.Filters.Court,
+		ScrapedAfter: &after,
+		TenantScope:  &tenant,
+	}
+
+	matches := make([]*models.Case, 0)
+	for offset := 0; ; offset += r.config.PageSize {
+		page, err := r.storage.SearchCases(ctx, storage.SearchQuery{
+			Query:   strings.TrimSpace(search.Query),
+			Filters: filter,
+			Limit:   r.config.PageSize,
+			Offset:  offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search cases for saved search %s: %w", search.ID, err)
+		}
+
+		// Backends don't filter by concept, but the window is narrow
+		for _, c := range page {
+			if len(search.Filters.Concepts) == 0 || hasAnyConcept(c, search.Filters.Concepts) {
+				matches = append(matches, c)
+			}
+		}
+
+		if len(page) < r.config.PageSize {
+			return matches, nil
+		}
+	}
+}
+
+// hasAnyConcept reports whether a case carries any of the concepts
+func hasAnyConcept(c *models.Case, concepts []string) bool {
+	for _, want := range concepts {
+		for _, have := range c.LegalConcepts {
+			if strings.EqualFold(have, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronAliases are the shorthand schedules accepted in place of five fields
+var cronAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// cronField bounds one field of a cron expression
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = []cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+// CronSchedule is a parsed cron expression: minute, hour, day of month,
+// month and day of week
+type CronSchedule struct {
+	spec   string
+	fields [5]uint64 // bit n set when value n matches
+
+	// Cron matches either day field when both are restricted; a field
+	// starting with * is unrestricted
+	anyDayOfMonth bool
+	anyDayOfWeek  bool
+}
+
+// ParseCronSchedule parses a five-field cron expression such as "0 2 * * 1"
+// (02:00 every Monday) or one of @hourly, @daily, @midnight, @weekly and
+// @monthly. Fields take *, values, ranges (1-5), lists (1,3) and steps (*/15).
+func ParseCronSchedule(spec string) (*CronSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	expr := spec
+	if alias, ok := cronAliases[strings.ToLower(spec)]; ok {
+		expr = alias
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron schedule %q: expected 5 fields, got %d", spec, len(parts))
+	}
+
+	s := &CronSchedule{
+		spec:          spec,
+		anyDayOfMonth: strings.HasPrefix(parts[2], "*"),
+		anyDayOfWeek:  strings.HasPrefix(parts[4], "*"),
+	}
+	for i, part := range parts {
+		bits, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron schedule %q: %w", spec, err)
+		}
+		s.fields[i] = bits
+	}
+
+	// Sunday may be written as 7
+	if s.fields[4]&(1<<7) != 0 {
+		s.fields[4] |= 1
+	}
+
+	return s, nil
+}
+
+// parseCronField parses one comma-separated field into a bit set
+func parseCronField(value string, field cronField) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(value, ",") {
+		rangePart, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("invalid step in %s field: %q", field.name, item)
+			}
+			rangePart, step = item[:i], n
+		}
+
+		lo, hi := field.min, field.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid %s field: %q", field.name, item)
+			}
+			hi = lo
+			if len(bounds) == 2 {
+				if hi, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid %s field: %q", field.name, item)
+				}
+			} else if step > 1 {
+				// "5/15" runs from 5 to the end of the range
+				hi = field.max
+			}
+		}
+		if lo < field.min || hi > field.max || lo > hi {
+			return 0, fmt.Errorf("%s field out of range %d-%d: %q", field.name, field.min, field.max, item)
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *CronSchedule) String() string {
+	return s.spec
+}
+
+// Next returns the first scheduled time strictly after t, in t's location.
+// It returns the zero time if the schedule never matches, as for "0 0 30 2 *".
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+
+	// Every schedule that can match does so within a leap-year cycle
+	limit := next.AddDate(5, 0, 0)
+	for next.Before(limit) {
+		if !s.matches(3, int(next.Month())) {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matchesDay(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matches(1, next.Hour()) {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if !s.matches(0, next.Minute()) {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+	return time.Time{}
+}
+
+// matches reports whether value is set in field i
+func (s *CronSchedule) matches(i, value int) bool {
+	return s.fields[i]&(1<<uint(value)) != 0
+}
+
+// matchesDay applies cron's day rule: when both day fields are restricted a
+// day matching either runs
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	dom := s.matches(2, t.Day())
+	dow := s.matches(4, int(t.Weekday()))
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// EnrichStep enriches a stored case, such as extracting its concepts or
+// citations. Steps store their own changes.
+type EnrichStep func(ctx context.Context, c *models.Case) error
+
+// NamedEnrichStep is an enrichment step with the steps that must run before it
+type NamedEnrichStep struct {
+	Name     string
+	Requires []string
+	Run      EnrichStep
+}
+
+// OrderEnrichSteps orders steps so each runs after its prerequisites: those
+// it declares and any added for it in requires. Steps named in order run in
+// that order as far as their prerequisites allow, followed by the rest in the
+// order given; a prerequisite moves up to run just ahead of the earliest step
+// needing it. Unknown or duplicate names and dependency cycles are errors.
+func OrderEnrichSteps(steps []NamedEnrichStep, order []string, requires map[string][]string) ([]NamedEnrichStep, error) {
+	rank := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("enrichment step %d has no name", i)
+		}
+		if _, ok := rank[step.Name]; ok {
+			return nil, fmt.Errorf("duplicate enrichment step: %s", step.Name)
+		}
+		rank[step.Name] = len(order) + i
+	}
+
+	listed := make(map[string]bool, len(order))
+	for i, name := range order {
+		if _, ok := rank[name]; !ok {
+			return nil, fmt.Errorf("unknown enrichment step in order: %s", name)
+		}
+		if listed[name] {
+			return nil, fmt.Errorf("enrichment step listed twice in order: %s", name)
+		}
+		listed[name] = true
+		rank[name] = i
+	}
+
+	for name := range requires {
+		if _, ok := rank[name]; !ok {
+			return nil, fmt.Errorf("unknown enrichment step: %s", name)
+		}
+	}
+	prereqs := make(map[string][]string, len(steps))
+	for _, step := range steps {
+		for _, req := range append(append([]string(nil), step.Requires...), requires[step.Name]...) {
+			if _, ok := rank[req]; !ok {
+				return nil, fmt.Errorf("enrichment step %s requires unknown step %s", step.Name, req)
+			}
+			prereqs[step.Name] = append(prereqs[step.Name], req)
+		}
+	}
+
+	// A prerequisite ranks with the earliest step that needs it
+	priority := make(map[string]int, len(rank))
+	for name, r := range rank {
+		priority[name] = r
+	}
+	for changed := true; changed; {
+		changed = false
+		for name, reqs := range prereqs {
+			for _, req := range reqs {
+				if priority[name] < priority[req] {
+					priority[req] = priority[name]
+					changed = true
+				}
+			}
+		}
+	}
+
+	// Repeatedly take the highest ranked step whose prerequisites have run
+	pending := append([]NamedEnrichStep(nil), steps...)
+	sort.SliceStable(pending, func(i, j int) bool {
+		a, b := pending[i].Name, pending[j].Name
+		if priority[a] != priority[b] {
+			return priority[a] < priority[b]
+		}
+		return rank[a] < rank[b]
+	})
+	done := make(map[string]bool, len(steps))
+	ordered := make([]NamedEnrichStep, 0, len(steps))
+	for len(pending) > 0 {
+		next := -1
+		for i, step := range pending {
+			ready := true
+			for _, req := range prereqs[step.Name] {
+				if !done[req] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+		if next < 0 {
+			names := make([]string, len(pending))
+			for i, step := range pending {
+				names[i] = step.Name
+			}
+			return nil, fmt.Errorf("enrichment steps have a dependency cycle: %s", strings.Join(names, ", "))
+		}
+		done[pending[next].Name] = true
+		ordered = append(ordered, pending[next])
+		pending = append(pending[:next], pending[next+1:]...)
+	}
+
+	return ordered, nil
+}
+
+// NewEnrichJobHandler returns a JobHandler for enrich jobs. It loads the case
+// named by the job's case_id payload and runs each step on it in order.
+func NewEnrichJobHandler(store storage.Storage, steps ...EnrichStep) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if job.Type != queue.JobTypeEnrich {
+			return fmt.Errorf("unexpected job type: %s", job.Type)
+		}
+
+		caseID, _ := job.Payload["case_id"].(string)
+		if caseID == "" {
+			return fmt.Errorf("enrich job %s has no case_id", job.ID)
+		}
+
+		c, err := store.GetCase(ctx, caseID)
+		if err != nil {
+			return fmt.Errorf("failed to load case %s: %w", caseID, err)
+		}
+
+		for _, step := range steps {
+			if err := step(ctx, c); err != nil {
+				return fmt.Errorf("failed to enrich case %s: %w", caseID, err)
+			}
+		}
+
+		job.Result = map[string]interface{}{
+			"case_id": caseID,
+			"steps":   len(steps),
+		}
+		return nil
+	}
+}
+
+// NewTypeJobHandler returns a JobHandler that dispatches jobs to the handler
+// for their type, and any other job to fallback. It lets one pool serve every
+// job type when no dedicated pool is configured for some of them.
+func NewTypeJobHandler(handlers map[queue.JobType]JobHandler, fallback JobHandler) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if handler, ok := handlers[job.Type]; ok {
+			return handler(ctx, job)
+		}
+		return fallback(ctx, job)
+	}
+}
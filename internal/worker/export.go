@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+)
+
+// NewExportJobHandler returns a JobHandler for export jobs. It writes every
+// case matching the job's query and filter (see export.Job) to the job's file
+// in jobs, reading as the tenant and from the jurisdictions the job was
+// created with, and keeps the job's record there up to date so the API can
+// report on it and serve the file. Formats a StreamExporter writes are
+// streamed from storage a page at a time; the others are read whole before
+// they are written.
+func NewExportJobHandler(store storage.Storage, jobs *export.JobStore) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if job.Type != queue.JobTypeExport {
+			return fmt.Errorf("unexpected job type: %s", job.Type)
+		}
+
+		request, err := export.ParseJob(job.Payload)
+		if err != nil {
+			return fmt.Errorf("export job %s: %w", job.ID, err)
+		}
+
+		record := export.NewJobRecord(job.ID, request)
+		record.Status = queue.JobStatusRunning
+		if err := jobs.Save(record); err != nil {
+			return fmt.Errorf("failed to record export job: %w", err)
+		}
+
+		ctx, scoped := request.Scope(ctx, store)
+		var count int
+		err = jobs.WriteFile(job.ID, request.Format, func(w io.Writer) error {
+			count, err = exportCases(ctx, scoped, request, w)
+			return err
+		})
+		if err != nil {
+			// The worker retries the job until its last attempt
+			record.Status = queue.JobStatusRetrying
+			if job.Attempts >= job.MaxAttempts {
+				record.Status = queue.JobStatusFailed
+			}
+			record.Error = err.Error()
+			if saveErr := jobs.Save(record); saveErr != nil {
+				return fmt.Errorf("%w (and failed to record export job: %v)", err, saveErr)
+			}
+			return err
+		}
+
+		record.Status = queue.JobStatusCompleted
+		record.Count = count
+		job.Result = map[string]interface{}{
+			"format": string(request.Format),
+			"count":  count,
+		}
+		return jobs.Save(record)
+	}
+}
+
+// exportCases writes the cases an export job matches to w and returns how
+// many it wrote
+func exportCases(ctx context.Context, store storage.Storage, job export.Job, w io.Writer) (int, error) {
+	if job.Format.Streams() {
+		return export.StreamFromStorage(ctx, store, job.Query, job.Filter, job.Format, w, job.Options)
+	}
+
+	cases, err := export.CasesMatching(ctx, store, job.Query, job.Filter, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	exporter, err := export.NewExporterWithOptions(job.Format, w, job.Options)
+	if err != nil {
+		return 0, err
+	}
+	if err := exporter.Export(cases); err != nil {
+		return 0, fmt.Errorf("failed to export cases: %w", err)
+	}
+	return len(cases), exporter.Close()
+}
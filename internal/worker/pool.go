@@ -6,9 +6,13 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/queue"
 )
 
+// requeueGrace bounds how long Stop waits for cancelled jobs to be requeued
+const requeueGrace = 5 * time.Second
+
 // Pool represents a pool of workers
 type Pool struct {
 	workers    []*Worker
@@ -18,9 +22,12 @@ type Pool struct {
 	wg         sync.WaitGroup
 	ctx        context.Context
 	cancel     context.CancelFunc
+	jobCtx     context.Context
+	jobCancel  context.CancelFunc
 	shutdownCh chan struct{}
 	logger     interface{}
-	metrics    interface{}
+	metrics    *observability.Metrics
+	lastDrain  time.Duration
 }
 
 // JobHandler is a function that handles a job
@@ -36,6 +43,7 @@ type PoolConfig struct {
 // NewPool creates a new worker pool
 func NewPool(cfg PoolConfig, q queue.Queue, handler JobHandler) *Pool {
 	ctx, cancel := context.WithCancel(context.Background())
+	jobCtx, jobCancel := context.WithCancel(context.Background())
 
 	return &Pool{
 		workers:    make([]*Worker, 0, cfg.WorkerCount),
@@ -43,10 +51,19 @@ func NewPool(cfg PoolConfig, q queue.Queue, handler JobHandler) *Pool {
 		handler:    handler,
 		ctx:        ctx,
 		cancel:     cancel,
+		jobCtx:     jobCtx,
+		jobCancel:  jobCancel,
 		shutdownCh: make(chan struct{}),
 	}
 }
 
+// SetMetrics enables recording of drain metrics on shutdown
+func (p *Pool) SetMetrics(metrics *observability.Metrics) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.metrics = metrics
+}
+
 // Start starts all workers in the pool
 func (p *Pool) Start(workerCount int) error {
 	p.mu.Lock()
@@ -59,16 +76,20 @@ func (p *Pool) Start(workerCount int) error {
 		p.wg.Add(1)
 		go func(w *Worker) {
 			defer p.wg.Done()
-			w.Run(p.ctx)
+			w.Run(p.ctx, p.jobCtx)
 		}(worker)
 	}
 
 	return nil
 }
 
-// Stop gracefully stops all workers
+// Stop gracefully stops all workers. Workers stop pulling new jobs at once and
+// in-flight jobs are given up to timeout to finish; any still running after
+// that are cancelled and requeued.
 func (p *Pool) Stop(timeout time.Duration) error {
-	// Cancel context to signal workers to stop
+	start := time.Now()
+
+	// Stop pulling new jobs, in-flight jobs keep running
 	p.cancel()
 
 	// Wait for workers to finish with timeout
@@ -81,10 +102,48 @@ func (p *Pool) Stop(timeout time.Duration) error {
 	select {
 	case <-done:
 		// All workers stopped gracefully
+		p.jobCancel()
+		p.recordDrain("drained", time.Since(start), 0)
 		return nil
 	case <-time.After(timeout):
-		// Timeout reached
-		return fmt.Errorf("worker pool shutdown timeout after %v", timeout)
+	}
+
+	// Timeout reached, interrupt the remaining jobs so workers requeue them
+	requeuedBefore := p.requeuedJobs()
+	p.jobCancel()
+
+	select {
+	case <-done:
+	case <-time.After(requeueGrace):
+	}
+
+	requeued := int(p.requeuedJobs() - requeuedBefore)
+	p.recordDrain("timeout", time.Since(start), requeued)
+
+	return fmt.Errorf("worker pool shutdown timeout after %v, %d in-flight jobs requeued", timeout, requeued)
+}
+
+// requeuedJobs returns the number of jobs requeued by interruption across workers
+func (p *Pool) requeuedJobs() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var total int64
+	for _, worker := range p.workers {
+		total += worker.GetStats().JobsRequeued
+	}
+	return total
+}
+
+// recordDrain stores the drain duration and reports it to metrics
+func (p *Pool) recordDrain(outcome string, duration time.Duration, requeued int) {
+	p.mu.Lock()
+	p.lastDrain = duration
+	metrics := p.metrics
+	p.mu.Unlock()
+
+	if metrics != nil {
+		metrics.RecordWorkerDrain(outcome, duration, requeued)
 	}
 }
 
@@ -101,13 +160,16 @@ func (p *Pool) GetStats() PoolStats {
 	defer p.mu.RUnlock()
 
 	stats := PoolStats{
-		WorkerCount: len(p.workers),
+		WorkerCount:   len(p.workers),
+		DrainDuration: p.lastDrain,
 	}
 
 	for _, worker := range p.workers {
 		workerStats := worker.GetStats()
 		stats.TotalJobsProcessed += workerStats.JobsProcessed
 		stats.TotalJobsFailed += workerStats.JobsFailed
+		stats.TotalJobsRequeued += workerStats.JobsRequeued
+		stats.TotalJobsDeferred += workerStats.JobsDeferred
 		if workerStats.IsBusy {
 			stats.BusyWorkers++
 		}
@@ -129,6 +191,9 @@ type PoolStats struct {
 	BusyWorkers        int           `json:"busy_workers"`
 	TotalJobsProcessed int64         `json:"total_jobs_processed"`
 	TotalJobsFailed    int64         `json:"total_jobs_failed"`
+	TotalJobsRequeued  int64         `json:"total_jobs_requeued"`
+	TotalJobsDeferred  int64         `json:"total_jobs_deferred"`
 	Utilization        float64       `json:"utilization"`
 	AverageJobDuration time.Duration `json:"average_job_duration"`
+	DrainDuration      time.Duration `json:"drain_duration"` // time the last Stop took to drain
 }
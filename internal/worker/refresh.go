@@ -0,0 +1,369 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/jurisdiction"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// RefreshConfig configures the stale-case refresh job
+type RefreshConfig struct {
+	// MaxAge is how old a case's last update must be before it is re-fetched
+	MaxAge time.Duration
+	// BatchSize is the maximum number of cases refreshed per run
+	BatchSize int
+	// BudgetFraction is the share of each source's rate limit refreshes may use
+	BudgetFraction float64
+}
+
+// DefaultRefreshConfig returns the default refresh configuration
+func DefaultRefreshConfig() RefreshConfig {
+	return RefreshConfig{
+		MaxAge:         30 * 24 * time.Hour,
+		BatchSize:      100,
+		BudgetFraction: 0.25,
+	}
+}
+
+// RefreshResult summarizes a refresh run
+type RefreshResult struct {
+	Checked   int `json:"checked"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Failed    int `json:"failed"`
+}
+
+// CaseRefresher re-fetches stale cases from precedential courts and stores new versions
+type CaseRefresher struct {
+	storage   storage.Storage
+	scrapers  *scraper.ScraperRegistry
+	hierarchy *jurisdiction.CourtHierarchy
+	config    RefreshConfig
+	limiters  map[string]*scraper.RateLimiter
+	checked   map[string]time.Time
+	clock     clock.Clock
+	metrics   *observability.Metrics
+	mu        sync.Mutex
+}
+
+// NewCaseRefresher creates a new CaseRefresher
+func NewCaseRefresher(store storage.Storage, scrapers *scraper.ScraperRegistry, config RefreshConfig) *CaseRefresher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRefreshConfig().BatchSize
+	}
+	if config.BudgetFraction <= 0 || config.BudgetFraction > 1 {
+		config.BudgetFraction = DefaultRefreshConfig().BudgetFraction
+	}
+
+	return &CaseRefresher{
+		storage:   store,
+		scrapers:  scrapers,
+		hierarchy: jurisdiction.NewCourtHierarchy(),
+		config:    config,
+		limiters:  make(map[string]*scraper.RateLimiter),
+		checked:   make(map[string]time.Time),
+		clock:     clock.Real(),
+	}
+}
+
+// SetClock sets the clock used to find stale cases and stamp refreshed ones
+func (r *CaseRefresher) SetClock(c clock.Clock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clock = c
+}
+
+// SetMetrics sets the metrics that unchanged re-fetched cases are counted in
+func (r *CaseRefresher) SetMetrics(m *observability.Metrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = m
+}
+
+// Run refreshes one batch of stale cases
+func (r *CaseRefresher) Run(ctx context.Context) (*RefreshResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &RefreshResult{}
+
+	candidates, err := r.findStaleCases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stored := range candidates {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		s, ok := r.scraperFor(stored)
+		if !ok {
+			continue
+		}
+
+		// Spread refreshes across the source's rate-limit budget
+		if err := r.limiterFor(s).Wait(ctx); err != nil {
+			return result, err
+		}
+
+		result.Checked++
+
+		fresh, err := s.GetCaseByID(ctx, sourceCaseID(stored))
+		if err != nil || fresh == nil {
+			result.Failed++
+			continue
+		}
+
+		fresh.ContentHash = CaseContentHash(fresh)
+		if fresh.ContentHash == storedContentHash(stored) {
+			r.checked[stored.ID] = r.clock.Now()
+			result.Unchanged++
+			if r.metrics != nil {
+				r.metrics.RecordUnchangedCases(s.GetJurisdiction(), s.GetName(), 1)
+			}
+			continue
+		}
+
+		// Keep the version being replaced
+		if err := r.keepVersion(ctx, stored.ID); err != nil {
+			result.Failed++
+			continue
+		}
+
+		// Store the new version, keeping identity, first-seen time and status;
+		// scrapers always report cases as active, which would undo a recorded
+		// overruling
+		fresh.ID = stored.ID
+		fresh.ScrapedAt = stored.ScrapedAt
+		fresh.Status = stored.Status
+		fresh.Version = stored.Version + 1
+		fresh.LastUpdated = r.clock.Now()
+
+		if err := r.storage.UpdateCase(ctx, fresh); err != nil {
+			result.Failed++
+			continue
+		}
+		result.Updated++
+	}
+
+	return result, nil
+}
+
+// Start runs the refresher every interval until the context is cancelled
+func (r *CaseRefresher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.Run(ctx)
+		}
+	}
+}
+
+// NewRefreshJobHandler returns a JobHandler that runs a refresh batch for refresh jobs
+func NewRefreshJobHandler(r *CaseRefresher) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if job.Type != queue.JobTypeRefresh {
+			return fmt.Errorf("unexpected job type: %s", job.Type)
+		}
+
+		result, err := r.Run(ctx)
+		if err != nil {
+			return err
+		}
+
+		job.Result = map[string]interface{}{
+			"checked":   result.Checked,
+			"updated":   result.Updated,
+			"unchanged": result.Unchanged,
+			"failed":    result.Failed,
+		}
+		return nil
+	}
+}
+
+// findStaleCases returns up to BatchSize precedential cases not updated within MaxAge
+func (r *CaseRefresher) findStaleCases(ctx context.Context) ([]*models.Case, error) {
+	cutoff := r.clock.Now().Add(-r.config.MaxAge)
+	candidates := make([]*models.Case, 0, r.config.BatchSize)
+	seen := make(map[string]bool)
+
+	pageSize := r.config.BatchSize * 4
+	for offset := 0; len(candidates) < r.config.BatchSize; offset += pageSize {
+		page, err := r.storage.ListCases(ctx, storage.CaseFilter{UpdatedBefore: &cutoff, Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cases: %w", err)
+		}
+
+		for _, c := range page {
+			if seen[c.ID] {
+				continue
+			}
+			seen[c.ID] = true
+
+			// Skip unchanged cases already checked within MaxAge
+			if last, ok := r.checked[c.ID]; ok && last.After(cutoff) {
+				continue
+			}
+
+			if !r.hierarchy.IsPrecedential(c.Court) {
+				continue
+			}
+
+			// Cases stored with a content hash are compared by it. For
+			// others the stored full text, which listed cases don't carry,
+			// is compared with the source's.
+			if c.ContentHash == "" {
+				if c.ContentHash, err = storage.StoredContentHash(ctx, r.storage, c.ID); err != nil {
+					return nil, fmt.Errorf("failed to read content hash of case %s: %w", c.ID, err)
+				}
+			}
+			if c.ContentHash == "" {
+				if c.FullText, err = storage.CaseFullText(ctx, r.storage, c.ID); err != nil {
+					return nil, fmt.Errorf("failed to load full text of case %s: %w", c.ID, err)
+				}
+			}
+
+			candidates = append(candidates, c)
+			if len(candidates) >= r.config.BatchSize {
+				break
+			}
+		}
+
+		if len(page) < pageSize {
+			break
+		}
+	}
+
+	return candidates, nil
+}
+
+// keepVersion saves the stored case, with its full text, as an earlier
+// version before it is replaced. Backends without a version store keep none.
+func (r *CaseRefresher) keepVersion(ctx context.Context, id string) error {
+	versions, ok := storage.CaseVersions(r.storage)
+	if !ok {
+		return nil
+	}
+	previous, err := r.storage.GetCase(ctx, id)
+	if err != nil {
+		return err
+	}
+	return versions.SaveCaseVersion(ctx, previous)
+}
+
+// sourceCaseID returns the ID a stored case has at its source. Cases saved
+// before source IDs were kept, or under the source strategy, use their own ID.
+func sourceCaseID(c *models.Case) string {
+	if id := scraper.SourceCaseID(c); id != "" {
+		return id
+	}
+	return c.ID
+}
+
+// scraperFor finds the registered scraper for a case's source database
+func (r *CaseRefresher) scraperFor(c *models.Case) (scraper.Scraper, bool) {
+	if s, ok := r.scrapers.Get(strings.ToLower(c.SourceDatabase)); ok {
+		return s, true
+	}
+	for _, s := range r.scrapers.GetAll() {
+		if strings.EqualFold(s.GetName(), c.SourceDatabase) {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// limiterFor returns the refresh rate limiter for a scraper
+func (r *CaseRefresher) limiterFor(s scraper.Scraper) *scraper.RateLimiter {
+	name := s.GetName()
+	if limiter, ok := r.limiters[name]; ok {
+		return limiter
+	}
+
+	perMin := int(float64(s.GetRateLimit()) * r.config.BudgetFraction)
+	if perMin < 1 {
+		perMin = 1
+	}
+
+	limiter := scraper.NewRateLimiter(perMin)
+	r.limiters[name] = limiter
+	return limiter
+}
+
+// caseContent holds the fields compared when deciding if a case changed at the source
+type caseContent struct {
+	CaseName     string
+	CaseNumber   string
+	Court        string
+	DecisionDate string
+	Judges       []string
+	Summary      string
+	Headnotes    string
+	FullText     string
+	Citations    []string
+}
+
+func contentOf(c *models.Case) caseContent {
+	content := caseContent{
+		CaseName:   strings.TrimSpace(c.CaseName),
+		CaseNumber: strings.TrimSpace(c.CaseNumber),
+		Court:      strings.TrimSpace(c.Court),
+		Judges:     c.Judges,
+		Summary:    strings.TrimSpace(c.Summary),
+		Headnotes:  strings.TrimSpace(c.Headnotes),
+		FullText:   strings.TrimSpace(c.FullText),
+	}
+	if c.DecisionDate != nil {
+		content.DecisionDate = c.DecisionDate.Format("2006-01-02")
+	}
+	for _, cit := range c.Citations {
+		content.Citations = append(content.Citations, cit.RawCitation)
+	}
+	if len(content.Judges) == 0 {
+		content.Judges = nil
+	}
+	return content
+}
+
+// CaseContentChanged reports whether the fetched case differs from the stored one
+func CaseContentChanged(stored, fetched *models.Case) bool {
+	return !reflect.DeepEqual(contentOf(stored), contentOf(fetched))
+}
+
+// CaseContentHash returns a hash of the fields compared when deciding if a
+// case changed at the source. It is stored with scraped cases so that a
+// re-scrape can be checked against the stored case without loading it.
+func CaseContentHash(c *models.Case) string {
+	encoded, _ := json.Marshal(contentOf(c))
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// storedContentHash returns the content hash stored with a case, or the hash
+// of its stored content for cases saved without one
+func storedContentHash(c *models.Case) string {
+	if c.ContentHash != "" {
+		return c.ContentHash
+	}
+	return CaseContentHash(c)
+}
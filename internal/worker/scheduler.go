@@ -0,0 +1,198 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+)
+
+// ScheduledScrape is the schedule of one source and the state of its runs
+type ScheduledScrape struct {
+	Source       string     `json:"source"`
+	Jurisdiction string     `json:"jurisdiction"`
+	Schedule     string     `json:"schedule"`
+	NextRun      time.Time  `json:"next_run"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastJobID    string     `json:"last_job_id,omitempty"`
+	LastSkipped  *time.Time `json:"last_skipped,omitempty"`
+	LastDeferred *time.Time `json:"last_deferred,omitempty"`
+
+	// Watermark is when the last enqueued scrape ran; the next scrape only
+	// asks for cases decided since then
+	Watermark *time.Time `json:"watermark,omitempty"`
+
+	cron *CronSchedule
+}
+
+// ScrapeScheduler enqueues incremental scrape jobs for each source on its
+// cron schedule, without an external cron
+type ScrapeScheduler struct {
+	queue     queue.Queue
+	scrapers  *scraper.ScraperRegistry
+	schedules map[string]*ScheduledScrape
+	breaker   scraper.SourceBreaker
+	clock     clock.Clock
+	mu        sync.Mutex
+}
+
+// NewScrapeScheduler creates a scheduler from cron schedules keyed by source
+// name. Every source must be registered, and every schedule valid.
+func NewScrapeScheduler(q queue.Queue, scrapers *scraper.ScraperRegistry, schedules map[string]string) (*ScrapeScheduler, error) {
+	s := &ScrapeScheduler{
+		queue:     q,
+		scrapers:  scrapers,
+		schedules: make(map[string]*ScheduledScrape, len(schedules)),
+		clock:     clock.Real(),
+	}
+
+	for source, spec := range schedules {
+		src, ok := scrapers.Get(source)
+		if !ok {
+			return nil, fmt.Errorf("unknown or disabled source in schedule: %s", source)
+		}
+		cron, err := ParseCronSchedule(spec)
+		if err != nil {
+			return nil, fmt.Errorf("schedule for %s: %w", source, err)
+		}
+
+		s.schedules[source] = &ScheduledScrape{
+			Source:       source,
+			Jurisdiction: src.GetJurisdiction(),
+			Schedule:     cron.String(),
+			cron:         cron,
+		}
+	}
+	s.reschedule()
+
+	return s, nil
+}
+
+// SetClock sets the clock the schedules are run against
+func (s *ScrapeScheduler) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+	s.reschedule()
+}
+
+// SetBreaker sets the circuit breaker consulted before each scheduled scrape;
+// sources whose breaker is open are skipped
+func (s *ScrapeScheduler) SetBreaker(breaker scraper.SourceBreaker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.breaker = breaker
+}
+
+// reschedule sets each source's next run from the current time
+func (s *ScrapeScheduler) reschedule() {
+	now := s.clock.Now()
+	for _, sched := range s.schedules {
+		sched.NextRun = sched.cron.Next(now)
+	}
+}
+
+// Schedules returns each source's schedule and next run, ordered by source
+func (s *ScrapeScheduler) Schedules() []ScheduledScrape {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]ScheduledScrape, 0, len(s.schedules))
+	for _, sched := range s.schedules {
+		out = append(out, *sched)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Source < out[j].Source })
+	return out
+}
+
+// Tick enqueues a scrape job for every source whose next run has come, and
+// returns the jobs enqueued. A run missed while the worker was down is made
+// up once, not once per missed slot. Sources whose breaker is open are
+// skipped until their following run, keeping the watermark so that the next
+// scrape covers the gap. A run due outside the source's scraping window is
+// deferred until the window opens.
+func (s *ScrapeScheduler) Tick(ctx context.Context) ([]*queue.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	var due []*ScheduledScrape
+	for _, sched := range s.schedules {
+		if !sched.NextRun.IsZero() && !now.Before(sched.NextRun) {
+			due = append(due, sched)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].Source < due[j].Source })
+
+	var jobs []*queue.Job
+	for _, sched := range due {
+		if window := s.scrapeWindow(sched.Source); !window.Open(now) {
+			deferred := now
+			sched.LastDeferred = &deferred
+			sched.NextRun = window.NextOpen(now)
+			continue
+		}
+		sched.NextRun = sched.cron.Next(now)
+
+		if s.breaker != nil && s.breaker.IsOpen(sched.Source) {
+			skipped := now
+			sched.LastSkipped = &skipped
+			continue
+		}
+
+		job := queue.NewJob(queue.JobTypeScrape, sched.payload())
+		if err := s.queue.Enqueue(ctx, job); err != nil {
+			return jobs, fmt.Errorf("failed to enqueue scheduled scrape of %s: %w", sched.Source, err)
+		}
+
+		ran := now
+		sched.LastRun = &ran
+		sched.LastJobID = job.ID
+		sched.Watermark = &ran
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// scrapeWindow returns the scraping window of a source, nil if it has none
+func (s *ScrapeScheduler) scrapeWindow(source string) *scraper.ScrapeWindow {
+	src, ok := s.scrapers.Get(source)
+	if !ok {
+		return nil
+	}
+	return scraper.ScrapeWindowOf(src)
+}
+
+// payload builds the scrape job for a scheduled run, from the watermark on
+func (sched *ScheduledScrape) payload() map[string]interface{} {
+	payload := map[string]interface{}{
+		"jurisdiction": sched.Jurisdiction,
+		"source":       sched.Source,
+		"scheduled":    true,
+	}
+	if sched.Watermark != nil {
+		payload["start_date"] = sched.Watermark.Format(time.RFC3339)
+	}
+	return payload
+}
+
+// Start checks the schedules every interval until the context is cancelled
+func (s *ScrapeScheduler) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gongahkia/kite/internal/jurisdiction"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+)
+
+// CourtLevelFilter drops scraped cases from courts outside an allowlist of
+// levels, such as keeping only supreme and appellate decisions
+type CourtLevelFilter struct {
+	hierarchy *jurisdiction.CourtHierarchy
+	levels    map[models.CourtLevel]bool
+}
+
+// NewCourtLevelFilter creates a filter keeping cases from courts at the given
+// levels. With no levels every case is kept.
+func NewCourtLevelFilter(levels []models.CourtLevel) *CourtLevelFilter {
+	f := &CourtLevelFilter{
+		hierarchy: jurisdiction.NewCourtHierarchy(),
+		levels:    make(map[models.CourtLevel]bool),
+	}
+	for _, level := range levels {
+		f.levels[level] = true
+	}
+	return f
+}
+
+// Allows reports whether a case's court is at an allowed level. The level is
+// resolved from the court name, falling back to the case's own CourtLevel for
+// courts the hierarchy only guesses at; cases whose level cannot be
+// determined are kept.
+func (f *CourtLevelFilter) Allows(c *models.Case) bool {
+	if f == nil || len(f.levels) == 0 {
+		return true
+	}
+
+	level := c.CourtLevel
+	if c.Court != "" {
+		if inferred, confidence := f.hierarchy.InferCourtLevel(c.Court); confidence != jurisdiction.LevelConfidenceDefault {
+			level = inferred
+		}
+	}
+	if level == 0 {
+		return true
+	}
+	return f.levels[level]
+}
+
+// Filter returns the cases the filter allows
+func (f *CourtLevelFilter) Filter(cases []*models.Case) []*models.Case {
+	if f == nil || len(f.levels) == 0 {
+		return cases
+	}
+
+	kept := make([]*models.Case, 0, len(cases))
+	for _, c := range cases {
+		if f.Allows(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// forQuery returns the filter for a search: the query's own court levels
+// when it has any, and otherwise f
+func (f *CourtLevelFilter) forQuery(query scraper.SearchQuery) *CourtLevelFilter {
+	if len(query.CourtLevels) == 0 {
+		return f
+	}
+	filter := NewCourtLevelFilter(query.CourtLevels)
+	if f != nil {
+		filter.hierarchy = f.hierarchy
+	}
+	return filter
+}
+
+// NewScrapeJobHandler returns a JobHandler for scrape jobs. It searches every
+// scraper for the job's jurisdiction, or only the job's source when it names
+// one, drops cases whose URL the source blocklists or from courts the filter
+// (or the job's own court_levels) excludes, and saves the rest, skipping
+// those whose content hash matches the stored case's. Each kept case is also
+// queued for the registry's result archiver, if one is set. With fetch_full_text set, each remaining case is replaced by its full details,
+// fetched concurrently within the source's limit; a case whose details cannot
+// be fetched is saved as found and reported in the result's detail_errors.
+// Failed requests are retried under the registry's retry configuration, and
+// the job fails once the run has spent its retry budget.
+// Sources outside their scraping window are left out and listed in the
+// result's outside_window; when every source is, the job is deferred until
+// the first window opens.
+func NewScrapeJobHandler(store storage.Storage, scrapers *scraper.ScraperRegistry, filter *CourtLevelFilter) JobHandler {
+	return NewScrapeJobHandlerWithOptions(store, scrapers, filter, ScrapeOptions{})
+}
+
+// ScrapeOptions tunes how scrape jobs treat the cases a search turns up
+type ScrapeOptions struct {
+	// OnlyNew skips cases whose ID is already stored, so their details are
+	// not fetched again. A job with force_refresh set scrapes them anyway.
+	OnlyNew bool
+}
+
+// NewScrapeJobHandlerWithOptions returns a scrape JobHandler like
+// NewScrapeJobHandler's, tuned by opts. With OnlyNew set, search results
+// already in storage are skipped before any detail fetch and counted in the
+// result's existing.
+func NewScrapeJobHandlerWithOptions(store storage.Storage, scrapers *scraper.ScraperRegistry, filter *CourtLevelFilter, opts ScrapeOptions) JobHandler {
+	return func(ctx context.Context, job *queue.Job) error {
+		if job.Type != queue.JobTypeScrape {
+			return fmt.Errorf("unexpected job type: %s", job.Type)
+		}
+
+		query := scrapeQuery(job.Payload)
+		if query.Jurisdiction == "" {
+			return fmt.Errorf("scrape job %s has no jurisdiction", job.ID)
+		}
+
+		sources := scrapers.GetByJurisdiction(query.Jurisdiction)
+		if name, _ := job.Payload["source"].(string); name != "" {
+			s, ok := scrapers.Get(name)
+			if !ok {
+				return fmt.Errorf("unknown or disabled source: %s", name)
+			}
+			sources = []scraper.Scraper{s}
+		}
+		if len(sources) == 0 {
+			return fmt.Errorf("no scraper for jurisdiction: %s", query.Jurisdiction)
+		}
+
+		open := make([]scraper.Scraper, 0, len(sources))
+		var outside []string
+		var opens time.Time
+		for _, s := range sources {
+			if until, ok := scraper.OutsideScrapeWindow(s); ok {
+				outside = append(outside, s.GetName())
+				if opens.IsZero() || until.Before(opens) {
+					opens = until
+				}
+				continue
+			}
+			open = append(open, s)
+		}
+		if len(open) == 0 {
+			return &DeferredError{
+				Until:  opens,
+				Reason: "outside the scraping window of " + strings.Join(outside, ", "),
+			}
+		}
+		sources = open
+
+		courts := filter.forQuery(query)
+		fullText, _ := job.Payload["fetch_full_text"].(bool)
+		forceRefresh, _ := job.Payload["force_refresh"].(bool)
+		onlyNew := opts.OnlyNew && !forceRefresh
+		found, saved, unchanged, blocked, existing := 0, 0, 0, 0, 0
+		var detailErrors []scraper.DetailError
+		retrier := scrapers.NewRetrier()
+		for _, s := range sources {
+			var cases []*models.Case
+			err := retrier.Do(ctx, func() error {
+				var err error
+				cases, err = s.SearchCases(ctx, query)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("%s search failed: %w", s.GetName(), err)
+			}
+			found += len(cases)
+
+			allowed := make([]*models.Case, 0, len(cases))
+			for _, c := range cases {
+				if scraper.IsURLBlocked(s, c.URL) {
+					blocked++
+					continue
+				}
+				allowed = append(allowed, c)
+			}
+
+			kept := courts.Filter(allowed)
+			if onlyNew {
+				fresh, err := dropStored(ctx, store, kept)
+				if err != nil {
+					return err
+				}
+				existing += len(kept) - len(fresh)
+				kept = fresh
+			}
+			if fullText {
+				details := scraper.FetchDetailsWithRetry(ctx, s, kept, retrier)
+				if details.Err != nil {
+					return fmt.Errorf("%s detail fetch abandoned: %w", s.GetName(), details.Err)
+				}
+				kept = details.Cases
+				detailErrors = append(detailErrors, details.Errors...)
+			}
+
+			for _, c := range kept {
+				scrapers.Archiver().Archive(s.GetName(), c)
+				written, err := saveChanged(ctx, store, c)
+				if err != nil {
+					return err
+				}
+				if written {
+					saved++
+				} else {
+					unchanged++
+				}
+			}
+		}
+
+		job.Result = map[string]interface{}{
+			"found":     found,
+			"saved":     saved,
+			"unchanged": unchanged,
+			"dropped":   found - saved - unchanged - existing,
+			"blocked":   blocked,
+			"retries":   retrier.Used(),
+		}
+		if opts.OnlyNew {
+			job.Result["existing"] = existing
+		}
+		if fullText {
+			job.Result["detail_errors"] = detailErrors
+		}
+		if len(outside) > 0 {
+			job.Result["outside_window"] = outside
+		}
+		return nil
+	}
+}
+
+// dropStored returns the cases whose IDs are not yet in storage
+func dropStored(ctx context.Context, store storage.Storage, cases []*models.Case) ([]*models.Case, error) {
+	if len(cases) == 0 {
+		return cases, nil
+	}
+
+	ids := make([]string, len(cases))
+	for i, c := range cases {
+		ids[i] = c.ID
+	}
+	stored, err := storage.StoredCaseIDs(ctx, store, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check stored cases: %w", err)
+	}
+
+	fresh := make([]*models.Case, 0, len(cases))
+	for _, c := range cases {
+		if !stored[c.ID] {
+			fresh = append(fresh, c)
+		}
+	}
+	return fresh, nil
+}
+
+// saveChanged stamps a scraped case with its content hash and saves it unless
+// the stored case has the same hash, reporting whether it was saved. Skipping
+// unchanged cases keeps a re-scrape from bumping their update time and
+// invalidating cached responses for nothing.
+func saveChanged(ctx context.Context, store storage.Storage, c *models.Case) (bool, error) {
+	c.ContentHash = CaseContentHash(c)
+	stored, err := storage.StoredContentHash(ctx, store, c.ID)
+	if err != nil {
+		return false, fmt.Errorf("failed to read content hash of case %s: %w", c.ID, err)
+	}
+	if stored == c.ContentHash {
+		return false, nil
+	}
+	if err := store.SaveCase(ctx, c); err != nil {
+		return false, fmt.Errorf("failed to save case %s: %w", c.ID, err)
+	}
+	return true, nil
+}
+
+// scrapeQuery builds a search query from a scrape job's payload. Payloads
+// that have been through a JSON queue carry numbers as float64 and dates as
+// strings.
+func scrapeQuery(payload map[string]interface{}) scraper.SearchQuery {
+	query := scraper.SearchQuery{}
+	query.Query, _ = payload["query"].(string)
+	query.Jurisdiction, _ = payload["jurisdiction"].(string)
+	query.Court, _ = payload["court"].(string)
+	query.Limit = payloadInt(payload["max_cases"])
+	query.StartDate = payloadDate(payload["start_date"])
+	query.EndDate = payloadDate(payload["end_date"])
+
+	if levels, ok := payload["court_levels"].([]interface{}); ok {
+		for _, v := range levels {
+			if level := payloadInt(v); level > 0 {
+				query.CourtLevels = append(query.CourtLevels, models.CourtLevel(level))
+			}
+		}
+	} else if levels, ok := payload["court_levels"].([]models.CourtLevel); ok {
+		query.CourtLevels = levels
+	}
+
+	return query
+}
+
+func payloadInt(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case models.CourtLevel:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
+func payloadDate(v interface{}) *time.Time {
+	switch d := v.(type) {
+	case time.Time:
+		return &d
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, d); err == nil {
+				return &t
+			}
+		}
+	}
+	return nil
+}
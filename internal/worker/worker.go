@@ -2,6 +2,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
@@ -18,11 +19,27 @@ type Worker struct {
 	isBusy         atomic.Bool
 	jobsProcessed  atomic.Int64
 	jobsFailed     atomic.Int64
+	jobsRequeued   atomic.Int64
+	jobsDeferred   atomic.Int64
 	totalDuration  atomic.Int64
 	currentJob     *queue.Job
 	mu             sync.RWMutex
 }
 
+// DeferredError is returned by a job handler for a job that must not run
+// before Until, such as a scrape outside its source's scraping window. The
+// worker returns the job to the queue to run then, without counting the
+// attempt or treating it as a failure.
+type DeferredError struct {
+	Until  time.Time
+	Reason string
+}
+
+// Error returns the reason the job was deferred and until when
+func (e *DeferredError) Error() string {
+	return fmt.Sprintf("deferred until %s: %s", e.Until.Format(time.RFC3339), e.Reason)
+}
+
 // NewWorker creates a new worker
 func NewWorker(id int, q queue.Queue, handler JobHandler) *Worker {
 	return &Worker{
@@ -32,8 +49,10 @@ func NewWorker(id int, q queue.Queue, handler JobHandler) *Worker {
 	}
 }
 
-// Run starts the worker loop
-func (w *Worker) Run(ctx context.Context) {
+// Run starts the worker loop. The worker stops pulling jobs when ctx is
+// cancelled; jobCtx is handed to in-flight jobs and cancelling it interrupts
+// them, returning them to the queue.
+func (w *Worker) Run(ctx, jobCtx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -52,7 +71,7 @@ func (w *Worker) Run(ctx context.Context) {
 			}
 
 			// Process the job
-			w.processJob(ctx, job)
+			w.processJob(jobCtx, job)
 		}
 	}
 }
@@ -81,13 +100,23 @@ func (w *Worker) processJob(ctx context.Context, job *queue.Job) {
 	// Execute the job handler
 	err := w.handler(jobCtx, job)
 
-	if err != nil {
+	// Acknowledge even if shutdown has cancelled ctx
+	ackCtx := context.WithoutCancel(ctx)
+
+	var deferred *DeferredError
+	if err != nil && ctx.Err() != nil {
+		// Interrupted by shutdown, not a failure of the job itself
+		w.requeueInterrupted(ackCtx, job)
+	} else if errors.As(err, &deferred) {
+		// Not to run yet, also not a failure
+		w.deferJob(ackCtx, job, deferred.Until)
+	} else if err != nil {
 		// Job failed
 		w.jobsFailed.Add(1)
 		job.MarkFailed(err)
 
 		// Nack the job (requeue if retries available)
-		if nackErr := w.queue.Nack(ctx, job.ID, job.ShouldRetry()); nackErr != nil {
+		if nackErr := w.queue.Nack(ackCtx, job.ID, job.ShouldRetry()); nackErr != nil {
 			// Log error
 			fmt.Printf("Worker %d: Failed to nack job %s: %v\n", w.id, job.ID, nackErr)
 		}
@@ -97,13 +126,49 @@ func (w *Worker) processJob(ctx context.Context, job *queue.Job) {
 		job.MarkCompleted(nil)
 
 		// Ack the job
-		if ackErr := w.queue.Ack(ctx, job.ID); ackErr != nil {
+		if ackErr := w.queue.Ack(ackCtx, job.ID); ackErr != nil {
 			// Log error
 			fmt.Printf("Worker %d: Failed to ack job %s: %v\n", w.id, job.ID, ackErr)
 		}
 	}
 }
 
+// requeueInterrupted returns a job cancelled by shutdown to the queue without
+// counting the interrupted run as an attempt
+func (w *Worker) requeueInterrupted(ctx context.Context, job *queue.Job) {
+	job.Attempts--
+	job.Status = queue.JobStatusRetrying
+
+	if err := w.queue.Nack(ctx, job.ID, true); err != nil {
+		fmt.Printf("Worker %d: Failed to requeue interrupted job %s: %v\n", w.id, job.ID, err)
+		return
+	}
+	w.jobsRequeued.Add(1)
+}
+
+// deferJob returns a job its handler deferred to the queue to run at until,
+// without counting the deferred run as an attempt. Queues that can't hold
+// jobs back have it requeued once until has passed.
+func (w *Worker) deferJob(ctx context.Context, job *queue.Job, until time.Time) {
+	job.Attempts--
+	job.Status = queue.JobStatusRetrying
+	job.Schedule(until)
+
+	if d, ok := w.queue.(queue.Deferrer); ok {
+		if err := d.Defer(ctx, job.ID, until); err != nil {
+			fmt.Printf("Worker %d: Failed to defer job %s: %v\n", w.id, job.ID, err)
+			return
+		}
+	} else {
+		time.AfterFunc(time.Until(until), func() {
+			if err := w.queue.Nack(context.Background(), job.ID, true); err != nil {
+				fmt.Printf("Worker %d: Failed to requeue deferred job %s: %v\n", w.id, job.ID, err)
+			}
+		})
+	}
+	w.jobsDeferred.Add(1)
+}
+
 // GetID returns the worker ID
 func (w *Worker) GetID() int {
 	return w.id
@@ -143,6 +208,8 @@ func (w *Worker) GetStats() WorkerStats {
 		IsBusy:             w.IsBusy(),
 		JobsProcessed:      jobsProcessed,
 		JobsFailed:         w.jobsFailed.Load(),
+		JobsRequeued:       w.jobsRequeued.Load(),
+		JobsDeferred:       w.jobsDeferred.Load(),
 		AverageJobDuration: avgDuration,
 		CurrentJob:         w.GetCurrentJob(),
 	}
@@ -154,6 +221,8 @@ type WorkerStats struct {
 	IsBusy             bool          `json:"is_busy"`
 	JobsProcessed      int64         `json:"jobs_processed"`
 	JobsFailed         int64         `json:"jobs_failed"`
+	JobsRequeued       int64         `json:"jobs_requeued"`
+	JobsDeferred       int64         `json:"jobs_deferred"`
 	AverageJobDuration time.Duration `json:"average_job_duration"`
 	CurrentJob         *queue.Job    `json:"current_job,omitempty"`
 }
@@ -236,6 +236,59 @@ func (c *Client) GetJobStatus(ctx context.Context, jobID string) (*JobResponse,
 	return &jobResp, nil
 }
 
+// ReplayJobsParams holds parameters for replaying failed jobs
+type ReplayJobsParams struct {
+	Type  string
+	Since *time.Time
+}
+
+// ReplayJobsResponse represents the result of a replay request
+type ReplayJobsResponse struct {
+	Replayed   []string `json:"replayed"`
+	Skipped    []string `json:"skipped"`
+	MaxReplays int      `json:"max_replays"`
+}
+
+// ReplayFailedJobs requeues failed jobs from the dead letter queue (requires admin role)
+func (c *Client) ReplayFailedJobs(ctx context.Context, params ReplayJobsParams) (*ReplayJobsResponse, error) {
+	endpoint := "/api/v1/admin/jobs/replay"
+
+	queryParams := url.Values{}
+	queryParams.Set("status", "failed")
+	if params.Type != "" {
+		queryParams.Set("type", params.Type)
+	}
+	if params.Since != nil {
+		queryParams.Set("since", params.Since.Format(time.RFC3339))
+	}
+
+	fullURL := fmt.Sprintf("%s%s?%s", c.baseURL, endpoint, queryParams.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var replayResp ReplayJobsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&replayResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &replayResp, nil
+}
+
 // ListJurisdictions retrieves the list of supported jurisdictions
 func (c *Client) ListJurisdictions(ctx context.Context) ([]string, error) {
 	endpoint := "/api/v1/jurisdictions"
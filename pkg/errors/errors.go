@@ -12,14 +12,17 @@ var (
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 	ErrParsingFailure    = errors.New("failed to parse response")
 	ErrRobotsDisallowed  = errors.New("robots.txt disallows scraping")
+	ErrURLBlocked        = errors.New("URL is blocklisted for this source")
 	ErrTimeout           = errors.New("request timeout")
 	ErrInvalidResponse   = errors.New("invalid response from server")
+	ErrRetryBudgetExhausted = errors.New("scrape run retry budget exhausted")
 
 	// Validation Errors
 	ErrValidationFailed  = errors.New("validation failed")
 	ErrInvalidData       = errors.New("invalid data")
 	ErrMissingRequired   = errors.New("missing required field")
 	ErrDuplicateEntry    = errors.New("duplicate entry detected")
+	ErrResultWindow      = errors.New("result window exceeded")
 
 	// Storage Errors
 	ErrNotFound          = errors.New("resource not found")
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Annotation is a private note a user attaches to a case, such as research
+// notes or tags for a brief. It belongs to the user and tenant that wrote it
+// and is kept apart from the case, so case exports and public dumps never
+// include it.
+type Annotation struct {
+	ID        string    `json:"id" bson:"id"`
+	CaseID    string    `json:"case_id" bson:"case_id" validate:"required"`
+	UserID    string    `json:"user_id" bson:"user_id" validate:"required"`
+	TenantID  string    `json:"tenant_id,omitempty" bson:"tenant_id"` // empty outside tenancy
+	Note      string    `json:"note" bson:"note"`
+	Tags      []string  `json:"tags,omitempty" bson:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" bson:"updated_at"`
+}
@@ -13,6 +13,7 @@ const (
 	CaseStatusClosed     CaseStatus = "closed"
 	CaseStatusAppealed   CaseStatus = "appealed"
 	CaseStatusOverturned CaseStatus = "overturned"
+	CaseStatusOverruled  CaseStatus = "overruled" // no longer good law, see metadata overruled_by
 )
 
 // CourtLevel represents the hierarchical level of a court
@@ -54,6 +55,7 @@ type Case struct {
 
 	// Court Information
 	Court           string      `json:"court" validate:"required"`
+	CourtID         string      `json:"court_id,omitempty"` // canonical court abbreviation, e.g. UKSC
 	CourtLevel      CourtLevel  `json:"court_level" validate:"required,min=1,max=5"`
 	CourtType       CourtType   `json:"court_type" validate:"required"`
 	Jurisdiction    string      `json:"jurisdiction" validate:"required"`
@@ -70,7 +72,9 @@ type Case struct {
 	// Content
 	Summary     string    `json:"summary,omitempty"`
 	Headnotes   string    `json:"headnotes,omitempty"`
+	Holding     string    `json:"holding,omitempty"` // best-effort holding / ratio decidendi, see metadata holding_confidence
 	FullText    string    `json:"full_text,omitempty"`
+	Sections    map[string]string `json:"sections,omitempty" xml:"-"` // full text by judgment section, e.g. "facts", "reasoning"
 	Language    string    `json:"language" validate:"required"`
 
 	// Citations
@@ -78,10 +82,16 @@ type Case struct {
 	CitedBy         []string    `json:"cited_by,omitempty"`
 	Precedent       []string    `json:"precedent,omitempty"`
 
+	// Appellate Chain
+	LowerCourtCaseID string     `json:"lower_court_case_id,omitempty"` // the decision this case appealed from
+	AppealedToCaseID string     `json:"appealed_to_case_id,omitempty"` // the appeal decided against this case
+
 	// Legal Concepts
+	KeyIssues       []string    `json:"key_issues,omitempty"`
 	LegalConcepts   []string    `json:"legal_concepts,omitempty"`
 	AreasOfLaw      []string    `json:"areas_of_law,omitempty"`
 	Keywords        []string    `json:"keywords,omitempty"`
+	Catchwords      []string    `json:"catchwords,omitempty"` // editorial catchwords block, e.g. "Negligence", "duty of care"
 
 	// Case Outcome
 	Status          CaseStatus  `json:"status" validate:"required"`
@@ -93,10 +103,14 @@ type Case struct {
 	SourceDatabase  string      `json:"source_database" validate:"required"`
 	ScrapedAt       time.Time   `json:"scraped_at" validate:"required"`
 	LastUpdated     time.Time   `json:"last_updated" validate:"required"`
+	Version         int         `json:"version"` // incremented when the source content changes
+	ExtractionVersion int       `json:"extraction_version,omitempty"` // version of the extraction logic that parsed the case
+	ContentHash     string      `json:"content_hash,omitempty"` // hash of the scraped content, to skip re-saving unchanged cases
 
 	// Metadata
-	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	Metadata        map[string]interface{} `json:"metadata,omitempty" xml:"-"` // maps can't be encoded as XML
 	QualityScore    float64     `json:"quality_score" validate:"min=0,max=1"`
+	TenantID        string      `json:"tenant_id,omitempty"` // owning tenant of imported or annotated cases; empty for shared public data
 
 	// Document Information
 	DocumentType    string      `json:"document_type,omitempty"`
@@ -136,6 +150,16 @@ func (c *Case) IsComplete() bool {
 		c.URL != ""
 }
 
+// Age returns how long before now the case was last updated, its freshness
+func (c *Case) Age(now time.Time) time.Duration {
+	return now.Sub(c.LastUpdated)
+}
+
+// IsStale reports whether the case has not been updated within maxAge of now
+func (c *Case) IsStale(now time.Time, maxAge time.Duration) bool {
+	return c.LastUpdated.Before(now.Add(-maxAge))
+}
+
 // AddCitation adds a citation to the case
 func (c *Case) AddCitation(citation Citation) {
 	c.Citations = append(c.Citations, citation)
@@ -15,6 +15,9 @@ const (
 
 // Citation represents a legal citation
 type Citation struct {
+	// Storage-assigned ID (ObjectID hex for MongoDB, row ID for SQL backends)
+	ID              string          `json:"id,omitempty"`
+
 	// Core Citation Information
 	RawCitation     string          `json:"raw_citation" validate:"required"`
 	NormalizedCitation string       `json:"normalized_citation,omitempty"`
@@ -40,10 +43,12 @@ type Citation struct {
 	// Validation
 	IsValid         bool            `json:"is_valid"`
 	ValidationError string          `json:"validation_error,omitempty"`
+	IsNormalized    bool            `json:"is_normalized,omitempty"` // NormalizedCitation was set by the normalizer
 
 	// Metadata
 	ExtractedAt     time.Time       `json:"extracted_at" validate:"required"`
 	Confidence      float64         `json:"confidence" validate:"min=0,max=1"`
+	TenantID        string          `json:"tenant_id,omitempty"` // owning tenant; empty for shared public data
 }
 
 // CitationNetwork represents a network of case citations
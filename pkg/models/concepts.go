@@ -21,10 +21,15 @@ type LegalConcept struct {
 	Description string   `json:"description,omitempty"`
 	Jurisdiction string  `json:"jurisdiction,omitempty"` // Some concepts are jurisdiction-specific
 	AreaOfLaw   string   `json:"area_of_law,omitempty"`
+	Area        AreaOfLaw `json:"area,omitempty"`
+	Importance  int       `json:"importance,omitempty"` // 1-10, used to weight matches and boost search results
 }
 
 // ConceptMatch represents a matched legal concept in a case
 type ConceptMatch struct {
+	ConceptID   string       `json:"concept_id"`
+	Name        string       `json:"name"`
+	Area        AreaOfLaw    `json:"area,omitempty"`
 	Concept     LegalConcept `json:"concept"`
 	Confidence  float64      `json:"confidence" validate:"min=0,max=1"`
 	Occurrences int          `json:"occurrences"`
@@ -47,6 +52,7 @@ const (
 	AreaOfLawTax            AreaOfLaw = "tax"
 	AreaOfLawIntellectualProperty AreaOfLaw = "intellectual_property"
 	AreaOfLawEnvironmental  AreaOfLaw = "environmental"
+	AreaOfLawEvidence       AreaOfLaw = "evidence"
 	AreaOfLawAdministrative AreaOfLaw = "administrative"
 	AreaOfLawInternational  AreaOfLaw = "international"
 	AreaOfLawHumanRights    AreaOfLaw = "human_rights"
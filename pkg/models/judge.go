@@ -27,6 +27,7 @@ type Judge struct {
 
 	// Metadata
 	Metadata        map[string]interface{} `json:"metadata,omitempty"`
+	TenantID        string    `json:"tenant_id,omitempty"` // owning tenant; empty for shared public data
 	CreatedAt       time.Time `json:"created_at" validate:"required"`
 	UpdatedAt       time.Time `json:"updated_at" validate:"required"`
 }
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// SavedSearch is a query and filters a user keeps to be alerted when newly
+// scraped cases match them. Like annotations, it belongs to the user and
+// tenant that saved it.
+type SavedSearch struct {
+	ID         string             `json:"id" bson:"id"`
+	UserID     string             `json:"user_id" bson:"user_id" validate:"required"`
+	TenantID   string             `json:"tenant_id,omitempty" bson:"tenant_id"` // empty outside tenancy
+	Name       string             `json:"name" bson:"name"`
+	Query      string             `json:"query,omitempty" bson:"query"`
+	Filters    SavedSearchFilters `json:"filters" bson:"filters"`
+	WebhookURL string             `json:"webhook_url" bson:"webhook_url" validate:"required,url"` // where alerts are posted
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// SavedSearchFilters narrows the cases a saved search alerts on
+type SavedSearchFilters struct {
+	Jurisdiction string   `json:"jurisdiction,omitempty" bson:"jurisdiction,omitempty"`
+	Court        string   `json:"court,omitempty" bson:"court,omitempty"`
+	Concepts     []string `json:"concepts,omitempty" bson:"concepts,omitempty"`
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/gongahkia/kite/internal/admin/commands"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runAdmin executes a kite-admin command tree and returns its stdout
+func runAdmin(t *testing.T, sub *cobra.Command, args ...string) string {
+	root := &cobra.Command{Use: "kite-admin", SilenceUsage: true}
+	root.PersistentFlags().BoolP("json", "j", false, "Output in JSON format")
+	root.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
+	root.AddCommand(sub)
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs(args)
+	require.NoError(t, root.Execute())
+
+	return out.String()
+}
+
+// TestAdminHealthCheckJSON tests that health check emits parseable JSON when --json is set
+func TestAdminHealthCheckJSON(t *testing.T) {
+	out := runAdmin(t, commands.NewHealthCmd(), "health", "check", "--json")
+
+	var report struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &report), out)
+	assert.Equal(t, "healthy", report.Status)
+	assert.Equal(t, "healthy", report.Checks["database"])
+	assert.Len(t, report.Checks, 5)
+}
+
+// TestAdminHealthComponentJSON tests that component checks omit progress text in JSON mode
+func TestAdminHealthComponentJSON(t *testing.T) {
+	out := runAdmin(t, commands.NewHealthCmd(), "health", "queue", "-j")
+
+	var health struct {
+		Component string                 `json:"component"`
+		Status    string                 `json:"status"`
+		Details   map[string]interface{} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(out), &health), out)
+	assert.Equal(t, "queue", health.Component)
+	assert.Equal(t, "healthy", health.Status)
+	assert.Contains(t, health.Details, "dlq_size")
+}
+
+// TestAdminHealthCheckTable tests that health check renders a human table without --json
+func TestAdminHealthCheckTable(t *testing.T) {
+	out := runAdmin(t, commands.NewHealthCmd(), "health", "check")
+
+	assert.False(t, json.Valid([]byte(out)))
+	assert.Contains(t, out, "System Health Check:")
+	assert.Contains(t, out, "Overall Status:  ✓ healthy")
+}
+
+// TestAdminJSONOutput tests that stats and action subcommands honor --json
+func TestAdminJSONOutput(t *testing.T) {
+	cases := []struct {
+		cmd  func() *cobra.Command
+		args []string
+	}{
+		{commands.NewMetricsCmd, []string{"metrics", "api", "--json"}},
+		{commands.NewQueueCmd, []string{"queue", "stats", "--json"}},
+		{commands.NewQueueCmd, []string{"queue", "dlq", "list", "--json"}},
+		{commands.NewCacheCmd, []string{"cache", "stats", "--json"}},
+		{commands.NewCacheCmd, []string{"cache", "flush", "--json", "--verbose"}},
+		{commands.NewConfigCmd, []string{"config", "env", "--json"}},
+	}
+
+	for _, tc := range cases {
+		out := runAdmin(t, tc.cmd(), tc.args...)
+		assert.True(t, json.Valid([]byte(out)), "%v: %s", tc.args, out)
+	}
+}
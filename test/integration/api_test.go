@@ -0,0 +1,1238 @@
+package integration
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/api"
+	"github.com/gongahkia/kite/internal/api/handlers"
+	"github.com/gongahkia/kite/internal/api/middleware"
+	"github.com/gongahkia/kite/internal/compliance"
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/jurisdiction"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/worker"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCacheControlHeaders tests the Cache-Control header per endpoint type
+func TestCacheControlHeaders(t *testing.T) {
+	cache := middleware.DefaultCacheConfig()
+
+	app := fiber.New()
+	app.Use(middleware.ETag())
+	ok := func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"ok": true}) }
+	app.Get("/cases/:id", middleware.CacheControl(cache.Case), func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return fiber.ErrNotFound
+		}
+		return ok(c)
+	})
+	app.Get("/cases", middleware.CacheControl(cache.List), ok)
+	app.Get("/stats", middleware.CacheControl(cache.Stats), ok)
+	app.Get("/jurisdictions", middleware.CacheControl(cache.Reference), ok)
+
+	tests := []struct {
+		path         string
+		cacheControl string
+	}{
+		{"/cases/UKSC-2023-15", "public, max-age=86400"},
+		{"/cases", "public, max-age=300"},
+		{"/stats", "public, max-age=60"},
+		{"/jurisdictions", "public, max-age=3600"},
+		{"/cases/missing", "no-store"},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, tt.cacheControl, resp.Header.Get("Cache-Control"), tt.path)
+	}
+
+	// Revalidation with the ETag keeps the same caching headers
+	resp, err := app.Test(httptest.NewRequest("GET", "/cases/UKSC-2023-15", nil))
+	require.NoError(t, err)
+	tag := resp.Header.Get("ETag")
+	require.NotEmpty(t, tag)
+
+	req := httptest.NewRequest("GET", "/cases/UKSC-2023-15", nil)
+	req.Header.Set("If-None-Match", tag)
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+	assert.Equal(t, "public, max-age=86400", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "Accept, Accept-Encoding", resp.Header.Get("Vary"))
+}
+
+// TestDisabledJurisdictionNotFound tests that disabled jurisdictions are neither registered nor served,
+// and their stored cases are not found
+func TestDisabledJurisdictionNotFound(t *testing.T) {
+	registry := scraper.NewScraperRegistry()
+	registry.SetJurisdictionFilter(scraper.NewJurisdictionFilter([]string{"singapore"}))
+	registry.Register("singapore", &refreshScraper{BaseScraper: scraper.NewBaseScraper("SingaporeLawWatch", "Singapore", "https://example.sg", 60)})
+	registry.Register("bailii", &refreshScraper{BaseScraper: scraper.NewBaseScraper("BAILII", "United Kingdom", "https://example.uk", 60)})
+
+	_, ok := registry.Get("bailii")
+	assert.False(t, ok, "scraper for a disabled jurisdiction must not be registered")
+	_, ok = registry.Get("singapore")
+	assert.True(t, ok)
+
+	filter := registry.JurisdictionFilter()
+	h := handlers.NewJurisdictionHandler(registry, nil)
+
+	memory := storage.NewMemoryStorage()
+	for id, jurisdiction := range map[string]string{"sg-case": "Singapore", "uk-case": "United Kingdom"} {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Contract dispute " + id
+		c.Jurisdiction = jurisdiction
+		require.NoError(t, memory.SaveCase(context.Background(), c))
+	}
+	store := storage.NewJurisdictionStorage(memory, filter.List())
+	logger := observability.NewLogger("error", "json")
+	caseHandler := handlers.NewCaseHandler(store, logger)
+
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Get("/jurisdictions/:jurisdiction/sources", h.ListSources)
+	app.Get("/cases", caseHandler.ListCases)
+	app.Get("/cases/:id", caseHandler.GetCase)
+	app.Post("/cases/search", caseHandler.SearchCases)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/jurisdictions/Singapore/sources", fiber.StatusOK},
+		{"/jurisdictions/United%20Kingdom/sources", fiber.StatusNotFound},
+		{"/cases/sg-case", fiber.StatusOK},
+		{"/cases/uk-case", fiber.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", tt.path, nil))
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, tt.path)
+	}
+
+	// Lists and searches leave out the disabled jurisdiction's cases, whether
+	// or not they name it
+	listed := []struct {
+		req *http.Request
+		ids []string
+	}{
+		{httptest.NewRequest("GET", "/cases", nil), []string{"sg-case"}},
+		{httptest.NewRequest("GET", "/cases?jurisdiction=Singapore", nil), []string{"sg-case"}},
+		{httptest.NewRequest("GET", "/cases?jurisdiction=United+Kingdom", nil), []string{}},
+		{httptest.NewRequest("GET", "/cases?jurisdiction=Singapore&jurisdiction=United+Kingdom", nil), []string{"sg-case"}},
+		{httptest.NewRequest("POST", "/cases/search", strings.NewReader(`{"query":"contract"}`)), []string{"sg-case"}},
+		{httptest.NewRequest("POST", "/cases/search", strings.NewReader(`{"query":"contract","filters":{"jurisdiction":"United Kingdom"}}`)), []string{}},
+		{httptest.NewRequest("POST", "/cases/search", strings.NewReader(`{"query":"contract","filters":{"jurisdictions":["Singapore","United Kingdom"]}}`)), []string{"sg-case"}},
+	}
+
+	for _, tt := range listed {
+		tt.req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(tt.req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode, "%s %s", tt.req.Method, tt.req.URL)
+
+		var body struct {
+			Data []*models.Case `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		ids := []string{}
+		for _, c := range body.Data {
+			ids = append(ids, c.ID)
+		}
+		assert.Equal(t, tt.ids, ids, "%s %s", tt.req.Method, tt.req.URL)
+	}
+}
+
+// probeScraper reports a fixed availability and counts its health checks
+type probeScraper struct {
+	*refreshScraper
+	available bool
+	checks    int32
+}
+
+func (s *probeScraper) IsAvailable(ctx context.Context) bool {
+	atomic.AddInt32(&s.checks, 1)
+	return s.available
+}
+
+// TestListJurisdictionsReportsPolicyAndStatus tests that registered sources are listed with their policy and availability
+func TestListJurisdictionsReportsPolicyAndStatus(t *testing.T) {
+	bailii := &probeScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("BAILII", "United Kingdom", "https://www.bailii.org", 12)},
+		available:      true,
+	}
+	local := &probeScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("LocalReports", "Singapore", "https://example.sg", 30)},
+	}
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", bailii)
+	registry.Register("local", local)
+
+	h := handlers.NewJurisdictionHandler(registry, nil)
+	app := fiber.New()
+	app.Get("/jurisdictions", h.ListJurisdictions)
+
+	list := func() []handlers.SourceInfo {
+		resp, err := app.Test(httptest.NewRequest("GET", "/jurisdictions", nil))
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Data  []handlers.SourceInfo `json:"data"`
+			Total int                   `json:"total"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		assert.Equal(t, len(body.Data), body.Total)
+		return body.Data
+	}
+
+	sources := list()
+	require.Len(t, sources, 2)
+
+	// Sorted by jurisdiction, then name
+	assert.Equal(t, "LocalReports", sources[0].Name)
+	assert.Equal(t, "Singapore", sources[0].Jurisdiction)
+	assert.Nil(t, sources[0].Compliance, "source without a policy has no compliance summary")
+	assert.Equal(t, scraper.ScraperStatusUnavailable, sources[0].Status)
+	assert.False(t, sources[0].LastChecked.IsZero())
+
+	assert.Equal(t, "BAILII", sources[1].Name)
+	assert.Equal(t, "United Kingdom", sources[1].Jurisdiction)
+	assert.Equal(t, "https://www.bailii.org", sources[1].BaseURL)
+	assert.Equal(t, 12, sources[1].RateLimit)
+	assert.Equal(t, scraper.ScraperStatusActive, sources[1].Status)
+	require.NotNil(t, sources[1].Compliance)
+	assert.True(t, sources[1].Compliance.AllowScraping)
+	assert.True(t, sources[1].Compliance.RequiresAttribution)
+	assert.Equal(t, compliance.CommercialUseRestricted, sources[1].Compliance.CommercialUse)
+	assert.Equal(t, 12, sources[1].Compliance.RateLimit)
+	assert.NotEmpty(t, sources[1].Compliance.TermsOfServiceURL)
+
+	// Availability is cached between requests
+	list()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&bailii.checks))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&local.checks))
+}
+
+// TestListCasesRepeatedJurisdictions tests that repeated jurisdiction params match cases from each
+func TestListCasesRepeatedJurisdictions(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	for i, j := range []string{"UK", "Australia", "Singapore"} {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("multi-%d", i)
+		c.Jurisdiction = j
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	app := fiber.New()
+	app.Get("/cases", handlers.NewCaseHandler(store, nil).ListCases)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/cases?jurisdiction=UK&jurisdiction=Australia", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data []*models.Case `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	jurisdictions := make([]string, 0, len(body.Data))
+	for _, c := range body.Data {
+		jurisdictions = append(jurisdictions, c.Jurisdiction)
+	}
+	assert.ElementsMatch(t, []string{"UK", "Australia"}, jurisdictions)
+}
+
+// TestListCasesRepeatedCourts tests that each repeated court param matches its variants, including cases stored without a court ID, or its name if unknown
+func TestListCasesRepeatedCourts(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	enricher := jurisdiction.NewMetadataEnricher()
+	for i, court := range []string{"UK Supreme Court", "UKSC", "High Court (England & Wales)", "Court of Nowhere", "Court of Elsewhere"} {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("court-%d", i)
+		c.Court = court
+		require.NoError(t, enricher.EnrichCase(c))
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	// Stored before courts were normalized
+	legacy := models.NewCase()
+	legacy.ID = "court-legacy"
+	legacy.Court = "UK Supreme Court"
+	require.NoError(t, store.SaveCase(ctx, legacy))
+
+	app := fiber.New()
+	app.Get("/cases", handlers.NewCaseHandler(store, nil).ListCases)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/cases?court=uksc&court=Court+of+Nowhere", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Data  []*models.Case `json:"data"`
+		Total int64          `json:"total"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+
+	ids := make([]string, 0, len(body.Data))
+	for _, c := range body.Data {
+		ids = append(ids, c.ID)
+	}
+	assert.ElementsMatch(t, []string{"court-0", "court-1", "court-3", "court-legacy"}, ids)
+	assert.Equal(t, int64(4), body.Total)
+}
+
+// TestCreateCaseConflict tests that creating a case with a taken ID gets a 409
+func TestCreateCaseConflict(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	app := fiber.New()
+	app.Post("/cases", handlers.NewCaseHandler(store, nil).CreateCase)
+
+	create := func() int {
+		req := httptest.NewRequest("POST", "/cases", strings.NewReader(`{"id":"conflict-1","case_name":"First v Second"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, fiber.StatusCreated, create())
+	assert.Equal(t, fiber.StatusConflict, create())
+}
+
+// TestResultWindowRejectsOversizedPages tests that list and search requests beyond the result window get a 400
+func TestResultWindowRejectsOversizedPages(t *testing.T) {
+	window := storage.ResultWindow{MaxLimit: 100, MaxOffset: 500}
+
+	app := fiber.New()
+	app.Use(middleware.ResultWindow(window))
+	ok := func(c *fiber.Ctx) error { return c.JSON(fiber.Map{"data": []string{}}) }
+	app.Get("/cases", ok)
+	app.Post("/search", ok)
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"GET", "/cases", "", fiber.StatusOK},
+		{"GET", "/cases?limit=100&offset=500", "", fiber.StatusOK},
+		{"GET", "/cases?limit=1000000", "", fiber.StatusBadRequest},
+		{"GET", "/cases?limit=10&offset=5000000", "", fiber.StatusBadRequest},
+		{"GET", "/cases?offset=-1", "", fiber.StatusBadRequest},
+		{"POST", "/search", `{"query":"contract","limit":50}`, fiber.StatusOK},
+		{"POST", "/search", `{"query":"contract","limit":1000000}`, fiber.StatusBadRequest},
+		{"POST", "/search", `{"query":"contract","offset":5000000}`, fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.status, resp.StatusCode, "%s %s %s", tt.method, tt.path, tt.body)
+
+		if resp.StatusCode == fiber.StatusBadRequest {
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			assert.NotEmpty(t, body["error"])
+			assert.EqualValues(t, 100, body["max_limit"])
+		}
+	}
+
+	// Zero bounds are unlimited
+	assert.NoError(t, storage.ResultWindow{}.Check(1000000, 5000000))
+	assert.Error(t, storage.DefaultResultWindow().Check(storage.DefaultMaxLimit+1, 0))
+}
+
+// TestDefaultPageSizeAcrossBackends tests that requests without a limit get the configured page size on every backend
+func TestDefaultPageSizeAcrossBackends(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "pages.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	// count decodes the length of the data or results array of a response
+	count := func(t *testing.T, app *fiber.App, req *http.Request) int {
+		t.Helper()
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Data    []json.RawMessage `json:"data"`
+			Results []json.RawMessage `json:"results"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return len(body.Data) + len(body.Results)
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 30; i++ {
+				c := models.NewCase()
+				c.ID = fmt.Sprintf("page-%02d", i)
+				c.CaseName = fmt.Sprintf("Contract dispute %d", i)
+				c.Summary = "A case about contract law"
+				c.Jurisdiction = "UK"
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			for _, window := range []storage.ResultWindow{
+				storage.DefaultResultWindow(),
+				{DefaultLimit: 7, MaxLimit: 100},
+			} {
+				want := window.Limit(0)
+
+				caseHandler := handlers.NewCaseHandler(store, nil)
+				caseHandler.SetResultWindow(window)
+				searchHandler := handlers.NewSearchHandler(store, observability.NewLogger("error", "json"), searchMetrics)
+				searchHandler.SetResultWindow(window)
+
+				app := fiber.New()
+				app.Get("/cases", caseHandler.ListCases)
+				app.Post("/search", searchHandler.Search)
+
+				assert.Equal(t, want, count(t, app, httptest.NewRequest("GET", "/cases", nil)), "GET /cases")
+
+				req := httptest.NewRequest("POST", "/search", strings.NewReader(`{"query":"contract"}`))
+				req.Header.Set("Content-Type", "application/json")
+				assert.Equal(t, want, count(t, app, req), "POST /search")
+
+				// An explicit limit still wins
+				assert.Equal(t, 3, count(t, app, httptest.NewRequest("GET", "/cases?limit=3", nil)))
+			}
+
+			// A zero limit reaching storage returns every case
+			all, err := store.ListCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Len(t, all, 30)
+		})
+	}
+
+	// The default page size never exceeds the largest page allowed
+	assert.Equal(t, 5, storage.ResultWindow{DefaultLimit: 20, MaxLimit: 5}.Limit(0))
+	assert.Equal(t, 0, storage.ResultWindow{}.Limit(0))
+}
+
+// TestServerCutsOffSlowHeaders tests that a client slow to send its request headers is disconnected
+func TestServerCutsOffSlowHeaders(t *testing.T) {
+	server := api.NewServer(storage.NewMemoryStorage(), observability.NewLogger("error", "json"), nil, nil)
+	timeouts := api.DefaultServerTimeouts()
+	timeouts.ReadHeaderTimeout = 200 * time.Millisecond
+	server.SetTimeouts(timeouts)
+	server.GetApp().Get("/ping", func(c *fiber.Ctx) error { return c.SendString("pong") })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.GetApp().Listener(ln)
+	defer server.Shutdown()
+
+	// A prompt client is served
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/ping")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// A client that never finishes its headers is dropped
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /ping HTTP/1.1\r\nHost: kite\r\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	start := time.Now()
+	reply, err := io.ReadAll(conn)
+	require.NoError(t, err, "the server should close the connection before the client gives up")
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.NotContains(t, string(reply), "pong")
+}
+
+// TestJudgeCasesHandler tests that a judge's cases are served newest first
+func TestJudgeCasesHandler(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	judge := models.NewJudge("Lady Hale")
+	judge.ID = "judge-hale"
+	require.NoError(t, store.SaveJudge(ctx, judge))
+
+	for i, judges := range [][]string{{"Lady Hale"}, {"judge-hale", "Lord Kerr"}, {"Lord Kerr"}} {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%d", i)
+		c.Judges = judges
+		decided := time.Date(2019, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC)
+		c.DecisionDate = &decided
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	h := handlers.NewJudgeHandler(store, nil)
+	app := fiber.New()
+	app.Get("/judges/:id/cases", h.GetJudgeCases)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/judges/judge-hale/cases", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Judge string         `json:"judge"`
+		Data  []*models.Case `json:"data"`
+		Total int64          `json:"total"`
+		Limit int            `json:"limit"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "judge-hale", body.Judge)
+	assert.Equal(t, int64(2), body.Total)
+	assert.Equal(t, storage.DefaultPageSize, body.Limit)
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, "case-1", body.Data[0].ID)
+	assert.Equal(t, "case-0", body.Data[1].ID)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/judges/no-such-judge/cases", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
+
+// TestResponseFieldNaming tests that the same case serializes with snake_case or camelCase keys by config or Accept header
+func TestResponseFieldNaming(t *testing.T) {
+	decided := time.Date(2023, 3, 15, 0, 0, 0, 0, time.UTC)
+	c := models.NewCase()
+	c.ID = "UKSC-2023-15"
+	c.CaseName = "R (Miller) v Secretary of State"
+	c.CaseNumber = "[2023] UKSC 15"
+	c.DecisionDate = &decided
+	c.SourceDatabase = "BAILII"
+	c.QualityScore = 0.85
+	c.Citations = []models.Citation{{RawCitation: "[2017] UKSC 5", Format: models.CitationFormatNeutral}}
+	c.Metadata = map[string]interface{}{"summary_source": "headnote"}
+
+	newApp := func(naming middleware.FieldNaming) *fiber.App {
+		app := fiber.New()
+		app.Use(middleware.ResponseFieldNaming(naming))
+		app.Get("/cases/:id", func(ctx *fiber.Ctx) error { return ctx.JSON(c) })
+		return app
+	}
+	get := func(app *fiber.App, accept string) (map[string]interface{}, *http.Response) {
+		req := httptest.NewRequest("GET", "/cases/UKSC-2023-15", nil)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body, resp
+	}
+
+	assertSnake := func(body map[string]interface{}) {
+		assert.Equal(t, "R (Miller) v Secretary of State", body["case_name"])
+		assert.Equal(t, "BAILII", body["source_database"])
+		assert.Equal(t, 0.85, body["quality_score"])
+		assert.Contains(t, body, "decision_date")
+		assert.NotContains(t, body, "caseName")
+		citation := body["citations"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "[2017] UKSC 5", citation["raw_citation"])
+	}
+	assertCamel := func(body map[string]interface{}) {
+		assert.Equal(t, "R (Miller) v Secretary of State", body["caseName"])
+		assert.Equal(t, "BAILII", body["sourceDatabase"])
+		assert.Equal(t, "[2023] UKSC 15", body["caseNumber"])
+		assert.Equal(t, 0.85, body["qualityScore"])
+		assert.Equal(t, "2023-03-15T00:00:00Z", body["decisionDate"])
+		assert.Equal(t, "UKSC-2023-15", body["id"])
+		assert.NotContains(t, body, "case_name")
+		citation := body["citations"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "[2017] UKSC 5", citation["rawCitation"])
+		assert.Equal(t, "headnote", body["metadata"].(map[string]interface{})["summarySource"])
+	}
+
+	snakeApp := newApp(middleware.SnakeCase)
+	body, resp := get(snakeApp, "")
+	assertSnake(body)
+	assert.Contains(t, resp.Header.Get("Vary"), "Accept")
+
+	body, _ = get(snakeApp, "application/json; naming=camel")
+	assertCamel(body)
+
+	camelApp := newApp(middleware.CamelCase)
+	body, _ = get(camelApp, "")
+	assertCamel(body)
+	body, _ = get(camelApp, "text/html, application/json;naming=snake_case")
+	assertSnake(body)
+
+	// Renaming keeps key order and values, and round-trips
+	snake := []byte(`{"case_name":"Miller v Secretary of State","court_id":"UKSC","judges":["Lord Reed"],"page_count":12345678901234567890}`)
+	camel, err := middleware.RenameJSONKeys(snake, middleware.CamelCase)
+	require.NoError(t, err)
+	assert.Equal(t, `{"caseName":"Miller v Secretary of State","courtId":"UKSC","judges":["Lord Reed"],"pageCount":12345678901234567890}`, string(camel))
+	back, err := middleware.RenameJSONKeys(camel, middleware.SnakeCase)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(snake), string(back))
+
+	naming, err := middleware.ParseFieldNaming("camelCase")
+	require.NoError(t, err)
+	assert.Equal(t, middleware.CamelCase, naming)
+	_, err = middleware.ParseFieldNaming("kebab-case")
+	assert.Error(t, err)
+}
+
+// slowPageStore delays every page of listed cases after the first
+type slowPageStore struct {
+	storage.Storage
+	delay time.Duration
+	pages int32
+}
+
+func (s *slowPageStore) ListCases(ctx context.Context, filter storage.CaseFilter) ([]*models.Case, error) {
+	if atomic.AddInt32(&s.pages, 1) > 1 {
+		time.Sleep(s.delay)
+	}
+	return s.Storage.ListCases(ctx, filter)
+}
+
+// TestListCasesStreamsNDJSON tests that NDJSON list responses are chunked, one case per line, with the first case sent before the last page is fetched
+func TestListCasesStreamsNDJSON(t *testing.T) {
+	ctx := context.Background()
+	memory := storage.NewMemoryStorage()
+	total := storage.StreamPageSize*2 + 50
+	for i := 0; i < total; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%03d", i)
+		c.CaseName = "Case " + c.ID
+		c.Jurisdiction = "UK"
+		require.NoError(t, memory.SaveCase(ctx, c))
+	}
+	store := &slowPageStore{Storage: memory, delay: 300 * time.Millisecond}
+
+	h := handlers.NewCaseHandler(store, observability.NewLogger("error", "json"))
+	app := fiber.New()
+	app.Use(middleware.ETag())
+	app.Use(middleware.ResponseFieldNaming(middleware.SnakeCase))
+	app.Get("/cases", h.ListCases)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	req, err := http.NewRequest("GET", "http://"+ln.Addr().String()+"/cases?jurisdiction=UK", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/x-ndjson")
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, middleware.MIMEApplicationNDJSON, resp.Header.Get("Content-Type"))
+	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+	assert.Empty(t, resp.Header.Get("ETag"))
+
+	lines := bufio.NewScanner(resp.Body)
+	lines.Buffer(make([]byte, 1024*1024), 1024*1024)
+	require.True(t, lines.Scan())
+	firstByte := time.Since(start)
+
+	seen := make(map[string]bool)
+	for {
+		var c models.Case
+		require.NoError(t, json.Unmarshal(lines.Bytes(), &c))
+		seen[c.ID] = true
+		if !lines.Scan() {
+			break
+		}
+	}
+	require.NoError(t, lines.Err())
+	elapsed := time.Since(start)
+
+	// Every case is streamed despite the default page size, and the first
+	// arrives before the slow pages are fetched
+	assert.Len(t, seen, total)
+	assert.GreaterOrEqual(t, elapsed, 600*time.Millisecond)
+	assert.Less(t, firstByte, 300*time.Millisecond)
+
+	// A limit caps the stream
+	req.URL.RawQuery = "limit=150"
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, 150, strings.Count(string(body), "\n"))
+
+	// Without the NDJSON Accept header the list is a single JSON page
+	plain, err := app.Test(httptest.NewRequest("GET", "/cases", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.MIMEApplicationJSON, plain.Header.Get("Content-Type"))
+}
+
+// TestImportCitationsLinksStoredCases tests that imported citation edges are resolved to stored cases and counted as linked or dangling
+func TestImportCitationsLinksStoredCases(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	citing := models.NewCase()
+	citing.ID = "uksc-2023-15"
+	citing.CaseNumber = "[2023] UKSC 15"
+	require.NoError(t, store.SaveCase(ctx, citing))
+
+	cited := models.NewCase()
+	cited.ID = "uksc-2020-5"
+	cited.CaseNumber = "[2020] UKSC 5"
+	require.NoError(t, store.SaveCase(ctx, cited))
+
+	app := fiber.New()
+	app.Post("/citations/import", handlers.NewCitationHandler(store, nil).ImportCitations)
+
+	body := strings.Join([]string{
+		// By citation on both sides
+		`{"citing_case_id":"[2023] UKSC 15","cited_case_id":"[2020] UKSC 5","raw_citation":"[2020] UKSC 5","treatment_type":"followed"}`,
+		// By stored ID
+		`{"citing_case_id":"uksc-2023-15","case_id":"uksc-2020-5","raw_citation":"[2020] UKSC 5 at [12]"}`,
+		// The cited case isn't stored
+		`{"citing_case_id":"uksc-2023-15","raw_citation":"[1932] AC 562"}`,
+		`{"citing_case_id":"uksc-2023-15"}`,
+		`not json`,
+	}, "\n")
+	req := httptest.NewRequest("POST", "/citations/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", middleware.MIMEApplicationNDJSON)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var result handlers.CitationImportResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 2, result.Linked)
+	assert.Equal(t, 1, result.Dangling)
+	assert.Equal(t, 2, result.Rejected)
+	require.Len(t, result.Errors, 2)
+	assert.Contains(t, result.Errors[0], "record 4")
+	assert.Contains(t, result.Errors[1], "record 5")
+
+	linked, err := store.ListCitations(ctx, storage.CitationFilter{CaseID: cited.ID})
+	require.NoError(t, err)
+	require.Len(t, linked, 2)
+	for _, cit := range linked {
+		assert.Equal(t, citing.ID, cit.CitingCaseID)
+	}
+
+	dangling, err := store.ListCitations(ctx, storage.CitationFilter{CaseID: "[1932] AC 562"})
+	require.NoError(t, err)
+	require.Len(t, dangling, 1)
+	assert.Equal(t, models.CitationFormatOther, dangling[0].Format)
+}
+
+// TestServerETagsAfterAuthAndLimits tests that the server only adds ETags to API responses that
+// made it past auth, rate limiting and request validation
+func TestServerETagsAfterAuthAndLimits(t *testing.T) {
+	store := storage.NewMemoryStorage()
+	c := models.NewCase()
+	c.ID = "etag-case"
+	require.NoError(t, store.SaveCase(context.Background(), c))
+
+	server := api.NewServer(store, observability.NewLogger("error", "json"), observability.NewMetrics(), nil)
+	server.SetupRoutes()
+	app := server.GetApp()
+
+	get := func(path, ifNoneMatch string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := get("/api/v1/cases/etag-case", "")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	tag := resp.Header.Get("ETag")
+	require.NotEmpty(t, tag)
+
+	resp = get("/api/v1/cases/etag-case", tag)
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	// Requests rejected before the handler are answered in full
+	resp = get("/api/v1/cases?limit=1000000", tag)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("ETag"))
+
+	// Routes outside the API aren't hashed
+	resp = get("/health", "")
+	assert.Empty(t, resp.Header.Get("ETag"))
+}
+
+// TestTenantScopedCaseAccess tests that API clients only reach their own tenant's and shared cases
+func TestTenantScopedCaseAccess(t *testing.T) {
+	store := storage.NewTenantStorage(storage.NewMemoryStorage())
+	defer store.Close()
+
+	authConfig := middleware.DefaultAuthConfig()
+	authConfig.APIKeys = map[string]string{"key-a": "client-a", "key-b": "client-b"}
+	authConfig.Tenants = map[string]string{"client-a": "tenant-a", "client-b": "tenant-b"}
+
+	logger := observability.NewLogger("error", "json")
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Use(middleware.OptionalAuth(authConfig, logger), middleware.TenantScope(authConfig))
+	caseHandler := handlers.NewCaseHandler(store, logger)
+	app.Get("/cases", caseHandler.ListCases)
+	app.Post("/cases", caseHandler.CreateCase)
+	app.Get("/cases/:id", middleware.CacheControl(middleware.DefaultCacheConfig().Case), caseHandler.GetCase)
+
+	do := func(method, path, apiKey, body string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := do("POST", "/cases", "key-a", `{"id":"tenant-case","case_name":"Acme v Widget"}`)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	resp = do("GET", "/cases/tenant-case", "key-a", "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.True(t, strings.HasPrefix(resp.Header.Get("Cache-Control"), "private"), "tenant responses aren't shared-cacheable")
+
+	assert.Equal(t, fiber.StatusNotFound, do("GET", "/cases/tenant-case", "key-b", "").StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, do("GET", "/cases/tenant-case", "", "").StatusCode)
+
+	var listed struct {
+		Total int64 `json:"total"`
+	}
+	resp = do("GET", "/cases", "key-b", "")
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+	assert.Zero(t, listed.Total)
+}
+
+// TestExportJobEndpoints tests reporting on and downloading an export job,
+// and that other tenants can't see it
+func TestExportJobEndpoints(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+	for i := 0; i < 3; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("export-%d", i)
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	authConfig := middleware.DefaultAuthConfig()
+	authConfig.APIKeys = map[string]string{"key-a": "client-a", "key-b": "client-b"}
+	authConfig.Tenants = map[string]string{"client-a": "tenant-a", "client-b": "tenant-b"}
+
+	exports := export.NewJobStore(t.TempDir())
+	request := export.NewJob(storage.WithTenant(ctx, "tenant-a"), store, "", storage.CaseFilter{}, export.FormatJSONLines, nil)
+	job := queue.NewJob(queue.JobTypeExport, request.Payload())
+	require.NoError(t, exports.Save(export.NewJobRecord(job.ID, request)))
+
+	logger := observability.NewLogger("error", "json")
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Use(middleware.OptionalAuth(authConfig, logger), middleware.TenantScope(authConfig))
+	exportHandler := handlers.NewExportHandler(exports, logger)
+	app.Get("/exports/:id", exportHandler.GetExportJob)
+	app.Get("/exports/:id/download", exportHandler.DownloadExport)
+
+	get := func(path, apiKey string) *http.Response {
+		req := httptest.NewRequest("GET", path, nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+	var status struct {
+		Status      string `json:"status"`
+		Count       int    `json:"count"`
+		DownloadURL string `json:"download_url"`
+	}
+
+	resp := get("/exports/"+job.ID, "key-a")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	assert.Equal(t, "pending", status.Status)
+	assert.Empty(t, status.DownloadURL)
+	assert.Equal(t, fiber.StatusConflict, get("/exports/"+job.ID+"/download", "key-a").StatusCode)
+
+	require.NoError(t, worker.NewExportJobHandler(store, exports)(ctx, job))
+
+	resp = get("/exports/"+job.ID, "key-a")
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	assert.Equal(t, "completed", status.Status)
+	assert.Equal(t, 3, status.Count)
+	assert.True(t, strings.HasSuffix(status.DownloadURL, "/api/v1/exports/"+job.ID+"/download"), status.DownloadURL)
+
+	resp = get("/exports/"+job.ID+"/download", "key-a")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), job.ID+".jsonl")
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(body), "\n"))
+
+	for _, apiKey := range []string{"key-b", ""} {
+		assert.Equal(t, fiber.StatusNotFound, get("/exports/"+job.ID, apiKey).StatusCode)
+		assert.Equal(t, fiber.StatusNotFound, get("/exports/"+job.ID+"/download", apiKey).StatusCode)
+	}
+	assert.Equal(t, fiber.StatusNotFound, get("/exports/..%2F"+job.ID, "key-a").StatusCode)
+}
+
+// TestCaseAnnotationsArePrivate tests creating case annotations and that each
+// caller only sees and changes its own
+func TestCaseAnnotationsArePrivate(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewTenantStorage(storage.NewMemoryStorage())
+	defer store.Close()
+
+	shared := models.NewCase()
+	shared.ID = "shared-case"
+	shared.CaseName = "Donoghue v Stevenson"
+	require.NoError(t, store.SaveCase(ctx, shared))
+
+	authConfig := middleware.DefaultAuthConfig()
+	authConfig.APIKeys = map[string]string{"key-a": "client-a", "key-b": "client-b", "key-c": "client-c"}
+	authConfig.Tenants = map[string]string{"client-c": "tenant-c"}
+
+	logger := observability.NewLogger("error", "json")
+	app := fiber.New(fiber.Config{ErrorHandler: middleware.ErrorHandler(logger)})
+	app.Use(middleware.OptionalAuth(authConfig, logger), middleware.TenantScope(authConfig))
+	app.Get("/cases/:id", handlers.NewCaseHandler(store, logger).GetCase)
+	annotationHandler := handlers.NewAnnotationHandler(store, logger)
+	app.Get("/cases/:id/annotations", annotationHandler.ListAnnotations)
+	app.Post("/cases/:id/annotations", annotationHandler.CreateAnnotation)
+	app.Put("/cases/:id/annotations/:annotation_id", annotationHandler.UpdateAnnotation)
+	app.Delete("/cases/:id/annotations/:annotation_id", annotationHandler.DeleteAnnotation)
+
+	do := func(method, path, apiKey, body string) *http.Response {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+	list := func(apiKey string) []models.Annotation {
+		resp := do("GET", "/cases/shared-case/annotations", apiKey, "")
+		require.Equal(t, fiber.StatusOK, resp.StatusCode)
+		var listed struct {
+			Data []models.Annotation `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&listed))
+		return listed.Data
+	}
+
+	note := `{"note":"Compare with the dissent","tags":["brief", " "]}`
+	assert.Equal(t, fiber.StatusUnauthorized, do("POST", "/cases/shared-case/annotations", "", note).StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, do("POST", "/cases/missing/annotations", "key-a", note).StatusCode)
+	assert.Equal(t, fiber.StatusBadRequest, do("POST", "/cases/shared-case/annotations", "key-a", `{"note":" "}`).StatusCode)
+
+	resp := do("POST", "/cases/shared-case/annotations", "key-a", note)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var created models.Annotation
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "shared-case", created.CaseID)
+	assert.Equal(t, "client-a", created.UserID)
+	assert.Equal(t, []string{"brief"}, created.Tags)
+
+	// Only the author lists, updates or deletes the annotation
+	require.Len(t, list("key-a"), 1)
+	assert.Empty(t, list("key-b"))
+	assert.Empty(t, list("key-c"))
+
+	path := "/cases/shared-case/annotations/" + created.ID
+	assert.Equal(t, fiber.StatusNotFound, do("PUT", path, "key-b", `{"note":"Hijacked"}`).StatusCode)
+	assert.Equal(t, fiber.StatusNotFound, do("DELETE", path, "key-c", "").StatusCode)
+	assert.Equal(t, "Compare with the dissent", list("key-a")[0].Note)
+
+	resp = do("PUT", path, "key-a", `{"note":"Followed in later appeals","tags":["brief","precedent"]}`)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	annotations := list("key-a")
+	require.Len(t, annotations, 1)
+	assert.Equal(t, "Followed in later appeals", annotations[0].Note)
+	assert.Equal(t, []string{"brief", "precedent"}, annotations[0].Tags)
+
+	// The case itself carries no annotations
+	resp = do("GET", "/cases/shared-case", "key-a", "")
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "Followed in later appeals")
+
+	assert.Equal(t, fiber.StatusNoContent, do("DELETE", path, "key-a", "").StatusCode)
+	assert.Empty(t, list("key-a"))
+}
+
+// closeTrackingStore blocks GetCase until released and records whether
+// storage was closed while a read was still running
+type closeTrackingStore struct {
+	*storage.MemoryStorage
+	started     chan struct{}
+	release     chan struct{}
+	closed      atomic.Bool
+	readsClosed atomic.Bool
+}
+
+func (s *closeTrackingStore) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	close(s.started)
+	<-s.release
+	if s.closed.Load() {
+		s.readsClosed.Store(true)
+	}
+	return s.MemoryStorage.GetCase(ctx, id)
+}
+
+func (s *closeTrackingStore) Close() error {
+	s.closed.Store(true)
+	return s.MemoryStorage.Close()
+}
+
+// TestShutdownDrainsRequestsBeforeClosingStorage tests that graceful shutdown
+// waits for in-flight requests before closing the storage they use
+func TestShutdownDrainsRequestsBeforeClosingStorage(t *testing.T) {
+	store := &closeTrackingStore{
+		MemoryStorage: storage.NewMemoryStorage(),
+		started:       make(chan struct{}),
+		release:       make(chan struct{}),
+	}
+	c := models.NewCase()
+	c.ID = "in-flight"
+	require.NoError(t, store.SaveCase(context.Background(), c))
+
+	server := api.NewServer(store, observability.NewLogger("error", "json"), nil, nil)
+	server.GetApp().Get("/cases/:id", func(c *fiber.Ctx) error {
+		found, err := store.GetCase(c.Context(), c.Params("id"))
+		if err != nil {
+			return err
+		}
+		return c.JSON(found)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.GetApp().Listener(ln)
+
+	status := make(chan int, 1)
+	go func() {
+		client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+		resp, err := client.Get("http://" + ln.Addr().String() + "/cases/in-flight")
+		if err != nil {
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+	<-store.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- api.GracefulShutdown(ctx, time.Second,
+			[]api.ShutdownStep{{Name: "HTTP server", Stop: server.ShutdownWithContext}},
+			api.ShutdownStep{Name: "storage", Stop: func(context.Context) error { return store.Close() }},
+		)
+	}()
+
+	time.Sleep(200 * time.Millisecond)
+	assert.False(t, store.closed.Load(), "storage is closed while a request is using it")
+
+	close(store.release)
+	require.NoError(t, <-done)
+	assert.Equal(t, http.StatusOK, <-status)
+	assert.True(t, store.closed.Load())
+	assert.False(t, store.readsClosed.Load())
+}
+
+// TestShutdownDrainsServersConcurrently tests that servers drain together, each
+// with the whole shutdown timeout, before resources close
+func TestShutdownDrainsServersConcurrently(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Each server finishes draining only once the other has started
+	httpStarted, grpcStarted := make(chan struct{}), make(chan struct{})
+	drain := func(started, other chan struct{}) func(context.Context) error {
+		return func(ctx context.Context) error {
+			close(started)
+			select {
+			case <-other:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	var closedAfterDrain bool
+	err := api.GracefulShutdown(ctx, time.Second,
+		[]api.ShutdownStep{
+			{Name: "HTTP server", Stop: drain(httpStarted, grpcStarted)},
+			{Name: "gRPC server", Stop: drain(grpcStarted, httpStarted)},
+		},
+		api.ShutdownStep{Name: "storage", Stop: func(context.Context) error {
+			closedAfterDrain = true
+			return nil
+		}},
+	)
+	require.NoError(t, err)
+	assert.True(t, closedAfterDrain)
+
+	// Failures are reported by step, and don't stop later steps
+	var closed bool
+	err = api.GracefulShutdown(ctx, time.Second,
+		[]api.ShutdownStep{{Name: "gRPC server", Stop: func(context.Context) error { return fmt.Errorf("stuck") }}},
+		api.ShutdownStep{Name: "storage", Stop: func(context.Context) error {
+			closed = true
+			return nil
+		}},
+	)
+	assert.ErrorContains(t, err, "gRPC server: stuck")
+	assert.True(t, closed)
+}
+
+// TestShutdownClosesResourcesAfterDrain tests that resources close with a
+// deadline of their own once the servers have drained, and stay open when a
+// server runs out of time with requests still running
+func TestShutdownClosesResourcesAfterDrain(t *testing.T) {
+	// A drain that uses the whole timeout leaves the closes a live context
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var closeErr error
+	err := api.GracefulShutdown(ctx, time.Second,
+		[]api.ShutdownStep{{Name: "HTTP server", Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}}},
+		api.ShutdownStep{Name: "storage", Stop: func(ctx context.Context) error {
+			closeErr = ctx.Err()
+			return nil
+		}},
+	)
+	require.NoError(t, err)
+	assert.NoError(t, closeErr, "close ran with the drain's expired context")
+
+	// A drain that times out skips the closes
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	var closed bool
+	err = api.GracefulShutdown(ctx, time.Second,
+		[]api.ShutdownStep{{Name: "HTTP server", Stop: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}}},
+		api.ShutdownStep{Name: "storage", Stop: func(context.Context) error {
+			closed = true
+			return nil
+		}},
+	)
+	assert.ErrorIs(t, err, api.ErrDrainTimeout)
+	assert.ErrorContains(t, err, "HTTP server: context deadline exceeded")
+	assert.ErrorContains(t, err, "leaving open: storage")
+	assert.False(t, closed, "storage is closed while requests may still be using it")
+}
+
+// TestIPFilter tests that restricted routes admit only allowed client
+// addresses, believing X-Forwarded-For only from trusted proxies
+func TestIPFilter(t *testing.T) {
+	serve := func(config middleware.IPFilterConfig) string {
+		filter, err := middleware.NewIPFilter(config)
+		require.NoError(t, err)
+		app := fiber.New()
+		app.Get("/admin", middleware.RestrictIPs(filter), func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		go app.Listener(ln)
+		t.Cleanup(func() { app.Shutdown() })
+		return "http://" + ln.Addr().String() + "/admin"
+	}
+	status := func(url, forwardedFor string) int {
+		req, err := http.NewRequest("GET", url, nil)
+		require.NoError(t, err)
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Without trusted proxies the peer is the client, whatever the header says
+	internal := serve(middleware.IPFilterConfig{Allow: []string{"10.0.0.0/8"}})
+	assert.Equal(t, http.StatusForbidden, status(internal, ""))
+	assert.Equal(t, http.StatusForbidden, status(internal, "10.1.2.3"), "a client can't spoof its address")
+
+	local := serve(middleware.IPFilterConfig{Allow: []string{"127.0.0.1"}})
+	assert.Equal(t, http.StatusOK, status(local, ""))
+
+	// Behind a trusted proxy the client is the last untrusted hop
+	proxied := serve(middleware.IPFilterConfig{
+		Allow:          []string{"10.0.0.0/8"},
+		Deny:           []string{"10.9.0.0/16"},
+		TrustedProxies: []string{"127.0.0.0/8", "172.16.0.0/12"},
+	})
+	assert.Equal(t, http.StatusOK, status(proxied, "10.1.2.3"))
+	assert.Equal(t, http.StatusOK, status(proxied, "10.1.2.3, 172.16.0.5"), "trusted hops are skipped")
+	assert.Equal(t, http.StatusForbidden, status(proxied, "10.1.2.3, 203.0.113.9"), "a spoofed hop before the real client is ignored")
+	assert.Equal(t, http.StatusForbidden, status(proxied, "10.9.1.1"), "deny wins over allow")
+	assert.Equal(t, http.StatusForbidden, status(proxied, ""), "the proxy itself is not allowed")
+	assert.Equal(t, http.StatusForbidden, status(proxied, "not-an-address"))
+
+	_, err := middleware.NewIPFilter(middleware.IPFilterConfig{Allow: []string{"10.0.0.0/33"}})
+	assert.Error(t, err)
+}
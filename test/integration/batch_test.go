@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/batch"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/validation"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBatchValidatePersistsQualityScores tests that a validate job writes quality scores and validation
+// metadata back to storage for the cases its filter selects
+func TestBatchValidatePersistsQualityScores(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	newCase := func(id, court, jurisdiction string) *models.Case {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Court = court
+		c.Jurisdiction = jurisdiction
+		c.QualityScore = 0.3
+		require.NoError(t, store.SaveCase(ctx, c))
+		return c
+	}
+	newCase("complete", "UK Supreme Court", "United Kingdom")
+	newCase("no-court", "", "United Kingdom")
+	newCase("elsewhere", "High Court of Australia", "Australia")
+
+	processor := batch.NewBatchProcessor(1)
+	defer processor.Shutdown()
+	processor.SetStorage(store)
+	processor.SetValidationPipeline(validation.NewPipeline(
+		observability.NewLogger("error", "json"), searchMetrics,
+		&validation.PipelineConfig{Stages: []validation.ValidationStage{validation.StageStructural}},
+	))
+
+	require.NoError(t, processor.SubmitJob(batch.BatchJob{
+		ID:    "validate-uk",
+		Type:  batch.BatchJobTypeValidate,
+		Input: map[string]interface{}{"filter": storage.CaseFilter{Jurisdiction: "United Kingdom"}},
+	}))
+
+	var result batch.BatchResult
+	select {
+	case result = <-processor.GetResults():
+	case <-time.After(5 * time.Second):
+		t.Fatal("validate job did not complete")
+	}
+	require.NoError(t, result.Error)
+	assert.Equal(t, batch.BatchJobStatusCompleted, result.Status)
+
+	output := result.Output.(map[string]interface{})
+	assert.Equal(t, 2, output["total"])
+	assert.Equal(t, 1, output["valid"])
+	assert.Equal(t, 1, output["invalid"])
+	assert.Equal(t, map[string]int{"REQUIRED_FIELD_MISSING": 1}, output["reasons"])
+
+	got, err := store.GetCase(ctx, "complete")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, got.QualityScore)
+	assert.Equal(t, true, got.Metadata["validation_valid"])
+
+	got, err = store.GetCase(ctx, "no-court")
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, got.QualityScore)
+	assert.Equal(t, false, got.Metadata["validation_valid"])
+	assert.Equal(t, []string{"court: Court is required"}, got.Metadata["validation_errors"])
+
+	got, err = store.GetCase(ctx, "elsewhere")
+	require.NoError(t, err)
+	assert.Equal(t, 0.3, got.QualityScore, "cases outside the filter are not validated")
+}
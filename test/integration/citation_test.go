@@ -0,0 +1,273 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/citation"
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTreatmentDetectorNearestPhrase tests that each cited case takes the treatment phrase closest to it
+func TestTreatmentDetectorNearestPhrase(t *testing.T) {
+	detector := citation.NewTreatmentDetector()
+
+	matches := detector.Detect("We distinguish Brown v Green [2016] UKSC 9 but overrule Smith v Jones [2015] UKSC 3. " +
+		"We decline to overrule Black v White [2017] UKSC 1. Counsel cited Doe v Roe [2018] UKSC 4.")
+
+	treatments := make(map[string]string)
+	for _, m := range matches {
+		treatments[m.Citation.RawCitation] = m.Treatment
+	}
+	assert.Equal(t, map[string]string{
+		"[2016] UKSC 9": citation.TreatmentDistinguished,
+		"[2015] UKSC 3": citation.TreatmentOverruled,
+	}, treatments)
+}
+
+// TestApplyTreatmentsOverrulesStoredCase tests that a judgment overruling a stored case flips its status
+func TestApplyTreatmentsOverrulesStoredCase(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "treatments.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	newCase := func(id, number string, year int, text string) *models.Case {
+		decided := time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC)
+		c := models.NewCase()
+		c.ID = id
+		c.CaseNumber = number
+		c.CaseName = "Case " + id
+		c.Court = "UK Supreme Court"
+		c.Jurisdiction = "United Kingdom"
+		c.DecisionDate = &decided
+		c.Status = models.CaseStatusClosed
+		c.FullText = text
+		return c
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			overruled := newCase("smith", "[2015] UKSC 3", 2015, "")
+			distinguished := newCase("brown", "[2016] UKSC 9", 2016, "")
+			affirmed := newCase("black", "[2017] UKSC 1", 2017, "")
+			for _, c := range []*models.Case{overruled, distinguished, affirmed} {
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			judgment := newCase("jones", "[2023] UKSC 20", 2023,
+				"The appellant relies on Smith v Jones [2015] UKSC 3.\n\n"+
+					"For these reasons we overrule Smith v Jones [2015] UKSC 3. "+
+					"Brown v Green [2016] UKSC 9 is distinguishable on its facts. "+
+					"We decline to overrule Black v White [2017] UKSC 1.")
+			require.NoError(t, store.SaveCase(ctx, judgment))
+
+			svc := citation.NewService(store)
+			updated, err := svc.ApplyTreatments(ctx, judgment)
+			require.NoError(t, err)
+			assert.Len(t, updated, 2)
+
+			got, err := store.GetCase(ctx, "smith")
+			require.NoError(t, err)
+			assert.Equal(t, models.CaseStatusOverruled, got.Status)
+
+			got, err = store.GetCase(ctx, "brown")
+			require.NoError(t, err)
+			assert.Equal(t, models.CaseStatusClosed, got.Status, "distinguishing leaves a case good law")
+
+			got, err = store.GetCase(ctx, "black")
+			require.NoError(t, err)
+			assert.Equal(t, models.CaseStatusClosed, got.Status)
+
+			// SQL backends don't persist case metadata
+			if name == "memory" {
+				got, err = store.GetCase(ctx, "smith")
+				require.NoError(t, err)
+				assert.Equal(t, "jones", got.Metadata["overruled_by"])
+				assert.Equal(t, "[2023] UKSC 20", got.Metadata["overruled_by_citation"])
+
+				got, err = store.GetCase(ctx, "brown")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"jones"}, got.Metadata["distinguished_by"])
+
+				updated, err = svc.ApplyTreatments(ctx, judgment)
+				require.NoError(t, err)
+				assert.Empty(t, updated, "treatments already recorded are not reapplied")
+			}
+		})
+	}
+}
+
+// TestApplyTreatmentsIgnoresLaterCases tests that a judgment cannot overrule a case decided after it
+func TestApplyTreatmentsIgnoresLaterCases(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	decided := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := models.NewCase()
+	later.ID = "later"
+	later.CaseNumber = "[2021] UKSC 7"
+	later.DecisionDate = &decided
+	require.NoError(t, store.SaveCase(ctx, later))
+
+	earlier := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	judgment := models.NewCase()
+	judgment.ID = "judgment"
+	judgment.DecisionDate = &earlier
+	judgment.FullText = "We overrule [2021] UKSC 7."
+
+	updated, err := citation.NewService(store).ApplyTreatments(ctx, judgment)
+	require.NoError(t, err)
+	assert.Empty(t, updated)
+}
+
+// TestCitationServiceUsesInjectedClock tests that extracted citations are stamped from the service's clock
+func TestCitationServiceUsesInjectedClock(t *testing.T) {
+	fixed := clock.NewFixed(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	judgment := models.NewCase()
+	judgment.ID = "judgment"
+	judgment.FullText = "As held in Smith v Jones [2015] UKSC 3, the duty is owed."
+
+	svc := citation.NewService(storage.NewMemoryStorage())
+	svc.SetClock(fixed)
+
+	citations, err := svc.ExtractAndStoreCitations(context.Background(), judgment)
+	require.NoError(t, err)
+	require.NotEmpty(t, citations)
+	for _, c := range citations {
+		assert.Equal(t, fixed.Now(), c.ExtractedAt)
+	}
+}
+
+// TestLinkAppealToStoredFirstInstance tests that an appeal is linked to the stored decision it was heard from
+func TestLinkAppealToStoredFirstInstance(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "appeals.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	newCase := func(id, number, court string, year int, text string) *models.Case {
+		decided := time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC)
+		c := models.NewCase()
+		c.ID = id
+		c.CaseNumber = number
+		c.CaseName = "Case " + id
+		c.Court = court
+		c.Jurisdiction = "United Kingdom"
+		c.DecisionDate = &decided
+		c.FullText = text
+		return c
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			trial := newCase("trial", "[2019] EWHC 1234", "High Court of Justice", 2019, "")
+			trial.Docket = "HQ17X01234"
+			unrelated := newCase("unrelated", "[2018] EWHC 77", "High Court of Justice", 2018, "")
+			for _, c := range []*models.Case{trial, unrelated} {
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			appeal := newCase("appeal", "[2020] EWCA Civ 56", "Court of Appeal (England & Wales)", 2020,
+				"The judge relied on [2018] EWHC 77.\n\n"+
+					"ON APPEAL FROM the High Court of Justice, Queen's Bench Division, Claim No. HQ17X01234. "+
+					"This is an appeal against the order of the judge below.")
+			require.NoError(t, store.SaveCase(ctx, appeal))
+
+			svc := citation.NewService(store)
+			lower, err := svc.LinkAppeal(ctx, appeal)
+			require.NoError(t, err)
+			require.NotNil(t, lower)
+			assert.Equal(t, "trial", lower.ID)
+
+			got, err := store.GetCase(ctx, "appeal")
+			require.NoError(t, err)
+			assert.Equal(t, "trial", got.LowerCourtCaseID)
+
+			got, err = store.GetCase(ctx, "trial")
+			require.NoError(t, err)
+			assert.Equal(t, "appeal", got.AppealedToCaseID)
+
+			got, err = store.GetCase(ctx, "unrelated")
+			require.NoError(t, err)
+			assert.Empty(t, got.AppealedToCaseID, "cases cited outside the appeal language are not linked")
+		})
+	}
+
+	t.Run("citation", func(t *testing.T) {
+		store := storage.NewMemoryStorage()
+		trial := newCase("trial", "[2019] EWHC 1234", "High Court of Justice", 2019, "")
+		require.NoError(t, store.SaveCase(ctx, trial))
+
+		// A later decision, or one from a higher court, can't be the decision below
+		later := newCase("later", "[2021] EWHC 9", "High Court of Justice", 2021, "")
+		supreme := newCase("supreme", "[2018] UKSC 4", "UK Supreme Court", 2018, "")
+		for _, c := range []*models.Case{later, supreme} {
+			require.NoError(t, store.SaveCase(ctx, c))
+		}
+
+		appeal := newCase("appeal", "[2020] EWCA Civ 56", "Court of Appeal (England & Wales)", 2020,
+			"On appeal from [2021] EWHC 9, [2018] UKSC 4 and [2019] EWHC 1234.")
+		require.NoError(t, store.SaveCase(ctx, appeal))
+
+		lower, err := citation.NewService(store).LinkAppeal(ctx, appeal)
+		require.NoError(t, err)
+		require.NotNil(t, lower)
+		assert.Equal(t, "trial", lower.ID)
+		assert.Equal(t, "trial", appeal.LowerCourtCaseID)
+	})
+}
+
+// TestNormalizeCaseCitationsDedupes tests that variant forms of the same embedded citation are
+// stored as one normalized entry
+func TestNormalizeCaseCitationsDedupes(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "citing"
+	c.CaseName = "Citing v Case"
+	c.Citations = []models.Citation{
+		*models.NewCitation("[2023] UKSC 15", models.CitationFormatNeutral),
+		*models.NewCitation("[2023]  UKSC  015", models.CitationFormatNeutral),
+		*models.NewCitation(" [2023] UKSC 15 ", models.CitationFormatNeutral),
+		*models.NewCitation("[2019] EWCA Civ 7", models.CitationFormatNeutral),
+	}
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	svc := citation.NewService(store)
+	require.NoError(t, svc.NormalizeCaseCitations(ctx, c))
+
+	stored, err := store.GetCase(ctx, "citing")
+	require.NoError(t, err)
+	require.Len(t, stored.Citations, 2)
+	assert.Equal(t, "[2023] UKSC 15", stored.Citations[0].NormalizedCitation)
+	assert.Equal(t, "[2023] UKSC 15", stored.Citations[0].RawCitation, "the first form seen is kept")
+	assert.Equal(t, "[2019] EWCA Civ 7", stored.Citations[1].RawCitation)
+	for _, cit := range stored.Citations {
+		assert.True(t, cit.IsNormalized, cit.RawCitation)
+	}
+
+	// Normalizing again changes nothing
+	require.NoError(t, svc.NormalizeCaseCitations(ctx, stored))
+	assert.Len(t, stored.Citations, 2)
+}
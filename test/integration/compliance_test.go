@@ -0,0 +1,205 @@
+package integration
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/compliance"
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// citedCase builds a case with the fields citations are generated from
+func citedCase(name, number, court, jurisdiction string, decided time.Time) *models.Case {
+	c := models.NewCase()
+	c.CaseName = name
+	c.CaseNumber = number
+	c.Court = court
+	c.Jurisdiction = jurisdiction
+	c.DecisionDate = &decided
+	return c
+}
+
+// TestCitationStyleFollowsJurisdiction tests that citations use each jurisdiction's customary style
+func TestCitationStyleFollowsJurisdiction(t *testing.T) {
+	ah := compliance.NewAttributionHandler(compliance.NewPolicyManager())
+
+	tests := []struct {
+		name     string
+		c        *models.Case
+		style    compliance.CitationStyle
+		expected string
+	}{
+		{
+			name: "UK neutral citation in OSCOLA",
+			c: citedCase("R (Miller) v Secretary of State for Exiting the European Union", "[2017] UKSC 5",
+				"UK Supreme Court", "United Kingdom", time.Date(2017, 1, 24, 0, 0, 0, 0, time.UTC)),
+			style:    compliance.CitationStyleOSCOLA,
+			expected: "R (Miller) v Secretary of State for Exiting the European Union [2017] UKSC 5",
+		},
+		{
+			name: "Australian neutral citation in AGLC",
+			c: citedCase("Mabo v Queensland (No 2)", "[1992] HCA 23",
+				"High Court of Australia", "Australia", time.Date(1992, 6, 3, 0, 0, 0, 0, time.UTC)),
+			style:    compliance.CitationStyleAGLC,
+			expected: "Mabo v Queensland (No 2) [1992] HCA 23",
+		},
+		{
+			name: "US reported citation in Bluebook",
+			c: citedCase("Riley v. California", "573 U.S. 373",
+				"Supreme Court of the United States", "United States", time.Date(2014, 6, 25, 0, 0, 0, 0, time.UTC)),
+			style:    compliance.CitationStyleBluebook,
+			expected: "Riley v. California, 573 U.S. 373 (2014)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.style, ah.CitationStyleFor(tt.c.Jurisdiction))
+			assert.Equal(t, tt.expected, ah.GenerateCitation(tt.c, compliance.CitationStyleAuto))
+			assert.Equal(t, tt.expected, ah.GenerateCitation(tt.c, ""))
+		})
+	}
+
+	assert.Equal(t, compliance.CitationStylePlain, ah.CitationStyleFor("Canada"))
+}
+
+// TestCitationStyleOverride tests that an explicit or per-jurisdiction style overrides the default
+func TestCitationStyleOverride(t *testing.T) {
+	ah := compliance.NewAttributionHandler(compliance.NewPolicyManager())
+	miller := citedCase("R (Miller) v Secretary of State for Exiting the European Union", "[2017] UKSC 5",
+		"UK Supreme Court", "United Kingdom", time.Date(2017, 1, 24, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t,
+		"R (Miller) v Secretary of State for Exiting the European Union, [2017] UKSC 5 (UK Supreme Court Jan. 24, 2017)",
+		ah.GenerateCitation(miller, compliance.CitationStyleBluebook))
+
+	jordan := citedCase("R v Jordan", "2016 SCC 27", "Supreme Court of Canada", "Canada",
+		time.Date(2016, 7, 8, 0, 0, 0, 0, time.UTC))
+	ah.SetJurisdictionStyle("Canada", compliance.CitationStyleOSCOLA)
+	assert.Equal(t, "R v Jordan 2016 SCC 27", ah.GenerateCitation(jordan, compliance.CitationStyleAuto))
+
+	// Unreported judgments cite the court and date, with source attribution
+	unreported := citedCase("Smith v Jones", "", "Federal Court of Australia", "Australia",
+		time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC))
+	unreported.SourceDatabase = "AustLII"
+	assert.Equal(t,
+		"Smith v Jones (Federal Court of Australia, 15 June 2023). Data sourced from AustLII (Australasian Legal Information Institute) (https://www.austlii.edu.au)",
+		ah.GenerateCitation(unreported, compliance.CitationStyleAuto))
+}
+
+// TestExportCitationStyle tests that exports cite cases in their jurisdiction's style unless overridden
+func TestExportCitationStyle(t *testing.T) {
+	cases := []*models.Case{
+		citedCase("Mabo v Queensland (No 2)", "[1992] HCA 23",
+			"High Court of Australia", "Australia", time.Date(1992, 6, 3, 0, 0, 0, 0, time.UTC)),
+		citedCase("Riley v. California", "573 U.S. 373",
+			"Supreme Court of the United States", "United States", time.Date(2014, 6, 25, 0, 0, 0, 0, time.UTC)),
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, export.NewExporter(export.FormatMarkdown, &buf).Export(cases))
+	assert.Contains(t, buf.String(), "| **Citation** | Mabo v Queensland (No 2) [1992] HCA 23 |")
+	assert.Contains(t, buf.String(), "| **Citation** | Riley v. California, 573 U.S. 373 (2014) |")
+
+	buf.Reset()
+	exporter := export.NewExporter(export.FormatPlainText, &buf)
+	exporter.SetCitationStyle(compliance.CitationStyleAGLC)
+	require.NoError(t, exporter.Export(cases[1:]))
+	assert.Contains(t, buf.String(), "Citation: Riley v California 573 U.S. 373\n")
+}
+
+// TestOSCOLAAndAGLCCitations tests OSCOLA and AGLC citations against known-correct examples
+func TestOSCOLAAndAGLCCitations(t *testing.T) {
+	ah := compliance.NewAttributionHandler(compliance.NewPolicyManager())
+
+	donoghue := citedCase("Donoghue v. Stevenson", "[1932] AC 562", "House of Lords", "United Kingdom",
+		time.Date(1932, 5, 26, 0, 0, 0, 0, time.UTC))
+	donoghue.CourtID = "UKHL"
+
+	unreportedVic := citedCase("Smith vs Jones", "", "Supreme Court of Victoria", "Australia",
+		time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC))
+	unreportedVic.Judges = []string{"Bell J"}
+
+	tests := []struct {
+		name      string
+		c         *models.Case
+		style     compliance.CitationStyle
+		paragraph string
+		expected  string
+	}{
+		{
+			name: "OSCOLA neutral citation with pinpoint",
+			c: citedCase("R (Miller) v Secretary of State for Exiting the European Union", "[2017] UKSC 5",
+				"UK Supreme Court", "United Kingdom", time.Date(2017, 1, 24, 0, 0, 0, 0, time.UTC)),
+			style:     compliance.CitationStyleOSCOLA,
+			paragraph: "45",
+			expected:  "R (Miller) v Secretary of State for Exiting the European Union [2017] UKSC 5 [45]",
+		},
+		{
+			name: "OSCOLA neutral citation with pinpoint range",
+			c: citedCase("Owens v Owens", "[2017] EWCA Civ 182", "Court of Appeal",
+				"United Kingdom", time.Date(2017, 3, 24, 0, 0, 0, 0, time.UTC)),
+			style:     compliance.CitationStyleOSCOLA,
+			paragraph: "paras 35-37",
+			expected:  "Owens v Owens [2017] EWCA Civ 182 [35]–[37]",
+		},
+		{
+			name:     "OSCOLA law report names the court",
+			c:        donoghue,
+			style:    compliance.CitationStyleOSCOLA,
+			expected: "Donoghue v Stevenson [1932] AC 562 (HL)",
+		},
+		{
+			name: "OSCOLA unreported judgment",
+			c: citedCase("R v Smith", "", "Crown Court", "United Kingdom",
+				time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC)),
+			style:     compliance.CitationStyleOSCOLA,
+			paragraph: "[12]",
+			expected:  "R v Smith (Crown Court, 3 March 2021) [12]",
+		},
+		{
+			name: "AGLC neutral citation with pinpoint",
+			c: citedCase("Mabo v Queensland (No. 2)", "[1992] HCA 23", "High Court of Australia",
+				"Australia", time.Date(1992, 6, 3, 0, 0, 0, 0, time.UTC)),
+			style:     compliance.CitationStyleAGLC,
+			paragraph: "42",
+			expected:  "Mabo v Queensland (No 2) [1992] HCA 23, [42]",
+		},
+		{
+			name: "AGLC law report",
+			c: citedCase("Australian Broadcasting Corporation v Lenah Game Meats Pty. Ltd.", "(2001) 208 CLR 199",
+				"High Court of Australia", "Australia", time.Date(2001, 11, 15, 0, 0, 0, 0, time.UTC)),
+			style:    compliance.CitationStyleAGLC,
+			expected: "Australian Broadcasting Corporation v Lenah Game Meats Pty Ltd (2001) 208 CLR 199",
+		},
+		{
+			name:      "AGLC unreported judgment names the judge",
+			c:         unreportedVic,
+			style:     compliance.CitationStyleAGLC,
+			paragraph: "20",
+			expected:  "Smith v Jones (Supreme Court of Victoria, Bell J, 15 June 2023) [20]",
+		},
+		{
+			name: "Bluebook ignores paragraph pinpoints",
+			c: citedCase("Riley v. California", "573 U.S. 373",
+				"Supreme Court of the United States", "United States", time.Date(2014, 6, 25, 0, 0, 0, 0, time.UTC)),
+			style:     compliance.CitationStyleBluebook,
+			paragraph: "12",
+			expected:  "Riley v. California, 573 U.S. 373 (2014)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ah.GeneratePinpointCitation(tt.c, tt.style, tt.paragraph))
+		})
+	}
+
+	// Without a paragraph number there is no pinpoint
+	assert.Equal(t, "Donoghue v Stevenson [1932] AC 562 (HL)",
+		ah.GeneratePinpointCitation(donoghue, compliance.CitationStyleOSCOLA, "passim"))
+}
@@ -0,0 +1,252 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gongahkia/kite/internal/api/handlers"
+	"github.com/gongahkia/kite/internal/concepts"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestConceptCooccurrenceRanking tests that the concepts sharing the most cases with a concept rank highest
+func TestConceptCooccurrenceRanking(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "cooccurrence.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+		// A wrapper without its own aggregation counts through ListCases
+		"fallback": struct{ storage.Storage }{storage.NewMemoryStorage()},
+	}
+
+	seeded := [][]string{
+		{"Mens Rea", "Actus Reus", "Causation"},
+		{"Mens Rea", "Actus Reus", "Self-Defence"},
+		{"Mens Rea", "Actus Reus", "Causation", "Actus Reus"},
+		{"Mens Rea", "Strict Liability"},
+		{"Actus Reus", "Causation", "Due Process"},
+		{"Due Process", "Equal Protection"},
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i, tags := range seeded {
+				c := models.NewCase()
+				c.ID = fmt.Sprintf("cooccur-%d", i)
+				c.CaseName = fmt.Sprintf("Case %d", i)
+				c.LegalConcepts = tags
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			// crim-01 is the taxonomy ID of Mens Rea
+			pairs, err := concepts.NewService(store).GetConceptCooccurrence(ctx, "crim-01", 3)
+			require.NoError(t, err)
+
+			// Repeated concepts on a case count once, and ties order by name
+			assert.Equal(t, []storage.ConceptPair{
+				{Concept: "Mens Rea", Related: "Actus Reus", Count: 3},
+				{Concept: "Mens Rea", Related: "Causation", Count: 2},
+				{Concept: "Mens Rea", Related: "Self-Defence", Count: 1},
+			}, pairs)
+
+			pairs, err = storage.ConceptCooccurrence(ctx, store, "Equal Protection", storage.CaseFilter{}, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []storage.ConceptPair{
+				{Concept: "Equal Protection", Related: "Due Process", Count: 1},
+			}, pairs)
+		})
+	}
+}
+
+// TestCatchwordsAreHighConfidenceConcepts tests that a concept named in the catchwords is matched with high confidence
+func TestCatchwordsAreHighConfidenceConcepts(t *testing.T) {
+	extractor := concepts.NewExtractor(concepts.NewTaxonomy())
+
+	c := models.NewCase()
+	c.FullText = "The applicant bought the building in 2010 and later found defects in its waterproofing."
+	c.Catchwords = []string{"Negligence", "pure economic loss"}
+
+	var negligence *models.ConceptMatch
+	matches := extractor.ExtractConceptsFromCase(context.Background(), c)
+	for i := range matches {
+		if matches[i].ConceptID == "tort-01" {
+			negligence = &matches[i]
+		}
+	}
+	require.NotNil(t, negligence, "catchwords should add the concept they name")
+	assert.GreaterOrEqual(t, negligence.Confidence, 0.95)
+
+	c.Catchwords = nil
+	for _, m := range extractor.ExtractConceptsFromCase(context.Background(), c) {
+		assert.NotEqual(t, "tort-01", m.ConceptID)
+	}
+}
+
+// TestCasesByConceptAcrossBackends tests that the cases tagged with a concept are paged by ID on every backend
+func TestCasesByConceptAcrossBackends(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "concept_cases.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+		// A wrapper without its own query filters the listed cases
+		"fallback": struct{ storage.Storage }{storage.NewMemoryStorage()},
+	}
+
+	seeded := map[string][]string{
+		"tagged-c": {"Mens Rea", "Causation"},
+		"tagged-a": {"Mens Rea"},
+		"other":    {"Actus Reus"},
+		"tagged-b": {"Actus Reus", "Mens Rea", "Mens Rea"},
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for id, tags := range seeded {
+				c := models.NewCase()
+				c.ID = id
+				c.CaseName = "Case " + id
+				c.LegalConcepts = tags
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(cases []*models.Case) []string {
+				out := make([]string, len(cases))
+				for i, c := range cases {
+					out[i] = c.ID
+				}
+				return out
+			}
+
+			cases, total, err := storage.CasesByConcept(ctx, store, "Mens Rea", storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), total)
+			assert.Equal(t, []string{"tagged-a", "tagged-b", "tagged-c"}, ids(cases))
+
+			cases, total, err = storage.CasesByConcept(ctx, store, "Mens Rea", storage.CaseFilter{Limit: 1, Offset: 1})
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), total)
+			assert.Equal(t, []string{"tagged-b"}, ids(cases))
+
+			cases, total, err = storage.CasesByConcept(ctx, store, "Strict Liability", storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Zero(t, total)
+			assert.Empty(t, cases)
+		})
+	}
+}
+
+// TestConceptBrowsingHandlers tests that concepts are served with their hierarchy and tagged cases
+func TestConceptBrowsingHandlers(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	for id, tags := range map[string][]string{
+		"intent-1": {"Mens Rea", "Actus Reus"},
+		"intent-2": {"Mens Rea"},
+		"act-1":    {"Actus Reus"},
+	} {
+		c := models.NewCase()
+		c.ID = id
+		c.LegalConcepts = tags
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	service := concepts.NewService(store)
+	service.GetTaxonomy().AddConcept(&models.LegalConcept{
+		ID:            "crim-01-a",
+		Name:          "Oblique Intention",
+		ParentConcept: "crim-01",
+		Keywords:      []string{"oblique intention", "virtual certainty"},
+	})
+
+	h := handlers.NewConceptHandler(service, nil)
+	app := fiber.New()
+	app.Get("/concepts", h.ListConcepts)
+	app.Get("/concepts/:id", h.GetConcept)
+	app.Get("/concepts/:id/cases", h.GetConceptCases)
+
+	get := func(path string, body interface{}) int {
+		resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+		require.NoError(t, err)
+		if resp.StatusCode == fiber.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(body))
+		}
+		return resp.StatusCode
+	}
+	conceptIDs := func(list []*models.LegalConcept) []string {
+		out := make([]string, len(list))
+		for i, c := range list {
+			out[i] = c.ID
+		}
+		return out
+	}
+
+	var list struct {
+		Data  []*models.LegalConcept `json:"data"`
+		Total int                    `json:"total"`
+	}
+	require.Equal(t, fiber.StatusOK, get("/concepts", &list))
+	assert.Equal(t, len(list.Data), list.Total)
+	assert.Contains(t, conceptIDs(list.Data), "crim-01")
+	assert.Contains(t, conceptIDs(list.Data), "crim-01-a")
+
+	type conceptDetail struct {
+		Data     *models.LegalConcept   `json:"data"`
+		Parents  []*models.LegalConcept `json:"parents"`
+		Children []*models.LegalConcept `json:"children"`
+	}
+
+	var detail conceptDetail
+	require.Equal(t, fiber.StatusOK, get("/concepts/crim-01", &detail))
+	assert.Equal(t, "Mens Rea", detail.Data.Name)
+	assert.Contains(t, detail.Data.Keywords, "mens rea")
+	assert.Empty(t, detail.Parents)
+	assert.Equal(t, []string{"crim-01-a"}, conceptIDs(detail.Children))
+
+	var child conceptDetail
+	require.Equal(t, fiber.StatusOK, get("/concepts/crim-01-a", &child))
+	assert.Equal(t, []string{"oblique intention", "virtual certainty"}, child.Data.Keywords)
+	assert.Equal(t, []string{"crim-01"}, conceptIDs(child.Parents))
+	assert.Empty(t, child.Children)
+
+	assert.Equal(t, fiber.StatusNotFound, get("/concepts/no-such-concept", &child))
+
+	var tagged struct {
+		Concept string         `json:"concept"`
+		Data    []*models.Case `json:"data"`
+		Total   int64          `json:"total"`
+	}
+	require.Equal(t, fiber.StatusOK, get("/concepts/crim-01/cases", &tagged))
+	assert.Equal(t, int64(2), tagged.Total)
+	require.Len(t, tagged.Data, 2)
+	assert.Equal(t, "intent-1", tagged.Data[0].ID)
+	assert.Equal(t, "intent-2", tagged.Data[1].ID)
+
+	var page struct {
+		Data  []*models.Case `json:"data"`
+		Total int64          `json:"total"`
+	}
+	require.Equal(t, fiber.StatusOK, get("/concepts/crim-01/cases?limit=1&offset=1", &page))
+	assert.Equal(t, int64(2), page.Total)
+	require.Len(t, page.Data, 1)
+	assert.Equal(t, "intent-2", page.Data[0].ID)
+}
@@ -0,0 +1,385 @@
+package integration
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// TestParquetExportRoundTrip tests that a streamed Parquet export reads back with the expected schema and values
+func TestParquetExportRoundTrip(t *testing.T) {
+	miller := citedCase("R (Miller) v Secretary of State for Exiting the European Union", "[2017] UKSC 5",
+		"UK Supreme Court", "United Kingdom", time.Date(2017, 1, 24, 0, 0, 0, 0, time.UTC))
+	miller.ID = "uksc-2017-5"
+	miller.CourtLevel = models.CourtLevelSupreme
+	miller.Judges = []string{"Lord Neuberger", "Lady Hale"}
+	miller.LegalConcepts = []string{"prerogative", "parliamentary sovereignty"}
+	miller.FullText = "Full judgment text"
+	miller.QualityScore = 0.9
+
+	undated := models.NewCase()
+	undated.ID = "undated"
+	undated.CaseName = "Smith v Jones"
+
+	cases := make(chan *models.Case, 2)
+	cases <- miller
+	cases <- undated
+	close(cases)
+
+	var buf bytes.Buffer
+	options := export.DefaultExportOptions()
+	options.IncludeFullText = false
+	streamer, err := export.NewStreamExporter(export.FormatParquet, &buf, options)
+	require.NoError(t, err)
+	require.NoError(t, streamer.StreamCases(context.Background(), cases))
+
+	pr, err := reader.NewParquetReader(buffer.NewBufferFileFromBytes(buf.Bytes()), new(export.ParquetCase), 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+
+	var columns []string
+	for _, el := range pr.Footer.Schema[1:] {
+		columns = append(columns, el.Name)
+	}
+	assert.Contains(t, columns, "case_name")
+	assert.Contains(t, columns, "decision_date")
+	assert.Contains(t, columns, "judges")
+
+	require.Equal(t, int64(2), pr.GetNumRows())
+	rows := make([]export.ParquetCase, 2)
+	require.NoError(t, pr.Read(&rows))
+
+	assert.Equal(t, "uksc-2017-5", rows[0].ID)
+	assert.Equal(t, miller.CaseName, rows[0].CaseName)
+	assert.Equal(t, "[2017] UKSC 5", rows[0].CaseNumber)
+	assert.Equal(t, int32(models.CourtLevelSupreme), rows[0].CourtLevel)
+	assert.Equal(t, []string{"Lord Neuberger", "Lady Hale"}, rows[0].Judges)
+	assert.Equal(t, []string{"prerogative", "parliamentary sovereignty"}, rows[0].LegalConcepts)
+	assert.Equal(t, 0.9, rows[0].QualityScore)
+	assert.Empty(t, rows[0].FullText, "full text is left out when IncludeFullText is false")
+
+	// Dates are days since the Unix epoch
+	require.NotNil(t, rows[0].DecisionDate)
+	assert.Equal(t, "2017-01-24", time.Unix(int64(*rows[0].DecisionDate)*86400, 0).UTC().Format("2006-01-02"))
+	require.NotNil(t, rows[0].ScrapedAt)
+	assert.Equal(t, miller.ScrapedAt.UnixMilli(), *rows[0].ScrapedAt)
+
+	assert.Equal(t, "undated", rows[1].ID)
+	assert.Nil(t, rows[1].DecisionDate)
+	assert.Empty(t, rows[1].Judges)
+}
+
+// TestExportExcludesAndRedactsFields tests that excluded fields are absent and redacted fields masked in JSON and CSV
+func TestExportExcludesAndRedactsFields(t *testing.T) {
+	family := citedCase("A v B", "[2022] EWFC 1", "Family Court", "United Kingdom", time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC))
+	family.ID = "ewfc-2022-1"
+	family.CourtType = models.CourtTypeFamily
+	family.Appellant = "Alice Smith"
+	family.Parties = []models.Party{{Name: "Alice Smith", Role: "applicant", Lawyers: []string{"Jane Counsel"}}}
+	family.Judges = []string{"Mostyn J"}
+	family.Summary = "Financial remedies"
+	family.FullText = "Full judgment text"
+
+	commercial := citedCase("Acme Ltd v Widget plc", "[2022] EWHC 2", "High Court", "United Kingdom", time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC))
+	commercial.ID = "ewhc-2022-2"
+	commercial.CourtType = models.CourtTypeCommercial
+	commercial.Appellant = "Acme Ltd"
+	commercial.Summary = "Breach of warranty"
+
+	options := export.DefaultExportOptions()
+	options.ExcludeFields = []string{"full_text", "summary"}
+	options.Redactions = []export.RedactionRule{{
+		Fields:     []string{"appellant", "parties", "case_name"},
+		CourtTypes: []models.CourtType{models.CourtTypeFamily},
+	}}
+	require.NoError(t, options.Validate())
+	cases := []*models.Case{family, commercial}
+
+	var jsonOut bytes.Buffer
+	exporter := export.NewExporter(export.FormatJSON, &jsonOut)
+	require.NoError(t, exporter.SetOptions(options))
+	require.NoError(t, exporter.Export(cases))
+
+	var objects []map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &objects))
+	require.Len(t, objects, 2)
+	for _, object := range objects {
+		assert.NotContains(t, object, "full_text")
+		assert.NotContains(t, object, "summary")
+	}
+	assert.Equal(t, export.DefaultRedactionMask, objects[0]["case_name"])
+	assert.Equal(t, export.DefaultRedactionMask, objects[0]["appellant"])
+	party := objects[0]["parties"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, export.DefaultRedactionMask, party["name"])
+	assert.Equal(t, []interface{}{export.DefaultRedactionMask}, party["lawyers"])
+	assert.Equal(t, "applicant", party["role"])
+	assert.Equal(t, []interface{}{"Mostyn J"}, objects[0]["judges"])
+	assert.Equal(t, "Acme Ltd v Widget plc", objects[1]["case_name"], "the rule only covers family cases")
+	assert.Equal(t, "Acme Ltd", objects[1]["appellant"])
+
+	var csvOut bytes.Buffer
+	exporter = export.NewExporter(export.FormatCSV, &csvOut)
+	require.NoError(t, exporter.SetOptions(options))
+	require.NoError(t, exporter.Export(cases))
+
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.NotContains(t, records[0], "Summary")
+	assert.Contains(t, records[0], "CaseName")
+	assert.Equal(t, []string{"ewfc-2022-1", export.DefaultRedactionMask, "[2022] EWFC 1"}, records[1][:3])
+	assert.Equal(t, []string{"ewhc-2022-2", "Acme Ltd v Widget plc", "[2022] EWHC 2"}, records[2][:3])
+	assert.NotContains(t, csvOut.String(), "Financial remedies")
+
+	// Exporting works on copies
+	assert.Equal(t, "A v B", family.CaseName)
+	assert.Equal(t, "Alice Smith", family.Parties[0].Name)
+	assert.Equal(t, "Full judgment text", family.FullText)
+
+	options.ExcludeFields = []string{"court_level"}
+	assert.Error(t, options.Validate())
+}
+
+// TestExportRejectsUnknownRedactionFields tests that a mistyped redaction
+// field fails the export instead of writing the field unredacted
+func TestExportRejectsUnknownRedactionFields(t *testing.T) {
+	c := citedCase("A v B", "[2022] EWFC 1", "Family Court", "United Kingdom", time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC))
+	c.ID = "ewfc-2022-1"
+	c.Appellant = "Alice Smith"
+	cases := []*models.Case{c}
+
+	options := export.DefaultExportOptions()
+	options.Redactions = []export.RedactionRule{{Fields: []string{"party", "appellant"}}}
+
+	var out bytes.Buffer
+	_, err := export.NewExporterWithOptions(export.FormatJSON, &out, options)
+	assert.Error(t, err)
+
+	exporter := export.NewExporter(export.FormatJSON, &out)
+	assert.Error(t, exporter.SetOptions(options))
+
+	assert.Error(t, export.ExportWithProgress(context.Background(), cases, export.FormatJSON, &out, options, nil))
+
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveCase(context.Background(), c))
+	_, err = export.StreamFromStorage(context.Background(), store, "", storage.CaseFilter{}, export.FormatJSONLines, &out, options)
+	assert.Error(t, err)
+
+	assert.Zero(t, out.Len(), "nothing is exported")
+}
+
+// TestJSONExportHonorsPretty tests that JSON exports are minified when Pretty is off
+func TestJSONExportHonorsPretty(t *testing.T) {
+	c := citedCase("Donoghue v Stevenson", "[1932] UKHL 100", "House of Lords", "United Kingdom",
+		time.Date(1932, 5, 26, 0, 0, 0, 0, time.UTC))
+	c.ID = "ukhl-1932-100"
+	cases := []*models.Case{c}
+
+	var pretty bytes.Buffer
+	require.NoError(t, export.NewExporter(export.FormatJSON, &pretty).Export(cases))
+	assert.Contains(t, pretty.String(), "\n  {\n    \"id\": \"ukhl-1932-100\"")
+
+	options := export.DefaultExportOptions()
+	options.Pretty = false
+
+	var minified bytes.Buffer
+	exporter := export.NewExporter(export.FormatJSON, &minified)
+	require.NoError(t, exporter.SetOptions(options))
+	require.NoError(t, exporter.Export(cases))
+
+	out := bytes.TrimSuffix(minified.Bytes(), []byte("\n"))
+	assert.NotContains(t, string(out), "\n")
+	assert.NotContains(t, string(out), "  ")
+	assert.Less(t, minified.Len(), pretty.Len())
+
+	var objects []map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &objects))
+	require.Len(t, objects, 1)
+	assert.Equal(t, "ukhl-1932-100", objects[0]["id"])
+
+	// Options reach exports with progress tracking too
+	var progress bytes.Buffer
+	require.NoError(t, export.ExportWithProgress(context.Background(), cases, export.FormatJSON, &progress, options, nil))
+	assert.Equal(t, minified.String(), progress.String())
+}
+
+// TestExporterWithOptions tests that each export option takes effect
+func TestExporterWithOptions(t *testing.T) {
+	c := citedCase("Carlill v Carbolic Smoke Ball Co", "[1892] EWCA Civ 1", "Court of Appeal", "United Kingdom",
+		time.Date(1892, 12, 7, 0, 0, 0, 0, time.UTC))
+	c.ID = "ewca-1892-1"
+	c.Summary = "Unilateral contract"
+	c.FullText = "The plaintiff used the smoke ball as directed."
+	cases := []*models.Case{c}
+
+	exportWith := func(t *testing.T, format export.ExportFormat, configure func(o *export.ExportOptions)) string {
+		options := export.DefaultExportOptions()
+		configure(options)
+
+		var out bytes.Buffer
+		exporter, err := export.NewExporterWithOptions(format, &out, options)
+		require.NoError(t, err)
+		require.NoError(t, exporter.Export(cases))
+		require.NoError(t, exporter.Close())
+		return out.String()
+	}
+
+	t.Run("compress", func(t *testing.T) {
+		out := exportWith(t, export.FormatJSON, func(o *export.ExportOptions) { o.Compress = true })
+
+		zr, err := gzip.NewReader(strings.NewReader(out))
+		require.NoError(t, err)
+		data, err := io.ReadAll(zr)
+		require.NoError(t, err)
+
+		var objects []map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &objects))
+		assert.Equal(t, "ewca-1892-1", objects[0]["id"])
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		out := exportWith(t, export.FormatCSV, func(o *export.ExportOptions) {
+			o.Fields = []string{"case_name", "id"}
+		})
+		records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"CaseName", "ID"}, {"Carlill v Carbolic Smoke Ball Co", "ewca-1892-1"}}, records)
+
+		out = exportWith(t, export.FormatJSON, func(o *export.ExportOptions) {
+			o.Fields = []string{"id", "summary"}
+		})
+		var objects []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(out), &objects))
+		assert.Equal(t, map[string]interface{}{"id": "ewca-1892-1", "summary": "Unilateral contract"}, objects[0])
+
+		options := export.DefaultExportOptions()
+		options.Fields = []string{"holding"}
+		var buf bytes.Buffer
+		exporter, err := export.NewExporterWithOptions(export.FormatCSV, &buf, options)
+		require.NoError(t, err)
+		assert.Error(t, exporter.Export(cases), "CSV has no holding column")
+	})
+
+	t.Run("date format", func(t *testing.T) {
+		out := exportWith(t, export.FormatCSV, func(o *export.ExportOptions) {})
+		assert.Contains(t, out, ",1892-12-07,", "CSV dates default to ISO dates")
+
+		out = exportWith(t, export.FormatCSV, func(o *export.ExportOptions) { o.DateFormat = "02/01/2006" })
+		assert.Contains(t, out, ",07/12/1892,")
+
+		out = exportWith(t, export.FormatMarkdown, func(o *export.ExportOptions) { o.DateFormat = time.RFC3339 })
+		assert.Contains(t, out, "| **Date** | 1892-12-07T00:00:00Z |")
+
+		out = exportWith(t, export.FormatPlainText, func(o *export.ExportOptions) { o.DateFormat = "2 Jan 2006" })
+		assert.Contains(t, out, "Date: 7 Dec 1892\n")
+	})
+
+	t.Run("include full text", func(t *testing.T) {
+		for _, format := range []export.ExportFormat{export.FormatJSON, export.FormatXML, export.FormatMarkdown, export.FormatPlainText} {
+			out := exportWith(t, format, func(o *export.ExportOptions) {})
+			assert.Contains(t, out, "smoke ball as directed", format)
+
+			out = exportWith(t, format, func(o *export.ExportOptions) { o.IncludeFullText = false })
+			assert.NotContains(t, out, "smoke ball as directed", format)
+		}
+	})
+
+	t.Run("pretty", func(t *testing.T) {
+		out := exportWith(t, export.FormatXML, func(o *export.ExportOptions) {})
+		assert.Contains(t, out, "\n  <case>")
+
+		out = exportWith(t, export.FormatXML, func(o *export.ExportOptions) { o.Pretty = false })
+		assert.Contains(t, out, "<cases><case>")
+	})
+}
+
+// TestExportDateFormat tests that a custom date format is validated and used in CSV output
+func TestExportDateFormat(t *testing.T) {
+	c := citedCase("Hadley v Baxendale", "[1854] EWHC J70", "Court of Exchequer", "United Kingdom",
+		time.Date(1854, 2, 23, 0, 0, 0, 0, time.UTC))
+	c.ID = "ewhc-1854-j70"
+
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveCase(context.Background(), c))
+
+	// Layouts that don't write whole dates fail the export before anything is written
+	options := export.DefaultExportOptions()
+	for _, layout := range []string{"YYYY-MM-DD", "01/2006", "Monday"} {
+		options.DateFormat = layout
+
+		var out bytes.Buffer
+		_, err := export.NewExporterWithOptions(export.FormatCSV, &out, options)
+		assert.Error(t, err, layout)
+		assert.Error(t, export.ExportWithProgress(context.Background(), []*models.Case{c}, export.FormatMarkdown, &out, options, nil), layout)
+		_, err = export.StreamFromStorage(context.Background(), store, "", storage.CaseFilter{}, export.FormatCSV, &out, options)
+		assert.Error(t, err, layout)
+		assert.Zero(t, out.Len(), layout)
+	}
+
+	options.DateFormat = "2 January 2006"
+
+	var out bytes.Buffer
+	exporter, err := export.NewExporterWithOptions(export.FormatCSV, &out, options)
+	require.NoError(t, err)
+	require.NoError(t, exporter.Export([]*models.Case{c}))
+
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "DecisionDate", records[0][5])
+	assert.Equal(t, "23 February 1854", records[1][5])
+}
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) { return 0, io.ErrClosedPipe }
+
+// TestStreamFromStorage tests that cases are streamed from storage to the
+// export a row at a time, and that a failed write stops the export
+func TestStreamFromStorage(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	for i, name := range []string{"Donoghue v Stevenson", "Caparo v Dickman", "Hedley Byrne v Heller"} {
+		c := citedCase(name, fmt.Sprintf("[%d] UKHL %d", 1930+i, i+1), "House of Lords", "United Kingdom",
+			time.Date(1930+i, 1, 1, 0, 0, 0, 0, time.UTC))
+		c.ID = fmt.Sprintf("ukhl-%d", i)
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	var out bytes.Buffer
+	count, err := export.StreamFromStorage(ctx, store, "", storage.CaseFilter{}, export.FormatCSV, &out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	records, err := csv.NewReader(&out).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, records, 4)
+
+	// A search query streams only its matches
+	out.Reset()
+	count, err = export.StreamFromStorage(ctx, store, "caparo", storage.CaseFilter{}, export.FormatJSONLines, &out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Contains(t, out.String(), "Caparo v Dickman")
+
+	_, err = export.StreamFromStorage(ctx, store, "", storage.CaseFilter{}, export.FormatJSONLines, failingWriter{}, nil)
+	assert.ErrorIs(t, err, io.ErrClosedPipe)
+
+	_, err = export.StreamFromStorage(ctx, store, "", storage.CaseFilter{}, export.FormatXML, &out, nil)
+	assert.Error(t, err)
+}
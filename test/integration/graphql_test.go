@@ -0,0 +1,285 @@
+package integration
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/export"
+	"github.com/gongahkia/kite/internal/graphql"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/worker"
+	"github.com/gongahkia/kite/pkg/models"
+	gql "github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedCitationGraph stores A->B, B->C, C->D and E->A (citing -> cited)
+func seedCitationGraph(t *testing.T, ctx context.Context, store storage.Storage) {
+	for _, id := range []string{"A", "B", "C", "D", "E"} {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	links := [][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}, {"E", "A"}}
+	for _, l := range links {
+		cit := models.NewCitation("["+l[1]+"]", models.CitationFormatNeutral)
+		cit.CitingCaseID = l[0]
+		cit.CaseID = l[1]
+		require.NoError(t, store.SaveCitation(ctx, cit))
+	}
+}
+
+// TestCitationNetworkResolver tests node and edge counts of the citationNetwork query
+func TestCitationNetworkResolver(t *testing.T) {
+	ctx := context.Background()
+
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+	seedCitationGraph(t, ctx, store)
+
+	schema, err := graphql.BuildSchema(graphql.NewResolver(store))
+	require.NoError(t, err)
+
+	query := `query($depth: Int) {
+		citationNetwork(caseId: "A", depth: $depth) {
+			nodes { caseId caseName }
+			edges { fromCaseId toCaseId }
+		}
+	}`
+
+	tests := []struct {
+		depth int
+		nodes int
+		edges int
+	}{
+		{depth: 1, nodes: 3, edges: 2}, // A, B, E
+		{depth: 2, nodes: 4, edges: 3}, // + C
+	}
+
+	for _, tt := range tests {
+		result := graphql.ExecuteQuery(schema, query, map[string]interface{}{"depth": tt.depth}, ctx)
+		require.Empty(t, result.Errors)
+
+		network := result.Data.(map[string]interface{})["citationNetwork"].(map[string]interface{})
+		assert.Len(t, network["nodes"], tt.nodes, "nodes at depth %d", tt.depth)
+		assert.Len(t, network["edges"], tt.edges, "edges at depth %d", tt.depth)
+	}
+
+	// Depth beyond the cap is rejected
+	result := graphql.ExecuteQuery(schema, query, map[string]interface{}{"depth": graphql.DefaultMaxCitationNetworkDepth + 1}, ctx)
+	assert.NotEmpty(t, result.Errors)
+
+	// A lower configured cap rejects depths the default allows
+	resolver := graphql.NewResolver(store)
+	resolver.SetMaxCitationNetworkDepth(1)
+	schema, err = graphql.BuildSchema(resolver)
+	require.NoError(t, err)
+
+	result = graphql.ExecuteQuery(schema, query, map[string]interface{}{"depth": 2}, ctx)
+	assert.NotEmpty(t, result.Errors)
+}
+
+// TestListCitationsByCaseIDs tests that citations are matched on either side of the link
+func TestListCitationsByCaseIDs(t *testing.T) {
+	ctx := context.Background()
+
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+	seedCitationGraph(t, ctx, store)
+
+	citations, err := store.ListCitations(ctx, storage.CitationFilter{CaseIDs: []string{"A"}})
+	require.NoError(t, err)
+	assert.Len(t, citations, 2) // A->B, E->A
+
+	citations, err = store.ListCitations(ctx, storage.CitationFilter{CaseIDs: []string{"B", "D"}})
+	require.NoError(t, err)
+	assert.Len(t, citations, 3) // A->B, B->C, C->D
+}
+
+// TestSearchCasesResolverResultWindow tests that oversized GraphQL pages are rejected
+func TestSearchCasesResolverResultWindow(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	resolver := graphql.NewResolver(store)
+	resolver.SetResultWindow(storage.ResultWindow{MaxLimit: 100, MaxOffset: 500})
+	schema, err := graphql.BuildSchema(resolver)
+	require.NoError(t, err)
+
+	queries := []string{
+		`{ searchCases(query: "contract", limit: 1000000) { total } }`,
+		`{ searchCases(query: "contract", offset: 5000000) { total } }`,
+		`{ casesByJurisdiction(jurisdiction: "UK", limit: 1000000) { id } }`,
+	}
+	for _, q := range queries {
+		result := graphql.ExecuteQuery(schema, q, nil, ctx)
+		require.NotEmpty(t, result.Errors, q)
+		assert.Contains(t, result.Errors[0].Message, "exceeds maximum", q)
+	}
+}
+
+// TestExportCasesResolver tests inline CSV exports and that larger exports
+// become jobs, read by the worker as the API would have read them
+func TestExportCasesResolver(t *testing.T) {
+	ctx := context.Background()
+	base := storage.NewMemoryStorage()
+	defer base.Close()
+
+	for i, jurisdiction := range []string{"UK", "UK", "UK", "Singapore"} {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("export-%d", i)
+		c.CaseName = fmt.Sprintf("Negligence claim %d", i)
+		c.Jurisdiction = jurisdiction
+		require.NoError(t, base.SaveCase(ctx, c))
+	}
+	private := models.NewCase()
+	private.ID = "export-private"
+	private.CaseName = "Negligence claim of another tenant"
+	private.Jurisdiction = "UK"
+	private.TenantID = "tenant-b"
+	require.NoError(t, base.SaveCase(ctx, private))
+
+	// The API's storage serves tenant-a the shared UK cases only
+	store := storage.NewJurisdictionStorage(storage.NewTenantStorage(base), []string{"UK"})
+	ctx = storage.WithTenant(ctx, "tenant-a")
+
+	resolver := graphql.NewResolver(store)
+	schema, err := graphql.BuildSchema(resolver)
+	require.NoError(t, err)
+
+	query := `query($format: String!) {
+		exportCases(query: "negligence", format: $format, options: { fields: ["id", "case_name"] }) {
+			status count payload jobId
+		}
+	}`
+
+	result := graphql.ExecuteQuery(schema, query, map[string]interface{}{"format": "csv"}, ctx)
+	require.Empty(t, result.Errors)
+	exported := result.Data.(map[string]interface{})["exportCases"].(map[string]interface{})
+	assert.Equal(t, "completed", exported["status"])
+	assert.Equal(t, 3, exported["count"])
+	assert.Nil(t, exported["jobId"])
+
+	data, err := base64.StdEncoding.DecodeString(exported["payload"].(string))
+	require.NoError(t, err)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 4, "header and one row per shared UK case")
+	assert.Equal(t, []string{"ID", "CaseName"}, rows[0], "the requested columns")
+
+	result = graphql.ExecuteQuery(schema, query, map[string]interface{}{"format": "docx"}, ctx)
+	assert.NotEmpty(t, result.Errors)
+
+	invalid := `{ exportCases(format: "csv", options: { excludeFields: ["party"] }) { status } }`
+	result = graphql.ExecuteQuery(schema, invalid, nil, ctx)
+	require.NotEmpty(t, result.Errors)
+	assert.Contains(t, result.Errors[0].Message, "unknown field party")
+
+	// Beyond the inline limit the export is rejected without a queue...
+	resolver.SetInlineExportLimit(2)
+	result = graphql.ExecuteQuery(schema, query, map[string]interface{}{"format": "csv"}, ctx)
+	require.NotEmpty(t, result.Errors)
+	assert.Contains(t, result.Errors[0].Message, "inline limit")
+
+	// ...and enqueued as a job with one
+	q := queue.NewMemoryQueue()
+	exports := export.NewJobStore(t.TempDir())
+	resolver.SetJobQueue(q, exports)
+	result = graphql.ExecuteQuery(schema, query, map[string]interface{}{"format": "csv"}, ctx)
+	require.Empty(t, result.Errors)
+	exported = result.Data.(map[string]interface{})["exportCases"].(map[string]interface{})
+	assert.Equal(t, "pending", exported["status"])
+	assert.Nil(t, exported["payload"])
+
+	status := `query($id: String!) { exportJob(id: $id) { status count downloadUrl } }`
+	vars := map[string]interface{}{"id": exported["jobId"]}
+	result = graphql.ExecuteQuery(schema, status, vars, ctx)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{"status": "pending", "count": nil, "downloadUrl": nil}, result.Data.(map[string]interface{})["exportJob"])
+
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, exported["jobId"], job.ID)
+	assert.Equal(t, queue.JobTypeExport, job.Type)
+
+	// The worker's storage is unscoped, so the job carries the scope
+	require.NoError(t, worker.NewExportJobHandler(base, exports)(context.Background(), job))
+	assert.Equal(t, 3, job.Result["count"])
+
+	result = graphql.ExecuteQuery(schema, status, vars, ctx)
+	require.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"status":      "completed",
+		"count":       3,
+		"downloadUrl": "/api/v1/exports/" + job.ID + "/download",
+	}, result.Data.(map[string]interface{})["exportJob"])
+
+	record, err := exports.Get(job.ID)
+	require.NoError(t, err)
+	f, err := exports.Open(record)
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err = csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	assert.Len(t, rows, 4, "neither the other tenant's case nor the disabled jurisdiction's")
+	assert.Equal(t, []string{"ID", "CaseName"}, rows[0])
+
+	// Other tenants don't see the job
+	result = graphql.ExecuteQuery(schema, status, vars, storage.WithTenant(context.Background(), "tenant-b"))
+	require.NotEmpty(t, result.Errors)
+	assert.Contains(t, result.Errors[0].Message, "not found")
+}
+
+// TestExecuteQueryTimeout tests that a query with a slow resolver is cancelled at the deadline
+func TestExecuteQueryTimeout(t *testing.T) {
+	cancelled := make(chan struct{})
+	schema, err := gql.NewSchema(gql.SchemaConfig{
+		Query: gql.NewObject(gql.ObjectConfig{
+			Name: "Query",
+			Fields: gql.Fields{
+				"slow": &gql.Field{
+					Type: gql.String,
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						select {
+						case <-p.Context.Done():
+							close(cancelled)
+							return nil, p.Context.Err()
+						case <-time.After(5 * time.Second):
+							return "done", nil
+						}
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	result := graphql.ExecuteQueryWithTimeout(schema, `{ slow }`, nil, context.Background(), 50*time.Millisecond)
+	elapsed := time.Since(start)
+
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, graphql.ErrExecutionTimeout.Error(), result.Errors[0].Message)
+	assert.Nil(t, result.Data, "partial results are dropped")
+	assert.Less(t, elapsed, time.Second, "the query returns at the deadline")
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the resolver's context was not cancelled")
+	}
+
+	// Without a timeout the query runs to completion
+	result = graphql.ExecuteQueryWithTimeout(schema, `{ __typename }`, nil, context.Background(), 0)
+	assert.Empty(t, result.Errors)
+}
@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	pb "github.com/gongahkia/kite/api/proto"
+	kitegrpc "github.com/gongahkia/kite/internal/grpc"
+	"github.com/gongahkia/kite/internal/grpc/services"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// unreachableStorage fails every ping, as when the database is down
+type unreachableStorage struct {
+	storage.Storage
+}
+
+func (unreachableStorage) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+// startGRPCServer starts a gRPC server on a free port and returns a client
+// connection to it accepting messages of up to 64MB
+func startGRPCServer(t *testing.T, config *kitegrpc.ServerConfig) *grpc.ClientConn {
+	config.Port = 0
+	config.Logger = observability.NewLogger("error", "json")
+	server, err := kitegrpc.NewServer(config)
+	require.NoError(t, err)
+	go server.Start()
+	t.Cleanup(server.Stop)
+
+	port := server.GetListener().Addr().(*net.TCPAddr).Port
+	conn, err := grpc.Dial(fmt.Sprintf("127.0.0.1:%d", port),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(64<<20)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestGRPCHealthService tests that the gRPC server reports its health through
+// the standard health service
+func TestGRPCHealthService(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := healthpb.NewHealthClient(startGRPCServer(t, &kitegrpc.ServerConfig{
+		Storage: storage.NewMemoryStorage(),
+		Queue:   queue.NewMemoryQueue(),
+	}))
+
+	for _, service := range []string{"", "kite.api.v1.ScraperService", "kite.api.v1.SearchService"} {
+		resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.NoError(t, err, service)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status, service)
+	}
+
+	_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "kite.api.v1.Unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Unreachable storage is reported as not serving
+	health := services.NewHealthService(unreachableStorage{storage.NewMemoryStorage()}, queue.NewMemoryQueue())
+	resp, err := health.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+// TestGRPCLargeCase tests that a case larger than gRPC's default 4MB message
+// limit is returned within the server's configured limit
+func TestGRPCLargeCase(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "long-judgment"
+	c.CaseName = "Long v Judgment"
+	c.FullText = strings.Repeat("The appellant's submissions are rejected. ", 150000) // ~6MB
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	conn := startGRPCServer(t, &kitegrpc.ServerConfig{Storage: store, Queue: queue.NewMemoryQueue()})
+	got, err := pb.NewSearchServiceClient(conn).GetCase(ctx, &pb.GetCaseRequest{Id: "long-judgment"})
+	require.NoError(t, err)
+	assert.Equal(t, len(c.FullText), len(got.FullText))
+
+	// A server configured below the case's size refuses to send it
+	conn = startGRPCServer(t, &kitegrpc.ServerConfig{Storage: store, Queue: queue.NewMemoryQueue(), MaxSendMsgSize: 4 << 20})
+	_, err = pb.NewSearchServiceClient(conn).GetCase(ctx, &pb.GetCaseRequest{Id: "long-judgment"})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
@@ -0,0 +1,664 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gongahkia/kite/internal/jurisdiction"
+	"github.com/gongahkia/kite/internal/opinions"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCourtVariantsShareCourtID tests that variant court strings map to the same CourtID
+func TestCourtVariantsShareCourtID(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	variants := []string{
+		"UK Supreme Court",
+		"UKSC",
+		"uksc",
+		"Supreme Court of the United Kingdom",
+		"The U.K. Supreme Court",
+	}
+
+	for _, court := range variants {
+		c := models.NewCase()
+		c.Court = court
+		require.NoError(t, enricher.EnrichCase(c))
+		assert.Equal(t, "UKSC", c.CourtID, court)
+		assert.Equal(t, court, c.Court, "display string must be kept")
+	}
+
+	c := models.NewCase()
+	c.Court = "Court of Nowhere"
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.Empty(t, c.CourtID)
+}
+
+// TestCourtIDFilter tests that filtering on CourtID matches every court variant, and that stored cases keep their CourtID
+func TestCourtIDFilter(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "court_id.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	enricher := jurisdiction.NewMetadataEnricher()
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i, court := range []string{"UK Supreme Court", "UKSC", "High Court (England & Wales)"} {
+				c := models.NewCase()
+				c.ID = string(rune('a' + i))
+				c.CaseName = "Case " + c.ID
+				c.Court = court
+				require.NoError(t, enricher.EnrichCase(c))
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			cases, err := store.ListCases(ctx, storage.CaseFilter{CourtID: "UKSC"})
+			require.NoError(t, err)
+			require.Len(t, cases, 2)
+			for _, c := range cases {
+				assert.Equal(t, "UKSC", c.CourtID)
+			}
+
+			// The identifier is matched exactly, as it is stored normalized
+			cases, err = store.ListCases(ctx, storage.CaseFilter{CourtID: "uksc"})
+			require.NoError(t, err)
+			assert.Empty(t, cases)
+
+			got, err := store.GetCase(ctx, "a")
+			require.NoError(t, err)
+			assert.Equal(t, "UKSC", got.CourtID)
+		})
+	}
+}
+
+// TestMultiJurisdictionFilter tests that a filter on several jurisdictions and courts returns cases from each
+func TestMultiJurisdictionFilter(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "multi.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	seed := []struct {
+		id           string
+		jurisdiction string
+		court        string
+	}{
+		{"uk-1", "UK", "UK Supreme Court"},
+		{"au-1", "Australia", "High Court of Australia"},
+		{"au-2", "Australia", "Federal Court of Australia"},
+		{"sg-1", "Singapore", "Court of Appeal"},
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, s := range seed {
+				c := models.NewCase()
+				c.ID = s.id
+				c.CaseName = "Case " + s.id
+				c.Jurisdiction = s.jurisdiction
+				c.Court = s.court
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(filter storage.CaseFilter) []string {
+				cases, err := store.ListCases(ctx, filter)
+				require.NoError(t, err)
+				ids := make([]string, 0, len(cases))
+				for _, c := range cases {
+					ids = append(ids, c.ID)
+				}
+				return ids
+			}
+
+			assert.ElementsMatch(t, []string{"uk-1", "au-1", "au-2"},
+				ids(storage.CaseFilter{Jurisdictions: []string{"UK", "Australia"}}))
+
+			// The single-value field still works and combines with the list
+			assert.ElementsMatch(t, []string{"uk-1", "sg-1"},
+				ids(storage.CaseFilter{Jurisdiction: "UK", Jurisdictions: []string{"Singapore"}}))
+
+			assert.ElementsMatch(t, []string{"uk-1", "au-1"},
+				ids(storage.CaseFilter{Courts: []string{"UK Supreme Court", "High Court of Australia"}}))
+
+			count, err := store.CountCases(ctx, storage.CaseFilter{Jurisdictions: []string{"UK", "Australia"}})
+			require.NoError(t, err)
+			assert.Equal(t, int64(3), count)
+		})
+	}
+}
+
+// TestHoldingExtractionUKStyle tests holding extraction from Commonwealth-style judgments
+func TestHoldingExtractionUKStyle(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	headed := models.NewCase()
+	headed.Jurisdiction = "United Kingdom"
+	headed.Court = "UKSC"
+	headed.FullText = `LORD REED (with whom Lord Hodge agrees):
+
+1. This appeal concerns the scope of the duty of care owed by a public authority.
+
+Analysis
+
+2. The authorities draw a distinction between causing harm and failing to confer a benefit.
+
+Conclusion
+
+3. A public authority owes no duty of care merely because it has statutory powers which, if exercised, would have prevented the harm.
+4. I would therefore dismiss the appeal.`
+
+	require.NoError(t, enricher.EnrichCase(headed))
+	assert.Contains(t, headed.Holding, "owes no duty of care merely because it has statutory powers")
+	assert.Contains(t, headed.Holding, "I would therefore dismiss the appeal")
+	assert.NotContains(t, headed.Holding, "authorities draw a distinction")
+	assert.Equal(t, 0.7, headed.Metadata["holding_confidence"])
+	assert.Equal(t, jurisdiction.HoldingSourceHeading, headed.Metadata["holding_source"])
+
+	ratio := models.NewCase()
+	ratio.Jurisdiction = "United Kingdom"
+	ratio.FullText = `Facts
+
+The claimant slipped on a wet floor in the defendant's shop.
+
+Ratio decidendi
+
+An occupier who fails to clear a known spillage within a reasonable time breaches the common duty of care.
+
+Obiter
+
+Nothing here turns on whether warning signs were displayed.`
+
+	require.NoError(t, enricher.EnrichCase(ratio))
+	assert.Equal(t, "An occupier who fails to clear a known spillage within a reasonable time breaches the common duty of care.", ratio.Holding)
+	assert.Equal(t, 0.9, ratio.Metadata["holding_confidence"])
+
+	// Without headings the last disposal phrase wins
+	unheaded := models.NewCase()
+	unheaded.Jurisdiction = "United Kingdom"
+	unheaded.FullText = `1. For the reasons given by the judge below, the contract was not frustrated.
+
+2. The remaining grounds raise no point of principle.
+
+3. For these reasons I would hold that the exclusion clause is unenforceable and allow the appeal.`
+
+	require.NoError(t, enricher.EnrichCase(unheaded))
+	assert.Contains(t, unheaded.Holding, "the exclusion clause is unenforceable")
+	assert.Equal(t, 0.5, unheaded.Metadata["holding_confidence"])
+	assert.Equal(t, jurisdiction.HoldingSourcePhrase, unheaded.Metadata["holding_source"])
+}
+
+// TestHoldingExtractionUSStyle tests holding extraction from US-style opinions
+func TestHoldingExtractionUSStyle(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	syllabus := models.NewCase()
+	syllabus.Jurisdiction = "United States"
+	syllabus.FullText = `SYLLABUS
+
+Petitioner was convicted on evidence obtained from a warrantless search of his cell phone.
+
+Held: The police generally may not, without a warrant, search digital information on a cell phone seized from an individual who has been arrested.
+
+ROBERTS, C.J., delivered the opinion of the Court.`
+
+	require.NoError(t, enricher.EnrichCase(syllabus))
+	assert.Equal(t, "The police generally may not, without a warrant, search digital information on a cell phone seized from an individual who has been arrested.", syllabus.Holding)
+	assert.Equal(t, 0.9, syllabus.Metadata["holding_confidence"])
+
+	// Without headings the first holding phrase wins
+	opinion := models.NewCase()
+	opinion.Jurisdiction = "United States"
+	opinion.FullText = `We granted certiorari to resolve a split among the circuits.
+
+We hold that the statute of limitations begins to run when the claimant discovers the injury.
+
+The dissent suggests otherwise, but we hold that its reading ignores the text.`
+
+	require.NoError(t, enricher.EnrichCase(opinion))
+	assert.Equal(t, "We hold that the statute of limitations begins to run when the claimant discovers the injury.", opinion.Holding)
+	assert.Equal(t, jurisdiction.HoldingSourcePhrase, opinion.Metadata["holding_source"])
+
+	extractor := jurisdiction.NewHoldingExtractor()
+	assert.Equal(t, "us", extractor.StyleFor("United States").Name)
+	assert.Equal(t, "commonwealth", extractor.StyleFor("Singapore").Name)
+}
+
+// TestHoldingExtractionBestEffort tests that extraction falls back and never overwrites
+func TestHoldingExtractionBestEffort(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	closing := models.NewCase()
+	closing.Jurisdiction = "Australia"
+	closing.FullText = `The applicant sought review of the tribunal's decision.
+
+The tribunal did not err in its construction of the visa criteria, and the application must fail.`
+
+	require.NoError(t, enricher.EnrichCase(closing))
+	assert.Contains(t, closing.Holding, "the application must fail")
+	assert.Equal(t, 0.2, closing.Metadata["holding_confidence"])
+	assert.Equal(t, jurisdiction.HoldingSourcePosition, closing.Metadata["holding_source"])
+
+	empty := models.NewCase()
+	require.NoError(t, enricher.EnrichCase(empty))
+	assert.Empty(t, empty.Holding)
+	assert.NotContains(t, empty.Metadata, "holding_confidence")
+
+	preset := models.NewCase()
+	preset.Holding = "Supplied by the source"
+	preset.FullText = "Conclusion\n\nSomething else entirely."
+	require.NoError(t, enricher.EnrichCase(preset))
+	assert.Equal(t, "Supplied by the source", preset.Holding)
+}
+
+// austliiCatchwordsFixture is the cover sheet of a Federal Court judgment as published on AustLII
+const austliiCatchwordsFixture = "FEDERAL COURT OF AUSTRALIA\n\n" +
+	"Owners Corporation Strata Plan 8231 v Brisbane City Council [2019] FCA 1234\n\n" +
+	"File number:\tQUD 123 of 2018\n\n" +
+	"Judge:\tRANGIAH J\n\n" +
+	"Date of judgment:\t14 August 2019\n\n" +
+	"Catchwords:\tNEGLIGENCE – duty of care – whether council owed a duty of care to subsequent purchasers of a residential building – pure economic loss – vulnerability\n\n" +
+	"ADMINISTRATIVE LAW – judicial review – jurisdictional error – failure to consider a mandatory relevant consideration\n\n" +
+	"Legislation:\tBuilding Act 1975 (Qld) s 45\n\n" +
+	"Cases cited:\tBrookfield Multiplex Ltd v Owners Corporation Strata Plan 61288 (2014) 254 CLR 185\n\n" +
+	"REASONS FOR JUDGMENT\n\n" +
+	"1 The applicant owns the common property of a residential building in Brisbane."
+
+// TestCatchwordsExtraction tests that the catchwords block of a judgment is parsed into topics
+func TestCatchwordsExtraction(t *testing.T) {
+	extractor := jurisdiction.NewCatchwordsExtractor()
+
+	assert.Equal(t, []string{
+		"Negligence",
+		"duty of care",
+		"pure economic loss",
+		"vulnerability",
+		"Administrative Law",
+		"judicial review",
+		"jurisdictional error",
+		"failure to consider a mandatory relevant consideration",
+	}, extractor.Extract(austliiCatchwordsFixture))
+
+	// Inline catchwords end where the judgment begins
+	inline := "Catchwords: Land law; adverse possession; Limitation Ordinance\n\n" +
+		"The plaintiff claims title to the strip of land by adverse possession - a claim the defendant resists.\n"
+	assert.Equal(t, []string{"Land law", "adverse possession", "Limitation Ordinance"}, extractor.Extract(inline))
+
+	assert.Empty(t, extractor.Extract("The appeal is dismissed with costs."))
+}
+
+// TestCatchwordsEnrichment tests that enrichment stores catchwords without overwriting those from the source
+func TestCatchwordsEnrichment(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	c := models.NewCase()
+	c.Jurisdiction = "Australia"
+	c.Court = "Federal Court of Australia"
+	c.FullText = austliiCatchwordsFixture
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.Contains(t, c.Catchwords, "Negligence")
+	assert.Contains(t, c.Catchwords, "judicial review")
+
+	preset := models.NewCase()
+	preset.Catchwords = []string{"Supplied by the source"}
+	preset.FullText = austliiCatchwordsFixture
+	require.NoError(t, enricher.EnrichCase(preset))
+	assert.Equal(t, []string{"Supplied by the source"}, preset.Catchwords)
+}
+
+// commonwealthJudgment is a headed judgment in the UK style
+const commonwealthJudgment = `IN THE COURT OF APPEAL OF THE REPUBLIC OF SINGAPORE
+[2023] SGCA 15
+
+Judgment reserved.
+
+1. Introduction
+
+1 This appeal concerns a claim in negligence against a building contractor.
+
+2. Background facts
+
+2 The respondent built a condominium for the appellant management corporation.
+
+3 Cracks appeared in the external walls within two years of completion.
+
+3. Issues for determination
+
+4 The issue is whether the respondent owed a duty of care in respect of pure economic loss.
+
+4. Our decision
+
+Proximity
+
+5 The parties were in a relationship of sufficient proximity.
+
+Policy considerations
+
+6 No policy consideration negates the duty.
+
+5. Conclusion
+
+7 For these reasons, the appeal is allowed with costs.`
+
+// TestJudgmentSections tests that a headed judgment is split into its sections
+func TestJudgmentSections(t *testing.T) {
+	parser := opinions.NewSectionParser()
+	sections := parser.Parse(commonwealthJudgment, "Singapore")
+
+	require.Len(t, sections, 5)
+	assert.Equal(t, "1 This appeal concerns a claim in negligence against a building contractor.", sections[opinions.SectionIntroduction])
+	assert.Equal(t, `2 The respondent built a condominium for the appellant management corporation.
+
+3 Cracks appeared in the external walls within two years of completion.`, sections[opinions.SectionFacts])
+	assert.Equal(t, "4 The issue is whether the respondent owed a duty of care in respect of pure economic loss.", sections[opinions.SectionIssues])
+	assert.Equal(t, "7 For these reasons, the appeal is allowed with costs.", sections[opinions.SectionDisposition])
+
+	// Subheadings stay in their section
+	reasoning := sections[opinions.SectionReasoning]
+	assert.Contains(t, reasoning, "Proximity")
+	assert.Contains(t, reasoning, "5 The parties were in a relationship of sufficient proximity.")
+	assert.Contains(t, reasoning, "6 No policy consideration negates the duty.")
+	assert.NotContains(t, reasoning, "appeal is allowed")
+
+	// The cover sheet is dropped when the judgment heads its own introduction
+	for _, text := range sections {
+		assert.NotContains(t, text, "Judgment reserved")
+	}
+
+	// Without headings there are no sections
+	assert.Nil(t, parser.Parse("The appeal is dismissed.\n\nThe respondent shall have its costs.", "Singapore"))
+}
+
+// TestJudgmentSectionsUSStyle tests US headings and text before the first heading
+func TestJudgmentSectionsUSStyle(t *testing.T) {
+	parser := opinions.NewSectionParser()
+	assert.Equal(t, "us", parser.StyleFor("United States").Name)
+	assert.Equal(t, "commonwealth", parser.StyleFor("Australia").Name)
+
+	sections := parser.Parse(`JUSTICE KAGAN delivered the opinion of the Court.
+
+We granted certiorari to decide whether the Act preempts state law.
+
+I. PROCEDURAL HISTORY
+
+The District Court dismissed the complaint.
+
+II. QUESTION PRESENTED
+
+Whether the Act preempts state-law claims.
+
+III. DISCUSSION
+
+The Act contains no express preemption clause.
+
+IV. CONCLUSION
+
+The judgment of the Court of Appeals is reversed.`, "United States")
+
+	assert.Equal(t, map[string]string{
+		opinions.SectionIntroduction: "JUSTICE KAGAN delivered the opinion of the Court.\n\nWe granted certiorari to decide whether the Act preempts state law.",
+		opinions.SectionFacts:        "The District Court dismissed the complaint.",
+		opinions.SectionIssues:       "Whether the Act preempts state-law claims.",
+		opinions.SectionReasoning:    "The Act contains no express preemption clause.",
+		opinions.SectionDisposition:  "The judgment of the Court of Appeals is reversed.",
+	}, sections)
+}
+
+// TestJudgmentSectionsCustomStyle tests configured heading patterns and enrichment
+func TestJudgmentSectionsCustomStyle(t *testing.T) {
+	_, err := opinions.NewSectionStyle("broken", map[string][]string{opinions.SectionFacts: {"(facts"}})
+	require.Error(t, err)
+
+	style, err := opinions.NewSectionStyle("hong kong", map[string][]string{
+		opinions.SectionFacts:       {`the (facts|evidence)`},
+		opinions.SectionReasoning:   {`the judge's reasoning`},
+		opinions.SectionDisposition: {`disposal`},
+		"dissent":                   {`dissenting judgment.*`},
+	})
+	require.NoError(t, err)
+
+	parser := opinions.NewSectionParser()
+	parser.SetStyle("Hong Kong", style)
+
+	enricher := jurisdiction.NewMetadataEnricher()
+	enricher.SetSectionParser(parser)
+
+	c := models.NewCase()
+	c.Jurisdiction = "Hong Kong"
+	c.FullText = `A. THE EVIDENCE
+
+1. The defendant was seen leaving the premises.
+
+B. THE JUDGE'S REASONING
+
+2. The judge accepted the identification evidence.
+
+C. DISPOSAL
+
+3. The appeal is dismissed.
+
+Dissenting judgment of Ribeiro PJ:
+
+4. I would have allowed the appeal.`
+
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.Equal(t, "1. The defendant was seen leaving the premises.", c.Sections[opinions.SectionFacts])
+	assert.Equal(t, "2. The judge accepted the identification evidence.", c.Sections[opinions.SectionReasoning])
+	assert.Equal(t, "3. The appeal is dismissed.", c.Sections[opinions.SectionDisposition])
+	assert.Equal(t, "4. I would have allowed the appeal.", c.Sections["dissent"])
+	assert.NotContains(t, c.Sections, opinions.SectionIntroduction)
+
+	// Sections supplied by the source are kept
+	preset := models.NewCase()
+	preset.FullText = commonwealthJudgment
+	preset.Sections = map[string]string{"facts": "Supplied by the source"}
+	require.NoError(t, enricher.EnrichCase(preset))
+	assert.Equal(t, map[string]string{"facts": "Supplied by the source"}, preset.Sections)
+}
+
+// TestJurisdictionReportersAndLanguage tests that a US case validates citations against US reporters and gets the default language
+func TestJurisdictionReportersAndLanguage(t *testing.T) {
+	rules := jurisdiction.NewJurisdictionRules()
+
+	us := rules.GetRulesForJurisdiction("United States")
+	require.NotNil(t, us)
+	assert.Equal(t, "en", us.DefaultLanguage)
+	assert.True(t, us.IsKnownReporter("F.3d"))
+	assert.True(t, us.IsKnownReporter("s. ct."), "case, spacing and periods are ignored")
+	assert.False(t, us.IsKnownReporter("W.L.R."))
+
+	usReporter := &models.Citation{RawCitation: "550 U.S. 544 (2007)", Reporter: "U.S."}
+	assert.True(t, rules.ValidateCitation("United States", usReporter))
+	assert.True(t, usReporter.IsValid)
+
+	ukReporter := &models.Citation{RawCitation: "[1990] 2 W.L.R. 358", Reporter: "W.L.R."}
+	assert.False(t, rules.ValidateCitation("United States", ukReporter))
+	assert.False(t, ukReporter.IsValid)
+	assert.Contains(t, ukReporter.ValidationError, "W.L.R.")
+	assert.True(t, rules.ValidateCitation("United Kingdom", ukReporter))
+
+	// Neutral citations and jurisdictions without rules are accepted
+	assert.True(t, rules.ValidateCitation("United States", &models.Citation{RawCitation: "[2023] UKSC 15"}))
+	assert.True(t, rules.ValidateCitation("Atlantis", ukReporter))
+
+	// Enrichment tags cases missing a language with the jurisdiction default
+	enricher := jurisdiction.NewMetadataEnricher()
+	c := models.NewCase()
+	c.Jurisdiction = "United States"
+	c.Court = "Supreme Court of the United States"
+	c.Language = ""
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.Equal(t, "en", c.Language)
+}
+
+// hkliiHeadnoteJudgment is an HKLII-style judgment with a headnote on its cover sheet
+const hkliiHeadnoteJudgment = `HCA 1234/2019
+
+IN THE HIGH COURT OF THE
+HONG KONG SPECIAL ADMINISTRATIVE REGION
+
+Before: Hon Chan J in Court
+Date of Judgment: 12 March 2021
+
+HEADNOTE
+
+The plaintiff bank sued on a personal guarantee given by the defendant director. The court held that the guarantee was enforceable notwithstanding the defendant's claim of undue influence, as he had received independent legal advice before signing.
+
+JUDGMENT
+
+1. This is an action on a guarantee dated 3 May 2015 given by the defendant in favour of the plaintiff bank to secure the debts of a company of which he was a director.`
+
+// TestHeadnoteSummary tests that an empty summary is derived from a headnote, or else the opening paragraph
+func TestHeadnoteSummary(t *testing.T) {
+	enricher := jurisdiction.NewMetadataEnricher()
+
+	c := models.NewCase()
+	c.Jurisdiction = "Hong Kong"
+	c.Court = "Court of First Instance"
+	c.FullText = hkliiHeadnoteJudgment
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.True(t, strings.HasPrefix(c.Summary, "The plaintiff bank sued on a personal guarantee"), c.Summary)
+	assert.NotContains(t, c.Summary, "JUDGMENT")
+	assert.Equal(t, jurisdiction.SummarySourceHeadnote, c.Metadata["summary_source"])
+
+	// Without a headnote the first paragraph of the judgment is used
+	c = models.NewCase()
+	c.FullText = strings.Replace(hkliiHeadnoteJudgment, "HEADNOTE", "", 1)
+	c.FullText = c.FullText[strings.Index(c.FullText, "JUDGMENT"):]
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.True(t, strings.HasPrefix(c.Summary, "1. This is an action on a guarantee"), c.Summary)
+	assert.Equal(t, jurisdiction.SummarySourceParagraph, c.Metadata["summary_source"])
+
+	// A source summary is kept
+	c = models.NewCase()
+	c.Summary = "Guarantee enforced."
+	c.FullText = hkliiHeadnoteJudgment
+	require.NoError(t, enricher.EnrichCase(c))
+	assert.Equal(t, "Guarantee enforced.", c.Summary)
+
+	// Long headnotes are cut at a sentence within the cap
+	extractor := jurisdiction.NewHeadnoteExtractor()
+	extractor.SetMaxLength(150)
+	summary, _, ok := extractor.Extract(hkliiHeadnoteJudgment)
+	require.True(t, ok)
+	assert.Equal(t, "The plaintiff bank sued on a personal guarantee given by the defendant director.", summary)
+}
+
+// TestCourtLevelConfidence tests that inferred court levels carry how they
+// were inferred, and that enrichment records it in metadata
+func TestCourtLevelConfidence(t *testing.T) {
+	hierarchy := jurisdiction.NewCourtHierarchy()
+
+	cases := []struct {
+		court      string
+		level      models.CourtLevel
+		confidence jurisdiction.LevelConfidence
+	}{
+		{"UK Supreme Court", models.CourtLevelSupreme, jurisdiction.LevelConfidenceExact},
+		{"uk supreme court", models.CourtLevelSupreme, jurisdiction.LevelConfidenceExact},
+		{"EWCA", models.CourtLevelAppellate, jurisdiction.LevelConfidenceExact},
+		{"High Court of Justice", models.CourtLevelHigher, jurisdiction.LevelConfidenceExact},
+		{"Supreme Court of Narnia", models.CourtLevelSupreme, jurisdiction.LevelConfidencePattern},
+		{"Narnian Court of Appeal", models.CourtLevelAppellate, jurisdiction.LevelConfidencePattern},
+		{"Narnia Magistrates' Court", models.CourtLevelLocal, jurisdiction.LevelConfidencePattern},
+		{"Employment Tribunal", models.CourtLevelHigher, jurisdiction.LevelConfidenceDefault},
+	}
+	for _, tc := range cases {
+		level, confidence := hierarchy.InferCourtLevel(tc.court)
+		assert.Equal(t, tc.level, level, tc.court)
+		assert.Equal(t, tc.confidence, confidence, tc.court)
+		assert.Equal(t, tc.level, hierarchy.GetCourtLevel(tc.court), tc.court)
+	}
+
+	hierarchy.SetDefaultLevel(models.CourtLevelLocal)
+	level, confidence := hierarchy.InferCourtLevel("Employment Tribunal")
+	assert.Equal(t, models.CourtLevelLocal, level)
+	assert.Equal(t, jurisdiction.LevelConfidenceDefault, confidence)
+
+	enricher := jurisdiction.NewMetadataEnricher()
+	for court, want := range map[string]string{
+		"UKSC":                    "exact",
+		"Narnian Court of Appeal": "pattern",
+		"Employment Tribunal":     "default",
+	} {
+		c := models.NewCase()
+		c.Court = court
+		require.NoError(t, enricher.EnrichCase(c))
+		assert.Equal(t, want, c.Metadata["court_level_confidence"], court)
+	}
+}
+
+// TestPartyNormalization tests that party names are title-cased and tagged
+// as individuals, corporations or government by their names
+func TestPartyNormalization(t *testing.T) {
+	normalizer := jurisdiction.NewPartyNormalizer()
+
+	tests := []struct {
+		party    models.Party
+		wantName string
+		wantRole string
+		wantType string
+	}{
+		{models.Party{Name: "JOHN SMITH", Role: "Appellant"}, "John Smith", "appellant", jurisdiction.PartyTypeIndividual},
+		{models.Party{Name: "mary  o'brien-mcdonald", Role: "respondents"}, "Mary O'Brien-McDonald", "respondent", jurisdiction.PartyTypeIndividual},
+		{models.Party{Name: "John R Smith", Role: "plaintiff"}, "John R Smith", "plaintiff", jurisdiction.PartyTypeIndividual},
+		{models.Party{Name: "ACME WIDGETS PTY LTD", Role: "defendant"}, "Acme Widgets Pty Ltd", "defendant", jurisdiction.PartyTypeCorporation},
+		{models.Party{Name: "Widget Holdings, Inc.", Role: "petitioner"}, "Widget Holdings, Inc.", "petitioner", jurisdiction.PartyTypeCorporation},
+		{models.Party{Name: "BARCLAYS BANK PLC", Role: "claimant"}, "Barclays Bank PLC", "claimant", jurisdiction.PartyTypeCorporation},
+		{models.Party{Name: "Commonwealth Bank of Australia Ltd", Role: "respondent"}, "Commonwealth Bank of Australia Ltd", "respondent", jurisdiction.PartyTypeCorporation},
+		{models.Party{Name: "REGINA", Role: "respondent"}, "Regina", "respondent", jurisdiction.PartyTypeGovernment},
+		{models.Party{Name: "R", Role: "respondent"}, "R", "respondent", jurisdiction.PartyTypeGovernment},
+		{models.Party{Name: "COMMISSIONER OF TAXATION", Role: "appellant"}, "Commissioner of Taxation", "appellant", jurisdiction.PartyTypeGovernment},
+		{models.Party{Name: "minister for immigration and border protection", Role: "respondent"}, "Minister for Immigration and Border Protection", "respondent", jurisdiction.PartyTypeGovernment},
+		{models.Party{Name: "Secretary of State for the Home Department", Role: "respondent"}, "Secretary of State for the Home Department", "respondent", jurisdiction.PartyTypeGovernment},
+
+		// Types reported by the source are kept
+		{models.Party{Name: "Crown Resorts Ltd", Role: "applicant", Type: "Government"}, "Crown Resorts Ltd", "applicant", jurisdiction.PartyTypeGovernment},
+	}
+
+	for _, tt := range tests {
+		got := normalizer.Normalize(tt.party)
+		assert.Equal(t, tt.wantName, got.Name, tt.party.Name)
+		assert.Equal(t, tt.wantRole, got.Role, tt.party.Name)
+		assert.Equal(t, tt.wantType, got.Type, tt.party.Name)
+	}
+
+	// Extra keywords and suffixes are configurable, as is title-casing
+	normalizer.AddGovernmentKeywords("Ombudsman")
+	normalizer.AddCompanySuffixes("S.p.A.")
+	normalizer.SetTitleCase(false)
+	assert.Equal(t, jurisdiction.PartyTypeGovernment, normalizer.DetectType("Commonwealth Ombudsman"))
+	assert.Equal(t, jurisdiction.PartyTypeCorporation, normalizer.DetectType("Ferrari S.p.A."))
+	assert.Equal(t, "JOHN SMITH", normalizer.Normalize(models.Party{Name: "JOHN SMITH"}).Name)
+
+	// Enrichment normalizes a case's parties in place
+	c := models.NewCase()
+	c.Court = "High Court of Australia"
+	c.Parties = []models.Party{
+		{Name: "JONES", Role: "Appellants"},
+		{Name: "WESTPAC BANKING CORPORATION", Role: "Respondents"},
+	}
+	require.NoError(t, jurisdiction.NewMetadataEnricher().EnrichCase(c))
+	assert.Equal(t, []models.Party{
+		{Name: "Jones", Role: "appellant", Type: jurisdiction.PartyTypeIndividual},
+		{Name: "Westpac Banking Corporation", Role: "respondent", Type: jurisdiction.PartyTypeCorporation},
+	}, c.Parties)
+}
@@ -0,0 +1,240 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInspectMemoryQueue tests that memory queue status counts queued, running and dead-lettered jobs
+func TestInspectMemoryQueue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	for i := 0; i < 4; i++ {
+		job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"case_id": fmt.Sprintf("case-%d", i)})
+		job.CreatedAt = time.Now().Add(-time.Duration(i) * time.Minute)
+		require.NoError(t, q.Enqueue(ctx, job))
+	}
+
+	// One job is claimed by a worker, another fails permanently
+	_, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	failed, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NoError(t, q.Nack(ctx, failed.ID, false))
+
+	statuses, err := q.Inspect(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+
+	status := statuses[0]
+	assert.Equal(t, "memory", status.Backend)
+	assert.Equal(t, int64(2), status.Depth)
+	assert.Equal(t, int64(1), status.InFlight)
+	assert.Equal(t, int64(1), status.DeadLetter)
+	assert.Greater(t, status.OldestMessageAge, time.Duration(0))
+}
+
+// mockRedisStreams answers the stream commands used by queue inspection
+type mockRedisStreams struct {
+	lengths map[string]int64
+	pending int64
+	oldest  string
+}
+
+func (m *mockRedisStreams) XLen(ctx context.Context, stream string) *redis.IntCmd {
+	return redis.NewIntResult(m.lengths[stream], nil)
+}
+
+func (m *mockRedisStreams) XInfoGroups(ctx context.Context, stream string) *redis.XInfoGroupsCmd {
+	cmd := redis.NewXInfoGroupsCmd(ctx, stream)
+	cmd.SetVal([]redis.XInfoGroup{
+		{Name: "other-workers", Pending: 7},
+		{Name: "kite-workers", Pending: m.pending},
+	})
+	return cmd
+}
+
+func (m *mockRedisStreams) XRangeN(ctx context.Context, stream, start, stop string, count int64) *redis.XMessageSliceCmd {
+	if m.oldest == "" {
+		return redis.NewXMessageSliceCmdResult(nil, nil)
+	}
+	return redis.NewXMessageSliceCmdResult([]redis.XMessage{{ID: m.oldest}}, nil)
+}
+
+// TestInspectRedisQueue tests that Redis queue status reads XLEN, the group's pending count and the oldest entry ID
+func TestInspectRedisQueue(t *testing.T) {
+	ctx := context.Background()
+
+	enqueued := time.Now().Add(-10 * time.Minute)
+	client := &mockRedisStreams{
+		lengths: map[string]int64{"kite:jobs": 42, "kite:jobs:dlq": 3},
+		pending: 5,
+		oldest:  fmt.Sprintf("%d-0", enqueued.UnixMilli()),
+	}
+
+	statuses, err := queue.InspectRedisStreams(ctx, client, "kite:jobs", "kite-workers", "kite:jobs:dlq")
+	require.NoError(t, err)
+	require.Len(t, statuses, 1)
+
+	status := statuses[0]
+	assert.Equal(t, "redis", status.Backend)
+	assert.Equal(t, "kite:jobs", status.Name)
+	assert.Equal(t, int64(37), status.Depth, "pending entries are in flight, not waiting")
+	assert.Equal(t, int64(5), status.InFlight)
+	assert.Equal(t, int64(3), status.DeadLetter)
+	assert.InDelta(t, (10 * time.Minute).Seconds(), status.OldestMessageAge.Seconds(), 5)
+
+	// An empty stream has no oldest message
+	statuses, err = queue.InspectRedisStreams(ctx, &mockRedisStreams{}, "kite:jobs", "kite-workers", "")
+	require.NoError(t, err)
+	assert.Zero(t, statuses[0].Depth)
+	assert.Zero(t, statuses[0].OldestMessageAge)
+}
+
+// TestRetryPolicySchedule tests that exponential retry delays double from the base delay up to the cap
+func TestRetryPolicySchedule(t *testing.T) {
+	policy := queue.RetryPolicy{
+		Strategy:  queue.RetryExponential,
+		BaseDelay: time.Second,
+		MaxDelay:  10 * time.Second,
+	}
+	require.NoError(t, policy.Validate())
+
+	expected := []time.Duration{1, 2, 4, 8, 10, 10}
+	for i, want := range expected {
+		assert.Equal(t, want*time.Second, policy.Delay(i+1), "attempt %d", i+1)
+	}
+
+	// Jitter varies each delay within its fraction of the exponential delay
+	policy.Jitter = 0.25
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(3)
+		assert.GreaterOrEqual(t, delay, 3*time.Second)
+		assert.LessOrEqual(t, delay, 5*time.Second)
+	}
+
+	fixed := queue.RetryPolicy{Strategy: queue.RetryFixed, BaseDelay: 2 * time.Second}
+	assert.Equal(t, 2*time.Second, fixed.Delay(5))
+	assert.Zero(t, queue.RetryPolicy{Strategy: queue.RetryImmediate}.Delay(5))
+
+	assert.Error(t, queue.RetryPolicy{Strategy: "linear"}.Validate())
+	assert.Error(t, queue.RetryPolicy{Strategy: queue.RetryFixed, Jitter: 1.5}.Validate())
+}
+
+// TestRetryQueueDelaysRequeue tests that a failed job is only dequeued again once its retry delay has passed
+func TestRetryQueueDelaysRequeue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := queue.NewRetryQueue(queue.NewMemoryQueue(), queue.RetryPolicy{
+		Strategy:  queue.RetryExponential,
+		BaseDelay: 100 * time.Millisecond,
+	})
+	defer q.Close()
+
+	require.NoError(t, q.Enqueue(ctx, queue.NewJob(queue.JobTypeScrape, nil)))
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	failedAt := time.Now()
+	job.MarkFailed(fmt.Errorf("database unavailable"))
+	require.NoError(t, q.Nack(ctx, job.ID, job.ShouldRetry()))
+
+	// The job waits out its delay in the queue, not in the process
+	depth, err := q.GetDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, depth, "job is requeued at once, scheduled for its retry")
+
+	retried, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.GreaterOrEqual(t, time.Since(failedAt), 100*time.Millisecond)
+
+	// The second failure waits twice as long
+	failedAt = time.Now()
+	retried.MarkFailed(fmt.Errorf("database unavailable"))
+	require.NoError(t, q.Nack(ctx, retried.ID, retried.ShouldRetry()))
+
+	retried, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(failedAt), 200*time.Millisecond)
+}
+
+// TestRetryQueueKeepsScheduleInQueue tests that a job waiting out its retry
+// delay is kept by the wrapped queue, so it survives the process that failed
+// it, and that due jobs are dequeued ahead of it
+func TestRetryQueueKeepsScheduleInQueue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	policy := queue.RetryPolicy{Strategy: queue.RetryFixed, BaseDelay: 200 * time.Millisecond}
+	backend := queue.NewMemoryQueue()
+	defer backend.Close()
+
+	q := queue.NewRetryQueue(backend, policy)
+	require.NoError(t, q.Enqueue(ctx, queue.NewJob(queue.JobTypeScrape, nil)))
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	failedAt := time.Now()
+	job.MarkFailed(fmt.Errorf("database unavailable"))
+	require.NoError(t, q.Nack(ctx, job.ID, job.ShouldRetry()))
+
+	// A new process reading the same queue finds the retry, and runs a due
+	// job first
+	restarted := queue.NewRetryQueue(backend, policy)
+	due := queue.NewJob(queue.JobTypeScrape, nil)
+	require.NoError(t, restarted.Enqueue(ctx, due))
+
+	next, err := restarted.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, due.ID, next.ID)
+
+	retried, err := restarted.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.Equal(t, 2, retried.Attempts, "waiting in the queue doesn't count as an attempt")
+	assert.GreaterOrEqual(t, time.Since(failedAt), 200*time.Millisecond)
+}
+
+// TestMemoryQueueHoldsScheduledJobs tests that a job scheduled for later stays
+// in the queue untouched until it is due, while due jobs are dequeued around it
+func TestMemoryQueueHoldsScheduledJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	scheduled := queue.NewJob(queue.JobTypeScrape, nil)
+	scheduled.Schedule(time.Now().Add(200 * time.Millisecond))
+	require.NoError(t, q.Enqueue(ctx, scheduled))
+
+	early, earlyCancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer earlyCancel()
+	_, err := q.Dequeue(early)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "a job isn't dequeued before it is due")
+	assert.Zero(t, scheduled.Attempts)
+
+	due := queue.NewJob(queue.JobTypeScrape, nil)
+	require.NoError(t, q.Enqueue(ctx, due))
+	next, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, due.ID, next.ID)
+
+	next, err = q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, scheduled.ID, next.ID)
+	assert.Equal(t, 1, next.Attempts)
+	assert.False(t, time.Now().Before(*scheduled.ScheduledAt))
+}
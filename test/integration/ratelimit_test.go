@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/scraper"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSharedRateLimitAcrossInstances tests that two limiters sharing a Redis key stay within one budget
+func TestSharedRateLimitAcrossInstances(t *testing.T) {
+	addr := os.Getenv("KITE_TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("Integration test requires KITE_TEST_REDIS_ADDR")
+	}
+
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	require.NoError(t, client.Ping(context.Background()).Err())
+
+	key := fmt.Sprintf("%stest-%d", scraper.SharedRateLimitKeyPrefix, time.Now().UnixNano())
+	defer client.Del(context.Background(), key)
+
+	// 240 requests per minute is 4 per second with a burst of 5
+	bucket := scraper.NewRedisTokenBucket(client)
+	limiters := []*scraper.RateLimiter{scraper.NewRateLimiter(240), scraper.NewRateLimiter(240)}
+	for _, l := range limiters {
+		l.SetShared(bucket, key, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var allowed int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, l := range limiters {
+		wg.Add(1)
+		go func(l *scraper.RateLimiter) {
+			defer wg.Done()
+			for l.Wait(ctx) == nil {
+				atomic.AddInt64(&allowed, 1)
+			}
+		}(l)
+	}
+	wg.Wait()
+
+	// Each limiter alone would allow about 13 requests in 2s; together they get one budget
+	budget := 5 + 4*time.Since(start).Seconds()
+	assert.LessOrEqual(t, float64(allowed), budget+1)
+	assert.GreaterOrEqual(t, float64(allowed), budget-2)
+}
+
+// failingBucket is a shared bucket whose backend is down
+type failingBucket struct {
+	takes int64
+}
+
+func (b *failingBucket) Take(ctx context.Context, key string, ratePerSecond float64, burst int) (time.Duration, error) {
+	atomic.AddInt64(&b.takes, 1)
+	return 0, errors.New("connection refused")
+}
+
+// TestSharedRateLimitFallsBackToLocal tests that an unreachable shared bucket falls back to the local limit
+func TestSharedRateLimitFallsBackToLocal(t *testing.T) {
+	bucket := &failingBucket{}
+	recorder := &warnRecorder{}
+
+	// 60 requests per minute allows a burst of 2, then one per second
+	limiter := scraper.NewRateLimiter(60)
+	limiter.SetShared(bucket, "kite:ratelimit:test", recorder)
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if limiter.Allow() {
+			allowed++
+		}
+	}
+	assert.Equal(t, 2, allowed)
+	assert.Equal(t, int64(10), bucket.takes)
+
+	// The outage is logged once, not per request
+	require.Len(t, recorder.lines, 1)
+	assert.Contains(t, recorder.lines[0], "falling back to local limit")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Error(t, limiter.Wait(ctx))
+}
@@ -2,15 +2,30 @@ package integration
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unicode/utf8"
 
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/compliance"
 	"github.com/gongahkia/kite/internal/config"
 	"github.com/gongahkia/kite/internal/observability"
 	"github.com/gongahkia/kite/internal/queue"
 	"github.com/gongahkia/kite/internal/scraper"
 	"github.com/gongahkia/kite/internal/storage"
 	"github.com/gongahkia/kite/internal/worker"
+	"github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -83,7 +98,7 @@ func TestJobQueueWorkerFlow(t *testing.T) {
 	workerCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	go w.Run(workerCtx)
+	go w.Run(workerCtx, workerCtx)
 
 	// Wait for job to be processed
 	time.Sleep(2 * time.Second)
@@ -233,3 +248,1637 @@ func TestMetricsCollection(t *testing.T) {
 	// Verify metrics are properly recorded
 	// Check Prometheus endpoint for expected values
 }
+
+// flakyScraper simulates a scraper whose upstream site is down until marked healthy
+type flakyScraper struct {
+	healthy bool
+	calls   int
+}
+
+func (f *flakyScraper) scrape(job *queue.Job) error {
+	f.calls++
+	if !f.healthy {
+		return assert.AnError
+	}
+	return nil
+}
+
+// runOnce dequeues a single job, runs it through the scraper and acks or nacks it
+func runOnce(ctx context.Context, t *testing.T, q *queue.MemoryQueue, s *flakyScraper) *queue.Job {
+	job, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+
+	if err := s.scrape(job); err != nil {
+		require.NoError(t, q.Nack(ctx, job.ID, false))
+	} else {
+		require.NoError(t, q.Ack(ctx, job.ID))
+	}
+	return job
+}
+
+// TestReplayFailedScrapeJobs tests that failed scrape jobs are replayed and succeed once the source recovers
+func TestReplayFailedScrapeJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	s := &flakyScraper{}
+
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction": "UK",
+		"query":        "negligence",
+	})
+	require.NoError(t, q.Enqueue(ctx, job))
+
+	// Site is down, job ends up in the DLQ
+	runOnce(ctx, t, q, s)
+	require.Equal(t, 1, q.GetDLQ().GetSize())
+
+	since := time.Now().Add(-time.Minute)
+	filter := queue.ReplayFilter{
+		Status:     queue.JobStatusFailed,
+		Type:       queue.JobTypeScrape,
+		Since:      &since,
+		MaxReplays: 1,
+	}
+
+	// Site recovers, replay re-enqueues the job with its original inputs
+	s.healthy = true
+	result, err := queue.ReplayJobs(ctx, q, q.GetDLQ(), filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{job.ID}, result.Replayed)
+	assert.Equal(t, 0, q.GetDLQ().GetSize())
+
+	replayed := runOnce(ctx, t, q, s)
+	assert.Equal(t, job.ID, replayed.ID)
+	assert.Equal(t, "negligence", replayed.Payload["query"])
+	assert.Equal(t, queue.JobStatusCompleted, replayed.Status)
+	assert.Equal(t, 1, replayed.ReplayCount)
+	assert.Equal(t, 2, s.calls)
+}
+
+// TestReplayRespectsMaxReplays tests that jobs at the replay limit stay in the DLQ
+func TestReplayRespectsMaxReplays(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	s := &flakyScraper{}
+
+	require.NoError(t, q.Enqueue(ctx, queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})))
+	runOnce(ctx, t, q, s)
+
+	filter := queue.ReplayFilter{Status: queue.JobStatusFailed, MaxReplays: 1}
+
+	result, err := queue.ReplayJobs(ctx, q, q.GetDLQ(), filter)
+	require.NoError(t, err)
+	require.Len(t, result.Replayed, 1)
+
+	// Still failing, goes back to the DLQ and must not be replayed again
+	runOnce(ctx, t, q, s)
+
+	result, err = queue.ReplayJobs(ctx, q, q.GetDLQ(), filter)
+	require.NoError(t, err)
+	assert.Empty(t, result.Replayed)
+	assert.Len(t, result.Skipped, 1)
+	assert.Equal(t, 1, q.GetDLQ().GetSize())
+}
+
+// TestHTMLDumpOnParseFailure tests that raw HTML is saved when extraction yields an invalid case
+func TestHTMLDumpOnParseFailure(t *testing.T) {
+	dir := t.TempDir()
+
+	base := scraper.NewBaseScraper("Test", "Test", "https://example.org", 60)
+	base.SetHTMLDumper(scraper.NewHTMLDumper(scraper.HTMLDumpConfig{
+		Enabled:   true,
+		Dir:       dir,
+		MaxBytes:  64,
+		Retention: time.Hour,
+	}))
+
+	// Fixture page with none of the expected selectors
+	fixture := "<html><body><div class=\"unexpected\">" + strings.Repeat("x", 200) + "</div></body></html>"
+	doc, raw, err := scraper.ReadDocument(strings.NewReader(fixture))
+	require.NoError(t, err)
+
+	c := models.NewCase()
+	c.CaseName = strings.TrimSpace(doc.Find("h1.case-title").Text())
+	require.False(t, scraper.MeetsMinimumValidity(c))
+
+	base.DumpOnParseFailure("https://example.org/cases/UKSC/2023/15.html", raw)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasPrefix(entries[0].Name(), "example.org_cases_UKSC_2023_15.html_"))
+
+	dumped, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	assert.Equal(t, fixture[:64], string(dumped), "dump should be truncated to the size cap")
+}
+
+// TestExtractionStopsWhenContextCancelled tests that extraction from a large results page returns
+// as soon as the context is cancelled
+func TestExtractionStopsWhenContextCancelled(t *testing.T) {
+	var page strings.Builder
+	page.WriteString("<html><body><ul>")
+	for i := 0; i < 10000; i++ {
+		page.WriteString(`<li class="resultItem"><a href="/cases/UKSC/2023/1.html">Smith v Jones</a></li>`)
+	}
+	page.WriteString("</ul></body></html>")
+
+	doc, _, err := scraper.ReadDocument(strings.NewReader(page.String()))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	extracted := 0
+	err = scraper.EachUntilDone(ctx, doc.Find("li.resultItem"), func(i int, s *goquery.Selection) bool {
+		extracted++
+		if extracted == 100 {
+			cancel()
+		}
+		return true
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 100, extracted, "extraction should stop at the first element after cancellation")
+
+	// an uncancelled context visits every element until fn stops
+	extracted = 0
+	err = scraper.EachUntilDone(context.Background(), doc.Find("li.resultItem"), func(i int, s *goquery.Selection) bool {
+		extracted++
+		return extracted < 20
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 20, extracted)
+}
+
+// TestScraperDecodesPageCharset tests that non-UTF-8 pages are decoded from their declared or configured charset
+func TestScraperDecodesPageCharset(t *testing.T) {
+	gbkCourt := "\xcf\xe3\xb8\xdb\xd6\xd5\xc9\xf3\xb7\xa8\xd4\xba" // 香港终审法院
+
+	tests := []struct {
+		name        string
+		contentType string
+		charset     string
+		body        string
+		want        string
+	}{
+		{"header", "text/html; charset=ISO-8859-1", "",
+			"<html><body><h1>Cour d'appel du Qu\xe9bec</h1></body></html>", "Cour d'appel du Québec"},
+		{"meta tag", "text/html", "",
+			`<html><head><meta charset="gbk"></head><body><h1>` + gbkCourt + `</h1></body></html>`, "香港终审法院"},
+		{"configured default", "text/html", "gbk",
+			"<html><body><h1>" + gbkCourt + "</h1></body></html>", "香港终审法院"},
+		{"utf-8 ignores default", "text/html", "gbk",
+			"<html><body><h1>香港终审法院</h1></body></html>", "香港终审法院"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := scraper.NewBaseScraper("Test", "Test", "https://example.org", 60)
+			base.SetDefaultCharset(tt.charset)
+
+			resp := &http.Response{
+				Header: http.Header{"Content-Type": []string{tt.contentType}},
+				Body:   io.NopCloser(strings.NewReader(tt.body)),
+			}
+			doc, raw, err := base.ReadResponse(resp)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, doc.Find("h1").Text())
+			assert.Equal(t, tt.body, string(raw), "raw HTML is kept undecoded for dumps")
+		})
+	}
+
+	registry := scraper.NewScraperRegistry()
+	assert.Error(t, registry.SetDefaultCharsets(map[string]string{"hklii": "klingon"}))
+}
+
+// TestPrefixedCaseIDsAvoidCollisions tests that identical source IDs from different sources stay distinct
+func TestPrefixedCaseIDsAvoidCollisions(t *testing.T) {
+	gen := scraper.NewCaseIDGenerator(scraper.IDStrategyPrefixed)
+
+	fromBailii := &models.Case{ID: "UKSC/2023/15"}
+	fromCommonlii := &models.Case{ID: "UKSC/2023/15"}
+	gen.Assign("bailii", fromBailii)
+	gen.Assign("commonlii", fromCommonlii)
+
+	assert.Equal(t, "bailii:UKSC/2023/15", fromBailii.ID)
+	assert.Equal(t, "commonlii:UKSC/2023/15", fromCommonlii.ID)
+	assert.Equal(t, "UKSC/2023/15", gen.SourceID("bailii", fromBailii.ID))
+
+	// Assigning twice must not double-prefix
+	gen.Assign("bailii", fromBailii)
+	assert.Equal(t, "bailii:UKSC/2023/15", fromBailii.ID)
+
+	// Legacy strategy leaves IDs untouched
+	legacy := &models.Case{ID: "UKSC/2023/15"}
+	scraper.NewCaseIDGenerator(scraper.IDStrategySource).Assign("bailii", legacy)
+	assert.Equal(t, "UKSC/2023/15", legacy.ID)
+}
+
+// TestCaseIDHashFallback tests that cases without a source ID get a deterministic hash ID
+func TestCaseIDHashFallback(t *testing.T) {
+	gen := scraper.NewCaseIDGenerator(scraper.IDStrategyPrefixed)
+	decided := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	first := &models.Case{CaseNumber: "[2023] UKSC 15", Court: "UK Supreme Court", DecisionDate: &decided}
+	second := &models.Case{CaseNumber: "[2023] UKSC 15", Court: "UK Supreme Court", DecisionDate: &decided}
+	other := &models.Case{CaseNumber: "[2023] UKSC 16", Court: "UK Supreme Court", DecisionDate: &decided}
+
+	gen.Assign("bailii", first)
+	gen.Assign("bailii", second)
+	gen.Assign("bailii", other)
+
+	require.NotEmpty(t, first.ID)
+	assert.True(t, strings.HasPrefix(first.ID, "bailii:"))
+	assert.Equal(t, first.ID, second.ID, "hash IDs must be deterministic")
+	assert.NotEqual(t, first.ID, other.ID)
+}
+
+// TestHashCaseIDsKeepSourceID tests that a case with a hash ID can be fetched again from its
+// source through the source ID kept on the stored case
+func TestHashCaseIDsKeepSourceID(t *testing.T) {
+	ctx := context.Background()
+	decided := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+	published := models.Case{ID: "UKSC/2023/15", CaseNumber: "[2023] UKSC 15", Court: "UK Supreme Court", DecisionDate: &decided}
+	source := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("bailii", "UK", "https://example.org", 6000),
+		source:      map[string]models.Case{"UKSC/2023/15": published},
+	}
+
+	gen := scraper.NewCaseIDGenerator(scraper.IDStrategyHash)
+	s := scraper.WithIDGenerator("bailii", source, gen)
+
+	fetched, err := s.GetCaseByID(ctx, "UKSC/2023/15")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(fetched.ID, "bailii:"))
+	assert.NotContains(t, fetched.ID, "UKSC/2023/15")
+	assert.Equal(t, "UKSC/2023/15", scraper.SourceCaseID(fetched))
+
+	// Without the stored case the hash can't be turned back into the source ID
+	_, err = s.GetCaseByID(ctx, fetched.ID)
+	assert.Error(t, err)
+
+	store := storage.NewMemoryStorage()
+	require.NoError(t, store.SaveCase(ctx, fetched))
+	gen.SetCaseLookup(store.GetCase)
+
+	again, err := s.GetCaseByID(ctx, fetched.ID)
+	require.NoError(t, err)
+	assert.Equal(t, fetched.ID, again.ID)
+
+	// Prefixed IDs keep the source ID too
+	prefixed := &models.Case{ID: "UKSC/2023/15"}
+	scraper.NewCaseIDGenerator(scraper.IDStrategyPrefixed).Assign("bailii", prefixed)
+	assert.Equal(t, "UKSC/2023/15", scraper.SourceCaseID(prefixed))
+}
+
+// refreshScraper returns copies of the cases currently published at the source
+type refreshScraper struct {
+	*scraper.BaseScraper
+	source map[string]models.Case
+	sample string // case ID fetched by Validate
+}
+
+func (s *refreshScraper) SearchCases(ctx context.Context, query scraper.SearchQuery) ([]*models.Case, error) {
+	return nil, nil
+}
+
+func (s *refreshScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	c, ok := s.source[caseID]
+	if !ok {
+		return nil, errors.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (s *refreshScraper) GetCasesByDateRange(ctx context.Context, startDate, endDate time.Time, limit int) ([]*models.Case, error) {
+	return nil, nil
+}
+
+func (s *refreshScraper) IsAvailable(ctx context.Context) bool {
+	return true
+}
+
+func (s *refreshScraper) Validate(ctx context.Context) error {
+	return s.ValidateSample(ctx, s.GetCaseByID, s.sample)
+}
+
+// TestValidateSampleCase tests that scraped cases missing a core field fail validation
+func TestValidateSampleCase(t *testing.T) {
+	decided := time.Date(2019, 9, 24, 0, 0, 0, 0, time.UTC)
+	complete := func() *models.Case {
+		c := models.NewCase()
+		c.ID = "UKSC/2019/41"
+		c.CaseName = "R (Miller) v The Prime Minister"
+		c.Court = "UK Supreme Court"
+		c.DecisionDate = &decided
+		return c
+	}
+
+	require.NoError(t, scraper.ValidateSampleCase(complete()))
+
+	tests := []struct {
+		name    string
+		mutate  func(c *models.Case)
+		missing string
+	}{
+		{"no name", func(c *models.Case) { c.CaseName = "  " }, "case_name"},
+		{"no date", func(c *models.Case) { c.DecisionDate = nil }, "decision_date"},
+		{"zero date", func(c *models.Case) { c.DecisionDate = &time.Time{} }, "decision_date"},
+		{"no court", func(c *models.Case) { c.Court = "" }, "court"},
+	}
+
+	for _, tt := range tests {
+		c := complete()
+		tt.mutate(c)
+		err := scraper.ValidateSampleCase(c)
+		require.Error(t, err, tt.name)
+		assert.ErrorIs(t, err, errors.ErrMissingRequired, tt.name)
+		assert.Contains(t, err.Error(), tt.missing, tt.name)
+	}
+
+	empty := models.NewCase()
+	empty.ID = "blank"
+	err := scraper.ValidateSampleCase(empty)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "case_name, decision_date, court")
+
+	assert.ErrorIs(t, scraper.ValidateSampleCase(nil), errors.ErrMissingRequired)
+}
+
+// TestSourceSelfTest tests that each source is validated against its sample case
+func TestSourceSelfTest(t *testing.T) {
+	decided := time.Date(1995, 6, 6, 0, 0, 0, 0, time.UTC)
+	fixture := models.Case{
+		ID:           "ZACC/1995/3",
+		CaseName:     "S v Makwanyane",
+		Court:        "Constitutional Court",
+		DecisionDate: &decided,
+	}
+	broken := fixture
+	broken.Court = "" // markup change dropped the court
+
+	healthy := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("SAFLII", "South Africa", "https://www.saflii.org", 12),
+		source:      map[string]models.Case{fixture.ID: fixture},
+		sample:      fixture.ID,
+	}
+	changed := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("Changed", "South Africa", "https://example.za", 12),
+		source:      map[string]models.Case{fixture.ID: broken},
+		sample:      fixture.ID,
+	}
+	gone := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("Gone", "South Africa", "https://example.org", 12),
+		source:      map[string]models.Case{},
+		sample:      fixture.ID,
+	}
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("saflii", healthy)
+	registry.Register("changed", changed)
+	registry.Register("gone", gone)
+
+	// Registered scrapers are wrapped, and must still validate through the wrapper
+	wrapped, ok := registry.Get("saflii")
+	require.True(t, ok)
+	require.NoError(t, wrapped.Validate(context.Background()))
+
+	results := scraper.SelfTest(context.Background(), registry.GetAll(), time.Second)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "changed", results[0].Source)
+	assert.False(t, results[0].Passed)
+	assert.Contains(t, results[0].Error, "Changed: sample case ZACC/1995/3")
+	assert.Contains(t, results[0].Error, "court")
+
+	assert.Equal(t, "gone", results[1].Source)
+	assert.False(t, results[1].Passed)
+	assert.Contains(t, results[1].Error, "failed to fetch sample case")
+
+	assert.Equal(t, "saflii", results[2].Source)
+	assert.Equal(t, "South Africa", results[2].Jurisdiction)
+	assert.True(t, results[2].Passed)
+	assert.Empty(t, results[2].Error)
+}
+
+// TestStaleCaseRefresh tests that only cases changed at the source are rewritten and versioned
+func TestStaleCaseRefresh(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	stale := time.Now().Add(-60 * 24 * time.Hour)
+	newCase := func(id, text string) *models.Case {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Court = "UK Supreme Court"
+		c.SourceDatabase = "refresh"
+		c.FullText = text
+		c.LastUpdated = stale
+		return c
+	}
+
+	unchanged := newCase("unchanged", "original judgment")
+	changed := newCase("changed", "original judgment")
+	require.NoError(t, store.SaveCase(ctx, unchanged))
+	require.NoError(t, store.SaveCase(ctx, changed))
+
+	src := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("refresh", "UK", "https://example.org", 6000),
+		source: map[string]models.Case{
+			"unchanged": *newCase("unchanged", "original judgment"),
+			"changed":   *newCase("changed", "corrected judgment"),
+		},
+	}
+	registry := scraper.NewScraperRegistry()
+	registry.Register("refresh", src)
+
+	refresher := worker.NewCaseRefresher(store, registry, worker.RefreshConfig{
+		MaxAge:         30 * 24 * time.Hour,
+		BatchSize:      10,
+		BudgetFraction: 1,
+	})
+
+	result, err := refresher.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Checked)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 1, result.Unchanged)
+
+	got, err := store.GetCase(ctx, "unchanged")
+	require.NoError(t, err)
+	assert.Equal(t, 0, got.Version)
+	assert.Equal(t, stale, got.LastUpdated, "unchanged case must not be rewritten")
+
+	got, err = store.GetCase(ctx, "changed")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Version)
+	assert.Equal(t, "corrected judgment", got.FullText)
+	assert.True(t, got.LastUpdated.After(stale))
+
+	// The replaced version is kept
+	versions, err := store.ListCaseVersions(ctx, "changed")
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	assert.Equal(t, 0, versions[0].Version)
+	assert.Equal(t, "original judgment", versions[0].FullText)
+}
+
+// TestStaleCaseRefreshBySourceID tests that cases with assigned IDs are re-fetched by the ID
+// they have at their source
+func TestStaleCaseRefreshBySourceID(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	decided := time.Date(2023, 5, 10, 0, 0, 0, 0, time.UTC)
+	published := func(text string) models.Case {
+		c := models.NewCase()
+		c.ID = "UKSC/2023/15"
+		c.CaseName = "Smith v Jones"
+		c.CaseNumber = "[2023] UKSC 15"
+		c.Court = "UK Supreme Court"
+		c.DecisionDate = &decided
+		c.SourceDatabase = "refresh"
+		c.FullText = text
+		return *c
+	}
+	src := &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("refresh", "UK", "https://example.org", 6000),
+		source:      map[string]models.Case{"UKSC/2023/15": published("original judgment")},
+	}
+	registry := scraper.NewScraperRegistry()
+	registry.SetIDGenerator(scraper.NewCaseIDGenerator(scraper.IDStrategyHash))
+	registry.Register("refresh", src)
+
+	s, ok := registry.Get("refresh")
+	require.True(t, ok)
+	stored, err := s.GetCaseByID(ctx, "UKSC/2023/15")
+	require.NoError(t, err)
+	stored.LastUpdated = time.Now().Add(-60 * 24 * time.Hour)
+	require.NoError(t, store.SaveCase(ctx, stored))
+
+	src.source["UKSC/2023/15"] = published("corrected judgment")
+	refresher := worker.NewCaseRefresher(store, registry, worker.RefreshConfig{
+		MaxAge:         30 * 24 * time.Hour,
+		BatchSize:      10,
+		BudgetFraction: 1,
+	})
+
+	result, err := refresher.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Zero(t, result.Failed)
+
+	got, err := store.GetCase(ctx, stored.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "corrected judgment", got.FullText)
+}
+
+// TestScraperUsesInjectedClock tests that scrapers stamp times from their clock
+func TestScraperUsesInjectedClock(t *testing.T) {
+	fixed := clock.NewFixed(time.Date(2024, 2, 1, 9, 30, 0, 0, time.UTC))
+
+	base := scraper.NewBaseScraper("Test", "Test", "https://example.org", 60)
+	base.SetClock(fixed)
+
+	assert.Equal(t, fixed.Now(), base.Now())
+	assert.Equal(t, fixed.Now(), base.GetMetadata().LastChecked)
+
+	fixed.Advance(time.Hour)
+	assert.Equal(t, time.Date(2024, 2, 1, 10, 30, 0, 0, time.UTC), base.Now())
+}
+
+// TestStaleCaseRefreshUsesClock tests that staleness is judged against the refresher's clock
+func TestStaleCaseRefreshUsesClock(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	defer store.Close()
+
+	lastUpdated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newCase := func(text string) *models.Case {
+		c := models.NewCase()
+		c.ID = "case"
+		c.CaseName = "Case"
+		c.Court = "UK Supreme Court"
+		c.SourceDatabase = "refresh"
+		c.FullText = text
+		c.LastUpdated = lastUpdated
+		return c
+	}
+	require.NoError(t, store.SaveCase(ctx, newCase("original judgment")))
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("refresh", &refreshScraper{
+		BaseScraper: scraper.NewBaseScraper("refresh", "UK", "https://example.org", 6000),
+		source:      map[string]models.Case{"case": *newCase("corrected judgment")},
+	})
+
+	refresher := worker.NewCaseRefresher(store, registry, worker.RefreshConfig{
+		MaxAge:         30 * 24 * time.Hour,
+		BatchSize:      10,
+		BudgetFraction: 1,
+	})
+	fixed := clock.NewFixed(lastUpdated.Add(29 * 24 * time.Hour))
+	refresher.SetClock(fixed)
+
+	result, err := refresher.Run(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, result.Checked, "case is not yet stale on the injected clock")
+
+	fixed.Advance(2 * 24 * time.Hour)
+	result, err = refresher.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+
+	got, err := store.GetCase(ctx, "case")
+	require.NoError(t, err)
+	assert.Equal(t, 1, got.Version)
+	assert.Equal(t, "corrected judgment", got.FullText)
+}
+
+// searchScraper returns a fixed set of cases for every search
+type searchScraper struct {
+	*refreshScraper
+	results []*models.Case
+}
+
+func (s *searchScraper) SearchCases(ctx context.Context, query scraper.SearchQuery) ([]*models.Case, error) {
+	cases := make([]*models.Case, len(s.results))
+	for i, c := range s.results {
+		copied := *c
+		cases[i] = &copied
+	}
+	return cases, nil
+}
+
+// TestScrapeJobDropsLowerCourts tests that scrape jobs store only cases from allowed court levels
+func TestScrapeJobDropsLowerCourts(t *testing.T) {
+	ctx := context.Background()
+
+	newCase := func(id, court string) *models.Case {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Court = court
+		c.Jurisdiction = "UK"
+		return c
+	}
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000)},
+		results: []*models.Case{
+			newCase("supreme", "UK Supreme Court"),
+			newCase("appeal", "Court of Appeal"),
+			newCase("high", "High Court of Justice"),
+			newCase("magistrates", "Westminster Magistrates' Court"),
+		},
+	})
+
+	storedIDs := func(store storage.Storage) []string {
+		cases, err := store.ListCases(ctx, storage.CaseFilter{})
+		require.NoError(t, err)
+		ids := make([]string, 0, len(cases))
+		for _, c := range cases {
+			ids = append(ids, c.ID)
+		}
+		return ids
+	}
+
+	t.Run("configured levels", func(t *testing.T) {
+		store := storage.NewMemoryStorage()
+		filter := worker.NewCourtLevelFilter([]models.CourtLevel{models.CourtLevelSupreme, models.CourtLevelAppellate})
+		handler := worker.NewScrapeJobHandler(store, registry, filter)
+
+		job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+		require.NoError(t, handler(ctx, job))
+
+		assert.ElementsMatch(t, []string{"supreme", "appeal"}, storedIDs(store))
+		assert.Equal(t, 2, job.Result["dropped"])
+	})
+
+	t.Run("job levels override", func(t *testing.T) {
+		store := storage.NewMemoryStorage()
+		handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+
+		// Levels decoded from a JSON queue payload arrive as float64
+		job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+			"jurisdiction": "UK",
+			"court_levels": []interface{}{float64(models.CourtLevelSupreme)},
+		})
+		require.NoError(t, handler(ctx, job))
+		assert.Equal(t, []string{"supreme"}, storedIDs(store))
+	})
+
+	t.Run("no levels", func(t *testing.T) {
+		store := storage.NewMemoryStorage()
+		handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+
+		require.NoError(t, handler(ctx, queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})))
+		assert.Len(t, storedIDs(store), 4)
+	})
+}
+
+// TestDedupScrapeJobs tests that identical scrape jobs enqueued within the dedup window run once
+func TestDedupScrapeJobs(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mq := queue.NewMemoryQueue()
+	defer mq.Close()
+	q := queue.NewDedupQueue(mq, queue.NewMemoryDedupStore(), time.Minute)
+
+	payload := map[string]interface{}{
+		"jurisdiction": "UK",
+		"case_id":      "uksc-2024-1",
+	}
+	first := queue.NewJob(queue.JobTypeScrape, payload)
+	second := queue.NewJob(queue.JobTypeScrape, payload)
+	require.NoError(t, q.Enqueue(ctx, first))
+	require.NoError(t, q.Enqueue(ctx, second))
+
+	// The duplicate is coalesced into the pending job
+	assert.Equal(t, first.ID, second.ID)
+	depth, err := mq.GetDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, depth)
+
+	// A different case is not a duplicate
+	other := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction": "UK",
+		"case_id":      "uksc-2024-2",
+	})
+	require.NoError(t, q.Enqueue(ctx, other))
+	assert.NotEqual(t, first.ID, other.ID)
+
+	s := &flakyScraper{healthy: true}
+	processed := []string{runOnce(ctx, t, mq, s).ID, runOnce(ctx, t, mq, s).ID}
+	assert.ElementsMatch(t, []string{first.ID, other.ID}, processed)
+	assert.Equal(t, 2, s.calls)
+}
+
+// TestDedupReleasesFailedEnqueue tests that a job which fails to enqueue does not suppress its retry
+func TestDedupReleasesFailedEnqueue(t *testing.T) {
+	ctx := context.Background()
+	store := queue.NewMemoryDedupStore()
+	payload := map[string]interface{}{
+		"jurisdiction": "UK",
+		"case_id":      "uksc-2024-1",
+	}
+
+	closed := queue.NewMemoryQueue()
+	require.NoError(t, closed.Close())
+	failed := queue.NewJob(queue.JobTypeScrape, payload)
+	assert.Error(t, queue.NewDedupQueue(closed, store, time.Minute).Enqueue(ctx, failed))
+
+	mq := queue.NewMemoryQueue()
+	defer mq.Close()
+	retry := queue.NewJob(queue.JobTypeScrape, payload)
+	require.NoError(t, queue.NewDedupQueue(mq, store, time.Minute).Enqueue(ctx, retry))
+
+	assert.NotEqual(t, failed.ID, retry.ID)
+	depth, err := mq.GetDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, depth)
+}
+
+// openBreaker reports the listed sources' circuit breakers as open
+type openBreaker map[string]bool
+
+func (b openBreaker) IsOpen(source string) bool {
+	return b[source]
+}
+
+// TestScrapeSchedulerEnqueuesOnTick tests that a scheduled scrape is enqueued
+// when its time comes, with the previous run as its watermark
+func TestScrapeSchedulerEnqueuesOnTick(t *testing.T) {
+	ctx := context.Background()
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", &refreshScraper{BaseScraper: scraper.NewBaseScraper("bailii", "UK", "https://example.org", 6000)})
+
+	_, err := worker.NewScrapeScheduler(q, registry, map[string]string{"missing": "@daily"})
+	assert.Error(t, err, "unknown sources are rejected")
+	_, err = worker.NewScrapeScheduler(q, registry, map[string]string{"bailii": "0 25 * * *"})
+	assert.Error(t, err, "invalid schedules are rejected")
+
+	// Daily at 02:00, starting on a Monday evening
+	fixed := clock.NewFixed(time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC))
+	scheduler, err := worker.NewScrapeScheduler(q, registry, map[string]string{"bailii": "0 2 * * *"})
+	require.NoError(t, err)
+	scheduler.SetClock(fixed)
+
+	schedules := scheduler.Schedules()
+	require.Len(t, schedules, 1)
+	assert.Equal(t, "UK", schedules[0].Jurisdiction)
+	assert.Equal(t, time.Date(2024, 3, 5, 2, 0, 0, 0, time.UTC), schedules[0].NextRun)
+
+	jobs, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "nothing is due before 02:00")
+
+	fixed.Set(time.Date(2024, 3, 5, 2, 0, 0, 0, time.UTC))
+	jobs, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, queue.JobTypeScrape, jobs[0].Type)
+	assert.Equal(t, "UK", jobs[0].Payload["jurisdiction"])
+	assert.Equal(t, "bailii", jobs[0].Payload["source"])
+	assert.NotContains(t, jobs[0].Payload, "start_date", "the first run has no watermark")
+
+	depth, err := q.GetDepth(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, depth)
+
+	jobs, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "a run is enqueued once")
+	assert.Equal(t, time.Date(2024, 3, 6, 2, 0, 0, 0, time.UTC), scheduler.Schedules()[0].NextRun)
+
+	// The open breaker skips the next day's run, keeping the watermark
+	scheduler.SetBreaker(openBreaker{"bailii": true})
+	fixed.Advance(24 * time.Hour)
+	jobs, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+	assert.NotNil(t, scheduler.Schedules()[0].LastSkipped)
+
+	scheduler.SetBreaker(openBreaker{})
+	fixed.Advance(24 * time.Hour)
+	jobs, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "2024-03-05T02:00:00Z", jobs[0].Payload["start_date"])
+}
+
+// TestScrapeWindowDefersScrapes tests that scrapes due outside a source's
+// scraping window are deferred until the window opens, by the scheduler and
+// by the scrape job handler
+func TestScrapeWindowDefersScrapes(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := scraper.NewScrapeWindow("Europe/London", []string{"20:00"})
+	assert.Error(t, err, "ranges need a start and an end")
+	_, err = scraper.NewScrapeWindow("Europe/Nowhere", []string{"20:00-06:00"})
+	assert.Error(t, err, "unknown timezones are rejected")
+
+	// Overnight in London, which is an hour ahead of UTC in July
+	window, err := scraper.NewScrapeWindow("Europe/London", []string{"20:00-06:00"})
+	require.NoError(t, err)
+	assert.True(t, window.Open(time.Date(2024, 7, 1, 4, 30, 0, 0, time.UTC)))
+	assert.False(t, window.Open(time.Date(2024, 7, 1, 5, 0, 0, 0, time.UTC)))
+	assert.True(t, window.Open(time.Date(2024, 7, 1, 19, 0, 0, 0, time.UTC)))
+
+	q := queue.NewMemoryQueue()
+	defer q.Close()
+
+	bailii := &refreshScraper{BaseScraper: scraper.NewBaseScraper("bailii", "UK", "https://example.org", 6000)}
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", bailii)
+	registry.SetScrapeWindows(map[string]*scraper.ScrapeWindow{"bailii": window})
+
+	// Hourly, from late morning
+	fixed := clock.NewFixed(time.Date(2024, 7, 1, 11, 30, 0, 0, time.UTC))
+	scheduler, err := worker.NewScrapeScheduler(q, registry, map[string]string{"bailii": "0 * * * *"})
+	require.NoError(t, err)
+	scheduler.SetClock(fixed)
+
+	fixed.Set(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC))
+	jobs, err := scheduler.Tick(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "the noon run is outside the window")
+	schedules := scheduler.Schedules()
+	assert.NotNil(t, schedules[0].LastDeferred)
+	assert.Equal(t, time.Date(2024, 7, 1, 19, 0, 0, 0, time.UTC), schedules[0].NextRun.UTC(), "deferred until 20:00 London time")
+
+	fixed.Set(time.Date(2024, 7, 1, 19, 0, 0, 0, time.UTC))
+	jobs, err = scheduler.Tick(ctx)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1, "the deferred run is enqueued once the window opens")
+	assert.Equal(t, time.Date(2024, 7, 1, 20, 0, 0, 0, time.UTC), scheduler.Schedules()[0].NextRun)
+
+	// A job for the source handled outside the window is deferred, not run
+	handler := worker.NewScrapeJobHandler(storage.NewMemoryStorage(), registry, worker.NewCourtLevelFilter(nil))
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK", "source": "bailii"})
+
+	bailii.SetClock(clock.NewFixed(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)))
+	err = handler(ctx, job)
+	var deferred *worker.DeferredError
+	require.ErrorAs(t, err, &deferred)
+	assert.Equal(t, time.Date(2024, 7, 1, 19, 0, 0, 0, time.UTC), deferred.Until.UTC())
+
+	bailii.SetClock(clock.NewFixed(time.Date(2024, 7, 1, 21, 0, 0, 0, time.UTC)))
+	require.NoError(t, handler(ctx, job), "the job runs inside the window")
+}
+
+// countingStore counts the cases written to a memory store
+type countingStore struct {
+	*storage.MemoryStorage
+	writes int
+}
+
+func (s *countingStore) SaveCase(ctx context.Context, c *models.Case) error {
+	s.writes++
+	return s.MemoryStorage.SaveCase(ctx, c)
+}
+
+func (s *countingStore) UpdateCase(ctx context.Context, c *models.Case) error {
+	s.writes++
+	return s.MemoryStorage.UpdateCase(ctx, c)
+}
+
+// TestUnchangedRescrapeIsSkipped tests that re-scraping a case whose content
+// hash matches the stored case's writes nothing, from a scrape job or a refresh
+func TestUnchangedRescrapeIsSkipped(t *testing.T) {
+	ctx := context.Background()
+	store := &countingStore{MemoryStorage: storage.NewMemoryStorage()}
+
+	scraped := models.NewCase()
+	scraped.ID = "rescraped"
+	scraped.CaseName = "Rescraped v Unchanged"
+	scraped.Court = "UK Supreme Court"
+	scraped.Jurisdiction = "UK"
+	scraped.SourceDatabase = "uk"
+	scraped.FullText = "original judgment"
+
+	src := &searchScraper{
+		refreshScraper: &refreshScraper{
+			BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000),
+			source:      map[string]models.Case{"rescraped": *scraped},
+		},
+		results: []*models.Case{scraped},
+	}
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", src)
+	handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+	require.NoError(t, handler(ctx, job))
+	assert.Equal(t, 1, job.Result["saved"])
+	require.Equal(t, 1, store.writes)
+
+	hash, err := storage.StoredContentHash(ctx, store, "rescraped")
+	require.NoError(t, err)
+	assert.Equal(t, worker.CaseContentHash(scraped), hash, "the hash is stored with the case")
+	stored, err := store.GetCase(ctx, "rescraped")
+	require.NoError(t, err)
+
+	job = queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+	require.NoError(t, handler(ctx, job))
+	assert.Equal(t, 0, job.Result["saved"])
+	assert.Equal(t, 1, job.Result["unchanged"])
+	assert.Equal(t, 0, job.Result["dropped"])
+	assert.Equal(t, 1, store.writes, "an identical re-scrape writes nothing")
+
+	// A refresh of the stale case finds the same content at the source
+	stored.LastUpdated = time.Now().Add(-60 * 24 * time.Hour)
+	refresher := worker.NewCaseRefresher(store, registry, worker.RefreshConfig{
+		MaxAge:         30 * 24 * time.Hour,
+		BatchSize:      10,
+		BudgetFraction: 1,
+	})
+	result, err := refresher.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Unchanged)
+	assert.Equal(t, 1, store.writes, "an identical refresh writes nothing")
+
+	// A changed judgment is written again
+	changed := *scraped
+	changed.FullText = "corrected judgment"
+	src.results = []*models.Case{&changed}
+	job = queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+	require.NoError(t, handler(ctx, job))
+	assert.Equal(t, 1, job.Result["saved"])
+	assert.Equal(t, 2, store.writes)
+}
+
+// TestCronScheduleNext tests cron schedule parsing and next-run times
+func TestCronScheduleNext(t *testing.T) {
+	// Friday 2024-03-08 10:30 UTC
+	from := time.Date(2024, 3, 8, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, 3, 8, 10, 45, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 3, 8, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"0 3 * * 1", time.Date(2024, 3, 11, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC)},
+		{"30 10 * * 1-5", time.Date(2024, 3, 11, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := worker.ParseCronSchedule(tt.spec)
+		require.NoError(t, err, tt.spec)
+		assert.Equal(t, tt.want, s.Next(from), tt.spec)
+	}
+
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *"} {
+		_, err := worker.ParseCronSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+// TestScrapedFullTextIsCapped tests that oversized full text from a source is
+// truncated, flagged and logged
+func TestScrapedFullTextIsCapped(t *testing.T) {
+	ctx := context.Background()
+
+	// An infinite-scroll page repeating until it reaches 1 MiB
+	oversized := models.NewCase()
+	oversized.ID = "scroll"
+	oversized.URL = "https://example.org/judgments/scroll"
+	oversized.FullText = strings.Repeat("Load more judgments… ", (1<<20)/22+1)
+
+	normal := models.NewCase()
+	normal.ID = "normal"
+	normal.FullText = "A short judgment."
+
+	logs := &warnRecorder{}
+	registry := scraper.NewScraperRegistry()
+	registry.SetIDGenerator(scraper.NewCaseIDGenerator(scraper.IDStrategyPrefixed))
+	registry.SetMaxFullTextBytes(64<<10, logs)
+	registry.Register("scroll", &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("scroll", "UK", "https://example.org", 6000)},
+		results:        []*models.Case{oversized, normal},
+	})
+
+	s, ok := registry.Get("scroll")
+	require.True(t, ok)
+	cases, err := s.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+
+	truncated := cases[0]
+	assert.LessOrEqual(t, len(truncated.FullText), 64<<10)
+	assert.Greater(t, len(truncated.FullText), 64<<10-4)
+	assert.True(t, utf8.ValidString(truncated.FullText), "text is cut on a character boundary")
+	assert.Equal(t, true, truncated.Metadata[scraper.MetadataFullTextTruncated])
+	assert.Equal(t, len(oversized.FullText), truncated.Metadata[scraper.MetadataFullTextOriginalBytes])
+
+	require.Len(t, logs.lines, 1)
+	assert.Contains(t, logs.lines[0], "https://example.org/judgments/scroll")
+	assert.Contains(t, logs.lines[0], "scroll:scroll", "the logged case has its assigned ID")
+
+	assert.Equal(t, "A short judgment.", cases[1].FullText)
+	assert.NotContains(t, cases[1].Metadata, scraper.MetadataFullTextTruncated)
+
+	// Zero disables the cap
+	c := models.NewCase()
+	c.FullText = oversized.FullText
+	assert.False(t, scraper.TruncateFullText(c, 0))
+	assert.Equal(t, len(oversized.FullText), len(c.FullText))
+}
+
+// fanOutScraper returns fixed search results, reports a fixed availability
+// and counts its searches
+type fanOutScraper struct {
+	*searchScraper
+	available bool
+	searches  int32
+}
+
+func (s *fanOutScraper) SearchCases(ctx context.Context, query scraper.SearchQuery) ([]*models.Case, error) {
+	atomic.AddInt32(&s.searches, 1)
+	return s.searchScraper.SearchCases(ctx, query)
+}
+
+func (s *fanOutScraper) IsAvailable(ctx context.Context) bool {
+	return s.available
+}
+
+// TestMultiScraperSkipsUnhealthySources tests that a fan-out search skips
+// circuit-open and unavailable sources and queries the healthy one
+func TestMultiScraperSkipsUnhealthySources(t *testing.T) {
+	ctx := context.Background()
+
+	newSource := func(name string, available bool) *fanOutScraper {
+		c := models.NewCase()
+		c.ID = name + "-case"
+		return &fanOutScraper{
+			searchScraper: &searchScraper{
+				refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper(name, "UK", "https://example.org", 6000)},
+				results:        []*models.Case{c},
+			},
+			available: available,
+		}
+	}
+	healthy := newSource("bailii", true)
+	tripped := newSource("commonlii", true)
+	down := newSource("worldlii", false)
+	sources := map[string]scraper.Scraper{"bailii": healthy, "commonlii": tripped, "worldlii": down}
+
+	multi := scraper.NewMultiScraper(sources)
+	multi.SetBreaker(openBreaker{"commonlii": true})
+
+	// Keep availability current in the background
+	refreshCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	go multi.StartAvailabilityRefresh(refreshCtx, time.Minute)
+
+	// Until worldlii has been checked it is still tried
+	require.Eventually(t, func() bool {
+		result, err := multi.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+		return err == nil && result.Sources[2].Status == scraper.ScraperStatusUnavailable
+	}, time.Second, 10*time.Millisecond)
+
+	searches := atomic.LoadInt32(&down.searches)
+	result, err := multi.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+	require.NoError(t, err)
+
+	require.Len(t, result.Cases, 1)
+	assert.Equal(t, "bailii-case", result.Cases[0].ID)
+	assert.Zero(t, atomic.LoadInt32(&tripped.searches), "the circuit-open source is never queried")
+	assert.Equal(t, searches, atomic.LoadInt32(&down.searches), "the unavailable source is not queried")
+
+	require.Len(t, result.Sources, 3)
+	assert.Equal(t, scraper.FanOutSource{Source: "bailii", Status: scraper.ScraperStatusActive, Cases: 1}, result.Sources[0])
+	assert.Equal(t, scraper.ScraperStatusUnavailable, result.Sources[1].Status)
+	assert.Equal(t, "circuit breaker open", result.Sources[1].Error)
+	assert.Equal(t, scraper.ScraperStatusUnavailable, result.Sources[2].Status)
+
+	// With every source unhealthy there is nothing to query
+	multi.SetBreaker(openBreaker{"bailii": true, "commonlii": true})
+	result, err = multi.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+	assert.Error(t, err)
+	assert.Empty(t, result.Cases)
+}
+
+// TestExtractionVersionIsStampedAndQueryable tests that scraped cases record
+// the extraction version and that cases from older versions can be listed
+func TestExtractionVersionIsStampedAndQueryable(t *testing.T) {
+	ctx := context.Background()
+
+	fresh := models.NewCase()
+	fresh.ID = "fresh"
+	pinned := models.NewCase()
+	pinned.ID = "pinned"
+	pinned.ExtractionVersion = 3 // stamped by the scraper itself
+
+	registry := scraper.NewScraperRegistry()
+	registry.SetExtractionVersion(2)
+	registry.Register("versioned", &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("versioned", "UK", "https://example.org", 6000)},
+		results:        []*models.Case{fresh, pinned},
+	})
+
+	s, ok := registry.Get("versioned")
+	require.True(t, ok)
+	cases, err := s.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+	require.NoError(t, err)
+	require.Len(t, cases, 2)
+	assert.Equal(t, 2, cases[0].ExtractionVersion)
+	assert.Equal(t, 3, cases[1].ExtractionVersion)
+
+	// Cases saved before versioning have no version and count as outdated
+	legacy := models.NewCase()
+	legacy.ID = "legacy"
+
+	store := storage.NewMemoryStorage()
+	for _, c := range append(cases, legacy) {
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	outdated, err := store.ListCases(ctx, storage.CaseFilter{ExtractionVersionBelow: 3})
+	require.NoError(t, err)
+	ids := make([]string, 0, len(outdated))
+	for _, c := range outdated {
+		ids = append(ids, c.ID)
+	}
+	assert.ElementsMatch(t, []string{"fresh", "legacy"}, ids)
+
+	count, err := store.CountCases(ctx, storage.CaseFilter{ExtractionVersionBelow: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	// Without a configured version the default is stamped
+	registry = scraper.NewScraperRegistry()
+	registry.Register("unversioned", &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("unversioned", "UK", "https://example.org", 6000)},
+		results:        []*models.Case{models.NewCase()},
+	})
+	s, ok = registry.Get("unversioned")
+	require.True(t, ok)
+	cases, err = s.SearchCases(ctx, scraper.SearchQuery{Jurisdiction: "UK"})
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Equal(t, scraper.DefaultExtractionVersion, cases[0].ExtractionVersion)
+}
+
+// TestFullTextKeepsParagraphNumbers tests that preserved tags keep a
+// judgment's paragraph breaks and numbering in its full text
+func TestFullTextKeepsParagraphNumbers(t *testing.T) {
+	// A BAILII judgment numbers its paragraphs with an ordered list
+	page := `<html><body><div class="judgment-body">
+		<p>LORD JUSTICE SMITH:</p>
+		<ol type="1">
+			<li><p>This is an appeal from the   High Court.</p></li>
+			<li>The claimant <i>was</i> injured<br>on 1 May 2019.</li>
+		</ol>
+		<ol type="1" start="12">
+			<li>For these reasons the appeal is dismissed.</li>
+			<li value="20">Costs follow the event.</li>
+		</ol>
+		<script>var tracking = true;</script>
+	</div></body></html>`
+
+	doc, _, err := scraper.ReadDocument(strings.NewReader(page))
+	require.NoError(t, err)
+	body := doc.Find(".judgment-body")
+
+	structure, err := scraper.NewTextStructure([]string{"p", "br", "ol", "LI"})
+	require.NoError(t, err)
+
+	base := scraper.NewBaseScraper("bailii", "United Kingdom", "https://www.bailii.org", 60)
+	base.SetTextStructure(structure)
+	assert.Equal(t, "LORD JUSTICE SMITH:\n\n"+
+		"1. This is an appeal from the High Court.\n\n"+
+		"2. The claimant was injured\non 1 May 2019.\n\n"+
+		"12. For these reasons the appeal is dismissed.\n\n"+
+		"20. Costs follow the event.", base.ExtractText(body))
+
+	// Without preserved tags the text is flattened as before
+	base.SetTextStructure(nil)
+	flat := base.ExtractText(body)
+	assert.NotContains(t, flat, "\n\n")
+	assert.NotContains(t, flat, "12.")
+	assert.Contains(t, flat, "For these reasons the appeal is dismissed.")
+
+	_, err = scraper.NewTextStructure([]string{"p", "marquee"})
+	assert.Error(t, err)
+}
+
+// TestSelectorOverrides tests that selectors loaded from a file replace a scraper's built-in ones
+func TestSelectorOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "selectors.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+bailii:
+  case_name: "h2.judgment-title"
+  full_text: "section#judgment"
+hklii:
+  case_name: "h2.judgment-title"
+`), 0o644))
+
+	overrides, err := scraper.LoadSelectorOverrides(path)
+	require.NoError(t, err)
+
+	// BAILII moved the case name and judgment body in a redesign
+	page := `<html><body>
+		<h1 class="case-title"></h1>
+		<h2 class="judgment-title">Smith v Jones</h2>
+		<div class="court">Court of Appeal</div>
+		<section id="judgment"><p>The appeal is dismissed.</p></section>
+	</body></html>`
+	doc, _, err := scraper.ReadDocument(strings.NewReader(page))
+	require.NoError(t, err)
+
+	bailii := &refreshScraper{BaseScraper: scraper.NewBaseScraper("BAILII", "United Kingdom", "https://www.bailii.org", 60)}
+	hklii := &refreshScraper{BaseScraper: scraper.NewBaseScraper("HKLII", "Hong Kong", "https://www.hklii.hk", 60)}
+	canlii := &refreshScraper{BaseScraper: scraper.NewBaseScraper("CanLII", "Canada", "https://www.canlii.org", 60)}
+
+	// Overrides reach scrapers registered before and after they are set
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", bailii)
+	registry.SetSelectors(overrides)
+	registry.Register("hklii", hklii)
+	registry.Register("canlii", canlii)
+
+	assert.Equal(t, "Smith v Jones", doc.Find(bailii.Selector("case_name", "h1.case-title")).Text())
+	assert.Equal(t, "The appeal is dismissed.", bailii.ExtractText(doc.Find(bailii.Selector("full_text", ".judgment-body"))))
+	assert.Equal(t, "Court of Appeal", doc.Find(bailii.Selector("court", ".court")).Text(), "unset selectors keep the built-in default")
+	assert.Equal(t, "Smith v Jones", doc.Find(hklii.Selector("case_name", "h1")).Text())
+	assert.Empty(t, doc.Find(canlii.Selector("case_name", "h1.case-title")).Text(), "scrapers without overrides keep their defaults")
+
+	_, err = scraper.LoadSelectorOverridesFromReader(strings.NewReader("bailii:\n  case_name: \"h1[\"\n"))
+	assert.Error(t, err)
+}
+
+// TestBlocklistedURLsAreSkipped tests that scrape jobs skip cases whose URL is on the source's blocklist
+func TestBlocklistedURLsAreSkipped(t *testing.T) {
+	ctx := context.Background()
+
+	policy, ok := compliance.NewPolicyManager().GetPolicy("BAILII")
+	require.True(t, ok)
+	blocklist, err := scraper.NewURLBlocklist(append(policy.Blocklist, `/consolidated/`))
+	require.NoError(t, err)
+
+	assert.True(t, blocklist.Blocked("https://www.bailii.org/ew/cases/EWCA/Civ/index.html"))
+	assert.True(t, blocklist.Blocked("https://www.bailii.org/cgi-bin/lucy_search_1.cgi?query=negligence"))
+	assert.True(t, blocklist.Blocked("https://www.bailii.org/uk/cases/consolidated/2023.html"))
+	assert.False(t, blocklist.Blocked("https://www.bailii.org/ew/cases/EWCA/Civ/2023/15.html"))
+
+	_, err = scraper.NewURLBlocklist([]string{"("})
+	assert.Error(t, err)
+
+	newCase := func(id, url string) *models.Case {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Jurisdiction = "UK"
+		c.URL = url
+		return c
+	}
+	source := &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("BAILII", "UK", "https://www.bailii.org", 6000)},
+		results: []*models.Case{
+			newCase("judgment", "https://www.bailii.org/ew/cases/EWCA/Civ/2023/15.html"),
+			newCase("index", "https://www.bailii.org/ew/cases/EWCA/Civ/index.html"),
+			newCase("consolidated", "https://www.bailii.org/uk/cases/consolidated/2023.html"),
+		},
+	}
+
+	// Blocklists reach scrapers registered before they are set
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", source)
+	registry.SetURLBlocklists(map[string]*scraper.URLBlocklist{"bailii": blocklist})
+	assert.True(t, source.IsBlocked("https://www.bailii.org/ew/cases/EWCA/Civ/index.html"))
+
+	store := storage.NewMemoryStorage()
+	handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+	require.NoError(t, handler(ctx, job))
+
+	cases, err := store.ListCases(ctx, storage.CaseFilter{})
+	require.NoError(t, err)
+	require.Len(t, cases, 1)
+	assert.Equal(t, "judgment", cases[0].ID)
+	assert.Equal(t, 2, job.Result["blocked"])
+	assert.Equal(t, 2, job.Result["dropped"])
+}
+
+// detailScraper serves case details slowly, recording how many are fetched at once
+type detailScraper struct {
+	*searchScraper
+	inFlight, maxInFlight, fetched int32
+}
+
+func (s *detailScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	n := atomic.AddInt32(&s.inFlight, 1)
+	defer atomic.AddInt32(&s.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxInFlight)
+		if n <= max || atomic.CompareAndSwapInt32(&s.maxInFlight, max, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if caseID == "withdrawn" {
+		return nil, errors.ErrNotFound
+	}
+	atomic.AddInt32(&s.fetched, 1)
+	c := models.NewCase()
+	c.ID = caseID
+	c.CaseName = "Case " + caseID
+	c.Jurisdiction = "UK"
+	c.FullText = "Full text of " + caseID
+	return c, nil
+}
+
+// TestFetchDetailsWithinConcurrencyLimit tests that case details are fetched
+// concurrently, never more at once than the source's limit
+func TestFetchDetailsWithinConcurrencyLimit(t *testing.T) {
+	ctx := context.Background()
+
+	var results []*models.Case
+	for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "withdrawn"} {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Jurisdiction = "UK"
+		c.URL = "https://example.org/" + id
+		results = append(results, c)
+	}
+	source := &detailScraper{searchScraper: &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000)},
+		results:        results,
+	}}
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", source)
+	registry.SetConcurrencyLimits(10, map[string]int{"uk": 3})
+	assert.Equal(t, 3, source.GetConcurrencyLimit())
+
+	store := storage.NewMemoryStorage()
+	handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction":    "UK",
+		"fetch_full_text": true,
+	})
+	require.NoError(t, handler(ctx, job))
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&source.maxInFlight), int32(3))
+	assert.Greater(t, atomic.LoadInt32(&source.maxInFlight), int32(1))
+	assert.Equal(t, int32(8), atomic.LoadInt32(&source.fetched))
+
+	// Details replace the search results; a failed fetch keeps the case as found
+	for _, id := range []string{"a", "h"} {
+		c, err := store.GetCase(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, "Full text of "+id, c.FullText)
+		assert.Equal(t, "https://example.org/"+id, c.URL)
+	}
+	withdrawn, err := store.GetCase(ctx, "withdrawn")
+	require.NoError(t, err)
+	assert.Empty(t, withdrawn.FullText)
+
+	assert.Equal(t, 9, job.Result["saved"])
+	detailErrors, ok := job.Result["detail_errors"].([]scraper.DetailError)
+	require.True(t, ok)
+	require.Len(t, detailErrors, 1)
+	assert.Equal(t, "withdrawn", detailErrors[0].CaseID)
+}
+
+// TestScrapeOnlyNewSkipsStoredCases tests that with OnlyNew set, cases already
+// in storage are not fetched again unless the job forces a refresh
+func TestScrapeOnlyNewSkipsStoredCases(t *testing.T) {
+	ctx := context.Background()
+
+	var results []*models.Case
+	for _, id := range []string{"a", "b"} {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Case " + id
+		c.Jurisdiction = "UK"
+		c.URL = "https://example.org/" + id
+		results = append(results, c)
+	}
+	source := &detailScraper{searchScraper: &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000)},
+		results:        results,
+	}}
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", source)
+
+	store := storage.NewMemoryStorage()
+	stored := models.NewCase()
+	stored.ID = "a"
+	stored.CaseName = "Case a"
+	stored.Jurisdiction = "UK"
+	require.NoError(t, store.SaveCase(ctx, stored))
+
+	existing, err := storage.StoredCaseIDs(ctx, store, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"a": true}, existing)
+
+	handler := worker.NewScrapeJobHandlerWithOptions(store, registry, worker.NewCourtLevelFilter(nil), worker.ScrapeOptions{OnlyNew: true})
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction":    "UK",
+		"fetch_full_text": true,
+	})
+	require.NoError(t, handler(ctx, job))
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&source.fetched))
+	assert.Equal(t, 1, job.Result["existing"])
+	assert.Equal(t, 1, job.Result["saved"])
+	assert.Equal(t, 0, job.Result["dropped"])
+
+	a, err := store.GetCase(ctx, "a")
+	require.NoError(t, err)
+	assert.Empty(t, a.FullText)
+	b, err := store.GetCase(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "Full text of b", b.FullText)
+
+	// A forced refresh fetches stored cases too
+	job = queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction":    "UK",
+		"fetch_full_text": true,
+		"force_refresh":   true,
+	})
+	require.NoError(t, handler(ctx, job))
+
+	assert.Equal(t, int32(3), atomic.LoadInt32(&source.fetched))
+	assert.Equal(t, 0, job.Result["existing"])
+	a, err = store.GetCase(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "Full text of a", a.FullText)
+}
+
+// downScraper fails every detail fetch as if its source were down
+type downScraper struct {
+	*searchScraper
+	calls int32
+}
+
+func (s *downScraper) GetCaseByID(ctx context.Context, caseID string) (*models.Case, error) {
+	atomic.AddInt32(&s.calls, 1)
+	return nil, errors.NetworkError("service unavailable", errors.ErrNetworkFailure)
+}
+
+// TestScrapeRetryBudgetAbortsRun tests that a scrape run failing on every
+// case gives up once its shared retry budget is spent
+func TestScrapeRetryBudgetAbortsRun(t *testing.T) {
+	ctx := context.Background()
+
+	var results []*models.Case
+	for i := 0; i < 10; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%d", i)
+		c.CaseName = "Case " + c.ID
+		c.Jurisdiction = "UK"
+		results = append(results, c)
+	}
+	source := &downScraper{searchScraper: &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000)},
+		results:        results,
+	}}
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", source)
+	registry.SetConcurrencyLimits(1, nil)
+	registry.SetRetryConfig(scraper.RetryConfig{MaxRetries: 3, Budget: 5})
+
+	store := storage.NewMemoryStorage()
+	handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction":    "UK",
+		"fetch_full_text": true,
+	})
+	err := handler(ctx, job)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrRetryBudgetExhausted)
+
+	// The first case takes its 3 retries and the second spends the last 2,
+	// where 40 requests would be made without a budget
+	assert.Equal(t, int32(7), atomic.LoadInt32(&source.calls))
+	cases, err := store.ListCases(ctx, storage.CaseFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, cases)
+
+	// Within a per-request cap alone, every case is tried and kept as found
+	registry.SetRetryConfig(scraper.RetryConfig{MaxRetries: 1})
+	atomic.StoreInt32(&source.calls, 0)
+	job = queue.NewJob(queue.JobTypeScrape, map[string]interface{}{
+		"jurisdiction":    "UK",
+		"fetch_full_text": true,
+	})
+	require.NoError(t, handler(ctx, job))
+	assert.Equal(t, int32(20), atomic.LoadInt32(&source.calls))
+	assert.Equal(t, 10, job.Result["retries"])
+	assert.Equal(t, 10, job.Result["saved"])
+}
+
+// TestScrapeResultsAreArchived tests that scraped cases are archived as raw
+// JSON alongside the database save
+func TestScrapeResultsAreArchived(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	dir := t.TempDir()
+
+	scraped := models.NewCase()
+	scraped.ID = "archived"
+	scraped.CaseName = "Archived v Raw"
+	scraped.Court = "UK Supreme Court"
+	scraped.Jurisdiction = "UK"
+
+	registry := scraper.NewScraperRegistry()
+	registry.Register("uk", &searchScraper{
+		refreshScraper: &refreshScraper{BaseScraper: scraper.NewBaseScraper("uk", "UK", "https://example.org", 6000)},
+		results:        []*models.Case{scraped},
+	})
+	archiver := scraper.NewResultArchiver(scraper.NewDirArchiveSink(dir), 10)
+	registry.SetArchiver(archiver)
+	handler := worker.NewScrapeJobHandler(store, registry, worker.NewCourtLevelFilter(nil))
+
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "UK"})
+	require.NoError(t, handler(ctx, job))
+	archiver.Close()
+
+	_, err := store.GetCase(ctx, "archived")
+	require.NoError(t, err, "the case is saved to storage")
+
+	files, err := filepath.Glob(filepath.Join(dir, "uk", "archived", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	var archived models.Case
+	require.NoError(t, json.Unmarshal(data, &archived))
+	assert.Equal(t, "Archived v Raw", archived.CaseName)
+
+	t.Run("s3", func(t *testing.T) {
+		var path, auth string
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, auth = r.URL.Path, r.Header.Get("Authorization")
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		sink, err := scraper.NewS3ArchiveSink(scraper.S3ArchiveConfig{
+			Endpoint:        server.URL,
+			Bucket:          "kite",
+			AccessKeyID:     "key",
+			SecretAccessKey: "secret",
+			Prefix:          "raw/",
+		})
+		require.NoError(t, err)
+		require.NoError(t, sink.Put(ctx, "uk/archived/1.json", data))
+		assert.Equal(t, "/kite/raw/uk/archived/1.json", path)
+		assert.Equal(t, data, body)
+		assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=key/"), auth)
+	})
+}
+
+// fallbackCounter counts extracted fields by fallback level
+type fallbackCounter map[string]int
+
+func (f fallbackCounter) RecordExtractionFallback(source, field string, level int) {
+	f[fmt.Sprintf("%s/%s/%d", source, field, level)]++
+}
+
+// TestExtractionFallbackLevelRecorded tests that a field found only by a fallback selector is
+// recorded with its fallback level in case metadata, logs and metrics
+func TestExtractionFallbackLevelRecorded(t *testing.T) {
+	logger := &planRecorder{}
+	counts := fallbackCounter{}
+
+	base := scraper.NewBaseScraper("bailii", "UK", "https://example.org", 6000)
+	registry := scraper.NewScraperRegistry()
+	registry.Register("bailii", &refreshScraper{BaseScraper: base})
+	registry.SetFallbackReporting(logger, counts)
+
+	// The primary h1.case-title selector misses; the name is in the second fallback
+	fixture := `<html><body><h2>R v Fallback</h2><div class="citation">[2023] UKSC 15</div></body></html>`
+	doc, _, err := scraper.ReadDocument(strings.NewReader(fixture))
+	require.NoError(t, err)
+
+	c := models.NewCase()
+	c.URL = "https://example.org/uk/cases/UKSC/2023/15.html"
+	caseName, level := doc.Find(base.Selector("case_name", "h1.case-title")).First().Text(), 0
+	if caseName == "" {
+		caseName, level = doc.Find("blockquote > p > b").First().Text(), 1
+	}
+	if caseName == "" {
+		caseName, level = doc.Find("h2").First().Text(), 2
+	}
+	c.CaseName = strings.TrimSpace(caseName)
+	base.RecordFallback(c, "case_name", c.CaseName, level)
+	c.CaseNumber = strings.TrimSpace(doc.Find(base.Selector("citation", ".citation")).Text())
+	base.RecordFallback(c, "citation", c.CaseNumber, 0)
+	base.RecordFallback(c, "docket", "", 0)
+
+	assert.Equal(t, "R v Fallback", c.CaseName)
+	assert.Equal(t, map[string]int{"case_name": 2, "citation": 0}, c.Metadata[scraper.MetadataExtractionFallbacks],
+		"fields no selector found are left out")
+	assert.Equal(t, fallbackCounter{"bailii/case_name/2": 1, "bailii/citation/0": 1}, counts)
+	require.Len(t, logger.lines, 1, "only fallbacks past the primary selector are logged")
+	assert.Contains(t, logger.lines[0], "case_name")
+	assert.Contains(t, logger.lines[0], "fallback level 2")
+}
@@ -0,0 +1,771 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/concepts"
+	"github.com/gongahkia/kite/internal/jurisdiction"
+	"github.com/gongahkia/kite/internal/observability"
+	"github.com/gongahkia/kite/internal/search"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/validation"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var searchMetrics = observability.NewMetrics()
+
+// seedFacetCases saves cases spread across jurisdictions, courts and years
+func seedFacetCases(t *testing.T, ctx context.Context, store storage.Storage) {
+	courts := []struct {
+		jurisdiction string
+		court        string
+	}{
+		{"UK", "UK Supreme Court"},
+		{"UK", "Court of Appeal"},
+		{"Singapore", "Court of Appeal"},
+	}
+
+	for i := 0; i < 12; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("facet-%d", i)
+		c.CaseName = fmt.Sprintf("Contract dispute %d", i)
+		c.Summary = "A case about contract law"
+		c.Jurisdiction = courts[i%len(courts)].jurisdiction
+		c.Court = courts[i%len(courts)].court
+		decided := time.Date(2018+i%2, time.March, 1, 0, 0, 0, 0, time.UTC)
+		c.DecisionDate = &decided
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+}
+
+// TestFacetsOnlySearch tests that facets-only queries match full-search facets without returning results
+func TestFacetsOnlySearch(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "facets.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	seedFacetCases(t, ctx, store)
+
+	engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+	fields := []string{"jurisdiction", "court", "year"}
+
+	full, err := engine.Search(ctx, search.NewQuery().
+		FullText("contract").
+		Limit(100).
+		WithFacets(fields...).
+		Build())
+	require.NoError(t, err)
+	require.Len(t, full.Results, 12)
+
+	facetsOnly, err := engine.Search(ctx, search.NewQuery().
+		FullText("contract").
+		WithFacets(fields...).
+		FacetsOnly().
+		Build())
+	require.NoError(t, err)
+
+	assert.Empty(t, facetsOnly.Results)
+	assert.Equal(t, full.TotalHits, facetsOnly.TotalHits)
+	for _, field := range fields {
+		require.Contains(t, facetsOnly.Facets, field)
+		assert.ElementsMatch(t, full.Facets[field].Values, facetsOnly.Facets[field].Values, field)
+	}
+}
+
+// TestFacetsCountBeyondPage tests that facets count every matching case, not just the returned page
+func TestFacetsCountBeyondPage(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "facets.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			seedFacetCases(t, ctx, store)
+
+			engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+			resp, err := engine.Search(ctx, search.NewQuery().
+				FullText("contract").
+				Limit(5).
+				WithFacets("jurisdiction", "court").
+				Build())
+			require.NoError(t, err)
+			assert.Len(t, resp.Results, 5)
+
+			counts := make(map[string]int)
+			for _, v := range resp.Facets["jurisdiction"].Values {
+				counts[v.Value] = v.Count
+			}
+			assert.Equal(t, map[string]int{"UK": 8, "Singapore": 4}, counts)
+
+			counts = make(map[string]int)
+			for _, v := range resp.Facets["court"].Values {
+				counts[v.Value] = v.Count
+			}
+			assert.Equal(t, map[string]int{"UK Supreme Court": 4, "Court of Appeal": 8}, counts)
+		})
+	}
+}
+
+// TestFacetsFollowSearchFilters tests that facets count the same cases the
+// search returns when filtering on fields beyond jurisdiction and court
+func TestFacetsFollowSearchFilters(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "facets.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			seedFacetCases(t, ctx, store)
+
+			engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+			resp, err := engine.Search(ctx, search.NewQuery().
+				FullText("contract").
+				FilterByDateRange(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)).
+				WithFacets("jurisdiction", "year").
+				Build())
+			require.NoError(t, err)
+			assert.Len(t, resp.Results, 6)
+
+			counts := make(map[string]int)
+			for _, v := range resp.Facets["jurisdiction"].Values {
+				counts[v.Value] = v.Count
+			}
+			assert.Equal(t, map[string]int{"UK": 4, "Singapore": 2}, counts)
+			require.Len(t, resp.Facets["year"].Values, 1)
+			assert.Equal(t, "2019", resp.Facets["year"].Values[0].Value)
+		})
+	}
+}
+
+// TestTimelineCountsPerYear tests that timeline aggregation counts matching cases per decision year and quarter
+func TestTimelineCountsPerYear(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "timeline.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	decided := []time.Time{
+		time.Date(2018, time.January, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.May, 2, 0, 0, 0, 0, time.UTC),
+		time.Date(2018, time.December, 31, 0, 0, 0, 0, time.UTC),
+		time.Date(2019, time.June, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.April, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, time.October, 20, 0, 0, 0, 0, time.UTC),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i, d := range decided {
+				c := models.NewCase()
+				c.ID = fmt.Sprintf("timeline-%d", i)
+				c.CaseName = fmt.Sprintf("Contract dispute %d", i)
+				decision := d
+				c.DecisionDate = &decision
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			// Undated cases and cases not matching the query are not counted
+			undated := models.NewCase()
+			undated.ID = "timeline-undated"
+			undated.CaseName = "Contract dispute undated"
+			require.NoError(t, store.SaveCase(ctx, undated))
+
+			other := models.NewCase()
+			other.ID = "timeline-other"
+			other.CaseName = "Negligence claim"
+			otherDate := time.Date(2020, time.March, 1, 0, 0, 0, 0, time.UTC)
+			other.DecisionDate = &otherDate
+			require.NoError(t, store.SaveCase(ctx, other))
+
+			engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+
+			resp, err := engine.Search(ctx, search.NewQuery().
+				FullText("contract").
+				WithTimeline(storage.TimelineYear).
+				FacetsOnly().
+				Build())
+			require.NoError(t, err)
+			assert.Empty(t, resp.Results)
+			assert.Equal(t, 7, resp.TotalHits)
+			assert.Equal(t, []storage.TimelineBucket{
+				{Period: "2018", Count: 3},
+				{Period: "2019", Count: 1},
+				{Period: "2021", Count: 2},
+			}, resp.Timeline)
+
+			// The timeline covers every match, alongside a single page of documents
+			resp, err = engine.Search(ctx, search.NewQuery().
+				FullText("contract").
+				Limit(2).
+				WithTimeline(storage.TimelineQuarter).
+				Build())
+			require.NoError(t, err)
+			assert.Len(t, resp.Results, 2)
+			assert.Equal(t, []storage.TimelineBucket{
+				{Period: "2018-Q1", Count: 1},
+				{Period: "2018-Q2", Count: 1},
+				{Period: "2018-Q4", Count: 1},
+				{Period: "2019-Q2", Count: 1},
+				{Period: "2021-Q2", Count: 1},
+				{Period: "2021-Q4", Count: 1},
+			}, resp.Timeline)
+		})
+	}
+}
+
+// TestSuggestCaseNamesPrefix tests that autocomplete finds every prefix match in a large seeded set
+func TestSuggestCaseNamesPrefix(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "suggest.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 500; i++ {
+				c := models.NewCase()
+				c.ID = fmt.Sprintf("suggest-%d", i)
+				c.CaseName = fmt.Sprintf("Jones v Party %d", i)
+				if i%50 == 0 {
+					c.CaseName = fmt.Sprintf("Smith v Party %d", i)
+				}
+				c.LegalConcepts = []string{"contract law", fmt.Sprintf("concept %d", i%3)}
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			names, err := store.SuggestCaseNames(ctx, "smith", storage.CaseFilter{}, 100)
+			require.NoError(t, err)
+			assert.Len(t, names, 10)
+			for _, n := range names {
+				assert.True(t, strings.HasPrefix(n, "Smith v Party"), n)
+			}
+
+			names, err = store.SuggestCaseNames(ctx, "Jones", storage.CaseFilter{}, 20)
+			require.NoError(t, err)
+			assert.Len(t, names, 20)
+
+			names, err = store.SuggestCaseNames(ctx, "Party", storage.CaseFilter{}, 20)
+			require.NoError(t, err)
+			assert.Empty(t, names, "only prefixes match")
+
+			concepts, err := store.SuggestConcepts(ctx, "con", storage.CaseFilter{}, 10)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"contract law", "concept 0", "concept 1", "concept 2"}, concepts)
+
+			suggestions, err := search.NewSuggestionEngine(store).Suggest(ctx, "Smith v Party 45", 5)
+			require.NoError(t, err)
+			require.NotEmpty(t, suggestions)
+			assert.Equal(t, "Smith v Party 450", suggestions[0].Text)
+		})
+	}
+}
+
+// TestRankingConfigChangesOrder tests that engine and per-query ranking weights reorder results
+func TestRankingConfigChangesOrder(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	byName := models.NewCase()
+	byName.ID = "by-name"
+	byName.CaseName = "Negligence Appeal"
+	byName.Summary = "A dispute over a fence"
+	require.NoError(t, store.SaveCase(ctx, byName))
+
+	byConcept := models.NewCase()
+	byConcept.ID = "by-concept"
+	byConcept.CaseName = "Smith v Jones"
+	byConcept.Summary = "A claim in negligence"
+	byConcept.LegalConcepts = []string{"negligence"}
+	require.NoError(t, store.SaveCase(ctx, byConcept))
+
+	order := func(resp *search.SearchResponse) []string {
+		ids := make([]string, len(resp.Results))
+		for i, r := range resp.Results {
+			ids[i] = r.Case.ID
+		}
+		return ids
+	}
+	query := func() *search.QueryBuilder {
+		return search.NewQuery().FullText("negligence").SortByRelevance()
+	}
+	logger := observability.NewLogger("error", "json")
+
+	// Defaults: summary + concept (2.0 + 2.5) outrank case name (3.0)
+	defaults := search.NewSearchEngine(store, logger, searchMetrics, search.DefaultRankingConfig())
+	resp, err := defaults.Search(ctx, query().Build())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"by-concept", "by-name"}, order(resp))
+
+	nameHeavy := search.DefaultRankingConfig()
+	nameHeavy.CaseNameWeight = 10
+
+	custom := search.NewSearchEngine(store, logger, searchMetrics, nameHeavy)
+	resp, err = custom.Search(ctx, query().Build())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"by-name", "by-concept"}, order(resp))
+
+	// A per-query override takes precedence over the engine's weights
+	resp, err = defaults.Search(ctx, query().WithRanking(nameHeavy).Build())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"by-name", "by-concept"}, order(resp))
+
+	nameHeavy.ConceptWeight = -1
+	_, err = defaults.Search(ctx, query().WithRanking(nameHeavy).Build())
+	assert.Error(t, err)
+}
+
+// TestConceptImportanceBoostsRanking tests that a case matching a
+// high-importance concept outranks one matching a low-importance concept
+func TestConceptImportanceBoostsRanking(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	// Negligence is rated 10 in the taxonomy, easement 6
+	for id, concept := range map[string]string{"important": "Negligence", "minor": "Easement"} {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = "Smith v Jones"
+		c.Summary = "A negligence easement dispute"
+		c.LegalConcepts = []string{concept}
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	scores := func(engine *search.SearchEngine) (map[string]float64, []string) {
+		resp, err := engine.Search(ctx, search.NewQuery().FullText("negligence easement").SortByRelevance().Build())
+		require.NoError(t, err)
+		byID := make(map[string]float64)
+		order := make([]string, len(resp.Results))
+		for i, r := range resp.Results {
+			byID[r.Case.ID] = r.Score
+			order[i] = r.Case.ID
+		}
+		return byID, order
+	}
+	logger := observability.NewLogger("error", "json")
+	engine := search.NewSearchEngine(store, logger, searchMetrics, search.DefaultRankingConfig())
+
+	// Off by default: both cases score alike
+	plain, _ := scores(engine)
+	require.Len(t, plain, 2)
+	assert.InDelta(t, plain["important"], plain["minor"], 1e-9)
+
+	boost := search.ConceptBoostConfig{MinImportance: 8, Weight: 0.5, Taxonomy: concepts.NewTaxonomy()}
+	require.NoError(t, boost.Validate())
+	engine.SetConceptBoost(boost)
+
+	boosted, order := scores(engine)
+	assert.Equal(t, []string{"important", "minor"}, order)
+	assert.InDelta(t, plain["important"]*1.5, boosted["important"], 1e-9)
+	assert.InDelta(t, plain["minor"], boosted["minor"], 1e-9)
+
+	boost.MinImportance = 11
+	assert.Error(t, boost.Validate())
+}
+
+// TestBM25RareTermsScoreHigher tests that rarer query terms contribute more to the BM25 score
+func TestBM25RareTermsScoreHigher(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	for i := 0; i < 10; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("bm25-%d", i)
+		c.CaseName = fmt.Sprintf("Contract claim %d", i)
+		c.Summary = "A dispute about contract terms"
+		if i == 0 {
+			c.Summary = "A dispute about contract terms and estoppel"
+		}
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	stats, err := store.TermStats(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 10, stats.DocCount())
+	assert.Equal(t, 10, stats.DocFreq("contract"))
+	assert.Equal(t, 1, stats.DocFreq("estoppel"))
+
+	scorer := search.DefaultBM25Scorer()
+	ranking := search.DefaultRankingConfig()
+	assert.Greater(t, scorer.IDF(stats, "estoppel"), scorer.IDF(stats, "contract"))
+
+	target, err := store.GetCase(ctx, "bm25-0")
+	require.NoError(t, err)
+	rare := scorer.Score(stats, target, "estoppel", ranking)
+	common := scorer.Score(stats, target, "contract", ranking)
+	assert.Greater(t, rare, common, "a term in one case outweighs a term in every case")
+
+	// Stats are maintained on save, so the term loses weight as it spreads
+	before := scorer.IDF(stats, "estoppel")
+	for i := 0; i < 5; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("bm25-estoppel-%d", i)
+		c.CaseName = "Promissory estoppel"
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+	assert.Equal(t, 6, stats.DocFreq("estoppel"))
+	assert.Less(t, scorer.IDF(stats, "estoppel"), before)
+
+	require.NoError(t, store.DeleteCase(ctx, "bm25-estoppel-0"))
+	assert.Equal(t, 5, stats.DocFreq("estoppel"))
+}
+
+// TestBM25RerankSQLResults tests that SQL search results are re-ranked by term rarity
+func TestBM25RerankSQLResults(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bm25.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	// Seed before the stats are first loaded so they come from the database
+	for i := 0; i < 8; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("common-%d", i)
+		c.CaseName = fmt.Sprintf("Negligence claim %d", i)
+		c.Summary = "Negligence in a road traffic accident"
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+
+	rare := models.NewCase()
+	rare.ID = "rare"
+	rare.CaseName = "Negligence and novus actus"
+	rare.Summary = "Whether a novus actus interveniens broke the chain of causation"
+	require.NoError(t, store.SaveCase(ctx, rare))
+
+	stats, err := store.TermStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 9, stats.DocCount())
+	assert.Equal(t, 9, stats.DocFreq("negligence"))
+	assert.Equal(t, 1, stats.DocFreq("novus"))
+
+	engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+	resp, err := engine.Search(ctx, search.NewQuery().FullText("negligence").SortByRelevance().Limit(20).Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 9)
+
+	scorer := search.DefaultBM25Scorer()
+	ranking := search.DefaultRankingConfig()
+	assert.Greater(t, scorer.Score(stats, rare, "novus", ranking), scorer.Score(stats, rare, "negligence", ranking))
+
+	// New saves are reflected without reseeding
+	extra := models.NewCase()
+	extra.ID = "extra"
+	extra.CaseName = "Novus actus again"
+	require.NoError(t, store.SaveCase(ctx, extra))
+	assert.Equal(t, 2, stats.DocFreq("novus"))
+}
+
+// TestTermStatsRefreshSeesOtherWriters tests that SQLite term stats are recomputed
+// to count cases written through another connection
+func TestTermStatsRefreshSeesOtherWriters(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "terms.db")
+
+	reader, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer reader.Close()
+	writer, err := storage.NewSQLiteStorage(path)
+	require.NoError(t, err)
+	defer writer.Close()
+
+	reader.SetTermStatsRefresh(50 * time.Millisecond)
+
+	first := models.NewCase()
+	first.ID = "estoppel-0"
+	first.CaseName = "Promissory estoppel"
+	require.NoError(t, reader.SaveCase(ctx, first))
+
+	stats, err := reader.TermStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.DocFreq("estoppel"))
+
+	second := models.NewCase()
+	second.ID = "estoppel-1"
+	second.CaseName = "Proprietary estoppel"
+	require.NoError(t, writer.SaveCase(ctx, second))
+
+	// Stale stats are served while the refresh runs in the background
+	assert.Eventually(t, func() bool {
+		stats, err := reader.TermStats(ctx)
+		return err == nil && stats.DocFreq("estoppel") == 2
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// TestSearchRejectsOversizedWindow tests that the engine rejects pages beyond its result window
+func TestSearchRejectsOversizedWindow(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	seedFacetCases(t, ctx, store)
+
+	engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+	engine.SetResultWindow(storage.ResultWindow{MaxLimit: 50, MaxOffset: 100})
+
+	_, err := engine.Search(ctx, search.NewQuery().FullText("contract").Limit(50).Offset(100).Build())
+	assert.NoError(t, err)
+
+	_, err = engine.Search(ctx, search.NewQuery().FullText("contract").Limit(1000000).Build())
+	assert.Error(t, err)
+
+	_, err = engine.Search(ctx, search.NewQuery().FullText("contract").Limit(10).Offset(5000000).Build())
+	assert.Error(t, err)
+}
+
+// duplicatingStorage returns every search match twice, as when results are
+// merged from overlapping sources
+type duplicatingStorage struct {
+	storage.Storage
+}
+
+func (ds duplicatingStorage) SearchCases(ctx context.Context, query storage.SearchQuery) ([]*models.Case, error) {
+	cases, err := ds.Storage.SearchCases(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return append(cases, cases...), nil
+}
+
+// TestSearchCollapsesDuplicates tests that duplicate matches collapse into their highest-scoring result
+func TestSearchCollapsesDuplicates(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	// The same decision scraped from two sources under different IDs
+	scraped := models.NewCase()
+	scraped.ID = "source-a-1"
+	scraped.CaseNumber = "[2020] SGCA 1"
+	scraped.Court = "Court of Appeal"
+	scraped.CaseName = "Negligence appeal"
+	require.NoError(t, store.SaveCase(ctx, scraped))
+
+	mirrored := models.NewCase()
+	mirrored.ID = "source-b-1"
+	mirrored.CaseNumber = "[2020] SGCA 1"
+	mirrored.Court = "Court of Appeal"
+	mirrored.CaseName = "Tan v Lim"
+	mirrored.Summary = "A claim in negligence"
+	require.NoError(t, store.SaveCase(ctx, mirrored))
+
+	other := models.NewCase()
+	other.ID = "other"
+	other.CaseNumber = "[2021] SGCA 7"
+	other.Court = "Court of Appeal"
+	other.Summary = "Contributory negligence"
+	require.NoError(t, store.SaveCase(ctx, other))
+
+	ids := func(resp *search.SearchResponse) []string {
+		ids := make([]string, len(resp.Results))
+		for i, r := range resp.Results {
+			ids[i] = r.Case.ID
+		}
+		return ids
+	}
+	query := search.NewQuery().FullText("negligence").SortByRelevance().Build()
+	engine := search.NewSearchEngine(duplicatingStorage{store}, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+
+	// By default each case appears once
+	resp, err := engine.Search(ctx, query)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"source-a-1", "source-b-1", "other"}, ids(resp))
+	assert.Equal(t, 3, resp.TotalHits)
+
+	// A content hash also collapses the copies, keeping the better match
+	contentHash := validation.HashTemplate{Name: "court_case_number", Fields: []string{"court", "case_number"}}
+	engine.SetDedup(search.DedupConfig{Enabled: true, ContentHash: &contentHash})
+	resp, err = engine.Search(ctx, query)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"source-a-1", "other"}, ids(resp))
+
+	engine.SetDedup(search.DedupConfig{})
+	resp, err = engine.Search(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, resp.Results, 6)
+}
+
+// termlessStorage hides the wrapped backend's term statistics, so the engine
+// falls back to field-match scoring
+type termlessStorage struct {
+	storage.Storage
+}
+
+// TestSearchExplainsScores tests that explanations list the terms that made up each score, and only when asked for
+func TestSearchExplainsScores(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "explained"
+	c.CaseName = "Negligence Appeal"
+	c.LegalConcepts = []string{"Negligence"}
+	c.QualityScore = 0.5
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	logger := observability.NewLogger("error", "json")
+	query := func() *search.QueryBuilder {
+		return search.NewQuery().FullText("negligence").SortByRelevance()
+	}
+
+	// Off by default
+	engine := search.NewSearchEngine(termlessStorage{store}, logger, searchMetrics, search.DefaultRankingConfig())
+	resp, err := engine.Search(ctx, query().Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Empty(t, resp.Results[0].Explain)
+
+	// Field matches: (3.0 + 2.5) / 1 term, boosted ×1.5
+	resp, err = engine.Search(ctx, query().WithExplain().Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	result := resp.Results[0]
+	assert.InDelta(t, 8.25, result.Score, 1e-9)
+	assert.Equal(t, `case name matches "negligence" +3.0; concept "Negligence" matches "negligence" +2.5; ÷ 1 query terms; quality boost ×1.50; = 8.250`, result.Explain)
+
+	// Under BM25 each matching term is broken down with the fields it was found in
+	engine = search.NewSearchEngine(store, logger, searchMetrics, search.DefaultRankingConfig())
+	resp, err = engine.Search(ctx, query().WithExplain().Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	result = resp.Results[0]
+	assert.Contains(t, result.Explain, "BM25 over 1 cases")
+	assert.Contains(t, result.Explain, `"negligence" in case name +3.0, concept +2.5: tf 5.5`)
+	assert.Contains(t, result.Explain, "quality boost ×1.50")
+	assert.True(t, strings.HasSuffix(result.Explain, fmt.Sprintf("= %.3f", result.Score)), result.Explain)
+}
+
+// TestSearchSnippetLength tests that highlights honour the requested snippet
+// length and count
+func TestSearchSnippetLength(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "snippets"
+	c.CaseName = "Negligence Appeal"
+	c.Summary = strings.Repeat("background facts ", 20) + "the negligence claim " + strings.Repeat("and further reasons ", 20)
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+	snippetText := func(h string) string {
+		h = strings.NewReplacer("<em>", "", "</em>", "").Replace(h)
+		return strings.TrimSuffix(strings.TrimPrefix(h, "..."), "...")
+	}
+
+	resp, err := engine.Search(ctx, search.NewQuery().FullText("negligence").Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	require.Len(t, resp.Results[0].Highlights, 2)
+	assert.Len(t, snippetText(resp.Results[0].Highlights[1]), search.DefaultSnippetLength)
+
+	resp, err = engine.Search(ctx, search.NewQuery().FullText("negligence").WithSnippetLength(40).Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	summary := resp.Results[0].Highlights[1]
+	assert.Len(t, snippetText(summary), 40)
+	assert.Contains(t, summary, "<em>negligence</em>")
+
+	resp, err = engine.Search(ctx, search.NewQuery().FullText("negligence").WithMaxHighlights(1).Build())
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 1)
+	assert.Len(t, resp.Results[0].Highlights, 1)
+
+	_, err = engine.Search(ctx, search.NewQuery().FullText("negligence").WithSnippetLength(search.MaxSnippetLength+1).Build())
+	assert.Error(t, err)
+	_, err = engine.Search(ctx, search.NewQuery().FullText("negligence").WithMaxHighlights(-1).Build())
+	assert.Error(t, err)
+}
+
+// TestSearchOrdersByAuthority tests that authority ordering puts higher
+// courts first and newer decisions first within a court level, in search
+// results and across listed pages
+func TestSearchOrdersByAuthority(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "authority.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	date := func(year int) *time.Time {
+		d := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		return &d
+	}
+	courts := jurisdiction.NewCourtHierarchy()
+	want := []string{"supreme-new", "supreme-old", "appeal", "trial"}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for _, c := range []struct {
+				id    string
+				court string
+				year  int
+			}{
+				{"trial", "High Court of Justice", 2023},
+				{"supreme-old", "UK Supreme Court", 2001},
+				{"appeal", "Court of Appeal (England & Wales)", 2020},
+				{"supreme-new", "UK Supreme Court", 2019},
+			} {
+				cs := models.NewCase()
+				cs.ID = c.id
+				cs.CaseName = "Negligence Claim"
+				cs.Summary = "A claim in negligence"
+				cs.Court = c.court
+				cs.CourtLevel = courts.GetCourtLevel(c.court)
+				cs.Jurisdiction = "UK"
+				cs.DecisionDate = date(c.year)
+				require.NoError(t, store.SaveCase(ctx, cs))
+			}
+
+			engine := search.NewSearchEngine(store, observability.NewLogger("error", "json"), searchMetrics, search.DefaultRankingConfig())
+			resp, err := engine.Search(ctx, search.NewQuery().FullText("negligence").SortByAuthority().Build())
+			require.NoError(t, err)
+			ids := make([]string, len(resp.Results))
+			for i, r := range resp.Results {
+				ids[i] = r.Case.ID
+			}
+			assert.Equal(t, want, ids)
+
+			// Pages continue the ordering
+			page, err := store.ListCases(ctx, storage.CaseFilter{OrderBy: storage.OrderByAuthority, Limit: 2, Offset: 1})
+			require.NoError(t, err)
+			require.Len(t, page, 2)
+			assert.Equal(t, want[1], page[0].ID)
+			assert.Equal(t, want[2], page[1].ID)
+		})
+	}
+}
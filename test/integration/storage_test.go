@@ -0,0 +1,1624 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/clock"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/pkg/errors"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// TestMongoClientOptions tests that read/write concerns and timeouts are applied to the client options
+func TestMongoClientOptions(t *testing.T) {
+	cfg := storage.DefaultMongoConfig()
+	cfg.ReadPreference = "secondaryPreferred"
+	cfg.WriteTimeout = 2 * time.Second
+	cfg.ServerSelectionTimeout = 15 * time.Second
+
+	opts, err := cfg.ClientOptions("mongodb://localhost:27017")
+	require.NoError(t, err)
+
+	assert.Equal(t, readpref.SecondaryPreferredMode, opts.ReadPreference.Mode())
+	assert.Equal(t, readconcern.Majority().Level, opts.ReadConcern.Level)
+	require.NotNil(t, opts.WriteConcern)
+	assert.Equal(t, "majority", opts.WriteConcern.W)
+	assert.Equal(t, 2*time.Second, opts.WriteConcern.WTimeout)
+	require.NotNil(t, opts.WriteConcern.Journal)
+	assert.True(t, *opts.WriteConcern.Journal)
+	assert.Equal(t, 15*time.Second, *opts.ServerSelectionTimeout)
+	assert.Equal(t, 10*time.Second, *opts.ConnectTimeout)
+	require.NotNil(t, opts.BSONOptions)
+	assert.True(t, opts.BSONOptions.UseJSONStructTags, "documents must use the field names queries filter on")
+}
+
+// TestMongoClientOptionsPrecedence tests that configured options override the URI
+// and that defaults only fill in options neither sets
+func TestMongoClientOptionsPrecedence(t *testing.T) {
+	uri := "mongodb://localhost:27017/?readPreference=secondary&w=2&connectTimeoutMS=3000"
+
+	opts, err := storage.MongoConfig{ServerSelectionTimeout: 15 * time.Second}.ClientOptions(uri)
+	require.NoError(t, err)
+
+	// From the URI
+	assert.Equal(t, readpref.SecondaryMode, opts.ReadPreference.Mode())
+	require.NotNil(t, opts.WriteConcern)
+	assert.Equal(t, 2, opts.WriteConcern.W)
+	assert.Nil(t, opts.WriteConcern.Journal, "a URI write concern is not given the journal default")
+	assert.Equal(t, 3*time.Second, *opts.ConnectTimeout)
+
+	// From the configuration
+	assert.Equal(t, 15*time.Second, *opts.ServerSelectionTimeout)
+
+	// Defaults
+	assert.Equal(t, readconcern.Majority().Level, opts.ReadConcern.Level)
+	assert.Equal(t, 30*time.Second, *opts.SocketTimeout)
+
+	// The configuration overrides the URI
+	opts, err = storage.MongoConfig{ReadPreference: "nearest", WriteConcern: "majority"}.ClientOptions(uri)
+	require.NoError(t, err)
+	assert.Equal(t, readpref.NearestMode, opts.ReadPreference.Mode())
+	assert.Equal(t, "majority", opts.WriteConcern.W)
+
+	// A URI without a write concern gets the default one
+	opts, err = storage.MongoConfig{}.ClientOptions("mongodb://localhost:27017")
+	require.NoError(t, err)
+	assert.Equal(t, "majority", opts.WriteConcern.W)
+	assert.Equal(t, 5*time.Second, opts.WriteConcern.WTimeout)
+	require.NotNil(t, opts.WriteConcern.Journal)
+	assert.True(t, *opts.WriteConcern.Journal)
+}
+
+// TestMongoConfigConflicts tests that conflicting Mongo settings are rejected
+func TestMongoConfigConflicts(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*storage.MongoConfig)
+	}{
+		{"linearizable on secondary", func(c *storage.MongoConfig) {
+			c.ReadConcern = "linearizable"
+			c.ReadPreference = "secondary"
+		}},
+		{"journal without acknowledgement", func(c *storage.MongoConfig) { c.WriteConcern = "0" }},
+		{"unknown read preference", func(c *storage.MongoConfig) { c.ReadPreference = "fastest" }},
+		{"invalid write concern", func(c *storage.MongoConfig) { c.WriteConcern = "most" }},
+		{"negative timeout", func(c *storage.MongoConfig) { c.SocketTimeout = -time.Second }},
+		{"unsupported text language", func(c *storage.MongoConfig) { c.TextLanguage = "klingon" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := storage.DefaultMongoConfig()
+			tt.modify(&cfg)
+			assert.Error(t, cfg.Validate())
+		})
+	}
+
+	assert.NoError(t, storage.DefaultMongoConfig().Validate())
+}
+
+// TestMongoTextIndexLanguage tests that the case text index is created with the configured languages
+// and rebuilt only when they change
+func TestMongoTextIndexLanguage(t *testing.T) {
+	cfg := storage.DefaultMongoConfig()
+	cfg.TextLanguage = "French"
+	cfg.TextLanguageOverride = "lang"
+
+	model := cfg.TextIndexModel()
+	require.NotNil(t, model.Options)
+	require.NotNil(t, model.Options.DefaultLanguage)
+	assert.Equal(t, "french", *model.Options.DefaultLanguage)
+	require.NotNil(t, model.Options.LanguageOverride)
+	assert.Equal(t, "lang", *model.Options.LanguageOverride)
+
+	defaults := storage.DefaultMongoConfig().TextIndexModel()
+	assert.Equal(t, "english", *defaults.Options.DefaultLanguage)
+	assert.Equal(t, "language", *defaults.Options.LanguageOverride)
+
+	existing := bson.M{
+		"name":              *defaults.Options.Name,
+		"key":               bson.M{"_fts": "text", "_ftsx": int32(1)},
+		"default_language":  "english",
+		"language_override": "language",
+	}
+	assert.True(t, storage.TextIndexMatches(existing, defaults))
+	assert.False(t, storage.TextIndexMatches(existing, model), "a changed language rebuilds the index")
+
+	// indexes created before the language was configurable have the generated name
+	existing["name"] = "case_name_text_summary_text_full_text_text"
+	assert.False(t, storage.TextIndexMatches(existing, defaults))
+}
+
+// TestMongoCitationRoundTrip tests that citation IDs assigned on save resolve on get and list
+func TestMongoCitationRoundTrip(t *testing.T) {
+	uri := os.Getenv("KITE_TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("Integration test requires KITE_TEST_MONGO_URI")
+	}
+
+	ctx := context.Background()
+	dbName := "kite_test_" + time.Now().Format("20060102150405")
+
+	store, err := storage.NewMongoStorage(uri, dbName)
+	require.NoError(t, err)
+	defer store.Close()
+
+	// Two citations saved within the same second must get distinct IDs
+	first := models.NewCitation("[2023] UKSC 15", models.CitationFormatNeutral)
+	first.CitingCaseID = "case-a"
+	second := models.NewCitation("[2023] UKSC 16", models.CitationFormatNeutral)
+	second.CitingCaseID = "case-a"
+
+	require.NoError(t, store.SaveCitation(ctx, first))
+	require.NoError(t, store.SaveCitation(ctx, second))
+	require.NotEmpty(t, first.ID)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	got, err := store.GetCitation(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, first.RawCitation, got.RawCitation)
+
+	// Updating by ID must not create a new document
+	got.TreatmentType = "followed"
+	require.NoError(t, store.SaveCitation(ctx, got))
+
+	updated, err := store.GetCitation(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "followed", updated.TreatmentType)
+
+	listed, err := store.ListCitations(ctx, storage.CitationFilter{})
+	require.NoError(t, err)
+	ids := make([]string, 0, len(listed))
+	for _, c := range listed {
+		ids = append(ids, c.ID)
+	}
+	assert.ElementsMatch(t, []string{first.ID, second.ID}, ids)
+}
+
+// planRecorder captures debug log lines
+type planRecorder struct {
+	lines []string
+}
+
+func (r *planRecorder) Debugf(format string, args ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+// TestSlowQueryExplainLogsPlan tests that SQLite query plans are logged only for queries over the threshold
+func TestSlowQueryExplainLogsPlan(t *testing.T) {
+	ctx := context.Background()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "explain.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	c := models.NewCase()
+	c.ID = "explain-1"
+	c.CaseName = "Plan v Query"
+	c.Jurisdiction = "UK"
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	// Fast query: no plan
+	recorder := &planRecorder{}
+	store.SetSlowQueryExplainer(storage.NewSlowQueryExplainer(storage.ExplainConfig{
+		Enabled:   true,
+		Threshold: time.Hour,
+		Logger:    recorder,
+	}))
+	_, err = store.ListCases(ctx, storage.CaseFilter{Jurisdiction: "UK"})
+	require.NoError(t, err)
+	assert.Empty(t, recorder.lines)
+
+	// Every query is over a zero threshold
+	store.SetSlowQueryExplainer(storage.NewSlowQueryExplainer(storage.ExplainConfig{
+		Enabled: true,
+		Logger:  recorder,
+	}))
+	_, err = store.ListCases(ctx, storage.CaseFilter{Jurisdiction: "UK"})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.lines, 1)
+	assert.Contains(t, recorder.lines[0], "sqlite.ListCases")
+	assert.Contains(t, recorder.lines[0], "cases")
+	assert.NotContains(t, recorder.lines[0], "explain failed")
+
+	// Disabled by default: nil explainer is a no-op
+	disabled := storage.NewSlowQueryExplainer(storage.ExplainConfig{Logger: recorder})
+	assert.Nil(t, disabled)
+	store.SetSlowQueryExplainer(disabled)
+	_, err = store.ListCases(ctx, storage.CaseFilter{Jurisdiction: "UK"})
+	require.NoError(t, err)
+	assert.Len(t, recorder.lines, 1)
+}
+
+// TestSlowQueryExplainOutlivesRequest tests that the plan is still explained once the query's context is done, within a bound
+func TestSlowQueryExplainOutlivesRequest(t *testing.T) {
+	recorder := &planRecorder{}
+	explainer := storage.NewSlowQueryExplainer(storage.ExplainConfig{
+		Enabled: true,
+		Timeout: time.Second,
+		Logger:  recorder,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	explainer.Observe(ctx, "sqlite.ListCases", time.Now(), func(ctx context.Context) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok, "explain must be bounded")
+		assert.WithinDuration(t, time.Now().Add(time.Second), deadline, time.Second)
+		return "SCAN cases", nil
+	})
+
+	require.Len(t, recorder.lines, 1)
+	assert.Contains(t, recorder.lines[0], "SCAN cases")
+}
+
+// TestDeleteCasesByFilter tests that bulk deletes remove only the filtered cases
+func TestDeleteCasesByFilter(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "delete.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for i, j := range []string{"Singapore", "Singapore", "UK", "Australia"} {
+				c := models.NewCase()
+				c.ID = fmt.Sprintf("delete-%d", i)
+				c.CaseName = c.ID
+				c.Jurisdiction = j
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			// delete-0 cites delete-2 and delete-3 cites delete-1
+			for _, link := range [][2]string{{"delete-0", "delete-2"}, {"delete-3", "delete-1"}} {
+				cit := models.NewCitation("["+link[1]+"]", models.CitationFormatNeutral)
+				cit.CitingCaseID = link[0]
+				cit.CaseID = link[1]
+				require.NoError(t, store.SaveCitation(ctx, cit))
+			}
+
+			_, err := store.DeleteCasesByFilter(ctx, storage.CaseFilter{Limit: 10}, storage.DeleteOptions{})
+			assert.ErrorIs(t, err, storage.ErrEmptyDeleteFilter)
+
+			if name != "memory" {
+				_, err = store.DeleteCasesByFilter(ctx, storage.CaseFilter{MinQuality: 0.5}, storage.DeleteOptions{})
+				assert.ErrorIs(t, err, storage.ErrUnsupportedDeleteFilter)
+			}
+
+			deleted, err := store.DeleteCasesByFilter(ctx, storage.CaseFilter{Jurisdiction: "Singapore"}, storage.DeleteOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), deleted)
+
+			// The deleted case's citation is removed; the one pointing at a deleted case is kept
+			citations, err := store.ListCitations(ctx, storage.CitationFilter{})
+			require.NoError(t, err)
+			require.Len(t, citations, 1)
+			assert.Equal(t, "delete-3", citations[0].CitingCaseID)
+
+			remaining, err := store.ListCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			ids := make([]string, 0, len(remaining))
+			for _, c := range remaining {
+				ids = append(ids, c.ID)
+			}
+			assert.ElementsMatch(t, []string{"delete-2", "delete-3"}, ids)
+
+			deleted, err = store.DeleteCasesByFilter(ctx, storage.CaseFilter{}, storage.DeleteOptions{Force: true})
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), deleted)
+		})
+	}
+}
+
+// TestMinFullTextLengthExcludesStubs tests that stub cases are filtered out of listings, counts and searches
+func TestMinFullTextLengthExcludesStubs(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "stubs.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	texts := map[string]string{
+		"stub-empty":   "",
+		"stub-short":   "Appeal dismissed.",
+		"full-accents": strings.Repeat("é", 40), // counted in characters, not bytes
+		"full-text":    strings.Repeat("The contract was validly formed. ", 5),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for id, text := range texts {
+				c := models.NewCase()
+				c.ID = id
+				c.CaseName = "Contract dispute " + id
+				c.Jurisdiction = "Singapore"
+				c.FullText = text
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(cases []*models.Case) []string {
+				out := make([]string, 0, len(cases))
+				for _, c := range cases {
+					out = append(out, c.ID)
+				}
+				return out
+			}
+
+			all, err := store.ListCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Len(t, all, 4, "no threshold keeps stubs")
+
+			filter := storage.CaseFilter{MinFullTextLength: 40}
+			listed, err := store.ListCases(ctx, filter)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"full-accents", "full-text"}, ids(listed))
+
+			count, err := store.CountCases(ctx, filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+
+			found, err := store.SearchCases(ctx, storage.SearchQuery{Query: "contract", Filters: filter})
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"full-accents", "full-text"}, ids(found))
+
+			longer, err := store.ListCases(ctx, storage.CaseFilter{MinFullTextLength: 41})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"full-text"}, ids(longer))
+		})
+	}
+}
+
+// TestFullTextLoadedOnDemand tests that listed and searched cases leave out their full text, which is loaded with the case or on its own
+func TestFullTextLoadedOnDemand(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "fulltext.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	text := strings.Repeat("The respondent owed the appellant a duty of care. ", 20)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			c := models.NewCase()
+			c.ID = "fulltext-1"
+			c.CaseName = "Negligence appeal"
+			c.Jurisdiction = "UK"
+			c.FullText = text
+			require.NoError(t, store.SaveCase(ctx, c))
+
+			listed, err := store.ListCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			require.Len(t, listed, 1)
+			assert.Empty(t, listed[0].FullText, "listing reads metadata only")
+			assert.Equal(t, "Negligence appeal", listed[0].CaseName)
+
+			found, err := store.SearchCases(ctx, storage.SearchQuery{Query: "negligence"})
+			require.NoError(t, err)
+			require.Len(t, found, 1)
+			assert.Empty(t, found[0].FullText, "searching reads metadata only")
+
+			withText, err := store.ListCases(ctx, storage.CaseFilter{IncludeFullText: true})
+			require.NoError(t, err)
+			require.Len(t, withText, 1)
+			assert.Equal(t, text, withText[0].FullText)
+
+			loaded, err := storage.CaseFullText(ctx, store, c.ID)
+			require.NoError(t, err)
+			assert.Equal(t, text, loaded)
+
+			got, err := store.GetCase(ctx, c.ID)
+			require.NoError(t, err)
+			assert.Equal(t, text, got.FullText, "GetCase hydrates the full text")
+
+			// Replacing the text replaces what is loaded
+			got.FullText = "Appeal allowed."
+			require.NoError(t, store.UpdateCase(ctx, got))
+			loaded, err = storage.CaseFullText(ctx, store, c.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Appeal allowed.", loaded)
+
+			_, err = storage.CaseFullText(ctx, store, "missing")
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestSaveCaseIsIdempotent tests that re-saving a case replaces it on every backend, while CreateCase conflicts
+func TestSaveCaseIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "upsert.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			c := models.NewCase()
+			c.ID = "upsert-1"
+			c.CaseName = "Original v Name"
+			c.Jurisdiction = "UK"
+			require.NoError(t, store.SaveCase(ctx, c))
+
+			// A retried save succeeds and the latest version wins
+			retried := *c
+			retried.CaseName = "Corrected v Name"
+			require.NoError(t, store.SaveCase(ctx, &retried))
+			require.NoError(t, store.SaveCase(ctx, &retried))
+
+			got, err := store.GetCase(ctx, c.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Corrected v Name", got.CaseName)
+
+			count, err := store.CountCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			// CreateCase only accepts new IDs
+			assert.ErrorIs(t, store.CreateCase(ctx, c), errors.ErrAlreadyExists)
+
+			created := models.NewCase()
+			created.ID = "upsert-2"
+			created.CaseName = "Fresh v Case"
+			require.NoError(t, store.CreateCase(ctx, created))
+
+			got, err = store.GetCase(ctx, created.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "Fresh v Case", got.CaseName)
+		})
+	}
+}
+
+// batchRecorder records the batches written through SaveCases. Writes block
+// while gate is non-nil and open.
+type batchRecorder struct {
+	*storage.MemoryStorage
+	mu      sync.Mutex
+	batches []int
+	gate    chan struct{}
+}
+
+func (r *batchRecorder) SaveCases(ctx context.Context, cases []*models.Case) error {
+	if r.gate != nil {
+		<-r.gate
+	}
+
+	r.mu.Lock()
+	r.batches = append(r.batches, len(cases))
+	r.mu.Unlock()
+	return r.MemoryStorage.SaveCases(ctx, cases)
+}
+
+func (r *batchRecorder) recorded() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int(nil), r.batches...)
+}
+
+// TestWriteBehindFlushesInBatches tests that buffered saves are written in batches and flushed on close
+func TestWriteBehindFlushesInBatches(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchRecorder{MemoryStorage: storage.NewMemoryStorage()}
+	buffered := storage.NewWriteBehindStorage(recorder, storage.WriteBehindConfig{
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		BufferSize:    100,
+	})
+
+	for i := 0; i < 25; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%02d", i)
+		require.NoError(t, buffered.SaveCase(ctx, c))
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.recorded()) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{10, 10}, recorder.recorded())
+
+	// The remainder is still buffered but visible through GetCase
+	got, err := buffered.GetCase(ctx, "case-24")
+	require.NoError(t, err)
+	assert.Equal(t, "case-24", got.ID)
+
+	require.NoError(t, buffered.Close())
+	assert.Equal(t, []int{10, 10, 5}, recorder.recorded())
+	assert.EqualValues(t, 25, recorder.GetStats().TotalCases)
+
+	assert.ErrorIs(t, buffered.SaveCase(ctx, models.NewCase()), storage.ErrWriteBehindClosed)
+}
+
+// TestWriteBehindFlushesOnInterval tests that a partial batch is written once the interval passes
+func TestWriteBehindFlushesOnInterval(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchRecorder{MemoryStorage: storage.NewMemoryStorage()}
+	buffered := storage.NewWriteBehindStorage(recorder, storage.WriteBehindConfig{
+		BatchSize:     100,
+		FlushInterval: 20 * time.Millisecond,
+	})
+	defer buffered.Close()
+
+	for i := 0; i < 3; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%d", i)
+		require.NoError(t, buffered.SaveCase(ctx, c))
+	}
+
+	assert.Eventually(t, func() bool {
+		return recorder.GetStats().TotalCases == 3
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []int{3}, recorder.recorded())
+}
+
+// TestWriteBehindBackpressure tests that saves block once the buffer is full and nothing is lost
+func TestWriteBehindBackpressure(t *testing.T) {
+	ctx := context.Background()
+	recorder := &batchRecorder{MemoryStorage: storage.NewMemoryStorage(), gate: make(chan struct{})}
+	buffered := storage.NewWriteBehindStorage(recorder, storage.WriteBehindConfig{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		BufferSize:    2,
+	})
+
+	// Two cases fill a batch whose write is held at the gate, two more fill the buffer
+	for i := 0; i < 4; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%d", i)
+		require.NoError(t, buffered.SaveCase(ctx, c))
+	}
+
+	blocked := models.NewCase()
+	blocked.ID = "case-blocked"
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, buffered.SaveCase(timeoutCtx, blocked), context.DeadlineExceeded)
+
+	close(recorder.gate)
+	require.NoError(t, buffered.Close())
+
+	assert.Equal(t, []int{2, 2}, recorder.recorded())
+	assert.EqualValues(t, 4, recorder.GetStats().TotalCases)
+	_, err := recorder.GetCase(ctx, "case-blocked")
+	assert.Error(t, err)
+}
+
+// stalledStorage never completes a case save before its context ends
+type stalledStorage struct {
+	*storage.MemoryStorage
+}
+
+func (s *stalledStorage) SaveCase(ctx context.Context, c *models.Case) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (s *stalledStorage) SaveCases(ctx context.Context, cases []*models.Case) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// TestWriteBehindBoundsStalledWrites tests that a stalled backend can't hold up a flush and that failed writes are counted
+func TestWriteBehindBoundsStalledWrites(t *testing.T) {
+	ctx := context.Background()
+	buffered := storage.NewWriteBehindStorage(&stalledStorage{MemoryStorage: storage.NewMemoryStorage()}, storage.WriteBehindConfig{
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		WriteTimeout:  20 * time.Millisecond,
+	})
+	defer buffered.Close()
+
+	for i := 0; i < 2; i++ {
+		c := models.NewCase()
+		c.ID = fmt.Sprintf("case-%d", i)
+		require.NoError(t, buffered.SaveCase(ctx, c))
+	}
+
+	flushCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	require.NoError(t, buffered.Flush(flushCtx))
+	assert.EqualValues(t, 2, buffered.FailedWrites())
+}
+
+// flakyDriver is a database/sql driver that refuses its first connections,
+// records the last query and answers every query with its fixed rows. A
+// statement containing failOn fails; transactions are counted.
+type flakyDriver struct {
+	mu        sync.Mutex
+	failures  int
+	opens     int
+	query     string
+	args      []driver.Value
+	cols      []string
+	rows      [][]driver.Value
+	failOn    string
+	commits   int
+	rollbacks int
+}
+
+var flakyPostgres = &flakyDriver{}
+
+func init() {
+	sql.Register("flaky-postgres", flakyPostgres)
+}
+
+func (d *flakyDriver) reset(failures int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failures, d.opens = failures, 0
+	d.failOn, d.commits, d.rollbacks = "", 0, 0
+}
+
+func (d *flakyDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.opens++
+	if d.opens <= d.failures {
+		return nil, fmt.Errorf("connection refused")
+	}
+	return flakyConn{d}, nil
+}
+
+func (d *flakyDriver) last() (string, []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.query, d.args
+}
+
+// flakyConn accepts every statement, which is enough for schema setup
+type flakyConn struct{ d *flakyDriver }
+
+func (c flakyConn) Prepare(query string) (driver.Stmt, error) { return flakyStmt{c.d, query}, nil }
+func (flakyConn) Close() error                                { return nil }
+func (c flakyConn) Begin() (driver.Tx, error)                 { return flakyTx{c.d}, nil }
+
+type flakyTx struct{ d *flakyDriver }
+
+func (tx flakyTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	return nil
+}
+
+func (tx flakyTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+type flakyStmt struct {
+	d     *flakyDriver
+	query string
+}
+
+func (flakyStmt) Close() error  { return nil }
+func (flakyStmt) NumInput() int { return -1 }
+func (s flakyStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.query, s.d.args = s.query, args
+	if s.d.failOn != "" && strings.Contains(s.query, s.d.failOn) {
+		return nil, fmt.Errorf("disk full")
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s flakyStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.query, s.d.args = s.query, args
+	return &flakyRows{cols: s.d.cols, rows: s.d.rows}, nil
+}
+
+type flakyRows struct {
+	cols []string
+	rows [][]driver.Value
+}
+
+func (r *flakyRows) Columns() []string { return r.cols }
+func (r *flakyRows) Close() error      { return nil }
+func (r *flakyRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+// TestPostgresListCasesFilters tests that Postgres ListCases applies the same
+// filters, paging and ordering as SQLite and returns complete cases
+func TestPostgresListCasesFilters(t *testing.T) {
+	ctx := context.Background()
+	config := storage.DefaultPostgresConfig()
+	config.Driver = "flaky-postgres"
+	flakyPostgres.reset(0)
+
+	store, err := storage.NewPostgresStorageWithConfig("flaky", config)
+	require.NoError(t, err)
+	defer store.Close()
+
+	decided := time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)
+	flakyPostgres.cols = strings.Split("id case_number case_name decision_date court court_level court_type "+
+		"jurisdiction docket parties judges summary full_text key_issues legal_concepts outcome "+
+		"procedural_history citations url pdf_url source_database scraped_at last_updated language "+
+		"status court_id holding lower_court_case_id appealed_to_case_id extraction_version content_hash tenant_id", " ")
+	flakyPostgres.rows = [][]driver.Value{{
+		"case-1", "[2021] UKSC 1", "Smith v Jones", decided, "UK Supreme Court", int64(1), "supreme",
+		"UK", "", nil, nil, "A claim in negligence", "", nil, []byte(`["negligence"]`), "Dismissed",
+		"", nil, "https://example.com/1", "", "bailii", decided, decided, "en",
+		"active", "uksc", "No duty was owed", nil, nil, int64(2), "hash", nil,
+	}}
+
+	level := models.CourtLevelSupreme
+	cases, err := store.ListCases(ctx, storage.CaseFilter{
+		Status:     "active",
+		CourtLevel: &level,
+		Limit:      25,
+		Offset:     50,
+		OrderBy:    "case_name",
+	})
+	require.NoError(t, err)
+
+	query, args := flakyPostgres.last()
+	assert.Contains(t, query, "status = $")
+	assert.Contains(t, query, "court_level = $")
+	assert.Contains(t, query, "ORDER BY case_name ASC")
+	assert.Contains(t, query, "LIMIT $")
+	assert.Contains(t, query, "OFFSET $")
+	assert.Contains(t, args, driver.Value("active"))
+	assert.Contains(t, args, driver.Value(int64(25)))
+	assert.Contains(t, args, driver.Value(int64(50)))
+
+	require.Len(t, cases, 1)
+	c := cases[0]
+	assert.Equal(t, "A claim in negligence", c.Summary)
+	assert.Equal(t, models.CourtLevelSupreme, c.CourtLevel)
+	assert.Equal(t, []string{"negligence"}, c.LegalConcepts)
+	assert.Equal(t, "No duty was owed", c.Holding)
+	assert.Equal(t, "uksc", c.CourtID)
+	assert.Equal(t, 2, c.ExtractionVersion)
+
+	// Unknown order columns are not interpolated into the query
+	_, err = store.ListCases(ctx, storage.CaseFilter{OrderBy: "case_name; DROP TABLE cases"})
+	require.NoError(t, err)
+	query, _ = flakyPostgres.last()
+	assert.NotContains(t, query, "DROP TABLE")
+}
+
+// TestPostgresSaveCaseAtomic tests that a case row is not kept when writing its full text fails
+func TestPostgresSaveCaseAtomic(t *testing.T) {
+	ctx := context.Background()
+	config := storage.DefaultPostgresConfig()
+	config.Driver = "flaky-postgres"
+	flakyPostgres.reset(0)
+
+	store, err := storage.NewPostgresStorageWithConfig("flaky", config)
+	require.NoError(t, err)
+	defer store.Close()
+
+	c := models.NewCase()
+	c.ID = "case-1"
+	c.FullText = "The appeal is dismissed."
+	require.NoError(t, store.SaveCase(ctx, c))
+	assert.Equal(t, 1, flakyPostgres.commits)
+
+	flakyPostgres.reset(0)
+	flakyPostgres.failOn = "case_full_text"
+	require.Error(t, store.SaveCase(ctx, c))
+	require.Error(t, store.UpdateCase(ctx, c.ID, c))
+	assert.Equal(t, 0, flakyPostgres.commits, "the case row is rolled back with the text")
+	assert.Equal(t, 2, flakyPostgres.rollbacks)
+}
+
+// warnRecorder captures warning log lines
+type warnRecorder struct {
+	lines []string
+}
+
+func (r *warnRecorder) Warnf(format string, args ...interface{}) {
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+// TestPostgresConnectRetry tests that the constructor retries the initial ping until the database is up
+func TestPostgresConnectRetry(t *testing.T) {
+	config := storage.DefaultPostgresConfig()
+	config.Driver = "flaky-postgres"
+
+	// Down for two attempts, then up
+	flakyPostgres.reset(2)
+	recorder := &warnRecorder{}
+	config.ConnectRetry = storage.ConnectRetry{Attempts: 5, Interval: time.Millisecond, Logger: recorder}
+
+	store, err := storage.NewPostgresStorageWithConfig("flaky", config)
+	require.NoError(t, err)
+	require.NoError(t, store.Close())
+
+	require.Len(t, recorder.lines, 2)
+	assert.Contains(t, recorder.lines[0], "attempt 1/5 failed")
+	assert.Contains(t, recorder.lines[1], "attempt 2/5 failed")
+
+	// Never comes up within the attempts
+	flakyPostgres.reset(10)
+	recorder.lines = nil
+	config.ConnectRetry.Attempts = 3
+
+	_, err = storage.NewPostgresStorageWithConfig("flaky", config)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+	require.Len(t, recorder.lines, 3)
+	assert.Contains(t, recorder.lines[2], "giving up")
+
+	// The default tries once
+	flakyPostgres.reset(1)
+	config.ConnectRetry = storage.DefaultConnectRetry()
+	_, err = storage.NewPostgresStorageWithConfig("flaky", config)
+	require.Error(t, err)
+}
+
+// TestStatusLifecycleTransitions tests that updates making legal status transitions are applied and
+// audited, and illegal ones are rejected
+func TestStatusLifecycleTransitions(t *testing.T) {
+	ctx := context.Background()
+	fixed := clock.NewFixed(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+
+	store := storage.NewLifecycleStorage(storage.NewMemoryStorage(), nil, nil)
+	store.SetClock(fixed)
+
+	c := models.NewCase()
+	c.ID = "lifecycle-1"
+	c.CaseName = "Smith v Jones"
+	c.Status = models.CaseStatusActive
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	// the memory backend stores the saved pointer, so update copies
+	update := func(status models.CaseStatus) error {
+		updated := *c
+		updated.Status = status
+		return store.UpdateCase(ctx, &updated)
+	}
+
+	// Active -> Overruled is legal
+	require.NoError(t, update(models.CaseStatusOverruled))
+
+	// Overruled -> Active skips review
+	err := update(models.CaseStatusActive)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, storage.ErrIllegalStatusTransition)
+
+	got, err := store.GetCase(ctx, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.CaseStatusOverruled, got.Status, "rejected update is not applied")
+
+	// Updates that keep the status are not audited
+	require.NoError(t, update(models.CaseStatusOverruled))
+
+	history, err := store.AuditLog().StatusHistory(ctx, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []storage.StatusChange{{
+		CaseID: c.ID,
+		From:   models.CaseStatusActive,
+		To:     models.CaseStatusOverruled,
+		At:     fixed.Now(),
+	}}, history)
+}
+
+// TestStatusLifecycleGuardsSaves tests that saves and creates are checked like updates, and that
+// the audit log is kept by the backend rather than the wrapper
+func TestStatusLifecycleGuardsSaves(t *testing.T) {
+	ctx := context.Background()
+	backend := storage.NewMemoryStorage()
+	store := storage.NewLifecycleStorage(backend, nil, nil)
+
+	c := models.NewCase()
+	c.ID = "lifecycle-2"
+	c.CaseName = "Smith v Jones"
+	c.Status = models.CaseStatusOverruled
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	// A re-scrape can't make an overruled case good law again
+	rescraped := *c
+	rescraped.Status = models.CaseStatusActive
+	err := store.SaveCase(ctx, &rescraped)
+	assert.ErrorIs(t, err, storage.ErrIllegalStatusTransition)
+
+	reviewed := *c
+	reviewed.Status = models.CaseStatusPending
+	require.NoError(t, store.SaveCase(ctx, &reviewed))
+
+	// A new case must start in a status of the lifecycle
+	created := models.NewCase()
+	created.ID = "lifecycle-3"
+	created.Status = models.CaseStatus("withdrawn")
+	err = store.CreateCase(ctx, created)
+	assert.ErrorIs(t, err, storage.ErrIllegalStatusTransition)
+	_, err = backend.GetCase(ctx, created.ID)
+	assert.Error(t, err, "rejected case is not created")
+
+	// The history outlives the wrapper that recorded it
+	restarted := storage.NewLifecycleStorage(backend, nil, nil)
+	history, err := restarted.AuditLog().StatusHistory(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, models.CaseStatusOverruled, history[0].From)
+	assert.Equal(t, models.CaseStatusPending, history[0].To)
+}
+
+// TestParseStatusTransitions tests that a configured lifecycle replaces the default one
+func TestParseStatusTransitions(t *testing.T) {
+	transitions, err := storage.ParseStatusTransitions(map[string][]string{
+		"overruled": {"active"},
+	})
+	require.NoError(t, err)
+	assert.True(t, transitions.Allows(models.CaseStatusOverruled, models.CaseStatusActive))
+	assert.False(t, transitions.Allows(models.CaseStatusActive, models.CaseStatusClosed))
+
+	_, err = storage.ParseStatusTransitions(map[string][]string{"active": {"archived"}})
+	assert.Error(t, err)
+}
+
+// TestCasesByJudgeAcrossBackends tests that a judge's cases are paged newest first on every backend
+func TestCasesByJudgeAcrossBackends(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "judge_cases.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+		// A wrapper without its own query filters the listed cases
+		"fallback": struct{ storage.Storage }{storage.NewMemoryStorage()},
+	}
+
+	day := func(d int) *time.Time {
+		decided := time.Date(2023, 1, d, 0, 0, 0, 0, time.UTC)
+		return &decided
+	}
+	seeded := []struct {
+		id           string
+		jurisdiction string
+		judges       []string
+		decided      *time.Time
+	}{
+		{"linked", "United Kingdom", []string{"judge-reed", "Lord Hodge"}, day(3)},
+		{"named", "United Kingdom", []string{"Lord Reed"}, day(9)},
+		{"full-name", "Scotland", []string{"Robert Reed"}, day(1)},
+		{"other", "United Kingdom", []string{"Lord Hodge"}, day(5)},
+		{"undated", "United Kingdom", []string{"Lord Reed"}, nil},
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			judge := models.NewJudge("Lord Reed")
+			judge.ID = "judge-reed"
+			judge.FullName = "Robert Reed"
+			require.NoError(t, store.SaveJudge(ctx, judge))
+
+			for _, s := range seeded {
+				c := models.NewCase()
+				c.ID = s.id
+				c.CaseName = "Case " + s.id
+				c.Jurisdiction = s.jurisdiction
+				c.Judges = s.judges
+				c.DecisionDate = s.decided
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(cases []*models.Case) []string {
+				out := make([]string, len(cases))
+				for i, c := range cases {
+					out[i] = c.ID
+				}
+				return out
+			}
+
+			cases, total, err := storage.CasesByJudge(ctx, store, "judge-reed", storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), total)
+			assert.Equal(t, []string{"named", "linked", "full-name", "undated"}, ids(cases))
+
+			cases, total, err = storage.CasesByJudge(ctx, store, "judge-reed", storage.CaseFilter{Limit: 2, Offset: 1})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), total)
+			assert.Equal(t, []string{"linked", "full-name"}, ids(cases))
+
+			cases, total, err = storage.CasesByJudge(ctx, store, "judge-reed", storage.CaseFilter{Jurisdiction: "Scotland"})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+			assert.Equal(t, []string{"full-name"}, ids(cases))
+
+			_, _, err = storage.CasesByJudge(ctx, store, "no-such-judge", storage.CaseFilter{})
+			assert.ErrorIs(t, err, errors.ErrNotFound)
+		})
+	}
+}
+
+// TestStalerThanListsStaleCases tests that only cases not updated within the window are listed and reported stale
+func TestStalerThanListsStaleCases(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "stale.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	now := time.Now()
+	updated := map[string]time.Time{
+		"fresh":      now.Add(-time.Hour),
+		"recent":     now.Add(-20 * 24 * time.Hour),
+		"stale":      now.Add(-45 * 24 * time.Hour),
+		"very-stale": now.Add(-400 * 24 * time.Hour),
+	}
+	sources := map[string]string{"fresh": "BAILII", "recent": "CanLII", "stale": "BAILII", "very-stale": "CanLII"}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			for id, lastUpdated := range updated {
+				c := models.NewCase()
+				c.ID = id
+				c.CaseName = "Case " + id
+				c.Jurisdiction = "United Kingdom"
+				c.SourceDatabase = sources[id]
+				c.LastUpdated = lastUpdated
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(cases []*models.Case) []string {
+				out := make([]string, 0, len(cases))
+				for _, c := range cases {
+					out = append(out, c.ID)
+				}
+				return out
+			}
+
+			filter := storage.CaseFilter{StalerThan: 30 * 24 * time.Hour}
+			stale, err := store.ListCases(ctx, filter)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"stale", "very-stale"}, ids(stale))
+
+			count, err := store.CountCases(ctx, filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), count)
+
+			stale, err = store.ListCases(ctx, storage.CaseFilter{StalerThan: 365 * 24 * time.Hour})
+			require.NoError(t, err)
+			assert.Equal(t, []string{"very-stale"}, ids(stale))
+
+			freshness, err := storage.CaseFreshness(ctx, store, 30*24*time.Hour, now)
+			require.NoError(t, err)
+			require.Len(t, freshness, 2)
+			assert.Equal(t, "BAILII", freshness[0].Source)
+			assert.Equal(t, 2, freshness[0].Cases)
+			assert.Equal(t, 1, freshness[0].Stale)
+			assert.Equal(t, "CanLII", freshness[1].Source)
+			assert.Equal(t, 1, freshness[1].Stale)
+			assert.InDelta(t, (400 * 24 * time.Hour).Hours(), freshness[1].OldestAge.Hours(), 1)
+		})
+	}
+
+	c := models.NewCase()
+	c.LastUpdated = now.Add(-48 * time.Hour)
+	assert.Equal(t, 48*time.Hour, c.Age(now))
+	assert.True(t, c.IsStale(now, 24*time.Hour))
+	assert.False(t, c.IsStale(now, 72*time.Hour))
+}
+
+// TestTenantIsolation tests that tenants can't read or change each other's cases but share public ones
+func TestTenantIsolation(t *testing.T) {
+	inner := storage.NewMemoryStorage()
+	store := storage.NewTenantStorage(inner)
+	ctxA := storage.WithTenant(context.Background(), "tenant-a")
+	ctxB := storage.WithTenant(context.Background(), "tenant-b")
+
+	// Shared cases are written without a tenant, as by the scrapers
+	shared := models.NewCase()
+	shared.ID = "shared-1"
+	shared.CaseName = "Public v Record"
+	require.NoError(t, store.SaveCase(context.Background(), shared))
+
+	owned := models.NewCase()
+	owned.ID = "tenant-a-1"
+	owned.CaseName = "Private v Annotation"
+	owned.TenantID = "tenant-b" // ignored; writes are stamped with the caller's tenant
+	require.NoError(t, store.SaveCase(ctxA, owned))
+	assert.Equal(t, "tenant-a", owned.TenantID)
+
+	// Tenant A sees its own case and the shared one
+	got, err := store.GetCase(ctxA, owned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, owned.CaseName, got.CaseName)
+
+	listed, err := store.ListCases(ctxA, storage.CaseFilter{})
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+
+	// Tenant B sees only the shared case
+	_, err = store.GetCase(ctxB, owned.ID)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+
+	listed, err = store.ListCases(ctxB, storage.CaseFilter{})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, shared.ID, listed[0].ID)
+
+	count, err := store.CountCases(ctxB, storage.CaseFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	found, err := store.SearchCases(ctxB, storage.SearchQuery{Query: "v"})
+	require.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	// Tenant B can't overwrite, update or delete tenant A's case
+	hijack := *owned
+	hijack.CaseName = "Hijacked"
+	assert.ErrorIs(t, store.SaveCase(ctxB, &hijack), errors.ErrNotFound)
+	assert.ErrorIs(t, store.UpdateCase(ctxB, &hijack), errors.ErrNotFound)
+	assert.ErrorIs(t, store.DeleteCase(ctxB, owned.ID), errors.ErrNotFound)
+
+	deleted, err := store.DeleteCasesByFilter(ctxB, storage.CaseFilter{}, storage.DeleteOptions{Force: true})
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// Nor can a tenant change shared data
+	edit := *shared
+	edit.CaseName = "Edited"
+	assert.ErrorIs(t, store.UpdateCase(ctxA, &edit), storage.ErrNotTenantOwned)
+
+	got, err = inner.GetCase(context.Background(), owned.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Private v Annotation", got.CaseName)
+	assert.Equal(t, "tenant-a", got.TenantID)
+
+	// Unscoped callers see everything
+	listed, err = store.ListCases(context.Background(), storage.CaseFilter{})
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+}
+
+// TestTenantScopeInCaseQueries tests that the case queries beyond listing and
+// searching only reach the scoped tenant's and shared cases on every backend
+func TestTenantScopeInCaseQueries(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "tenant_queries.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	stores := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+		// A wrapper without its own queries filters the listed cases
+		"fallback": struct{ storage.Storage }{storage.NewMemoryStorage()},
+	}
+
+	decided := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	seeded := []struct {
+		id, tenant, jurisdiction string
+	}{
+		{"shared", "", "Singapore"},
+		{"own", "tenant-a", "Singapore"},
+		{"foreign", "tenant-b", "United Kingdom"},
+	}
+
+	scope := "tenant-a"
+	scoped := storage.CaseFilter{TenantScope: &scope}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			judge := models.NewJudge("Justice Tan")
+			judge.ID = "judge-tan"
+			require.NoError(t, store.SaveJudge(ctx, judge))
+			foreignJudge := models.NewJudge("Justice Thomas")
+			foreignJudge.ID = "judge-thomas"
+			foreignJudge.TenantID = "tenant-b"
+			require.NoError(t, store.SaveJudge(ctx, foreignJudge))
+
+			for _, s := range seeded {
+				c := models.NewCase()
+				c.ID = s.id
+				c.CaseName = "Case " + s.id
+				c.TenantID = s.tenant
+				c.Jurisdiction = s.jurisdiction
+				c.Judges = []string{"judge-tan"}
+				c.LegalConcepts = []string{"Negligence", "Duty of Care " + s.id}
+				c.DecisionDate = &decided
+				require.NoError(t, store.SaveCase(ctx, c))
+			}
+
+			ids := func(cases []*models.Case) []string {
+				out := make([]string, len(cases))
+				for i, c := range cases {
+					out[i] = c.ID
+				}
+				return out
+			}
+
+			cases, total, err := storage.CasesByJudge(ctx, store, "judge-tan", scoped)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), total)
+			assert.ElementsMatch(t, []string{"own", "shared"}, ids(cases))
+
+			cases, total, err = storage.CasesByConcept(ctx, store, "Negligence", scoped)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), total)
+			assert.Equal(t, []string{"own", "shared"}, ids(cases))
+
+			pairs, err := storage.ConceptCooccurrence(ctx, store, "Negligence", scoped, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []storage.ConceptPair{
+				{Concept: "Negligence", Related: "Duty of Care own", Count: 1},
+				{Concept: "Negligence", Related: "Duty of Care shared", Count: 1},
+			}, pairs)
+
+			var streamed []string
+			require.NoError(t, storage.StreamCases(ctx, store, scoped, func(c *models.Case) error {
+				streamed = append(streamed, c.ID)
+				return nil
+			}))
+			assert.ElementsMatch(t, []string{"own", "shared"}, streamed)
+
+			// Suggestions are scoped by the prefix queries, which TenantStorage
+			// scopes to the context's tenant
+			tenants := storage.NewTenantStorage(store)
+			tenantCtx := storage.WithTenant(ctx, scope)
+			names, err := tenants.SuggestCaseNames(tenantCtx, "case", storage.CaseFilter{}, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Case own", "Case shared"}, names)
+			concepts, err := tenants.SuggestConcepts(tenantCtx, "duty", storage.CaseFilter{}, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Duty of Care own", "Duty of Care shared"}, concepts)
+			judges, err := tenants.SuggestJudgeNames(tenantCtx, "justice", storage.JudgeFilter{}, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Justice Tan"}, judges)
+
+			names, err = store.SuggestCaseNames(ctx, "case", storage.CaseFilter{}, 10)
+			require.NoError(t, err)
+			assert.Len(t, names, 3, "unscoped suggestions cover every tenant")
+
+			// Backends aggregating in the database scope their counts too
+			query := storage.SearchQuery{Filters: scoped}
+			if aggregator, ok := store.(storage.FacetAggregator); ok {
+				facets, err := aggregator.AggregateFacets(ctx, query, []string{"jurisdiction"})
+				require.NoError(t, err)
+				assert.Equal(t, int64(2), facets.Total)
+				assert.Equal(t, map[string]int{"Singapore": 2}, facets.Counts["jurisdiction"])
+			}
+			if aggregator, ok := store.(storage.TimelineAggregator); ok {
+				buckets, err := aggregator.AggregateTimeline(ctx, query, storage.TimelineYear)
+				require.NoError(t, err)
+				assert.Equal(t, []storage.TimelineBucket{{Period: "2021", Count: 2}}, buckets)
+			}
+		})
+	}
+}
+
+// TestJurisdictionStorageScopesCases tests that cases of disabled
+// jurisdictions are left out of every read on every backend
+func TestJurisdictionStorageScopesCases(t *testing.T) {
+	ctx := context.Background()
+
+	sqliteStore, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "jurisdiction_scope.db"))
+	require.NoError(t, err)
+	defer sqliteStore.Close()
+
+	backends := map[string]storage.Storage{
+		"sqlite": sqliteStore,
+		"memory": storage.NewMemoryStorage(),
+	}
+
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			for id, jurisdiction := range map[string]string{"sg": "Singapore", "uk": "United Kingdom"} {
+				c := models.NewCase()
+				c.ID = id
+				c.CaseName = "Tort claim " + id
+				c.Jurisdiction = jurisdiction
+				c.LegalConcepts = []string{"Negligence"}
+				require.NoError(t, backend.SaveCase(ctx, c))
+			}
+
+			// Enabled jurisdictions are matched case-insensitively
+			store := storage.NewJurisdictionStorage(backend, []string{"singapore"})
+
+			cases, err := store.ListCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			require.Len(t, cases, 1)
+			assert.Equal(t, "sg", cases[0].ID)
+
+			cases, err = store.ListCases(ctx, storage.CaseFilter{Jurisdiction: "United Kingdom"})
+			require.NoError(t, err)
+			assert.Empty(t, cases)
+
+			count, err := store.CountCases(ctx, storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), count)
+
+			cases, err = store.SearchCases(ctx, storage.SearchQuery{Query: "Tort"})
+			require.NoError(t, err)
+			require.Len(t, cases, 1)
+			assert.Equal(t, "sg", cases[0].ID)
+
+			_, err = store.GetCase(ctx, "uk")
+			assert.ErrorIs(t, err, errors.ErrNotFound)
+
+			names, err := store.SuggestCaseNames(ctx, "Tort", storage.CaseFilter{}, 10)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"Tort claim sg"}, names)
+
+			facets, err := store.AggregateFacets(ctx, storage.SearchQuery{}, []string{"jurisdiction"})
+			require.NoError(t, err)
+			assert.Equal(t, map[string]int{"Singapore": 1}, facets.Counts["jurisdiction"])
+
+			_, total, err := storage.CasesByConcept(ctx, store, "Negligence", storage.CaseFilter{})
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), total)
+
+			// Hidden cases still exist, so imports don't collide with them
+			existing, err := storage.StoredCaseIDs(ctx, store, []string{"sg", "uk"})
+			require.NoError(t, err)
+			assert.True(t, existing["uk"])
+		})
+	}
+}
+
+// optionalSpy records calls to the optional storage interfaces, and the
+// tenant scope of those taking a filter
+type optionalSpy struct {
+	*storage.MemoryStorage
+	calls  []string
+	scopes []*string
+}
+
+func (s *optionalSpy) record(call string, scope *string) {
+	s.calls = append(s.calls, call)
+	s.scopes = append(s.scopes, scope)
+}
+
+func (s *optionalSpy) AggregateFacets(ctx context.Context, query storage.SearchQuery, fields []string) (*storage.FacetAggregation, error) {
+	s.record("aggregate_facets", query.Filters.TenantScope)
+	return &storage.FacetAggregation{Counts: map[string]map[string]int{}}, nil
+}
+
+func (s *optionalSpy) AggregateTimeline(ctx context.Context, query storage.SearchQuery, period storage.TimelinePeriod) ([]storage.TimelineBucket, error) {
+	s.record("aggregate_timeline", query.Filters.TenantScope)
+	return nil, nil
+}
+
+func (s *optionalSpy) TermStats(ctx context.Context) (*storage.TermStats, error) {
+	s.record("term_stats", nil)
+	return storage.NewTermStats(), nil
+}
+
+func (s *optionalSpy) StreamCases(ctx context.Context, filter storage.CaseFilter, fn func(*models.Case) error) error {
+	s.record("stream_cases", filter.TenantScope)
+	return nil
+}
+
+func (s *optionalSpy) ExistingIDs(ctx context.Context, ids []string) (map[string]bool, error) {
+	s.record("existing_ids", nil)
+	return map[string]bool{}, nil
+}
+
+func (s *optionalSpy) GetContentHash(ctx context.Context, id string) (string, error) {
+	s.record("get_content_hash", nil)
+	return "", nil
+}
+
+func (s *optionalSpy) GetCaseFullText(ctx context.Context, id string) (string, error) {
+	s.record("get_case_full_text", nil)
+	return "", nil
+}
+
+func (s *optionalSpy) ListCasesByJudge(ctx context.Context, judgeID string, filter storage.CaseFilter) ([]*models.Case, int64, error) {
+	s.record("list_cases_by_judge", filter.TenantScope)
+	return nil, 0, nil
+}
+
+func (s *optionalSpy) ListCasesByConcept(ctx context.Context, concept string, filter storage.CaseFilter) ([]*models.Case, int64, error) {
+	s.record("list_cases_by_concept", filter.TenantScope)
+	return nil, 0, nil
+}
+
+func (s *optionalSpy) CountConceptCooccurrence(ctx context.Context, concept string, filter storage.CaseFilter, limit int) ([]storage.ConceptPair, error) {
+	s.record("count_concept_cooccurrence", filter.TenantScope)
+	return nil, nil
+}
+
+func (s *optionalSpy) SaveCitations(ctx context.Context, citations []*models.Citation) error {
+	s.record("save_citations", nil)
+	return nil
+}
+
+// TestWrappersForwardOptionalStorage tests that the storage wrappers pass the
+// optional interfaces through to the backend, scoped to the caller's tenant
+func TestWrappersForwardOptionalStorage(t *testing.T) {
+	spy := &optionalSpy{MemoryStorage: storage.NewMemoryStorage()}
+	timeouts, err := storage.NewTimeoutStorage(spy, storage.TimeoutConfig{Default: time.Second})
+	require.NoError(t, err)
+	writeBehind := storage.NewWriteBehindStorage(timeouts, storage.WriteBehindConfig{})
+	defer writeBehind.Close()
+	store := storage.NewTenantStorage(storage.NewLifecycleStorage(writeBehind, nil, nil))
+
+	judge := models.NewJudge("Justice Tan")
+	judge.ID = "judge-tan"
+	require.NoError(t, store.SaveJudge(context.Background(), judge))
+
+	foreign := models.NewCase()
+	foreign.ID = "foreign"
+	foreign.TenantID = "tenant-b"
+	foreign.FullText = "Private judgment"
+	require.NoError(t, spy.SaveCase(context.Background(), foreign))
+
+	callAll := func(ctx context.Context) {
+		query := storage.SearchQuery{Query: "negligence"}
+		_, err := storage.AggregateFacets(ctx, store, query, []string{"jurisdiction"})
+		require.NoError(t, err)
+		_, err = storage.AggregateTimeline(ctx, store, query, storage.TimelineYear)
+		require.NoError(t, err)
+		_, err = storage.CorpusTermStats(ctx, store)
+		require.NoError(t, err)
+		require.NoError(t, storage.StreamCases(ctx, store, storage.CaseFilter{}, func(*models.Case) error { return nil }))
+		_, _, err = storage.CasesByJudge(ctx, store, judge.ID, storage.CaseFilter{})
+		require.NoError(t, err)
+		_, _, err = storage.CasesByConcept(ctx, store, "Negligence", storage.CaseFilter{})
+		require.NoError(t, err)
+		_, err = storage.ConceptCooccurrence(ctx, store, "Negligence", storage.CaseFilter{}, 5)
+		require.NoError(t, err)
+		require.NoError(t, storage.SaveCitations(ctx, store, []*models.Citation{{ID: "cite-1", RawCitation: "[2020] SGCA 1"}}))
+	}
+
+	// Unscoped calls reach the backend's own implementations
+	ctx := context.Background()
+	callAll(ctx)
+	_, err = storage.StoredCaseIDs(ctx, store, []string{foreign.ID})
+	require.NoError(t, err)
+	_, err = storage.StoredContentHash(ctx, store, foreign.ID)
+	require.NoError(t, err)
+	_, err = storage.CaseFullText(ctx, store, foreign.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"aggregate_facets", "aggregate_timeline", "term_stats", "stream_cases",
+		"list_cases_by_judge", "list_cases_by_concept", "count_concept_cooccurrence",
+		"save_citations", "existing_ids", "get_content_hash", "get_case_full_text",
+	}, spy.calls)
+	for _, scope := range spy.scopes {
+		assert.Nil(t, scope)
+	}
+
+	// Scoped calls carry the tenant's scope down to the backend
+	spy.calls, spy.scopes = nil, nil
+	scoped := storage.WithTenant(ctx, "tenant-a")
+	callAll(scoped)
+
+	assert.Len(t, spy.calls, 8)
+	for i, call := range spy.calls {
+		if call == "term_stats" || call == "save_citations" {
+			continue
+		}
+		if assert.NotNil(t, spy.scopes[i], call) {
+			assert.Equal(t, "tenant-a", *spy.scopes[i], call)
+		}
+	}
+
+	// and don't reveal other tenants' cases by ID
+	existing, err := storage.StoredCaseIDs(scoped, store, []string{foreign.ID})
+	require.NoError(t, err)
+	assert.Empty(t, existing)
+
+	_, err = storage.CaseFullText(scoped, store, foreign.ID)
+	assert.ErrorIs(t, err, errors.ErrNotFound)
+}
+
+// hangingStorage blocks case reads and searches until their context is done
+type hangingStorage struct {
+	storage.Storage
+}
+
+func (s *hangingStorage) GetCase(ctx context.Context, id string) (*models.Case, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *hangingStorage) SearchCases(ctx context.Context, query storage.SearchQuery) ([]*models.Case, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// storageErrorCounter counts storage errors by operation and type
+type storageErrorCounter map[string]int
+
+func (c storageErrorCounter) RecordStorageError(operation, errorType string) {
+	c[operation+"/"+errorType]++
+}
+
+// TestStorageQueryTimeout tests that storage calls on a hung backend fail at their timeout
+func TestStorageQueryTimeout(t *testing.T) {
+	ctx := context.Background()
+	counter := storageErrorCounter{}
+	store, err := storage.NewTimeoutStorage(&hangingStorage{Storage: storage.NewMemoryStorage()}, storage.TimeoutConfig{
+		Default:    50 * time.Millisecond,
+		Operations: map[string]time.Duration{"search_cases": 100 * time.Millisecond},
+		Metrics:    counter,
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = store.GetCase(ctx, "hung")
+	assert.ErrorIs(t, err, storage.ErrQueryTimeout)
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	_, err = store.SearchCases(ctx, storage.SearchQuery{Query: "contract"})
+	assert.ErrorIs(t, err, storage.ErrQueryTimeout)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond, "search uses its own timeout")
+	assert.Equal(t, storageErrorCounter{"get_case/timeout": 1, "search_cases/timeout": 1}, counter)
+
+	// Calls that finish in time are unaffected
+	c := models.NewCase()
+	c.ID = "quick"
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	// The caller's own cancellation is passed through, not counted as a timeout
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = store.GetCase(cancelled, "hung")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, storage.ErrQueryTimeout)
+	assert.Len(t, counter, 2)
+
+	_, err = storage.NewTimeoutStorage(storage.NewMemoryStorage(), storage.TimeoutConfig{
+		Operations: map[string]time.Duration{"get_cases": time.Second},
+	})
+	assert.Error(t, err, "unknown operations are rejected")
+}
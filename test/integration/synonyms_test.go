@@ -0,0 +1,38 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gongahkia/kite/internal/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadCustomThesaurus tests that query expansion uses a thesaurus loaded from file
+func TestLoadCustomThesaurus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "thesaurus.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("- [tort, delict]\n- [solicitor, lawyer, attorney]\n"), 0o644))
+
+	qe := search.NewQueryExpander()
+	require.NoError(t, qe.LoadSynonyms(path, true))
+
+	assert.Equal(t, []string{"tort", "delict"}, qe.Expand("tort"))
+	assert.Equal(t, []string{"delict", "tort"}, qe.Expand("delict"), "expansion must be bidirectional")
+	assert.ElementsMatch(t, []string{"attorney", "solicitor", "lawyer"}, qe.Expand("Attorney"))
+	assert.Equal(t, []string{"contract"}, qe.Expand("contract"), "defaults must be replaced")
+}
+
+// TestMergeThesaurusCSV tests that a CSV thesaurus is merged with the default synonyms
+func TestMergeThesaurusCSV(t *testing.T) {
+	qe := search.NewQueryExpander()
+	csv := "# Scots law\ndelict, tort\npursuer, plaintiff\n"
+	require.NoError(t, qe.LoadSynonymsFromReader(strings.NewReader(csv), search.SynonymFormatCSV, false))
+
+	assert.Contains(t, qe.Expand("pursuer"), "plaintiff")
+	assert.Contains(t, qe.Expand("plaintiff"), "pursuer")
+	assert.Contains(t, qe.Expand("plaintiff"), "claimant", "defaults must be kept")
+	assert.Contains(t, qe.Expand("contract"), "agreement")
+}
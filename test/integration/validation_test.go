@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/validation"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomDedupHashes tests that configured field combinations replace the default duplicate hashes
+func TestCustomDedupHashes(t *testing.T) {
+	newCase := func(id, name string, decided time.Time) *models.Case {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = name
+		c.CaseNumber = ""
+		c.Court = "High Court"
+		c.Jurisdiction = "Hong Kong"
+		c.DecisionDate = &decided
+		return c
+	}
+	march := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []*models.Case{
+		newCase("brown-1", "R v Brown", march),
+		newCase("brown-2", "R. v. Brown", march),
+		newCase("smith-1", "Smith v Jones", march),
+		newCase("smith-2", "Smith v Jones", june),
+	}
+
+	templates := []validation.HashTemplate{
+		{Name: "name_and_date", Fields: []string{"case_name", "decision_date"}, Normalize: true},
+	}
+	require.NoError(t, validation.ValidateHashTemplates(templates))
+
+	groups := func(detector *validation.DuplicateDetector) [][]string {
+		var ids [][]string
+		for _, group := range detector.DetectDuplicates(cases) {
+			sort.Strings(group)
+			ids = append(ids, group)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i][0] < ids[j][0] })
+		return ids
+	}
+
+	// the same name on different dates is a different case under the custom hash
+	assert.Equal(t, [][]string{{"brown-1", "brown-2"}},
+		groups(validation.NewDuplicateDetectorWithTemplates(templates)))
+	assert.Contains(t, groups(validation.NewDuplicateDetector()), []string{"smith-1", "smith-2"})
+
+	validator := validation.NewDuplicationValidatorWithTemplates(templates)
+	_, err := validator.Validate(context.Background(), cases[0])
+	require.NoError(t, err)
+	result, err := validator.Validate(context.Background(), cases[1])
+	require.NoError(t, err)
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, "name_and_date", result.Warnings[0].Field)
+	assert.Equal(t, "POTENTIAL_DUPLICATE", result.Warnings[0].Code)
+
+	assert.Error(t, validation.ValidateHashTemplates([]validation.HashTemplate{
+		{Name: "bad", Fields: []string{"neutral_citation"}},
+	}))
+}
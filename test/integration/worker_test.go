@@ -0,0 +1,350 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gongahkia/kite/internal/events"
+	"github.com/gongahkia/kite/internal/queue"
+	"github.com/gongahkia/kite/internal/storage"
+	"github.com/gongahkia/kite/internal/worker"
+	"github.com/gongahkia/kite/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startLongJob starts a one-worker pool and waits until it has picked up a single job
+func startLongJob(t *testing.T, q *queue.MemoryQueue, handler worker.JobHandler) (*worker.Pool, *queue.Job) {
+	pool := worker.NewPool(worker.PoolConfig{WorkerCount: 1}, q, handler)
+	require.NoError(t, pool.Start(1))
+
+	job := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"case_id": "long-case"})
+	require.NoError(t, q.Enqueue(context.Background(), job))
+
+	require.Eventually(t, func() bool {
+		return pool.GetStats().BusyWorkers == 1
+	}, time.Second, 5*time.Millisecond)
+
+	return pool, job
+}
+
+// TestPoolStopDrainsInFlightJob tests that Stop lets a running job finish before returning
+func TestPoolStopDrainsInFlightJob(t *testing.T) {
+	q := queue.NewMemoryQueue()
+
+	var completed atomic.Bool
+	pool, job := startLongJob(t, q, func(ctx context.Context, job *queue.Job) error {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			completed.Store(true)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+
+	require.NoError(t, pool.Stop(2*time.Second))
+
+	assert.True(t, completed.Load(), "in-flight job should finish during drain")
+	assert.Equal(t, queue.JobStatusCompleted, job.Status)
+
+	stats := pool.GetStats()
+	assert.Equal(t, int64(1), stats.TotalJobsProcessed)
+	assert.Zero(t, stats.TotalJobsRequeued)
+	assert.GreaterOrEqual(t, stats.DrainDuration, 100*time.Millisecond)
+}
+
+// TestPoolStopRequeuesUnfinishedJob tests that a job outliving the timeout is cancelled and requeued
+func TestPoolStopRequeuesUnfinishedJob(t *testing.T) {
+	q := queue.NewMemoryQueue()
+
+	pool, job := startLongJob(t, q, func(ctx context.Context, job *queue.Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	err := pool.Stop(100 * time.Millisecond)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	stats := pool.GetStats()
+	assert.Equal(t, int64(1), stats.TotalJobsRequeued)
+	assert.Zero(t, stats.TotalJobsFailed)
+
+	// The job is back in the queue without losing an attempt
+	depth, err := q.GetDepth(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, depth)
+	assert.Equal(t, queue.JobStatusRetrying, job.Status)
+	assert.Zero(t, job.Attempts)
+}
+
+// TestEnrichJobsUseEnrichmentPool tests that routed enrich jobs are handled by the enrichment pool and scrape jobs by the main pool
+func TestEnrichJobsUseEnrichmentPool(t *testing.T) {
+	ctx := context.Background()
+	scrapeQueue := queue.NewMemoryQueue()
+	enrichQueue := queue.NewMemoryQueue()
+	q := queue.NewRoutedQueue(scrapeQueue, map[queue.JobType]queue.Queue{
+		queue.JobTypeEnrich: enrichQueue,
+	})
+
+	var mu sync.Mutex
+	handledBy := make(map[string]string)
+	recordingHandler := func(pool string) worker.JobHandler {
+		return func(ctx context.Context, job *queue.Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handledBy[job.ID] = pool
+			return nil
+		}
+	}
+
+	scrapePool := worker.NewPool(worker.PoolConfig{WorkerCount: 2}, scrapeQueue, recordingHandler("scrape"))
+	require.NoError(t, scrapePool.Start(2))
+	defer scrapePool.Stop(time.Second)
+
+	enrichPool := worker.NewPool(worker.PoolConfig{WorkerCount: 1}, enrichQueue, recordingHandler("enrich"))
+	require.NoError(t, enrichPool.Start(1))
+	defer enrichPool.Stop(time.Second)
+
+	want := make(map[string]string)
+	for i := 0; i < 3; i++ {
+		scrape := queue.NewJob(queue.JobTypeScrape, map[string]interface{}{"jurisdiction": "uk"})
+		enrich := queue.NewJob(queue.JobTypeEnrich, map[string]interface{}{"case_id": "case"})
+		require.NoError(t, q.Enqueue(ctx, scrape))
+		require.NoError(t, q.Enqueue(ctx, enrich))
+		want[scrape.ID] = "scrape"
+		want[enrich.ID] = "enrich"
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handledBy) == len(want)
+	}, time.Second, 5*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, want, handledBy)
+	assert.Equal(t, int64(3), enrichPool.GetStats().TotalJobsProcessed)
+}
+
+// TestEnrichJobHandlerRunsSteps tests that the enrich handler runs each step on the stored case
+func TestEnrichJobHandlerRunsSteps(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "enrich-case"
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	var steps []string
+	step := func(name string) worker.EnrichStep {
+		return func(ctx context.Context, c *models.Case) error {
+			steps = append(steps, name+":"+c.ID)
+			return nil
+		}
+	}
+	handler := worker.NewEnrichJobHandler(store, step("concepts"), step("citations"))
+
+	job := queue.NewJob(queue.JobTypeEnrich, map[string]interface{}{"case_id": "enrich-case"})
+	require.NoError(t, handler(ctx, job))
+	assert.Equal(t, []string{"concepts:enrich-case", "citations:enrich-case"}, steps)
+
+	assert.Error(t, handler(ctx, queue.NewJob(queue.JobTypeScrape, nil)))
+	assert.Error(t, handler(ctx, queue.NewJob(queue.JobTypeEnrich, map[string]interface{}{"case_id": "missing"})))
+}
+
+// TestEnrichStepsRunInDependencyOrder tests that enrichment steps run after
+// their prerequisites, in the configured order where those allow
+func TestEnrichStepsRunInDependencyOrder(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+
+	c := models.NewCase()
+	c.ID = "ordered-case"
+	require.NoError(t, store.SaveCase(ctx, c))
+
+	var ran []string
+	step := func(name string, requires ...string) worker.NamedEnrichStep {
+		return worker.NamedEnrichStep{Name: name, Requires: requires, Run: func(ctx context.Context, c *models.Case) error {
+			ran = append(ran, name)
+			return nil
+		}}
+	}
+	steps := []worker.NamedEnrichStep{
+		step("concepts", "metadata"),
+		step("treatments", "citations"),
+		step("metadata"),
+		step("citations"),
+	}
+	run := func(order []string, requires map[string][]string) []string {
+		ordered, err := worker.OrderEnrichSteps(steps, order, requires)
+		require.NoError(t, err)
+		runs := make([]worker.EnrichStep, len(ordered))
+		for i, s := range ordered {
+			runs[i] = s.Run
+		}
+		ran = nil
+		job := queue.NewJob(queue.JobTypeEnrich, map[string]interface{}{"case_id": "ordered-case"})
+		require.NoError(t, worker.NewEnrichJobHandler(store, runs...)(ctx, job))
+		return ran
+	}
+
+	// Prerequisites move ahead of the steps that need them
+	assert.Equal(t, []string{"metadata", "concepts", "citations", "treatments"}, run(nil, nil))
+
+	// The configured order is followed as far as prerequisites allow, and
+	// unlisted steps come after
+	assert.Equal(t, []string{"citations", "metadata", "concepts", "treatments"}, run([]string{"citations", "concepts"}, nil))
+
+	// Configured prerequisites are added to those the steps declare
+	assert.Equal(t, []string{"citations", "treatments", "metadata", "concepts"}, run(nil, map[string][]string{"metadata": {"treatments"}}))
+
+	_, err := worker.OrderEnrichSteps(steps, nil, map[string][]string{"metadata": {"concepts"}})
+	assert.ErrorContains(t, err, "cycle")
+	_, err = worker.OrderEnrichSteps(steps, []string{"judges"}, nil)
+	assert.Error(t, err)
+	_, err = worker.OrderEnrichSteps(steps, nil, map[string][]string{"concepts": {"judges"}})
+	assert.Error(t, err)
+	_, err = worker.OrderEnrichSteps(append(steps, step("metadata")), nil, nil)
+	assert.Error(t, err)
+}
+
+// recordingNotifier records the cases each alert was sent for, failing while fail is set
+type recordingNotifier struct {
+	alerts [][]string
+	fail   bool
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, search *models.SavedSearch, cases []*models.Case) error {
+	if n.fail {
+		return assert.AnError
+	}
+	ids := make([]string, 0, len(cases))
+	for _, c := range cases {
+		ids = append(ids, c.ID)
+	}
+	n.alerts = append(n.alerts, ids)
+	return nil
+}
+
+// TestSavedSearchAlertsNewMatchingCaseOnce tests that a newly saved case
+// matching a saved search alerts once, and only after delivery succeeds
+func TestSavedSearchAlertsNewMatchingCaseOnce(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	now := time.Now()
+
+	search := &models.SavedSearch{
+		ID:         "dismissal",
+		UserID:     "user-1",
+		Query:      "unfair dismissal",
+		Filters:    models.SavedSearchFilters{Jurisdiction: "Australia"},
+		WebhookURL: "https://example.com/alerts",
+		CreatedAt:  now.Add(-time.Hour),
+	}
+	require.NoError(t, store.SaveSavedSearch(ctx, search))
+
+	saveCase := func(id, name, jurisdiction string, scraped time.Time) {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = name
+		c.Jurisdiction = jurisdiction
+		c.ScrapedAt = scraped
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+	saveCase("before-saved", "Smith v Acme (unfair dismissal)", "Australia", now.Add(-2*time.Hour))
+	saveCase("other-topic", "Jones v Bank (negligence)", "Australia", now)
+	saveCase("other-jurisdiction", "Brown v Mill (unfair dismissal)", "UK", now)
+
+	notifier := &recordingNotifier{}
+	runner, err := worker.NewAlertRunner(store, notifier, worker.AlertConfig{})
+	require.NoError(t, err)
+
+	result, err := runner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Alerts)
+	assert.Empty(t, notifier.alerts)
+
+	// A failed delivery leaves the case to alert on the next run
+	saveCase("new-match", "Lee v Retail Co (Unfair Dismissal)", "Australia", now)
+	notifier.fail = true
+	result, err = runner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+
+	notifier.fail = false
+	result, err = runner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Alerts)
+	assert.Equal(t, [][]string{{"new-match"}}, notifier.alerts)
+
+	// Later runs don't alert the same case again
+	result, err = runner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Alerts)
+	assert.Len(t, notifier.alerts, 1)
+}
+
+// TestSavedSearchAlertsWithinLookback tests that a saved search only alerts
+// on cases scraped within the lookback window that carry one of its concepts
+func TestSavedSearchAlertsWithinLookback(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemoryStorage()
+	now := time.Now()
+
+	search := &models.SavedSearch{
+		ID:         "employment",
+		UserID:     "user-1",
+		Filters:    models.SavedSearchFilters{Concepts: []string{"Employment Law"}},
+		WebhookURL: "https://example.com/alerts",
+		CreatedAt:  now.Add(-3 * time.Hour),
+	}
+	require.NoError(t, store.SaveSavedSearch(ctx, search))
+
+	saveCase := func(id string, concepts []string, scraped time.Time) {
+		c := models.NewCase()
+		c.ID = id
+		c.CaseName = id
+		c.LegalConcepts = concepts
+		c.ScrapedAt = scraped
+		require.NoError(t, store.SaveCase(ctx, c))
+	}
+	saveCase("outside-lookback", []string{"Employment Law"}, now.Add(-2*time.Hour))
+	saveCase("other-concept", []string{"Negligence"}, now)
+	saveCase("match", []string{"Employment Law"}, now)
+
+	notifier := &recordingNotifier{}
+	runner, err := worker.NewAlertRunner(store, notifier, worker.AlertConfig{Lookback: time.Hour, PageSize: 1})
+	require.NoError(t, err)
+
+	result, err := runner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Alerts)
+	assert.Equal(t, [][]string{{"match"}}, notifier.alerts)
+}
+
+// TestAlertWebhookMustBePublic tests that webhook URLs naming loopback,
+// link-local or private hosts are rejected
+func TestAlertWebhookMustBePublic(t *testing.T) {
+	for _, url := range []string{
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.1/hook",
+		"http://0.0.0.0/hook",
+		"ftp://example.com/hook",
+	} {
+		assert.Error(t, events.CheckPublicURL(url), url)
+	}
+	assert.NoError(t, events.CheckPublicURL("https://example.com/alerts"))
+	assert.NoError(t, events.CheckPublicURL("http://93.184.216.34/hook"))
+}